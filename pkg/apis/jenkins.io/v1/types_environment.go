@@ -36,6 +36,7 @@ type EnvironmentSpec struct {
 	TeamSettings      TeamSettings          `json:"teamSettings,omitempty" protobuf:"bytes,9,opt,name=teamSettings"`
 	PreviewGitSpec    PreviewGitSpec        `json:"previewGitInfo,omitempty" protobuf:"bytes,10,opt,name=previewGitInfo"`
 	WebHookEngine     WebHookEngineType     `json:"webHookEngine,omitempty" protobuf:"bytes,11,opt,name=webHookEngine"`
+	Rollout           *RolloutStrategy      `json:"rollout,omitempty" protobuf:"bytes,12,opt,name=rollout"`
 }
 
 // EnvironmentStatus is the status for an Environment resource
@@ -98,6 +99,50 @@ const (
 	WebHookEngineProw    WebHookEngineType = "Prow"
 )
 
+// RolloutKindType is the kind of progressive rollout used when promoting to an environment
+type RolloutKindType string
+
+const (
+	// RolloutKindTypeCanary shifts traffic to the new version in increasing steps
+	RolloutKindTypeCanary RolloutKindType = "Canary"
+	// RolloutKindTypeBlueGreen deploys the new version next to the old one and then switches all traffic in one step
+	RolloutKindTypeBlueGreen RolloutKindType = "BlueGreen"
+)
+
+// RolloutRouterType is the way traffic is split between the old and new versions during a rollout
+type RolloutRouterType string
+
+const (
+	// RolloutRouterTypeIstio uses an Istio VirtualService to split traffic
+	RolloutRouterTypeIstio RolloutRouterType = "Istio"
+	// RolloutRouterTypeIngress uses a weighted canary ingress to split traffic
+	RolloutRouterTypeIngress RolloutRouterType = "Ingress"
+)
+
+// RolloutStrategy describes how a new version is progressively rolled out when promoting to an environment
+type RolloutStrategy struct {
+	Kind                RolloutKindType   `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
+	Router              RolloutRouterType `json:"router,omitempty" protobuf:"bytes,2,opt,name=router"`
+	Weights             []int32           `json:"weights,omitempty" protobuf:"bytes,3,opt,name=weights"`
+	StepDuration        string            `json:"stepDuration,omitempty" protobuf:"bytes,4,opt,name=stepDuration"`
+	MaxRestarts         int32             `json:"maxRestarts,omitempty" protobuf:"bytes,5,opt,name=maxRestarts"`
+	PrometheusURL       string            `json:"prometheusURL,omitempty" protobuf:"bytes,6,opt,name=prometheusURL"`
+	PrometheusQuery     string            `json:"prometheusQuery,omitempty" protobuf:"bytes,7,opt,name=prometheusQuery"`
+	PrometheusThreshold string            `json:"prometheusThreshold,omitempty" protobuf:"bytes,8,opt,name=prometheusThreshold"`
+}
+
+// RolloutKindTypeValues is the list of all rollout kinds
+var RolloutKindTypeValues = []string{
+	string(RolloutKindTypeCanary),
+	string(RolloutKindTypeBlueGreen),
+}
+
+// RolloutRouterTypeValues is the list of all rollout routers
+var RolloutRouterTypeValues = []string{
+	string(RolloutRouterTypeIstio),
+	string(RolloutRouterTypeIngress),
+}
+
 // IsPermanent returns true if this environment is permanent
 func (e EnvironmentKindType) IsPermanent() bool {
 	switch e {
@@ -132,24 +177,106 @@ type EnvironmentRepository struct {
 
 // TeamSettings the default settings for a team
 type TeamSettings struct {
-	UseGitOPs           bool                 `json:"useGitOps,omitempty" protobuf:"bytes,1,opt,name=useGitOps"`
-	AskOnCreate         bool                 `json:"askOnCreate,omitempty" protobuf:"bytes,2,opt,name=askOnCreate"`
-	BranchPatterns      string               `json:"branchPatterns,omitempty" protobuf:"bytes,3,opt,name=branchPatterns"`
-	ForkBranchPatterns  string               `json:"forkBranchPatterns,omitempty" protobuf:"bytes,4,opt,name=forkBranchPatterns"`
-	QuickstartLocations []QuickStartLocation `json:"quickstartLocations,omitempty" protobuf:"bytes,5,opt,name=quickstartLocations"`
-	BuildPackURL        string               `json:"buildPackUrl,omitempty" protobuf:"bytes,6,opt,name=buildPackUrl"`
-	BuildPackRef        string               `json:"buildPackRef,omitempty" protobuf:"bytes,7,opt,name=buildPackRef"`
-	HelmBinary          string               `json:"helmBinary,omitempty" protobuf:"bytes,8,opt,name=helmBinary"`
-	PostPreviewJobs     []batchv1.Job        `json:"postPreviewJobs,omitempty" protobuf:"bytes,9,opt,name=postPreviewJobs"`
-	PromotionEngine     PromotionEngineType  `json:"promotionEngine,omitempty" protobuf:"bytes,10,opt,name=promotionEngine"`
-	NoTiller            bool                 `json:"noTiller,omitempty" protobuf:"bytes,11,opt,name=noTiller"`
-	HelmTemplate        bool                 `json:"helmTemplate,omitempty" protobuf:"bytes,12,opt,name=helmTemplate"`
-	GitServer           string               `json:"gitServer,omitempty" protobuf:"bytes,13,opt,name=gitServer" command:"gitserver" commandUsage:"Default git server for new repositories"`
-	Organisation        string               `json:"organisation,omitempty" protobuf:"bytes,14,opt,name=organisation" command:"organisation" commandUsage:"Default git organisation for new repositories"`
-	PipelineUsername    string               `json:"pipelineUsername,omitempty" protobuf:"bytes,15,opt,name=pipelineUsername" command:"pipelineusername" commandUsage:"User used by pipeline. Is given write permission on new repositories."`
-	DockerRegistryOrg   string               `json:"dockerRegistryOrg,omitempty" protobuf:"bytes,16,opt,name=dockerRegistryOrg" command:"dockerregistryorg" commandUsage:"Docker registry organisation used for new projects in Jenkins X."`
-	GitPrivate          bool                 `json:"gitPrivate,omitempty" protobuf:"bytes,17,opt,name=gitPrivate" command:"gitprivate" commandUsage:"Are new repositories private by default"`
-	KubeProvider        string               `json:"kubeProvider,omitempty" protobuf:"bytes,18,opt,name=kubeProvider"`
+	UseGitOPs           bool                     `json:"useGitOps,omitempty" protobuf:"bytes,1,opt,name=useGitOps"`
+	AskOnCreate         bool                     `json:"askOnCreate,omitempty" protobuf:"bytes,2,opt,name=askOnCreate"`
+	BranchPatterns      string                   `json:"branchPatterns,omitempty" protobuf:"bytes,3,opt,name=branchPatterns"`
+	ForkBranchPatterns  string                   `json:"forkBranchPatterns,omitempty" protobuf:"bytes,4,opt,name=forkBranchPatterns"`
+	QuickstartLocations []QuickStartLocation     `json:"quickstartLocations,omitempty" protobuf:"bytes,5,opt,name=quickstartLocations"`
+	BuildPackURL        string                   `json:"buildPackUrl,omitempty" protobuf:"bytes,6,opt,name=buildPackUrl"`
+	BuildPackRef        string                   `json:"buildPackRef,omitempty" protobuf:"bytes,7,opt,name=buildPackRef"`
+	HelmBinary          string                   `json:"helmBinary,omitempty" protobuf:"bytes,8,opt,name=helmBinary"`
+	PostPreviewJobs     []batchv1.Job            `json:"postPreviewJobs,omitempty" protobuf:"bytes,9,opt,name=postPreviewJobs"`
+	PromotionEngine     PromotionEngineType      `json:"promotionEngine,omitempty" protobuf:"bytes,10,opt,name=promotionEngine"`
+	NoTiller            bool                     `json:"noTiller,omitempty" protobuf:"bytes,11,opt,name=noTiller"`
+	HelmTemplate        bool                     `json:"helmTemplate,omitempty" protobuf:"bytes,12,opt,name=helmTemplate"`
+	GitServer           string                   `json:"gitServer,omitempty" protobuf:"bytes,13,opt,name=gitServer" command:"gitserver" commandUsage:"Default git server for new repositories"`
+	Organisation        string                   `json:"organisation,omitempty" protobuf:"bytes,14,opt,name=organisation" command:"organisation" commandUsage:"Default git organisation for new repositories"`
+	PipelineUsername    string                   `json:"pipelineUsername,omitempty" protobuf:"bytes,15,opt,name=pipelineUsername" command:"pipelineusername" commandUsage:"User used by pipeline. Is given write permission on new repositories."`
+	DockerRegistryOrg   string                   `json:"dockerRegistryOrg,omitempty" protobuf:"bytes,16,opt,name=dockerRegistryOrg" command:"dockerregistryorg" commandUsage:"Docker registry organisation used for new projects in Jenkins X."`
+	GitPrivate          bool                     `json:"gitPrivate,omitempty" protobuf:"bytes,17,opt,name=gitPrivate" command:"gitprivate" commandUsage:"Are new repositories private by default"`
+	KubeProvider        string                   `json:"kubeProvider,omitempty" protobuf:"bytes,18,opt,name=kubeProvider"`
+	ActivityRetention   *ActivityRetentionPolicy `json:"activityRetention,omitempty" protobuf:"bytes,19,opt,name=activityRetention"`
+	ArtifactStorage     *ArtifactStorageSpec     `json:"artifactStorage,omitempty" protobuf:"bytes,20,opt,name=artifactStorage"`
+	PreviewLifecycle    *PreviewLifecyclePolicy  `json:"previewLifecycle,omitempty" protobuf:"bytes,21,opt,name=previewLifecycle"`
+}
+
+// ActivityRetentionPolicy the rules used to garbage collect the PipelineActivity resources of the team
+type ActivityRetentionPolicy struct {
+	// Rules the rules which keep activities. An activity is kept if any of the rules which match it keeps it.
+	// Activities which match no rule are kept using the revision history limit of each pipeline
+	Rules []ActivityRetentionRule `json:"rules,omitempty" protobuf:"bytes,1,opt,name=rules"`
+	// KeepPromotedTo the environments such as production for which every activity which promoted a release is kept
+	KeepPromotedTo []string `json:"keepPromotedTo,omitempty" protobuf:"bytes,2,opt,name=keepPromotedTo"`
+	// DeleteClosedPullRequests deletes the activities of pull requests which are closed or merged
+	DeleteClosedPullRequests bool `json:"deleteClosedPullRequests,omitempty" protobuf:"bytes,3,opt,name=deleteClosedPullRequests"`
+	// Archive where the activities are archived before they are deleted
+	Archive *ActivityArchiveSpec `json:"archive,omitempty" protobuf:"bytes,4,opt,name=archive"`
+}
+
+// ActivityRetentionRule keeps the activities of the matching branches and statuses by age and count
+type ActivityRetentionRule struct {
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	// Branches a regular expression of the branch names the rule applies to such as 'PR-.*'. Matches every branch if empty
+	Branches string `json:"branches,omitempty" protobuf:"bytes,2,opt,name=branches"`
+	// Statuses the statuses of the activities the rule applies to. Matches every status if empty
+	Statuses []ActivityStatusType `json:"statuses,omitempty" protobuf:"bytes,3,opt,name=statuses"`
+	// MaxAgeDays keeps the matching activities started within the number of days
+	MaxAgeDays int `json:"maxAgeDays,omitempty" protobuf:"bytes,4,opt,name=maxAgeDays"`
+	// Keep keeps the number of most recent matching activities of each pipeline
+	Keep int `json:"keep,omitempty" protobuf:"bytes,5,opt,name=keep"`
+}
+
+// ActivityArchiveKindType the kind of storage activities are archived to
+type ActivityArchiveKindType string
+
+const (
+	// ActivityArchiveKindTypeTarball archives activities as a tarball in a local directory
+	ActivityArchiveKindTypeTarball ActivityArchiveKindType = "tarball"
+	// ActivityArchiveKindTypeS3 archives activities as a tarball in an S3 bucket
+	ActivityArchiveKindTypeS3 ActivityArchiveKindType = "s3"
+	// ActivityArchiveKindTypeElasticsearch archives activities to the Elasticsearch of the pipeline-events addon
+	ActivityArchiveKindTypeElasticsearch ActivityArchiveKindType = "elasticsearch"
+)
+
+// ActivityArchiveSpec where PipelineActivity resources are archived before they are garbage collected
+type ActivityArchiveSpec struct {
+	Kind   ActivityArchiveKindType `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
+	Dir    string                  `json:"dir,omitempty" protobuf:"bytes,2,opt,name=dir"`
+	Bucket string                  `json:"bucket,omitempty" protobuf:"bytes,3,opt,name=bucket"`
+	Region string                  `json:"region,omitempty" protobuf:"bytes,4,opt,name=region"`
+}
+
+// ArtifactStorageSpec where the files collected from builds by 'jx step collect' such as test reports and coverage
+// are stored. The Kind is one of GitHub, s3, gcs or pvc
+type ArtifactStorageSpec struct {
+	Kind   string `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
+	Bucket string `json:"bucket,omitempty" protobuf:"bytes,2,opt,name=bucket"`
+	Region string `json:"region,omitempty" protobuf:"bytes,3,opt,name=region"`
+	// Endpoint the URL of an S3 compatible object store such as Minio. Uses AWS S3 if empty
+	Endpoint string `json:"endpoint,omitempty" protobuf:"bytes,4,opt,name=endpoint"`
+	// Dir the directory such as a mounted persistent volume the files are stored in for the pvc kind
+	Dir string `json:"dir,omitempty" protobuf:"bytes,5,opt,name=dir"`
+}
+
+// PreviewLifecyclePolicy the rules used to reclaim the preview environments of the team and limit their resources
+type PreviewLifecyclePolicy struct {
+	// TTL deletes previews which have not been updated by a push for the duration such as 72h
+	TTL string `json:"ttl,omitempty" protobuf:"bytes,1,opt,name=ttl"`
+	// IdleTimeout scales the deployments of previews which have not been updated by a push or woken up by a request
+	// for the duration such as 2h down to zero replicas
+	IdleTimeout string `json:"idleTimeout,omitempty" protobuf:"bytes,2,opt,name=idleTimeout"`
+	// MaximumInstances the maximum number of previews of each repository which do not configure
+	// previewEnvironments.maximumInstances in their jenkins-x.yml
+	MaximumInstances int `json:"maximumInstances,omitempty" protobuf:"bytes,3,opt,name=maximumInstances"`
+	// Quota the hard limits of the ResourceQuota of each preview namespace such as cpu, memory and pods
+	Quota map[string]string `json:"quota,omitempty" protobuf:"bytes,4,rep,name=quota"`
+	// DefaultRequests the default resource requests of the containers in each preview namespace
+	DefaultRequests map[string]string `json:"defaultRequests,omitempty" protobuf:"bytes,5,rep,name=defaultRequests"`
+	// DefaultLimits the default resource limits of the containers in each preview namespace
+	DefaultLimits map[string]string `json:"defaultLimits,omitempty" protobuf:"bytes,6,rep,name=defaultLimits"`
+	// WakerService the service in the dev namespace running 'jx controller preview-waker' which wakes up previews
+	// scaled down to zero on the next request. Previews are only woken up by a push if empty
+	WakerService string `json:"wakerService,omitempty" protobuf:"bytes,7,opt,name=wakerService"`
 }
 
 // QuickStartLocation
@@ -51,10 +51,12 @@ type PipelineActivitySpec struct {
 
 // PipelineActivityStep represents a step in a pipeline activity
 type PipelineActivityStep struct {
-	Kind    ActivityStepKindType `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
-	Stage   *StageActivityStep   `json:"stage,omitempty" protobuf:"bytes,2,opt,name=stage"`
-	Promote *PromoteActivityStep `json:"promote,omitempty" protobuf:"bytes,3,opt,name=promote"`
-	Preview *PreviewActivityStep `json:"preview,omitempty" protobuf:"bytes,4,opt,name=preview"`
+	Kind     ActivityStepKindType  `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
+	Stage    *StageActivityStep    `json:"stage,omitempty" protobuf:"bytes,2,opt,name=stage"`
+	Promote  *PromoteActivityStep  `json:"promote,omitempty" protobuf:"bytes,3,opt,name=promote"`
+	Preview  *PreviewActivityStep  `json:"preview,omitempty" protobuf:"bytes,4,opt,name=preview"`
+	Workflow *WorkflowActivityStep `json:"workflow,omitempty" protobuf:"bytes,5,opt,name=workflow"`
+	Rollback *RollbackActivityStep `json:"rollback,omitempty" protobuf:"bytes,6,opt,name=rollback"`
 }
 
 // CoreActivityStep is a base step included in Stages of a pipeline or other kinds of step
@@ -90,6 +92,36 @@ type PromoteActivityStep struct {
 	PullRequest    *PromotePullRequestStep `json:"pullRequest,omitempty" protobuf:"bytes,2,opt,name=pullRequest"`
 	Update         *PromoteUpdateStep      `json:"update,omitempty" protobuf:"bytes,3,opt,name=update"`
 	ApplicationURL string                  `json:"applicationURL,omitempty" protobuf:"bytes,4,opt,name=environment"`
+	Rollout        []PromoteRolloutStep    `json:"rollout,omitempty" protobuf:"bytes,5,opt,name=rollout"`
+}
+
+// PromoteRolloutStep is the state of one traffic shifting step of a canary or blue/green promotion
+type PromoteRolloutStep struct {
+	CoreActivityStep
+
+	Weight  int32  `json:"weight,omitempty" protobuf:"bytes,1,opt,name=weight"`
+	Message string `json:"message,omitempty" protobuf:"bytes,2,opt,name=message"`
+}
+
+// WorkflowActivityStep is the state of a Workflow step which is not a promotion such as an approval, wait or verification
+type WorkflowActivityStep struct {
+	CoreActivityStep
+
+	Kind       WorkflowStepKindType `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
+	Message    string               `json:"message,omitempty" protobuf:"bytes,2,opt,name=message"`
+	ApprovedBy string               `json:"approvedBy,omitempty" protobuf:"bytes,3,opt,name=approvedBy"`
+	JobName    string               `json:"jobName,omitempty" protobuf:"bytes,4,opt,name=jobName"`
+}
+
+// RollbackActivityStep is the step of rolling back an application in an Environment to a previous version
+type RollbackActivityStep struct {
+	CoreActivityStep
+
+	Environment    string `json:"environment" protobuf:"bytes,1,opt,name=environment"`
+	FromVersion    string `json:"fromVersion,omitempty" protobuf:"bytes,2,opt,name=fromVersion"`
+	ToVersion      string `json:"toVersion,omitempty" protobuf:"bytes,3,opt,name=toVersion"`
+	PullRequestURL string `json:"pullRequestURL,omitempty" protobuf:"bytes,4,opt,name=pullRequestURL"`
+	MergeCommitSHA string `json:"mergeCommitSHA,omitempty" protobuf:"bytes,5,opt,name=mergeCommitSHA"`
 }
 
 // GitStatus the status of a git commit in terms of CI/CD
@@ -141,6 +173,10 @@ const (
 	ActivityStepKindTypePreview ActivityStepKindType = "Preview"
 	// ActivityStepKindTypePromote a promote activity
 	ActivityStepKindTypePromote ActivityStepKindType = "Promote"
+	// ActivityStepKindTypeWorkflow a workflow step such as an approval, wait or verification
+	ActivityStepKindTypeWorkflow ActivityStepKindType = "Workflow"
+	// ActivityStepKindTypeRollback a rollback of an application to a previous version
+	ActivityStepKindTypeRollback ActivityStepKindType = "Rollback"
 )
 
 // ActivityStatusType is the status of an activity; usually succeeded or failed/error on completion
@@ -163,6 +199,8 @@ const (
 	ActivityStatusTypeError ActivityStatusType = "Error"
 	// ActivityStatusTypeAborted if the workflow was aborted
 	ActivityStatusTypeAborted ActivityStatusType = "Aborted"
+	// ActivityStatusTypeSkipped if the step was skipped as its conditions were not met
+	ActivityStatusTypeSkipped ActivityStatusType = "Skipped"
 )
 
 type Attachment struct {
@@ -228,18 +266,22 @@ const (
 )
 
 const (
-	MeasurementPercent = "percent"
-	MeasurementCount   = "count"
+	MeasurementPercent      = "percent"
+	MeasurementCount        = "count"
+	MeasurementMilliseconds = "milliseconds"
 )
 
 const (
 	FactTypeCoverage              = "jx.coverage"
 	FactTypeStaticProgramAnalysis = "jx.staticProgramAnalysis"
+	FactTypeVerify                = "jx.verify"
+	FactTypeVulnerabilityScan     = "jx.vulnerabilityScan"
+	FactTypeTestResults           = "jx.testResults"
 )
 
 // IsTerminated returns true if this activity has stopped executing
 func (s ActivityStatusType) IsTerminated() bool {
-	return s == ActivityStatusTypeSucceeded || s == ActivityStatusTypeFailed || s == ActivityStatusTypeError || s == ActivityStatusTypeAborted
+	return s == ActivityStatusTypeSucceeded || s == ActivityStatusTypeFailed || s == ActivityStatusTypeError || s == ActivityStatusTypeAborted || s == ActivityStatusTypeSkipped
 }
 
 func (s ActivityStatusType) String() string {
@@ -34,6 +34,11 @@ type WorkflowStep struct {
 	Description   string                `json:"description,omitempty" protobuf:"bytes,2,opt,name=description"`
 	Preconditions WorkflowPreconditions `json:"trigger,omitempty" protobuf:"bytes,3,opt,name=trigger"`
 	Promote       *PromoteWorkflowStep  `json:"promote,omitempty" protobuf:"bytes,4,opt,name=promote"`
+	Approve       *ApproveWorkflowStep  `json:"approve,omitempty" protobuf:"bytes,5,opt,name=approve"`
+	Wait          *WaitWorkflowStep     `json:"wait,omitempty" protobuf:"bytes,6,opt,name=wait"`
+	Verify        *VerifyWorkflowStep   `json:"verify,omitempty" protobuf:"bytes,7,opt,name=verify"`
+	// the conditions which must all be true for this step to execute; otherwise the step is skipped
+	Conditions []WorkflowCondition `json:"when,omitempty" protobuf:"bytes,8,opt,name=when"`
 }
 
 // PromoteWorkflowStep is the step of promoting a version of an application to an environment
@@ -41,10 +46,46 @@ type PromoteWorkflowStep struct {
 	Environment string `json:"environment,omitempty" protobuf:"bytes,1,opt,name=environment"`
 }
 
+// ApproveWorkflowStep is a manual approval gate which blocks the workflow until it is approved via `jx approve`
+type ApproveWorkflowStep struct {
+	// the Kubernetes users who are granted permission to approve this step via a RoleBinding. If empty any user can approve
+	Approvers []string `json:"approvers,omitempty" protobuf:"bytes,1,opt,name=approvers"`
+	Message   string   `json:"message,omitempty" protobuf:"bytes,2,opt,name=message"`
+}
+
+// WaitWorkflowStep is a step which waits for a duration such as a soak period after a promotion
+type WaitWorkflowStep struct {
+	// the duration to wait such as 30m or 2h
+	Duration string `json:"duration,omitempty" protobuf:"bytes,1,opt,name=duration"`
+}
+
+// VerifyWorkflowStep is a step which runs a Kubernetes Job and succeeds if the Job completes successfully
+type VerifyWorkflowStep struct {
+	Image   string   `json:"image,omitempty" protobuf:"bytes,1,opt,name=image"`
+	Command []string `json:"command,omitempty" protobuf:"bytes,2,opt,name=command"`
+	Args    []string `json:"args,omitempty" protobuf:"bytes,3,opt,name=args"`
+	// the maximum duration the Job can run before the step fails such as 10m
+	Timeout string `json:"timeout,omitempty" protobuf:"bytes,4,opt,name=timeout"`
+}
+
+// WorkflowCondition is a condition on the facts of a PipelineActivity
+type WorkflowCondition struct {
+	// the name or type of the fact
+	Fact string `json:"fact,omitempty" protobuf:"bytes,1,opt,name=fact"`
+	// the name of the measurement in the fact to compare with the value
+	Measurement string `json:"measurement,omitempty" protobuf:"bytes,2,opt,name=measurement"`
+	// the name of the statement in the fact to compare with the value
+	Statement string                    `json:"statement,omitempty" protobuf:"bytes,3,opt,name=statement"`
+	Operator  WorkflowConditionOperator `json:"operator,omitempty" protobuf:"bytes,4,opt,name=operator"`
+	Value     string                    `json:"value,omitempty" protobuf:"bytes,5,opt,name=value"`
+}
+
 // WorkflowPreconditions is the trigger to start a step
 type WorkflowPreconditions struct {
 	// the names of the environments which need to have promoted before this step can be triggered
 	Environments []string `json:"environments,omitempty" protobuf:"bytes,1,opt,name=environments"`
+	// the names of the steps which need to have completed before this step can be triggered
+	Steps []string `json:"steps,omitempty" protobuf:"bytes,2,opt,name=steps"`
 }
 
 // WorkflowStatus is the status for an Environment resource
@@ -70,6 +111,28 @@ const (
 	WorkflowStepKindTypeNone WorkflowStepKindType = ""
 	// WorkflowStepKindTypePromote a promote activity
 	WorkflowStepKindTypePromote WorkflowStepKindType = "Promote"
+	// WorkflowStepKindTypeApprove a manual approval gate
+	WorkflowStepKindTypeApprove WorkflowStepKindType = "Approve"
+	// WorkflowStepKindTypeWait waits for a duration
+	WorkflowStepKindTypeWait WorkflowStepKindType = "Wait"
+	// WorkflowStepKindTypeVerify runs a verification Job
+	WorkflowStepKindTypeVerify WorkflowStepKindType = "Verify"
+)
+
+// WorkflowConditionOperator is the operator used to compare a fact with the value of a condition
+type WorkflowConditionOperator string
+
+const (
+	// WorkflowConditionOperatorEquals the fact equals the value. This is the default operator
+	WorkflowConditionOperatorEquals WorkflowConditionOperator = "Equals"
+	// WorkflowConditionOperatorNotEquals the fact does not equal the value
+	WorkflowConditionOperatorNotEquals WorkflowConditionOperator = "NotEquals"
+	// WorkflowConditionOperatorLessThan the fact is less than the value
+	WorkflowConditionOperatorLessThan WorkflowConditionOperator = "LessThan"
+	// WorkflowConditionOperatorGreaterThan the fact is greater than the value
+	WorkflowConditionOperatorGreaterThan WorkflowConditionOperator = "GreaterThan"
+	// WorkflowConditionOperatorExists the fact exists
+	WorkflowConditionOperatorExists WorkflowConditionOperator = "Exists"
 )
 
 // WorkflowStatusType is the status of an activity; usually succeeded or failed/error on completion
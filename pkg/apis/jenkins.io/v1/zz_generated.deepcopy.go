@@ -10,6 +10,113 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActivityArchiveSpec) DeepCopyInto(out *ActivityArchiveSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActivityArchiveSpec.
+func (in *ActivityArchiveSpec) DeepCopy() *ActivityArchiveSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ActivityArchiveSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActivityRetentionPolicy) DeepCopyInto(out *ActivityRetentionPolicy) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]ActivityRetentionRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.KeepPromotedTo != nil {
+		in, out := &in.KeepPromotedTo, &out.KeepPromotedTo
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Archive != nil {
+		in, out := &in.Archive, &out.Archive
+		*out = new(ActivityArchiveSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActivityRetentionPolicy.
+func (in *ActivityRetentionPolicy) DeepCopy() *ActivityRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ActivityRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActivityRetentionRule) DeepCopyInto(out *ActivityRetentionRule) {
+	*out = *in
+	if in.Statuses != nil {
+		in, out := &in.Statuses, &out.Statuses
+		*out = make([]ActivityStatusType, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActivityRetentionRule.
+func (in *ActivityRetentionRule) DeepCopy() *ActivityRetentionRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ActivityRetentionRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApproveWorkflowStep) DeepCopyInto(out *ApproveWorkflowStep) {
+	*out = *in
+	if in.Approvers != nil {
+		in, out := &in.Approvers, &out.Approvers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApproveWorkflowStep.
+func (in *ApproveWorkflowStep) DeepCopy() *ApproveWorkflowStep {
+	if in == nil {
+		return nil
+	}
+	out := new(ApproveWorkflowStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactStorageSpec) DeepCopyInto(out *ArtifactStorageSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactStorageSpec.
+func (in *ArtifactStorageSpec) DeepCopy() *ArtifactStorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactStorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Attachment) DeepCopyInto(out *Attachment) {
 	*out = *in
@@ -416,6 +523,11 @@ func (in *EnvironmentSpec) DeepCopyInto(out *EnvironmentSpec) {
 	out.Source = in.Source
 	in.TeamSettings.DeepCopyInto(&out.TeamSettings)
 	out.PreviewGitSpec = in.PreviewGitSpec
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1154,6 +1266,16 @@ func (in *PipelineActivityStep) DeepCopyInto(out *PipelineActivityStep) {
 		*out = new(PreviewActivityStep)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Workflow != nil {
+		in, out := &in.Workflow, &out.Workflow
+		*out = new(WorkflowActivityStep)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(RollbackActivityStep)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1201,6 +1323,43 @@ func (in *PreviewGitSpec) DeepCopy() *PreviewGitSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreviewLifecyclePolicy) DeepCopyInto(out *PreviewLifecyclePolicy) {
+	*out = *in
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DefaultRequests != nil {
+		in, out := &in.DefaultRequests, &out.DefaultRequests
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DefaultLimits != nil {
+		in, out := &in.DefaultLimits, &out.DefaultLimits
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreviewLifecyclePolicy.
+func (in *PreviewLifecyclePolicy) DeepCopy() *PreviewLifecyclePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PreviewLifecyclePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PromoteActivityStep) DeepCopyInto(out *PromoteActivityStep) {
 	*out = *in
@@ -1215,6 +1374,13 @@ func (in *PromoteActivityStep) DeepCopyInto(out *PromoteActivityStep) {
 		*out = new(PromoteUpdateStep)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = make([]PromoteRolloutStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -1245,6 +1411,23 @@ func (in *PromotePullRequestStep) DeepCopy() *PromotePullRequestStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromoteRolloutStep) DeepCopyInto(out *PromoteRolloutStep) {
+	*out = *in
+	in.CoreActivityStep.DeepCopyInto(&out.CoreActivityStep)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromoteRolloutStep.
+func (in *PromoteRolloutStep) DeepCopy() *PromoteRolloutStep {
+	if in == nil {
+		return nil
+	}
+	out := new(PromoteRolloutStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PromoteUpdateStep) DeepCopyInto(out *PromoteUpdateStep) {
 	*out = *in
@@ -1439,6 +1622,44 @@ func (in *ResourceReference) DeepCopy() *ResourceReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollbackActivityStep) DeepCopyInto(out *RollbackActivityStep) {
+	*out = *in
+	in.CoreActivityStep.DeepCopyInto(&out.CoreActivityStep)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollbackActivityStep.
+func (in *RollbackActivityStep) DeepCopy() *RollbackActivityStep {
+	if in == nil {
+		return nil
+	}
+	out := new(RollbackActivityStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
+	*out = *in
+	if in.Weights != nil {
+		in, out := &in.Weights, &out.Weights
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategy.
+func (in *RolloutStrategy) DeepCopy() *RolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StageActivityStep) DeepCopyInto(out *StageActivityStep) {
 	*out = *in
@@ -1562,6 +1783,21 @@ func (in *TeamSettings) DeepCopyInto(out *TeamSettings) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ActivityRetention != nil {
+		in, out := &in.ActivityRetention, &out.ActivityRetention
+		*out = new(ActivityRetentionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ArtifactStorage != nil {
+		in, out := &in.ArtifactStorage, &out.ArtifactStorage
+		*out = new(ArtifactStorageSpec)
+		**out = **in
+	}
+	if in.PreviewLifecycle != nil {
+		in, out := &in.PreviewLifecycle, &out.PreviewLifecycle
+		*out = new(PreviewLifecyclePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1709,6 +1945,48 @@ func (in *UserSpec) DeepCopy() *UserSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerifyWorkflowStep) DeepCopyInto(out *VerifyWorkflowStep) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerifyWorkflowStep.
+func (in *VerifyWorkflowStep) DeepCopy() *VerifyWorkflowStep {
+	if in == nil {
+		return nil
+	}
+	out := new(VerifyWorkflowStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WaitWorkflowStep) DeepCopyInto(out *WaitWorkflowStep) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WaitWorkflowStep.
+func (in *WaitWorkflowStep) DeepCopy() *WaitWorkflowStep {
+	if in == nil {
+		return nil
+	}
+	out := new(WaitWorkflowStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Workflow) DeepCopyInto(out *Workflow) {
 	*out = *in
@@ -1737,6 +2015,39 @@ func (in *Workflow) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowActivityStep) DeepCopyInto(out *WorkflowActivityStep) {
+	*out = *in
+	in.CoreActivityStep.DeepCopyInto(&out.CoreActivityStep)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowActivityStep.
+func (in *WorkflowActivityStep) DeepCopy() *WorkflowActivityStep {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowActivityStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowCondition) DeepCopyInto(out *WorkflowCondition) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowCondition.
+func (in *WorkflowCondition) DeepCopy() *WorkflowCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkflowList) DeepCopyInto(out *WorkflowList) {
 	*out = *in
@@ -1778,6 +2089,11 @@ func (in *WorkflowPreconditions) DeepCopyInto(out *WorkflowPreconditions) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1839,6 +2155,26 @@ func (in *WorkflowStep) DeepCopyInto(out *WorkflowStep) {
 		*out = new(PromoteWorkflowStep)
 		**out = **in
 	}
+	if in.Approve != nil {
+		in, out := &in.Approve, &out.Approve
+		*out = new(ApproveWorkflowStep)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Wait != nil {
+		in, out := &in.Wait, &out.Wait
+		*out = new(WaitWorkflowStep)
+		**out = **in
+	}
+	if in.Verify != nil {
+		in, out := &in.Verify, &out.Verify
+		*out = new(VerifyWorkflowStep)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]WorkflowCondition, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
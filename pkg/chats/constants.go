@@ -1,10 +1,13 @@
 package chats
 
 const (
-	Slack = "slack"
-	Irc   = "irc"
+	Slack      = "slack"
+	Irc        = "irc"
+	Mattermost = "mattermost"
+	Teams      = "teams"
+	RocketChat = "rocketchat"
 )
 
 var (
-	ChatKinds = []string{Slack, Irc}
+	ChatKinds = []string{Slack, Irc, Mattermost, Teams, RocketChat}
 )
@@ -0,0 +1,74 @@
+package chats
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultHTTPTimeout = 30 * time.Second
+)
+
+// restClient invokes the JSON REST APIs of chat servers
+type restClient struct {
+	client  *http.Client
+	headers map[string]string
+}
+
+func newRestClient(headers map[string]string) *restClient {
+	return &restClient{
+		client: &http.Client{
+			Timeout: defaultHTTPTimeout,
+		},
+		headers: headers,
+	}
+}
+
+// do invokes the given URL marshalling the body to JSON if it is not nil and unmarshalling the response into the
+// result if it is not nil
+func (c *restClient) do(method string, u string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s returned status %d: %s", method, u, resp.StatusCode, string(data))
+	}
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return fmt.Errorf("failed to parse the response of %s %s: %s", method, u, err)
+	}
+	return nil
+}
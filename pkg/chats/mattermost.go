@@ -0,0 +1,154 @@
+package chats
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// MattermostChatProvider a chat provider for Mattermost using its v4 REST API
+//
+// The server URL should include the team name such as https://mattermost.example.com/myteam.
+// Channels are specified by name such as 'town-square' or by team and name such as 'myteam/town-square'
+type MattermostChatProvider struct {
+	Server   *auth.AuthServer
+	UserAuth *auth.UserAuth
+	BaseURL  string
+	Team     string
+
+	client *restClient
+}
+
+type mattermostChannel struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	TeamID      string `json:"team_id"`
+}
+
+type mattermostChannelStats struct {
+	ChannelID   string `json:"channel_id"`
+	MemberCount int    `json:"member_count"`
+}
+
+type mattermostChannelMember struct {
+	UserID string `json:"user_id"`
+}
+
+type mattermostPost struct {
+	ID        string `json:"id,omitempty"`
+	ChannelID string `json:"channel_id"`
+	RootID    string `json:"root_id,omitempty"`
+	Message   string `json:"message"`
+}
+
+func CreateMattermostChatProvider(server *auth.AuthServer, userAuth *auth.UserAuth, batchMode bool) (ChatProvider, error) {
+	u := server.URL
+	if u == "" {
+		return nil, fmt.Errorf("No base URL for server!")
+	}
+	if userAuth == nil || userAuth.IsInvalid() || userAuth.ApiToken == "" {
+		return nil, fmt.Errorf("No authentication found for Mattermost server %s", u)
+	}
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid Mattermost server URL %s: %s", u, err)
+	}
+	team := strings.Split(strings.Trim(parsed.Path, "/"), "/")[0]
+	parsed.Path = ""
+	return &MattermostChatProvider{
+		Server:   server,
+		UserAuth: userAuth,
+		BaseURL:  parsed.String(),
+		Team:     team,
+		client: newRestClient(map[string]string{
+			"Authorization": "Bearer " + userAuth.ApiToken,
+		}),
+	}, nil
+}
+
+func (c *MattermostChatProvider) GetChannelMetrics(name string) (*ChannelMetrics, error) {
+	metrics := &ChannelMetrics{
+		Name: name,
+	}
+	team, channel, err := c.findChannel(name)
+	if err != nil {
+		return metrics, err
+	}
+	stats := &mattermostChannelStats{}
+	err = c.client.do(http.MethodGet, c.apiURL("channels", channel.ID, "stats"), nil, stats)
+	if err != nil {
+		return metrics, err
+	}
+	members := []mattermostChannelMember{}
+	err = c.client.do(http.MethodGet, c.apiURL("channels", channel.ID, "members"), nil, &members)
+	if err != nil {
+		return metrics, err
+	}
+	metrics.ID = channel.ID
+	metrics.Name = channel.Name
+	metrics.MemberCount = stats.MemberCount
+	for _, member := range members {
+		metrics.Members = append(metrics.Members, member.UserID)
+	}
+	metrics.URL = util.UrlJoin(c.BaseURL, team, "channels", channel.Name)
+	return metrics, nil
+}
+
+func (c *MattermostChatProvider) PostMessage(channel string, text string) (*Message, error) {
+	return c.createPost(channel, "", text)
+}
+
+func (c *MattermostChatProvider) PostThreadReply(channel string, threadID string, text string) (*Message, error) {
+	return c.createPost(channel, threadID, text)
+}
+
+func (c *MattermostChatProvider) createPost(name string, threadID string, text string) (*Message, error) {
+	_, channel, err := c.findChannel(name)
+	if err != nil {
+		return nil, err
+	}
+	post := &mattermostPost{
+		ChannelID: channel.ID,
+		RootID:    threadID,
+		Message:   text,
+	}
+	result := &mattermostPost{}
+	err = c.client.do(http.MethodPost, c.apiURL("posts"), post, result)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{
+		ID:        result.ID,
+		ChannelID: channel.ID,
+		ThreadID:  threadID,
+	}, nil
+}
+
+// findChannel finds the channel for the given name returning the name of its team
+func (c *MattermostChatProvider) findChannel(name string) (string, *mattermostChannel, error) {
+	name = strings.TrimPrefix(name, "#")
+	team := c.Team
+	paths := strings.SplitN(name, "/", 2)
+	if len(paths) == 2 {
+		team = paths[0]
+		name = paths[1]
+	}
+	if team == "" {
+		return team, nil, fmt.Errorf("No team specified for Mattermost channel %s. Please include the team in the server URL or use 'team/channel'", name)
+	}
+	channel := &mattermostChannel{}
+	err := c.client.do(http.MethodGet, c.apiURL("teams", "name", team, "channels", "name", name), nil, channel)
+	if err != nil {
+		return team, nil, fmt.Errorf("Failed to find Mattermost channel %s in team %s: %s", name, team, err)
+	}
+	return team, channel, nil
+}
+
+func (c *MattermostChatProvider) apiURL(paths ...string) string {
+	return util.UrlJoin(append([]string{c.BaseURL, "api", "v4"}, paths...)...)
+}
@@ -0,0 +1,70 @@
+package chats_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/chats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMattermostChatProvider(t *testing.T) {
+	t.Parallel()
+	posts := []map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer mytoken", r.Header.Get("Authorization"))
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v4/teams/name/myteam/channels/name/town-square":
+			fmt.Fprint(w, `{"id":"c1","name":"town-square","display_name":"Town Square","team_id":"t1"}`)
+		case "GET /api/v4/teams/name/other/channels/name/dev":
+			fmt.Fprint(w, `{"id":"c2","name":"dev","display_name":"Dev","team_id":"t2"}`)
+		case "GET /api/v4/channels/c1/stats":
+			fmt.Fprint(w, `{"channel_id":"c1","member_count":2}`)
+		case "GET /api/v4/channels/c1/members":
+			fmt.Fprint(w, `[{"user_id":"u1"},{"user_id":"u2"}]`)
+		case "POST /api/v4/posts":
+			post := map[string]string{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&post))
+			posts = append(posts, post)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id":"p%d","channel_id":"%s","message":"%s"}`, len(posts), post["channel_id"], post["message"])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := chats.CreateChatProvider(chats.Mattermost, &auth.AuthServer{URL: server.URL + "/myteam"}, &auth.UserAuth{Username: "bot", ApiToken: "mytoken"}, true)
+	assert.NoError(t, err)
+
+	metrics, err := provider.GetChannelMetrics("#town-square")
+	assert.NoError(t, err)
+	assert.Equal(t, "c1", metrics.ID)
+	assert.Equal(t, 2, metrics.MemberCount)
+	assert.Equal(t, []string{"u1", "u2"}, metrics.Members)
+	assert.Equal(t, server.URL+"/myteam/channels/town-square", metrics.URL)
+
+	message, err := provider.PostMessage("town-square", "build failed")
+	assert.NoError(t, err)
+	assert.Equal(t, "p1", message.ID)
+	assert.Equal(t, "c1", message.ChannelID)
+
+	reply, err := provider.PostThreadReply("other/dev", message.ID, "fixed")
+	assert.NoError(t, err)
+	assert.Equal(t, "p2", reply.ID)
+	assert.Equal(t, "p1", reply.ThreadID)
+
+	if assert.Len(t, posts, 2) {
+		assert.Equal(t, "build failed", posts[0]["message"])
+		assert.Equal(t, "", posts[0]["root_id"])
+		assert.Equal(t, "c2", posts[1]["channel_id"])
+		assert.Equal(t, "p1", posts[1]["root_id"])
+	}
+
+	_, err = provider.PostMessage("missing", "hello")
+	assert.Error(t, err, "missing channel")
+}
@@ -10,6 +10,22 @@ import (
 // CreateChatProvider represents an integration interface to chat
 type ChatProvider interface {
 	GetChannelMetrics(name string) (*ChannelMetrics, error)
+
+	// PostMessage posts a message to the channel
+	PostMessage(channel string, text string) (*Message, error)
+
+	// PostThreadReply posts a reply to the thread of a message previously posted to the channel
+	PostThreadReply(channel string, threadID string, text string) (*Message, error)
+}
+
+// Message a message posted to a channel
+type Message struct {
+	// ID identifies the message so that replies can be posted to its thread
+	ID string
+	// ChannelID the ID of the channel the message was posted to
+	ChannelID string
+	// ThreadID the ID of the thread the message was posted to if it is a reply
+	ThreadID string
 }
 
 // ChannelMetrics metrics for a channel
@@ -30,6 +46,12 @@ func CreateChatProvider(kind string, server *auth.AuthServer, userAuth *auth.Use
 	switch kind {
 	case Slack:
 		return CreateSlackChatProvider(server, userAuth, batchMode)
+	case Mattermost:
+		return CreateMattermostChatProvider(server, userAuth, batchMode)
+	case Teams:
+		return CreateTeamsChatProvider(server, userAuth, batchMode)
+	case RocketChat:
+		return CreateRocketChatProvider(server, userAuth, batchMode)
 	default:
 		return nil, fmt.Errorf("Unsupported chat provider kind: %s", kind)
 	}
@@ -39,6 +61,8 @@ func ProviderAccessTokenURL(kind string, url string) string {
 	switch kind {
 	case Slack:
 		return "https://my.slack.com/services/new/bot"
+	case RocketChat:
+		return util.UrlJoin(url, "account", "tokens")
 	default:
 		return ""
 	}
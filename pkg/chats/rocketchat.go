@@ -0,0 +1,181 @@
+package chats
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// RocketChatProvider a chat provider for Rocket.Chat using its REST API
+//
+// If the user has a password it is used to login, otherwise the API token is used as a personal access token
+// and the username must be the ID of the user
+type RocketChatProvider struct {
+	Server   *auth.AuthServer
+	UserAuth *auth.UserAuth
+
+	client *restClient
+}
+
+type rocketChatResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+type rocketChatLogin struct {
+	rocketChatResponse
+	Data struct {
+		UserID    string `json:"userId"`
+		AuthToken string `json:"authToken"`
+	} `json:"data"`
+}
+
+type rocketChatRoom struct {
+	ID         string `json:"_id"`
+	Name       string `json:"name"`
+	UsersCount int    `json:"usersCount"`
+}
+
+type rocketChatChannelInfo struct {
+	rocketChatResponse
+	Channel rocketChatRoom `json:"channel"`
+}
+
+type rocketChatUser struct {
+	ID       string `json:"_id"`
+	Username string `json:"username"`
+}
+
+type rocketChatMembers struct {
+	rocketChatResponse
+	Members []rocketChatUser `json:"members"`
+	Total   int              `json:"total"`
+}
+
+type rocketChatMessage struct {
+	ID       string `json:"_id,omitempty"`
+	RoomID   string `json:"rid"`
+	ThreadID string `json:"tmid,omitempty"`
+	Msg      string `json:"msg"`
+}
+
+type rocketChatSendMessage struct {
+	Message rocketChatMessage `json:"message"`
+}
+
+type rocketChatMessageResult struct {
+	rocketChatResponse
+	Message rocketChatMessage `json:"message"`
+}
+
+func CreateRocketChatProvider(server *auth.AuthServer, userAuth *auth.UserAuth, batchMode bool) (ChatProvider, error) {
+	u := server.URL
+	if u == "" {
+		return nil, fmt.Errorf("No base URL for server!")
+	}
+	if userAuth == nil || userAuth.Username == "" || (userAuth.ApiToken == "" && userAuth.Password == "") {
+		return nil, fmt.Errorf("No authentication found for Rocket.Chat server %s", u)
+	}
+	userID := userAuth.Username
+	token := userAuth.ApiToken
+	if userAuth.Password != "" {
+		login := &rocketChatLogin{}
+		body := map[string]string{
+			"user":     userAuth.Username,
+			"password": userAuth.Password,
+		}
+		err := newRestClient(nil).do(http.MethodPost, util.UrlJoin(u, "api", "v1", "login"), body, login)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to login to Rocket.Chat server %s: %s", u, err)
+		}
+		userID = login.Data.UserID
+		token = login.Data.AuthToken
+	}
+	return &RocketChatProvider{
+		Server:   server,
+		UserAuth: userAuth,
+		client: newRestClient(map[string]string{
+			"X-User-Id":    userID,
+			"X-Auth-Token": token,
+		}),
+	}, nil
+}
+
+func (c *RocketChatProvider) GetChannelMetrics(name string) (*ChannelMetrics, error) {
+	metrics := &ChannelMetrics{
+		Name: name,
+	}
+	channel, err := c.findChannel(name)
+	if err != nil {
+		return metrics, err
+	}
+	members := &rocketChatMembers{}
+	err = c.client.do(http.MethodGet, c.apiURL("channels.members")+"?roomId="+url.QueryEscape(channel.ID), nil, members)
+	if err != nil {
+		return metrics, err
+	}
+	metrics.ID = channel.ID
+	metrics.Name = channel.Name
+	metrics.MemberCount = channel.UsersCount
+	for _, member := range members.Members {
+		metrics.Members = append(metrics.Members, member.Username)
+	}
+	metrics.URL = util.UrlJoin(c.Server.URL, "channel", channel.Name)
+	return metrics, nil
+}
+
+func (c *RocketChatProvider) PostMessage(channel string, text string) (*Message, error) {
+	return c.sendMessage(channel, "", text)
+}
+
+func (c *RocketChatProvider) PostThreadReply(channel string, threadID string, text string) (*Message, error) {
+	return c.sendMessage(channel, threadID, text)
+}
+
+func (c *RocketChatProvider) sendMessage(name string, threadID string, text string) (*Message, error) {
+	channel, err := c.findChannel(name)
+	if err != nil {
+		return nil, err
+	}
+	body := &rocketChatSendMessage{
+		Message: rocketChatMessage{
+			RoomID:   channel.ID,
+			ThreadID: threadID,
+			Msg:      text,
+		},
+	}
+	result := &rocketChatMessageResult{}
+	err = c.client.do(http.MethodPost, c.apiURL("chat.sendMessage"), body, result)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, fmt.Errorf("Failed to send message to Rocket.Chat channel %s: %s", name, result.Error)
+	}
+	return &Message{
+		ID:        result.Message.ID,
+		ChannelID: channel.ID,
+		ThreadID:  threadID,
+	}, nil
+}
+
+func (c *RocketChatProvider) findChannel(name string) (*rocketChatRoom, error) {
+	name = strings.TrimPrefix(name, "#")
+	info := &rocketChatChannelInfo{}
+	err := c.client.do(http.MethodGet, c.apiURL("channels.info")+"?roomName="+url.QueryEscape(name), nil, info)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find Rocket.Chat channel %s: %s", name, err)
+	}
+	if !info.Success {
+		return nil, fmt.Errorf("Failed to find Rocket.Chat channel %s: %s", name, info.Error)
+	}
+	return &info.Channel, nil
+}
+
+func (c *RocketChatProvider) apiURL(method string) string {
+	return util.UrlJoin(c.Server.URL, "api", "v1", method)
+}
@@ -0,0 +1,82 @@
+package chats_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/chats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRocketChatProvider(t *testing.T) {
+	t.Parallel()
+	sent := []map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/login" {
+			body := map[string]string{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			if body["user"] != "bot" || body["password"] != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"status":"success","data":{"userId":"u1","authToken":"token1"}}`)
+			return
+		}
+		assert.Equal(t, "u1", r.Header.Get("X-User-Id"))
+		assert.Equal(t, "token1", r.Header.Get("X-Auth-Token"))
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/channels.info":
+			if r.URL.Query().Get("roomName") != "general" {
+				fmt.Fprint(w, `{"success":false,"error":"The required roomName param is invalid"}`)
+				return
+			}
+			fmt.Fprint(w, `{"success":true,"channel":{"_id":"GENERAL","name":"general","usersCount":2}}`)
+		case "GET /api/v1/channels.members":
+			assert.Equal(t, "GENERAL", r.URL.Query().Get("roomId"))
+			fmt.Fprint(w, `{"success":true,"members":[{"_id":"u1","username":"bot"},{"_id":"u2","username":"alice"}],"total":2}`)
+		case "POST /api/v1/chat.sendMessage":
+			body := map[string]map[string]string{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			sent = append(sent, body["message"])
+			fmt.Fprintf(w, `{"success":true,"message":{"_id":"m%d","rid":"%s","msg":"%s"}}`, len(sent), body["message"]["rid"], body["message"]["msg"])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	_, err := chats.CreateChatProvider(chats.RocketChat, &auth.AuthServer{URL: server.URL}, &auth.UserAuth{Username: "bot", Password: "wrong"}, true)
+	assert.Error(t, err, "invalid password")
+
+	provider, err := chats.CreateChatProvider(chats.RocketChat, &auth.AuthServer{URL: server.URL}, &auth.UserAuth{Username: "bot", Password: "secret"}, true)
+	assert.NoError(t, err)
+
+	metrics, err := provider.GetChannelMetrics("#general")
+	assert.NoError(t, err)
+	assert.Equal(t, "GENERAL", metrics.ID)
+	assert.Equal(t, 2, metrics.MemberCount)
+	assert.Equal(t, []string{"bot", "alice"}, metrics.Members)
+	assert.Equal(t, server.URL+"/channel/general", metrics.URL)
+
+	message, err := provider.PostMessage("general", "build failed")
+	assert.NoError(t, err)
+	assert.Equal(t, "m1", message.ID)
+
+	reply, err := provider.PostThreadReply("general", message.ID, "build fixed")
+	assert.NoError(t, err)
+	assert.Equal(t, "m2", reply.ID)
+
+	if assert.Len(t, sent, 2) {
+		assert.Equal(t, "GENERAL", sent[0]["rid"])
+		assert.Equal(t, "", sent[0]["tmid"])
+		assert.Equal(t, "m1", sent[1]["tmid"])
+		assert.Equal(t, "build fixed", sent[1]["msg"])
+	}
+
+	_, err = provider.PostMessage("random", "hello")
+	assert.Error(t, err, "missing channel")
+}
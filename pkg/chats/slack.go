@@ -62,3 +62,25 @@ func (c *SlackChatProvider) GetChannelMetrics(name string) (*ChannelMetrics, err
 	metrics.URL = util.UrlJoin(c.Server.URL, "messages", info.ID)
 	return metrics, nil
 }
+
+func (c *SlackChatProvider) PostMessage(channel string, text string) (*Message, error) {
+	return c.postMessage(channel, "", text)
+}
+
+func (c *SlackChatProvider) PostThreadReply(channel string, threadID string, text string) (*Message, error) {
+	return c.postMessage(channel, threadID, text)
+}
+
+func (c *SlackChatProvider) postMessage(channel string, threadID string, text string) (*Message, error) {
+	params := slack.NewPostMessageParameters()
+	params.ThreadTimestamp = threadID
+	channelID, timestamp, err := c.SlackClient.PostMessage(channel, text, params)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{
+		ID:        timestamp,
+		ChannelID: channelID,
+		ThreadID:  threadID,
+	}, nil
+}
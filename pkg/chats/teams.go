@@ -0,0 +1,145 @@
+package chats
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// TeamsChatProvider a chat provider for Microsoft Teams using the Microsoft Graph API
+//
+// The server URL should be the Graph API URL of the team such as
+// https://graph.microsoft.com/v1.0/teams/<team-id> and the API token should be an OAuth access token
+// with permission to read the channels of the team and to send channel messages.
+// Channels are specified by their display name or ID
+type TeamsChatProvider struct {
+	Server   *auth.AuthServer
+	UserAuth *auth.UserAuth
+
+	client *restClient
+}
+
+type teamsChannel struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	WebURL      string `json:"webUrl"`
+}
+
+type teamsChannels struct {
+	Value []teamsChannel `json:"value"`
+}
+
+type teamsMember struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+}
+
+type teamsMembers struct {
+	Value []teamsMember `json:"value"`
+}
+
+type teamsItemBody struct {
+	ContentType string `json:"contentType,omitempty"`
+	Content     string `json:"content"`
+}
+
+type teamsChatMessage struct {
+	ID   string        `json:"id,omitempty"`
+	Body teamsItemBody `json:"body"`
+}
+
+func CreateTeamsChatProvider(server *auth.AuthServer, userAuth *auth.UserAuth, batchMode bool) (ChatProvider, error) {
+	u := server.URL
+	if u == "" {
+		return nil, fmt.Errorf("No base URL for server!")
+	}
+	if userAuth == nil || userAuth.IsInvalid() || userAuth.ApiToken == "" {
+		return nil, fmt.Errorf("No authentication found for Teams server %s", u)
+	}
+	if !strings.Contains(u, "/teams/") {
+		return nil, fmt.Errorf("The Teams server URL %s should be the Graph API URL of a team such as https://graph.microsoft.com/v1.0/teams/<team-id>", u)
+	}
+	return &TeamsChatProvider{
+		Server:   server,
+		UserAuth: userAuth,
+		client: newRestClient(map[string]string{
+			"Authorization": "Bearer " + userAuth.ApiToken,
+		}),
+	}, nil
+}
+
+func (c *TeamsChatProvider) GetChannelMetrics(name string) (*ChannelMetrics, error) {
+	metrics := &ChannelMetrics{
+		Name: name,
+	}
+	channel, err := c.findChannel(name)
+	if err != nil {
+		return metrics, err
+	}
+	members := &teamsMembers{}
+	err = c.client.do(http.MethodGet, util.UrlJoin(c.Server.URL, "channels", channel.ID, "members"), nil, members)
+	if err != nil {
+		return metrics, err
+	}
+	metrics.ID = channel.ID
+	metrics.Name = channel.DisplayName
+	metrics.URL = channel.WebURL
+	metrics.MemberCount = len(members.Value)
+	for _, member := range members.Value {
+		metrics.Members = append(metrics.Members, member.DisplayName)
+	}
+	return metrics, nil
+}
+
+func (c *TeamsChatProvider) PostMessage(channel string, text string) (*Message, error) {
+	return c.sendMessage(channel, "", text)
+}
+
+func (c *TeamsChatProvider) PostThreadReply(channel string, threadID string, text string) (*Message, error) {
+	return c.sendMessage(channel, threadID, text)
+}
+
+func (c *TeamsChatProvider) sendMessage(name string, threadID string, text string) (*Message, error) {
+	channel, err := c.findChannel(name)
+	if err != nil {
+		return nil, err
+	}
+	u := util.UrlJoin(c.Server.URL, "channels", channel.ID, "messages")
+	if threadID != "" {
+		u = util.UrlJoin(u, threadID, "replies")
+	}
+	message := &teamsChatMessage{
+		Body: teamsItemBody{
+			Content: text,
+		},
+	}
+	result := &teamsChatMessage{}
+	err = c.client.do(http.MethodPost, u, message, result)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{
+		ID:        result.ID,
+		ChannelID: channel.ID,
+		ThreadID:  threadID,
+	}, nil
+}
+
+func (c *TeamsChatProvider) findChannel(name string) (*teamsChannel, error) {
+	name = strings.TrimPrefix(name, "#")
+	channels := &teamsChannels{}
+	err := c.client.do(http.MethodGet, util.UrlJoin(c.Server.URL, "channels"), nil, channels)
+	if err != nil {
+		return nil, err
+	}
+	for i := range channels.Value {
+		channel := &channels.Value[i]
+		if channel.ID == name || strings.EqualFold(channel.DisplayName, name) {
+			return channel, nil
+		}
+	}
+	return nil, fmt.Errorf("Failed to find Teams channel %s", name)
+}
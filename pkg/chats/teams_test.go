@@ -0,0 +1,65 @@
+package chats_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/chats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamsChatProvider(t *testing.T) {
+	t.Parallel()
+	messages := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer mytoken", r.Header.Get("Authorization"))
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v1.0/teams/team1/channels":
+			fmt.Fprint(w, `{"value":[{"id":"19:general","displayName":"General","webUrl":"https://teams.microsoft.com/l/channel/general"},{"id":"19:dev","displayName":"Developers"}]}`)
+		case "GET /v1.0/teams/team1/channels/19:general/members":
+			fmt.Fprint(w, `{"value":[{"id":"m1","displayName":"Alice"},{"id":"m2","displayName":"Bob"},{"id":"m3","displayName":"Carol"}]}`)
+		case "POST /v1.0/teams/team1/channels/19:dev/messages", "POST /v1.0/teams/team1/channels/19:dev/messages/msg1/replies":
+			body := map[string]map[string]string{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			id := fmt.Sprintf("msg%d", len(messages)+1)
+			messages[r.URL.Path] = body["body"]["content"]
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id":"%s"}`, id)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	_, err := chats.CreateChatProvider(chats.Teams, &auth.AuthServer{URL: server.URL}, &auth.UserAuth{Username: "bot", ApiToken: "mytoken"}, true)
+	assert.Error(t, err, "URL without a team")
+
+	provider, err := chats.CreateChatProvider(chats.Teams, &auth.AuthServer{URL: server.URL + "/v1.0/teams/team1"}, &auth.UserAuth{Username: "bot", ApiToken: "mytoken"}, true)
+	assert.NoError(t, err)
+
+	metrics, err := provider.GetChannelMetrics("general")
+	assert.NoError(t, err)
+	assert.Equal(t, "19:general", metrics.ID)
+	assert.Equal(t, "General", metrics.Name)
+	assert.Equal(t, 3, metrics.MemberCount)
+	assert.Equal(t, "https://teams.microsoft.com/l/channel/general", metrics.URL)
+
+	message, err := provider.PostMessage("Developers", "release 1.0.1 is out")
+	assert.NoError(t, err)
+	assert.Equal(t, "msg1", message.ID)
+	assert.Equal(t, "19:dev", message.ChannelID)
+
+	reply, err := provider.PostThreadReply("19:dev", message.ID, "promoted to production")
+	assert.NoError(t, err)
+	assert.Equal(t, "msg2", reply.ID)
+
+	assert.Equal(t, "release 1.0.1 is out", messages["/v1.0/teams/team1/channels/19:dev/messages"])
+	assert.Equal(t, "promoted to production", messages["/v1.0/teams/team1/channels/19:dev/messages/msg1/replies"])
+
+	_, err = provider.PostMessage("random", "hello")
+	assert.Error(t, err, "missing channel")
+}
@@ -1,7 +1,10 @@
 package amazon
 
 import (
+	"io"
+
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
@@ -27,3 +30,56 @@ func CreateS3Bucket(bucketName string, profile string, region string) (string, e
 	}
 	return location, err
 }
+
+// PutS3Object uploads the body to the key in the S3 bucket returning the URL of the object
+func PutS3Object(bucketName string, key string, body io.ReadSeeker, profile string, region string) (string, error) {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return "", err
+	}
+	svc := s3.New(sess)
+	_, err = svc.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+		Body:   body,
+	})
+	if err != nil {
+		return "", err
+	}
+	return "s3://" + bucketName + "/" + key, nil
+}
+
+// NewS3Client creates an S3 client. If the endpoint is not empty the client uses the S3 compatible object store
+// at the endpoint such as Minio with path style addressing
+func NewS3Client(profile string, region string, endpoint string) (*s3.S3, error) {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == "" {
+		return s3.New(sess), nil
+	}
+	return s3.New(sess, &aws.Config{
+		Endpoint:         aws.String(endpoint),
+		S3ForcePathStyle: aws.Bool(true),
+	}), nil
+}
+
+// S3BucketExists returns true if the S3 bucket exists and is accessible
+func S3BucketExists(bucketName string, profile string, region string) (bool, error) {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return false, err
+	}
+	svc := s3.New(sess)
+	_, err = svc.HeadBucket(&s3.HeadBucketInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && (aerr.Code() == s3.ErrCodeNoSuchBucket || aerr.Code() == "NotFound") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
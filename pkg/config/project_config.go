@@ -5,15 +5,23 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"reflect"
+	"strings"
 
 	"github.com/jenkins-x/jx/pkg/util"
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 const (
 	// ProjectConfigFileName is the name of the project configuration file
 	ProjectConfigFileName = "jenkins-x.yml"
+
+	// DevPodIDETheia the Theia browser based IDE which is added to DevPods by default
+	DevPodIDETheia = "theia"
+
+	// DevPodIDENone no browser based IDE is added to the DevPod
+	DevPodIDENone = "none"
 )
 
 type ProjectConfig struct {
@@ -30,17 +38,89 @@ type ProjectConfig struct {
 	BuildPackGitURL     string                    `yaml:"buildPackGitURL,omitempty"`
 	BuildPackGitURef    string                    `yaml:"buildPackGitRef,omitempty"`
 	Workflow            string                    `yaml:"workflow,omitempty"`
+	Verify              *VerifyConfig             `yaml:"verify,omitempty"`
+	DevPod              *DevPodConfig             `yaml:"devpod,omitempty"`
 }
 
 type PreviewEnvironmentConfig struct {
 	Disabled         bool `yaml:"disabled,omitempty"`
 	MaximumInstances int  `yaml:"maximumInstances,omitempty"`
+
+	// the apps and charts installed into each preview environment before the preview itself
+	Dependencies []*PreviewDependency `yaml:"dependencies,omitempty"`
+
+	// the Jobs which seed the dependencies with data before the preview URL is posted
+	SeedJobs []*PreviewSeedJob `yaml:"seedJobs,omitempty"`
+}
+
+// PreviewDependency an app or chart installed into a preview environment which is deleted with the preview
+type PreviewDependency struct {
+	// the name of the dependency which is also the app name used to find the version running in the environment
+	Name string `yaml:"name,omitempty"`
+
+	// the chart to install which defaults to the chart of the app in the chart repository of the team
+	Chart string `yaml:"chart,omitempty"`
+
+	// the URL of the chart repository if the chart is not in the chart repository of the team
+	Repository string `yaml:"repository,omitempty"`
+
+	// the version of the chart
+	Version string `yaml:"version,omitempty"`
+
+	// pins the version of the chart to the version of the app running in this environment such as staging
+	Environment string `yaml:"environment,omitempty"`
+
+	// the values set on the chart as key=value and the values files relative to the project directory
+	Values     []string `yaml:"values,omitempty"`
+	ValueFiles []string `yaml:"valueFiles,omitempty"`
+}
+
+// PreviewSeedJob a Job which populates the dependencies of a preview environment with data
+type PreviewSeedJob struct {
+	Name    string          `yaml:"name,omitempty"`
+	Image   string          `yaml:"image,omitempty"`
+	Command []string        `yaml:"command,omitempty"`
+	Args    []string        `yaml:"args,omitempty"`
+	Env     []corev1.EnvVar `yaml:"env,omitempty"`
+
+	// how long to wait for the Job to complete which defaults to the post preview Job timeout
+	Timeout string `yaml:"timeout,omitempty"`
 }
 
 type IssueTrackerConfig struct {
 	Kind    string `yaml:"kind,omitempty"`
 	URL     string `yaml:"url,omitempty"`
 	Project string `yaml:"project,omitempty"`
+
+	// the workflow transitions of the issues referenced by a release when it is promoted to an environment
+	Transitions []*IssueTransitionConfig `yaml:"transitions,omitempty"`
+}
+
+// IssueTransitionConfig the changes made to the issues referenced by a release when it is promoted to an environment
+type IssueTransitionConfig struct {
+	// the name of the environment such as staging or production
+	Environment string `yaml:"environment,omitempty"`
+
+	// the status the issues are moved to such as "In QA" or "Done"
+	Status string `yaml:"status,omitempty"`
+
+	// whether the version of the release is added to the fix versions of the issues
+	FixVersion bool `yaml:"fixVersion,omitempty"`
+}
+
+// EnvironmentTransition returns the transition for promotions to the environment with any of the given names or nil
+func (c *IssueTrackerConfig) EnvironmentTransition(envNames ...string) *IssueTransitionConfig {
+	for _, t := range c.Transitions {
+		if t == nil {
+			continue
+		}
+		for _, name := range envNames {
+			if name != "" && strings.EqualFold(t.Environment, name) {
+				return t
+			}
+		}
+	}
+	return nil
 }
 
 type WikiConfig struct {
@@ -54,6 +134,23 @@ type ChatConfig struct {
 	URL              string `yaml:"url,omitempty"`
 	DeveloperChannel string `yaml:"developerChannel,omitempty"`
 	UserChannel      string `yaml:"userChannel,omitempty"`
+
+	// the rules deciding which notifications 'jx controller notify' posts to which channel
+	Notifications []*ChatNotificationRule `yaml:"notifications,omitempty"`
+}
+
+// ChatNotificationRule posts notifications of the given kinds to a channel
+type ChatNotificationRule struct {
+	// the kinds of notification such as buildFailed, release, promotePullRequest or preview. All kinds if empty
+	Kinds []string `yaml:"kinds,omitempty"`
+
+	// the channel to post to which defaults to the developer channel
+	Channel string `yaml:"channel,omitempty"`
+}
+
+// Matches returns true if the rule applies to notifications of the given kind
+func (r *ChatNotificationRule) Matches(kind string) bool {
+	return len(r.Kinds) == 0 || util.StringArrayIndex(r.Kinds, kind) >= 0
 }
 
 type AddonConfig struct {
@@ -61,6 +158,134 @@ type AddonConfig struct {
 	Version string `yaml:"version,omitempty"`
 }
 
+// VerifyConfig the deployment verification checks performed by 'jx step verify'
+type VerifyConfig struct {
+	// how long the checks are retried for before the verification fails
+	Timeout string `yaml:"timeout,omitempty"`
+
+	// how long to wait between attempts of a failing check
+	Interval string `yaml:"interval,omitempty"`
+
+	Checks []*VerifyCheck `yaml:"checks,omitempty"`
+}
+
+// VerifyCheck a single verification check which should specify one of HTTP, Prometheus or Job
+type VerifyCheck struct {
+	Name string `yaml:"name,omitempty"`
+
+	// overrides the timeout of the verification for this check
+	Timeout string `yaml:"timeout,omitempty"`
+
+	HTTP       *HTTPVerifyCheck       `yaml:"http,omitempty"`
+	Prometheus *PrometheusVerifyCheck `yaml:"prometheus,omitempty"`
+	Job        *JobVerifyCheck        `yaml:"job,omitempty"`
+}
+
+// HTTPVerifyCheck probes an HTTP endpoint of the application
+type HTTPVerifyCheck struct {
+	// the URL to probe. If not specified the path is resolved against the URL of the application
+	URL  string `yaml:"url,omitempty"`
+	Path string `yaml:"path,omitempty"`
+
+	Method  string            `yaml:"method,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// the acceptable status codes which default to 200
+	ExpectedStatus []int `yaml:"expectedStatus,omitempty"`
+
+	// the response body must contain this text and match this regular expression if they are specified
+	BodyContains string `yaml:"bodyContains,omitempty"`
+	BodyRegex    string `yaml:"bodyRegex,omitempty"`
+}
+
+// PrometheusVerifyCheck evaluates a Prometheus query whose value must be within the minimum and maximum
+type PrometheusVerifyCheck struct {
+	// the URL of the Prometheus server which defaults to the Prometheus addon
+	URL   string   `yaml:"url,omitempty"`
+	Query string   `yaml:"query,omitempty"`
+	Min   *float64 `yaml:"min,omitempty"`
+	Max   *float64 `yaml:"max,omitempty"`
+}
+
+// JobVerifyCheck runs a smoke test as a Kubernetes Job which must succeed
+type JobVerifyCheck struct {
+	Image   string          `yaml:"image,omitempty"`
+	Command []string        `yaml:"command,omitempty"`
+	Args    []string        `yaml:"args,omitempty"`
+	Env     []corev1.EnvVar `yaml:"env,omitempty"`
+}
+
+// DevPodConfig the DevPod which 'jx create devpod' creates for the project so that it is shared by the team
+type DevPodConfig struct {
+	// the label of the pod template the DevPod is based on
+	Label string `yaml:"label,omitempty"`
+
+	// the first container is the one shells and file synchronisation use and is based on the first container of the
+	// pod template. Any other containers are added as sidecars
+	Containers []*DevPodContainer `yaml:"containers,omitempty"`
+
+	// the ports exposed by the DevPod and the environment variables added to each of its containers
+	Ports []int           `yaml:"ports,omitempty"`
+	Env   []corev1.EnvVar `yaml:"env,omitempty"`
+
+	// the shell commands run in the DevPod after it is created
+	InitCommands []string `yaml:"initCommands,omitempty"`
+
+	// the browser based IDE which is either theia (the default) or none
+	IDE string `yaml:"ide,omitempty"`
+
+	// the size of the persistent volume claim of the workspace such as 10Gi. The claim is kept when the DevPod is
+	// stopped so that the workspace is restored the next time the DevPod is created
+	WorkspaceSize string `yaml:"workspaceSize,omitempty"`
+
+	// how long the DevPod can go without any shells or file synchronisation before 'jx gc devpods' stops it
+	IdleTimeout string `yaml:"idleTimeout,omitempty"`
+}
+
+// DevPodContainer a container of the DevPod
+type DevPodContainer struct {
+	Name      string           `yaml:"name,omitempty"`
+	Image     string           `yaml:"image,omitempty"`
+	Command   []string         `yaml:"command,omitempty"`
+	Args      []string         `yaml:"args,omitempty"`
+	Env       []corev1.EnvVar  `yaml:"env,omitempty"`
+	Ports     []int            `yaml:"ports,omitempty"`
+	Resources *DevPodResources `yaml:"resources,omitempty"`
+}
+
+// DevPodResources the resources of a DevPod container keyed by the resource name such as cpu or memory
+type DevPodResources struct {
+	Requests map[string]string `yaml:"requests,omitempty"`
+	Limits   map[string]string `yaml:"limits,omitempty"`
+}
+
+// ResourceRequirements returns the Kubernetes resource requirements of the resources
+func (r *DevPodResources) ResourceRequirements() (corev1.ResourceRequirements, error) {
+	answer := corev1.ResourceRequirements{}
+	var err error
+	answer.Requests, err = toResourceList(r.Requests)
+	if err != nil {
+		return answer, err
+	}
+	answer.Limits, err = toResourceList(r.Limits)
+	return answer, err
+}
+
+func toResourceList(values map[string]string) (corev1.ResourceList, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	answer := corev1.ResourceList{}
+	for name, value := range values {
+		q, err := resource.ParseQuantity(value)
+		if err != nil {
+			return answer, fmt.Errorf("Invalid %s resource %s: %s", name, value, err)
+		}
+		answer[corev1.ResourceName(name)] = q
+	}
+	return answer, nil
+}
+
 type BranchBuild struct {
 	Build Build `yaml:"build,omitempty"`
 
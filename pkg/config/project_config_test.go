@@ -47,3 +47,161 @@ func TestProjectConfigMarshal(t *testing.T) {
 	assert.True(t, projectConfig.Builds[0].ExcludePodTemplateEnv)
 	assert.True(t, projectConfig.Builds[0].ExcludePodTemplateVolumes)
 }
+
+func TestProjectConfigVerifyUnmarshal(t *testing.T) {
+	t.Parallel()
+	text := `verify:
+  timeout: 3m
+  checks:
+  - name: health
+    http:
+      path: /health
+      expectedStatus: [200, 204]
+      bodyContains: UP
+  - prometheus:
+      query: sum(rate(http_errors_total[1m]))
+      max: 0.5
+  - job:
+      image: myorg/smoke-tests:1.0.0
+      command: [./smoke.sh]
+`
+	projectConfig := &config.ProjectConfig{}
+	err := yaml.Unmarshal([]byte(text), projectConfig)
+	assert.NoError(t, err)
+
+	verify := projectConfig.Verify
+	if assert.NotNil(t, verify, "verify") && assert.Len(t, verify.Checks, 3, "checks") {
+		assert.Equal(t, "3m", verify.Timeout)
+		assert.Equal(t, []int{200, 204}, verify.Checks[0].HTTP.ExpectedStatus)
+		assert.Equal(t, "UP", verify.Checks[0].HTTP.BodyContains)
+		if assert.NotNil(t, verify.Checks[1].Prometheus.Max, "max") {
+			assert.Equal(t, 0.5, *verify.Checks[1].Prometheus.Max)
+		}
+		assert.Nil(t, verify.Checks[1].Prometheus.Min, "min")
+		assert.Equal(t, []string{"./smoke.sh"}, verify.Checks[2].Job.Command)
+	}
+}
+
+func TestIssueTrackerEnvironmentTransition(t *testing.T) {
+	t.Parallel()
+	text := `issueTracker:
+  kind: jira
+  url: https://myorg.atlassian.net
+  project: PROJ
+  transitions:
+  - environment: staging
+    status: In QA
+  - environment: production
+    status: Done
+    fixVersion: true
+`
+	projectConfig := &config.ProjectConfig{}
+	err := yaml.Unmarshal([]byte(text), projectConfig)
+	assert.NoError(t, err)
+
+	tracker := projectConfig.IssueTracker
+	if assert.NotNil(t, tracker, "issueTracker") {
+		staging := tracker.EnvironmentTransition("env-staging", "Staging")
+		if assert.NotNil(t, staging, "staging transition") {
+			assert.Equal(t, "In QA", staging.Status)
+			assert.False(t, staging.FixVersion)
+		}
+		production := tracker.EnvironmentTransition("production")
+		if assert.NotNil(t, production, "production transition") {
+			assert.Equal(t, "Done", production.Status)
+			assert.True(t, production.FixVersion)
+		}
+		assert.Nil(t, tracker.EnvironmentTransition("dev", ""), "dev transition")
+	}
+}
+
+func TestProjectConfigPreviewDependenciesUnmarshal(t *testing.T) {
+	t.Parallel()
+	text := `previewEnvironments:
+  maximumInstances: 3
+  dependencies:
+  - name: postgresql
+    chart: stable/postgresql
+    repository: https://kubernetes-charts.storage.googleapis.com
+    version: 3.1.0
+    values:
+    - postgresqlDatabase=orders
+  - name: customers
+    environment: staging
+  seedJobs:
+  - name: restore
+    image: postgres:10
+    command: [pg_restore]
+    args: [--dbname, orders, /snapshots/orders.dump]
+    timeout: 5m
+`
+	projectConfig := &config.ProjectConfig{}
+	err := yaml.Unmarshal([]byte(text), projectConfig)
+	assert.NoError(t, err)
+
+	previews := projectConfig.PreviewEnvironments
+	if assert.NotNil(t, previews, "previewEnvironments") && assert.Len(t, previews.Dependencies, 2, "dependencies") {
+		assert.Equal(t, 3, previews.MaximumInstances)
+		assert.Equal(t, "stable/postgresql", previews.Dependencies[0].Chart)
+		assert.Equal(t, "3.1.0", previews.Dependencies[0].Version)
+		assert.Equal(t, []string{"postgresqlDatabase=orders"}, previews.Dependencies[0].Values)
+		assert.Equal(t, "staging", previews.Dependencies[1].Environment)
+		if assert.Len(t, previews.SeedJobs, 1, "seedJobs") {
+			assert.Equal(t, "postgres:10", previews.SeedJobs[0].Image)
+			assert.Equal(t, []string{"pg_restore"}, previews.SeedJobs[0].Command)
+			assert.Equal(t, "5m", previews.SeedJobs[0].Timeout)
+		}
+	}
+}
+
+func TestProjectConfigDevPodUnmarshal(t *testing.T) {
+	t.Parallel()
+	text := `devpod:
+  label: go
+  containers:
+  - image: golang:1.11
+    resources:
+      requests:
+        cpu: 500m
+        memory: 1Gi
+      limits:
+        memory: 2Gi
+  - name: postgres
+    image: postgres:10
+    ports: [5432]
+  ports: [8080]
+  env:
+  - name: DB_HOST
+    value: localhost
+  initCommands:
+  - make deps
+  ide: none
+  workspaceSize: 10Gi
+  idleTimeout: 2h
+`
+	projectConfig := &config.ProjectConfig{}
+	err := yaml.Unmarshal([]byte(text), projectConfig)
+	assert.NoError(t, err)
+
+	devPod := projectConfig.DevPod
+	if assert.NotNil(t, devPod, "devpod") && assert.Len(t, devPod.Containers, 2, "containers") {
+		assert.Equal(t, "go", devPod.Label)
+		assert.Equal(t, []int{8080}, devPod.Ports)
+		assert.Equal(t, "localhost", devPod.Env[0].Value)
+		assert.Equal(t, []string{"make deps"}, devPod.InitCommands)
+		assert.Equal(t, config.DevPodIDENone, devPod.IDE)
+		assert.Equal(t, "10Gi", devPod.WorkspaceSize)
+		assert.Equal(t, "2h", devPod.IdleTimeout)
+		assert.Equal(t, []int{5432}, devPod.Containers[1].Ports)
+
+		resources, err := devPod.Containers[0].Resources.ResourceRequirements()
+		assert.NoError(t, err)
+		cpu := resources.Requests["cpu"]
+		memory := resources.Limits["memory"]
+		assert.Equal(t, "500m", cpu.String())
+		assert.Equal(t, "2Gi", memory.String())
+	}
+
+	_, err = (&config.DevPodResources{Requests: map[string]string{"cpu": "lots"}}).ResourceRequirements()
+	assert.Error(t, err)
+}
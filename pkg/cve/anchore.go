@@ -28,6 +28,8 @@ type result interface {
 type VulnerabilityList struct {
 	ImageDigest     string
 	Vulnerabilities []Vulnerability
+	// Image is the name of the image the vulnerabilities were found in
+	Image string `json:"-"`
 }
 
 type Vulnerability struct {
@@ -44,11 +46,12 @@ type Image struct {
 }
 
 type ImageDetail struct {
-	Registry string
-	Repo     string
-	Tag      string
-	ImageId  string
-	Fulltag  string
+	Registry   string
+	Repo       string
+	Tag        string
+	ImageId    string
+	Fulltag    string
+	Fulldigest string
 }
 
 // AnchoreProvider implements CVEProvider interface for anchore.io
@@ -186,19 +189,46 @@ func (a AnchoreProvider) addVulnerabilitiesTableRows(table *table.Table, vList *
 	}
 	// TODO sort vList on severity and version?
 
-	for _, v := range vList.Vulnerabilities {
-		var sev string
-		switch v.Severity {
-		case "High":
-			sev = util.ColorError(v.Severity)
-		case "Medium":
-			sev = util.ColorWarning(v.Severity)
-		case "Low":
-			sev = util.ColorStatus(v.Severity)
+	AddVulnerabilityRows(table, image[0].ImageDetails[0].Fulltag, vList.Vulnerabilities)
+	return nil
+}
+
+// GetImageVulnerabilities returns the vulnerabilities of an image which has been analysed by the anchore engine
+func (a AnchoreProvider) GetImageVulnerabilities(image string) (*VulnerabilityList, error) {
+	var images []Image
+	err := a.AnchoreGet(GetImages, &images)
+	if err != nil {
+		return nil, fmt.Errorf("error getting images %v", err)
+	}
+	for _, i := range images {
+		for _, d := range i.ImageDetails {
+			if !d.matches(image) {
+				continue
+			}
+			if i.AnalysisStatus != "" && i.AnalysisStatus != "analyzed" {
+				return nil, fmt.Errorf("image %s has not been analyzed by the anchore engine yet, its status is %s", image, i.AnalysisStatus)
+			}
+			vList := &VulnerabilityList{}
+			subPath := fmt.Sprintf(getVulnerabilitiesByImageID, d.ImageId, vulnerabilityType)
+			err = a.AnchoreGet(subPath, vList)
+			if err != nil {
+				return nil, fmt.Errorf("error getting vulnerabilities for image %s: %v", image, err)
+			}
+			vList.Image = image
+			return vList, nil
 		}
-		table.AddRow(image[0].ImageDetails[0].Fulltag, sev, v.Vuln, v.URL, v.Package, v.Fix)
 	}
-	return nil
+	return nil, fmt.Errorf("image %s has not been added to the anchore engine", image)
+}
+
+// matches returns true if the image name, which may omit the docker.io registry, refers to this image by tag or digest
+func (d *ImageDetail) matches(image string) bool {
+	for _, name := range []string{d.Fulltag, d.Fulldigest} {
+		if name != "" && (name == image || name == "docker.io/"+image) {
+			return true
+		}
+	}
+	return d.Repo+":"+d.Tag == image
 }
 
 func (a AnchoreProvider) getCVEsFromImageList(table *table.Table, vList *VulnerabilityList, ids []string) error {
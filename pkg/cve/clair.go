@@ -0,0 +1,208 @@
+package cve
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/table"
+	"github.com/jenkins-x/jx/pkg/util"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	getClairVulnerabilityReport = "/matcher/api/v1/vulnerability_report/%s"
+)
+
+// ClairProvider implements CVEProvider interface for the vulnerability reports of a Clair server.
+// Images are looked up by the digest of their manifest which must have been indexed by Clair,
+// for example by the registry or by running clairctl in the pipeline
+type ClairProvider struct {
+	Client      *http.Client
+	BearerToken string
+	BaseURL     string
+}
+
+type clairPackage struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type clairVulnerability struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	Links              string `json:"links"`
+	Severity           string `json:"severity"`
+	NormalizedSeverity string `json:"normalized_severity"`
+	FixedInVersion     string `json:"fixed_in_version"`
+}
+
+// ClairVulnerabilityReport the vulnerability report of a manifest returned by the Clair matcher API
+type ClairVulnerabilityReport struct {
+	ManifestHash           string                         `json:"manifest_hash"`
+	Packages               map[string]*clairPackage       `json:"packages"`
+	Vulnerabilities        map[string]*clairVulnerability `json:"vulnerabilities"`
+	PackageVulnerabilities map[string][]string            `json:"package_vulnerabilities"`
+}
+
+// NewClairProvider creates a CVE provider for the Clair server
+func NewClairProvider(server *auth.AuthServer, user *auth.UserAuth) (CVEProvider, error) {
+	if server.URL == "" {
+		return nil, fmt.Errorf("no URL for the Clair server")
+	}
+	provider := ClairProvider{
+		BaseURL: strings.TrimSuffix(server.URL, "/"),
+		Client:  http.DefaultClient,
+	}
+	if user != nil {
+		provider.BearerToken = user.BearerToken
+		if provider.BearerToken == "" {
+			provider.BearerToken = user.ApiToken
+		}
+	}
+	return &provider, nil
+}
+
+func (c ClairProvider) GetImageVulnerabilityTable(jxClient versioned.Interface, client kubernetes.Interface, table *table.Table, query CVEQuery) error {
+	images := []string{}
+	if query.ImageID != "" {
+		images = append(images, query.ImageID)
+	} else if query.ImageName != "" {
+		images = append(images, query.ImageName)
+	} else if query.Environment != "" {
+		// the image IDs of the running containers include the digest of the manifest
+		podList, err := client.CoreV1().Pods(query.TargetNamespace).List(meta_v1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for _, p := range podList.Items {
+			for _, s := range p.Status.ContainerStatuses {
+				image := strings.TrimPrefix(s.ImageID, "docker-pullable://")
+				if ImageDigest(image) != "" && util.StringArrayIndex(images, image) < 0 {
+					images = append(images, image)
+				}
+			}
+		}
+	} else {
+		return fmt.Errorf("choose an image name with a digest, a manifest digest or an environment to find vulnerabilities")
+	}
+
+	for _, image := range images {
+		vList, err := c.GetImageVulnerabilities(image)
+		if err != nil {
+			return err
+		}
+		AddVulnerabilityRows(table, image, vList.Vulnerabilities)
+	}
+	return nil
+}
+
+// GetImageVulnerabilities returns the vulnerabilities of the image which must be referenced by the digest of its manifest
+func (c ClairProvider) GetImageVulnerabilities(image string) (*VulnerabilityList, error) {
+	digest := ImageDigest(image)
+	if digest == "" {
+		return nil, fmt.Errorf("image %s must be referenced by its digest such as myorg/myapp@sha256:... to be looked up in Clair", image)
+	}
+	report := &ClairVulnerabilityReport{}
+	err := c.ClairGet(fmt.Sprintf(getClairVulnerabilityReport, digest), report)
+	if err != nil {
+		return nil, fmt.Errorf("error getting vulnerabilities for image %s: %v", image, err)
+	}
+	vList := report.VulnerabilityList()
+	vList.Image = image
+	return vList, nil
+}
+
+// ClairGet gets the resource with the given path from the Clair server
+func (c ClairProvider) ClairGet(subPath string, rs result) error {
+	url := c.BaseURL + subPath
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	if c.BearerToken != "" {
+		req.Header.Add("Authorization", "Bearer "+c.BearerToken)
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error getting vulnerabilities from clair %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("the manifest has not been indexed by clair")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error response getting vulnerabilities from clair: %s", resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(data, rs)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling %v", err)
+	}
+	return nil
+}
+
+// VulnerabilityList returns the vulnerabilities of each package in the report sorted by severity
+func (r *ClairVulnerabilityReport) VulnerabilityList() *VulnerabilityList {
+	vList := &VulnerabilityList{
+		ImageDigest: r.ManifestHash,
+	}
+	for packageID, vulnerabilityIDs := range r.PackageVulnerabilities {
+		pkg := r.Packages[packageID]
+		for _, id := range vulnerabilityIDs {
+			v := r.Vulnerabilities[id]
+			if v == nil {
+				continue
+			}
+			severity := v.NormalizedSeverity
+			if severity == "" {
+				severity = v.Severity
+			}
+			vulnerability := Vulnerability{
+				Fix:      v.FixedInVersion,
+				Severity: NormalizeSeverity(severity),
+				Vuln:     v.Name,
+			}
+			links := strings.Fields(v.Links)
+			if len(links) > 0 {
+				vulnerability.URL = links[0]
+			}
+			if pkg != nil {
+				vulnerability.Package = packageName(pkg.Name, pkg.Version)
+			}
+			vList.Vulnerabilities = append(vList.Vulnerabilities, vulnerability)
+		}
+	}
+	SortVulnerabilities(vList.Vulnerabilities)
+	return vList
+}
+
+// ImageDigest returns the digest of an image reference such as "myorg/myapp@sha256:..." or an empty string if it has none
+func ImageDigest(image string) string {
+	idx := strings.LastIndex(image, "@")
+	if idx >= 0 {
+		return image[idx+1:]
+	}
+	if strings.HasPrefix(image, "sha256:") {
+		return image
+	}
+	return ""
+}
+
+// packageName returns the name of a package including its version
+func packageName(name string, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + "-" + version
+}
@@ -0,0 +1,54 @@
+package cve_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/cve"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+const clairDigest = "sha256:8f0bd4b2c5ab4c1c4d2fce3c0a1d9f64cd4e0d9b8b6a1c0f3d8e0f6a2a1b3c4d"
+
+func TestClairGetImageVulnerabilities(t *testing.T) {
+	t.Parallel()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/matcher/api/v1/vulnerability_report/"+clairDigest, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer mytoken", r.Header.Get("Authorization"))
+		data, err := util.LoadBytes(filepath.Join("test_data", "clair"), "vulnerability_report.json")
+		assert.NoError(t, err)
+		w.Write(data)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	provider, err := cve.NewClairProvider(&auth.AuthServer{URL: server.URL}, &auth.UserAuth{ApiToken: "mytoken"})
+	assert.NoError(t, err)
+	scanner := provider.(cve.ImageScanner)
+
+	vList, err := scanner.GetImageVulnerabilities("myorg/myapp@" + clairDigest)
+	assert.NoError(t, err)
+	if assert.NotNil(t, vList) {
+		assert.Equal(t, "myorg/myapp@"+clairDigest, vList.Image)
+		assert.Equal(t, clairDigest, vList.ImageDigest)
+		if assert.Len(t, vList.Vulnerabilities, 2) {
+			v := vList.Vulnerabilities[0]
+			assert.Equal(t, "CVE-2019-14697", v.Vuln)
+			assert.Equal(t, cve.SeverityCritical, v.Severity)
+			assert.Equal(t, "musl-1.1.20-r4", v.Package)
+			assert.Equal(t, "1.1.20-r5", v.Fix)
+			assert.Equal(t, "https://cve.mitre.org/cgi-bin/cvename.cgi?name=CVE-2019-14697", v.URL)
+			assert.Equal(t, cve.SeverityMedium, vList.Vulnerabilities[1].Severity)
+		}
+	}
+
+	_, err = scanner.GetImageVulnerabilities("myorg/myapp:1.0.0")
+	assert.Error(t, err, "images must be referenced by digest")
+
+	_, err = scanner.GetImageVulnerabilities("myorg/myapp@sha256:unknown")
+	assert.Error(t, err, "unindexed manifest")
+}
@@ -1,6 +1,8 @@
 package cve
 
 import (
+	"sort"
+
 	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx/pkg/table"
 	"k8s.io/client-go/kubernetes"
@@ -10,6 +12,19 @@ const (
 	AnnotationCVEImageId = "jenkins-x.io/cve-image-id"
 )
 
+// The kinds of CVE provider
+const (
+	// ProviderKindAnchore queries an Anchore engine
+	ProviderKindAnchore = "anchore"
+	// ProviderKindClair queries the vulnerability reports of a Clair server
+	ProviderKindClair = "clair"
+	// ProviderKindReport reads the JSON reports of scanners such as Trivy or Grype run in the pipeline
+	ProviderKindReport = "report"
+)
+
+// ProviderKinds the kinds of CVE provider
+var ProviderKinds = []string{ProviderKindAnchore, ProviderKindClair, ProviderKindReport}
+
 type CVEQuery struct {
 	ImageName       string
 	ImageID         string
@@ -20,3 +35,50 @@ type CVEQuery struct {
 type CVEProvider interface {
 	GetImageVulnerabilityTable(jxClient versioned.Interface, client kubernetes.Interface, table *table.Table, query CVEQuery) error
 }
+
+// ImageScanner is implemented by CVE providers which can find the vulnerabilities of a single image
+type ImageScanner interface {
+	// GetImageVulnerabilities returns the vulnerabilities of the image which is a name such as "myorg/myapp:1.0.0"
+	// or a reference by digest such as "myorg/myapp@sha256:..."
+	GetImageVulnerabilities(image string) (*VulnerabilityList, error)
+}
+
+// SortVulnerabilities sorts the vulnerabilities with the most severe first
+func SortVulnerabilities(vulnerabilities []Vulnerability) {
+	sort.SliceStable(vulnerabilities, func(i, j int) bool {
+		r1 := SeverityRank(vulnerabilities[i].Severity)
+		r2 := SeverityRank(vulnerabilities[j].Severity)
+		if r1 != r2 {
+			return r1 > r2
+		}
+		if vulnerabilities[i].Vuln != vulnerabilities[j].Vuln {
+			return vulnerabilities[i].Vuln < vulnerabilities[j].Vuln
+		}
+		return vulnerabilities[i].Package < vulnerabilities[j].Package
+	})
+}
+
+// AddVulnerabilityRows adds a row to the table for each vulnerability of the image
+func AddVulnerabilityRows(table *table.Table, image string, vulnerabilities []Vulnerability) {
+	for _, v := range vulnerabilities {
+		item := &VulnerabilityItem{
+			Image:    image,
+			Severity: v.Severity,
+			Vuln:     v.Vuln,
+			URL:      v.URL,
+			Package:  v.Package,
+			Fix:      v.Fix,
+		}
+		table.AddItem(item, image, ColorSeverity(v.Severity), v.Vuln, v.URL, v.Package, v.Fix)
+	}
+}
+
+// VulnerabilityItem is the machine readable output of a vulnerability of an image
+type VulnerabilityItem struct {
+	Image    string `json:"image"`
+	Severity string `json:"severity"`
+	Vuln     string `json:"vulnerability"`
+	URL      string `json:"url,omitempty"`
+	Package  string `json:"package"`
+	Fix      string `json:"fix,omitempty"`
+}
@@ -0,0 +1,209 @@
+package cve
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/table"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ReportProvider implements CVEProvider interface for the JSON reports of image scanners such as
+// Trivy or Grype which are run in the pipeline
+type ReportProvider struct {
+	Files []string
+}
+
+type trivyReport struct {
+	ArtifactName string        `json:"ArtifactName"`
+	Results      []trivyResult `json:"Results"`
+}
+
+type trivyResult struct {
+	Target          string               `json:"Target"`
+	Vulnerabilities []trivyVulnerability `json:"Vulnerabilities"`
+}
+
+type trivyVulnerability struct {
+	VulnerabilityID  string   `json:"VulnerabilityID"`
+	PkgName          string   `json:"PkgName"`
+	InstalledVersion string   `json:"InstalledVersion"`
+	FixedVersion     string   `json:"FixedVersion"`
+	Severity         string   `json:"Severity"`
+	PrimaryURL       string   `json:"PrimaryURL"`
+	References       []string `json:"References"`
+}
+
+type grypeReport struct {
+	Matches []grypeMatch `json:"matches"`
+	Source  struct {
+		Type   string          `json:"type"`
+		Target json.RawMessage `json:"target"`
+	} `json:"source"`
+}
+
+type grypeMatch struct {
+	Vulnerability struct {
+		ID       string   `json:"id"`
+		Severity string   `json:"severity"`
+		URLs     []string `json:"urls"`
+		Fix      struct {
+			Versions []string `json:"versions"`
+		} `json:"fix"`
+	} `json:"vulnerability"`
+	Artifact struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"artifact"`
+}
+
+// NewReportProvider creates a CVE provider which reads the given Trivy or Grype JSON report files
+func NewReportProvider(files ...string) (CVEProvider, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no vulnerability report files specified")
+	}
+	return &ReportProvider{
+		Files: files,
+	}, nil
+}
+
+func (r ReportProvider) GetImageVulnerabilityTable(jxClient versioned.Interface, client kubernetes.Interface, table *table.Table, query CVEQuery) error {
+	for _, file := range r.Files {
+		vList, err := LoadReport(file)
+		if err != nil {
+			return err
+		}
+		if query.ImageName != "" && vList.Image != "" && !strings.Contains(vList.Image, query.ImageName) {
+			continue
+		}
+		AddVulnerabilityRows(table, vList.Image, vList.Vulnerabilities)
+	}
+	return nil
+}
+
+// GetImageVulnerabilities returns the vulnerabilities in all of the reports. The image is only used to name the
+// results when the reports do not include the name of the image they scanned
+func (r ReportProvider) GetImageVulnerabilities(image string) (*VulnerabilityList, error) {
+	answer := &VulnerabilityList{
+		Image: image,
+	}
+	for _, file := range r.Files {
+		vList, err := LoadReport(file)
+		if err != nil {
+			return nil, err
+		}
+		if answer.Image == "" {
+			answer.Image = vList.Image
+		}
+		answer.Vulnerabilities = append(answer.Vulnerabilities, vList.Vulnerabilities...)
+	}
+	SortVulnerabilities(answer.Vulnerabilities)
+	return answer, nil
+}
+
+// LoadReport loads the vulnerabilities from a Trivy or Grype JSON report file
+func LoadReport(file string) (*VulnerabilityList, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vulnerability report %s: %v", file, err)
+	}
+	vList, err := ParseReport(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse vulnerability report %s: %v", file, err)
+	}
+	return vList, nil
+}
+
+// ParseReport parses a Trivy or Grype JSON report detecting which scanner produced it
+func ParseReport(data []byte) (*VulnerabilityList, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		// older versions of trivy report an array of results
+		results := []trivyResult{}
+		err := json.Unmarshal(data, &results)
+		if err != nil {
+			return nil, err
+		}
+		return trivyReportToVulnerabilityList(&trivyReport{Results: results}), nil
+	}
+
+	fields := map[string]json.RawMessage{}
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := fields["matches"]; ok {
+		report := &grypeReport{}
+		err = json.Unmarshal(data, report)
+		if err != nil {
+			return nil, err
+		}
+		return grypeReportToVulnerabilityList(report), nil
+	}
+	if _, ok := fields["Results"]; ok {
+		report := &trivyReport{}
+		err = json.Unmarshal(data, report)
+		if err != nil {
+			return nil, err
+		}
+		return trivyReportToVulnerabilityList(report), nil
+	}
+	return nil, fmt.Errorf("the report is not a Trivy or Grype JSON report")
+}
+
+func trivyReportToVulnerabilityList(report *trivyReport) *VulnerabilityList {
+	vList := &VulnerabilityList{
+		Image: report.ArtifactName,
+	}
+	for _, result := range report.Results {
+		if vList.Image == "" {
+			// older reports only include the image in the target such as "myorg/myapp:1.0.0 (alpine 3.9.4)"
+			vList.Image = strings.TrimSpace(strings.Split(result.Target, " (")[0])
+		}
+		for _, v := range result.Vulnerabilities {
+			url := v.PrimaryURL
+			if url == "" && len(v.References) > 0 {
+				url = v.References[0]
+			}
+			vList.Vulnerabilities = append(vList.Vulnerabilities, Vulnerability{
+				Fix:      v.FixedVersion,
+				Package:  packageName(v.PkgName, v.InstalledVersion),
+				Severity: NormalizeSeverity(v.Severity),
+				URL:      url,
+				Vuln:     v.VulnerabilityID,
+			})
+		}
+	}
+	SortVulnerabilities(vList.Vulnerabilities)
+	return vList
+}
+
+func grypeReportToVulnerabilityList(report *grypeReport) *VulnerabilityList {
+	vList := &VulnerabilityList{}
+	target := struct {
+		UserInput      string `json:"userInput"`
+		ManifestDigest string `json:"manifestDigest"`
+	}{}
+	if json.Unmarshal(report.Source.Target, &target) == nil {
+		vList.Image = target.UserInput
+		vList.ImageDigest = target.ManifestDigest
+	}
+	for _, m := range report.Matches {
+		v := Vulnerability{
+			Fix:      strings.Join(m.Vulnerability.Fix.Versions, ", "),
+			Package:  packageName(m.Artifact.Name, m.Artifact.Version),
+			Severity: NormalizeSeverity(m.Vulnerability.Severity),
+			Vuln:     m.Vulnerability.ID,
+		}
+		if len(m.Vulnerability.URLs) > 0 {
+			v.URL = m.Vulnerability.URLs[0]
+		}
+		vList.Vulnerabilities = append(vList.Vulnerabilities, v)
+	}
+	SortVulnerabilities(vList.Vulnerabilities)
+	return vList
+}
@@ -0,0 +1,103 @@
+package cve_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cve"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadTrivyReport(t *testing.T) {
+	t.Parallel()
+	vList, err := cve.LoadReport(filepath.Join("test_data", "reports", "trivy.json"))
+	assert.NoError(t, err)
+	if assert.NotNil(t, vList) {
+		assert.Equal(t, "myorg/myapp:1.0.0", vList.Image)
+		if assert.Len(t, vList.Vulnerabilities, 3) {
+			v := vList.Vulnerabilities[0]
+			assert.Equal(t, "CVE-2019-14697", v.Vuln)
+			assert.Equal(t, cve.SeverityCritical, v.Severity)
+			assert.Equal(t, "musl-1.1.20-r4", v.Package)
+			assert.Equal(t, "1.1.20-r5", v.Fix)
+			assert.Equal(t, "https://www.openwall.com/lists/musl/2019/08/06/1", v.URL)
+
+			assert.Equal(t, cve.SeverityMedium, vList.Vulnerabilities[1].Severity)
+			assert.Equal(t, "https://avd.aquasec.com/nvd/cve-2019-1543", vList.Vulnerabilities[1].URL)
+			assert.Equal(t, cve.SeverityLow, vList.Vulnerabilities[2].Severity)
+		}
+	}
+
+	vList, err = cve.LoadReport(filepath.Join("test_data", "reports", "trivy-legacy.json"))
+	assert.NoError(t, err)
+	if assert.NotNil(t, vList) {
+		assert.Equal(t, "myorg/myapp:1.0.0", vList.Image)
+		if assert.Len(t, vList.Vulnerabilities, 1) {
+			assert.Equal(t, cve.SeverityHigh, vList.Vulnerabilities[0].Severity)
+		}
+	}
+}
+
+func TestLoadGrypeReport(t *testing.T) {
+	t.Parallel()
+	vList, err := cve.LoadReport(filepath.Join("test_data", "reports", "grype.json"))
+	assert.NoError(t, err)
+	if assert.NotNil(t, vList) {
+		assert.Equal(t, "myorg/myapp:1.0.0", vList.Image)
+		assert.Equal(t, "sha256:8f0bd4b2c5ab4c1c4d2fce3c0a1d9f64cd4e0d9b8b6a1c0f3d8e0f6a2a1b3c4d", vList.ImageDigest)
+		if assert.Len(t, vList.Vulnerabilities, 2) {
+			assert.Equal(t, "CVE-2019-14697", vList.Vulnerabilities[0].Vuln)
+			assert.Equal(t, cve.SeverityCritical, vList.Vulnerabilities[0].Severity)
+			assert.Equal(t, "", vList.Vulnerabilities[0].Fix)
+			assert.Equal(t, "openssl-1.1.1b-r1", vList.Vulnerabilities[1].Package)
+			assert.Equal(t, "1.1.1b-r2", vList.Vulnerabilities[1].Fix)
+		}
+	}
+}
+
+func TestParseUnknownReport(t *testing.T) {
+	t.Parallel()
+	_, err := cve.ParseReport([]byte(`{"foo": "bar"}`))
+	assert.Error(t, err)
+}
+
+func TestScan(t *testing.T) {
+	t.Parallel()
+	provider, err := cve.NewReportProvider(filepath.Join("test_data", "reports", "grype.json"))
+	assert.NoError(t, err)
+	vList, err := provider.(cve.ImageScanner).GetImageVulnerabilities("")
+	assert.NoError(t, err)
+
+	result := cve.Scan(vList, cve.ProviderKindReport, "high", nil, false)
+	assert.False(t, result.Passed(), "a critical vulnerability is above the high threshold")
+	assert.Len(t, result.Failures, 1)
+
+	assert.True(t, cve.Scan(vList, cve.ProviderKindReport, "high", nil, true).Passed(), "ignoring vulnerabilities without a fix")
+	assert.True(t, cve.Scan(vList, cve.ProviderKindReport, "high", []string{"CVE-2019-14697"}, false).Passed(), "ignoring the vulnerability")
+	assert.Len(t, cve.Scan(vList, cve.ProviderKindReport, "medium", nil, false).Failures, 2)
+
+	activity := &v1.PipelineActivity{}
+	cve.AddScanFact(activity, result)
+	cve.AddScanFact(activity, result)
+	if assert.Len(t, activity.Spec.Facts, 1, "scanning the same image replaces its fact") {
+		fact := activity.Spec.Facts[0]
+		assert.Equal(t, "myorg/myapp:1.0.0", fact.Name)
+		assert.Equal(t, v1.FactTypeVulnerabilityScan, fact.FactType)
+		assert.Equal(t, 1, fact.ID)
+	}
+	assert.Equal(t, []string{"myorg/myapp:1.0.0"}, cve.FailedScans(activity))
+
+	cve.AddScanFact(activity, cve.Scan(vList, cve.ProviderKindReport, "critical", []string{"CVE-2019-14697"}, false))
+	assert.Empty(t, cve.FailedScans(activity))
+}
+
+func TestSeverities(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, cve.SeverityCritical, cve.NormalizeSeverity("Defcon1"))
+	assert.Equal(t, cve.SeverityHigh, cve.NormalizeSeverity("HIGH"))
+	assert.Equal(t, cve.SeverityUnknown, cve.NormalizeSeverity("whatever"))
+	assert.True(t, cve.IsSeverityAtLeast("CRITICAL", cve.SeverityHigh))
+	assert.True(t, cve.IsSeverityAtLeast("High", cve.SeverityHigh))
+	assert.False(t, cve.IsSeverityAtLeast("Medium", cve.SeverityHigh))
+}
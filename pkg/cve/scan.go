@@ -0,0 +1,122 @@
+package cve
+
+import (
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// StatementScanPassed the name of the statement recording whether an image scan found no vulnerabilities at or
+	// above the severity threshold
+	StatementScanPassed = "passed"
+	// MeasurementScanFailures the name of the measurement recording how many vulnerabilities are at or above the
+	// severity threshold
+	MeasurementScanFailures = "failures"
+)
+
+// ScanResult the result of checking the vulnerabilities of an image against a severity threshold
+type ScanResult struct {
+	Image           string
+	Provider        string
+	Threshold       string
+	Vulnerabilities []Vulnerability
+	// Failures are the vulnerabilities at or above the threshold which are not ignored
+	Failures []Vulnerability
+}
+
+// Scan checks the vulnerabilities against the severity threshold ignoring the vulnerabilities with the given IDs
+// and, if ignoreUnfixed is true, the vulnerabilities which have no fix available yet
+func Scan(vList *VulnerabilityList, provider string, threshold string, ignore []string, ignoreUnfixed bool) *ScanResult {
+	result := &ScanResult{
+		Image:           vList.Image,
+		Provider:        provider,
+		Threshold:       NormalizeSeverity(threshold),
+		Vulnerabilities: vList.Vulnerabilities,
+	}
+	for _, v := range vList.Vulnerabilities {
+		if !IsSeverityAtLeast(v.Severity, result.Threshold) {
+			continue
+		}
+		if util.StringArrayIndex(ignore, v.Vuln) >= 0 || (ignoreUnfixed && v.Fix == "") {
+			continue
+		}
+		result.Failures = append(result.Failures, v)
+	}
+	return result
+}
+
+// Passed returns true if no vulnerabilities were found at or above the threshold
+func (r *ScanResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// SeverityCounts returns the number of vulnerabilities of each severity
+func (r *ScanResult) SeverityCounts() map[string]int {
+	answer := map[string]int{}
+	for _, v := range r.Vulnerabilities {
+		answer[NormalizeSeverity(v.Severity)]++
+	}
+	return answer
+}
+
+// Fact returns the fact recording the result on a PipelineActivity so that promotions can be blocked on it
+func (r *ScanResult) Fact() v1.Fact {
+	counts := r.SeverityCounts()
+	measurements := []v1.Measurement{}
+	for _, severity := range Severities {
+		measurements = append(measurements, v1.Measurement{
+			Name:             severity,
+			MeasurementType:  v1.MeasurementCount,
+			MeasurementValue: counts[severity],
+		})
+	}
+	measurements = append(measurements, v1.Measurement{
+		Name:             MeasurementScanFailures,
+		MeasurementType:  v1.MeasurementCount,
+		MeasurementValue: len(r.Failures),
+	})
+	return v1.Fact{
+		Name:         r.Image,
+		FactType:     v1.FactTypeVulnerabilityScan,
+		Measurements: measurements,
+		Statements: []v1.Statement{
+			{
+				Name:             StatementScanPassed,
+				StatementType:    r.Threshold,
+				MeasurementValue: r.Passed(),
+			},
+		},
+		Tags: []string{r.Provider},
+	}
+}
+
+// AddScanFact adds the fact of the scan result to the activity replacing any previous scan of the same image
+func AddScanFact(activity *v1.PipelineActivity, result *ScanResult) {
+	fact := result.Fact()
+	for i := range activity.Spec.Facts {
+		existing := &activity.Spec.Facts[i]
+		if existing.Name == fact.Name && existing.FactType == fact.FactType {
+			fact.ID = existing.ID
+			*existing = fact
+			return
+		}
+	}
+	fact.ID = len(activity.Spec.Facts) + 1
+	activity.Spec.Facts = append(activity.Spec.Facts, fact)
+}
+
+// FailedScans returns the names of the images whose vulnerability scans recorded on the activity did not pass
+func FailedScans(activity *v1.PipelineActivity) []string {
+	answer := []string{}
+	for _, fact := range activity.Spec.Facts {
+		if fact.FactType != v1.FactTypeVulnerabilityScan {
+			continue
+		}
+		for _, statement := range fact.Statements {
+			if statement.Name == StatementScanPassed && !statement.MeasurementValue {
+				answer = append(answer, fact.Name)
+			}
+		}
+	}
+	return answer
+}
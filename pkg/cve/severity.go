@@ -0,0 +1,69 @@
+package cve
+
+import (
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// The severities of vulnerabilities in increasing order of severity
+const (
+	SeverityUnknown    = "Unknown"
+	SeverityNegligible = "Negligible"
+	SeverityLow        = "Low"
+	SeverityMedium     = "Medium"
+	SeverityHigh       = "High"
+	SeverityCritical   = "Critical"
+)
+
+// Severities the severities of vulnerabilities in increasing order of severity
+var Severities = []string{
+	SeverityUnknown,
+	SeverityNegligible,
+	SeverityLow,
+	SeverityMedium,
+	SeverityHigh,
+	SeverityCritical,
+}
+
+// NormalizeSeverity converts the severity reported by a scanner such as "CRITICAL" or "Defcon1" into one of the Severities
+func NormalizeSeverity(severity string) string {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case "negligible":
+		return SeverityNegligible
+	case "low":
+		return SeverityLow
+	case "medium", "moderate":
+		return SeverityMedium
+	case "high", "important":
+		return SeverityHigh
+	case "critical", "defcon1":
+		return SeverityCritical
+	default:
+		return SeverityUnknown
+	}
+}
+
+// SeverityRank returns the position of the severity in Severities so that more severe vulnerabilities have a higher rank
+func SeverityRank(severity string) int {
+	return util.StringArrayIndex(Severities, NormalizeSeverity(severity))
+}
+
+// IsSeverityAtLeast returns true if the severity is the same as or more severe than the threshold
+func IsSeverityAtLeast(severity string, threshold string) bool {
+	return SeverityRank(severity) >= SeverityRank(threshold)
+}
+
+// ColorSeverity returns the severity colored by how severe it is for display in a table
+func ColorSeverity(severity string) string {
+	switch NormalizeSeverity(severity) {
+	case SeverityCritical, SeverityHigh:
+		return util.ColorError(severity)
+	case SeverityMedium:
+		return util.ColorWarning(severity)
+	case SeverityLow:
+		return util.ColorStatus(severity)
+	default:
+		return severity
+	}
+}
@@ -0,0 +1,206 @@
+package filesync_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/filesync"
+	"github.com/stretchr/testify/assert"
+)
+
+// dirRemote a remote directory on the local file system
+type dirRemote struct {
+	dir string
+}
+
+func (r *dirRemote) List(matcher *filesync.Matcher) (filesync.Snapshot, error) {
+	return filesync.ScanLocal(r.dir, matcher)
+}
+
+func (r *dirRemote) Upload(dir string, paths []string) error {
+	return copyFiles(dir, r.dir, paths)
+}
+
+func (r *dirRemote) Download(dir string, paths []string) error {
+	return copyFiles(r.dir, dir, paths)
+}
+
+func (r *dirRemote) Delete(paths []string) error {
+	for _, path := range paths {
+		err := os.Remove(filepath.Join(r.dir, path))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFiles(from string, to string, paths []string) error {
+	var buffer bytes.Buffer
+	err := filesync.WriteTar(&buffer, from, paths)
+	if err != nil {
+		return err
+	}
+	return filesync.ExtractTar(&buffer, to)
+}
+
+func writeFile(t *testing.T, dir string, path string, text string, modTime time.Time) {
+	file := filepath.Join(dir, path)
+	err := os.MkdirAll(filepath.Dir(file), 0755)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(file, []byte(text), 0644)
+	assert.NoError(t, err)
+	err = os.Chtimes(file, modTime, modTime)
+	assert.NoError(t, err)
+}
+
+func readFile(t *testing.T, dir string, path string) string {
+	data, err := ioutil.ReadFile(filepath.Join(dir, path))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func TestMatcher(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-filesync-matcher")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeFile(t, dir, ".gitignore", "target/\n*.log\n", time.Now())
+
+	matcher, err := filesync.NewMatcher(dir, []string{"target/generated/**"}, []string{"*.tmp"})
+	assert.NoError(t, err)
+	assert.False(t, matcher.Ignored("src/main.go", false))
+	assert.True(t, matcher.Ignored("app.log", false))
+	assert.True(t, matcher.Ignored("src/cache.tmp", false))
+	assert.True(t, matcher.Ignored("target/app.jar", false))
+	assert.True(t, matcher.Ignored(".git/config", false))
+	assert.False(t, matcher.Ignored("target/generated/Model.java", false))
+	assert.True(t, matcher.Descend("target"))
+	assert.False(t, matcher.Descend(".git"))
+	assert.True(t, matcher.Descend("src"))
+}
+
+func TestReconcile(t *testing.T) {
+	t.Parallel()
+	a := filesync.FileState{Size: 1, ModTime: 100}
+	b := filesync.FileState{Size: 2, ModTime: 200}
+	c := filesync.FileState{Size: 3, ModTime: 300}
+	base := filesync.Snapshot{"same": a, "pushed": a, "pulled": a, "deletedLocal": a, "deletedRemote": a, "conflict": a, "both": a}
+	local := filesync.Snapshot{"same": a, "pushed": b, "pulled": a, "deletedRemote": a, "conflict": b, "both": b, "newLocal": a}
+	remote := filesync.Snapshot{"same": a, "pushed": a, "pulled": b, "deletedLocal": a, "conflict": c, "both": b, "newRemote": a}
+
+	plan := filesync.Reconcile(base, local, remote, false)
+	assert.Equal(t, []string{"newLocal", "pushed"}, plan.Push)
+	assert.Equal(t, []string{"newRemote", "pulled"}, plan.Pull)
+	assert.Equal(t, []string{"deletedLocal"}, plan.DeleteRemote)
+	assert.Equal(t, []string{"deletedRemote"}, plan.DeleteLocal)
+	assert.Equal(t, []string{"conflict"}, plan.Conflicts)
+
+	next := plan.Base(base, local, remote)
+	assert.Equal(t, filesync.Snapshot{"same": a, "pushed": b, "pulled": b, "conflict": a, "both": b, "newLocal": a, "newRemote": a}, next)
+
+	plan = filesync.Reconcile(nil, local, remote, true)
+	assert.Equal(t, []string{"conflict", "deletedRemote", "newLocal", "pulled", "pushed"}, plan.Push)
+	assert.Equal(t, []string{"deletedLocal", "newRemote"}, plan.Pull)
+	assert.Empty(t, plan.Conflicts)
+}
+
+func TestParseFileList(t *testing.T) {
+	t.Parallel()
+	matcher, err := filesync.NewMatcher("", nil, []string{"target/"})
+	assert.NoError(t, err)
+	snapshot, err := filesync.ParseFileList("src/main.go\t120\t1539000000.1234567890\ntarget/app.jar\t5000\t1539000000.0\n\n", matcher)
+	assert.NoError(t, err)
+	assert.Equal(t, filesync.Snapshot{"src/main.go": filesync.FileState{Size: 120, ModTime: 1539000000}}, snapshot)
+
+	_, err = filesync.ParseFileList("src/main.go 120\n", matcher)
+	assert.Error(t, err)
+}
+
+func TestExtractTarRejectsEntriesOutsideDir(t *testing.T) {
+	t.Parallel()
+	from, err := ioutil.TempDir("", "test-filesync-tar")
+	assert.NoError(t, err)
+	defer os.RemoveAll(from)
+	writeFile(t, from, "a/evil.txt", "evil", time.Now())
+
+	var buffer bytes.Buffer
+	err = filesync.WriteTar(&buffer, from, []string{"a/../a/evil.txt"})
+	assert.NoError(t, err)
+	to, err := ioutil.TempDir("", "test-filesync-tar-to")
+	assert.NoError(t, err)
+	defer os.RemoveAll(to)
+	err = filesync.ExtractTar(&buffer, filepath.Join(to, "b"))
+	assert.NoError(t, err)
+
+	buffer.Reset()
+	err = filesync.WriteTar(&buffer, from, []string{"../" + filepath.Base(from) + "/a/evil.txt"})
+	assert.NoError(t, err)
+	err = filesync.ExtractTar(&buffer, filepath.Join(to, "b"))
+	assert.Error(t, err)
+}
+
+func TestSyncer(t *testing.T) {
+	t.Parallel()
+	local, err := ioutil.TempDir("", "test-filesync-local")
+	assert.NoError(t, err)
+	defer os.RemoveAll(local)
+	remoteDir, err := ioutil.TempDir("", "test-filesync-remote")
+	assert.NoError(t, err)
+	defer os.RemoveAll(remoteDir)
+
+	old := time.Now().Add(-time.Hour)
+	writeFile(t, local, ".gitignore", "target/\n", old)
+	writeFile(t, local, "src/main.go", "package main", old)
+	writeFile(t, local, "target/app.jar", "binary", old)
+	writeFile(t, remoteDir, "src/main.go", "stale", old.Add(-time.Hour))
+	writeFile(t, remoteDir, "gen/model.go", "generated", old)
+
+	matcher, err := filesync.NewMatcher(local, nil, nil)
+	assert.NoError(t, err)
+	syncer := filesync.NewSyncer(local, matcher, &dirRemote{dir: remoteDir})
+	changes := 0
+	syncer.OnChange = func(plan *filesync.Plan) {
+		changes++
+	}
+
+	// the first synchronisation prefers the local files
+	plan, err := syncer.Sync()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{".gitignore", "src/main.go"}, plan.Push)
+	assert.Equal(t, []string{"gen/model.go"}, plan.Pull)
+	assert.Equal(t, "package main", readFile(t, remoteDir, "src/main.go"))
+	assert.Equal(t, "generated", readFile(t, local, "gen/model.go"))
+	assert.Equal(t, "", readFile(t, remoteDir, "target/app.jar"))
+
+	plan, err = syncer.Sync()
+	assert.NoError(t, err)
+	assert.True(t, plan.Empty(), "plan should be empty but was %s", plan.String())
+	assert.Equal(t, 1, changes, "only the synchronisation which changed files should be reported")
+
+	// generated files flow back from the remote and deletions are synchronised
+	writeFile(t, remoteDir, "gen/model.go", "regenerated", old.Add(time.Minute))
+	err = os.Remove(filepath.Join(local, "src/main.go"))
+	assert.NoError(t, err)
+	plan, err = syncer.Sync()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"gen/model.go"}, plan.Pull)
+	assert.Equal(t, []string{"src/main.go"}, plan.DeleteRemote)
+	assert.Equal(t, "regenerated", readFile(t, local, "gen/model.go"))
+	assert.Equal(t, "", readFile(t, remoteDir, "src/main.go"))
+
+	// changes on both sides are conflicts which are left alone
+	writeFile(t, local, "gen/model.go", "local", old.Add(2*time.Minute))
+	writeFile(t, remoteDir, "gen/model.go", "remote edit", old.Add(3*time.Minute))
+	plan, err = syncer.Sync()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"gen/model.go"}, plan.Conflicts)
+	assert.Equal(t, "local", readFile(t, local, "gen/model.go"))
+	assert.Equal(t, "remote edit", readFile(t, remoteDir, "gen/model.go"))
+}
@@ -0,0 +1,96 @@
+package filesync
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/denormal/go-gitignore"
+)
+
+// Matcher decides which files are synchronised using the .gitignore files of the directory together with
+// include and exclude patterns in the .gitignore syntax. Includes take precedence so that generated files in ignored
+// directories can be synchronised
+type Matcher struct {
+	gitIgnore gitignore.GitIgnore
+	includes  gitignore.GitIgnore
+	excludes  gitignore.GitIgnore
+	prefixes  []string
+}
+
+// NewMatcher creates a matcher for the directory
+func NewMatcher(dir string, includes []string, excludes []string) (*Matcher, error) {
+	answer := &Matcher{}
+	if dir != "" {
+		ignore, err := gitignore.NewRepository(dir)
+		if err != nil {
+			return nil, err
+		}
+		answer.gitIgnore = ignore
+	}
+	answer.includes = newPatterns(includes, dir)
+	answer.excludes = newPatterns(excludes, dir)
+	for _, include := range includes {
+		prefix := strings.TrimPrefix(include, "/")
+		idx := strings.IndexAny(prefix, "*?[")
+		if idx >= 0 {
+			prefix = prefix[0:idx]
+		}
+		if prefix != "" {
+			answer.prefixes = append(answer.prefixes, prefix)
+		}
+	}
+	return answer, nil
+}
+
+func newPatterns(patterns []string, dir string) gitignore.GitIgnore {
+	if len(patterns) == 0 {
+		return nil
+	}
+	return gitignore.New(strings.NewReader(strings.Join(patterns, "\n")), dir, nil)
+}
+
+// Ignored returns true if the file or directory with the slash separated path relative to the directory should not
+// be synchronised because it or one of its parent directories is ignored and it is not included
+func (m *Matcher) Ignored(path string, isDir bool) bool {
+	path = filepath.ToSlash(path)
+	if matches(m.includes, path, isDir) {
+		return false
+	}
+	names := strings.Split(path, "/")
+	for i := 1; i <= len(names); i++ {
+		dir := i < len(names) || isDir
+		if m.ignored(strings.Join(names[0:i], "/"), names[i-1], dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// Descend returns true if the directory should be walked as it is not ignored or contains included files
+func (m *Matcher) Descend(path string) bool {
+	if !m.Ignored(path, true) {
+		return true
+	}
+	dir := filepath.ToSlash(path) + "/"
+	for _, prefix := range m.prefixes {
+		if strings.HasPrefix(prefix, dir) || strings.HasPrefix(dir, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Matcher) ignored(path string, name string, isDir bool) bool {
+	if isDir && name == ".git" {
+		return true
+	}
+	return matches(m.excludes, path, isDir) || matches(m.gitIgnore, path, isDir)
+}
+
+func matches(ignore gitignore.GitIgnore, path string, isDir bool) bool {
+	if ignore == nil {
+		return false
+	}
+	match := ignore.Relative(path, isDir)
+	return match != nil && match.Ignore()
+}
@@ -0,0 +1,144 @@
+package filesync
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+const deleteBatchSize = 100
+
+// Remote the remote directory files are synchronised with
+type Remote interface {
+	// List returns the snapshot of the remote files which are not ignored by the matcher
+	List(matcher *Matcher) (Snapshot, error)
+
+	// Upload copies the files with the given paths from the local directory to the remote directory
+	Upload(dir string, paths []string) error
+
+	// Download copies the files with the given paths from the remote directory into the local directory
+	Download(dir string, paths []string) error
+
+	// Delete removes the files with the given paths from the remote directory
+	Delete(paths []string) error
+}
+
+// PodRemote a directory of a container in a pod such as a DevPod which is accessed via the Kubernetes exec API
+// so that no agent needs to be installed in the cluster
+type PodRemote struct {
+	KubeClient kubernetes.Interface
+	Config     *rest.Config
+	Namespace  string
+	Pod        string
+	Container  string
+	Dir        string
+}
+
+// List returns the snapshot of the remote files
+func (p *PodRemote) List(matcher *Matcher) (Snapshot, error) {
+	err := p.Exec([]string{"mkdir", "-p", p.Dir}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	err = p.Exec([]string{"find", p.Dir, "-type", "f", "-printf", "%P\\t%s\\t%T@\\n"}, nil, &out)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFileList(out.String(), matcher)
+}
+
+// Upload streams the files as a tar archive into the remote directory
+func (p *PodRemote) Upload(dir string, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(WriteTar(writer, dir, paths))
+	}()
+	err := p.Exec([]string{"tar", "-xf", "-", "--no-same-owner", "-C", p.Dir}, reader, nil)
+	reader.Close()
+	return err
+}
+
+// Download streams the files as a tar archive from the remote directory
+func (p *PodRemote) Download(dir string, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	reader, writer := io.Pipe()
+	errCh := make(chan error, 1)
+	go func() {
+		err := ExtractTar(reader, dir)
+		// drain any remaining output so that the remote tar can terminate
+		io.Copy(ioutil.Discard, reader)
+		errCh <- err
+	}()
+	stdin := strings.NewReader(strings.Join(paths, "\n") + "\n")
+	err := p.Exec([]string{"tar", "-cf", "-", "-C", p.Dir, "-T", "-"}, stdin, writer)
+	writer.CloseWithError(err)
+	extractErr := <-errCh
+	if err != nil {
+		return err
+	}
+	return extractErr
+}
+
+// Delete removes the files from the remote directory
+func (p *PodRemote) Delete(paths []string) error {
+	for i := 0; i < len(paths); i += deleteBatchSize {
+		end := i + deleteBatchSize
+		if end > len(paths) {
+			end = len(paths)
+		}
+		args := []string{"rm", "-f", "--"}
+		for _, file := range paths[i:end] {
+			args = append(args, path.Join(p.Dir, file))
+		}
+		err := p.Exec(args, nil, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Exec runs the command in the container of the pod
+func (p *PodRemote) Exec(command []string, stdin io.Reader, stdout io.Writer) error {
+	req := p.KubeClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(p.Pod).
+		Namespace(p.Namespace).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: p.Container,
+			Command:   command,
+			Stdin:     stdin != nil,
+			Stdout:    stdout != nil,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(p.Config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	err = executor.Stream(remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to run %s in pod %s: %s %s", command[0], p.Pod, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package filesync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FileState the size and modification time in seconds of a synchronised file
+type FileState struct {
+	Size    int64
+	ModTime int64
+}
+
+// Snapshot the state of the synchronised files keyed by their slash separated path relative to the directory
+type Snapshot map[string]FileState
+
+// Paths returns the sorted paths of the snapshot
+func (s Snapshot) Paths() []string {
+	answer := []string{}
+	for path := range s {
+		answer = append(answer, path)
+	}
+	sort.Strings(answer)
+	return answer
+}
+
+// ScanLocal returns the snapshot of the files in the directory which are not ignored by the matcher
+func ScanLocal(dir string, matcher *Matcher) (Snapshot, error) {
+	answer := Snapshot{}
+	err := filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		path, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		if path == "." {
+			return nil
+		}
+		path = filepath.ToSlash(path)
+		if info.IsDir() {
+			if !matcher.Descend(path) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || matcher.Ignored(path, false) {
+			return nil
+		}
+		answer[path] = FileState{
+			Size:    info.Size(),
+			ModTime: info.ModTime().Unix(),
+		}
+		return nil
+	})
+	return answer, err
+}
+
+// ParseFileList parses the output of 'find -type f -printf "%P\t%s\t%T@\n"' into a snapshot of the files which are
+// not ignored by the matcher
+func ParseFileList(text string, matcher *Matcher) (Snapshot, error) {
+	answer := Snapshot{}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		cols := strings.Split(line, "\t")
+		if len(cols) != 3 {
+			return nil, fmt.Errorf("invalid file list line: %s", line)
+		}
+		path := cols[0]
+		if matcher.Ignored(path, false) {
+			continue
+		}
+		size, err := strconv.ParseInt(cols[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size of %s: %s", path, cols[1])
+		}
+		modTime, err := strconv.ParseFloat(cols[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modification time of %s: %s", path, cols[2])
+		}
+		answer[path] = FileState{
+			Size:    size,
+			ModTime: int64(modTime),
+		}
+	}
+	return answer, nil
+}
+
+// Plan the changes which synchronise the local and remote files
+type Plan struct {
+	Push         []string
+	Pull         []string
+	DeleteRemote []string
+	DeleteLocal  []string
+	Conflicts    []string
+}
+
+// Empty returns true if there are no changes or conflicts
+func (p *Plan) Empty() bool {
+	return len(p.Push) == 0 && len(p.Pull) == 0 && len(p.DeleteRemote) == 0 && len(p.DeleteLocal) == 0 && len(p.Conflicts) == 0
+}
+
+// Changed returns true if the plan copies or deletes any files
+func (p *Plan) Changed() bool {
+	return len(p.Push) > 0 || len(p.Pull) > 0 || len(p.DeleteRemote) > 0 || len(p.DeleteLocal) > 0
+}
+
+// String returns a summary of the plan
+func (p *Plan) String() string {
+	return fmt.Sprintf("pushed %d, pulled %d, deleted %d remote and %d local files with %d conflicts", len(p.Push), len(p.Pull), len(p.DeleteRemote), len(p.DeleteLocal), len(p.Conflicts))
+}
+
+// Reconcile compares the local and remote files with the base snapshot of the last synchronisation. Files which
+// only changed on one side are copied to the other side and files which changed differently on both sides are
+// conflicts unless preferLocal is true, such as for the first synchronisation, when the local files win
+func Reconcile(base Snapshot, local Snapshot, remote Snapshot, preferLocal bool) *Plan {
+	plan := &Plan{}
+	for _, path := range unionPaths(base, local, remote) {
+		b, hasBase := base[path]
+		l, hasLocal := local[path]
+		r, hasRemote := remote[path]
+		localChanged := hasLocal != hasBase || l != b
+		remoteChanged := hasRemote != hasBase || r != b
+		switch {
+		case !localChanged && !remoteChanged:
+		case hasLocal == hasRemote && l == r:
+			// both sides changed the same way
+		case localChanged && (!remoteChanged || preferLocal):
+			if hasLocal {
+				plan.Push = append(plan.Push, path)
+			} else if hasRemote {
+				plan.DeleteRemote = append(plan.DeleteRemote, path)
+			}
+		case remoteChanged && !localChanged:
+			if hasRemote {
+				plan.Pull = append(plan.Pull, path)
+			} else if hasLocal {
+				plan.DeleteLocal = append(plan.DeleteLocal, path)
+			}
+		default:
+			plan.Conflicts = append(plan.Conflicts, path)
+		}
+	}
+	return plan
+}
+
+// Base returns the base snapshot for the next synchronisation once the plan has been applied. Conflicts keep their
+// previous base so that they are reported until they are resolved
+func (p *Plan) Base(base Snapshot, local Snapshot, remote Snapshot) Snapshot {
+	conflicts := map[string]bool{}
+	for _, path := range p.Conflicts {
+		conflicts[path] = true
+	}
+	pulled := map[string]bool{}
+	for _, path := range p.Pull {
+		pulled[path] = true
+	}
+	answer := Snapshot{}
+	for _, path := range unionPaths(base, local, remote) {
+		if conflicts[path] {
+			if state, ok := base[path]; ok {
+				answer[path] = state
+			}
+			continue
+		}
+		if pulled[path] {
+			answer[path] = remote[path]
+			continue
+		}
+		if state, ok := local[path]; ok {
+			answer[path] = state
+		}
+	}
+	for _, path := range p.DeleteLocal {
+		delete(answer, path)
+	}
+	return answer
+}
+
+func unionPaths(snapshots ...Snapshot) []string {
+	union := Snapshot{}
+	for _, s := range snapshots {
+		for path, state := range s {
+			union[path] = state
+		}
+	}
+	return union.Paths()
+}
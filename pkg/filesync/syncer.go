@@ -0,0 +1,163 @@
+package filesync
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// DefaultPollInterval how often the remote files are checked for changes such as generated files
+	DefaultPollInterval = 5 * time.Second
+
+	debounceInterval = 300 * time.Millisecond
+)
+
+// Syncer synchronises a local directory with a remote directory in both directions
+type Syncer struct {
+	Dir          string
+	Matcher      *Matcher
+	Remote       Remote
+	PollInterval time.Duration
+
+	// OnChange is called after each synchronisation which changed any files
+	OnChange func(plan *Plan)
+
+	base      Snapshot
+	conflicts map[string]bool
+}
+
+// NewSyncer creates a syncer of the local directory
+func NewSyncer(dir string, matcher *Matcher, remote Remote) *Syncer {
+	return &Syncer{
+		Dir:          dir,
+		Matcher:      matcher,
+		Remote:       remote,
+		PollInterval: DefaultPollInterval,
+	}
+}
+
+// Sync performs a single synchronisation returning the plan which was applied. The local files win any differences
+// on the first synchronisation
+func (s *Syncer) Sync() (*Plan, error) {
+	local, err := ScanLocal(s.Dir, s.Matcher)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := s.Remote.List(s.Matcher)
+	if err != nil {
+		return nil, err
+	}
+	plan := Reconcile(s.base, local, remote, s.base == nil)
+	err = s.Remote.Upload(s.Dir, plan.Push)
+	if err != nil {
+		return plan, err
+	}
+	err = s.Remote.Delete(plan.DeleteRemote)
+	if err != nil {
+		return plan, err
+	}
+	err = s.Remote.Download(s.Dir, plan.Pull)
+	if err != nil {
+		return plan, err
+	}
+	for _, path := range plan.DeleteLocal {
+		err = os.Remove(filepath.Join(s.Dir, filepath.FromSlash(path)))
+		if err != nil && !os.IsNotExist(err) {
+			return plan, err
+		}
+	}
+	conflicts := map[string]bool{}
+	for _, path := range plan.Conflicts {
+		if !s.conflicts[path] {
+			log.Warnf("Conflict: %s was changed both locally and remotely. Make both copies the same to resolve it\n", util.ColorWarning(path))
+		}
+		conflicts[path] = true
+	}
+	s.conflicts = conflicts
+	s.base = plan.Base(s.base, local, remote)
+	if s.OnChange != nil && plan.Changed() {
+		s.OnChange(plan)
+	}
+	return plan, nil
+}
+
+// Watch synchronises whenever local files change and whenever the poll interval elapses until the stop channel is
+// closed. Failed synchronisations are logged and retried
+func (s *Syncer) Watch(stop <-chan struct{}) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	err = s.watchDir(watcher, s.Dir)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(s.PollInterval)
+	defer ticker.Stop()
+	debounce := time.NewTimer(debounceInterval)
+	debounce.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case event := <-watcher.Events:
+			if event.Op&fsnotify.Create != 0 {
+				info, err := os.Stat(event.Name)
+				if err == nil && info.IsDir() {
+					err = s.watchDir(watcher, event.Name)
+					if err != nil {
+						log.Warnf("Failed to watch %s: %s\n", event.Name, err)
+					}
+				}
+			}
+			debounce.Reset(debounceInterval)
+		case err := <-watcher.Errors:
+			log.Warnf("Failed to watch files: %s\n", err)
+		case <-debounce.C:
+			s.logSync()
+		case <-ticker.C:
+			s.logSync()
+		}
+	}
+}
+
+func (s *Syncer) logSync() {
+	plan, err := s.Sync()
+	if err != nil {
+		log.Warnf("Failed to synchronise %s: %s\n", s.Dir, err)
+		return
+	}
+	if plan.Changed() {
+		log.Infof("Synchronised %s: %s\n", util.ColorInfo(s.Dir), plan.String())
+	}
+}
+
+// watchDir adds watches for the directory and its sub directories which are not ignored
+func (s *Syncer) watchDir(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		path, err := filepath.Rel(s.Dir, file)
+		if err != nil {
+			return err
+		}
+		if path != "." && !s.Matcher.Descend(path) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(file)
+	})
+}
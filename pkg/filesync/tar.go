@@ -0,0 +1,94 @@
+package filesync
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// WriteTar writes the files of the directory with the given slash separated relative paths as a tar archive
+func WriteTar(w io.Writer, dir string, paths []string) error {
+	tw := tar.NewWriter(w)
+	for _, path := range paths {
+		err := writeTarFile(tw, dir, path)
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+func writeTarFile(tw *tar.Writer, dir string, path string) error {
+	file := filepath.Join(dir, filepath.FromSlash(path))
+	info, err := os.Stat(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// the file was deleted since the snapshot so the next synchronisation deletes it
+			return nil
+		}
+		return err
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = path
+	// the snapshots compare whole seconds
+	header.ModTime = info.ModTime().Truncate(time.Second)
+	err = tw.WriteHeader(header)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.CopyN(tw, f, header.Size)
+	return err
+}
+
+// ExtractTar extracts the regular files of the tar archive into the directory keeping their modification times. Entries
+// outside of the directory are rejected
+func ExtractTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+		name := filepath.FromSlash(strings.TrimPrefix(header.Name, "./"))
+		file := filepath.Join(dir, name)
+		rel, err := filepath.Rel(dir, file)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("tar entry %s is outside of the directory %s", header.Name, dir)
+		}
+		err = os.MkdirAll(filepath.Dir(file), 0755)
+		if err != nil {
+			return err
+		}
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode).Perm())
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(f, tr)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		err = os.Chtimes(file, header.ModTime, header.ModTime)
+		if err != nil {
+			return err
+		}
+	}
+}
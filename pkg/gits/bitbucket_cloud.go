@@ -696,6 +696,11 @@ func (b *BitbucketCloudProvider) CreateWebHook(data *GitWebHookArguments) error
 	return nil
 }
 
+func (b *BitbucketCloudProvider) ProtectBranch(org string, name string, protection *GitBranchProtection) error {
+	log.Warn("Bitbucket Cloud doesn't support protecting branches via the REST API")
+	return nil
+}
+
 func BitbucketIssueToGitIssue(bIssue bitbucket.Issue) *GitIssue {
 	id := int(bIssue.Id)
 	ownerAndRepo := strings.Split(bIssue.Repository.FullName, "/")
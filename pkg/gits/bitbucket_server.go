@@ -1,10 +1,13 @@
 package gits
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -695,6 +698,100 @@ func (b *BitbucketServerProvider) CreateWebHook(data *GitWebHookArguments) error
 	return err
 }
 
+// ProtectBranch restricts the branch so that changes can only be merged via pull requests and sets the number of
+// approvals and successful builds each pull request needs before it can be merged
+func (b *BitbucketServerProvider) ProtectBranch(org string, name string, protection *GitBranchProtection) error {
+	matcher := map[string]interface{}{
+		"id":        "refs/heads/" + protection.Branch,
+		"displayId": protection.Branch,
+		"type": map[string]interface{}{
+			"id":   "BRANCH",
+			"name": "Branch",
+		},
+		"active": true,
+	}
+	// branch permissions are not part of the REST API client so lets call them directly
+	restrictionsPath := fmt.Sprintf("rest/branch-permissions/2.0/projects/%s/repos/%s/restrictions", org, name)
+	existing := struct {
+		Values []struct {
+			Type string `json:"type"`
+		} `json:"values"`
+	}{}
+	query := url.Values{}
+	query.Set("matcherType", "BRANCH")
+	query.Set("matcherId", "refs/heads/"+protection.Branch)
+	err := b.getJSON(restrictionsPath+"?"+query.Encode(), &existing)
+	if err != nil {
+		return fmt.Errorf("failed to get the restrictions of branch %s of %s/%s: %s", protection.Branch, org, name, err)
+	}
+	existingTypes := []string{}
+	for _, restriction := range existing.Values {
+		existingTypes = append(existingTypes, restriction.Type)
+	}
+	for _, restriction := range []string{"pull-request-only", "no-deletes"} {
+		if util.Contains(existingTypes, restriction) {
+			continue
+		}
+		err := b.postJSON(restrictionsPath, map[string]interface{}{
+			"type":    restriction,
+			"matcher": matcher,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to protect branch %s of %s/%s: %s", protection.Branch, org, name, err)
+		}
+	}
+
+	settings := map[string]interface{}{
+		"requiredApprovers":        protection.RequiredApprovals,
+		"requiredSuccessfulBuilds": len(protection.RequiredContexts),
+	}
+	err = b.postJSON(fmt.Sprintf("rest/api/1.0/projects/%s/repos/%s/settings/pull-requests", org, name), settings)
+	if err != nil {
+		return fmt.Errorf("failed to update the pull request settings of %s/%s: %s", org, name, err)
+	}
+	return nil
+}
+
+func (b *BitbucketServerProvider) getJSON(path string, result interface{}) error {
+	req, err := http.NewRequest("GET", util.UrlJoin(b.Server.URL, path), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+b.User.ApiToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("GET %s returned status %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (b *BitbucketServerProvider) postJSON(path string, body interface{}) error {
+	requestBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", util.UrlJoin(b.Server.URL, path), bytes.NewReader(requestBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+b.User.ApiToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("POST %s returned status %s", path, resp.Status)
+	}
+	return nil
+}
+
 func (b *BitbucketServerProvider) SearchIssues(org string, name string, query string) ([]*GitIssue, error) {
 
 	gitIssues := []*GitIssue{}
@@ -58,6 +58,13 @@ var bitbucketServerRouter = util.Router{
 	"/rest/api/1.0/projects/TEST-ORG/repos/test-repo/webhooks": util.MethodMap{
 		"POST": "webhook.json",
 	},
+	"/rest/api/1.0/projects/TEST-ORG/repos/test-repo/settings/pull-requests": util.MethodMap{
+		"POST": "pr-settings.json",
+	},
+	"/rest/branch-permissions/2.0/projects/TEST-ORG/repos/test-repo/restrictions": util.MethodMap{
+		"GET":  "branch-restrictions.json",
+		"POST": "branch-restriction.json",
+	},
 	"/rest/api/1.0/users/test-user": util.MethodMap{
 		"GET": "user.json",
 	},
@@ -291,6 +298,51 @@ func (suite *BitbucketServerProviderTestSuite) TestCreateWebHook() {
 	suite.Require().Nil(err)
 }
 
+func (suite *BitbucketServerProviderTestSuite) TestProtectBranch() {
+	provider := *suite.provider
+	provider.Server.URL = suite.server.URL
+
+	protection := &gits.GitBranchProtection{
+		Branch:            "master",
+		RequiredContexts:  []string{"serverless-jenkins"},
+		RequiredApprovals: 1,
+	}
+	err := provider.ProtectBranch("TEST-ORG", "test-repo", protection)
+
+	suite.Require().Nil(err)
+
+	err = provider.ProtectBranch("TEST-ORG", "missing-repo", protection)
+
+	suite.Require().NotNil(err)
+}
+
+func (suite *BitbucketServerProviderTestSuite) TestProtectBranchSkipsExistingRestrictions() {
+	provider := *suite.provider
+	provider.Server.URL = suite.server.URL
+
+	posted := []string{}
+	suite.mux.HandleFunc("/rest/branch-permissions/2.0/projects/TEST-ORG/repos/protected-repo/restrictions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			suite.Require().Equal("refs/heads/master", r.URL.Query().Get("matcherId"))
+			w.Write([]byte(`{"values": [{"type": "pull-request-only"}, {"type": "no-deletes"}]}`))
+			return
+		}
+		posted = append(posted, r.Method)
+	})
+	suite.mux.HandleFunc("/rest/api/1.0/projects/TEST-ORG/repos/protected-repo/settings/pull-requests", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+
+	protection := &gits.GitBranchProtection{
+		Branch:            "master",
+		RequiredApprovals: 1,
+	}
+	err := provider.ProtectBranch("TEST-ORG", "protected-repo", protection)
+
+	suite.Require().Nil(err)
+	suite.Require().Empty(posted)
+}
+
 func (suite *BitbucketServerProviderTestSuite) TestUserInfo() {
 
 	userInfo := suite.provider.UserInfo("test-user")
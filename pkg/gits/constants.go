@@ -3,6 +3,7 @@ package gits
 const (
 	KindBitBucketCloud  = "bitbucketcloud"
 	KindBitBucketServer = "bitbucketserver"
+	KindGerrit          = "gerrit"
 	KindGitea           = "gitea"
 	KindGitlab          = "gitlab"
 	KindGitHub          = "github"
@@ -12,5 +13,5 @@ const (
 )
 
 var (
-	KindGits = []string{KindBitBucketCloud, KindBitBucketServer, KindGitea, KindGitHub, KindGitlab}
+	KindGits = []string{KindBitBucketCloud, KindBitBucketServer, KindGerrit, KindGitea, KindGitHub, KindGitlab}
 )
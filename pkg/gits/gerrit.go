@@ -2,14 +2,33 @@ package gits
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	gerrit "github.com/andygrunwald/go-gerrit"
 	"github.com/google/go-github/github"
 	"github.com/jenkins-x/jx/pkg/auth"
 	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
 )
 
+const (
+	// GerritVerifiedLabel the label used to record the result of CI builds on a change
+	GerritVerifiedLabel = "Verified"
+
+	gerritTimestampLayout = "2006-01-02 15:04:05.000000000"
+	gerritWebHookName     = "jenkins-x"
+)
+
+// GerritProvider implements GitProvider interface for a Gerrit server.
+//
+// Gerrit projects are mapped onto repositories using the "org/name" naming convention,
+// pull requests are mapped onto changes and commit statuses onto votes on the Verified label.
 type GerritProvider struct {
 	Client   *gerrit.Client
 	Username string
@@ -20,67 +39,564 @@ type GerritProvider struct {
 	Git    Gitter
 }
 
+type gerritAccount struct {
+	AccountID int    `json:"_account_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Username  string `json:"username,omitempty"`
+}
+
+type gerritApproval struct {
+	gerritAccount
+	Value int    `json:"value,omitempty"`
+	Date  string `json:"date,omitempty"`
+}
+
+type gerritLabel struct {
+	Approved *gerritAccount   `json:"approved,omitempty"`
+	Rejected *gerritAccount   `json:"rejected,omitempty"`
+	All      []gerritApproval `json:"all,omitempty"`
+}
+
+type gerritCommit struct {
+	Commit    string        `json:"commit,omitempty"`
+	Subject   string        `json:"subject,omitempty"`
+	Message   string        `json:"message,omitempty"`
+	Author    gerritAccount `json:"author,omitempty"`
+	Committer gerritAccount `json:"committer,omitempty"`
+}
+
+type gerritRevision struct {
+	Number int          `json:"_number,omitempty"`
+	Ref    string       `json:"ref,omitempty"`
+	Commit gerritCommit `json:"commit,omitempty"`
+}
+
+type gerritChange struct {
+	ID              string                    `json:"id,omitempty"`
+	Project         string                    `json:"project,omitempty"`
+	Branch          string                    `json:"branch,omitempty"`
+	ChangeID        string                    `json:"change_id,omitempty"`
+	Subject         string                    `json:"subject,omitempty"`
+	Status          string                    `json:"status,omitempty"`
+	Updated         string                    `json:"updated,omitempty"`
+	Submitted       string                    `json:"submitted,omitempty"`
+	Mergeable       *bool                     `json:"mergeable,omitempty"`
+	Number          int                       `json:"_number,omitempty"`
+	Owner           gerritAccount             `json:"owner,omitempty"`
+	Labels          map[string]gerritLabel    `json:"labels,omitempty"`
+	CurrentRevision string                    `json:"current_revision,omitempty"`
+	Revisions       map[string]gerritRevision `json:"revisions,omitempty"`
+}
+
+type gerritMergeInput struct {
+	Source string `json:"source"`
+}
+
+type gerritChangeInput struct {
+	Project string            `json:"project"`
+	Branch  string            `json:"branch"`
+	Subject string            `json:"subject"`
+	Topic   string            `json:"topic,omitempty"`
+	Merge   *gerritMergeInput `json:"merge,omitempty"`
+}
+
+type gerritWebHookInput struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events,omitempty"`
+}
+
 func NewGerritProvider(server *auth.AuthServer, user *auth.UserAuth, git Gitter) (GitProvider, error) {
-	return nil, nil
+	client, err := gerrit.NewClient(server.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	client.Authentication.SetBasicAuth(user.Username, user.ApiToken)
+
+	provider := GerritProvider{
+		Client:   client,
+		Server:   *server,
+		User:     *user,
+		Username: user.Username,
+		Context:  context.Background(),
+		Git:      git,
+	}
+	return &provider, nil
+}
+
+// gerritProjectName returns the Gerrit project name for the given organisation and repository
+func gerritProjectName(org string, name string) string {
+	if org == "" {
+		return name
+	}
+	return org + "/" + name
+}
+
+// splitGerritProjectName splits a Gerrit project name into its organisation and repository name
+func splitGerritProjectName(project string) (string, string) {
+	idx := strings.LastIndex(project, "/")
+	if idx < 0 {
+		return "", project
+	}
+	return project[0:idx], project[idx+1:]
+}
+
+func parseGerritTimestamp(text string) *time.Time {
+	if text == "" {
+		return nil
+	}
+	t, err := time.Parse(gerritTimestampLayout, text)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+func (p *GerritProvider) toGitRepository(project string) *GitRepository {
+	_, name := splitGerritProjectName(project)
+	cloneURL := util.UrlJoin(p.Server.URL, project)
+	if !strings.HasSuffix(cloneURL, ".git") {
+		cloneURL += ".git"
+	}
+	sshURL := ""
+	u, err := url.Parse(p.Server.URL)
+	if err == nil && u.Hostname() != "" {
+		sshURL = fmt.Sprintf("ssh://%s@%s:29418/%s", p.Username, u.Hostname(), project)
+	}
+	return &GitRepository{
+		Name:             name,
+		AllowMergeCommit: true,
+		HTMLURL:          util.UrlJoin(p.Server.URL, "admin/repos", project),
+		CloneURL:         cloneURL,
+		SSHURL:           sshURL,
+	}
+}
+
+func (p *GerritProvider) changeURL(project string, number int) string {
+	return util.UrlJoin(p.Server.URL, "c", project, "+", strconv.Itoa(number))
+}
+
+func (p *GerritProvider) toGitUser(account *gerritAccount) *GitUser {
+	login := account.Username
+	if login == "" {
+		login = account.Email
+	}
+	return &GitUser{
+		URL:   util.UrlJoin(p.Server.URL, "q", "owner:"+login),
+		Login: login,
+		Name:  account.Name,
+		Email: account.Email,
+	}
+}
+
+// doRequest invokes a REST endpoint on Gerrit which is not covered by the client library
+func (p *GerritProvider) doRequest(method string, path string, body interface{}, result interface{}) (*gerrit.Response, error) {
+	req, err := p.Client.NewRequest(method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	return p.Client.Do(req, result)
+}
+
+func (p *GerritProvider) getChange(number int) (*gerritChange, error) {
+	change := &gerritChange{}
+	path := fmt.Sprintf("changes/%d?o=CURRENT_REVISION&o=CURRENT_COMMIT&o=DETAILED_LABELS&o=DETAILED_ACCOUNTS", number)
+	_, err := p.doRequest("GET", path, nil, change)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Gerrit change %d: %s", number, err)
+	}
+	return change, nil
+}
+
+func (p *GerritProvider) queryChanges(query string) ([]gerritChange, error) {
+	changes := []gerritChange{}
+	path := "changes/?q=" + url.QueryEscape(query) + "&o=CURRENT_REVISION&o=DETAILED_LABELS&o=DETAILED_ACCOUNTS"
+	_, err := p.doRequest("GET", path, nil, &changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Gerrit changes with %s: %s", query, err)
+	}
+	return changes, nil
+}
+
+// populatePullRequest updates the pull request with the details of the Gerrit change
+func (p *GerritProvider) populatePullRequest(pr *GitPullRequest, change *gerritChange) {
+	org, name := splitGerritProjectName(change.Project)
+	number := change.Number
+	state := gerritChangeState(change.Status)
+	merged := change.Status == "MERGED"
+	changeURL := p.changeURL(change.Project, number)
+
+	pr.URL = changeURL
+	pr.Owner = org
+	pr.Repo = name
+	pr.Number = &number
+	pr.State = &state
+	pr.Merged = &merged
+	pr.Mergeable = change.Mergeable
+	pr.Title = change.Subject
+	pr.DiffURL = &changeURL
+	pr.Author = p.toGitUser(&change.Owner)
+	if change.CurrentRevision != "" {
+		pr.LastCommitSha = change.CurrentRevision
+		headRef := change.CurrentRevision
+		if rev, ok := change.Revisions[change.CurrentRevision]; ok {
+			if rev.Ref != "" {
+				headRef = rev.Ref
+			}
+			pr.Body = rev.Commit.Message
+		}
+		pr.HeadRef = &headRef
+	}
+	if merged {
+		pr.MergeCommitSHA = &pr.LastCommitSha
+		pr.MergedAt = parseGerritTimestamp(change.Submitted)
+		pr.ClosedAt = pr.MergedAt
+	} else if change.Status == "ABANDONED" {
+		pr.ClosedAt = parseGerritTimestamp(change.Updated)
+	}
+}
+
+// gerritChangeState maps the status of a Gerrit change onto the pull request states used by the other providers
+func gerritChangeState(status string) string {
+	switch status {
+	case "NEW":
+		return "open"
+	case "ABANDONED":
+		return "closed"
+	default:
+		return strings.ToLower(status)
+	}
+}
+
+// verifiedState returns the commit status for the Verified label of the change
+func verifiedState(change *gerritChange) string {
+	label, ok := change.Labels[GerritVerifiedLabel]
+	if !ok {
+		return "pending"
+	}
+	if label.Rejected != nil {
+		return "failure"
+	}
+	if label.Approved != nil {
+		return "success"
+	}
+	return "pending"
+}
+
+func verifiedVoteState(value int) string {
+	if value > 0 {
+		return "success"
+	}
+	if value < 0 {
+		return "failure"
+	}
+	return "pending"
+}
+
+func (p *GerritProvider) ListOrganisations() ([]GitOrganisation, error) {
+	answer := []GitOrganisation{}
+	projects, _, err := p.Client.Projects.ListProjects(&gerrit.ProjectOptions{})
+	if err != nil {
+		return answer, err
+	}
+	orgs := map[string]string{}
+	for name := range *projects {
+		org, _ := splitGerritProjectName(name)
+		if org != "" {
+			orgs[org] = org
+		}
+	}
+	for _, org := range util.SortedMapKeys(orgs) {
+		answer = append(answer, GitOrganisation{Login: org})
+	}
+	return answer, nil
 }
 
 func (p *GerritProvider) ListRepositories(org string) ([]*GitRepository, error) {
-	return nil, nil
+	answer := []*GitRepository{}
+	opt := &gerrit.ProjectOptions{}
+	if org != "" {
+		opt.Prefix = org + "/"
+	}
+	projects, _, err := p.Client.Projects.ListProjects(opt)
+	if err != nil {
+		return answer, err
+	}
+	names := []string{}
+	for name := range *projects {
+		projectOrg, _ := splitGerritProjectName(name)
+		if projectOrg == org {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		answer = append(answer, p.toGitRepository(name))
+	}
+	return answer, nil
 }
 
 func (p *GerritProvider) CreateRepository(org string, name string, private bool) (*GitRepository, error) {
-	return nil, nil
+	project := gerritProjectName(org, name)
+	input := &gerrit.ProjectInput{
+		Name:              project,
+		CreateEmptyCommit: true,
+	}
+	if private {
+		log.Warnf("Gerrit controls the visibility of project %s through the access rights of its parent project\n", project)
+	}
+	_, _, err := p.Client.Projects.CreateProject(project, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gerrit project %s: %s", project, err)
+	}
+	return p.toGitRepository(project), nil
 }
 
 func (p *GerritProvider) GetRepository(org string, name string) (*GitRepository, error) {
-	return nil, nil
-}
-
+	project := gerritProjectName(org, name)
+	info, _, err := p.Client.Projects.GetProject(project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Gerrit project %s: %s", project, err)
+	}
+	if info.Name != "" {
+		project = info.Name
+	}
+	return p.toGitRepository(project), nil
+}
+
+// DeleteRepository deletes the project using the delete-project plugin which must be installed on the Gerrit server
 func (p *GerritProvider) DeleteRepository(org string, name string) error {
+	project := gerritProjectName(org, name)
+	path := "projects/" + url.QueryEscape(project) + "/delete-project~delete"
+	body := map[string]interface{}{
+		"force":    false,
+		"preserve": false,
+	}
+	_, err := p.doRequest("POST", path, body, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete Gerrit project %s: %s", project, err)
+	}
 	return nil
 }
 
 func (p *GerritProvider) ForkRepository(originalOrg string, name string, destinationOrg string) (*GitRepository, error) {
-	return nil, nil
+	return nil, fmt.Errorf("Forking of repositories is not supported for Gerrit")
 }
 
 func (p *GerritProvider) RenameRepository(org string, name string, newName string) (*GitRepository, error) {
-	return nil, nil
+	return nil, fmt.Errorf("Rename of repositories is not supported for Gerrit")
 }
 
 func (p *GerritProvider) ValidateRepositoryName(org string, name string) error {
-	return nil
-}
-
+	project := gerritProjectName(org, name)
+	_, resp, err := p.Client.Projects.GetProject(project)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if err == nil {
+		return fmt.Errorf("repository %s already exists", project)
+	}
+	return err
+}
+
+// CreatePullRequest creates a Gerrit change which merges the head branch into the base branch
 func (p *GerritProvider) CreatePullRequest(data *GitPullRequestArguments) (*GitPullRequest, error) {
-	return nil, nil
+	repo := data.GitRepositoryInfo
+	project := gerritProjectName(repo.Organisation, repo.Name)
+	input := &gerritChangeInput{
+		Project: project,
+		Branch:  strings.TrimPrefix(data.Base, "refs/heads/"),
+		Subject: data.Title,
+		Merge: &gerritMergeInput{
+			Source: data.Head,
+		},
+	}
+	change := &gerritChange{}
+	_, err := p.doRequest("POST", "changes/", input, change)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gerrit change on project %s: %s", project, err)
+	}
+	if data.Body != "" {
+		err = p.setReview(change.Number, "current", data.Body, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	pr := &GitPullRequest{}
+	p.populatePullRequest(pr, change)
+	return pr, nil
 }
 
 func (p *GerritProvider) UpdatePullRequestStatus(pr *GitPullRequest) error {
+	if pr.Number == nil {
+		return fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	change, err := p.getChange(*pr.Number)
+	if err != nil {
+		return err
+	}
+	p.populatePullRequest(pr, change)
 	return nil
 }
 
 func (p *GerritProvider) GetPullRequest(owner string, repo *GitRepositoryInfo, number int) (*GitPullRequest, error) {
-	return nil, nil
+	change, err := p.getChange(number)
+	if err != nil {
+		return nil, err
+	}
+	pr := &GitPullRequest{}
+	p.populatePullRequest(pr, change)
+	return pr, nil
 }
 
 func (p *GerritProvider) GetPullRequestCommits(owner string, repo *GitRepositoryInfo, number int) ([]*GitCommit, error) {
-	return nil, nil
-}
-
+	commit := &gerritCommit{}
+	path := fmt.Sprintf("changes/%d/revisions/current/commit", number)
+	_, err := p.doRequest("GET", path, nil, commit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the commit of Gerrit change %d: %s", number, err)
+	}
+	project := gerritProjectName(repo.Organisation, repo.Name)
+	return []*GitCommit{
+		{
+			SHA:       commit.Commit,
+			Message:   commit.Message,
+			Author:    p.toGitUser(&commit.Author),
+			Committer: p.toGitUser(&commit.Committer),
+			URL:       p.changeURL(project, number),
+		},
+	}, nil
+}
+
+// PullRequestLastCommitStatus returns the status of the Verified label on the current patch set of the change
 func (p *GerritProvider) PullRequestLastCommitStatus(pr *GitPullRequest) (string, error) {
-	return "", nil
+	if pr.Number == nil {
+		return "", fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	change, err := p.getChange(*pr.Number)
+	if err != nil {
+		return "", err
+	}
+	return verifiedState(change), nil
 }
 
+// ListCommitStatus returns a status for each Verified vote on the changes containing the given commit
 func (p *GerritProvider) ListCommitStatus(org string, repo string, sha string) ([]*GitRepoStatus, error) {
-	return nil, nil
+	answer := []*GitRepoStatus{}
+	changes, err := p.queryChanges("commit:" + sha)
+	if err != nil {
+		return answer, err
+	}
+	for _, change := range changes {
+		label, ok := change.Labels[GerritVerifiedLabel]
+		if !ok {
+			continue
+		}
+		changeURL := p.changeURL(change.Project, change.Number)
+		for _, vote := range label.All {
+			voter := vote.Username
+			if voter == "" {
+				voter = vote.Name
+			}
+			answer = append(answer, &GitRepoStatus{
+				ID:          voter,
+				Context:     GerritVerifiedLabel,
+				URL:         changeURL,
+				State:       verifiedVoteState(vote.Value),
+				TargetURL:   changeURL,
+				Description: fmt.Sprintf("%s %+d by %s", GerritVerifiedLabel, vote.Value, voter),
+			})
+		}
+	}
+	return answer, nil
+}
+
+// UpdateCommitStatus votes on the Verified label of the change containing the given commit
+func (p *GerritProvider) UpdateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	changes, err := p.queryChanges("commit:" + sha)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("no Gerrit change found for commit %s", sha)
+	}
+	vote := "0"
+	if status.IsSuccess() {
+		vote = "+1"
+	} else if status.IsFailed() {
+		vote = "-1"
+	}
+	message := status.Description
+	if status.TargetURL != "" {
+		message = strings.TrimSpace(message + " " + status.TargetURL)
+	}
+	err = p.setReview(changes[0].Number, sha, message, map[string]string{GerritVerifiedLabel: vote})
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func (p *GerritProvider) setReview(number int, revision string, message string, labels map[string]string) error {
+	input := &gerrit.ReviewInput{
+		Message: message,
+		Labels:  labels,
+	}
+	_, _, err := p.Client.Changes.SetReview(strconv.Itoa(number), revision, input)
+	if err != nil {
+		return fmt.Errorf("failed to review Gerrit change %d: %s", number, err)
+	}
+	return nil
 }
 
+// MergePullRequest submits the Gerrit change
 func (p *GerritProvider) MergePullRequest(pr *GitPullRequest, message string) error {
+	if pr.Number == nil {
+		return fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	number := *pr.Number
+	if message != "" {
+		err := p.setReview(number, "current", message, nil)
+		if err != nil {
+			return err
+		}
+	}
+	_, _, err := p.Client.Changes.SubmitChange(strconv.Itoa(number), &gerrit.SubmitInput{})
+	if err != nil {
+		return fmt.Errorf("failed to submit Gerrit change %d: %s", number, err)
+	}
 	return nil
 }
 
+// CreateWebHook registers a remote with the webhooks plugin which must be installed on the Gerrit server
 func (p *GerritProvider) CreateWebHook(data *GitWebHookArguments) error {
+	repo := data.Repo
+	if repo == nil {
+		return fmt.Errorf("Missing property Repo")
+	}
+	org := repo.Organisation
+	if org == "" {
+		org = data.Owner
+	}
+	project := gerritProjectName(org, repo.Name)
+	input := &gerritWebHookInput{
+		URL:    data.URL,
+		Events: []string{"patchset-created", "change-merged", "change-abandoned", "comment-added", "ref-updated"},
+	}
+	if data.Secret != "" {
+		log.Warnf("The Gerrit webhooks plugin does not support secrets so ignoring the secret for project %s\n", project)
+	}
+	path := "projects/" + url.QueryEscape(project) + "/webhooks~remotes/" + gerritWebHookName
+	_, err := p.doRequest("PUT", path, input, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook on Gerrit project %s: %s", project, err)
+	}
+	return nil
+}
+
+// ProtectBranch is not supported as Gerrit controls who can submit changes through the access rights of the project
+func (p *GerritProvider) ProtectBranch(org string, name string, protection *GitBranchProtection) error {
+	log.Warnf("Gerrit controls who can submit changes to project %s through its access rights\n", gerritProjectName(org, name))
 	return nil
 }
 
@@ -105,75 +621,99 @@ func (p *GerritProvider) IsGerrit() bool {
 }
 
 func (p *GerritProvider) Kind() string {
-	return "gerrit"
+	return KindGerrit
 }
 
 func (p *GerritProvider) GetIssue(org string, name string, number int) (*GitIssue, error) {
+	log.Warn("Gerrit does not support issues")
 	return nil, nil
 }
 
 func (p *GerritProvider) IssueURL(org string, name string, number int, isPull bool) string {
+	if isPull {
+		return p.changeURL(gerritProjectName(org, name), number)
+	}
 	return ""
 }
 
 func (p *GerritProvider) SearchIssues(org string, name string, query string) ([]*GitIssue, error) {
-	return nil, nil
+	log.Warn("Gerrit does not support issues")
+	return []*GitIssue{}, nil
 }
 
 func (p *GerritProvider) SearchIssuesClosedSince(org string, name string, t time.Time) ([]*GitIssue, error) {
-	return nil, nil
+	return []*GitIssue{}, nil
 }
 
 func (p *GerritProvider) CreateIssue(owner string, repo string, issue *GitIssue) (*GitIssue, error) {
-	return nil, nil
+	return nil, fmt.Errorf("Gerrit does not support issues")
 }
 
 func (p *GerritProvider) HasIssues() bool {
 	return false
 }
 
+// AddPRComment adds a review message to the current patch set of the change
 func (p *GerritProvider) AddPRComment(pr *GitPullRequest, comment string) error {
-	return nil
+	if pr.Number == nil {
+		return fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	return p.setReview(*pr.Number, "current", comment, nil)
 }
 
 func (p *GerritProvider) CreateIssueComment(owner string, repo string, number int, comment string) error {
+	log.Warn("Gerrit does not support issues")
 	return nil
 }
 
 func (p *GerritProvider) UpdateRelease(owner string, repo string, tag string, releaseInfo *GitRelease) error {
+	log.Warn("Gerrit doesn't support releases")
 	return nil
 }
 
 func (p *GerritProvider) ListReleases(org string, name string) ([]*GitRelease, error) {
-	return nil, nil
+	log.Warn("Gerrit doesn't support releases")
+	return []*GitRelease{}, nil
 }
 
+// Exposed by the Gerrit Code Review plugin https://plugins.jenkins.io/gerrit-code-review
 func (p *GerritProvider) JenkinsWebHookPath(gitURL string, secret string) string {
-	return ""
+	return "/gerrit-webhook/"
 }
 
 func (p *GerritProvider) Label() string {
-	return ""
+	return p.Server.Label()
 }
 
 func (p *GerritProvider) ServerURL() string {
-	return ""
+	return p.Server.URL
 }
 
+// BranchArchiveURL returns the archive of the branch served by the gitiles plugin
 func (p *GerritProvider) BranchArchiveURL(org string, name string, branch string) string {
-	return ""
+	return util.UrlJoin(p.ServerURL(), "plugins/gitiles", gerritProjectName(org, name), "+archive", branch+".tar.gz")
 }
 
 func (p *GerritProvider) CurrentUsername() string {
-	return ""
+	return p.Username
 }
 
 func (p *GerritProvider) UserAuth() auth.UserAuth {
-	return auth.UserAuth{}
+	return p.User
 }
 
 func (p *GerritProvider) UserInfo(username string) *GitUser {
-	return nil
+	account, _, err := p.Client.Accounts.GetAccount(username)
+	if err != nil {
+		log.Error("Unable to fetch user info for " + username + " due to " + err.Error() + "\n")
+		return nil
+	}
+	return p.toGitUser(&gerritAccount{
+		AccountID: account.AccountID,
+		Name:      account.Name,
+		Email:     account.Email,
+		Username:  account.Username,
+	})
 }
 
 func (p *GerritProvider) AddCollaborator(user string, organisation string, repo string) error {
@@ -190,3 +730,8 @@ func (p *GerritProvider) AcceptInvitation(ID int64) (*github.Response, error) {
 	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for gerrit.\n")
 	return &github.Response{}, nil
 }
+
+// GerritAccessTokenURL returns the URL where users can generate their HTTP password
+func GerritAccessTokenURL(url string) string {
+	return util.UrlJoin(url, "/settings/#HTTPCredentials")
+}
@@ -0,0 +1,289 @@
+package gits_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gerrit "github.com/andygrunwald/go-gerrit"
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/suite"
+)
+
+type GerritProviderTestSuite struct {
+	suite.Suite
+	mux      *http.ServeMux
+	server   *httptest.Server
+	provider *gits.GerritProvider
+}
+
+var gerritRouter = util.Router{
+	"/projects/": util.MethodMap{
+		"GET": "projects.json",
+	},
+	"/projects/test-org/test-repo": util.MethodMap{
+		"GET": "project.test-repo.json",
+	},
+	"/projects/test-org/test-repo123": util.MethodMap{
+		"PUT": "project.test-repo123.json",
+	},
+	"/projects/test-org/test-repo/delete-project~delete": util.MethodMap{
+		"POST": "empty.json",
+	},
+	"/projects/test-org/test-repo/webhooks~remotes/jenkins-x": util.MethodMap{
+		"PUT": "webhook.json",
+	},
+	"/changes/": util.MethodMap{
+		"GET":  "changes.json",
+		"POST": "change.json",
+	},
+	"/changes/1": util.MethodMap{
+		"GET": "change.json",
+	},
+	"/changes/1/revisions/current/commit": util.MethodMap{
+		"GET": "commit.json",
+	},
+	"/changes/1/revisions/current/review": util.MethodMap{
+		"POST": "review.json",
+	},
+	"/changes/1/revisions/d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c/review": util.MethodMap{
+		"POST": "review.json",
+	},
+	"/changes/1/submit": util.MethodMap{
+		"POST": "change-merged.json",
+	},
+	"/accounts/test-user": util.MethodMap{
+		"GET": "account.json",
+	},
+}
+
+func (suite *GerritProviderTestSuite) SetupSuite() {
+	suite.mux = http.NewServeMux()
+
+	for path, methodMap := range gerritRouter {
+		suite.mux.HandleFunc(path, util.GetMockAPIResponseFromFile("test_data/gerrit", methodMap))
+	}
+
+	as := auth.AuthServer{
+		URL:         "http://auth.example.com",
+		Name:        "Test Auth Server",
+		Kind:        "gerrit",
+		CurrentUser: "test-user",
+	}
+	ua := auth.UserAuth{
+		Username: "test-user",
+		ApiToken: "0123456789abdef",
+	}
+
+	git := gits.NewGitCLI()
+	gp, err := gits.NewGerritProvider(&as, &ua, git)
+
+	suite.Require().NotNil(gp)
+	suite.Require().Nil(err)
+
+	var ok bool
+	suite.provider, ok = gp.(*gits.GerritProvider)
+	suite.Require().True(ok)
+	suite.Require().NotNil(suite.provider)
+
+	suite.server = httptest.NewServer(suite.mux)
+	suite.Require().NotNil(suite.server)
+
+	suite.provider.Client, err = gerrit.NewClient(suite.server.URL, nil)
+	suite.Require().Nil(err)
+}
+
+func (suite *GerritProviderTestSuite) TestListOrganizations() {
+	orgs, err := suite.provider.ListOrganisations()
+	suite.Require().Nil(err)
+	suite.Require().Equal([]gits.GitOrganisation{{Login: "another-org"}, {Login: "test-org"}}, orgs)
+}
+
+func (suite *GerritProviderTestSuite) TestListRepositories() {
+	repos, err := suite.provider.ListRepositories("test-org")
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(2, len(repos))
+	suite.Require().Equal("other-repo", repos[0].Name)
+	suite.Require().Equal("test-repo", repos[1].Name)
+	suite.Require().Equal("http://auth.example.com/test-org/test-repo.git", repos[1].CloneURL)
+	suite.Require().Equal("ssh://test-user@auth.example.com:29418/test-org/test-repo", repos[1].SSHURL)
+}
+
+func (suite *GerritProviderTestSuite) TestGetRepository() {
+	repo, err := suite.provider.GetRepository("test-org", "test-repo")
+	suite.Require().Nil(err)
+	suite.Require().NotNil(repo)
+	suite.Require().Equal("test-repo", repo.Name)
+}
+
+func (suite *GerritProviderTestSuite) TestCreateRepository() {
+	repo, err := suite.provider.CreateRepository("test-org", "test-repo123", false)
+
+	suite.Require().Nil(err)
+	suite.Require().NotNil(repo)
+	suite.Require().Equal("test-repo123", repo.Name)
+}
+
+func (suite *GerritProviderTestSuite) TestDeleteRepository() {
+	err := suite.provider.DeleteRepository("test-org", "test-repo")
+	suite.Require().Nil(err)
+}
+
+func (suite *GerritProviderTestSuite) TestValidateRepositoryName() {
+	err := suite.provider.ValidateRepositoryName("test-org", "test-repo")
+	suite.Require().NotNil(err)
+}
+
+func (suite *GerritProviderTestSuite) TestCreatePullRequest() {
+	args := gits.GitPullRequestArguments{
+		GitRepositoryInfo: &gits.GitRepositoryInfo{
+			Name:         "test-repo",
+			Organisation: "test-org",
+		},
+		Head:  "feat/world",
+		Base:  "master",
+		Title: "Test Pull Request",
+		Body:  "Test Pull request description",
+	}
+
+	pr, err := suite.provider.CreatePullRequest(&args)
+
+	suite.Require().Nil(err)
+	suite.Require().NotNil(pr)
+	suite.Require().Equal(1, *pr.Number)
+	suite.Require().Equal("open", *pr.State)
+	suite.Require().Equal("test-org", pr.Owner)
+	suite.Require().Equal("test-repo", pr.Repo)
+	suite.Require().Equal("http://auth.example.com/c/test-org/test-repo/+/1", pr.URL)
+}
+
+func (suite *GerritProviderTestSuite) TestUpdatePullRequestStatus() {
+	number := 1
+	state := "closed"
+
+	pr := &gits.GitPullRequest{
+		Number: &number,
+		State:  &state,
+	}
+
+	err := suite.provider.UpdatePullRequestStatus(pr)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("open", *pr.State)
+	suite.Require().False(*pr.Merged)
+	suite.Require().False(pr.IsClosed())
+	suite.Require().Equal("d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c", pr.LastCommitSha)
+	suite.Require().Equal("test-user", pr.Author.Login)
+}
+
+func (suite *GerritProviderTestSuite) TestGetPullRequest() {
+	pr, err := suite.provider.GetPullRequest(
+		"test-org",
+		&gits.GitRepositoryInfo{Name: "test-repo", Organisation: "test-org"},
+		1,
+	)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(1, *pr.Number)
+	suite.Require().Equal("Test Pull Request", pr.Title)
+}
+
+func (suite *GerritProviderTestSuite) TestPullRequestCommits() {
+	commits, err := suite.provider.GetPullRequestCommits("test-org", &gits.GitRepositoryInfo{
+		Name:         "test-repo",
+		Organisation: "test-org",
+	}, 1)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(1, len(commits))
+	suite.Require().Equal("d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c", commits[0].SHA)
+	suite.Require().Equal("Test User", commits[0].Author.Name)
+}
+
+func (suite *GerritProviderTestSuite) TestPullRequestLastCommitStatus() {
+	number := 1
+	pr := &gits.GitPullRequest{
+		Number: &number,
+	}
+	status, err := suite.provider.PullRequestLastCommitStatus(pr)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("success", status)
+}
+
+func (suite *GerritProviderTestSuite) TestListCommitStatuses() {
+	statuses, err := suite.provider.ListCommitStatus("test-org", "test-repo", "d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c")
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(1, len(statuses))
+	suite.Require().Equal("jenkins-x", statuses[0].ID)
+	suite.Require().Equal("success", statuses[0].State)
+	suite.Require().True(gits.IsGitRepoStatusSuccess(statuses...))
+}
+
+func (suite *GerritProviderTestSuite) TestUpdateCommitStatus() {
+	status := &gits.GitRepoStatus{
+		State:       "success",
+		Description: "build passed",
+		TargetURL:   "https://my-jenkins.example.com/job/test-repo/1",
+	}
+	result, err := suite.provider.UpdateCommitStatus("test-org", "test-repo", "d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c", status)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(status, result)
+}
+
+func (suite *GerritProviderTestSuite) TestMergePullRequest() {
+	number := 1
+	pr := &gits.GitPullRequest{
+		Number: &number,
+	}
+	err := suite.provider.MergePullRequest(pr, "Merging from unit tests")
+
+	suite.Require().Nil(err)
+}
+
+func (suite *GerritProviderTestSuite) TestAddPRComment() {
+	number := 1
+	pr := &gits.GitPullRequest{
+		Number: &number,
+	}
+	err := suite.provider.AddPRComment(pr, "a comment from unit tests")
+
+	suite.Require().Nil(err)
+}
+
+func (suite *GerritProviderTestSuite) TestCreateWebHook() {
+	data := &gits.GitWebHookArguments{
+		Repo:   &gits.GitRepositoryInfo{Name: "test-repo", Organisation: "test-org"},
+		URL:    "https://my-jenkins.example.com/gerrit-webhook/",
+		Secret: "someSecret",
+	}
+	err := suite.provider.CreateWebHook(data)
+
+	suite.Require().Nil(err)
+}
+
+func (suite *GerritProviderTestSuite) TestUserInfo() {
+	userInfo := suite.provider.UserInfo("test-user")
+
+	suite.Require().NotNil(userInfo)
+	suite.Require().Equal("test-user", userInfo.Login)
+	suite.Require().Equal("Test User", userInfo.Name)
+	suite.Require().Equal("test-user@example.com", userInfo.Email)
+}
+
+func TestGerritProviderTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping TestGerritProviderTestSuite in short mode")
+	} else {
+		suite.Run(t, new(GerritProviderTestSuite))
+	}
+}
+
+func (suite *GerritProviderTestSuite) TearDownSuite() {
+	suite.server.Close()
+}
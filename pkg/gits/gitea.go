@@ -234,6 +234,11 @@ func (p *GiteaProvider) CreateWebHook(data *GitWebHookArguments) error {
 	return err
 }
 
+func (p *GiteaProvider) ProtectBranch(org string, name string, protection *GitBranchProtection) error {
+	log.Warnf("Protecting branches is not supported for Gitea so ignoring branch %s of %s/%s\n", protection.Branch, org, name)
+	return nil
+}
+
 func (p *GiteaProvider) CreatePullRequest(data *GitPullRequestArguments) (*GitPullRequest, error) {
 	owner := data.GitRepositoryInfo.Organisation
 	repo := data.GitRepositoryInfo.Name
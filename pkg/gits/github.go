@@ -336,6 +336,29 @@ func (p *GitHubProvider) CreateWebHook(data *GitWebHookArguments) error {
 	return err
 }
 
+func (p *GitHubProvider) ProtectBranch(org string, name string, protection *GitBranchProtection) error {
+	contexts := protection.RequiredContexts
+	if contexts == nil {
+		contexts = []string{}
+	}
+	request := &github.ProtectionRequest{
+		RequiredStatusChecks: &github.RequiredStatusChecks{
+			Strict:   true,
+			Contexts: contexts,
+		},
+	}
+	if protection.RequiredApprovals > 0 {
+		request.RequiredPullRequestReviews = &github.PullRequestReviewsEnforcementRequest{
+			RequiredApprovingReviewCount: protection.RequiredApprovals,
+		}
+	}
+	_, _, err := p.Client.Repositories.UpdateBranchProtection(p.Context, org, name, protection.Branch, request)
+	if err != nil {
+		return fmt.Errorf("failed to protect branch %s of %s/%s: %s", protection.Branch, org, name, err)
+	}
+	return nil
+}
+
 func (p *GitHubProvider) CreatePullRequest(data *GitPullRequestArguments) (*GitPullRequest, error) {
 	owner := data.GitRepositoryInfo.Organisation
 	repo := data.GitRepositoryInfo.Name
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -399,17 +400,103 @@ func (g *GitlabProvider) CreateWebHook(data *GitWebHookArguments) error {
 		return nil
 	}
 
-	owner := owner(g.Username, data.Owner)
-	webhookURL := util.UrlJoin(data.URL, owner, data.Repo.Name)
 	opt := &gitlab.AddProjectHookOptions{
-		URL:   &webhookURL,
-		Token: &data.Secret,
+		URL:                 &data.URL,
+		Token:               &data.Secret,
+		PushEvents:          gitlab.Bool(true),
+		TagPushEvents:       gitlab.Bool(true),
+		MergeRequestsEvents: gitlab.Bool(true),
+		NoteEvents:          gitlab.Bool(true),
 	}
 
 	_, _, err = g.Client.Projects.AddProjectHook(pid, opt)
 	return err
 }
 
+// ProtectBranch only allows changes to be merged into the branch by developers. GitLab does not have required
+// commit statuses so merge requests must have a successful pipeline instead
+func (g *GitlabProvider) ProtectBranch(org string, name string, protection *GitBranchProtection) error {
+	pid, err := g.projectId(org, g.Username, name)
+	if err != nil {
+		return err
+	}
+
+	existing, resp, err := g.Client.ProtectedBranches.GetProtectedBranch(pid, protection.Branch)
+	if err != nil {
+		if resp == nil || resp.StatusCode != http.StatusNotFound {
+			return fmt.Errorf("failed to get the protection of branch %s of %s/%s: %s", protection.Branch, org, name, err)
+		}
+		existing = nil
+	}
+	if existing == nil || !isGitlabBranchProtected(existing) {
+		if existing != nil {
+			// GitLab cannot change the access levels of a protected branch so lets replace its protection
+			_, err = g.Client.ProtectedBranches.UnprotectRepositoryBranches(pid, protection.Branch)
+			if err != nil {
+				return fmt.Errorf("failed to unprotect branch %s of %s/%s: %s", protection.Branch, org, name, err)
+			}
+		}
+		branchOpt := &gitlab.ProtectRepositoryBranchesOptions{
+			Name:             gitlab.String(protection.Branch),
+			PushAccessLevel:  gitlab.AccessLevel(gitlab.NoPermissions),
+			MergeAccessLevel: gitlab.AccessLevel(gitlab.DeveloperPermissions),
+		}
+		_, _, err = g.Client.ProtectedBranches.ProtectRepositoryBranches(pid, branchOpt)
+		if err != nil {
+			return fmt.Errorf("failed to protect branch %s of %s/%s: %s", protection.Branch, org, name, err)
+		}
+	}
+
+	projectOpt := &gitlab.EditProjectOptions{
+		OnlyAllowMergeIfPipelineSucceeds: gitlab.Bool(len(protection.RequiredContexts) > 0),
+	}
+	_, _, err = g.Client.Projects.EditProject(pid, projectOpt)
+	if err != nil {
+		return fmt.Errorf("failed to update the merge request settings of %s/%s: %s", org, name, err)
+	}
+	if protection.RequiredApprovals > 0 {
+		return g.requireMergeRequestApprovals(pid, org, name, protection.RequiredApprovals)
+	}
+	return nil
+}
+
+// isGitlabBranchProtected returns true if nobody can push to the branch and developers can merge into it
+func isGitlabBranchProtected(branch *gitlab.ProtectedBranch) bool {
+	return hasOnlyGitlabAccessLevel(branch.PushAccessLevels, gitlab.NoPermissions) &&
+		hasOnlyGitlabAccessLevel(branch.MergeAccessLevels, gitlab.DeveloperPermissions)
+}
+
+func hasOnlyGitlabAccessLevel(levels []*gitlab.BranchAccessDescription, level gitlab.AccessLevelValue) bool {
+	if len(levels) != 1 {
+		return false
+	}
+	return levels[0].AccessLevel == level
+}
+
+// requireMergeRequestApprovals sets the number of approvals merge requests of the project need before they can be
+// merged. Merge request approvals are only available in GitLab Enterprise Edition so they are skipped with a warning
+// if the server does not support them
+func (g *GitlabProvider) requireMergeRequestApprovals(pid string, org string, name string, approvals int) error {
+	opt := &struct {
+		ApprovalsBeforeMerge *int `url:"approvals_before_merge,omitempty" json:"approvals_before_merge,omitempty"`
+	}{
+		ApprovalsBeforeMerge: gitlab.Int(approvals),
+	}
+	req, err := g.Client.NewRequest("POST", fmt.Sprintf("projects/%s/approvals", pid), opt, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := g.Client.Do(req, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			log.Warnf("Could not require %d merge request approvals on %s/%s as the GitLab server does not support merge request approvals\n", approvals, org, name)
+			return nil
+		}
+		return fmt.Errorf("failed to require %d merge request approvals on %s/%s: %s", approvals, org, name, err)
+	}
+	return nil
+}
+
 func (g *GitlabProvider) SearchIssues(org, repo, query string) ([]*GitIssue, error) {
 	opt := &gitlab.ListProjectIssuesOptions{Search: &query}
 	return g.searchIssuesWithOptions(org, repo, opt)
@@ -561,7 +648,11 @@ func (g *GitlabProvider) Kind() string {
 }
 
 func (g *GitlabProvider) JenkinsWebHookPath(gitURL string, secret string) string {
-	return "/project"
+	gitInfo, err := ParseGitURL(gitURL)
+	if err != nil {
+		return "/project"
+	}
+	return util.UrlJoin("/project", owner(gitInfo.Organisation, g.Username), gitInfo.Name)
 }
 
 func (g *GitlabProvider) Label() string {
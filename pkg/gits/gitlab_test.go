@@ -96,6 +96,7 @@ func configureGitlabMock(suite *GitlabProviderSuite, mux *http.ServeMux) {
 	gitlabRouter := util.Router{
 		fmt.Sprintf("/api/v4/projects/%s", gitlabProjectID): util.MethodMap{
 			"GET": "project.json",
+			"PUT": "project.json",
 		},
 	}
 	for path, methodMap := range gitlabRouter {
@@ -172,6 +173,38 @@ func (suite *GitlabProviderSuite) TestAcceptInvitations() {
 	suite.Require().Nil(err)
 }
 
+func (suite *GitlabProviderSuite) TestProtectBranch() {
+	methods := []string{}
+	mux := suite.mux
+	mux.HandleFunc(fmt.Sprintf("/api/v4/projects/%s/protected_branches/master", gitlabProjectID), func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" protected_branches/master")
+		if r.Method == "GET" {
+			w.Write([]byte(`{"name":"master","push_access_levels":[{"access_level":30}],"merge_access_levels":[{"access_level":30}]}`))
+		}
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/v4/projects/%s/protected_branches", gitlabProjectID), func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" protected_branches")
+		w.Write([]byte(`{"name":"master","push_access_levels":[{"access_level":0}],"merge_access_levels":[{"access_level":30}]}`))
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/v4/projects/%s/approvals", gitlabProjectID), func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" approvals")
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	err := suite.provider.ProtectBranch(gitlabUserName, gitlabProjectName, &gits.GitBranchProtection{
+		Branch:            "master",
+		RequiredContexts:  []string{"continuous-integration/jenkins/pr-merge"},
+		RequiredApprovals: 1,
+	})
+	suite.Require().Nil(err)
+	suite.Require().Equal([]string{
+		"GET protected_branches/master",
+		"DELETE protected_branches/master",
+		"POST protected_branches",
+		"POST approvals",
+	}, methods)
+}
+
 // In order for 'go test' to run this suite, we need to create
 // a normal test function and pass our suite to suite.Run
 func TestGitlabProviderSuite(t *testing.T) {
@@ -58,6 +58,10 @@ type GitProvider interface {
 
 	CreateWebHook(data *GitWebHookArguments) error
 
+	// ProtectBranch protects the branch of the repository so changes can only be merged via pull requests which pass
+	// the required commit statuses and approvals
+	ProtectBranch(org string, name string, protection *GitBranchProtection) error
+
 	IsGitHub() bool
 
 	IsGitea() bool
@@ -560,6 +560,21 @@ func (mock *MockGitProvider) MergePullRequest(_param0 *gits.GitPullRequest, _par
 	return ret0
 }
 
+func (mock *MockGitProvider) ProtectBranch(_param0 string, _param1 string, _param2 *gits.GitBranchProtection) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
+	}
+	params := []pegomock.Param{_param0, _param1, _param2}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("ProtectBranch", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
 func (mock *MockGitProvider) PullRequestLastCommitStatus(_param0 *gits.GitPullRequest) (string, error) {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
@@ -1637,6 +1652,41 @@ func (c *GitProvider_MergePullRequest_OngoingVerification) GetAllCapturedArgumen
 	return
 }
 
+func (verifier *VerifierGitProvider) ProtectBranch(_param0 string, _param1 string, _param2 *gits.GitBranchProtection) *GitProvider_ProtectBranch_OngoingVerification {
+	params := []pegomock.Param{_param0, _param1, _param2}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "ProtectBranch", params)
+	return &GitProvider_ProtectBranch_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type GitProvider_ProtectBranch_OngoingVerification struct {
+	mock              *MockGitProvider
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *GitProvider_ProtectBranch_OngoingVerification) GetCapturedArguments() (string, string, *gits.GitBranchProtection) {
+	_param0, _param1, _param2 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1], _param1[len(_param1)-1], _param2[len(_param2)-1]
+}
+
+func (c *GitProvider_ProtectBranch_OngoingVerification) GetAllCapturedArguments() (_param0 []string, _param1 []string, _param2 []*gits.GitBranchProtection) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]string, len(params[0]))
+		for u, param := range params[0] {
+			_param0[u] = param.(string)
+		}
+		_param1 = make([]string, len(params[1]))
+		for u, param := range params[1] {
+			_param1[u] = param.(string)
+		}
+		_param2 = make([]*gits.GitBranchProtection, len(params[2]))
+		for u, param := range params[2] {
+			_param2[u] = param.(*gits.GitBranchProtection)
+		}
+	}
+	return
+}
+
 func (verifier *VerifierGitProvider) PullRequestLastCommitStatus(_param0 *gits.GitPullRequest) *GitProvider_PullRequestLastCommitStatus_OngoingVerification {
 	params := []pegomock.Param{_param0}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "PullRequestLastCommitStatus", params)
@@ -133,6 +133,15 @@ type GitWebHookArguments struct {
 	Secret string
 }
 
+// GitBranchProtection the rules which must pass before changes can be merged into a branch
+type GitBranchProtection struct {
+	Branch string
+	// RequiredContexts the commit status contexts which must succeed before a pull request can be merged
+	RequiredContexts []string
+	// RequiredApprovals the number of approvals a pull request needs before it can be merged
+	RequiredApprovals int
+}
+
 // IsClosed returns true if the PullRequest has been closed
 func (pr *GitPullRequest) IsClosed() bool {
 	return pr.ClosedAt != nil
@@ -143,6 +152,8 @@ func CreateProvider(server *auth.AuthServer, user *auth.UserAuth, git Gitter) (G
 		return NewBitbucketCloudProvider(server, user, git)
 	} else if server.Kind == KindBitBucketServer {
 		return NewBitbucketServerProvider(server, user, git)
+	} else if server.Kind == KindGerrit {
+		return NewGerritProvider(server, user, git)
 	} else if server.Kind == KindGitea {
 		return NewGiteaProvider(server, user, git)
 	} else if server.Kind == KindGitlab {
@@ -175,6 +186,8 @@ func ProviderAccessTokenURL(kind string, url string, username string) string {
 		return BitBucketCloudAccessTokenURL(url, username)
 	case KindBitBucketServer:
 		return BitBucketServerAccessTokenURL(url)
+	case KindGerrit:
+		return GerritAccessTokenURL(url)
 	case KindGitea:
 		return GiteaAccessTokenURL(url)
 	case KindGitlab:
@@ -60,6 +60,7 @@ type FakeRepository struct {
 	issueCount         int
 	Releases           map[string]*GitRelease
 	PullRequestCounter int
+	BranchProtections  map[string]*GitBranchProtection
 }
 
 type FakeProvider struct {
@@ -349,6 +350,24 @@ func (f *FakeProvider) CreateWebHook(data *GitWebHookArguments) error {
 	return nil
 }
 
+func (f *FakeProvider) ProtectBranch(org string, name string, protection *GitBranchProtection) error {
+	repos, ok := f.Repositories[org]
+	if !ok {
+		return fmt.Errorf("organization '%s' not found", org)
+	}
+
+	for _, repo := range repos {
+		if repo.GitRepo.Name == name {
+			if repo.BranchProtections == nil {
+				repo.BranchProtections = map[string]*GitBranchProtection{}
+			}
+			repo.BranchProtections[protection.Branch] = protection
+			return nil
+		}
+	}
+	return fmt.Errorf("repository with name '%s' not found", name)
+}
+
 func (f *FakeProvider) IsGitHub() bool {
 	return f.Type == GitHub
 }
@@ -17,6 +17,7 @@ import (
 
 const (
 	RequirementsFileName = "requirements.yaml"
+	ValuesFileName       = "values.yaml"
 
 	DefaultHelmRepositoryURL = "http://jenkins-x-chartmuseum:8080"
 
@@ -109,6 +110,45 @@ func (r *Requirements) RemoveApp(app string) bool {
 	return false
 }
 
+// FindDependency returns the dependency of the given app which uses the given alias or nil if there is none
+func (r *Requirements) FindDependency(app string, alias string) *Dependency {
+	for _, dep := range r.Dependencies {
+		if dep != nil && dep.Name == app && dep.Alias == alias {
+			return dep
+		}
+	}
+	return nil
+}
+
+// SetAliasVersion sets the version of the dependency of the app which uses the given alias, adding the dependency if
+// it does not exist. This lets the same chart be installed more than once such as during a rollout
+func (r *Requirements) SetAliasVersion(app string, version string, repository string, alias string) {
+	dep := r.FindDependency(app, alias)
+	if dep != nil {
+		dep.Version = version
+		dep.Repository = repository
+		return
+	}
+	r.Dependencies = append(r.Dependencies, &Dependency{
+		Name:       app,
+		Version:    version,
+		Repository: repository,
+		Alias:      alias,
+	})
+	sort.Sort(DepSorter(r.Dependencies))
+}
+
+// RemoveAlias removes the dependency which uses the given alias. Returns true if a dependency was removed
+func (r *Requirements) RemoveAlias(alias string) bool {
+	for i, dep := range r.Dependencies {
+		if dep != nil && dep.Alias == alias {
+			r.Dependencies = append(r.Dependencies[:i], r.Dependencies[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 // FindRequirementsFileName returns the default requirements.yaml file name
 func FindRequirementsFileName(dir string) (string, error) {
 	names := []string{
@@ -189,6 +229,36 @@ func SaveRequirementsFile(fileName string, requirements *Requirements) error {
 	return ioutil.WriteFile(fileName, data, util.DefaultWritePermissions)
 }
 
+// LoadValuesFile loads the values file of a chart or returns empty values if the file does not exist
+func LoadValuesFile(fileName string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	exists, err := util.FileExists(fileName)
+	if err != nil || !exists {
+		return values, err
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return values, err
+	}
+	err = yaml.Unmarshal(data, &values)
+	if err != nil {
+		return values, errors.Wrapf(err, "failed to parse values file %s", fileName)
+	}
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	return values, nil
+}
+
+// SaveValuesFile saves the values file of a chart
+func SaveValuesFile(fileName string, values map[string]interface{}) error {
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, data, util.DefaultWritePermissions)
+}
+
 func LoadChartName(chartFile string) (string, error) {
 	chart, err := chartutil.LoadChartfile(chartFile)
 	if err != nil {
@@ -0,0 +1,323 @@
+package issues
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	azureBoardsAPIVersion = "5.0"
+
+	// AzureBoardsWorkItemType the type of work item created for new issues
+	AzureBoardsWorkItemType = "Bug"
+
+	azureBoardsPatchContentType = "application/json-patch+json"
+
+	// azureBoardsMaxWorkItems the maximum number of work items which can be fetched in one request
+	azureBoardsMaxWorkItems = 200
+)
+
+var azureBoardsClosedStates = []string{"Closed", "Done", "Removed", "Resolved"}
+
+// AzureBoardsService uses the work items of an Azure DevOps project as the issue tracker
+type AzureBoardsService struct {
+	// Server is the URL of the Azure DevOps organisation such as https://dev.azure.com/myorg
+	Server   *auth.AuthServer
+	UserAuth *auth.UserAuth
+	Project  string
+
+	client *restClient
+}
+
+type azureBoardsIdentity struct {
+	DisplayName string `json:"displayName"`
+	UniqueName  string `json:"uniqueName"`
+	ImageURL    string `json:"imageUrl"`
+	URL         string `json:"url"`
+}
+
+type azureBoardsWorkItem struct {
+	ID     int `json:"id"`
+	Fields struct {
+		Title        string               `json:"System.Title"`
+		Description  string               `json:"System.Description"`
+		State        string               `json:"System.State"`
+		Tags         string               `json:"System.Tags"`
+		CreatedDate  *time.Time           `json:"System.CreatedDate"`
+		ChangedDate  *time.Time           `json:"System.ChangedDate"`
+		ClosedDate   *time.Time           `json:"Microsoft.VSTS.Common.ClosedDate"`
+		CreatedBy    *azureBoardsIdentity `json:"System.CreatedBy"`
+		AssignedTo   *azureBoardsIdentity `json:"System.AssignedTo"`
+		ClosedBy     *azureBoardsIdentity `json:"Microsoft.VSTS.Common.ClosedBy"`
+		WorkItemType string               `json:"System.WorkItemType"`
+	} `json:"fields"`
+}
+
+type azureBoardsWorkItems struct {
+	Value []*azureBoardsWorkItem `json:"value"`
+}
+
+type azureBoardsQueryResult struct {
+	WorkItems []struct {
+		ID int `json:"id"`
+	} `json:"workItems"`
+}
+
+type azureBoardsPatch struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value string `json:"value"`
+}
+
+// CreateAzureBoardsIssueProvider creates an issue provider for the work items of an Azure DevOps project
+func CreateAzureBoardsIssueProvider(server *auth.AuthServer, userAuth *auth.UserAuth, project string, batchMode bool) (IssueProvider, error) {
+	if server.URL == "" {
+		return nil, fmt.Errorf("No base URL for server!")
+	}
+	if project == "" {
+		return nil, fmt.Errorf("No Azure DevOps project specified for server %s", server.URL)
+	}
+	headers := map[string]string{}
+	if userAuth != nil && !userAuth.IsInvalid() {
+		// personal access tokens use basic authentication with any user name
+		token := base64.StdEncoding.EncodeToString([]byte(userAuth.Username + ":" + userAuth.ApiToken))
+		headers["Authorization"] = "Basic " + token
+	} else if batchMode {
+		log.Warnf("No personal access token found for Azure DevOps server %s so using anonymous access\n", server.URL)
+	}
+	return &AzureBoardsService{
+		Server:   server,
+		UserAuth: userAuth,
+		Project:  project,
+		client:   newRestClient(headers),
+	}, nil
+}
+
+func (i *AzureBoardsService) GetIssue(key string) (*gits.GitIssue, error) {
+	id, err := azureBoardsWorkItemID(key)
+	if err != nil {
+		return nil, err
+	}
+	workItem := &azureBoardsWorkItem{}
+	err = i.client.do("GET", i.apiURL(true, "wit/workitems", id), nil, workItem)
+	if err != nil {
+		return nil, err
+	}
+	return i.toGitIssue(workItem), nil
+}
+
+func (i *AzureBoardsService) SearchIssues(query string) ([]*gits.GitIssue, error) {
+	wiql := "SELECT [System.Id] FROM WorkItems WHERE [System.TeamProject] = @project AND [System.State] NOT IN (" +
+		wiqlStrings(azureBoardsClosedStates) + ")"
+	if query != "" {
+		wiql += " AND [System.Title] CONTAINS " + wiqlString(query)
+	}
+	return i.queryWorkItems(wiql)
+}
+
+func (i *AzureBoardsService) SearchIssuesClosedSince(t time.Time) ([]*gits.GitIssue, error) {
+	wiql := "SELECT [System.Id] FROM WorkItems WHERE [System.TeamProject] = @project AND [Microsoft.VSTS.Common.ClosedDate] >= " +
+		wiqlString(t.Format("2006-01-02"))
+	return i.queryWorkItems(wiql)
+}
+
+func (i *AzureBoardsService) queryWorkItems(wiql string) ([]*gits.GitIssue, error) {
+	result := &azureBoardsQueryResult{}
+	body := map[string]string{
+		"query": wiql,
+	}
+	err := i.client.do("POST", i.apiURL(true, "wit/wiql"), body, result)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*gits.GitIssue{}
+	ids := []string{}
+	for _, w := range result.WorkItems {
+		ids = append(ids, strconv.Itoa(w.ID))
+	}
+	for len(ids) > 0 {
+		batch := ids
+		if len(batch) > azureBoardsMaxWorkItems {
+			batch = batch[0:azureBoardsMaxWorkItems]
+		}
+		ids = ids[len(batch):]
+
+		workItems := &azureBoardsWorkItems{}
+		err = i.client.do("GET", i.apiURL(false, "wit/workitems")+"&ids="+strings.Join(batch, ","), nil, workItems)
+		if err != nil {
+			return answer, err
+		}
+		for _, w := range workItems.Value {
+			answer = append(answer, i.toGitIssue(w))
+		}
+	}
+	return answer, nil
+}
+
+func (i *AzureBoardsService) CreateIssue(issue *gits.GitIssue) (*gits.GitIssue, error) {
+	patches := []azureBoardsPatch{
+		{
+			Op:    "add",
+			Path:  "/fields/System.Title",
+			Value: issue.Title,
+		},
+		{
+			Op:    "add",
+			Path:  "/fields/System.Description",
+			Value: issue.Body,
+		},
+	}
+	labels := []string{}
+	for _, label := range issue.Labels {
+		labels = append(labels, label.Name)
+	}
+	if len(labels) > 0 {
+		patches = append(patches, azureBoardsPatch{
+			Op:    "add",
+			Path:  "/fields/System.Tags",
+			Value: strings.Join(labels, "; "),
+		})
+	}
+	workItem := &azureBoardsWorkItem{}
+	err := i.client.doWithContentType("POST", i.apiURL(true, "wit/workitems", "$"+AzureBoardsWorkItemType), azureBoardsPatchContentType, patches, workItem)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create work item in Azure DevOps project %s: %s", i.Project, err)
+	}
+	return i.toGitIssue(workItem), nil
+}
+
+// CreateIssueComment adds the comment to the discussion of the work item
+func (i *AzureBoardsService) CreateIssueComment(key string, comment string) error {
+	id, err := azureBoardsWorkItemID(key)
+	if err != nil {
+		return err
+	}
+	patches := []azureBoardsPatch{
+		{
+			Op:    "add",
+			Path:  "/fields/System.History",
+			Value: comment,
+		},
+	}
+	return i.client.doWithContentType("PATCH", i.apiURL(true, "wit/workitems", id), azureBoardsPatchContentType, patches, nil)
+}
+
+func (i *AzureBoardsService) TransitionIssue(key string, status string) error {
+	id, err := azureBoardsWorkItemID(key)
+	if err != nil {
+		return err
+	}
+	patches := []azureBoardsPatch{
+		{
+			Op:    "add",
+			Path:  "/fields/System.State",
+			Value: status,
+		},
+	}
+	return i.client.doWithContentType("PATCH", i.apiURL(true, "wit/workitems", id), azureBoardsPatchContentType, patches, nil)
+}
+
+func (i *AzureBoardsService) SetIssueFixVersion(key string, version string) error {
+	return notSupported(AzureBoards, "setting the fix version of issues")
+}
+
+func (i *AzureBoardsService) IssueURL(key string) string {
+	id, err := azureBoardsWorkItemID(key)
+	if err != nil {
+		return ""
+	}
+	return util.UrlJoin(i.Server.URL, url.PathEscape(i.Project), "_workitems/edit", id)
+}
+
+func (i *AzureBoardsService) HomeURL() string {
+	return util.UrlJoin(i.Server.URL, url.PathEscape(i.Project), "_workitems")
+}
+
+// apiURL returns the URL of the REST API with the given path which is scoped to the project if required
+func (i *AzureBoardsService) apiURL(project bool, paths ...string) string {
+	prefix := []string{i.Server.URL}
+	if project {
+		prefix = append(prefix, url.PathEscape(i.Project))
+	}
+	prefix = append(prefix, "_apis")
+	return util.UrlJoin(append(prefix, paths...)...) + "?api-version=" + azureBoardsAPIVersion
+}
+
+func (i *AzureBoardsService) toGitIssue(workItem *azureBoardsWorkItem) *gits.GitIssue {
+	fields := &workItem.Fields
+	key := strconv.Itoa(workItem.ID)
+	number := workItem.ID
+	state := "open"
+	if fields.ClosedDate != nil || util.StringArrayIndex(azureBoardsClosedStates, fields.State) >= 0 {
+		state = "closed"
+	}
+	answer := &gits.GitIssue{
+		URL:       i.IssueURL(key),
+		Number:    &number,
+		Key:       key,
+		Title:     fields.Title,
+		Body:      fields.Description,
+		State:     &state,
+		CreatedAt: fields.CreatedDate,
+		UpdatedAt: fields.ChangedDate,
+		ClosedAt:  fields.ClosedDate,
+		User:      azureBoardsIdentityToGitUser(fields.CreatedBy),
+		ClosedBy:  azureBoardsIdentityToGitUser(fields.ClosedBy),
+	}
+	for _, tag := range strings.Split(fields.Tags, ";") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			answer.Labels = append(answer.Labels, gits.GitLabel{Name: tag})
+		}
+	}
+	assignee := azureBoardsIdentityToGitUser(fields.AssignedTo)
+	if assignee != nil {
+		answer.Assignees = []gits.GitUser{*assignee}
+	}
+	return answer
+}
+
+func azureBoardsIdentityToGitUser(identity *azureBoardsIdentity) *gits.GitUser {
+	if identity == nil {
+		return nil
+	}
+	answer := &gits.GitUser{
+		URL:       identity.URL,
+		Login:     identity.UniqueName,
+		Name:      identity.DisplayName,
+		AvatarURL: identity.ImageURL,
+	}
+	if strings.Contains(identity.UniqueName, "@") {
+		answer.Email = identity.UniqueName
+	}
+	return answer
+}
+
+// azureBoardsWorkItemID returns the numeric ID of a work item from a key such as "AB#123" or "#123"
+func azureBoardsWorkItemID(key string) (string, error) {
+	id := strings.TrimPrefix(strings.TrimPrefix(key, "AB"), "#")
+	_, err := issueKeyToNumber(id)
+	return id, err
+}
+
+// wiqlString quotes the text as a WIQL string literal
+func wiqlString(text string) string {
+	return "'" + strings.Replace(text, "'", "''", -1) + "'"
+}
+
+func wiqlStrings(values []string) string {
+	answer := []string{}
+	for _, v := range values {
+		answer = append(answer, wiqlString(v))
+	}
+	return strings.Join(answer, ", ")
+}
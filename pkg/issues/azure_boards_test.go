@@ -0,0 +1,95 @@
+package issues_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/issues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAzureBoardsIssueProvider(t *testing.T) {
+	t.Parallel()
+	patches := [][]map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Basic "+base64.StdEncoding.EncodeToString([]byte("bot:mytoken")), r.Header.Get("Authorization"))
+		assert.Equal(t, "5.0", r.URL.Query().Get("api-version"))
+		switch r.Method + " " + r.URL.EscapedPath() {
+		case "GET /myorg/My%20Project/_apis/wit/workitems/12":
+			fmt.Fprint(w, `{"id":12,"fields":{"System.Title":"it broke","System.Description":"details","System.State":"Closed","System.Tags":"bug; ui",
+				"System.CreatedDate":"2018-10-01T10:00:00Z","Microsoft.VSTS.Common.ClosedDate":"2018-10-02T10:00:00Z",
+				"System.CreatedBy":{"displayName":"Jane","uniqueName":"jane@example.com"},"System.AssignedTo":{"displayName":"Bob","uniqueName":"bob@example.com"}}}`)
+		case "POST /myorg/My%20Project/_apis/wit/wiql":
+			body := map[string]string{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Contains(t, body["query"], "[System.Title] CONTAINS 'it''s broken'")
+			fmt.Fprint(w, `{"workItems":[{"id":13},{"id":14}]}`)
+		case "GET /myorg/_apis/wit/workitems":
+			assert.Equal(t, "13,14", r.URL.Query().Get("ids"))
+			fmt.Fprint(w, `{"value":[{"id":13,"fields":{"System.Title":"it's broken","System.State":"New"}},{"id":14,"fields":{"System.State":"Active"}}]}`)
+		case "POST /myorg/My%20Project/_apis/wit/workitems/$Bug":
+			assert.Equal(t, "application/json-patch+json", r.Header.Get("Content-Type"))
+			fmt.Fprint(w, `{"id":15,"fields":{"System.Title":"new issue","System.State":"New"}}`)
+		case "PATCH /myorg/My%20Project/_apis/wit/workitems/12":
+			assert.Equal(t, "application/json-patch+json", r.Header.Get("Content-Type"))
+			patch := []map[string]string{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&patch))
+			patches = append(patches, patch)
+			fmt.Fprint(w, `{"id":12}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := issues.CreateIssueProvider(issues.AzureBoards, &auth.AuthServer{URL: server.URL + "/myorg"}, &auth.UserAuth{Username: "bot", ApiToken: "mytoken"}, "My Project", true, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, issues.AzureBoards, issues.GetIssueProvider(provider))
+	assert.Equal(t, server.URL+"/myorg/My%20Project/_workitems/edit/12", provider.IssueURL("AB#12"))
+
+	issue, err := provider.GetIssue("AB#12")
+	assert.NoError(t, err)
+	if assert.NotNil(t, issue) {
+		assert.Equal(t, "12", issue.Key)
+		assert.Equal(t, "it broke", issue.Title)
+		assert.Equal(t, "closed", *issue.State)
+		assert.Equal(t, time.Date(2018, 10, 2, 10, 0, 0, 0, time.UTC), issue.ClosedAt.UTC())
+		assert.Equal(t, []gits.GitLabel{{Name: "bug"}, {Name: "ui"}}, issue.Labels)
+		assert.Equal(t, "jane@example.com", issue.User.Email)
+		if assert.Len(t, issue.Assignees, 1) {
+			assert.Equal(t, "Bob", issue.Assignees[0].Name)
+		}
+	}
+
+	open, err := provider.SearchIssues("it's broken")
+	assert.NoError(t, err)
+	if assert.Len(t, open, 2) {
+		assert.Equal(t, "13", open[0].Key)
+		assert.Equal(t, "open", *open[1].State)
+	}
+
+	created, err := provider.CreateIssue(&gits.GitIssue{Title: "new issue"})
+	assert.NoError(t, err)
+	assert.Equal(t, "15", created.Key)
+
+	assert.NoError(t, provider.CreateIssueComment("#12", "deployed"))
+	if assert.Len(t, patches, 1) {
+		assert.Equal(t, []map[string]string{{"op": "add", "path": "/fields/System.History", "value": "deployed"}}, patches[0])
+	}
+
+	assert.NoError(t, provider.TransitionIssue("AB#12", "Resolved"))
+	if assert.Len(t, patches, 2) {
+		assert.Equal(t, []map[string]string{{"op": "add", "path": "/fields/System.State", "value": "Resolved"}}, patches[1])
+	}
+	assert.Error(t, provider.SetIssueFixVersion("AB#12", "1.0.3"), "fix versions are not supported")
+
+	_, err = provider.GetIssue("PROJ-12")
+	assert.Error(t, err, "work item keys must be numbers")
+}
@@ -1,12 +1,15 @@
 package issues
 
 const (
-	Bugzilla = "bugzilla"
-	Jira     = "jira"
-	Trello   = "trello"
-	Git      = "git"
+	Bugzilla    = "bugzilla"
+	Jira        = "jira"
+	Trello      = "trello"
+	Git         = "git"
+	GitLab      = "gitlab"
+	YouTrack    = "youtrack"
+	AzureBoards = "azureboards"
 )
 
 var (
-	IssueTrackerKinds = []string{Bugzilla, Jira, Trello}
+	IssueTrackerKinds = []string{AzureBoards, Bugzilla, GitLab, Jira, Trello, YouTrack}
 )
@@ -73,6 +73,14 @@ func (i *GitIssueProvider) CreateIssueComment(key string, comment string) error
 	return i.GitProvider.CreateIssueComment(i.Owner, i.Repository, n, comment)
 }
 
+func (i *GitIssueProvider) TransitionIssue(key string, status string) error {
+	return notSupported(Git, "transitioning issues")
+}
+
+func (i *GitIssueProvider) SetIssueFixVersion(key string, version string) error {
+	return notSupported(Git, "setting the fix version of issues")
+}
+
 func (i *GitIssueProvider) HomeURL() string {
 	return util.UrlJoin(i.GitProvider.ServerURL(), i.Owner, i.Repository)
 }
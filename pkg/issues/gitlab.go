@@ -0,0 +1,221 @@
+package issues
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// GitLabIssueService uses the issues of a GitLab project as the issue tracker which can differ from the
+// git repository of the source code
+type GitLabIssueService struct {
+	Server   *auth.AuthServer
+	UserAuth *auth.UserAuth
+	// Project is the path of the GitLab project such as "mygroup/myproject"
+	Project string
+
+	client *restClient
+}
+
+type gitlabUser struct {
+	Username  string `json:"username"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatar_url"`
+	WebURL    string `json:"web_url"`
+}
+
+type gitlabIssue struct {
+	IID         int          `json:"iid"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	State       string       `json:"state"`
+	WebURL      string       `json:"web_url"`
+	Labels      []string     `json:"labels"`
+	CreatedAt   *time.Time   `json:"created_at"`
+	UpdatedAt   *time.Time   `json:"updated_at"`
+	ClosedAt    *time.Time   `json:"closed_at"`
+	Author      *gitlabUser  `json:"author"`
+	ClosedBy    *gitlabUser  `json:"closed_by"`
+	Assignees   []gitlabUser `json:"assignees"`
+}
+
+// CreateGitLabIssueProvider creates an issue provider for the issues of a GitLab project
+func CreateGitLabIssueProvider(server *auth.AuthServer, userAuth *auth.UserAuth, project string, batchMode bool) (IssueProvider, error) {
+	if server.URL == "" {
+		return nil, fmt.Errorf("No base URL for server!")
+	}
+	if project == "" {
+		return nil, fmt.Errorf("No GitLab project specified for server %s", server.URL)
+	}
+	headers := map[string]string{}
+	if userAuth != nil && !userAuth.IsInvalid() {
+		headers["PRIVATE-TOKEN"] = userAuth.ApiToken
+	} else if batchMode {
+		log.Warnf("No API token found for GitLab server %s so using anonymous access\n", server.URL)
+	}
+	return &GitLabIssueService{
+		Server:   server,
+		UserAuth: userAuth,
+		Project:  project,
+		client:   newRestClient(headers),
+	}, nil
+}
+
+func (i *GitLabIssueService) GetIssue(key string) (*gits.GitIssue, error) {
+	issue := &gitlabIssue{}
+	err := i.client.do("GET", i.projectURL("issues", gitLabIssueID(key)), nil, issue)
+	if err != nil {
+		return nil, err
+	}
+	return i.toGitIssue(issue), nil
+}
+
+func (i *GitLabIssueService) SearchIssues(query string) ([]*gits.GitIssue, error) {
+	params := url.Values{}
+	params.Set("state", "opened")
+	if query != "" {
+		params.Set("search", query)
+	}
+	return i.listIssues(params, time.Time{})
+}
+
+func (i *GitLabIssueService) SearchIssuesClosedSince(t time.Time) ([]*gits.GitIssue, error) {
+	params := url.Values{}
+	params.Set("state", "closed")
+	params.Set("updated_after", t.Format(time.RFC3339))
+	return i.listIssues(params, t)
+}
+
+func (i *GitLabIssueService) listIssues(params url.Values, closedSince time.Time) ([]*gits.GitIssue, error) {
+	params.Set("per_page", "100")
+	results := []*gitlabIssue{}
+	err := i.client.do("GET", i.projectURL("issues")+"?"+params.Encode(), nil, &results)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*gits.GitIssue{}
+	for _, issue := range results {
+		if !closedSince.IsZero() && (issue.ClosedAt == nil || issue.ClosedAt.Before(closedSince)) {
+			continue
+		}
+		answer = append(answer, i.toGitIssue(issue))
+	}
+	return answer, nil
+}
+
+func (i *GitLabIssueService) CreateIssue(issue *gits.GitIssue) (*gits.GitIssue, error) {
+	labels := []string{}
+	for _, label := range issue.Labels {
+		labels = append(labels, label.Name)
+	}
+	body := map[string]string{
+		"title":       issue.Title,
+		"description": issue.Body,
+	}
+	if len(labels) > 0 {
+		body["labels"] = strings.Join(labels, ",")
+	}
+	created := &gitlabIssue{}
+	err := i.client.do("POST", i.projectURL("issues"), body, created)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create issue in GitLab project %s: %s", i.Project, err)
+	}
+	return i.toGitIssue(created), nil
+}
+
+func (i *GitLabIssueService) CreateIssueComment(key string, comment string) error {
+	body := map[string]string{
+		"body": comment,
+	}
+	return i.client.do("POST", i.projectURL("issues", gitLabIssueID(key), "notes"), body, nil)
+}
+
+// TransitionIssue closes or reopens the issue as GitLab issues only have the opened and closed states
+func (i *GitLabIssueService) TransitionIssue(key string, status string) error {
+	event := ""
+	switch strings.ToLower(status) {
+	case "closed", "close":
+		event = "close"
+	case "opened", "open", "reopen":
+		event = "reopen"
+	default:
+		return fmt.Errorf("GitLab issues cannot be moved to status %s as they can only be opened or closed", status)
+	}
+	body := map[string]string{
+		"state_event": event,
+	}
+	return i.client.do("PUT", i.projectURL("issues", gitLabIssueID(key)), body, nil)
+}
+
+func (i *GitLabIssueService) SetIssueFixVersion(key string, version string) error {
+	return notSupported(GitLab, "setting the fix version of issues")
+}
+
+func (i *GitLabIssueService) IssueURL(key string) string {
+	return util.UrlJoin(i.Server.URL, i.Project, "issues", gitLabIssueID(key))
+}
+
+func (i *GitLabIssueService) HomeURL() string {
+	return util.UrlJoin(i.Server.URL, i.Project, "issues")
+}
+
+func (i *GitLabIssueService) projectURL(paths ...string) string {
+	return util.UrlJoin(append([]string{i.Server.URL, "api/v4/projects", url.PathEscape(i.Project)}, paths...)...)
+}
+
+func (i *GitLabIssueService) toGitIssue(issue *gitlabIssue) *gits.GitIssue {
+	key := fmt.Sprintf("%d", issue.IID)
+	number := issue.IID
+	state := "open"
+	if issue.State == "closed" {
+		state = "closed"
+	}
+	answer := &gits.GitIssue{
+		URL:       issue.WebURL,
+		Number:    &number,
+		Key:       key,
+		Title:     issue.Title,
+		Body:      issue.Description,
+		State:     &state,
+		CreatedAt: issue.CreatedAt,
+		UpdatedAt: issue.UpdatedAt,
+		ClosedAt:  issue.ClosedAt,
+		User:      gitlabUserToGitUser(issue.Author),
+		ClosedBy:  gitlabUserToGitUser(issue.ClosedBy),
+	}
+	if answer.URL == "" {
+		answer.URL = i.IssueURL(key)
+	}
+	for _, label := range issue.Labels {
+		answer.Labels = append(answer.Labels, gits.GitLabel{Name: label})
+	}
+	for j := range issue.Assignees {
+		answer.Assignees = append(answer.Assignees, *gitlabUserToGitUser(&issue.Assignees[j]))
+	}
+	return answer
+}
+
+func gitlabUserToGitUser(user *gitlabUser) *gits.GitUser {
+	if user == nil {
+		return nil
+	}
+	return &gits.GitUser{
+		URL:       user.WebURL,
+		Login:     user.Username,
+		Name:      user.Name,
+		Email:     user.Email,
+		AvatarURL: user.AvatarURL,
+	}
+}
+
+// gitLabIssueID returns the internal ID of the GitLab issue from a key such as "#123"
+func gitLabIssueID(key string) string {
+	return strings.TrimPrefix(key, "#")
+}
@@ -0,0 +1,102 @@
+package issues_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/issues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitLabIssueProvider(t *testing.T) {
+	t.Parallel()
+	comments := []string{}
+	events := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "mytoken", r.Header.Get("PRIVATE-TOKEN"))
+		switch r.Method + " " + r.URL.EscapedPath() {
+		case "GET /api/v4/projects/mygroup%2Fmyproject/issues/12":
+			fmt.Fprint(w, `{"iid":12,"title":"it broke","description":"details","state":"closed","web_url":"https://gitlab.example.com/mygroup/myproject/issues/12",
+				"labels":["bug"],"created_at":"2018-10-01T10:00:00Z","closed_at":"2018-10-02T10:00:00Z",
+				"author":{"username":"jane","name":"Jane"},"closed_by":{"username":"bob","name":"Bob"},"assignees":[{"username":"bob","name":"Bob"}]}`)
+		case "GET /api/v4/projects/mygroup%2Fmyproject/issues":
+			if r.URL.Query().Get("state") == "closed" {
+				assert.Equal(t, "2018-10-02T00:00:00Z", r.URL.Query().Get("updated_after"))
+				fmt.Fprint(w, `[{"iid":12,"state":"closed","closed_at":"2018-10-02T10:00:00Z"},{"iid":11,"state":"closed","closed_at":"2018-09-01T10:00:00Z"}]`)
+			} else {
+				assert.Equal(t, "opened", r.URL.Query().Get("state"))
+				assert.Equal(t, "broken", r.URL.Query().Get("search"))
+				fmt.Fprint(w, `[{"iid":13,"title":"broken again","state":"opened"}]`)
+			}
+		case "POST /api/v4/projects/mygroup%2Fmyproject/issues":
+			body := map[string]string{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "new issue", body["title"])
+			assert.Equal(t, "bug,ui", body["labels"])
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"iid":14,"title":"new issue","state":"opened"}`)
+		case "PUT /api/v4/projects/mygroup%2Fmyproject/issues/12":
+			body := map[string]string{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			events = append(events, body["state_event"])
+			fmt.Fprint(w, `{"iid":12}`)
+		case "POST /api/v4/projects/mygroup%2Fmyproject/issues/12/notes":
+			body := map[string]string{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			comments = append(comments, body["body"])
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id":1}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := issues.CreateIssueProvider(issues.GitLab, &auth.AuthServer{URL: server.URL}, &auth.UserAuth{Username: "bot", ApiToken: "mytoken"}, "mygroup/myproject", true, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, issues.GitLab, issues.GetIssueProvider(provider))
+	assert.Equal(t, server.URL+"/mygroup/myproject/issues/12", provider.IssueURL("#12"))
+
+	issue, err := provider.GetIssue("#12")
+	assert.NoError(t, err)
+	if assert.NotNil(t, issue) {
+		assert.Equal(t, "12", issue.Key)
+		assert.Equal(t, "it broke", issue.Title)
+		assert.Equal(t, "closed", *issue.State)
+		assert.Equal(t, "https://gitlab.example.com/mygroup/myproject/issues/12", issue.URL)
+		assert.Equal(t, []gits.GitLabel{{Name: "bug"}}, issue.Labels)
+		assert.Equal(t, "jane", issue.User.Login)
+		assert.Equal(t, "bob", issue.ClosedBy.Login)
+		assert.Len(t, issue.Assignees, 1)
+	}
+
+	open, err := provider.SearchIssues("broken")
+	assert.NoError(t, err)
+	if assert.Len(t, open, 1) {
+		assert.Equal(t, "open", *open[0].State)
+		assert.Equal(t, server.URL+"/mygroup/myproject/issues/13", open[0].URL)
+	}
+
+	closed, err := provider.SearchIssuesClosedSince(time.Date(2018, 10, 2, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	if assert.Len(t, closed, 1) {
+		assert.Equal(t, "12", closed[0].Key)
+	}
+
+	created, err := provider.CreateIssue(&gits.GitIssue{Title: "new issue", Labels: []gits.GitLabel{{Name: "bug"}, {Name: "ui"}}})
+	assert.NoError(t, err)
+	assert.Equal(t, "14", created.Key)
+
+	assert.NoError(t, provider.CreateIssueComment("12", "deployed"))
+	assert.Equal(t, []string{"deployed"}, comments)
+
+	assert.NoError(t, provider.TransitionIssue("12", "Closed"))
+	assert.Equal(t, []string{"close"}, events)
+	assert.Error(t, provider.TransitionIssue("12", "In QA"), "GitLab issues can only be opened or closed")
+}
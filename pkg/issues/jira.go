@@ -20,6 +20,32 @@ type JiraService struct {
 	UserAuth   *auth.UserAuth
 	Project    string
 	Git        gits.Gitter
+
+	// the fix versions which are known to exist
+	versions map[string]bool
+}
+
+type jiraStatusIssue struct {
+	Fields struct {
+		Status struct {
+			Name string `json:"name"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+type jiraTransitions struct {
+	Transitions []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		To   struct {
+			Name string `json:"name"`
+		} `json:"to"`
+	} `json:"transitions"`
+}
+
+type jiraVersion struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
 }
 
 func CreateJiraIssueProvider(server *auth.AuthServer, userAuth *auth.UserAuth, project string, batchMode bool, git gits.Gitter) (IssueProvider, error) {
@@ -108,7 +134,120 @@ func (i *JiraService) CreateIssue(issue *gits.GitIssue) (*gits.GitIssue, error)
 }
 
 func (i *JiraService) CreateIssueComment(key string, comment string) error {
-	return fmt.Errorf("TODO")
+	body := map[string]string{
+		"body": comment,
+	}
+	return i.jiraRequest("POST", "rest/api/2/issue/"+key+"/comment", body, nil)
+}
+
+// TransitionIssue moves the issue to the given status using the first available transition of the workflow which
+// leads to that status
+func (i *JiraService) TransitionIssue(key string, status string) error {
+	issue := &jiraStatusIssue{}
+	err := i.jiraRequest("GET", "rest/api/2/issue/"+key+"?fields=status", nil, issue)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(issue.Fields.Status.Name, status) {
+		return nil
+	}
+	transitions := &jiraTransitions{}
+	err = i.jiraRequest("GET", "rest/api/2/issue/"+key+"/transitions", nil, transitions)
+	if err != nil {
+		return err
+	}
+	statuses := []string{}
+	for _, t := range transitions.Transitions {
+		if strings.EqualFold(t.To.Name, status) || strings.EqualFold(t.Name, status) {
+			body := map[string]interface{}{
+				"transition": map[string]string{
+					"id": t.ID,
+				},
+			}
+			return i.jiraRequest("POST", "rest/api/2/issue/"+key+"/transitions", body, nil)
+		}
+		statuses = append(statuses, t.To.Name)
+	}
+	return fmt.Errorf("JIRA issue %s in status %s cannot be moved to status %s. Available statuses: %s",
+		key, issue.Fields.Status.Name, status, strings.Join(statuses, ", "))
+}
+
+// SetIssueFixVersion adds the version to the fix versions of the issue creating the version in the project of the
+// issue if it does not exist
+func (i *JiraService) SetIssueFixVersion(key string, version string) error {
+	err := i.getOrCreateVersion(jiraIssueProject(key, i.Project), version)
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"update": map[string]interface{}{
+			"fixVersions": []interface{}{
+				map[string]interface{}{
+					"add": map[string]string{
+						"name": version,
+					},
+				},
+			},
+		},
+	}
+	return i.jiraRequest("PUT", "rest/api/2/issue/"+key, body, nil)
+}
+
+func (i *JiraService) getOrCreateVersion(project string, version string) error {
+	cacheKey := project + "/" + version
+	if i.versions[cacheKey] {
+		return nil
+	}
+	versions := []jiraVersion{}
+	err := i.jiraRequest("GET", "rest/api/2/project/"+project+"/versions", nil, &versions)
+	if err != nil {
+		return fmt.Errorf("Could not find the versions of project %s: %s", project, err)
+	}
+	found := false
+	for _, v := range versions {
+		if v.Name == version {
+			found = true
+			break
+		}
+	}
+	if !found {
+		log.Infof("Creating version %s in JIRA project %s\n", util.ColorInfo(version), util.ColorInfo(project))
+		body := map[string]interface{}{
+			"name":    version,
+			"project": project,
+		}
+		err = i.jiraRequest("POST", "rest/api/2/version", body, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to create version %s in project %s: %s", version, project, err)
+		}
+	}
+	if i.versions == nil {
+		i.versions = map[string]bool{}
+	}
+	i.versions[cacheKey] = true
+	return nil
+}
+
+// jiraRequest invokes the JIRA REST API with the path relative to the server URL
+func (i *JiraService) jiraRequest(method string, path string, body interface{}, result interface{}) error {
+	req, err := i.JiraClient.NewRequest(method, path, body)
+	if err != nil {
+		return err
+	}
+	_, err = i.JiraClient.Do(req, result)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %s", method, path, err)
+	}
+	return nil
+}
+
+// jiraIssueProject returns the project of the issue from its key such as "PROJ-123"
+func jiraIssueProject(key string, defaultProject string) string {
+	idx := strings.LastIndex(key, "-")
+	if idx > 0 {
+		return key[0:idx]
+	}
+	return defaultProject
 }
 
 func (i *JiraService) IssueURL(key string) string {
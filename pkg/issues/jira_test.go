@@ -0,0 +1,86 @@
+package issues_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/issues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJiraTransitionAndFixVersion(t *testing.T) {
+	t.Parallel()
+	requests := []string{}
+	bodies := map[string]map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := r.Method + " " + r.URL.Path
+		requests = append(requests, request)
+		if r.Method != "GET" {
+			body := map[string]interface{}{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			bodies[request] = body
+		}
+		switch request {
+		case "GET /rest/api/2/issue/PROJ-1":
+			fmt.Fprint(w, `{"key":"PROJ-1","fields":{"status":{"name":"In Progress"}}}`)
+		case "GET /rest/api/2/issue/PROJ-2":
+			fmt.Fprint(w, `{"key":"PROJ-2","fields":{"status":{"name":"In QA"}}}`)
+		case "GET /rest/api/2/issue/PROJ-1/transitions":
+			fmt.Fprint(w, `{"transitions":[{"id":"11","name":"Start review","to":{"name":"In Review"}},{"id":"21","name":"Ready for QA","to":{"name":"In QA"}}]}`)
+		case "POST /rest/api/2/issue/PROJ-1/transitions", "PUT /rest/api/2/issue/PROJ-1":
+			w.WriteHeader(http.StatusNoContent)
+		case "POST /rest/api/2/issue/PROJ-1/comment":
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id":"100"}`)
+		case "GET /rest/api/2/project/PROJ/versions":
+			fmt.Fprint(w, `[{"id":"1","name":"1.0.0"}]`)
+		case "POST /rest/api/2/version":
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id":"2","name":"1.0.1"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := issues.CreateIssueProvider(issues.Jira, &auth.AuthServer{URL: server.URL}, &auth.UserAuth{Username: "bot", ApiToken: "mytoken"}, "PROJ", true, nil)
+	assert.NoError(t, err)
+
+	err = provider.TransitionIssue("PROJ-1", "in qa")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"transition": map[string]interface{}{"id": "21"}}, bodies["POST /rest/api/2/issue/PROJ-1/transitions"])
+
+	requests = []string{}
+	err = provider.TransitionIssue("PROJ-2", "In QA")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GET /rest/api/2/issue/PROJ-2"}, requests, "no transition for an issue already in the status")
+
+	err = provider.TransitionIssue("PROJ-1", "Done")
+	assert.Error(t, err, "no transition to the status")
+
+	err = provider.SetIssueFixVersion("PROJ-1", "1.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "1.0.1", "project": "PROJ"}, bodies["POST /rest/api/2/version"])
+	assert.Equal(t, map[string]interface{}{
+		"update": map[string]interface{}{
+			"fixVersions": []interface{}{
+				map[string]interface{}{
+					"add": map[string]interface{}{"name": "1.0.1"},
+				},
+			},
+		},
+	}, bodies["PUT /rest/api/2/issue/PROJ-1"])
+
+	requests = []string{}
+	err = provider.SetIssueFixVersion("PROJ-1", "1.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PUT /rest/api/2/issue/PROJ-1"}, requests, "the version is only created once")
+
+	err = provider.CreateIssueComment("PROJ-1", "deployed")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"body": "deployed"}, bodies["POST /rest/api/2/issue/PROJ-1/comment"])
+}
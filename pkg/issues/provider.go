@@ -6,6 +6,7 @@ import (
 
 	"github.com/jenkins-x/jx/pkg/auth"
 	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/util"
 )
 
 type IssueProvider interface {
@@ -24,6 +25,12 @@ type IssueProvider interface {
 	// Creates a comment on the given issue
 	CreateIssueComment(key string, comment string) error
 
+	// TransitionIssue moves the issue to the given status of the workflow of the issue tracker
+	TransitionIssue(key string, status string) error
+
+	// SetIssueFixVersion adds the version to the fix versions of the issue creating the version if required
+	SetIssueFixVersion(key string, version string) error
+
 	// IssueURL returns the URL of the given issue for this project
 	IssueURL(key string) string
 
@@ -35,6 +42,12 @@ func CreateIssueProvider(kind string, server *auth.AuthServer, userAuth *auth.Us
 	switch kind {
 	case Jira:
 		return CreateJiraIssueProvider(server, userAuth, project, batchMode, git)
+	case GitLab:
+		return CreateGitLabIssueProvider(server, userAuth, project, batchMode)
+	case YouTrack:
+		return CreateYouTrackIssueProvider(server, userAuth, project, batchMode)
+	case AzureBoards:
+		return CreateAzureBoardsIssueProvider(server, userAuth, project, batchMode)
 	default:
 		return nil, fmt.Errorf("Unsupported issue provider kind: %s", kind)
 	}
@@ -45,16 +58,34 @@ func ProviderAccessTokenURL(kind string, url string) string {
 	case Jira:
 		// TODO handle on premise servers too by detecting the URL is at atlassian.com
 		return "https://id.atlassian.com/manage/api-tokens"
+	case GitLab:
+		return util.UrlJoin(url, "/profile/personal_access_tokens")
+	case YouTrack:
+		return util.UrlJoin(url, "/users/me?tab=account-security")
+	case AzureBoards:
+		return util.UrlJoin(url, "/_usersSettings/tokens")
 	default:
 		return ""
 	}
 }
 
+// notSupported returns the error for an operation the kind of issue tracker does not support
+func notSupported(kind string, operation string) error {
+	return fmt.Errorf("%s is not supported by %s issue trackers", operation, kind)
+}
+
 // GetIssueProvider returns the kind of issue provider
 func GetIssueProvider(tracker IssueProvider) string {
-	_, ok := tracker.(*JiraService)
-	if ok {
+	switch tracker.(type) {
+	case *JiraService:
 		return Jira
+	case *GitLabIssueService:
+		return GitLab
+	case *YouTrackService:
+		return YouTrack
+	case *AzureBoardsService:
+		return AzureBoards
+	default:
+		return Git
 	}
-	return Git
 }
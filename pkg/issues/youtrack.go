@@ -0,0 +1,267 @@
+package issues
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	youTrackIssueFields = "idReadable,summary,description,created,updated,resolved," +
+		"reporter(login,fullName,email,avatarUrl),tags(name)," +
+		"customFields(name,value(name,login,fullName,email,avatarUrl))"
+
+	youTrackAssigneeField    = "Assignee"
+	youTrackStateField       = "State"
+	youTrackFixVersionsField = "Fix versions"
+)
+
+// YouTrackService uses a project in a YouTrack server as the issue tracker
+type YouTrackService struct {
+	Server   *auth.AuthServer
+	UserAuth *auth.UserAuth
+	// Project is the short name of the YouTrack project such as "PROJ"
+	Project string
+
+	client *restClient
+}
+
+type youTrackUser struct {
+	Login     string `json:"login"`
+	FullName  string `json:"fullName"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatarUrl"`
+}
+
+type youTrackCustomField struct {
+	Name  string          `json:"name"`
+	Value json.RawMessage `json:"value"`
+}
+
+type youTrackIssue struct {
+	ID           string                `json:"idReadable"`
+	Summary      string                `json:"summary"`
+	Description  string                `json:"description"`
+	Created      int64                 `json:"created"`
+	Updated      int64                 `json:"updated"`
+	Resolved     *int64                `json:"resolved"`
+	Reporter     *youTrackUser         `json:"reporter"`
+	Tags         []youTrackTag         `json:"tags"`
+	CustomFields []youTrackCustomField `json:"customFields"`
+}
+
+type youTrackTag struct {
+	Name string `json:"name"`
+}
+
+type youTrackProject struct {
+	ID        string `json:"id"`
+	ShortName string `json:"shortName"`
+}
+
+// CreateYouTrackIssueProvider creates an issue provider for a YouTrack project
+func CreateYouTrackIssueProvider(server *auth.AuthServer, userAuth *auth.UserAuth, project string, batchMode bool) (IssueProvider, error) {
+	if server.URL == "" {
+		return nil, fmt.Errorf("No base URL for server!")
+	}
+	if project == "" {
+		return nil, fmt.Errorf("No YouTrack project specified for server %s", server.URL)
+	}
+	headers := map[string]string{}
+	if userAuth != nil && !userAuth.IsInvalid() {
+		headers["Authorization"] = "Bearer " + userAuth.ApiToken
+	} else if batchMode {
+		log.Warnf("No permanent token found for YouTrack server %s so using anonymous access\n", server.URL)
+	}
+	return &YouTrackService{
+		Server:   server,
+		UserAuth: userAuth,
+		Project:  project,
+		client:   newRestClient(headers),
+	}, nil
+}
+
+func (i *YouTrackService) GetIssue(key string) (*gits.GitIssue, error) {
+	issue := &youTrackIssue{}
+	err := i.client.do("GET", i.apiURL("issues", i.issueID(key))+"?fields="+url.QueryEscape(youTrackIssueFields), nil, issue)
+	if err != nil {
+		return nil, err
+	}
+	return i.toGitIssue(issue), nil
+}
+
+func (i *YouTrackService) SearchIssues(query string) ([]*gits.GitIssue, error) {
+	q := "project: " + i.Project + " #Unresolved"
+	if query != "" {
+		q += " " + query
+	}
+	return i.searchIssues(q)
+}
+
+func (i *YouTrackService) SearchIssuesClosedSince(t time.Time) ([]*gits.GitIssue, error) {
+	return i.searchIssues(fmt.Sprintf("project: %s resolved date: %s .. Today", i.Project, t.Format("2006-01-02")))
+}
+
+func (i *YouTrackService) searchIssues(query string) ([]*gits.GitIssue, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("fields", youTrackIssueFields)
+	results := []*youTrackIssue{}
+	err := i.client.do("GET", i.apiURL("issues")+"?"+params.Encode(), nil, &results)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*gits.GitIssue{}
+	for _, issue := range results {
+		answer = append(answer, i.toGitIssue(issue))
+	}
+	return answer, nil
+}
+
+func (i *YouTrackService) CreateIssue(issue *gits.GitIssue) (*gits.GitIssue, error) {
+	params := url.Values{}
+	params.Set("query", i.Project)
+	params.Set("fields", "id,shortName")
+	projects := []*youTrackProject{}
+	err := i.client.do("GET", i.apiURL("admin/projects")+"?"+params.Encode(), nil, &projects)
+	if err != nil {
+		return nil, fmt.Errorf("Could not find project %s: %s", i.Project, err)
+	}
+	projectID := ""
+	for _, p := range projects {
+		if p.ShortName == i.Project {
+			projectID = p.ID
+		}
+	}
+	if projectID == "" {
+		return nil, fmt.Errorf("Could not find project %s in YouTrack server %s", i.Project, i.Server.URL)
+	}
+	body := map[string]interface{}{
+		"project": map[string]string{
+			"id": projectID,
+		},
+		"summary":     issue.Title,
+		"description": issue.Body,
+	}
+	created := &youTrackIssue{}
+	err = i.client.do("POST", i.apiURL("issues")+"?fields="+url.QueryEscape(youTrackIssueFields), body, created)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create issue in YouTrack project %s: %s", i.Project, err)
+	}
+	return i.toGitIssue(created), nil
+}
+
+func (i *YouTrackService) CreateIssueComment(key string, comment string) error {
+	body := map[string]string{
+		"text": comment,
+	}
+	return i.client.do("POST", i.apiURL("issues", i.issueID(key), "comments"), body, nil)
+}
+
+func (i *YouTrackService) TransitionIssue(key string, status string) error {
+	return i.applyCommand(key, youTrackStateField+" "+status)
+}
+
+// SetIssueFixVersion adds the version to the fix versions of the issue which must already exist in the project
+func (i *YouTrackService) SetIssueFixVersion(key string, version string) error {
+	return i.applyCommand(key, "add "+youTrackFixVersionsField+" "+version)
+}
+
+// applyCommand applies a YouTrack command such as "State Fixed" to the issue
+func (i *YouTrackService) applyCommand(key string, command string) error {
+	body := map[string]interface{}{
+		"query": command,
+		"issues": []map[string]string{
+			{
+				"idReadable": i.issueID(key),
+			},
+		},
+	}
+	return i.client.do("POST", i.apiURL("commands"), body, nil)
+}
+
+func (i *YouTrackService) IssueURL(key string) string {
+	return util.UrlJoin(i.Server.URL, "issue", i.issueID(key))
+}
+
+func (i *YouTrackService) HomeURL() string {
+	return util.UrlJoin(i.Server.URL, "issues", i.Project)
+}
+
+func (i *YouTrackService) apiURL(paths ...string) string {
+	return util.UrlJoin(append([]string{i.Server.URL, "api"}, paths...)...)
+}
+
+// issueID returns the readable ID of the issue adding the project prefix if the key is just a number
+func (i *YouTrackService) issueID(key string) string {
+	key = strings.TrimPrefix(key, "#")
+	if _, err := issueKeyToNumber(key); err == nil {
+		return i.Project + "-" + key
+	}
+	return key
+}
+
+func (i *YouTrackService) toGitIssue(issue *youTrackIssue) *gits.GitIssue {
+	state := "open"
+	var closedAt *time.Time
+	if issue.Resolved != nil {
+		state = "closed"
+		closedAt = youTrackTime(*issue.Resolved)
+	}
+	answer := &gits.GitIssue{
+		URL:       i.IssueURL(issue.ID),
+		Key:       issue.ID,
+		Title:     issue.Summary,
+		Body:      issue.Description,
+		State:     &state,
+		CreatedAt: youTrackTime(issue.Created),
+		UpdatedAt: youTrackTime(issue.Updated),
+		ClosedAt:  closedAt,
+		User:      youTrackUserToGitUser(issue.Reporter),
+	}
+	for _, tag := range issue.Tags {
+		answer.Labels = append(answer.Labels, gits.GitLabel{Name: tag.Name})
+	}
+	for _, field := range issue.CustomFields {
+		if field.Name != youTrackAssigneeField || len(field.Value) == 0 {
+			continue
+		}
+		users := []youTrackUser{}
+		user := youTrackUser{}
+		if json.Unmarshal(field.Value, &users) != nil && json.Unmarshal(field.Value, &user) == nil && user.Login != "" {
+			users = append(users, user)
+		}
+		for j := range users {
+			answer.Assignees = append(answer.Assignees, *youTrackUserToGitUser(&users[j]))
+		}
+	}
+	return answer
+}
+
+func youTrackUserToGitUser(user *youTrackUser) *gits.GitUser {
+	if user == nil {
+		return nil
+	}
+	return &gits.GitUser{
+		Login:     user.Login,
+		Name:      user.FullName,
+		Email:     user.Email,
+		AvatarURL: user.AvatarURL,
+	}
+}
+
+// youTrackTime converts a YouTrack timestamp in milliseconds to a time
+func youTrackTime(millis int64) *time.Time {
+	if millis == 0 {
+		return nil
+	}
+	t := time.Unix(0, millis*int64(time.Millisecond))
+	return &t
+}
@@ -0,0 +1,103 @@
+package issues_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/issues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYouTrackIssueProvider(t *testing.T) {
+	t.Parallel()
+	comments := []string{}
+	commands := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer perm:mytoken", r.Header.Get("Authorization"))
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/issues/PROJ-12":
+			assert.Contains(t, r.URL.Query().Get("fields"), "idReadable")
+			fmt.Fprint(w, `{"idReadable":"PROJ-12","summary":"it broke","description":"details","created":1538388000000,"resolved":1538474400000,
+				"reporter":{"login":"jane","fullName":"Jane"},"tags":[{"name":"bug"}],
+				"customFields":[{"name":"State","value":{"name":"Fixed"}},{"name":"Assignee","value":{"login":"bob","fullName":"Bob"}}]}`)
+		case "GET /api/issues":
+			query := r.URL.Query().Get("query")
+			if query == "project: PROJ resolved date: 2018-10-02 .. Today" {
+				fmt.Fprint(w, `[{"idReadable":"PROJ-12","resolved":1538474400000}]`)
+			} else {
+				assert.Equal(t, "project: PROJ #Unresolved broken", query)
+				fmt.Fprint(w, `[{"idReadable":"PROJ-13","summary":"broken again","customFields":[{"name":"Assignee","value":null}]}]`)
+			}
+		case "GET /api/admin/projects":
+			fmt.Fprint(w, `[{"id":"0-1","shortName":"PROJX"},{"id":"0-2","shortName":"PROJ"}]`)
+		case "POST /api/issues":
+			body := map[string]interface{}{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, map[string]interface{}{"id": "0-2"}, body["project"])
+			assert.Equal(t, "new issue", body["summary"])
+			fmt.Fprint(w, `{"idReadable":"PROJ-14","summary":"new issue"}`)
+		case "POST /api/commands":
+			body := map[string]interface{}{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, []interface{}{map[string]interface{}{"idReadable": "PROJ-12"}}, body["issues"])
+			commands = append(commands, body["query"].(string))
+			fmt.Fprint(w, `{}`)
+		case "POST /api/issues/PROJ-12/comments":
+			body := map[string]string{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			comments = append(comments, body["text"])
+			fmt.Fprint(w, `{"id":"4-1"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := issues.CreateIssueProvider(issues.YouTrack, &auth.AuthServer{URL: server.URL}, &auth.UserAuth{Username: "bot", ApiToken: "perm:mytoken"}, "PROJ", true, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, issues.YouTrack, issues.GetIssueProvider(provider))
+	assert.Equal(t, server.URL+"/issue/PROJ-12", provider.IssueURL("12"))
+
+	issue, err := provider.GetIssue("PROJ-12")
+	assert.NoError(t, err)
+	if assert.NotNil(t, issue) {
+		assert.Equal(t, "PROJ-12", issue.Key)
+		assert.Equal(t, "it broke", issue.Title)
+		assert.Equal(t, "closed", *issue.State)
+		assert.Equal(t, server.URL+"/issue/PROJ-12", issue.URL)
+		assert.Equal(t, time.Date(2018, 10, 2, 10, 0, 0, 0, time.UTC), issue.ClosedAt.UTC())
+		assert.Equal(t, []gits.GitLabel{{Name: "bug"}}, issue.Labels)
+		assert.Equal(t, "jane", issue.User.Login)
+		if assert.Len(t, issue.Assignees, 1) {
+			assert.Equal(t, "bob", issue.Assignees[0].Login)
+		}
+	}
+
+	open, err := provider.SearchIssues("broken")
+	assert.NoError(t, err)
+	if assert.Len(t, open, 1) {
+		assert.Equal(t, "open", *open[0].State)
+		assert.Empty(t, open[0].Assignees)
+	}
+
+	closed, err := provider.SearchIssuesClosedSince(time.Date(2018, 10, 2, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Len(t, closed, 1)
+
+	created, err := provider.CreateIssue(&gits.GitIssue{Title: "new issue"})
+	assert.NoError(t, err)
+	assert.Equal(t, "PROJ-14", created.Key)
+
+	assert.NoError(t, provider.CreateIssueComment("PROJ-12", "deployed"))
+	assert.Equal(t, []string{"deployed"}, comments)
+
+	assert.NoError(t, provider.TransitionIssue("12", "Verified"))
+	assert.NoError(t, provider.SetIssueFixVersion("PROJ-12", "1.0.3"))
+	assert.Equal(t, []string{"State Verified", "add Fix versions 1.0.3"}, commands)
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/jenkins-x/jx/pkg/workflow"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApproveOptions the options for the approve command
+type ApproveOptions struct {
+	CommonOptions
+
+	Build   string
+	Step    string
+	Message string
+	Reject  bool
+}
+
+var (
+	approve_long = templates.LongDesc(`
+		Approves a Workflow step which is waiting for approval so that the release can continue.
+
+		If the step has approvers the workflow controller creates a Role and RoleBinding which grant them the 'approve'
+		verb on the step. The API server then checks that the user authenticated by the current Kubernetes credentials
+		is allowed to approve the step so renaming the user of a kubeconfig context does not bypass the approvers.
+		The approval is recorded against the name of the user of the current Kubernetes context.
+
+		Steps can only be approved via this command. Approving a step via an '/approve' comment on a Pull Request is
+		not supported yet.
+
+`)
+
+	approve_example = templates.Examples(`
+		# Pick a workflow step which is waiting for approval and approve it
+		jx approve
+
+		# Approve the 'production-gate' step of build 3 of a pipeline
+		jx approve myorg/myapp/master --build 3 --step production-gate
+
+		# Reject a step so that the release fails
+		jx approve myorg/myapp/master --reject
+	`)
+)
+
+// NewCmdApprove creates the command
+func NewCmdApprove(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ApproveOptions{
+		CommonOptions: CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "approve [pipeline]",
+		Short:   "Approves a Workflow step which is waiting for approval",
+		Long:    approve_long,
+		Example: approve_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Build, "build", "b", "", "The build number of the pipeline to approve")
+	cmd.Flags().StringVarP(&options.Step, "step", "s", "", "The name of the workflow step to approve")
+	cmd.Flags().StringVarP(&options.Message, "message", "m", "", "The message to record with the approval")
+	cmd.Flags().BoolVarP(&options.Reject, "reject", "", false, "Rejects the step so that the workflow fails")
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *ApproveOptions) Run() error {
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	kubeClient, _, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	userName, err := o.GetClusterUserName()
+	if err != nil {
+		return errors.Wrap(err, "failed to find the user of the current Kubernetes context")
+	}
+	if userName == "" {
+		return fmt.Errorf("no user found for the current Kubernetes context")
+	}
+	pipelineName := ""
+	if len(o.Args) > 0 {
+		pipelineName = o.Args[0]
+	}
+
+	activities := jxClient.JenkinsV1().PipelineActivities(ns)
+	list, err := activities.List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list PipelineActivity resources in namespace %s", ns)
+	}
+
+	waitingMap := map[string]*v1.PipelineActivity{}
+	stepNames := map[string]string{}
+	for i := range list.Items {
+		activity := &list.Items[i]
+		spec := &activity.Spec
+		if pipelineName != "" && spec.Pipeline != pipelineName {
+			continue
+		}
+		if o.Build != "" && spec.Build != o.Build {
+			continue
+		}
+		for _, step := range spec.Steps {
+			w := step.Workflow
+			if w == nil || w.Status != v1.ActivityStatusTypeWaitingForApproval {
+				continue
+			}
+			if o.Step != "" && w.Name != o.Step {
+				continue
+			}
+			key := fmt.Sprintf("%s #%s %s", spec.Pipeline, spec.Build, w.Name)
+			waitingMap[key] = activity
+			stepNames[key] = w.Name
+		}
+	}
+	keys := []string{}
+	for k := range waitingMap {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	if len(keys) == 0 {
+		return fmt.Errorf("there are no workflow steps waiting for approval")
+	}
+	key := keys[0]
+	if len(keys) > 1 {
+		if o.BatchMode {
+			return fmt.Errorf("there are %d workflow steps waiting for approval. Please specify the pipeline, build and step: %s", len(keys), strings.Join(keys, ", "))
+		}
+		key, err = util.PickName(keys, "Which workflow step do you want to approve: ", o.In, o.Out, o.Err)
+		if err != nil {
+			return err
+		}
+	}
+	activity := waitingMap[key]
+	stepName := stepNames[key]
+
+	flow, err := workflow.GetWorkflow(activity.Spec.Workflow, jxClient, ns)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find Workflow %s", activity.Spec.Workflow)
+	}
+	step := workflow.FindWorkflowStep(flow, stepName)
+	if step == nil {
+		return fmt.Errorf("Workflow %s has no step called %s", flow.Name, stepName)
+	}
+	if workflow.HasApprovers(step) {
+		allowed, reason, err := kube.CanApproveWorkflowStep(kubeClient, ns, flow.Name, stepName)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			if reason != "" {
+				reason = ": " + reason
+			}
+			return fmt.Errorf("the current Kubernetes user is not allowed to approve step %s. The approvers are: %s%s", stepName, strings.Join(step.Approve.Approvers, ", "), reason)
+		}
+	}
+
+	status := v1.ActivityStatusTypeSucceeded
+	message := o.Message
+	if o.Reject {
+		status = v1.ActivityStatusTypeFailed
+		if message == "" {
+			message = "Rejected"
+		}
+	} else if message == "" {
+		message = "Approved"
+	}
+	w := workflow.FindWorkflowActivityStep(activity, stepName)
+	w.ApprovedBy = userName
+	workflow.CompleteWorkflowActivityStep(w, status, message)
+	_, err = activities.Update(activity)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update PipelineActivity %s", activity.Name)
+	}
+	log.Infof("%s step %s of %s\n", message, util.ColorInfo(stepName), util.ColorInfo(activity.Spec.Pipeline+" #"+activity.Spec.Build))
+	return nil
+}
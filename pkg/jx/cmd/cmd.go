@@ -43,6 +43,7 @@ func NewJXCommand(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 	installCommands := []*cobra.Command{
 		NewCmdInstall(f, in, out, err),
 		NewCmdUninstall(f, in, out, err),
+		NewCmdDiff(f, in, out, err),
 		NewCmdUpgrade(f, in, out, err),
 	}
 	installCommands = append(installCommands, findCommands("cluster", createCommands, deleteCommands)...)
@@ -70,6 +71,8 @@ func NewJXCommand(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 	environmentsCommands := []*cobra.Command{
 		NewCmdPreview(f, in, out, err),
 		NewCmdPromote(f, in, out, err),
+		NewCmdApprove(f, in, out, err),
+		NewCmdRollback(f, in, out, err),
 	}
 	environmentsCommands = append(environmentsCommands, findCommands("environment", createCommands, deleteCommands, editCommands, getCommands)...)
 
@@ -148,6 +151,7 @@ func NewJXCommand(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 			Message: "Jenkins X services:",
 			Commands: []*cobra.Command{
 				NewCmdController(f, in, out, err),
+				NewCmdRestore(f, in, out, err),
 				NewCmdGC(f, in, out, err),
 			},
 		},
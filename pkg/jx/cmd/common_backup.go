@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/kube"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	// backupResourceTeamSettings the resource name of the team settings of the dev environment in the backup repository
+	backupResourceTeamSettings = "teamsettings"
+)
+
+// backupResource the functions to backup and restore a kind of Jenkins X resource
+type backupResource struct {
+	// Resource the name of the resource used for the directory of the backup files
+	Resource string
+	Register func(o *CommonOptions) error
+	New      func() metav1.Object
+	List     func(jxClient versioned.Interface, ns string, lo metav1.ListOptions) (runtime.Object, error)
+	Watch    func(jxClient versioned.Interface, ns string, lo metav1.ListOptions) (watch.Interface, error)
+	Get      func(jxClient versioned.Interface, ns string, name string) (metav1.Object, error)
+	Create   func(jxClient versioned.Interface, ns string, obj metav1.Object) error
+	Update   func(jxClient versioned.Interface, ns string, obj metav1.Object) error
+}
+
+// backupResources the resources which are backed up in the order that they are restored
+var backupResources = []*backupResource{
+	{
+		Resource: "team",
+		Register: func(o *CommonOptions) error {
+			return o.registerTeamCRD()
+		},
+		New: func() metav1.Object {
+			return &v1.Team{}
+		},
+		List: func(jxClient versioned.Interface, ns string, lo metav1.ListOptions) (runtime.Object, error) {
+			return jxClient.JenkinsV1().Teams(ns).List(lo)
+		},
+		Watch: func(jxClient versioned.Interface, ns string, lo metav1.ListOptions) (watch.Interface, error) {
+			return jxClient.JenkinsV1().Teams(ns).Watch(lo)
+		},
+		Get: func(jxClient versioned.Interface, ns string, name string) (metav1.Object, error) {
+			return jxClient.JenkinsV1().Teams(ns).Get(name, metav1.GetOptions{})
+		},
+		Create: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+			_, err := jxClient.JenkinsV1().Teams(ns).Create(obj.(*v1.Team))
+			return err
+		},
+		Update: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+			_, err := jxClient.JenkinsV1().Teams(ns).Update(obj.(*v1.Team))
+			return err
+		},
+	},
+	{
+		Resource: "user",
+		Register: func(o *CommonOptions) error {
+			return o.registerUserCRD()
+		},
+		New: func() metav1.Object {
+			return &v1.User{}
+		},
+		List: func(jxClient versioned.Interface, ns string, lo metav1.ListOptions) (runtime.Object, error) {
+			return jxClient.JenkinsV1().Users(ns).List(lo)
+		},
+		Watch: func(jxClient versioned.Interface, ns string, lo metav1.ListOptions) (watch.Interface, error) {
+			return jxClient.JenkinsV1().Users(ns).Watch(lo)
+		},
+		Get: func(jxClient versioned.Interface, ns string, name string) (metav1.Object, error) {
+			return jxClient.JenkinsV1().Users(ns).Get(name, metav1.GetOptions{})
+		},
+		Create: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+			_, err := jxClient.JenkinsV1().Users(ns).Create(obj.(*v1.User))
+			return err
+		},
+		Update: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+			_, err := jxClient.JenkinsV1().Users(ns).Update(obj.(*v1.User))
+			return err
+		},
+	},
+	{
+		Resource: "environment",
+		Register: func(o *CommonOptions) error {
+			return o.registerEnvironmentCRD()
+		},
+		New: func() metav1.Object {
+			return &v1.Environment{}
+		},
+		List: func(jxClient versioned.Interface, ns string, lo metav1.ListOptions) (runtime.Object, error) {
+			return jxClient.JenkinsV1().Environments(ns).List(lo)
+		},
+		Watch: func(jxClient versioned.Interface, ns string, lo metav1.ListOptions) (watch.Interface, error) {
+			return jxClient.JenkinsV1().Environments(ns).Watch(lo)
+		},
+		Get: func(jxClient versioned.Interface, ns string, name string) (metav1.Object, error) {
+			return jxClient.JenkinsV1().Environments(ns).Get(name, metav1.GetOptions{})
+		},
+		Create: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+			_, err := jxClient.JenkinsV1().Environments(ns).Create(obj.(*v1.Environment))
+			return err
+		},
+		Update: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+			_, err := jxClient.JenkinsV1().Environments(ns).Update(obj.(*v1.Environment))
+			return err
+		},
+	},
+	{
+		Resource: "environmentrolebinding",
+		Register: func(o *CommonOptions) error {
+			return o.registerEnvironmentRoleBindingCRD()
+		},
+		New: func() metav1.Object {
+			return &v1.EnvironmentRoleBinding{}
+		},
+		List: func(jxClient versioned.Interface, ns string, lo metav1.ListOptions) (runtime.Object, error) {
+			return jxClient.JenkinsV1().EnvironmentRoleBindings(ns).List(lo)
+		},
+		Watch: func(jxClient versioned.Interface, ns string, lo metav1.ListOptions) (watch.Interface, error) {
+			return jxClient.JenkinsV1().EnvironmentRoleBindings(ns).Watch(lo)
+		},
+		Get: func(jxClient versioned.Interface, ns string, name string) (metav1.Object, error) {
+			return jxClient.JenkinsV1().EnvironmentRoleBindings(ns).Get(name, metav1.GetOptions{})
+		},
+		Create: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+			_, err := jxClient.JenkinsV1().EnvironmentRoleBindings(ns).Create(obj.(*v1.EnvironmentRoleBinding))
+			return err
+		},
+		Update: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+			_, err := jxClient.JenkinsV1().EnvironmentRoleBindings(ns).Update(obj.(*v1.EnvironmentRoleBinding))
+			return err
+		},
+	},
+	{
+		Resource: "gitservice",
+		Register: func(o *CommonOptions) error {
+			return o.registerGitServiceCRD()
+		},
+		New: func() metav1.Object {
+			return &v1.GitService{}
+		},
+		List: func(jxClient versioned.Interface, ns string, lo metav1.ListOptions) (runtime.Object, error) {
+			return jxClient.JenkinsV1().GitServices(ns).List(lo)
+		},
+		Watch: func(jxClient versioned.Interface, ns string, lo metav1.ListOptions) (watch.Interface, error) {
+			return jxClient.JenkinsV1().GitServices(ns).Watch(lo)
+		},
+		Get: func(jxClient versioned.Interface, ns string, name string) (metav1.Object, error) {
+			return jxClient.JenkinsV1().GitServices(ns).Get(name, metav1.GetOptions{})
+		},
+		Create: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+			_, err := jxClient.JenkinsV1().GitServices(ns).Create(obj.(*v1.GitService))
+			return err
+		},
+		Update: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+			_, err := jxClient.JenkinsV1().GitServices(ns).Update(obj.(*v1.GitService))
+			return err
+		},
+	},
+	{
+		Resource: "extension",
+		Register: func(o *CommonOptions) error {
+			return o.registerExtensionCRD()
+		},
+		New: func() metav1.Object {
+			return &v1.Extension{}
+		},
+		List: func(jxClient versioned.Interface, ns string, lo metav1.ListOptions) (runtime.Object, error) {
+			return jxClient.JenkinsV1().Extensions(ns).List(lo)
+		},
+		Watch: func(jxClient versioned.Interface, ns string, lo metav1.ListOptions) (watch.Interface, error) {
+			return jxClient.JenkinsV1().Extensions(ns).Watch(lo)
+		},
+		Get: func(jxClient versioned.Interface, ns string, name string) (metav1.Object, error) {
+			return jxClient.JenkinsV1().Extensions(ns).Get(name, metav1.GetOptions{})
+		},
+		Create: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+			_, err := jxClient.JenkinsV1().Extensions(ns).Create(obj.(*v1.Extension))
+			return err
+		},
+		Update: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+			_, err := jxClient.JenkinsV1().Extensions(ns).Update(obj.(*v1.Extension))
+			return err
+		},
+	},
+	{
+		Resource: "workflow",
+		Register: func(o *CommonOptions) error {
+			return o.registerWorkflowCRD()
+		},
+		New: func() metav1.Object {
+			return &v1.Workflow{}
+		},
+		List: func(jxClient versioned.Interface, ns string, lo metav1.ListOptions) (runtime.Object, error) {
+			return jxClient.JenkinsV1().Workflows(ns).List(lo)
+		},
+		Watch: func(jxClient versioned.Interface, ns string, lo metav1.ListOptions) (watch.Interface, error) {
+			return jxClient.JenkinsV1().Workflows(ns).Watch(lo)
+		},
+		Get: func(jxClient versioned.Interface, ns string, name string) (metav1.Object, error) {
+			return jxClient.JenkinsV1().Workflows(ns).Get(name, metav1.GetOptions{})
+		},
+		Create: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+			_, err := jxClient.JenkinsV1().Workflows(ns).Create(obj.(*v1.Workflow))
+			return err
+		},
+		Update: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+			_, err := jxClient.JenkinsV1().Workflows(ns).Update(obj.(*v1.Workflow))
+			return err
+		},
+	},
+}
+
+// backupDirName returns the name of the directory in the backup repository of the resource
+func backupDirName(resource string) string {
+	if strings.HasSuffix(resource, "s") {
+		return resource
+	}
+	return fmt.Sprintf("%ss", resource)
+}
+
+// findBackupResource returns the backup resource of the given name or nil if it is not backed up
+func findBackupResource(resource string) *backupResource {
+	for _, r := range backupResources {
+		if r.Resource == resource {
+			return r
+		}
+	}
+	return nil
+}
+
+func (o *CommonOptions) registerGitServiceCRD() error {
+	apisClient, err := o.Factory.CreateApiExtensionsClient()
+	if err != nil {
+		return err
+	}
+	return kube.RegisterGitServiceCRD(apisClient)
+}
+
+func (o *CommonOptions) registerExtensionCRD() error {
+	apisClient, err := o.Factory.CreateApiExtensionsClient()
+	if err != nil {
+		return err
+	}
+	return kube.RegisterExtensionCRD(apisClient)
+}
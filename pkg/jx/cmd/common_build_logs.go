@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/storage"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// BuildLogsStoreFlags the flags which configure where the logs of builds are archived
+type BuildLogsStoreFlags struct {
+	Kind      string
+	GitURL    string
+	GitBranch string
+	Bucket    string
+	Region    string
+	Dir       string
+}
+
+func (f *BuildLogsStoreFlags) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.Kind, "logs-store", "", "", fmt.Sprintf("The kind of store to archive the build logs in. One of: %v", storage.Kinds))
+	cmd.Flags().StringVarP(&f.GitURL, "logs-git-url", "", "", "The git repository to archive the build logs in for the git store")
+	cmd.Flags().StringVarP(&f.GitBranch, "logs-git-branch", "", "build-logs", "The branch of the git repository to archive the build logs in for the git store")
+	cmd.Flags().StringVarP(&f.Bucket, "logs-bucket", "", "", "The bucket to archive the build logs in for the s3 and gcs stores")
+	cmd.Flags().StringVarP(&f.Region, "logs-region", "", "", "The AWS region of the S3 bucket for the s3 store")
+	cmd.Flags().StringVarP(&f.Dir, "logs-dir", "", "/var/jx/build-logs", "The directory such as a mounted persistent volume to archive the build logs in for the pvc store")
+}
+
+// createBuildLogsStore creates the store configured by the flags
+func (o *CommonOptions) createBuildLogsStore(flags *BuildLogsStoreFlags) (storage.Store, error) {
+	switch flags.Kind {
+	case storage.KindGit:
+		if flags.GitURL == "" {
+			return nil, util.MissingOption("logs-git-url")
+		}
+		return storage.NewGitStore(flags.GitURL, flags.GitBranch, o.Git()), nil
+	case storage.KindS3:
+		if flags.Bucket == "" {
+			return nil, util.MissingOption("logs-bucket")
+		}
+		return storage.NewS3Store(flags.Bucket, "", flags.Region), nil
+	case storage.KindGCS:
+		if flags.Bucket == "" {
+			return nil, util.MissingOption("logs-bucket")
+		}
+		return storage.NewGCSStore(flags.Bucket), nil
+	case storage.KindPVC:
+		if flags.Dir == "" {
+			return nil, util.MissingOption("logs-dir")
+		}
+		return storage.NewFileStore(flags.Dir), nil
+	case "":
+		return nil, util.MissingOption("logs-store")
+	default:
+		return nil, util.InvalidOption("logs-store", flags.Kind, storage.Kinds)
+	}
+}
+
+// BuildLogPath returns the path in a store of the archived log of a build of a pipeline
+func BuildLogPath(pipeline string, build string) string {
+	return pipeline + "/" + build + ".log"
+}
+
+// archiveBuildLog writes the log of the build of the activity to the store and records its URL on the activity
+func (o *CommonOptions) archiveBuildLog(jxClient versioned.Interface, ns string, activity *v1.PipelineActivity, data []byte, store storage.Store) error {
+	path := BuildLogPath(activity.Spec.Pipeline, activity.Spec.Build)
+	logURL, err := store.Write(path, data)
+	if err != nil {
+		return errors.Wrapf(err, "failed to archive the build log %s", path)
+	}
+	activity.Spec.BuildLogsURL = logURL
+	_, err = jxClient.JenkinsV1().PipelineActivities(ns).Update(activity)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update PipelineActivity %s", activity.Name)
+	}
+	return nil
+}
+
+// readArchivedBuildLog reads an archived build log. Logs archived in a git repository are read from their raw HTTP URL
+// using the credentials of the git server so that logs in private repositories can be read
+func (o *CommonOptions) readArchivedBuildLog(logURL string) ([]byte, error) {
+	u, err := url.Parse(logURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return storage.ReadURL(logURL)
+	}
+	authConfigSvc, err := o.CreateGitAuthConfigService()
+	if err != nil {
+		return nil, err
+	}
+	serverURL := u.Scheme + "://" + u.Host
+	server := authConfigSvc.Config().GetServer(serverURL)
+	if server == nil {
+		return storage.ReadURL(logURL)
+	}
+	userAuth := authConfigSvc.Config().FindUserAuth(serverURL, server.CurrentUser)
+	if userAuth == nil {
+		return storage.ReadURL(logURL)
+	}
+	password := userAuth.ApiToken
+	if password == "" {
+		password = userAuth.Password
+	}
+	return storage.ReadURLWithBasicAuth(logURL, userAuth.Username, password)
+}
+
+// getBuildPodLog returns the logs of all the steps of a Knative build pod. Steps which have not started yet are skipped
+func (o *CommonOptions) getBuildPodLog(kubeClient kubernetes.Interface, ns string, pod *corev1.Pod) ([]byte, error) {
+	waiting := map[string]bool{}
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.State.Waiting != nil {
+			waiting[status.Name] = true
+		}
+	}
+	var buffer bytes.Buffer
+	for _, c := range pod.Spec.InitContainers {
+		if waiting[c.Name] {
+			continue
+		}
+		data, err := kubeClient.CoreV1().Pods(ns).GetLogs(pod.Name, &corev1.PodLogOptions{Container: c.Name}).Do().Raw()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the log of container %s of pod %s", c.Name, pod.Name)
+		}
+		fmt.Fprintf(&buffer, "Build step %s\n", c.Name)
+		buffer.Write(data)
+	}
+	return buffer.Bytes(), nil
+}
+
+// isBuildPodCompleted returns true if all the steps of a Knative build pod have finished
+func isBuildPodCompleted(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+// getJenkinsBuildLog returns the complete console log of a Jenkins build or false if the build is still running
+func (o *CommonOptions) getJenkinsBuildLog(pipeline string, build int) ([]byte, bool, error) {
+	jobMap, err := o.getJobMap(pipeline)
+	if err != nil {
+		return nil, false, err
+	}
+	job, ok := jobMap[pipeline]
+	if !ok {
+		return nil, false, fmt.Errorf("no Jenkins pipeline found for %s", pipeline)
+	}
+	jenkinsClient, err := o.JenkinsClient()
+	if err != nil {
+		return nil, false, err
+	}
+	jenkinsBuild, err := jenkinsClient.GetBuild(job, build)
+	if err != nil {
+		return nil, false, err
+	}
+	u, err := url.Parse(jenkinsBuild.Url)
+	if err != nil {
+		return nil, false, err
+	}
+	if jenkinsBuild.Building {
+		return nil, false, nil
+	}
+	var buffer bytes.Buffer
+	err = jenkinsClient.TailLog(u.Path, &buffer, time.Second, time.Minute)
+	if err != nil {
+		return nil, false, err
+	}
+	return buffer.Bytes(), true, nil
+}
+
+// getArchivedBuilds returns the activities of the pipeline which have an archived build log sorted by build
+// number with the latest build first. If build is not zero only that build is returned
+func getArchivedBuilds(jxClient versioned.Interface, ns string, pipeline string, build int) ([]v1.PipelineActivity, error) {
+	activities, err := jxClient.JenkinsV1().PipelineActivities(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	answer := []v1.PipelineActivity{}
+	for _, activity := range activities.Items {
+		if activity.Spec.BuildLogsURL == "" || (pipeline != "" && activity.Spec.Pipeline != pipeline) {
+			continue
+		}
+		if build > 0 && activity.Spec.Build != strconv.Itoa(build) {
+			continue
+		}
+		answer = append(answer, activity)
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		if answer[i].Spec.Pipeline != answer[j].Spec.Pipeline {
+			return answer[i].Spec.Pipeline < answer[j].Spec.Pipeline
+		}
+		bi, _ := strconv.Atoi(answer[i].Spec.Build)
+		bj, _ := strconv.Atoi(answer[j].Spec.Build)
+		return bi > bj
+	})
+	return answer, nil
+}
+
+// SearchBuildLog returns the lines of the build log which match the regular expression
+func SearchBuildLog(data []byte, re *regexp.Regexp) []string {
+	answer := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if re.MatchString(line) {
+			answer = append(answer, line)
+		}
+	}
+	return answer
+}
@@ -0,0 +1,30 @@
+package cmd_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildLogPath(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "myorg/myapp/master/3.log", cmd.BuildLogPath("myorg/myapp/master", "3"))
+}
+
+func TestSearchBuildLog(t *testing.T) {
+	t.Parallel()
+	data := []byte(`Build step build-step-git-source
+Successfully cloned
+Build step build-step-build
+--- FAIL: TestSomething (0.01s)
+ok  	github.com/myorg/myapp/pkg	0.123s
+panic: runtime error
+`)
+	lines := cmd.SearchBuildLog(data, regexp.MustCompile("FAIL|panic"))
+	assert.Equal(t, []string{"--- FAIL: TestSomething (0.01s)", "panic: runtime error"}, lines)
+
+	lines = cmd.SearchBuildLog(data, regexp.MustCompile("nothing"))
+	assert.Empty(t, lines)
+}
@@ -24,7 +24,11 @@ func (o *CommonOptions) createChatProvider(chatConfig *config.ChatConfig) (chats
 	if err != nil {
 		return nil, err
 	}
-	return chats.CreateChatProvider(server.Kind, server, userAuth, o.BatchMode)
+	kind := server.Kind
+	if kind == "" {
+		kind = chatConfig.Kind
+	}
+	return chats.CreateChatProvider(kind, server, userAuth, o.BatchMode)
 }
 
 func (o *CommonOptions) CreateChatAuthConfigService() (auth.AuthConfigService, error) {
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/cve"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// createCVEProvider creates the CVE provider of the given kind. The Anchore provider uses the anchore addon service,
+// the Clair provider uses the server at the URL and the report provider reads the report files
+func (o *CommonOptions) createCVEProvider(kind string, serverURL string, reports []string) (cve.CVEProvider, error) {
+	switch kind {
+	case cve.ProviderKindAnchore, "":
+		externalURL, err := o.ensureAddonServiceAvailable(kube.AddonServices[defaultAnchoreName])
+		if err != nil {
+			log.Warnf("no CVE provider service found, are you in your teams dev environment?  Type `jx env` to switch.\n")
+			return nil, fmt.Errorf("if no CVE provider running, try running `jx create addon anchore` in your teams dev environment: %v", err)
+		}
+		server, userAuth, err := o.getAddonAuthByKind(kube.ValueKindCVE, externalURL)
+		if err != nil {
+			return nil, fmt.Errorf("error getting anchore engine auth details, %v", err)
+		}
+		p, err := cve.NewAnchoreProvider(server, userAuth)
+		if err != nil {
+			return nil, fmt.Errorf("error creating anchore provider, %v", err)
+		}
+		return p, nil
+
+	case cve.ProviderKindClair:
+		if serverURL == "" {
+			return nil, util.MissingOption("url")
+		}
+		server, userAuth, err := o.getAddonAuthByKind(kube.ValueKindCVE, serverURL)
+		if err != nil {
+			// clair servers do not require authentication by default
+			server = &auth.AuthServer{
+				URL: serverURL,
+			}
+			userAuth = nil
+		}
+		return cve.NewClairProvider(server, userAuth)
+
+	case cve.ProviderKindReport:
+		if len(reports) == 0 {
+			return nil, util.MissingOption("report")
+		}
+		return cve.NewReportProvider(reports...)
+
+	default:
+		return nil, util.InvalidOption("provider", kind, cve.ProviderKinds)
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/reports"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeliveryMetricsFlags the flags to filter the delivery metrics
+type DeliveryMetricsFlags struct {
+	FromDate    string
+	ToDate      string
+	Environment string
+	App         string
+}
+
+func (f *DeliveryMetricsFlags) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.FromDate, "from-date", "f", "", "The date to calculate the metrics from. Defaults to 4 weeks before the to date. Should be a format: "+util.DateFormat)
+	cmd.Flags().StringVarP(&f.ToDate, "to-date", "t", "", "The date to calculate the metrics up to. Defaults to now. Should be a format: "+util.DateFormat)
+	cmd.Flags().StringVarP(&f.Environment, "env", "e", "", "Only include the promotions to the given environment")
+	cmd.Flags().StringVarP(&f.App, "app", "a", "", "Only include the promotions of the given application")
+}
+
+// filter returns the filter of the activities for the flags
+func (f *DeliveryMetricsFlags) filter() (*reports.DeliveryMetricsFilter, error) {
+	to := time.Now()
+	if f.ToDate != "" {
+		t, err := util.ParseDate(f.ToDate)
+		if err != nil {
+			return nil, util.InvalidOptionError("to-date", f.ToDate, err)
+		}
+		// lets include the whole of the to date
+		to = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	from := to.Add(-time.Hour * 24 * 7 * 4)
+	if f.FromDate != "" {
+		t, err := util.ParseDate(f.FromDate)
+		if err != nil {
+			return nil, util.InvalidOptionError("from-date", f.FromDate, err)
+		}
+		from = t
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("the from date %s must be before the to date %s", util.FormatDate(from), util.FormatDate(to))
+	}
+	return &reports.DeliveryMetricsFilter{
+		From:        from,
+		To:          to,
+		Environment: f.Environment,
+		App:         f.App,
+	}, nil
+}
+
+// createDeliveryReport calculates the delivery metrics from the PipelineActivity and Release resources in the dev
+// namespace
+func (o *CommonOptions) createDeliveryReport(flags *DeliveryMetricsFlags) (*reports.DeliveryReport, error) {
+	filter, err := flags.filter()
+	if err != nil {
+		return nil, err
+	}
+	err = o.registerPipelineActivityCRD()
+	if err != nil {
+		return nil, err
+	}
+	err = o.registerReleaseCRD()
+	if err != nil {
+		return nil, err
+	}
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return nil, err
+	}
+	activities, err := jxClient.JenkinsV1().PipelineActivities(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list PipelineActivity resources in namespace %s", ns)
+	}
+	releases, err := jxClient.JenkinsV1().Releases(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list Release resources in namespace %s", ns)
+	}
+	return reports.CalculateDeliveryMetrics(activities.Items, releases.Items, filter), nil
+}
+
+// formatMetricsDuration formats the duration of a metric to the nearest minute
+func formatMetricsDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.Round(time.Minute).String()
+}
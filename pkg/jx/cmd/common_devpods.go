@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"k8s.io/client-go/kubernetes"
+)
+
+// devPodActivityInterval how often the activity of a DevPod is recorded while it is used
+const devPodActivityInterval = time.Minute
+
+// markDevPodActive records that the DevPod is being used so that 'jx gc devpods' does not stop it
+func (o *CommonOptions) markDevPodActive(client kubernetes.Interface, ns string, name string) {
+	err := kube.MarkDevPodActive(client, ns, name, time.Now())
+	if err != nil {
+		log.Warnf("Failed to record the activity of DevPod %s: %s\n", name, err)
+	}
+}
+
+// keepDevPodActive records the activity of the DevPod periodically until the returned function is called
+func (o *CommonOptions) keepDevPodActive(client kubernetes.Interface, ns string, name string) func() {
+	stop := make(chan struct{})
+	o.markDevPodActive(client, ns, name)
+	go func() {
+		ticker := time.NewTicker(devPodActivityInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				o.markDevPodActive(client, ns, name)
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		o.markDevPodActive(client, ns, name)
+	}
+}
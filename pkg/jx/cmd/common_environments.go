@@ -19,12 +19,15 @@ import (
 // ModifyRequirementsFn callback for modifying requirements
 type ModifyRequirementsFn func(requirements *helm.Requirements) error
 
+// ModifyChartFilesFn callback for modifying other files in the chart directory such as its values or templates
+type ModifyChartFilesFn func(chartDir string) error
+
 // ConfigureGitFolderFn callback to optionally configure git before its used for creating commits and PRs
 type ConfigureGitFolderFn func(dir string, gitInfo *gits.GitRepositoryInfo, gitAdapter gits.Gitter) error
 
 type CreateEnvPullRequestFn func(env *v1.Environment, modifyRequirementsFn ModifyRequirementsFn, branchNameText string, title string, message string, pullRequestInfo *ReleasePullRequestInfo) (*ReleasePullRequestInfo, error)
 
-func (o *CommonOptions) createEnvironmentPullRequest(env *v1.Environment, modifyRequirementsFn ModifyRequirementsFn, branchNameText string, title string, message string, pullRequestInfo *ReleasePullRequestInfo, configGitFn ConfigureGitFolderFn) (*ReleasePullRequestInfo, error) {
+func (o *CommonOptions) createEnvironmentPullRequest(env *v1.Environment, modifyRequirementsFn ModifyRequirementsFn, modifyChartFilesFn ModifyChartFilesFn, branchNameText string, title string, message string, pullRequestInfo *ReleasePullRequestInfo, configGitFn ConfigureGitFolderFn) (*ReleasePullRequestInfo, error) {
 	var answer *ReleasePullRequestInfo
 	source := &env.Spec.Source
 	gitURL := source.URL
@@ -130,8 +133,21 @@ func (o *CommonOptions) createEnvironmentPullRequest(env *v1.Environment, modify
 	}
 
 	err = modifyRequirementsFn(requirements)
+	if err != nil {
+		return answer, err
+	}
 
 	err = helm.SaveRequirementsFile(requirementsFile, requirements)
+	if err != nil {
+		return answer, err
+	}
+
+	if modifyChartFilesFn != nil {
+		err = modifyChartFilesFn(filepath.Dir(requirementsFile))
+		if err != nil {
+			return answer, err
+		}
+	}
 
 	err = o.Git().Add(dir, "*", "*/*")
 	if err != nil {
@@ -62,12 +62,13 @@ jenkinsLocationConfiguration.save()
 )
 
 type Prow struct {
-	Version     string
-	Chart       string
-	SetValues   string
-	ReleaseName string
-	HMACToken   string
-	OAUTHToken  string
+	Version        string
+	Chart          string
+	SetValues      string
+	ReleaseName    string
+	HMACToken      string
+	OAUTHToken     string
+	GitProviderURL string
 }
 
 func (o *CommonOptions) doInstallMissingDependencies(install []string) error {
@@ -1540,15 +1541,35 @@ func (o *CommonOptions) installProw() error {
 		}
 	}
 
-	if o.OAUTHToken == "" {
-		authConfigSvc, err := o.CreateGitAuthConfigService()
+	authConfigSvc, err := o.CreateGitAuthConfigService()
+	if err != nil {
+		return err
+	}
+	config := authConfigSvc.Config()
+	gitServerURL := o.GitProviderURL
+	if gitServerURL == "" {
+		gitServerURL = config.CurrentServer
+	}
+	if gitServerURL == "" {
+		gitServerURL = gits.GitHubURL
+	}
+	server := config.GetOrCreateServer(gitServerURL)
+	gitKind := server.Kind
+	if gitKind == "" {
+		gitKind = gits.SaasGitKind(server.URL)
+	}
+	if gitKind == "" {
+		gitKind, err = o.GitServerHostURLKind(server.URL)
 		if err != nil {
 			return err
 		}
+	}
+
+	if gitKind != gits.KindGitHub {
+		log.Warnf("Prow only accepts webhook events signed like GitHub so webhook events from the %s server %s will be rejected\n", gitKind, server.URL)
+	}
 
-		config := authConfigSvc.Config()
-		// lets assume github.com for now so ignore config.CurrentServer
-		server := config.GetOrCreateServer("https://github.com")
+	if o.OAUTHToken == "" {
 		userAuth, err := config.PickServerUserAuth(server, "Git account to be used to send webhook events", o.BatchMode, "", o.In, o.Out, o.Err)
 		if err != nil {
 			return err
@@ -1575,7 +1596,7 @@ func (o *CommonOptions) installProw() error {
 	// create initial configmaps if they don't already exist, use a dummy repo so tide doesn't start scanning all github
 	_, err = o.KubeClientCached.CoreV1().ConfigMaps(devNamespace).Get("config", metav1.GetOptions{})
 	if err != nil {
-		err = prow.AddApplication(o.KubeClientCached, []string{"jenkins-x/dummy"}, devNamespace, "base")
+		err = prow.AddApplication(o.KubeClientCached, []string{"jenkins-x/dummy"}, devNamespace, "base", nil)
 		if err != nil {
 			return err
 		}
@@ -1644,6 +1665,24 @@ func (o *CommonOptions) createWebhookProw(gitURL string, gitProvider gits.GitPro
 	return gitProvider.CreateWebHook(webhook)
 }
 
+// prowGitProvider returns the Git provider of the team's Git server so that Prow can protect the branches of
+// repositories which are not on GitHub. Returns nil for GitHub where Prow's branch protector is used instead
+func (o *CommonOptions) prowGitProvider() (gits.GitProvider, error) {
+	teamSettings, err := o.TeamSettings()
+	if err != nil {
+		return nil, err
+	}
+	gitServer := teamSettings.GitServer
+	if gits.IsGitHubServerURL(gitServer) {
+		return nil, nil
+	}
+	gitKind, err := o.GitServerHostURLKind(gitServer)
+	if err != nil {
+		return nil, err
+	}
+	return o.gitProviderForGitServerURL(gitServer, gitKind)
+}
+
 func (o *CommonOptions) isProw() (bool, error) {
 	env, err := kube.GetEnvironment(o.jxClient, o.currentNamespace, "dev")
 	if err != nil {
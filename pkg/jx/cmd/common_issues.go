@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
 
 	"github.com/jenkins-x/jx/pkg/auth"
 	"github.com/jenkins-x/jx/pkg/config"
@@ -9,6 +11,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/issues"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/pkg/errors"
 )
 
 func (o *CommonOptions) CreateIssueTrackerAuthConfigService() (auth.AuthConfigService, error) {
@@ -28,18 +31,48 @@ func (o *CommonOptions) errorCreateIssueTrackerAuthConfigService(parentError err
 }
 
 func (o *CommonOptions) createIssueProvider(dir string) (issues.IssueProvider, error) {
-	gitDir, gitConfDir, err := o.Git().FindGitConfigDir(dir)
+	_, gitConfDir, err := o.Git().FindGitConfigDir(dir)
 	if err != nil {
 		return nil, fmt.Errorf("No issue tracker configured for this project and cannot find the .git directory: %s", err)
 	}
-	pc, _, err := config.LoadProjectConfig(dir)
+	tracker, _, err := o.createProjectIssueProvider(dir)
+	if err != nil || tracker != nil {
+		return tracker, err
+	}
+
+	if gitConfDir == "" {
+		return nil, fmt.Errorf("No issue tracker configured and no git directory could be found from dir %s\n", dir)
+	}
+	gitUrl, err := o.Git().DiscoverUpstreamGitURL(gitConfDir)
+	if err != nil {
+		return nil, fmt.Errorf("No issue tracker configured and could not find the upstream git URL for dir %s, due to: %s\n", dir, err)
+	}
+	gitInfo, err := gits.ParseGitURL(gitUrl)
+	if err != nil {
+		return nil, err
+	}
+	gitProvider, err := o.gitProviderForURL(gitUrl, "user name to use for authenticating with git issues")
 	if err != nil {
 		return nil, err
 	}
+	return issues.CreateGitIssueProvider(gitProvider, gitInfo.Organisation, gitInfo.Name)
+}
+
+// createProjectIssueProvider creates the issue provider configured in the jenkins-x.yml of the project along with its
+// configuration or returns nil if the project uses the issues of its git provider
+func (o *CommonOptions) createProjectIssueProvider(dir string) (issues.IssueProvider, *config.IssueTrackerConfig, error) {
+	gitDir, _, err := o.Git().FindGitConfigDir(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("No issue tracker configured for this project and cannot find the .git directory: %s", err)
+	}
+	pc, _, err := config.LoadProjectConfig(dir)
+	if err != nil {
+		return nil, nil, err
+	}
 	if pc != nil && pc.IssueTracker == nil {
 		pc, _, err = config.LoadProjectConfig(gitDir)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 	if pc != nil {
@@ -48,33 +81,62 @@ func (o *CommonOptions) createIssueProvider(dir string) (issues.IssueProvider, e
 			if it.URL != "" && it.Kind != "" {
 				authConfigSvc, err := o.CreateIssueTrackerAuthConfigService()
 				if err != nil {
-					return nil, err
+					return nil, it, err
 				}
 				config := authConfigSvc.Config()
 				server := config.GetOrCreateServer(it.URL)
 				userAuth, err := config.PickServerUserAuth(server, "user to access the issue tracker", o.BatchMode, "", o.In, o.Out, o.Err)
 				if err != nil {
-					return nil, err
+					return nil, it, err
 				}
-				return issues.CreateIssueProvider(it.Kind, server, userAuth, it.Project, o.BatchMode, o.Git())
+				tracker, err := issues.CreateIssueProvider(it.Kind, server, userAuth, it.Project, o.BatchMode, o.Git())
+				return tracker, it, err
 			}
 		}
 	}
+	return nil, nil, nil
+}
 
-	if gitConfDir == "" {
-		return nil, fmt.Errorf("No issue tracker configured and no git directory could be found from dir %s\n", dir)
+// createRepositoryIssueProvider creates the issue provider configured in the jenkins-x.yml of the given git repository
+// along with its configuration or returns nil if the repository uses the issues of its git provider. The current
+// directory is used if it is a clone of the repository, otherwise the repository is cloned so that commands such as
+// the controllers which do not run inside the source of the project find its issue tracker
+func (o *CommonOptions) createRepositoryIssueProvider(gitInfo *gits.GitRepositoryInfo, branch string) (issues.IssueProvider, *config.IssueTrackerConfig, error) {
+	gitDir, _, err := o.Git().FindGitConfigDir("")
+	if err == nil && gitDir != "" {
+		localInfo, err := o.Git().Info(gitDir)
+		if err == nil && localInfo.Host == gitInfo.Host && localInfo.Organisation == gitInfo.Organisation && localInfo.Name == gitInfo.Name {
+			return o.createProjectIssueProvider(gitDir)
+		}
 	}
-	gitUrl, err := o.Git().DiscoverUpstreamGitURL(gitConfDir)
+	if branch == "" {
+		branch = "master"
+	}
+	dir, err := ioutil.TempDir("", "jx-issue-tracker-")
 	if err != nil {
-		return nil, fmt.Errorf("No issue tracker configured and could not find the upstream git URL for dir %s, due to: %s\n", dir, err)
+		return nil, nil, err
 	}
-	gitInfo, err := gits.ParseGitURL(gitUrl)
+	defer os.RemoveAll(dir)
+
+	gitURL := gitInfo.HttpCloneURL()
+	cloneURL := gitURL
+	authConfigSvc, err := o.CreateGitAuthConfigService()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	gitProvider, err := o.gitProviderForURL(gitUrl, "user name to use for authenticating with git issues")
+	server := authConfigSvc.Config().GetServer(gitInfo.HostURLWithoutUser())
+	if server != nil {
+		userAuth := authConfigSvc.Config().FindUserAuth(server.URL, server.CurrentUser)
+		if userAuth != nil {
+			cloneURL, err = o.Git().CreatePushURL(gitURL, userAuth)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	err = o.Git().ShallowCloneBranch(cloneURL, branch, dir)
 	if err != nil {
-		return nil, err
+		return nil, nil, errors.Wrapf(err, "cloning branch %s of %s", branch, gitURL)
 	}
-	return issues.CreateGitIssueProvider(gitProvider, gitInfo.Organisation, gitInfo.Name)
+	return o.createProjectIssueProvider(dir)
 }
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	pe "github.com/jenkins-x/jx/pkg/pipeline_events"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PipelineEventSinkFlags the flags to configure the sinks which pipeline events are published to
+type PipelineEventSinkFlags struct {
+	CloudEventsURL        string
+	CloudEventsStructured bool
+	WebhookURL            string
+	WebhookSecret         string
+	KafkaURL              string
+	KafkaTopic            string
+}
+
+// PipelineEventsFlags the flags for the commands which report pipeline events
+type PipelineEventsFlags struct {
+	PipelineEventSinkFlags
+
+	QueueDir        string
+	RetryInterval   time.Duration
+	NoElasticsearch bool
+}
+
+func (f *PipelineEventSinkFlags) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.CloudEventsURL, "cloudevents-url", "", "", "The URL to publish CloudEvents to such as a Knative broker")
+	cmd.Flags().BoolVarP(&f.CloudEventsStructured, "cloudevents-structured", "", false, "Sends CloudEvents in structured rather than binary content mode")
+	cmd.Flags().StringVarP(&f.WebhookURL, "webhook-url", "", "", "The URL of a webhook to post the events to")
+	cmd.Flags().StringVarP(&f.WebhookSecret, "webhook-secret", "", "", "The secret used to sign the webhook requests with a HMAC SHA256 signature in the "+pe.WebhookSignatureHeader+" header")
+	cmd.Flags().StringVarP(&f.KafkaURL, "kafka-url", "", "", "The URL of the Kafka REST Proxy to write the events to")
+	cmd.Flags().StringVarP(&f.KafkaTopic, "kafka-topic", "", "jx-pipeline-events", "The Kafka topic to write the events to")
+}
+
+func (f *PipelineEventsFlags) addFlags(cmd *cobra.Command) {
+	f.PipelineEventSinkFlags.addFlags(cmd)
+	cmd.Flags().StringVarP(&f.QueueDir, "queue-dir", "", "", "The directory used to queue the events which failed to be delivered. Should be on a persistent volume when running in a pod. Defaults to ~/.jx/pipeline-events")
+	cmd.Flags().DurationVarP(&f.RetryInterval, "retry-interval", "", time.Minute, "How often queued events are retried when watching")
+	cmd.Flags().BoolVarP(&f.NoElasticsearch, "no-elasticsearch", "", false, "Disables sending events to the Elasticsearch of the pipeline-events addon")
+}
+
+// sinksConfig returns the configuration of the sinks specified on the command line
+func (f *PipelineEventSinkFlags) sinksConfig() *pe.SinksConfig {
+	config := &pe.SinksConfig{}
+	if f.CloudEventsURL != "" {
+		config.AddSink(pe.SinkConfig{
+			Kind:       pe.SinkKindCloudEvents,
+			URL:        f.CloudEventsURL,
+			Structured: f.CloudEventsStructured,
+		})
+	}
+	if f.WebhookURL != "" {
+		config.AddSink(pe.SinkConfig{
+			Kind:   pe.SinkKindWebhook,
+			URL:    f.WebhookURL,
+			Secret: f.WebhookSecret,
+		})
+	}
+	if f.KafkaURL != "" {
+		config.AddSink(pe.SinkConfig{
+			Kind:  pe.SinkKindKafka,
+			URL:   f.KafkaURL,
+			Topic: f.KafkaTopic,
+		})
+	}
+	return config
+}
+
+// loadPipelineEventSinksConfig loads the configuration of the event sinks from the secret in the namespace
+func (o *CommonOptions) loadPipelineEventSinksConfig(ns string) (*pe.SinksConfig, error) {
+	kubeClient, _, err := o.KubeClient()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := kubeClient.CoreV1().Secrets(ns).Get(kube.SecretPipelineEventSinks, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return &pe.SinksConfig{}, nil
+		}
+		return nil, err
+	}
+	return pe.LoadSinksConfig(secret.Data[kube.SecretDataPipelineEventSinks])
+}
+
+// savePipelineEventSinksConfig saves the configuration of the event sinks into the secret in the namespace
+func (o *CommonOptions) savePipelineEventSinksConfig(ns string, config *pe.SinksConfig) error {
+	kubeClient, _, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	data, err := config.ToYAML()
+	if err != nil {
+		return err
+	}
+	secrets := kubeClient.CoreV1().Secrets(ns)
+	secret, err := secrets.Get(kube.SecretPipelineEventSinks, metav1.GetOptions{})
+	if err != nil {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: kube.SecretPipelineEventSinks,
+			},
+			Data: map[string][]byte{
+				kube.SecretDataPipelineEventSinks: data,
+			},
+		}
+		_, err = secrets.Create(secret)
+		if err != nil {
+			return fmt.Errorf("failed to create secret %s in namespace %s: %s", kube.SecretPipelineEventSinks, ns, err)
+		}
+		return nil
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[kube.SecretDataPipelineEventSinks] = data
+	_, err = secrets.Update(secret)
+	if err != nil {
+		return fmt.Errorf("failed to update secret %s in namespace %s: %s", kube.SecretPipelineEventSinks, ns, err)
+	}
+	return nil
+}
+
+// createPipelineEventsProvider creates the provider which sends the pipeline events to the Elasticsearch of the
+// pipeline-events addon and to the event sinks configured in the dev namespace or on the command line
+func (o *CommonOptions) createPipelineEventsProvider(flags *PipelineEventsFlags) (pe.PipelineEventsProvider, error) {
+	_, devNs, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return nil, err
+	}
+	config, err := o.loadPipelineEventSinksConfig(devNs)
+	if err != nil {
+		return nil, err
+	}
+	for _, sink := range flags.sinksConfig().Sinks {
+		config.AddSink(sink)
+	}
+
+	providers := pe.Providers{}
+	if !flags.NoElasticsearch {
+		provider, err := o.createElasticsearchPipelineEventsProvider()
+		if err != nil {
+			if len(config.Sinks) == 0 {
+				log.Warnf("no %s service found, are you in your teams dev environment?  Type `jx env` to switch.\n", kube.AddonServices[defaultPEName])
+				return nil, fmt.Errorf("try running `jx create addon pipeline-events` in your teams dev environment: %v", err)
+			}
+			log.Warnf("Not sending events to Elasticsearch: %s\n", err)
+		} else {
+			providers = append(providers, provider)
+		}
+	}
+
+	if len(config.Sinks) > 0 {
+		sinks, err := config.CreateSinks()
+		if err != nil {
+			return nil, err
+		}
+		queueDir := flags.QueueDir
+		if queueDir == "" {
+			configDir, err := util.ConfigDir()
+			if err != nil {
+				return nil, err
+			}
+			queueDir = filepath.Join(configDir, "pipeline-events")
+		}
+		queue, err := pe.NewEventQueue(queueDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, sink := range sinks {
+			log.Infof("Sending pipeline events to the %s sink\n", util.ColorInfo(sink.Name()))
+		}
+		providers = append(providers, pe.NewSinkProvider(queue, sinks...))
+	}
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no pipeline event sinks configured")
+	}
+	return providers, nil
+}
+
+// createElasticsearchPipelineEventsProvider creates the provider which sends the pipeline events to the Elasticsearch
+// of the pipeline-events addon
+func (o *CommonOptions) createElasticsearchPipelineEventsProvider() (pe.PipelineEventsProvider, error) {
+	esServiceName := kube.AddonServices[defaultPEName]
+	externalURL, err := o.ensureAddonServiceAvailable(esServiceName)
+	if err == nil && externalURL == "" {
+		err = fmt.Errorf("no %s service found", esServiceName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	server, auth, err := o.getAddonAuthByKind(kube.ValueKindPipelineEvent, externalURL)
+	if err != nil {
+		return nil, fmt.Errorf("error getting %s auth details, %v", kube.ValueKindPipelineEvent, err)
+	}
+	provider, err := pe.NewElasticsearchProvider(server, auth)
+	if err != nil {
+		return nil, fmt.Errorf("error creating elasticsearch provider, %v", err)
+	}
+	return provider, nil
+}
+
+// retryQueuedPipelineEvents retries sending the events of the provider which failed to be delivered previously
+func retryQueuedPipelineEvents(provider pe.PipelineEventsProvider) {
+	retrier, ok := provider.(pe.Retrier)
+	if ok {
+		err := retrier.Retry()
+		if err != nil {
+			log.Warnf("%s\n", err)
+		}
+	}
+}
+
+// retryPipelineEvents periodically retries the queued events of the provider until the stop channel is closed
+func retryPipelineEvents(provider pe.PipelineEventsProvider, interval time.Duration, stop chan struct{}) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			retryQueuedPipelineEvents(provider)
+		case <-stop:
+			return
+		}
+	}
+}
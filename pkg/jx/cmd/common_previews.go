@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+// previewWakerServiceName the name of the service in a preview namespace which the nginx ingresses of a preview
+// scaled down to zero send their requests to so that the preview is woken up
+const previewWakerServiceName = "preview-waker"
+
+// reclaimPreview deletes or scales down the preview environment of the decision and comments on its pull request
+func (o *CommonOptions) reclaimPreview(kubeClient kubernetes.Interface, jxClient versioned.Interface, devNs string, decision *kube.PreviewDecision, policy *v1.PreviewLifecyclePolicy) error {
+	env := decision.Environment
+	comment := ""
+	switch decision.Action {
+	case kube.PreviewActionDelete:
+		deleteOpts := DeleteEnvOptions{
+			DeleteNamespace: true,
+			CommonOptions:   *o,
+		}
+		deleteOpts.CommonOptions.Args = []string{env.Name}
+		err := deleteOpts.Run()
+		if err != nil {
+			return fmt.Errorf("failed to delete preview environment %s: %v", env.Name, err)
+		}
+		log.Infof("Deleted preview environment %s as it was %s\n", util.ColorInfo(env.Name), decision.Reason)
+		comment = fmt.Sprintf(":wastebasket: The preview environment **%s** was deleted as it was %s. It will be recreated by the next push to this pull request.", env.Name, decision.Reason)
+	case kube.PreviewActionScaleDown:
+		ns := env.Spec.Namespace
+		err := kube.ScaleDownDeployments(kubeClient, ns)
+		if err != nil {
+			return err
+		}
+		wakeUp := "the next push to this pull request"
+		if policy != nil && policy.WakerService != "" {
+			err = kube.EnsureExternalNameService(kubeClient, ns, previewWakerServiceName, fmt.Sprintf("%s.%s.svc.cluster.local", policy.WakerService, devNs))
+			if err != nil {
+				return errors.Wrapf(err, "failed to create the %s service in namespace %s", previewWakerServiceName, ns)
+			}
+			err = kube.RouteIngressErrorsToService(kubeClient, ns, previewWakerServiceName)
+			if err != nil {
+				return err
+			}
+			wakeUp = "the next request to the preview or the next push to this pull request"
+		}
+		if env.Annotations == nil {
+			env.Annotations = map[string]string{}
+		}
+		env.Annotations[kube.AnnotationPreviewScaledDown] = "true"
+		_, err = jxClient.JenkinsV1().Environments(devNs).Update(env)
+		if err != nil {
+			return errors.Wrapf(err, "failed to update preview environment %s", env.Name)
+		}
+		log.Infof("Scaled down preview environment %s as it was %s\n", util.ColorInfo(env.Name), decision.Reason)
+		comment = fmt.Sprintf(":sleeping: The preview environment **%s** was scaled down to zero as it was %s. It will be woken up by %s.", env.Name, decision.Reason, wakeUp)
+	default:
+		return nil
+	}
+	err := o.commentOnPreviewPullRequest(env, comment)
+	if err != nil {
+		log.Warnf("Failed to comment on the pull request of preview environment %s: %s\n", env.Name, err)
+	}
+	return nil
+}
+
+// commentOnPreviewPullRequest adds the comment to the pull request of the preview environment
+func (o *CommonOptions) commentOnPreviewPullRequest(env *v1.Environment, comment string) error {
+	gitURL := env.Spec.Source.URL
+	if gitURL == "" {
+		return fmt.Errorf("no source URL")
+	}
+	prNumber, err := strconv.Atoi(env.Spec.PreviewGitSpec.Name)
+	if err != nil {
+		return fmt.Errorf("invalid pull request number %s", env.Spec.PreviewGitSpec.Name)
+	}
+	gitInfo, err := gits.ParseGitURL(gitURL)
+	if err != nil {
+		return err
+	}
+	provider, err := o.gitProviderForURL(gitURL, "user name to comment on the pull request")
+	if err != nil {
+		return err
+	}
+	return provider.AddPRComment(&gits.GitPullRequest{
+		Owner:  gitInfo.Organisation,
+		Repo:   gitInfo.Name,
+		Number: &prNumber,
+	}, comment)
+}
+
+// wakePreview scales the deployments of a preview environment scaled down to zero back up
+func (o *CommonOptions) wakePreview(kubeClient kubernetes.Interface, jxClient versioned.Interface, devNs string, env *v1.Environment) error {
+	ns := env.Spec.Namespace
+	err := kube.WakeDeployments(kubeClient, ns)
+	if err != nil {
+		return err
+	}
+	err = kube.RemoveIngressErrorRouting(kubeClient, ns, previewWakerServiceName)
+	if err != nil {
+		return err
+	}
+	delete(env.Annotations, kube.AnnotationPreviewScaledDown)
+	kube.SetPreviewAnnotationTime(env, kube.AnnotationPreviewLastWoken, time.Now())
+	_, err = jxClient.JenkinsV1().Environments(devNs).Update(env)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update preview environment %s", env.Name)
+	}
+	log.Infof("Woke up preview environment %s\n", util.ColorInfo(env.Name))
+	return nil
+}
@@ -54,6 +54,8 @@ func NewCmdController(f Factory, in terminal.FileReader, out terminal.FileWriter
 	cmd.AddCommand(NewCmdControllerTeam(f, in, out, errOut))
 	cmd.AddCommand(NewCmdControllerWorkflow(f, in, out, errOut))
 	cmd.AddCommand(NewCmdControllerCommitStatus(f, in, out, errOut))
+	cmd.AddCommand(NewCmdControllerNotify(f, in, out, errOut))
+	cmd.AddCommand(NewCmdControllerPreviewWaker(f, in, out, errOut))
 	return cmd
 }
 
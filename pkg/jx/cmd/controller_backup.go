@@ -67,20 +67,12 @@ func NewCmdControllerBackup(f Factory, in terminal.FileReader, out terminal.File
 
 // Run implements this command
 func (o *ControllerBackupOptions) Run() error {
-	// ensure Environment / Team / User CRDs are registered before we start
-	err := o.registerEnvironmentCRD()
-	if err != nil {
-		return err
-	}
-
-	err = o.registerTeamCRD()
-	if err != nil {
-		return err
-	}
-
-	err = o.registerUserCRD()
-	if err != nil {
-		return err
+	// ensure the CRDs of the backed up resources are registered before we start
+	for _, resource := range backupResources {
+		err := resource.Register(&o.CommonOptions)
+		if err != nil {
+			return err
+		}
 	}
 
 	jxClient, devNs, err := o.JXClientAndDevNamespace()
@@ -94,117 +86,55 @@ func (o *ControllerBackupOptions) Run() error {
 	}
 
 	dir, err := o.getOrCreateBackupRepository()
+	if err != nil {
+		return err
+	}
 
-	log.Infof("Watching for users/teams/environments in namespace %s\n", util.ColorInfo(ns))
-
-	_, environmentController := cache.NewInformer(
-		&cache.ListWatch{
-			ListFunc: func(lo meta_v1.ListOptions) (runtime.Object, error) {
-				return jxClient.JenkinsV1().Environments(ns).List(lo)
-			},
-			WatchFunc: func(lo meta_v1.ListOptions) (watch.Interface, error) {
-				return jxClient.JenkinsV1().Environments(ns).Watch(lo)
-			},
-		},
-		&v1.Environment{},
-		time.Minute*10,
-		cache.ResourceEventHandlerFuncs{
-			AddFunc: func(obj interface{}) {
-				o.onEnvironmentChange(obj, ns, dir)
-			},
-			UpdateFunc: func(oldObj, newObj interface{}) {
-				o.onEnvironmentChange(newObj, ns, dir)
-			},
-			DeleteFunc: func(obj interface{}) {
-				//o.onEnvironmentChange(obj, jxClient, ns)
-			},
-		},
-	)
+	log.Infof("Watching for users/teams/environments/environmentrolebindings/gitservices/extensions/workflows in namespace %s\n", util.ColorInfo(ns))
 
 	stop := make(chan struct{})
-	go environmentController.Run(stop)
-
-	_, teamController := cache.NewInformer(
-		&cache.ListWatch{
-			ListFunc: func(lo meta_v1.ListOptions) (runtime.Object, error) {
-				return jxClient.JenkinsV1().Teams(ns).List(lo)
-			},
-			WatchFunc: func(lo meta_v1.ListOptions) (watch.Interface, error) {
-				return jxClient.JenkinsV1().Teams(ns).Watch(lo)
+	for _, r := range backupResources {
+		resource := r
+		_, controller := cache.NewInformer(
+			&cache.ListWatch{
+				ListFunc: func(lo meta_v1.ListOptions) (runtime.Object, error) {
+					return resource.List(jxClient, ns, lo)
+				},
+				WatchFunc: func(lo meta_v1.ListOptions) (watch.Interface, error) {
+					return resource.Watch(jxClient, ns, lo)
+				},
 			},
-		},
-		&v1.Team{},
-		time.Minute*10,
-		cache.ResourceEventHandlerFuncs{
-			AddFunc: func(obj interface{}) {
-				o.onTeamChange(obj, ns, dir)
-			},
-			UpdateFunc: func(oldObj, newObj interface{}) {
-				o.onTeamChange(newObj, ns, dir)
-			},
-			DeleteFunc: func(obj interface{}) {
-				//o.onEnvironmentChange(obj, jxClient, ns)
-			},
-		},
-	)
-
-	go teamController.Run(stop)
-
-	_, userController := cache.NewInformer(
-		&cache.ListWatch{
-			ListFunc: func(lo meta_v1.ListOptions) (runtime.Object, error) {
-				return jxClient.JenkinsV1().Users(ns).List(lo)
+			resource.New().(runtime.Object),
+			time.Minute*10,
+			cache.ResourceEventHandlerFuncs{
+				AddFunc: func(obj interface{}) {
+					o.onResourceChange(obj, resource.Resource, ns, dir)
+				},
+				UpdateFunc: func(oldObj, newObj interface{}) {
+					o.onResourceChange(newObj, resource.Resource, ns, dir)
+				},
 			},
-			WatchFunc: func(lo meta_v1.ListOptions) (watch.Interface, error) {
-				return jxClient.JenkinsV1().Users(ns).Watch(lo)
-			},
-		},
-		&v1.User{},
-		time.Minute*10,
-		cache.ResourceEventHandlerFuncs{
-			AddFunc: func(obj interface{}) {
-				o.onUserChange(obj, ns, dir)
-			},
-			UpdateFunc: func(oldObj, newObj interface{}) {
-				o.onUserChange(newObj, ns, dir)
-			},
-			DeleteFunc: func(obj interface{}) {
-				//o.onEnvironmentChange(obj, jxClient, ns)
-			},
-		},
-	)
-
-	go userController.Run(stop)
+		)
+		go controller.Run(stop)
+	}
 
 	// Wait forever
 	select {}
 }
 
-func (o *ControllerBackupOptions) onEnvironmentChange(obj interface{}, ns string, dir string) {
-	env, ok := obj.(*v1.Environment)
-	if !ok {
-		log.Infof("Object is not a Environment %#v\n", obj)
-		return
-	}
-	o.writeResourceToBackupFile(env, "environment", env.ObjectMeta.Name, ns, dir)
-}
-
-func (o *ControllerBackupOptions) onTeamChange(obj interface{}, ns string, dir string) {
-	env, ok := obj.(*v1.Team)
+func (o *ControllerBackupOptions) onResourceChange(obj interface{}, resource string, ns string, dir string) {
+	object, ok := obj.(meta_v1.Object)
 	if !ok {
-		log.Infof("Object is not a Team %#v\n", obj)
+		log.Infof("Object is not a %s %#v\n", resource, obj)
 		return
 	}
-	o.writeResourceToBackupFile(env, "team", env.ObjectMeta.Name, ns, dir)
-}
+	o.writeResourceToBackupFile(obj, resource, object.GetName(), ns, dir)
 
-func (o *ControllerBackupOptions) onUserChange(obj interface{}, ns string, dir string) {
-	env, ok := obj.(*v1.User)
-	if !ok {
-		log.Infof("Object is not a User %#v\n", obj)
-		return
+	// lets also backup the team settings separately so they can be restored onto an existing dev environment
+	env, ok := obj.(*v1.Environment)
+	if ok && env.Spec.Kind == v1.EnvironmentKindTypeDevelopment {
+		o.writeResourceToBackupFile(&env.Spec.TeamSettings, backupResourceTeamSettings, env.Name, ns, dir)
 	}
-	o.writeResourceToBackupFile(env, "user", env.ObjectMeta.Name, ns, dir)
 }
 
 func (o *ControllerBackupOptions) writeResourceToBackupFile(obj interface{}, resource string, key string, ns string, dir string) {
@@ -217,7 +147,7 @@ func (o *ControllerBackupOptions) writeResourceToBackupFile(obj interface{}, res
 	o.Debugf("Dumping %s with key %s...\n", util.ColorInfo(resource), util.ColorInfo(key))
 	o.Debugf("%s\n", string(out))
 
-	nsDir := path.Join(dir, fmt.Sprintf("%ss", resource), ns)
+	nsDir := path.Join(dir, backupDirName(resource), ns)
 	err = os.MkdirAll(nsDir, os.FileMode(0755))
 	if err != nil {
 		log.Errorf("Unable to create directory %s\n", err)
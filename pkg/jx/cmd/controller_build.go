@@ -4,7 +4,9 @@ import (
 	"io"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
@@ -13,12 +15,14 @@ import (
 	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/storage"
 	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/jenkins-x/jx/pkg/kube"
@@ -28,9 +32,26 @@ import (
 type ControllerBuildOptions struct {
 	ControllerOptions
 
-	Namespace string
+	Namespace   string
+	ArchiveLogs bool
+	Store       BuildLogsStoreFlags
+
+	store        storage.Store
+	archiveQueue chan buildLogArchive
+	archiveLock  sync.Mutex
+	archiving    map[string]bool
+}
+
+// buildLogArchive a completed build whose log is waiting to be archived
+type buildLogArchive struct {
+	activity string
+	pod      *corev1.Pod
 }
 
+// buildLogArchiveQueueSize how many completed builds can wait to have their logs archived. Builds which do not fit
+// are archived when the informer resyncs
+const buildLogArchiveQueueSize = 100
+
 // NewCmdControllerBuild creates a command object for the generic "get" action, which
 // retrieves one or more resources from a server.
 func NewCmdControllerBuild(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
@@ -58,6 +79,8 @@ func NewCmdControllerBuild(f Factory, in terminal.FileReader, out terminal.FileW
 	}
 
 	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace to watch or defaults to the current namespace")
+	cmd.Flags().BoolVarP(&options.ArchiveLogs, "archive-logs", "", false, "Archives the log of each build to long term storage when it completes")
+	options.Store.addFlags(cmd)
 	return cmd
 }
 
@@ -82,10 +105,31 @@ func (o *ControllerBuildOptions) Run() error {
 		return err
 	}
 
+	if o.ArchiveLogs {
+		o.store, err = o.createBuildLogsStore(&o.Store)
+		if err != nil {
+			return err
+		}
+	}
+
 	ns := o.Namespace
 	if ns == "" {
 		ns = devNs
 	}
+	if o.store != nil {
+		// the logs are archived by a worker so that slow stores such as git do not block the processing of pod events
+		o.archiveQueue = make(chan buildLogArchive, buildLogArchiveQueueSize)
+		o.archiving = map[string]bool{}
+		go o.archiveBuildLogs(client, jxClient, ns)
+
+		devEnv, err := kube.GetEnrichedDevEnvironment(client, jxClient, devNs)
+		if err != nil {
+			return err
+		}
+		if devEnv.Spec.WebHookEngine != v1.WebHookEngineProw {
+			o.watchJenkinsBuilds(jxClient, ns)
+		}
+	}
 	pod := &corev1.Pod{}
 	log.Infof("Watching for Knative build pods in namespace %s\n", util.ColorInfo(ns))
 	listWatch := cache.NewListWatchFromClient(client.CoreV1().RESTClient(), "pods", ns, fields.Everything())
@@ -96,10 +140,10 @@ func (o *ControllerBuildOptions) Run() error {
 		time.Minute*10,
 		cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
-				o.onPod(obj, jxClient, ns)
+				o.onPod(obj, client, jxClient, ns)
 			},
 			UpdateFunc: func(oldObj, newObj interface{}) {
-				o.onPod(newObj, jxClient, ns)
+				o.onPod(newObj, client, jxClient, ns)
 			},
 			DeleteFunc: func(obj interface{}) {
 			},
@@ -113,7 +157,7 @@ func (o *ControllerBuildOptions) Run() error {
 	select {}
 }
 
-func (o *ControllerBuildOptions) onPod(obj interface{}, jxClient versioned.Interface, ns string) {
+func (o *ControllerBuildOptions) onPod(obj interface{}, kubeClient kubernetes.Interface, jxClient versioned.Interface, ns string) {
 	pod, ok := obj.(*corev1.Pod)
 	if !ok {
 		log.Infof("Object is not a Pod %#v\n", obj)
@@ -142,10 +186,15 @@ func (o *ControllerBuildOptions) onPod(obj interface{}, jxClient versioned.Inter
 					}
 
 					if o.updatePipelineActivity(a, buildName, pod) {
-						_, err := activities.Update(a)
+						updated, err := activities.Update(a)
 						if err != nil {
 							log.Warnf("Failed to update PipelineActivities%s: %s\n", a.Name, err)
+							return
 						}
+						a = updated
+					}
+					if o.store != nil && a.Spec.BuildLogsURL == "" && isCompletedActivity(a) {
+						o.queueBuildLogArchive(a.Name, pod)
 					}
 				}
 			}
@@ -342,6 +391,120 @@ func (o *ControllerBuildOptions) updatePipelineActivity(activity *v1.PipelineAct
 	return !reflect.DeepEqual(&copy, activity)
 }
 
+// queueBuildLogArchive queues the archiving of the log of the completed build unless it is already queued
+func (o *ControllerBuildOptions) queueBuildLogArchive(activity string, pod *corev1.Pod) {
+	o.archiveLock.Lock()
+	defer o.archiveLock.Unlock()
+	if o.archiving[activity] {
+		return
+	}
+	select {
+	case o.archiveQueue <- buildLogArchive{activity: activity, pod: pod}:
+		o.archiving[activity] = true
+	default:
+		log.Warnf("Too many build logs are waiting to be archived so the log of %s will be archived later\n", activity)
+	}
+}
+
+// watchJenkinsBuilds queues the archiving of the log of each Jenkins build once its PipelineActivity completes
+func (o *ControllerBuildOptions) watchJenkinsBuilds(jxClient versioned.Interface, ns string) {
+	log.Infof("Watching for completed Jenkins builds in namespace %s to archive their logs\n", util.ColorInfo(ns))
+	activity := &v1.PipelineActivity{}
+	listWatch := cache.NewListWatchFromClient(jxClient.JenkinsV1().RESTClient(), "pipelineactivities", ns, fields.Everything())
+	kube.SortListWatchByName(listWatch)
+	_, controller := cache.NewInformer(
+		listWatch,
+		activity,
+		time.Minute*10,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				o.onJenkinsActivity(obj)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				o.onJenkinsActivity(newObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+			},
+		},
+	)
+	stop := make(chan struct{})
+	go controller.Run(stop)
+}
+
+func (o *ControllerBuildOptions) onJenkinsActivity(obj interface{}) {
+	activity, ok := obj.(*v1.PipelineActivity)
+	if !ok {
+		log.Infof("Object is not a PipelineActivity %#v\n", obj)
+		return
+	}
+	if activity.Spec.BuildLogsURL == "" && isCompletedActivity(activity) {
+		o.queueBuildLogArchive(activity.Name, nil)
+	}
+}
+
+// archiveBuildLogs archives the logs of the queued builds one at a time
+func (o *ControllerBuildOptions) archiveBuildLogs(kubeClient kubernetes.Interface, jxClient versioned.Interface, ns string) {
+	for item := range o.archiveQueue {
+		activity, err := jxClient.JenkinsV1().PipelineActivities(ns).Get(item.activity, metav1.GetOptions{})
+		if err != nil {
+			log.Warnf("Failed to find PipelineActivity %s to archive its build log: %s\n", item.activity, err)
+		} else if activity.Spec.BuildLogsURL == "" {
+			if item.pod != nil {
+				o.archivePodLog(kubeClient, jxClient, ns, activity, item.pod)
+			} else {
+				o.archiveJenkinsLog(jxClient, ns, activity)
+			}
+		}
+		o.archiveLock.Lock()
+		delete(o.archiving, item.activity)
+		o.archiveLock.Unlock()
+	}
+}
+
+// archivePodLog archives the log of the completed build pod of the activity
+func (o *ControllerBuildOptions) archivePodLog(kubeClient kubernetes.Interface, jxClient versioned.Interface, ns string, activity *v1.PipelineActivity, pod *corev1.Pod) {
+	data, err := o.getBuildPodLog(kubeClient, ns, pod)
+	if err != nil {
+		log.Warnf("Failed to get the log of build pod %s: %s\n", pod.Name, err)
+		return
+	}
+	err = o.archiveBuildLog(jxClient, ns, activity, data, o.store)
+	if err != nil {
+		log.Warnf("%s\n", err)
+		return
+	}
+	log.Infof("Archived the log of build pod %s to %s\n", pod.Name, util.ColorInfo(activity.Spec.BuildLogsURL))
+}
+
+// archiveJenkinsLog archives the complete console log of the Jenkins build of the completed activity
+func (o *ControllerBuildOptions) archiveJenkinsLog(jxClient versioned.Interface, ns string, activity *v1.PipelineActivity) {
+	build, err := strconv.Atoi(activity.Spec.Build)
+	if err != nil {
+		log.Warnf("PipelineActivity %s has an invalid build number %s\n", activity.Name, activity.Spec.Build)
+		return
+	}
+	data, completed, err := o.getJenkinsBuildLog(activity.Spec.Pipeline, build)
+	if err != nil {
+		log.Warnf("Failed to get the log of Jenkins build %s #%d: %s\n", activity.Spec.Pipeline, build, err)
+		return
+	}
+	if !completed {
+		// the log is archived when the informer resyncs once Jenkins has finished the build
+		return
+	}
+	err = o.archiveBuildLog(jxClient, ns, activity, data, o.store)
+	if err != nil {
+		log.Warnf("%s\n", err)
+		return
+	}
+	log.Infof("Archived the log of Jenkins build %s #%d to %s\n", activity.Spec.Pipeline, build, util.ColorInfo(activity.Spec.BuildLogsURL))
+}
+
+func isCompletedActivity(activity *v1.PipelineActivity) bool {
+	status := activity.Spec.Status
+	return status == v1.ActivityStatusTypeSucceeded || status == v1.ActivityStatusTypeFailed
+}
+
 // createStepDescription uses the spec of the init container to return a description
 func createStepDescription(initContainerName string, pod *corev1.Pod) string {
 	for _, c := range pod.Spec.InitContainers {
@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/chats"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/notify"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ControllerNotifyOptions are the flags for the commands
+type ControllerNotifyOptions struct {
+	ControllerOptions
+
+	Namespace        string
+	ChatKind         string
+	ChatURL          string
+	DeveloperChannel string
+	UserChannel      string
+	RateLimit        time.Duration
+	ConfigRefresh    time.Duration
+	GitBranch        string
+
+	// calculated fields
+	startTime      time.Time
+	tracker        *notify.Tracker
+	lock           sync.Mutex
+	projectConfigs map[string]*notifyProjectConfig
+	chatProviders  map[string]chats.ChatProvider
+}
+
+type notifyProjectConfig struct {
+	chat   *config.ChatConfig
+	loaded time.Time
+}
+
+var (
+	controllerNotifyLong = templates.LongDesc(`
+		Runs the notify controller which posts build failures, releases, promotion Pull Requests and preview environments
+		to the chat channels of each project.
+
+		The chat server and channels are read from the 'chat' section of the jenkins-x.yml file of the project. The
+		'notifications' rules in that section decide which kinds of notification go to which channel. Projects which
+		do not configure a chat server use the chat server and channels specified on the command line.
+
+		The kinds of notification are: buildFailed, release, promotePullRequest and preview
+
+`)
+
+	controllerNotifyExample = templates.Examples(`
+		# post notifications for projects which do not configure chat to a Slack channel
+		jx controller notify --kind slack --url https://myorg.slack.com --channel "#dev"
+
+		# only notify each pipeline's failures at most once every 30 minutes
+		jx controller notify --rate-limit 30m
+	`)
+)
+
+// NewCmdControllerNotify creates a command object for the "controller notify" action
+func NewCmdControllerNotify(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ControllerNotifyOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "notify",
+		Short:   "Runs the controller which posts pipeline and release notifications to chat",
+		Long:    controllerNotifyLong,
+		Example: controllerNotifyExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+		Aliases: []string{"notifications"},
+	}
+
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace to watch or defaults to the current namespace")
+	cmd.Flags().StringVarP(&options.ChatKind, "kind", "k", "", "The kind of the default chat server for projects which do not configure chat. One of: "+fmt.Sprintf("%v", chats.ChatKinds))
+	cmd.Flags().StringVarP(&options.ChatURL, "url", "u", "", "The URL of the default chat server for projects which do not configure chat")
+	cmd.Flags().StringVarP(&options.DeveloperChannel, "channel", "c", "", "The default developer channel for projects which do not configure chat")
+	cmd.Flags().StringVarP(&options.UserChannel, "user-channel", "", "", "The default user channel for projects which do not configure chat")
+	cmd.Flags().DurationVarP(&options.RateLimit, "rate-limit", "", time.Hour, "The minimum time between notifications of the failures of the same pipeline")
+	cmd.Flags().DurationVarP(&options.ConfigRefresh, "config-refresh", "", 10*time.Minute, "How often the jenkins-x.yml of each project is reloaded")
+	cmd.Flags().StringVarP(&options.GitBranch, "branch", "b", "master", "The git branch the jenkins-x.yml of each project is loaded from")
+	return cmd
+}
+
+// Run implements this command
+func (o *ControllerNotifyOptions) Run() error {
+	if o.ChatKind != "" && util.StringArrayIndex(chats.ChatKinds, o.ChatKind) < 0 {
+		return util.InvalidOption("kind", o.ChatKind, chats.ChatKinds)
+	}
+	// there is no terminal to ask for chat credentials
+	o.BatchMode = true
+
+	apisClient, err := o.CreateApiExtensionsClient()
+	if err != nil {
+		return err
+	}
+	err = kube.RegisterPipelineActivityCRD(apisClient)
+	if err != nil {
+		return err
+	}
+	err = kube.RegisterReleaseCRD(apisClient)
+	if err != nil {
+		return err
+	}
+
+	jxClient, devNs, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	ns := o.Namespace
+	if ns == "" {
+		ns = devNs
+	}
+
+	o.startTime = time.Now()
+	o.tracker = notify.NewTracker(o.RateLimit)
+	o.projectConfigs = map[string]*notifyProjectConfig{}
+	o.chatProviders = map[string]chats.ChatProvider{}
+
+	log.Infof("Watching for PipelineActivity and Release resources in namespace %s\n", util.ColorInfo(ns))
+	activity := &v1.PipelineActivity{}
+	activityListWatch := cache.NewListWatchFromClient(jxClient.JenkinsV1().RESTClient(), "pipelineactivities", ns, fields.Everything())
+	kube.SortListWatchByName(activityListWatch)
+	_, activityController := cache.NewInformer(
+		activityListWatch,
+		activity,
+		time.Minute*10,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				o.onActivity(obj)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				o.onActivity(newObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+			},
+		},
+	)
+	stop := make(chan struct{})
+	go activityController.Run(stop)
+
+	release := &v1.Release{}
+	releaseListWatch := cache.NewListWatchFromClient(jxClient.JenkinsV1().RESTClient(), "releases", ns, fields.Everything())
+	kube.SortListWatchByName(releaseListWatch)
+	_, releaseController := cache.NewInformer(
+		releaseListWatch,
+		release,
+		time.Minute*10,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				o.onRelease(obj)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				o.onRelease(newObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+			},
+		},
+	)
+	go releaseController.Run(stop)
+
+	// Wait forever
+	select {}
+}
+
+func (o *ControllerNotifyOptions) onActivity(obj interface{}) {
+	activity, ok := obj.(*v1.PipelineActivity)
+	if !ok {
+		log.Infof("Object is not a PipelineActivity %#v\n", obj)
+		return
+	}
+	gitURL := activity.Spec.GitURL
+	for _, event := range notify.ActivityEvents(activity) {
+		o.notify(event, gitURL)
+	}
+}
+
+func (o *ControllerNotifyOptions) onRelease(obj interface{}) {
+	release, ok := obj.(*v1.Release)
+	if !ok {
+		log.Infof("Object is not a Release %#v\n", obj)
+		return
+	}
+	gitURL := release.Spec.GitCloneURL
+	if gitURL == "" {
+		gitURL = release.Spec.GitHTTPURL
+	}
+	o.notify(notify.ReleaseEvent(release), gitURL)
+}
+
+// notify posts the event to the channels of the project unless it happened before the controller started or has
+// already been posted
+func (o *ControllerNotifyOptions) notify(event *notify.Event, gitURL string) {
+	if !event.Time.IsZero() && event.Time.Before(o.startTime) {
+		return
+	}
+	chatConfig := o.chatConfig(gitURL)
+	channels := notify.Channels(chatConfig, event.Kind)
+	if len(channels) == 0 || chatConfig.URL == "" {
+		return
+	}
+	post, suppressed := o.tracker.Accept(event)
+	if !post {
+		return
+	}
+	message := event.Message
+	if suppressed > 0 {
+		message += fmt.Sprintf("\n(%d earlier failures of this pipeline were not notified)", suppressed)
+	}
+
+	provider, err := o.chatProvider(chatConfig)
+	if err != nil {
+		log.Warnf("Failed to create the chat provider for %s: %s\n", chatConfig.URL, err)
+		o.tracker.Forget(event, suppressed)
+		return
+	}
+	posted := false
+	for _, channel := range channels {
+		_, err = provider.PostMessage(channel, message)
+		if err != nil {
+			log.Warnf("Failed to post %s notification to channel %s: %s\n", event.Kind, channel, err)
+			continue
+		}
+		posted = true
+		log.Infof("Posted %s notification for %s/%s to channel %s\n", event.Kind, event.Owner, event.Repository, util.ColorInfo(channel))
+	}
+	if !posted {
+		// lets try again the next time the event is seen
+		o.tracker.Forget(event, suppressed)
+	}
+}
+
+// chatConfig returns the chat configuration of the project with the given git URL or the default configuration
+// if the project does not configure a chat server
+func (o *ControllerNotifyOptions) chatConfig(gitURL string) *config.ChatConfig {
+	defaultConfig := &config.ChatConfig{
+		Kind:             o.ChatKind,
+		URL:              o.ChatURL,
+		DeveloperChannel: o.DeveloperChannel,
+		UserChannel:      o.UserChannel,
+	}
+	if gitURL == "" {
+		return defaultConfig
+	}
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	cached := o.projectConfigs[gitURL]
+	if cached == nil || time.Since(cached.loaded) > o.ConfigRefresh {
+		chat, err := o.loadProjectChatConfig(gitURL)
+		if err != nil {
+			log.Warnf("Failed to load the jenkins-x.yml of %s: %s\n", gitURL, err)
+			if cached != nil {
+				// lets keep using the last configuration we loaded
+				return o.mergeChatConfig(cached.chat, defaultConfig)
+			}
+		}
+		cached = &notifyProjectConfig{
+			chat:   chat,
+			loaded: time.Now(),
+		}
+		o.projectConfigs[gitURL] = cached
+	}
+	return o.mergeChatConfig(cached.chat, defaultConfig)
+}
+
+func (o *ControllerNotifyOptions) mergeChatConfig(chat *config.ChatConfig, defaultConfig *config.ChatConfig) *config.ChatConfig {
+	if chat == nil {
+		return defaultConfig
+	}
+	if chat.URL == "" {
+		answer := *chat
+		answer.Kind = defaultConfig.Kind
+		answer.URL = defaultConfig.URL
+		if answer.DeveloperChannel == "" {
+			answer.DeveloperChannel = defaultConfig.DeveloperChannel
+		}
+		if answer.UserChannel == "" {
+			answer.UserChannel = defaultConfig.UserChannel
+		}
+		return &answer
+	}
+	return chat
+}
+
+// loadProjectChatConfig loads the chat configuration from the jenkins-x.yml in the git repository of the project
+func (o *ControllerNotifyOptions) loadProjectChatConfig(gitURL string) (*config.ChatConfig, error) {
+	dir, err := ioutil.TempDir("", "jx-controller-notify-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	err = o.Git().ShallowCloneBranch(gitURL, o.GitBranch, dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cloning branch %s", o.GitBranch)
+	}
+	projectConfig, _, err := config.LoadProjectConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+	return projectConfig.Chat, nil
+}
+
+func (o *ControllerNotifyOptions) chatProvider(chatConfig *config.ChatConfig) (chats.ChatProvider, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	provider := o.chatProviders[chatConfig.URL]
+	if provider != nil {
+		return provider, nil
+	}
+	provider, err := o.createChatProvider(chatConfig)
+	if err != nil {
+		return nil, err
+	}
+	if provider == nil {
+		return nil, fmt.Errorf("no chat server is configured")
+	}
+	o.chatProviders[chatConfig.URL] = provider
+	return provider, nil
+}
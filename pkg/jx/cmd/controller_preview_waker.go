@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	previewWakerRetrySeconds = 10
+)
+
+// ControllerPreviewWakerOptions are the flags for the commands
+type ControllerPreviewWakerOptions struct {
+	ControllerOptions
+
+	Port int
+	Path string
+
+	// calculated fields
+	kubeClient kubernetes.Interface
+	jxClient   versioned.Interface
+	devNs      string
+	lock       sync.Mutex
+}
+
+var (
+	controllerPreviewWakerLong = templates.LongDesc(`
+		Runs the controller which wakes up preview environments which were scaled down to zero while idle.
+
+		When 'jx gc previews' scales a preview down it points the ingresses of the preview at this controller via
+		the 'previewLifecycle.wakerService' team setting. The next request to the preview then scales its deployments
+		back up, restores its ingresses and shows a page which refreshes until the preview is running again.
+
+		The preview is found from the host of the request which must be the host of one of the ingresses of a
+		preview environment.
+
+`)
+
+	controllerPreviewWakerExample = templates.Examples(`
+		# run the preview waker on port 8080
+		jx controller preview-waker
+	`)
+)
+
+// NewCmdControllerPreviewWaker creates a command object for the "controller preview-waker" action
+func NewCmdControllerPreviewWaker(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ControllerPreviewWakerOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "preview-waker",
+		Short:   "Runs the controller which wakes up idle preview environments on the next request",
+		Long:    controllerPreviewWakerLong,
+		Example: controllerPreviewWakerExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+		Aliases: []string{"waker"},
+	}
+
+	cmd.Flags().IntVarP(&options.Port, "port", "p", 8080, "The port to listen on")
+	cmd.Flags().StringVarP(&options.Path, "health-path", "", "/healthz", "The path of the health check")
+	return cmd
+}
+
+// Run implements this command
+func (o *ControllerPreviewWakerOptions) Run() error {
+	var err error
+	o.kubeClient, _, err = o.KubeClient()
+	if err != nil {
+		return err
+	}
+	o.jxClient, o.devNs, err = o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(o.Path, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "OK")
+	})
+	mux.HandleFunc("/", o.handleRequest)
+
+	log.Infof("Waking up preview environments in namespace %s on port %s\n", util.ColorInfo(o.devNs), util.ColorInfo(o.Port))
+	return http.ListenAndServe(fmt.Sprintf(":%d", o.Port), mux)
+}
+
+func (o *ControllerPreviewWakerOptions) handleRequest(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "" {
+		http.Error(w, "no host", http.StatusBadRequest)
+		return
+	}
+	name, err := o.wake(host)
+	if err != nil {
+		log.Warnf("Failed to wake up the preview environment of host %s: %s\n", host, err)
+		http.Error(w, "failed to wake up the preview environment", http.StatusInternalServerError)
+		return
+	}
+	if name == "" {
+		http.Error(w, "no preview environment found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Retry-After", fmt.Sprintf("%d", previewWakerRetrySeconds))
+	w.WriteHeader(http.StatusServiceUnavailable)
+	fmt.Fprintf(w, `<html><head><meta http-equiv="refresh" content="%d"><title>Waking up %s</title></head>
+<body><p>The preview environment <b>%s</b> was idle and is waking up. This page will refresh when it is ready.</p></body></html>
+`, previewWakerRetrySeconds, html.EscapeString(name), html.EscapeString(name))
+}
+
+// wake wakes up the preview environment with an ingress for the host returning its name or an empty string if
+// there is no such preview environment
+func (o *ControllerPreviewWakerOptions) wake(host string) (string, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	envs, err := o.jxClient.JenkinsV1().Environments(o.devNs).List(metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for i := range envs.Items {
+		env := &envs.Items[i]
+		if !kube.IsPreviewEnvironment(env) || env.Spec.Namespace == "" {
+			continue
+		}
+		found, err := kube.HasIngressForHost(o.kubeClient, env.Spec.Namespace, host)
+		if err != nil {
+			return "", err
+		}
+		if !found {
+			continue
+		}
+		if kube.IsPreviewScaledDown(env) {
+			err = o.wakePreview(o.kubeClient, o.jxClient, o.devNs, env)
+			if err != nil {
+				return "", err
+			}
+		}
+		return env.Name, nil
+	}
+	return "", nil
+}
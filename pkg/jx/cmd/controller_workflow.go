@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
@@ -19,14 +20,18 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/jenkins-x/jx/pkg/kube"
 )
 
+const defaultVerifyStepTimeout = "10m"
+
 // ControllerWorkflowOptions are the flags for the commands
 type ControllerWorkflowOptions struct {
 	ControllerOptions
@@ -46,6 +51,8 @@ type ControllerWorkflowOptions struct {
 	PullRequestPollDuration *time.Duration
 	workflowMap             map[string]*v1.Workflow
 	pipelineMap             map[string]*v1.PipelineActivity
+	rolloutLock             sync.Mutex
+	rollouts                map[string]bool
 }
 
 // NewCmdControllerWorkflow creates a command object for the generic "get" action, which
@@ -163,6 +170,9 @@ func (o *ControllerWorkflowOptions) Run() error {
 
 	go pipelineController.Run(stop)
 
+	// lets resume any rollouts which were in progress when the controller last stopped
+	o.ReloadAndPollGitPipelineStatuses(jxClient, ns)
+
 	ticker := time.NewTicker(*o.PullRequestPollDuration)
 	go func() {
 		for t := range ticker.C {
@@ -171,6 +181,7 @@ func (o *ControllerWorkflowOptions) Run() error {
 			}
 			//o.pollGitPipelineStatuses(jxClient, ns)
 			o.ReloadAndPollGitPipelineStatuses(jxClient, ns)
+			o.pollRunningWorkflowSteps(jxClient, ns)
 		}
 	}()
 
@@ -313,7 +324,14 @@ func (o *ControllerWorkflowOptions) onActivity(pipeline *v1.PipelineActivity, jx
 		promoteStatusMap := createPromoteStatus(pipeline)
 
 		allStepsComplete := true
-		for _, step := range flow.Spec.Steps {
+		failedSteps := []string{}
+		for i := range flow.Spec.Steps {
+			step := &flow.Spec.Steps[i]
+			stepName := workflow.StepName(flow, step)
+			current := workflow.FindWorkflowActivityStep(pipeline, stepName)
+			if current != nil && current.Status == v1.ActivityStatusTypeSkipped {
+				continue
+			}
 			promote := step.Promote
 			if promote != nil {
 				envName := promote.Environment
@@ -322,7 +340,10 @@ func (o *ControllerWorkflowOptions) onActivity(pipeline *v1.PipelineActivity, jx
 					if status == nil || status.PullRequest == nil || status.PullRequest.PullRequestURL == "" {
 						allStepsComplete = false
 						// can we generate a PR now?
-						if canExecuteStep(flow, pipeline, &step, promoteStatusMap, envName) {
+						if canExecuteStep(flow, pipeline, step, promoteStatusMap, envName) {
+							if o.skipStepIfConditionsNotMet(flow, pipeline, step, activities) {
+								continue
+							}
 							log.Infof("Creating PR for environment %s from PipelineActivity %s as current status is %#v\n", envName, pipeline.Name, status)
 							po := o.createPromoteOptions(repoName, envName, pipelineName, build, version)
 
@@ -336,7 +357,30 @@ func (o *ControllerWorkflowOptions) onActivity(pipeline *v1.PipelineActivity, jx
 						allStepsComplete = false
 					}
 				}
+			} else {
+				// the other steps are started as soon as their preconditions are met so that
+				// steps which do not depend on each other run in parallel
+				status := o.processWorkflowStep(flow, pipeline, step, activities, ns)
+				if status == v1.ActivityStatusTypeFailed || status == v1.ActivityStatusTypeError {
+					failedSteps = append(failedSteps, stepName)
+				}
+				if status != v1.ActivityStatusTypeSucceeded && status != v1.ActivityStatusTypeSkipped {
+					allStepsComplete = false
+				}
+			}
+		}
+		if len(failedSteps) > 0 {
+			message := fmt.Sprintf("Workflow steps failed: %s", strings.Join(failedSteps, ", "))
+			err := o.modifyLatestPipeline(activities, pipeline.Name, func(activity *v1.PipelineActivity) bool {
+				activity.Spec.Status = v1.ActivityStatusTypeFailed
+				activity.Spec.WorkflowStatus = v1.ActivityStatusTypeFailed
+				activity.Spec.WorkflowMessage = message
+				return true
+			})
+			if err != nil {
+				log.Warnf("Failed to update PipelineActivity %s due to failed steps: %s\n", pipeline.Name, err)
 			}
+			return
 		}
 		if allStepsComplete && (pipeline.Spec.Status != v1.ActivityStatusTypeSucceeded || pipeline.Spec.WorkflowStatus != v1.ActivityStatusTypeSucceeded) {
 			pipeline.Spec.Status = v1.ActivityStatusTypeSucceeded
@@ -580,26 +624,20 @@ func (o *ControllerWorkflowOptions) pollGitStatusforPipeline(activity *v1.Pipeli
 									}
 								}
 								if succeeded {
-									gitURL := activity.Spec.GitURL
-									if gitURL == "" {
-										log.Warnf("No git URL for PipelineActivity %s so cannot comment on issues\n", activity.Name)
-										return
-									}
-									gitInfo, err := gits.ParseGitURL(gitURL)
-									if err != nil {
-										log.Warnf("Failed to parse Git URL %s for PipelineActivity %s so cannot comment on issues: %s", gitURL, activity.Name, err)
-										return
-									}
-									po.GitInfo = gitInfo
-									err = po.commentOnIssues(ns, env, promoteKey)
-									if err != nil {
-										log.Warnf("Failed to comment on issues: %s", err)
+									if len(promote.Rollout) > 0 {
+										// the Pull Request started a rollout so lets shift the rest of the traffic in the background.
+										// A rollout which was in progress when the controller restarted is resumed
+										first := promote.Rollout[0]
+										po.Activities = activities
+										if first.Status == v1.ActivityStatusTypePending {
+											po.updateRolloutStep(promoteKey, first.Weight, v1.ActivityStatusTypeRunning, "")
+											o.startRollout(po, activity, activities, env, ns, promoteKey, first.Weight)
+										} else if weight, ok := kube.RolloutResumeWeight(promote); ok {
+											o.startRollout(po, activity, activities, env, ns, promoteKey, weight)
+										}
 										return
 									}
-									err = promoteKey.OnPromoteUpdate(activities, kube.CompletePromotionUpdate)
-									if err != nil {
-										log.Warnf("Failed to update PipelineActivity on promotion completion: %s", err)
-									}
+									o.completePromotion(po, activity, activities, env, ns, promoteKey)
 									return
 								}
 							}
@@ -652,6 +690,75 @@ func (o *ControllerWorkflowOptions) pollGitStatusforPipeline(activity *v1.Pipeli
 	}
 }
 
+// completePromotion comments on the issues fixed by the promoted version and marks the promotion as complete
+func (o *ControllerWorkflowOptions) completePromotion(po *PromoteOptions, activity *v1.PipelineActivity, activities typev1.PipelineActivityInterface, env *v1.Environment, ns string, promoteKey *kube.PromoteStepActivityKey) {
+	gitURL := activity.Spec.GitURL
+	if gitURL == "" {
+		log.Warnf("No git URL for PipelineActivity %s so cannot comment on issues\n", activity.Name)
+		return
+	}
+	gitInfo, err := gits.ParseGitURL(gitURL)
+	if err != nil {
+		log.Warnf("Failed to parse Git URL %s for PipelineActivity %s so cannot comment on issues: %s", gitURL, activity.Name, err)
+		return
+	}
+	po.GitInfo = gitInfo
+	err = po.commentOnIssues(ns, env, promoteKey)
+	if err != nil {
+		log.Warnf("Failed to comment on issues: %s", err)
+		return
+	}
+	err = promoteKey.OnPromoteUpdate(activities, kube.CompletePromotionUpdate)
+	if err != nil {
+		log.Warnf("Failed to update PipelineActivity on promotion completion: %s", err)
+	}
+}
+
+// startRollout continues the rollout of the promotion in the background unless it is already running
+func (o *ControllerWorkflowOptions) startRollout(po *PromoteOptions, activity *v1.PipelineActivity, activities typev1.PipelineActivityInterface, env *v1.Environment, ns string, promoteKey *kube.PromoteStepActivityKey, weight int32) {
+	key := activity.Name + "/" + env.Name
+	o.rolloutLock.Lock()
+	defer o.rolloutLock.Unlock()
+	if o.rollouts == nil {
+		o.rollouts = map[string]bool{}
+	}
+	if o.rollouts[key] {
+		return
+	}
+	o.rollouts[key] = true
+	log.Infof("Continuing the rollout of PipelineActivity %s to Environment %s from %d%% of traffic\n", util.ColorInfo(activity.Name), util.ColorInfo(env.Name), weight)
+	go func() {
+		defer func() {
+			o.rolloutLock.Lock()
+			delete(o.rollouts, key)
+			o.rolloutLock.Unlock()
+		}()
+		o.continueRollout(po, activity, activities, env, ns, promoteKey, weight)
+	}()
+}
+
+// continueRollout shifts the rest of the traffic of a rollout started by a promotion Pull Request to the new version
+// and then completes the promotion
+func (o *ControllerWorkflowOptions) continueRollout(po *PromoteOptions, activity *v1.PipelineActivity, activities typev1.PipelineActivityInterface, env *v1.Environment, ns string, promoteKey *kube.PromoteStepActivityKey, weight int32) {
+	releaseInfo := o.createReleaseInfo(activity, env)
+	if releaseInfo == nil {
+		return
+	}
+	releaseInfo.RolloutWeight = weight
+	if po.PullRequestPollDuration == nil {
+		duration := defaultRolloutPullRequestPollDuration
+		po.PullRequestPollDuration = &duration
+	}
+	end := time.Now().Add(defaultRolloutTimeout)
+	err := po.continueRolloutViaPullRequests(env.Spec.Namespace, env, releaseInfo, end, promoteKey)
+	if err != nil {
+		log.Warnf("Rollout of %s to Environment %s failed: %s\n", activity.Name, env.Name, err)
+		promoteKey.OnPromoteUpdate(activities, kube.FailedPromotionUpdate)
+		return
+	}
+	o.completePromotion(po, activity, activities, env, ns, promoteKey)
+}
+
 func (o *ControllerWorkflowOptions) createReleaseInfo(activity *v1.PipelineActivity, env *v1.Environment) *ReleaseInfo {
 	spec := &activity.Spec
 	app := activity.RepositoryName()
@@ -674,7 +781,7 @@ func (o *ControllerWorkflowOptions) createReleaseInfo(activity *v1.PipelineActiv
 	}
 }
 
-func canExecuteStep(workflow *v1.Workflow, activity *v1.PipelineActivity, step *v1.WorkflowStep, statusMap map[string]*v1.PromoteActivityStep, promoteToEnv string) bool {
+func canExecuteStep(flow *v1.Workflow, activity *v1.PipelineActivity, step *v1.WorkflowStep, statusMap map[string]*v1.PromoteActivityStep, promoteToEnv string) bool {
 	for _, envName := range step.Preconditions.Environments {
 		status := statusMap[envName]
 		if status == nil {
@@ -686,9 +793,283 @@ func canExecuteStep(workflow *v1.Workflow, activity *v1.PipelineActivity, step *
 			return false
 		}
 	}
+	for _, name := range step.Preconditions.Steps {
+		if !workflow.IsStepCompleted(flow, activity, name) {
+			log.Warnf("Cannot promote to Environment: %s as precondition step: %s has status %s\n", promoteToEnv, name, string(workflow.StepStatus(flow, activity, name)))
+			return false
+		}
+	}
+	return true
+}
+
+// processWorkflowStep starts or polls a step of the workflow which is not a promotion returning its current status
+func (o *ControllerWorkflowOptions) processWorkflowStep(flow *v1.Workflow, pipeline *v1.PipelineActivity, step *v1.WorkflowStep, activities typev1.PipelineActivityInterface, ns string) v1.ActivityStatusType {
+	stepName := workflow.StepName(flow, step)
+	current := workflow.FindWorkflowActivityStep(pipeline, stepName)
+	if current != nil && current.Status.IsTerminated() {
+		return current.Status
+	}
+	if current == nil {
+		met, reason := workflow.ArePreconditionsMet(flow, pipeline, step)
+		if !met {
+			if o.Verbose {
+				log.Infof("Cannot start step %s of PipelineActivity %s as %s\n", stepName, pipeline.Name, reason)
+			}
+			return v1.ActivityStatusTypePending
+		}
+		if o.skipStepIfConditionsNotMet(flow, pipeline, step, activities) {
+			return v1.ActivityStatusTypeSkipped
+		}
+	}
+
+	var status v1.ActivityStatusType
+	err := o.modifyWorkflowActivityStep(flow, activities, pipeline.Name, step, func(activity *v1.PipelineActivity, w *v1.WorkflowActivityStep, created bool) bool {
+		switch {
+		case step.Approve != nil:
+			return o.processApproveStep(flow, activity, step, w, created, ns)
+		case step.Wait != nil:
+			return o.processWaitStep(activity, step, w, created)
+		case step.Verify != nil:
+			return o.processVerifyStep(activity, step, w, created, ns)
+		default:
+			workflow.CompleteWorkflowActivityStep(w, v1.ActivityStatusTypeError, fmt.Sprintf("unsupported workflow step kind %s", string(step.Kind)))
+			return true
+		}
+	}, &status)
+	if err != nil {
+		log.Warnf("Failed to process step %s of PipelineActivity %s: %s\n", stepName, pipeline.Name, err)
+		return v1.ActivityStatusTypeRunning
+	}
+	return status
+}
+
+func (o *ControllerWorkflowOptions) processApproveStep(flow *v1.Workflow, activity *v1.PipelineActivity, step *v1.WorkflowStep, w *v1.WorkflowActivityStep, created bool, ns string) bool {
+	if !created {
+		return false
+	}
+	if workflow.HasApprovers(step) {
+		// lets grant the approvers permission to approve the step so that the API server checks their identity
+		kubeClient, _, err := o.KubeClient()
+		if err == nil {
+			err = kube.EnsureWorkflowApproversRole(kubeClient, ns, flow.Name, w.Name, step.Approve.Approvers)
+		}
+		if err != nil {
+			workflow.CompleteWorkflowActivityStep(w, v1.ActivityStatusTypeError, fmt.Sprintf("failed to grant the approvers permission to approve the step: %s", err))
+			return true
+		}
+	}
+	w.Status = v1.ActivityStatusTypeWaitingForApproval
+	w.Message = step.Approve.Message
+	if w.Message == "" {
+		w.Message = fmt.Sprintf("Waiting for approval via: jx approve %s --build %s --step %s", activity.Spec.Pipeline, activity.Spec.Build, w.Name)
+	}
+	log.Infof("Step %s of PipelineActivity %s is waiting for approval\n", util.ColorInfo(w.Name), util.ColorInfo(activity.Name))
+	return true
+}
+
+func (o *ControllerWorkflowOptions) processWaitStep(activity *v1.PipelineActivity, step *v1.WorkflowStep, w *v1.WorkflowActivityStep, created bool) bool {
+	duration, err := time.ParseDuration(step.Wait.Duration)
+	if err != nil {
+		workflow.CompleteWorkflowActivityStep(w, v1.ActivityStatusTypeError, fmt.Sprintf("invalid duration %s: %s", step.Wait.Duration, err))
+		return true
+	}
+	if time.Now().Sub(w.StartedTimestamp.Time) >= duration {
+		workflow.CompleteWorkflowActivityStep(w, v1.ActivityStatusTypeSucceeded, "")
+		return true
+	}
+	if created {
+		w.Status = v1.ActivityStatusTypeRunning
+		w.Message = fmt.Sprintf("Waiting for %s", duration.String())
+		return true
+	}
+	return false
+}
+
+func (o *ControllerWorkflowOptions) processVerifyStep(activity *v1.PipelineActivity, step *v1.WorkflowStep, w *v1.WorkflowActivityStep, created bool, ns string) bool {
+	kubeClient, _, err := o.KubeClient()
+	if err != nil {
+		workflow.CompleteWorkflowActivityStep(w, v1.ActivityStatusTypeError, fmt.Sprintf("failed to create the kube client: %s", err))
+		return true
+	}
+	jobs := kubeClient.BatchV1().Jobs(ns)
+	if created || w.JobName == "" {
+		job := o.createVerifyJob(activity, step, w)
+		_, err = jobs.Create(job)
+		if err != nil {
+			workflow.CompleteWorkflowActivityStep(w, v1.ActivityStatusTypeError, fmt.Sprintf("failed to create Job %s: %s", job.Name, err))
+			return true
+		}
+		log.Infof("Created verification Job %s for step %s of PipelineActivity %s\n", util.ColorInfo(job.Name), util.ColorInfo(w.Name), util.ColorInfo(activity.Name))
+		w.JobName = job.Name
+		w.Status = v1.ActivityStatusTypeRunning
+		return true
+	}
+	job, err := jobs.Get(w.JobName, metav1.GetOptions{})
+	if err != nil {
+		workflow.CompleteWorkflowActivityStep(w, v1.ActivityStatusTypeFailed, fmt.Sprintf("failed to find Job %s: %s", w.JobName, err))
+		return true
+	}
+	if kube.IsJobFinished(job) {
+		if kube.IsJobSucceeded(job) {
+			workflow.CompleteWorkflowActivityStep(w, v1.ActivityStatusTypeSucceeded, "")
+		} else {
+			workflow.CompleteWorkflowActivityStep(w, v1.ActivityStatusTypeFailed, fmt.Sprintf("Job %s has %d failed pods", w.JobName, job.Status.Failed))
+		}
+		return true
+	}
+	timeout := step.Verify.Timeout
+	if timeout == "" {
+		timeout = defaultVerifyStepTimeout
+	}
+	duration, err := time.ParseDuration(timeout)
+	if err != nil {
+		workflow.CompleteWorkflowActivityStep(w, v1.ActivityStatusTypeError, fmt.Sprintf("invalid timeout %s: %s", timeout, err))
+		return true
+	}
+	if time.Now().Sub(w.StartedTimestamp.Time) >= duration {
+		err = kube.DeleteJob(kubeClient, ns, w.JobName)
+		if err != nil {
+			log.Warnf("Failed to delete Job %s: %s\n", w.JobName, err)
+		}
+		workflow.CompleteWorkflowActivityStep(w, v1.ActivityStatusTypeFailed, fmt.Sprintf("Job %s did not complete within %s", w.JobName, timeout))
+		return true
+	}
+	return false
+}
+
+// createVerifyJob creates the Job for a verify step passing in the details of the release as environment variables
+func (o *ControllerWorkflowOptions) createVerifyJob(activity *v1.PipelineActivity, step *v1.WorkflowStep, w *v1.WorkflowActivityStep) *batchv1.Job {
+	name := kube.ToValidName(activity.Name + "-" + w.Name)
+	if len(name) > 57 {
+		name = strings.TrimSuffix(name[0:57], "-")
+	}
+	name += "-" + strings.ToLower(string(uuid.NewUUID())[0:5])
+	backoffLimit := int32(0)
+	envVars := []corev1.EnvVar{
+		{
+			Name:  "APP_NAME",
+			Value: activity.RepositoryName(),
+		},
+		{
+			Name:  "VERSION",
+			Value: activity.Spec.Version,
+		},
+		{
+			Name:  "JX_PIPELINE",
+			Value: activity.Spec.Pipeline,
+		},
+		{
+			Name:  "JX_BUILD",
+			Value: activity.Spec.Build,
+		},
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				kube.LabelJobKind:          kube.ValueJobKindWorkflowVerify,
+				kube.LabelPipelineActivity: activity.Name,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    "verify",
+							Image:   step.Verify.Image,
+							Command: step.Verify.Command,
+							Args:    step.Verify.Args,
+							Env:     envVars,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// skipStepIfConditionsNotMet records the step as skipped if any of its conditions are not met returning true if it was skipped
+func (o *ControllerWorkflowOptions) skipStepIfConditionsNotMet(flow *v1.Workflow, pipeline *v1.PipelineActivity, step *v1.WorkflowStep, activities typev1.PipelineActivityInterface) bool {
+	if len(step.Conditions) == 0 {
+		return false
+	}
+	stepName := workflow.StepName(flow, step)
+	ok, err := workflow.EvaluateConditions(pipeline, step.Conditions)
+	if err != nil {
+		log.Warnf("Failed to evaluate the conditions of step %s of PipelineActivity %s: %s\n", stepName, pipeline.Name, err)
+	}
+	if ok {
+		return false
+	}
+	log.Infof("Skipping step %s of PipelineActivity %s as its conditions are not met\n", util.ColorInfo(stepName), util.ColorInfo(pipeline.Name))
+	var status v1.ActivityStatusType
+	err = o.modifyWorkflowActivityStep(flow, activities, pipeline.Name, step, func(activity *v1.PipelineActivity, w *v1.WorkflowActivityStep, created bool) bool {
+		workflow.CompleteWorkflowActivityStep(w, v1.ActivityStatusTypeSkipped, "conditions not met")
+		return true
+	}, &status)
+	if err != nil {
+		log.Warnf("Failed to mark step %s of PipelineActivity %s as skipped: %s\n", stepName, pipeline.Name, err)
+	}
 	return true
 }
 
+// modifyWorkflowActivityStep reloads the PipelineActivity and modifies the state of the given workflow step
+// updating the PipelineActivity if the callback returns true
+func (o *ControllerWorkflowOptions) modifyWorkflowActivityStep(flow *v1.Workflow, activities typev1.PipelineActivityInterface, name string, step *v1.WorkflowStep, callback func(activity *v1.PipelineActivity, w *v1.WorkflowActivityStep, created bool) bool, status *v1.ActivityStatusType) error {
+	return o.modifyLatestPipeline(activities, name, func(activity *v1.PipelineActivity) bool {
+		w, created := workflow.GetOrCreateWorkflowActivityStep(flow, activity, step)
+		answer := callback(activity, w, created)
+		*status = w.Status
+		return answer
+	})
+}
+
+// modifyLatestPipeline reloads the PipelineActivity so that any changes made while processing
+// the workflow are not lost then updates it if the callback returns true
+func (o *ControllerWorkflowOptions) modifyLatestPipeline(activities typev1.PipelineActivityInterface, name string, callback func(activity *v1.PipelineActivity) bool) error {
+	activity, err := activities.Get(name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !callback(activity) {
+		return nil
+	}
+	activity, err = activities.Update(activity)
+	if err != nil {
+		return err
+	}
+	if o.pipelineMap[name] != nil {
+		o.pipelineMap[name] = activity
+	}
+	return nil
+}
+
+// pollRunningWorkflowSteps processes the pipelines with running wait or verify steps as they do not change
+// the PipelineActivity until they complete
+func (o *ControllerWorkflowOptions) pollRunningWorkflowSteps(jxClient versioned.Interface, ns string) {
+	activities := jxClient.JenkinsV1().PipelineActivities(ns)
+	names := []string{}
+	for name, pipeline := range o.pipelineMap {
+		for _, step := range pipeline.Spec.Steps {
+			if step.Workflow != nil && step.Workflow.Status == v1.ActivityStatusTypeRunning {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	for _, name := range names {
+		pipeline, err := activities.Get(name, metav1.GetOptions{})
+		if err != nil {
+			log.Warnf("Failed to find PipelineActivity %s: %s\n", name, err)
+			continue
+		}
+		o.onActivity(pipeline, jxClient, ns)
+	}
+}
+
 // createPromoteStatus returns a map indexed by environment name of all the promotions in this pipeline
 func createPromoteStatus(pipeline *v1.PipelineActivity) map[string]*v1.PromoteActivityStep {
 	answer := map[string]*v1.PromoteActivityStep{}
@@ -806,6 +1187,10 @@ func (o *ControllerWorkflowOptions) removePipelineActivityIfNoManual(activity *v
 				return
 			}
 		}
+		w := step.Workflow
+		if w != nil && w.Status == v1.ActivityStatusTypeWaitingForApproval {
+			return
+		}
 	}
 	o.removePipelineActivity(activity, activities)
 }
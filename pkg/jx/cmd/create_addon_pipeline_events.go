@@ -31,6 +31,9 @@ const (
 var (
 	createAddonPipelineEventsLong = templates.LongDesc(`
 		Creates the Jenkins X pipeline events addon
+
+		The PipelineActivity and Release events are sent to the Elasticsearch of the addon by 'jx step report activities'
+		and can also be published as CloudEvents, to signed webhooks or to Kafka by configuring the event sinks.
 `)
 
 	createAddonPipelineEventsExample = templates.Examples(`
@@ -39,13 +42,22 @@ var (
 
 		# Create the pipeline-events addon in a custom namespace
 		jx create addon pipeline-events -n mynamespace
+
+		# Create the pipeline-events addon also publishing events to a Kafka topic via the Kafka REST Proxy
+		jx create addon pipeline-events --kafka-url http://kafka-rest-proxy.kafka:8082 --kafka-topic pipelines
+
+		# Configure a signed webhook sink without installing Elasticsearch and Kibana
+		jx create addon pipeline-events --sinks-only --webhook-url https://example.com/hook --webhook-secret mysecret
 	`)
 )
 
 // CreateAddonPipelineEventsOptions the options for the create spring command
 type CreateAddonPipelineEventsOptions struct {
 	CreateAddonOptions
-	Password string
+	Password  string
+	SinksOnly bool
+
+	Sinks PipelineEventSinkFlags
 }
 
 // NewCmdCreateAddonPipelineEvents creates a command object for the "create" command
@@ -82,6 +94,8 @@ func NewCmdCreateAddonPipelineEvents(f Factory, in terminal.FileReader, out term
 	options.addFlags(cmd, defaultPENamespace, defaultPEReleaseName, defaultPEVersion)
 
 	cmd.Flags().StringVarP(&options.Password, "password", "p", "", "Password to access pipeline-events services such as Kibana and Elasticsearch.  Defaults to default Jenkins X admin password.")
+	cmd.Flags().BoolVarP(&options.SinksOnly, "sinks-only", "", false, "Only configures the event sinks without installing Elasticsearch and Kibana")
+	options.Sinks.addFlags(cmd)
 	return cmd
 }
 
@@ -92,11 +106,7 @@ func (o *CreateAddonPipelineEventsOptions) Run() error {
 		return util.MissingOption(optionRelease)
 	}
 
-	err := o.ensureHelm()
-	if err != nil {
-		return errors.Wrap(err, "failed to ensure that helm is present")
-	}
-	_, _, err = o.KubeClient()
+	_, _, err := o.KubeClient()
 	if err != nil {
 		return err
 	}
@@ -108,6 +118,19 @@ func (o *CreateAddonPipelineEventsOptions) Run() error {
 
 	log.Infof("found dev namespace %s\n", devNamespace)
 
+	err = o.configureSinks(devNamespace)
+	if err != nil {
+		return err
+	}
+	if o.SinksOnly {
+		return nil
+	}
+
+	err = o.ensureHelm()
+	if err != nil {
+		return errors.Wrap(err, "failed to ensure that helm is present")
+	}
+
 	setValues := strings.Split(o.SetValues, ",")
 	err = o.installChart(o.ReleaseName, kube.ChartPipelineEvent, o.Version, o.Namespace, true, setValues, nil)
 	if err != nil {
@@ -193,6 +216,27 @@ func (o *CreateAddonPipelineEventsOptions) Run() error {
 	log.Successf("kibana is available and running %s\n", kIng)
 	return nil
 }
+
+// configureSinks saves any event sinks specified on the command line into the dev namespace
+func (o *CreateAddonPipelineEventsOptions) configureSinks(devNamespace string) error {
+	sinks := o.Sinks.sinksConfig().Sinks
+	if len(sinks) == 0 {
+		if o.SinksOnly {
+			return fmt.Errorf("no event sinks specified. Please specify --cloudevents-url, --webhook-url or --kafka-url")
+		}
+		return nil
+	}
+	config, err := o.loadPipelineEventSinksConfig(devNamespace)
+	if err != nil {
+		return err
+	}
+	for _, sink := range sinks {
+		config.AddSink(sink)
+		log.Infof("Configured the %s event sink for %s\n", util.ColorInfo(sink.SinkName()), util.ColorInfo(sink.URL))
+	}
+	return o.savePipelineEventSinksConfig(devNamespace, config)
+}
+
 func (o *CreateAddonPipelineEventsOptions) addExposecontrollerAnnotations(serviceName string) error {
 
 	svc, err := o.KubeClientCached.CoreV1().Services(o.Namespace).Get(serviceName, meta_v1.GetOptions{})
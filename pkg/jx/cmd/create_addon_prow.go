@@ -17,6 +17,12 @@ import (
 var (
 	createAddonProwLong = templates.LongDesc(`
 		Creates the Prow addon for handling webhook events
+
+		The OAuth token of Prow is picked from the Git server which defaults to the current Git server of your Git
+		credentials or GitHub if there is none.
+
+		Prow only accepts webhook events signed with the HMAC signature of GitHub. Webhook events sent by GitLab,
+		which uses the X-Gitlab-Token header, or by Bitbucket Server are rejected by Prow until its hook supports them.
 `)
 
 	createAddonProwExample = templates.Examples(`
@@ -25,6 +31,9 @@ var (
 
 		# Create the Prow addon in a custom namespace
 		jx create addon prow -n mynamespace
+
+		# Create the Prow addon using the Git account of a GitHub Enterprise server
+		jx create addon prow --git-provider-url https://github.example.com
 	`)
 )
 
@@ -70,8 +79,9 @@ func NewCmdCreateAddonProw(f Factory, in terminal.FileReader, out terminal.FileW
 	options.addFlags(cmd, "", kube.DefaultProwReleaseName, defaultProwVersion)
 
 	cmd.Flags().StringVarP(&options.Prow.Chart, optionChart, "c", kube.ChartProw, "The name of the chart to use")
-	cmd.Flags().StringVarP(&options.Prow.HMACToken, "hmac-token", "", "", "OPTIONAL: The hmac-token is the token that you give to GitHub for validating webhooks. Generate it using any reasonable randomness-generator, eg openssl rand -hex 20")
+	cmd.Flags().StringVarP(&options.Prow.HMACToken, "hmac-token", "", "", "OPTIONAL: The hmac-token is the token that you give to the Git provider for validating webhooks. Generate it using any reasonable randomness-generator, eg openssl rand -hex 20")
 	cmd.Flags().StringVarP(&options.Prow.OAUTHToken, "oauth-token", "", "", "OPTIONAL: The oauth-token is an OAuth2 token that has read and write access to the bot account. Generate it from the account's settings -> Personal access tokens -> Generate new token.")
+	cmd.Flags().StringVarP(&options.Prow.GitProviderURL, "git-provider-url", "", "", "The Git server URL of the Git account whose OAuth token Prow uses. Defaults to the current Git server or GitHub")
 	cmd.Flags().StringVarP(&options.Password, "password", "", "", "Overwrite the default admin password used to login to the Deck UI")
 	return cmd
 }
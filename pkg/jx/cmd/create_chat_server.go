@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/jenkins-x/jx/pkg/chats"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
@@ -19,6 +20,15 @@ var (
 	createChatServer_example = templates.Examples(`
 		# Add a new chat server URL
 		jx create chat server slack https://myroom.slack.server
+
+		# Add a Mattermost server for a team
+		jx create chat server mattermost https://mattermost.example.com/myteam
+
+		# Add a Microsoft Teams team using the Microsoft Graph API
+		jx create chat server teams https://graph.microsoft.com/v1.0/teams/<team-id>
+
+		# Add a Rocket.Chat server
+		jx create chat server rocketchat https://rocketchat.example.com
 	`)
 )
 
@@ -67,6 +77,9 @@ func (o *CreateChatServerOptions) Run() error {
 		return missingChatArguments()
 	}
 	kind := args[0]
+	if util.StringArrayIndex(chats.ChatKinds, kind) < 0 {
+		return util.InvalidArg(kind, chats.ChatKinds)
+	}
 	name := o.Name
 	if name == "" {
 		name = kind
@@ -90,7 +103,7 @@ func (o *CreateChatServerOptions) Run() error {
 	if err != nil {
 		return err
 	}
-	log.Infof("Added issue chat server %s for URL %s\n", util.ColorInfo(name), util.ColorInfo(gitUrl))
+	log.Infof("Added chat server %s for URL %s\n", util.ColorInfo(name), util.ColorInfo(gitUrl))
 	return nil
 }
 
@@ -13,6 +13,7 @@ import (
 
 	"github.com/ghodss/yaml"
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/config"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
@@ -31,12 +32,20 @@ const (
 	optionLabel      = "label"
 	optionRequestCpu = "request-cpu"
 	devPodGoPath     = "/workspace"
+
+	devPodWorkspaceDir        = "/workspace"
+	devPodWorkspaceVolumeName = "ws-volume"
 )
 
 var (
 	createDevPodLong = templates.LongDesc(`
 		Creates a new DevPod
 
+		If the jenkins-x.yml of the project has a 'devpod' section then the DevPod is created from its containers,
+		resources, ports, environment variables, init commands and IDE so that the DevPod is shared by the team. If
+		the section specifies a 'workspaceSize' the workspace is kept in a persistent volume claim which is reused
+		when the DevPod is created again after 'jx gc devpods' stopped it for being idle.
+
 		For more documentation see: [https://jenkins-x.io/developing/devpods/](https://jenkins-x.io/developing/devpods/)
 
 `)
@@ -47,6 +56,12 @@ var (
 
 		# creates a new Maven DevPod 
 		jx create devpod -l maven
+
+		# creates a DevPod which the current directory is synchronised with while the shell into it is open
+		jx create devpod --sync
+
+		# creates the DevPod defined in the jenkins-x.yml of the current directory
+		jx create devpod
 	`)
 )
 
@@ -68,6 +83,7 @@ type CreateDevPodOptions struct {
 	Dir             string
 	Reuse           bool
 	Sync            bool
+	Ksync           bool
 	Ports           []int
 	AutoExpose      bool
 	Persist         bool
@@ -126,6 +142,7 @@ func NewCmdCreateDevPod(f Factory, in terminal.FileReader, out terminal.FileWrit
 	cmd.Flags().StringVarP(&options.RequestCpu, optionRequestCpu, "c", "1", "The request CPU of the DevPod")
 	cmd.Flags().BoolVarP(&options.Reuse, "reuse", "", true, "Reuse an existing DevPod if a suitable one exists. The DevPod will be selected based on the label (or current working directory)")
 	cmd.Flags().BoolVarP(&options.Sync, "sync", "", false, "Also synchronise the local file system into the DevPod")
+	cmd.Flags().BoolVarP(&options.Ksync, "ksync", "", false, "Uses ksync rather than the Kubernetes exec API to synchronise the local file system with --sync")
 	cmd.Flags().IntSliceVarP(&options.Ports, "ports", "p", []int{}, "Container ports exposed by the DevPod")
 	cmd.Flags().BoolVarP(&options.AutoExpose, "auto-expose", "", true, "Automatically expose useful ports as services such as the debug port, as well as any ports specified using --ports")
 	cmd.Flags().BoolVarP(&options.Persist, "persist", "", false, "Persist changes made to the DevPod. Cannot be used with --sync")
@@ -165,6 +182,21 @@ func (o *CreateDevPodOptions) Run() error {
 	if err != nil {
 		return err
 	}
+	projectConfig, _, err := config.LoadProjectConfig(dir)
+	if err != nil {
+		return err
+	}
+	devPodConfig := projectConfig.DevPod
+	ide := config.DevPodIDETheia
+	if devPodConfig != nil && devPodConfig.IDE != "" {
+		ide = devPodConfig.IDE
+	}
+	if ide != config.DevPodIDETheia && ide != config.DevPodIDENone {
+		return fmt.Errorf("Unknown DevPod IDE %s in %s. Supported values are %s and %s", ide, config.ProjectConfigFileName, config.DevPodIDETheia, config.DevPodIDENone)
+	}
+	// Theia won't work in --sync mode as we can't share a volume
+	theia := !o.Sync && ide == config.DevPodIDETheia
+	keepWorkspace := !o.Sync && devPodConfig != nil && devPodConfig.WorkspaceSize != ""
 
 	devpodConfigYml, err := client.CoreV1().ConfigMaps(curNs).Get("jenkins-x-devpod-config", metav1.GetOptions{})
 	versions := &map[string]string{}
@@ -183,6 +215,9 @@ func (o *CreateDevPodOptions) Run() error {
 	labels := util.SortedMapKeys(podTemplates)
 
 	label := o.Label
+	if label == "" && devPodConfig != nil {
+		label = devPodConfig.Label
+	}
 	if label == "" {
 		label = o.guessDevPodLabel(dir, labels)
 	}
@@ -267,14 +302,18 @@ func (o *CreateDevPodOptions) Run() error {
 	}
 
 	// Trying to reuse workspace-volume as a name seems to prevent us modifying the volumes!
-	workspaceVolumeName = "ws-volume"
+	workspaceVolumeName = devPodWorkspaceVolumeName
 	var workspaceVolume corev1.Volume
 	workspaceClaimName := fmt.Sprintf("%s-pvc", pod.Name)
+	if keepWorkspace {
+		// lets use the same claim each time the DevPod is created so that the workspace is restored
+		workspaceClaimName = kube.DevPodWorkspaceClaimName(userName, label, o.Suffix)
+	}
 	workspaceVolumeMount := corev1.VolumeMount{
 		Name:      workspaceVolumeName,
-		MountPath: "/workspace",
+		MountPath: devPodWorkspaceDir,
 	}
-	if o.Persist {
+	if o.Persist || keepWorkspace {
 		workspaceVolume = corev1.Volume{
 			Name: workspaceVolumeName,
 			VolumeSource: corev1.VolumeSource{
@@ -292,16 +331,29 @@ func (o *CreateDevPodOptions) Run() error {
 		}
 	}
 
+	var containerWorkspaceMount *corev1.VolumeMount
 	if !o.Sync {
 		pod.Spec.Volumes = append(pod.Spec.Volumes, workspaceVolume)
 		container1.VolumeMounts = append(container1.VolumeMounts, workspaceVolumeMount)
+		containerWorkspaceMount = &workspaceVolumeMount
+	}
+
+	ports := o.Ports
+	if devPodConfig != nil {
+		ports, err = ApplyDevPodConfig(pod, devPodConfig, ports, containerWorkspaceMount)
+		if err != nil {
+			return errors.Wrapf(err, "failed to apply the devpod section of %s", config.ProjectConfigFileName)
+		}
+		container1 = &pod.Spec.Containers[0]
+	}
 
+	if theia {
 		cpuLimit, _ := resource.ParseQuantity("400m")
 		cpuRequest, _ := resource.ParseQuantity("200m")
 		memoryLimit, _ := resource.ParseQuantity("1Gi")
 		memoryRequest, _ := resource.ParseQuantity("128Mi")
 
-		// Add Theia - note Theia won't work in --sync mode as we can't share a volume
+		// Add Theia
 
 		theiaVersion := "latest"
 		if val, ok := (*versions)["theia"]; ok {
@@ -344,14 +396,19 @@ func (o *CreateDevPodOptions) Run() error {
 		}
 
 		pod.Spec.Containers = append(pod.Spec.Containers, theiaContainer)
-
+		container1 = &pod.Spec.Containers[0]
 	}
 
-	if o.RequestCpu != "" {
+	// the CPU request of the devpod section wins unless --request-cpu is specified
+	requestCpuChanged := o.Cmd != nil && o.Cmd.Flags().Changed(optionRequestCpu)
+	if o.RequestCpu != "" && (requestCpuChanged || !devPodConfigRequestsCpu(devPodConfig)) {
 		q, err := resource.ParseQuantity(o.RequestCpu)
 		if err != nil {
 			return util.InvalidOptionError(optionRequestCpu, o.RequestCpu, err)
 		}
+		if container1.Resources.Requests == nil {
+			container1.Resources.Requests = corev1.ResourceList{}
+		}
 		container1.Resources.Requests[corev1.ResourceCPU] = q
 	}
 
@@ -362,7 +419,7 @@ func (o *CreateDevPodOptions) Run() error {
 		Value: devPodGoPath,
 	})
 	if workingDir == "" {
-		workingDir = "/workspace"
+		workingDir = devPodWorkspaceDir
 
 		if o.Sync {
 			// lets check for GOPATH stuff if we are in --sync mode so that we sync into gopath
@@ -376,6 +433,14 @@ func (o *CreateDevPodOptions) Run() error {
 		}
 	}
 	pod.Annotations[kube.AnnotationWorkingDir] = workingDir
+	pod.Annotations[kube.AnnotationDevPodLastActive] = time.Now().UTC().Format(time.RFC3339)
+	if devPodConfig != nil && devPodConfig.IdleTimeout != "" {
+		_, err = time.ParseDuration(devPodConfig.IdleTimeout)
+		if err != nil {
+			return fmt.Errorf("Invalid DevPod idleTimeout %s in %s: %s", devPodConfig.IdleTimeout, config.ProjectConfigFileName, err)
+		}
+		pod.Annotations[kube.AnnotationDevPodIdleTimeout] = devPodConfig.IdleTimeout
+	}
 	if o.Sync {
 		pod.Annotations[kube.AnnotationLocalDir] = dir
 	}
@@ -410,7 +475,7 @@ func (o *CreateDevPodOptions) Run() error {
 	// Assign the container the ports provided as input
 	var exposeServicePorts []int
 
-	for _, port := range o.Ports {
+	for _, port := range ports {
 		cp := corev1.ContainerPort{
 			Name:          fmt.Sprintf("port-%d", port),
 			ContainerPort: int32(port),
@@ -420,7 +485,7 @@ func (o *CreateDevPodOptions) Run() error {
 
 	// Assign the container the ports provided automatically
 	if o.AutoExpose {
-		exposeServicePorts = o.Ports
+		exposeServicePorts = ports
 		if portsStr, ok := pod.Annotations["jenkins-x.io/devpodPorts"]; ok {
 			ports := strings.Split(portsStr, ", ")
 			for _, portStr := range ports {
@@ -475,6 +540,17 @@ func (o *CreateDevPodOptions) Run() error {
 	}
 
 	theiaServiceName := name + "-theia"
+	if create && keepWorkspace {
+		// the workspace claim is ReadWriteOnce so it cannot be shared with another DevPod
+		other, err := kube.FindDevPodUsingVolumeClaim(client, ns, workspaceClaimName)
+		if err != nil {
+			return err
+		}
+		if other != nil {
+			return fmt.Errorf("the workspace %s is already used by the DevPod %s. Use --reuse to open it, delete it via: jx delete devpod %s or use --suffix to create a DevPod with a separate workspace",
+				workspaceClaimName, other.Name, other.Name)
+		}
+	}
 	if create {
 		log.Infof("Creating a DevPod of label: %s\n", util.ColorInfo(label))
 		_, err = podResources.Create(pod)
@@ -495,7 +571,12 @@ func (o *CreateDevPodOptions) Run() error {
 		}
 
 		// Create PVC if needed
-		if o.Persist {
+		if keepWorkspace {
+			err = o.ensureDevPodWorkspaceClaim(client, curNs, workspaceClaimName, devPodConfig.WorkspaceSize, userName, label)
+			if err != nil {
+				return err
+			}
+		} else if o.Persist {
 			storageRequest, _ := resource.ParseQuantity("2Gi")
 			pvc := corev1.PersistentVolumeClaim{
 				ObjectMeta: metav1.ObjectMeta{
@@ -560,7 +641,7 @@ func (o *CreateDevPodOptions) Run() error {
 			}
 			addedServices = true
 		}
-		if !o.Sync {
+		if theia {
 
 			// Create a service for theia
 			theiaService := corev1.Service{
@@ -611,7 +692,7 @@ func (o *CreateDevPodOptions) Run() error {
 	log.Infof("Pod %s is now ready!\n", util.ColorInfo(pod.Name))
 	log.Infof("You can open other shells into this DevPod via %s\n", util.ColorInfo("jx create devpod"))
 
-	if !o.Sync {
+	if theia {
 		theiaServiceURL, err := kube.FindServiceURL(client, curNs, theiaServiceName)
 		if err != nil {
 			return err
@@ -655,7 +736,19 @@ func (o *CreateDevPodOptions) Run() error {
 			Daemon:        true,
 			Dir:           dir,
 		}
-		err = syncOptions.CreateKsync(client, ns, pod.Name, dir, workingDir, userName)
+		if o.Ksync {
+			err = syncOptions.CreateKsync(client, ns, pod.Name, dir, workingDir, userName)
+		} else if o.Headless {
+			stopSync := make(chan struct{})
+			err = syncOptions.NativeSync(client, ns, pod.Name, dir, workingDir, stopSync)
+			close(stopSync)
+			log.Infof("Run %s to keep the files synchronised\n", util.ColorInfo("jx sync"))
+		} else {
+			// lets keep synchronising in the background until the shell into the DevPod exits
+			stopSync := make(chan struct{})
+			defer close(stopSync)
+			err = syncOptions.NativeSync(client, ns, pod.Name, dir, workingDir, stopSync)
+		}
 		if err != nil {
 			return err
 		}
@@ -667,8 +760,8 @@ func (o *CreateDevPodOptions) Run() error {
 		log.Infof("Installing Bash Completion into DevPod\n")
 		rshExec = append(rshExec, "yum install -q -y bash-completion bash-completion-extra", "mkdir -p ~/.jx", "jx completion bash > ~/.jx/bash", "echo \"source ~/.jx/bash\" >> ~/.bashrc")
 
-		// Only add git secrets to the Theia container when sync flag is missing (otherwise Theia container won't exist)
-		if !o.Sync {
+		// Only add git secrets to the Theia container when it exists
+		if theia {
 			// Add Git Secrets to Theia container
 			secrets, err := o.LoadPipelineSecrets(kube.ValueKindGit, "")
 			if err != nil {
@@ -735,6 +828,9 @@ func (o *CreateDevPodOptions) Run() error {
 			}
 		}
 	}
+	if create && devPodConfig != nil {
+		rshExec = append(rshExec, devPodConfig.InitCommands...)
+	}
 
 	// Only want to shell into the DevPod if the headless flag isn't set
 	if !o.Headless {
@@ -758,6 +854,133 @@ func (o *CreateDevPodOptions) Run() error {
 	return options.Run()
 }
 
+// ApplyDevPodConfig applies the devpod section of the jenkins-x.yml to the pod created from the pod template
+// returning the ports of the first container
+func ApplyDevPodConfig(pod *corev1.Pod, devPodConfig *config.DevPodConfig, ports []int, workspaceVolumeMount *corev1.VolumeMount) ([]int, error) {
+	ports = append(append([]int{}, ports...), devPodConfig.Ports...)
+	for i, c := range devPodConfig.Containers {
+		if c == nil {
+			continue
+		}
+		var container *corev1.Container
+		if i == 0 {
+			container = &pod.Spec.Containers[0]
+			ports = append(ports, c.Ports...)
+		} else {
+			if c.Image == "" {
+				return ports, fmt.Errorf("no image specified for DevPod container %s", c.Name)
+			}
+			name := c.Name
+			if name == "" {
+				name = fmt.Sprintf("sidecar-%d", i)
+			}
+			sidecar := corev1.Container{
+				Name: name,
+			}
+			if workspaceVolumeMount != nil {
+				sidecar.VolumeMounts = append(sidecar.VolumeMounts, *workspaceVolumeMount)
+			}
+			for _, port := range c.Ports {
+				sidecar.Ports = append(sidecar.Ports, corev1.ContainerPort{
+					Name:          fmt.Sprintf("port-%d", port),
+					ContainerPort: int32(port),
+				})
+			}
+			pod.Spec.Containers = append(pod.Spec.Containers, sidecar)
+			container = &pod.Spec.Containers[len(pod.Spec.Containers)-1]
+		}
+		if c.Image != "" {
+			container.Image = c.Image
+		}
+		if len(c.Command) > 0 {
+			container.Command = c.Command
+		}
+		if len(c.Args) > 0 {
+			container.Args = c.Args
+		}
+		container.Env = append(container.Env, c.Env...)
+		if c.Resources != nil {
+			resources, err := c.Resources.ResourceRequirements()
+			if err != nil {
+				return ports, err
+			}
+			container.Resources.Requests = mergeResourceList(container.Resources.Requests, resources.Requests)
+			container.Resources.Limits = mergeResourceList(container.Resources.Limits, resources.Limits)
+		}
+	}
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		container.Env = append(container.Env, devPodConfig.Env...)
+	}
+	return ports, nil
+}
+
+// devPodConfigRequestsCpu returns true if the devpod section specifies the CPU request of the first container
+func devPodConfigRequestsCpu(devPodConfig *config.DevPodConfig) bool {
+	if devPodConfig == nil || len(devPodConfig.Containers) == 0 {
+		return false
+	}
+	c := devPodConfig.Containers[0]
+	if c == nil || c.Resources == nil {
+		return false
+	}
+	_, ok := c.Resources.Requests[string(corev1.ResourceCPU)]
+	return ok
+}
+
+func mergeResourceList(resources corev1.ResourceList, overrides corev1.ResourceList) corev1.ResourceList {
+	if len(overrides) == 0 {
+		return resources
+	}
+	if resources == nil {
+		resources = corev1.ResourceList{}
+	}
+	for name, q := range overrides {
+		resources[name] = q
+	}
+	return resources
+}
+
+// ensureDevPodWorkspaceClaim creates the persistent volume claim of the workspace unless it was kept from a
+// previous DevPod. The claim is not owned by the DevPod so that it is kept when the DevPod is stopped
+func (o *CreateDevPodOptions) ensureDevPodWorkspaceClaim(client kubernetes.Interface, ns string, name string, size string, userName string, label string) error {
+	claims := client.CoreV1().PersistentVolumeClaims(ns)
+	_, err := claims.Get(name, metav1.GetOptions{})
+	if err == nil {
+		log.Infof("Reusing the workspace %s\n", util.ColorInfo(name))
+		return nil
+	}
+	storageRequest, err := resource.ParseQuantity(size)
+	if err != nil {
+		return fmt.Errorf("Invalid DevPod workspaceSize %s in %s: %s", size, config.ProjectConfigFileName, err)
+	}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				kube.LabelPodTemplate:    label,
+				kube.LabelDevPodUsername: userName,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{
+				corev1.ReadWriteOnce,
+			},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: storageRequest,
+				},
+			},
+		},
+	}
+	_, err = claims.Create(pvc)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the workspace %s", name)
+	}
+	log.Infof("Created the workspace %s of size %s\n", util.ColorInfo(name), util.ColorInfo(size))
+	return nil
+}
+
 func (o *CreateDevPodOptions) getOrCreateEditEnvironment() (*v1.Environment, error) {
 	var env *v1.Environment
 	apisClient, err := o.Factory.CreateApiExtensionsClient()
@@ -4,8 +4,10 @@ import (
 	"path"
 	"testing"
 
+	"github.com/jenkins-x/jx/pkg/config"
 	"github.com/jenkins-x/jx/pkg/jx/cmd"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func TestFindDevPodLabel(t *testing.T) {
@@ -26,3 +28,59 @@ func TestFindDevPodLabel(t *testing.T) {
 		}
 	}
 }
+
+func TestApplyDevPodConfig(t *testing.T) {
+	t.Parallel()
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "go",
+					Image: "jenkinsxio/builder-go",
+				},
+			},
+		},
+	}
+	devPodConfig := &config.DevPodConfig{
+		Containers: []*config.DevPodContainer{
+			{
+				Image: "jenkinsxio/builder-go:0.1.100",
+				Ports: []int{2345},
+				Resources: &config.DevPodResources{
+					Requests: map[string]string{"memory": "1Gi"},
+				},
+			},
+			{
+				Name:  "postgres",
+				Image: "postgres:10",
+				Ports: []int{5432},
+			},
+		},
+		Ports: []int{8080},
+		Env: []corev1.EnvVar{
+			{Name: "DB_HOST", Value: "localhost"},
+		},
+	}
+	mount := &corev1.VolumeMount{Name: "ws-volume", MountPath: "/workspace"}
+	ports, err := cmd.ApplyDevPodConfig(pod, devPodConfig, []int{3000}, mount)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3000, 8080, 2345}, ports)
+
+	if assert.Len(t, pod.Spec.Containers, 2) {
+		container := pod.Spec.Containers[0]
+		assert.Equal(t, "jenkinsxio/builder-go:0.1.100", container.Image)
+		memory := container.Resources.Requests["memory"]
+		assert.Equal(t, "1Gi", memory.String())
+		assert.Equal(t, []corev1.EnvVar{{Name: "DB_HOST", Value: "localhost"}}, container.Env)
+
+		sidecar := pod.Spec.Containers[1]
+		assert.Equal(t, "postgres", sidecar.Name)
+		assert.Equal(t, int32(5432), sidecar.Ports[0].ContainerPort)
+		assert.Equal(t, []corev1.VolumeMount{*mount}, sidecar.VolumeMounts)
+		assert.Equal(t, []corev1.EnvVar{{Name: "DB_HOST", Value: "localhost"}}, sidecar.Env)
+	}
+
+	devPodConfig.Containers = []*config.DevPodContainer{nil, {Name: "cache"}}
+	_, err = cmd.ApplyDevPodConfig(pod, devPodConfig, nil, nil)
+	assert.Error(t, err, "sidecars require an image")
+}
@@ -221,6 +221,21 @@ type Flags struct {
 	GKEAutoRepair               bool
 	GKEAutoUpgrade              bool
 	GKEServiceAccount           string
+	AKSLocation                 string
+	AKSResourceGroup            string
+	AKSNodeVMSize               string
+	AKSMinNumOfNodes            string
+	AKSMaxNumOfNodes            string
+	AKSDiskSize                 string
+	AKSKubernetesVersion        string
+	AKSClientID                 string
+	AKSClientSecret             string
+	EKSRegion                   string
+	EKSNodeInstanceType         string
+	EKSMinNumOfNodes            string
+	EKSMaxNumOfNodes            string
+	EKSDiskSize                 string
+	EKSKubernetesVersion        string
 	LocalOrganisationRepository string
 }
 
@@ -234,7 +249,7 @@ type CreateTerraformOptions struct {
 }
 
 var (
-	validTerraformClusterProviders = []string{"gke"}
+	validTerraformClusterProviders = []string{"gke", "aks", "eks"}
 
 	createTerraformExample = templates.Examples(`
 		jx create terraform
@@ -242,6 +257,12 @@ var (
 		# to specify the clusters via flags
 		jx create terraform -c dev=gke -c stage=gke -c prod=gke
 
+		# to create an AKS cluster
+		jx create terraform -c dev=aks --aks-location westeurope --aks-client-id <id> --aks-client-secret <secret>
+
+		# to create an EKS cluster
+		jx create terraform -c dev=eks --eks-region us-west-2
+
 `)
 	validTerraformVersions = "0.11.0"
 
@@ -303,7 +324,7 @@ func NewCmdCreateTerraform(f Factory, in terminal.FileReader, out terminal.FileW
 func (options *CreateTerraformOptions) addFlags(cmd *cobra.Command, addSharedFlags bool) {
 	// global flags
 	if addSharedFlags {
-		cmd.Flags().BoolVarP(&options.Flags.SkipLogin, "skip-login", "", false, "Skip Google auth if already logged in via gcloud auth")
+		cmd.Flags().BoolVarP(&options.Flags.SkipLogin, "skip-login", "", false, "Skip logging in to the cloud providers if already logged in via gcloud auth or az login")
 	}
 	cmd.Flags().StringArrayVarP(&options.Flags.Cluster, "cluster", "c", []string{}, "Name and Kubernetes provider (gke, aks, eks) of clusters to be created in the form --cluster foo=gke")
 	cmd.Flags().BoolVarP(&options.Flags.SkipTerraformApply, "skip-terraform-apply", "", false, "Skip applying the generated Terraform plans")
@@ -321,6 +342,24 @@ func (options *CreateTerraformOptions) addFlags(cmd *cobra.Command, addSharedFla
 	cmd.Flags().StringVarP(&options.Flags.GKEProjectID, "gke-project-id", "", "", "Google Project ID to create cluster in")
 	cmd.Flags().StringVarP(&options.Flags.GKEZone, "gke-zone", "", "", "The compute zone (e.g. us-central1-a) for the cluster")
 
+	// AKS specific overrides
+	cmd.Flags().StringVarP(&options.Flags.AKSLocation, "aks-location", "", "", "The Azure location (e.g. eastus) for the cluster")
+	cmd.Flags().StringVarP(&options.Flags.AKSResourceGroup, "aks-resource-group", "", "", "The Azure resource group of the cluster. Defaults to the cluster name")
+	cmd.Flags().StringVarP(&options.Flags.AKSNodeVMSize, "aks-node-vm-size", "", "", "The Azure virtual machine size of the nodes")
+	cmd.Flags().StringVarP(&options.Flags.AKSMinNumOfNodes, "aks-min-num-nodes", "", "", "The minimum number of nodes of the cluster")
+	cmd.Flags().StringVarP(&options.Flags.AKSMaxNumOfNodes, "aks-max-num-nodes", "", "", "The maximum number of nodes of the cluster")
+	cmd.Flags().StringVarP(&options.Flags.AKSDiskSize, "aks-disk-size", "", "100", "Size in GB of the OS disks of the nodes. Defaults to 100GB")
+	cmd.Flags().StringVarP(&options.Flags.AKSKubernetesVersion, "aks-kubernetes-version", "", "1.11.5", "The Kubernetes version of the cluster")
+	cmd.Flags().StringVarP(&options.Flags.AKSClientID, "aks-client-id", "", "", "The client ID of the Azure service principal used by the cluster. Can also be set with $TF_VAR_client_id")
+	cmd.Flags().StringVarP(&options.Flags.AKSClientSecret, "aks-client-secret", "", "", "The client secret of the Azure service principal used by the cluster. Can also be set with $TF_VAR_client_secret")
+
+	// EKS specific overrides
+	cmd.Flags().StringVarP(&options.Flags.EKSRegion, "eks-region", "", "", "The AWS region (e.g. us-west-2) for the cluster. Defaults to the region of the AWS configuration")
+	cmd.Flags().StringVarP(&options.Flags.EKSNodeInstanceType, "eks-node-instance-type", "", "", "The EC2 instance type of the nodes")
+	cmd.Flags().StringVarP(&options.Flags.EKSMinNumOfNodes, "eks-min-num-nodes", "", "", "The minimum number of nodes of the cluster")
+	cmd.Flags().StringVarP(&options.Flags.EKSMaxNumOfNodes, "eks-max-num-nodes", "", "", "The maximum number of nodes of the cluster")
+	cmd.Flags().StringVarP(&options.Flags.EKSDiskSize, "eks-disk-size", "", "100", "Size in GB of the root volumes of the nodes. Defaults to 100GB")
+	cmd.Flags().StringVarP(&options.Flags.EKSKubernetesVersion, "eks-kubernetes-version", "", "1.10", "The Kubernetes version of the cluster")
 }
 
 func stringInValidProviders(a string) bool {
@@ -332,31 +371,53 @@ func stringInValidProviders(a string) bool {
 	return false
 }
 
+// newTerraformCluster creates the Cluster of the given Kubernetes provider
+func newTerraformCluster(name string, provider string) (Cluster, error) {
+	switch provider {
+	case GKE:
+		return &GKECluster{name: name, provider: provider}, nil
+	case AKS:
+		return &AKSCluster{name: name, provider: provider}, nil
+	case EKS:
+		return &EKSCluster{name: name, provider: provider}, nil
+	default:
+		return nil, fmt.Errorf("invalid cluster provider type %s, must be one of %v", provider, validTerraformClusterProviders)
+	}
+}
+
 // Run implements this command
 func (options *CreateTerraformOptions) Run() error {
 	var err error
-	if !options.Flags.SkipLogin {
-		err = options.runCommandVerbose("gcloud", "auth", "login", "--brief")
+	if len(options.Flags.Cluster) >= 1 {
+		err := options.ValidateClusterDetails()
 		if err != nil {
 			return err
 		}
 	}
 
-	options.InstallOptions.Flags.Prow = true
-	err = options.installRequirements(GKE, "terraform", options.InstallOptions.InitOptions.HelmBinary())
-	if err != nil {
-		return err
-	}
-
-	if len(options.Flags.Cluster) >= 1 {
-		err := options.ValidateClusterDetails()
+	if len(options.Flags.Cluster) == 0 {
+		err := options.ClusterDetailsWizard()
 		if err != nil {
 			return err
 		}
 	}
 
-	if len(options.Flags.Cluster) == 0 {
-		err := options.ClusterDetailsWizard()
+	options.InstallOptions.Flags.Prow = true
+	providers := map[string]bool{}
+	for _, c := range options.Clusters {
+		if providers[c.Provider()] {
+			continue
+		}
+		providers[c.Provider()] = true
+		err = options.loginToProvider(c.Provider())
+		if err != nil {
+			return err
+		}
+		deps := []string{"terraform", options.InstallOptions.InitOptions.HelmBinary()}
+		if c.Provider() == EKS {
+			deps = append(deps, "aws", "heptio-authenticator-aws")
+		}
+		err = options.installRequirements(c.Provider(), deps...)
 		if err != nil {
 			return err
 		}
@@ -437,7 +498,10 @@ func (options *CreateTerraformOptions) ClusterDetailsWizard() error {
 				jxEnvironment = name
 			}
 		}
-		c := &GKECluster{name: name, provider: provider}
+		c, err := newTerraformCluster(name, provider)
+		if err != nil {
+			return err
+		}
 
 		options.Clusters = append(options.Clusters, c)
 	}
@@ -458,7 +522,10 @@ func (options *CreateTerraformOptions) ValidateClusterDetails() error {
 			return fmt.Errorf("invalid cluster provider type %s, must be one of %v", p, validTerraformClusterProviders)
 		}
 
-		c := &GKECluster{name: pair[0], provider: pair[1]}
+		c, err := newTerraformCluster(pair[0], pair[1])
+		if err != nil {
+			return err
+		}
 		options.Clusters = append(options.Clusters, c)
 	}
 	return nil
@@ -651,11 +718,21 @@ func (options *CreateTerraformOptions) CreateOrganisationFolderStructure(dir str
 				clusterDefinitions = append(clusterDefinitions, g)
 
 			case "aks":
-				// TODO add aks terraform templates URL
-				return nil, fmt.Errorf("creating an AKS cluster via Terraform is not currently supported")
+				a := c.(*AKSCluster)
+				err := options.configureAKSCluster(a, path)
+				if err != nil {
+					return nil, err
+				}
+				clusterDefinitions = append(clusterDefinitions, a)
+
 			case "eks":
-				// TODO add eks terraform templates URL
-				return nil, fmt.Errorf("creating an EKS cluster via Terraform is not currently supported")
+				e := c.(*EKSCluster)
+				err := options.configureEKSCluster(e, path)
+				if err != nil {
+					return nil, err
+				}
+				clusterDefinitions = append(clusterDefinitions, e)
+
 			default:
 				return nil, fmt.Errorf("unknown Kubernetes provider type %s must be one of %v", c.Provider(), validTerraformClusterProviders)
 			}
@@ -676,11 +753,21 @@ func (options *CreateTerraformOptions) CreateOrganisationFolderStructure(dir str
 				clusterDefinitions = append(clusterDefinitions, g)
 
 			case "aks":
-				// TODO add aks terraform templates URL
-				return nil, fmt.Errorf("creating an AKS cluster via Terraform is not currently supported")
+				a := c.(*AKSCluster)
+				terraformVars := filepath.Join(path, "terraform.tfvars")
+				fmt.Fprintf(options.Out, "loading config from %s\n", util.ColorInfo(terraformVars))
+
+				a.ParseTfVarsFile(terraformVars)
+				clusterDefinitions = append(clusterDefinitions, a)
+
 			case "eks":
-				// TODO add eks terraform templates URL
-				return nil, fmt.Errorf("creating an EKS cluster via Terraform is not currently supported")
+				e := c.(*EKSCluster)
+				terraformVars := filepath.Join(path, "terraform.tfvars")
+				fmt.Fprintf(options.Out, "loading config from %s\n", util.ColorInfo(terraformVars))
+
+				e.ParseTfVarsFile(terraformVars)
+				clusterDefinitions = append(clusterDefinitions, e)
+
 			default:
 				return nil, fmt.Errorf("unknown Kubernetes provider type %s must be one of %v", c.Provider(), validTerraformClusterProviders)
 			}
@@ -701,6 +788,20 @@ func (options *CreateTerraformOptions) createClusters(dir string, clusterDefinit
 			if err != nil {
 				return err
 			}
+		case *AKSCluster:
+			path := filepath.Join(dir, Clusters, v.Name(), Terraform)
+			fmt.Fprintf(options.Out, "\n\nCreating/Updating cluster %s\n", util.ColorInfo(c.Name()))
+			err := options.applyTerraformAKS(v, path)
+			if err != nil {
+				return err
+			}
+		case *EKSCluster:
+			path := filepath.Join(dir, Clusters, v.Name(), Terraform)
+			fmt.Fprintf(options.Out, "\n\nCreating/Updating cluster %s\n", util.ColorInfo(c.Name()))
+			err := options.applyTerraformEKS(v, path)
+			if err != nil {
+				return err
+			}
 		default:
 			return fmt.Errorf("unknown Kubernetes provider type, must be one of %v, got %s", validTerraformClusterProviders, v)
 		}
@@ -833,41 +934,75 @@ func (options *CreateTerraformOptions) configureGKECluster(g *GKECluster, path s
 		}
 	}
 
-	if g.MinNumOfNodes == "" {
-		prompt := &survey.Input{
-			Message: "Minimum number of Nodes",
-			Default: "3",
-			Help:    "We recommend a minimum of 3 for Jenkins X,  the minimum number of nodes to be created in each of the cluster's zones",
-		}
+	err := options.askNodeCounts(&g.MinNumOfNodes, &g.MaxNumOfNodes)
+	if err != nil {
+		return err
+	}
 
-		err := survey.AskOne(prompt, &g.MinNumOfNodes, nil, surveyOpts)
-		if err != nil {
-			return err
+	terraformVars := filepath.Join(path, "terraform.tfvars")
+	err = g.CreateTfVarsFile(terraformVars)
+	if err != nil {
+		return err
+	}
+
+	storageBucket := fmt.Sprintf(gkeBucketConfiguration, validTerraformVersions, g.ProjectID, options.Flags.OrganisationName, g.Name())
+	options.Debugf("Using bucket configuration %s", storageBucket)
+
+	return writeTerraformBackend(path, storageBucket)
+}
+
+// askNodeCounts prompts for the minimum and maximum number of nodes of a cluster if they were not specified,
+// using the recommended defaults in batch mode
+func (options *CreateTerraformOptions) askNodeCounts(minNumOfNodes *string, maxNumOfNodes *string) error {
+	surveyOpts := survey.WithStdio(options.In, options.Out, options.Err)
+	if *minNumOfNodes == "" {
+		*minNumOfNodes = "3"
+		if !options.BatchMode {
+			prompt := &survey.Input{
+				Message: "Minimum number of Nodes",
+				Default: *minNumOfNodes,
+				Help:    "We recommend a minimum of 3 for Jenkins X,  the minimum number of nodes to be created in each of the cluster's zones",
+			}
+
+			err := survey.AskOne(prompt, minNumOfNodes, nil, surveyOpts)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
-	if g.MaxNumOfNodes == "" {
-		prompt := &survey.Input{
-			Message: "Maximum number of Nodes",
-			Default: "5",
-			Help:    "We recommend at least 5 for Jenkins X,  the maximum number of nodes to be created in each of the cluster's zones",
+	if *maxNumOfNodes == "" {
+		*maxNumOfNodes = "5"
+		if !options.BatchMode {
+			prompt := &survey.Input{
+				Message: "Maximum number of Nodes",
+				Default: *maxNumOfNodes,
+				Help:    "We recommend at least 5 for Jenkins X,  the maximum number of nodes to be created in each of the cluster's zones",
+			}
+
+			err := survey.AskOne(prompt, maxNumOfNodes, nil, surveyOpts)
+			if err != nil {
+				return err
+			}
 		}
+	}
+	return nil
+}
 
-		err := survey.AskOne(prompt, &g.MaxNumOfNodes, nil, surveyOpts)
+// writeTfVars writes the key value pairs to the tfvars file keeping any values which already exist
+func writeTfVars(path string, values [][]string) error {
+	for _, kv := range values {
+		err := terraform.WriteKeyValueToFileIfNotExists(path, kv[0], kv[1])
 		if err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
-	terraformVars := filepath.Join(path, "terraform.tfvars")
-	err := g.CreateTfVarsFile(terraformVars)
-	if err != nil {
-		return err
-	}
-
-	storageBucket := fmt.Sprintf(gkeBucketConfiguration, validTerraformVersions, g.ProjectID, options.Flags.OrganisationName, g.Name())
-	options.Debugf("Using bucket configuration %s", storageBucket)
-
+// writeTerraformBackend writes the backend configuration of the Terraform state to terraform.tf unless it
+// already exists
+func writeTerraformBackend(path string, backend string) error {
 	terraformTf := filepath.Join(path, "terraform.tf")
 	// file exists
 	if _, err := os.Stat(terraformTf); os.IsNotExist(err) {
@@ -877,27 +1012,23 @@ func (options *CreateTerraformOptions) configureGKECluster(g *GKECluster, path s
 		}
 		defer file.Close()
 
-		_, err = file.WriteString(storageBucket)
+		_, err = file.WriteString(backend)
 		if err != nil {
 			return err
 		}
 
 		log.Infof("Created %s\n", terraformTf)
 	}
-
 	return nil
 }
 
 func (options *CreateTerraformOptions) applyTerraformGKE(g *GKECluster, path string) error {
-	surveyOpts := survey.WithStdio(options.In, options.Out, options.Err)
 	if g.ProjectID == "" {
 		return errors.New("Unable to apply terraform, projectId has not been set")
 	}
 
 	log.Info("Applying Terraform changes\n")
 
-	terraformVars := filepath.Join(path, "terraform.tfvars")
-
 	if g.ServiceAccount == "" {
 		if options.Flags.GKEServiceAccount != "" {
 			g.ServiceAccount = options.Flags.GKEServiceAccount
@@ -945,14 +1076,33 @@ func (options *CreateTerraformOptions) applyTerraformGKE(g *GKECluster, path str
 		fmt.Fprintf(options.Out, "Created GCS bucket: %s in region %s\n", util.ColorInfo(bucketName), util.ColorInfo(g.Region()))
 	}
 
-	err = terraform.Init(path, serviceAccountPath)
+	applied, err := options.planAndApplyTerraform(path, serviceAccountPath)
+	if err != nil || !applied {
+		return err
+	}
+
+	output, err := options.getCommandOutput("", "gcloud", "container", "clusters", "get-credentials", g.ClusterName(), "--zone", g.Zone, "--project", g.ProjectID)
 	if err != nil {
 		return err
 	}
+	log.Info(output)
+	return nil
+}
+
+// planAndApplyTerraform initialises and shows the plan of the Terraform module in the given directory, then applies
+// it once confirmed. Returns true if the plan was applied
+func (options *CreateTerraformOptions) planAndApplyTerraform(path string, serviceAccountPath string) (bool, error) {
+	surveyOpts := survey.WithStdio(options.In, options.Out, options.Err)
+	terraformVars := filepath.Join(path, "terraform.tfvars")
+
+	err := terraform.Init(path, serviceAccountPath)
+	if err != nil {
+		return false, err
+	}
 
 	plan, err := terraform.Plan(path, terraformVars, serviceAccountPath)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	fmt.Fprintf(options.Out, plan)
@@ -966,7 +1116,7 @@ func (options *CreateTerraformOptions) applyTerraformGKE(g *GKECluster, path str
 
 		if !confirm {
 			// exit at this point
-			return nil
+			return false, nil
 		}
 	}
 
@@ -974,25 +1124,33 @@ func (options *CreateTerraformOptions) applyTerraformGKE(g *GKECluster, path str
 		if strings.Contains(plan, "forces new resource") {
 			fmt.Fprintf(options.Out, "%s\n", util.ColorError("It looks like this plan is destructive, aborting."))
 			fmt.Fprintf(options.Out, "Use --ignore-terraform-warnings to override\n")
-			return errors.New("aborting destructive plan")
+			return false, errors.New("aborting destructive plan")
 		}
 	}
 
-	if !options.Flags.SkipTerraformApply {
-		log.Info("Applying plan...\n")
+	if options.Flags.SkipTerraformApply {
+		fmt.Fprintf(options.Out, "Skipping Terraform apply\n")
+		return false, nil
+	}
 
-		err = terraform.Apply(path, terraformVars, serviceAccountPath, options.Out, options.Err)
-		if err != nil {
-			return err
-		}
+	log.Info("Applying plan...\n")
+	err = terraform.Apply(path, terraformVars, serviceAccountPath, options.Out, options.Err)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
 
-		output, err := options.getCommandOutput("", "gcloud", "container", "clusters", "get-credentials", g.ClusterName(), "--zone", g.Zone, "--project", g.ProjectID)
-		if err != nil {
-			return err
-		}
-		log.Info(output)
-	} else {
-		fmt.Fprintf(options.Out, "Skipping Terraform apply\n")
+// loginToProvider logs in to the command line tools of the cloud provider unless --skip-login is specified
+func (options *CreateTerraformOptions) loginToProvider(provider string) error {
+	if options.Flags.SkipLogin {
+		return nil
+	}
+	switch provider {
+	case GKE:
+		return options.runCommandVerbose("gcloud", "auth", "login", "--brief")
+	case AKS:
+		return options.runCommandVerbose("az", "login")
 	}
 	return nil
 }
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	os_user "os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/cloud/aks"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/terraform"
+	"github.com/jenkins-x/jx/pkg/util"
+	"gopkg.in/AlecAivazis/survey.v1"
+)
+
+// AKSCluster implements Cluster interface for AKS
+type AKSCluster struct {
+	name              string
+	provider          string
+	Organisation      string
+	Location          string
+	ResourceGroup     string
+	NodeVMSize        string
+	MinNumOfNodes     string
+	MaxNumOfNodes     string
+	DiskSize          string
+	KubernetesVersion string
+}
+
+var (
+	aksBackendConfiguration = `terraform {
+  required_version = ">= %s"
+  backend "azurerm" {
+    resource_group_name  = "%s"
+    storage_account_name = "%s"
+    container_name       = "terraform-state"
+    key                  = "%s.tfstate"
+  }
+}`
+
+	disallowedStorageAccountCharacters = regexp.MustCompile("[^a-z0-9]")
+)
+
+// Name Get name
+func (a AKSCluster) Name() string {
+	return a.name
+}
+
+// SetName Sets the name
+func (a *AKSCluster) SetName(name string) string {
+	a.name = name
+	return a.name
+}
+
+// ClusterName get cluster name
+func (a AKSCluster) ClusterName() string {
+	return fmt.Sprintf("%s-%s", a.Organisation, a.name)
+}
+
+// Provider get provider
+func (a AKSCluster) Provider() string {
+	return a.provider
+}
+
+// SetProvider Set the provider
+func (a *AKSCluster) SetProvider(provider string) string {
+	a.provider = provider
+	return a.provider
+}
+
+// Context Get the context
+func (a AKSCluster) Context() string {
+	return a.ClusterName()
+}
+
+// CreateTfVarsFile create vars
+func (a AKSCluster) CreateTfVarsFile(path string) error {
+	user, err := os_user.Current()
+	var username string
+	if err != nil {
+		username = "unknown"
+	} else {
+		username = sanitizeLabel(user.Username)
+	}
+	return writeTfVars(path, [][]string{
+		{"created_by", username},
+		{"created_timestamp", time.Now().Format("20060102150405")},
+		{"cluster_name", a.ClusterName()},
+		{"organisation", a.Organisation},
+		{"provider", a.provider},
+		{"aks_location", a.Location},
+		{"aks_resource_group", a.ResourceGroup},
+		{"node_vm_size", a.NodeVMSize},
+		{"min_node_count", a.MinNumOfNodes},
+		{"max_node_count", a.MaxNumOfNodes},
+		{"node_disk_size", a.DiskSize},
+		{"kubernetes_version", a.KubernetesVersion},
+	})
+}
+
+// ParseTfVarsFile Parse vars file
+func (a *AKSCluster) ParseTfVarsFile(path string) {
+	a.Organisation, _ = terraform.ReadValueFromFile(path, "organisation")
+	a.provider, _ = terraform.ReadValueFromFile(path, "provider")
+	a.Location, _ = terraform.ReadValueFromFile(path, "aks_location")
+	a.ResourceGroup, _ = terraform.ReadValueFromFile(path, "aks_resource_group")
+	a.NodeVMSize, _ = terraform.ReadValueFromFile(path, "node_vm_size")
+	a.MinNumOfNodes, _ = terraform.ReadValueFromFile(path, "min_node_count")
+	a.MaxNumOfNodes, _ = terraform.ReadValueFromFile(path, "max_node_count")
+	a.DiskSize, _ = terraform.ReadValueFromFile(path, "node_disk_size")
+	a.KubernetesVersion, _ = terraform.ReadValueFromFile(path, "kubernetes_version")
+}
+
+// aksStateResourceGroup returns the resource group of the storage account of the Terraform state of an organisation
+func aksStateResourceGroup(organisation string) string {
+	return fmt.Sprintf("jx-%s-terraform-state", organisation)
+}
+
+// aksStateStorageAccount returns the name of the storage account of the Terraform state of an organisation which
+// can only contain up to 24 lower case letters and numbers
+func aksStateStorageAccount(organisation string) string {
+	name := "jx" + disallowedStorageAccountCharacters.ReplaceAllString(strings.ToLower(organisation), "")
+	if len(name) > 19 {
+		name = name[0:19]
+	}
+	return name + "state"
+}
+
+func (options *CreateTerraformOptions) configureAKSCluster(a *AKSCluster, path string) error {
+	surveyOpts := survey.WithStdio(options.In, options.Out, options.Err)
+	a.Organisation = options.Flags.OrganisationName
+	a.Location = options.Flags.AKSLocation
+	a.ResourceGroup = options.Flags.AKSResourceGroup
+	a.NodeVMSize = options.Flags.AKSNodeVMSize
+	a.MinNumOfNodes = options.Flags.AKSMinNumOfNodes
+	a.MaxNumOfNodes = options.Flags.AKSMaxNumOfNodes
+	a.DiskSize = options.Flags.AKSDiskSize
+	a.KubernetesVersion = options.Flags.AKSKubernetesVersion
+
+	if a.ResourceGroup == "" {
+		a.ResourceGroup = a.ClusterName()
+	}
+
+	if a.Location == "" {
+		a.Location = "eastus"
+		if !options.BatchMode {
+			prompts := &survey.Select{
+				Message:  "Azure Location:",
+				Options:  aks.GetResourceGroupLocation(),
+				PageSize: 10,
+				Default:  a.Location,
+				Help:     "The Azure location of the resource group of the cluster",
+			}
+			err := survey.AskOne(prompts, &a.Location, nil, surveyOpts)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if a.NodeVMSize == "" {
+		a.NodeVMSize = "Standard_D2s_v3"
+		if !options.BatchMode {
+			prompts := &survey.Select{
+				Message:  "Azure Virtual Machine Size:",
+				Options:  aks.GetSizes(),
+				PageSize: 10,
+				Default:  a.NodeVMSize,
+				Help:     "We recommend a minimum of Standard_D2s_v3 for Jenkins X",
+			}
+			err := survey.AskOne(prompts, &a.NodeVMSize, nil, surveyOpts)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err := options.askNodeCounts(&a.MinNumOfNodes, &a.MaxNumOfNodes)
+	if err != nil {
+		return err
+	}
+
+	terraformVars := filepath.Join(path, "terraform.tfvars")
+	err = a.CreateTfVarsFile(terraformVars)
+	if err != nil {
+		return err
+	}
+	err = terraform.WriteAKSModule(path)
+	if err != nil {
+		return err
+	}
+	backend := fmt.Sprintf(aksBackendConfiguration, validTerraformVersions, aksStateResourceGroup(a.Organisation),
+		aksStateStorageAccount(a.Organisation), a.Name())
+	return writeTerraformBackend(path, backend)
+}
+
+func (options *CreateTerraformOptions) applyTerraformAKS(a *AKSCluster, path string) error {
+	log.Info("Applying Terraform changes\n")
+
+	if options.Flags.AKSClientID != "" {
+		os.Setenv("TF_VAR_client_id", options.Flags.AKSClientID)
+	}
+	if options.Flags.AKSClientSecret != "" {
+		os.Setenv("TF_VAR_client_secret", options.Flags.AKSClientSecret)
+	}
+	if os.Getenv("TF_VAR_client_id") == "" || os.Getenv("TF_VAR_client_secret") == "" {
+		return util.MissingOption("aks-client-id")
+	}
+
+	// create the storage of the terraform state
+	stateResourceGroup := aksStateResourceGroup(a.Organisation)
+	storageAccount := aksStateStorageAccount(a.Organisation)
+	err := options.RunCommand("az", "group", "create", "--name", stateResourceGroup, "--location", a.Location)
+	if err != nil {
+		return err
+	}
+	err = options.RunCommand("az", "storage", "account", "create", "--name", storageAccount, "--resource-group", stateResourceGroup,
+		"--location", a.Location, "--sku", "Standard_LRS")
+	if err != nil {
+		return err
+	}
+	err = options.RunCommand("az", "storage", "container", "create", "--name", "terraform-state", "--account-name", storageAccount)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(options.Out, "Using Azure storage account %s for the Terraform state\n", util.ColorInfo(storageAccount))
+
+	applied, err := options.planAndApplyTerraform(path, "")
+	if err != nil || !applied {
+		return err
+	}
+	output, err := options.getCommandOutput("", "az", "aks", "get-credentials", "--resource-group", a.ResourceGroup, "--name", a.ClusterName())
+	if err != nil {
+		return err
+	}
+	log.Info(output)
+	return nil
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	os_user "os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/terraform"
+	"github.com/jenkins-x/jx/pkg/util"
+	"gopkg.in/AlecAivazis/survey.v1"
+)
+
+// EKSCluster implements Cluster interface for EKS
+type EKSCluster struct {
+	name              string
+	provider          string
+	Organisation      string
+	Region            string
+	NodeInstanceType  string
+	MinNumOfNodes     string
+	MaxNumOfNodes     string
+	DiskSize          string
+	KubernetesVersion string
+}
+
+var (
+	eksBackendConfiguration = `terraform {
+  required_version = ">= %s"
+  backend "s3" {
+    bucket = "%s"
+    key    = "%s/terraform.tfstate"
+    region = "%s"
+  }
+}`
+)
+
+// Name Get name
+func (e EKSCluster) Name() string {
+	return e.name
+}
+
+// SetName Sets the name
+func (e *EKSCluster) SetName(name string) string {
+	e.name = name
+	return e.name
+}
+
+// ClusterName get cluster name
+func (e EKSCluster) ClusterName() string {
+	return fmt.Sprintf("%s-%s", e.Organisation, e.name)
+}
+
+// Provider get provider
+func (e EKSCluster) Provider() string {
+	return e.provider
+}
+
+// SetProvider Set the provider
+func (e *EKSCluster) SetProvider(provider string) string {
+	e.provider = provider
+	return e.provider
+}
+
+// Context Get the context which is the alias used when updating the kube config
+func (e EKSCluster) Context() string {
+	return e.ClusterName()
+}
+
+// CreateTfVarsFile create vars
+func (e EKSCluster) CreateTfVarsFile(path string) error {
+	user, err := os_user.Current()
+	var username string
+	if err != nil {
+		username = "unknown"
+	} else {
+		username = sanitizeLabel(user.Username)
+	}
+	return writeTfVars(path, [][]string{
+		{"created_by", username},
+		{"created_timestamp", time.Now().Format("20060102150405")},
+		{"cluster_name", e.ClusterName()},
+		{"organisation", e.Organisation},
+		{"provider", e.provider},
+		{"aws_region", e.Region},
+		{"node_instance_type", e.NodeInstanceType},
+		{"min_node_count", e.MinNumOfNodes},
+		{"max_node_count", e.MaxNumOfNodes},
+		{"node_disk_size", e.DiskSize},
+		{"kubernetes_version", e.KubernetesVersion},
+	})
+}
+
+// ParseTfVarsFile Parse vars file
+func (e *EKSCluster) ParseTfVarsFile(path string) {
+	e.Organisation, _ = terraform.ReadValueFromFile(path, "organisation")
+	e.provider, _ = terraform.ReadValueFromFile(path, "provider")
+	e.Region, _ = terraform.ReadValueFromFile(path, "aws_region")
+	e.NodeInstanceType, _ = terraform.ReadValueFromFile(path, "node_instance_type")
+	e.MinNumOfNodes, _ = terraform.ReadValueFromFile(path, "min_node_count")
+	e.MaxNumOfNodes, _ = terraform.ReadValueFromFile(path, "max_node_count")
+	e.DiskSize, _ = terraform.ReadValueFromFile(path, "node_disk_size")
+	e.KubernetesVersion, _ = terraform.ReadValueFromFile(path, "kubernetes_version")
+}
+
+// eksStateBucket returns the S3 bucket of the Terraform state of an organisation
+func eksStateBucket(organisation string) string {
+	return fmt.Sprintf("jx-%s-terraform-state", organisation)
+}
+
+func (options *CreateTerraformOptions) configureEKSCluster(e *EKSCluster, path string) error {
+	surveyOpts := survey.WithStdio(options.In, options.Out, options.Err)
+	e.Organisation = options.Flags.OrganisationName
+	e.Region = options.Flags.EKSRegion
+	e.NodeInstanceType = options.Flags.EKSNodeInstanceType
+	e.MinNumOfNodes = options.Flags.EKSMinNumOfNodes
+	e.MaxNumOfNodes = options.Flags.EKSMaxNumOfNodes
+	e.DiskSize = options.Flags.EKSDiskSize
+	e.KubernetesVersion = options.Flags.EKSKubernetesVersion
+
+	if e.Region == "" {
+		region, err := amazon.ResolveRegionWithoutOptions()
+		if err != nil {
+			return err
+		}
+		e.Region = region
+		if !options.BatchMode {
+			prompt := &survey.Input{
+				Message: "AWS Region:",
+				Default: e.Region,
+				Help:    "The AWS region (e.g. us-west-2) to create the cluster in",
+			}
+			err = survey.AskOne(prompt, &e.Region, nil, surveyOpts)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if e.NodeInstanceType == "" {
+		e.NodeInstanceType = "m5.large"
+		if !options.BatchMode {
+			prompt := &survey.Input{
+				Message: "AWS EC2 Instance Type:",
+				Default: e.NodeInstanceType,
+				Help:    "We recommend a minimum of m5.large for Jenkins X",
+			}
+			err := survey.AskOne(prompt, &e.NodeInstanceType, nil, surveyOpts)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err := options.askNodeCounts(&e.MinNumOfNodes, &e.MaxNumOfNodes)
+	if err != nil {
+		return err
+	}
+
+	terraformVars := filepath.Join(path, "terraform.tfvars")
+	err = e.CreateTfVarsFile(terraformVars)
+	if err != nil {
+		return err
+	}
+	err = terraform.WriteEKSModule(path)
+	if err != nil {
+		return err
+	}
+	backend := fmt.Sprintf(eksBackendConfiguration, validTerraformVersions, eksStateBucket(e.Organisation), e.Name(), e.Region)
+	return writeTerraformBackend(path, backend)
+}
+
+func (options *CreateTerraformOptions) applyTerraformEKS(e *EKSCluster, path string) error {
+	log.Info("Applying Terraform changes\n")
+
+	// create the bucket of the terraform state
+	bucketName := eksStateBucket(e.Organisation)
+	exists, err := amazon.S3BucketExists(bucketName, "", e.Region)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		_, err = amazon.CreateS3Bucket(bucketName, "", e.Region)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(options.Out, "Created S3 bucket: %s in region %s\n", util.ColorInfo(bucketName), util.ColorInfo(e.Region))
+	}
+
+	applied, err := options.planAndApplyTerraform(path, "")
+	if err != nil || !applied {
+		return err
+	}
+	output, err := options.getCommandOutput("", "aws", "eks", "update-kubeconfig", "--name", e.ClusterName(), "--region", e.Region, "--alias", e.Context())
+	if err != nil {
+		return err
+	}
+	log.Info(output)
+	return nil
+}
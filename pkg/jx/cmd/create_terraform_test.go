@@ -1,6 +1,10 @@
 package cmd_test
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/jenkins-x/jx/pkg/jx/cmd"
@@ -33,8 +37,97 @@ func TestValidateClusterDetails(t *testing.T) {
 func TestValidateClusterDetailsFail(t *testing.T) {
 	t.Parallel()
 	o := cmd.CreateTerraformOptions{
-		Flags: cmd.Flags{Cluster: []string{"foo=gke", "bar=aks"}},
+		Flags: cmd.Flags{Cluster: []string{"foo=gke", "bar=foo"}},
 	}
 	err := o.ValidateClusterDetails()
 	assert.Error(t, err)
 }
+
+func TestValidateClusterDetailsAKSAndEKS(t *testing.T) {
+	t.Parallel()
+	o := cmd.CreateTerraformOptions{
+		Flags: cmd.Flags{Cluster: []string{"foo=aks", "bar=eks"}},
+	}
+	err := o.ValidateClusterDetails()
+	assert.NoError(t, err)
+	assert.Len(t, o.Clusters, 2)
+
+	_, ok := o.Clusters[0].(*cmd.AKSCluster)
+	assert.True(t, ok, "expected an AKSCluster")
+	_, ok = o.Clusters[1].(*cmd.EKSCluster)
+	assert.True(t, ok, "expected an EKSCluster")
+}
+
+func TestCreateOrganisationFolderStructureAKS(t *testing.T) {
+	t.Parallel()
+	assertTerraformGolden(t, "aks", cmd.Flags{
+		AKSLocation:          "westeurope",
+		AKSNodeVMSize:        "Standard_D2s_v3",
+		AKSMinNumOfNodes:     "3",
+		AKSMaxNumOfNodes:     "5",
+		AKSDiskSize:          "100",
+		AKSKubernetesVersion: "1.11.5",
+	})
+}
+
+func TestCreateOrganisationFolderStructureEKS(t *testing.T) {
+	t.Parallel()
+	assertTerraformGolden(t, "eks", cmd.Flags{
+		EKSRegion:            "us-west-2",
+		EKSNodeInstanceType:  "m5.large",
+		EKSMinNumOfNodes:     "3",
+		EKSMaxNumOfNodes:     "5",
+		EKSDiskSize:          "100",
+		EKSKubernetesVersion: "1.10",
+	})
+}
+
+// assertTerraformGolden generates the Terraform module of a dev cluster of the provider and compares it with the
+// files in test_data/terraform/<provider>
+func assertTerraformGolden(t *testing.T, provider string, flags cmd.Flags) {
+	dir, err := ioutil.TempDir("", "test-create-terraform-"+provider)
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	flags.OrganisationName = "myorg"
+	flags.Cluster = []string{"dev=" + provider}
+	o := cmd.CreateTerraformOptions{
+		CreateOptions: cmd.CreateOptions{
+			CommonOptions: cmd.CommonOptions{
+				BatchMode: true,
+				In:        os.Stdin,
+				Out:       os.Stdout,
+				Err:       os.Stdout,
+			},
+		},
+		Flags: flags,
+	}
+	err = o.ValidateClusterDetails()
+	assert.NoError(t, err)
+	clusters, err := o.CreateOrganisationFolderStructure(dir)
+	assert.NoError(t, err)
+	assert.Len(t, clusters, 1)
+
+	goldenDir := filepath.Join("test_data", "terraform", provider)
+	goldenFiles, err := ioutil.ReadDir(goldenDir)
+	assert.NoError(t, err)
+	for _, f := range goldenFiles {
+		expected, err := ioutil.ReadFile(filepath.Join(goldenDir, f.Name()))
+		assert.NoError(t, err)
+		actual, err := ioutil.ReadFile(filepath.Join(dir, cmd.Clusters, "dev", cmd.Terraform, f.Name()))
+		assert.NoError(t, err)
+		assert.Equal(t, string(expected), stripGeneratedTfVars(string(actual)), "generated %s", f.Name())
+	}
+}
+
+// stripGeneratedTfVars removes the variables which depend on the user and time of the generation
+func stripGeneratedTfVars(text string) string {
+	lines := []string{}
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if strings.HasPrefix(line, "created_by ") || strings.HasPrefix(line, "created_timestamp ") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "")
+}
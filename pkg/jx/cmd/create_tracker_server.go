@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/jenkins-x/jx/pkg/issues"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
@@ -19,6 +20,15 @@ var (
 	createTrackerServer_example = templates.Examples(`
 		# Add a new issue tracker server URL
 		jx create tracker server jira myURL
+
+		# Add a YouTrack server
+		jx create tracker server youtrack https://myorg.myjetbrains.com/youtrack
+
+		# Add the issues of GitLab projects as an issue tracker
+		jx create tracker server gitlab https://gitlab.com
+
+		# Add an Azure DevOps organisation to use Azure Boards work items as issues
+		jx create tracker server azureboards https://dev.azure.com/myorg
 	`)
 
 	trackerKindToServiceName = map[string]string{
@@ -71,6 +81,9 @@ func (o *CreateTrackerServerOptions) Run() error {
 		return missingTrackerArguments()
 	}
 	kind := args[0]
+	if util.StringArrayIndex(issues.IssueTrackerKinds, kind) < 0 && trackerKindToServiceName[kind] == "" {
+		return util.InvalidArg(kind, issues.IssueTrackerKinds)
+	}
 	name := o.Name
 	if name == "" {
 		name = kind
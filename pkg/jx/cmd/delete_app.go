@@ -230,7 +230,7 @@ func (o *DeleteAppOptions) deleteAppFromEnvironment(env *v1.Environment, appName
 		requirements.RemoveApp(appName)
 		return nil
 	}
-	info, err := o.createEnvironmentPullRequest(env, modifyRequirementsFn, branchName, title, message, nil, o.ConfigureGitCallback)
+	info, err := o.createEnvironmentPullRequest(env, modifyRequirementsFn, nil, branchName, title, message, nil, o.ConfigureGitCallback)
 	if err != nil {
 		return err
 	}
@@ -128,6 +128,9 @@ func (o *DeleteEnvOptions) deleteEnviroment(jxClient versioned.Interface, ns str
 		return fmt.Errorf("No namespace for environment %s", name)
 	}
 	kind := env.Spec.Kind
+	if kind == v1.EnvironmentKindTypePreview {
+		o.deletePreviewDependencies(env)
+	}
 	if o.DeleteNamespace || !kind.IsPermanent() {
 		return o.KubeClientCached.CoreV1().Namespaces().Delete(envNs, &metav1.DeleteOptions{})
 	}
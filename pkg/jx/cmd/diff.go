@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// DiffOptions the command line options
+type DiffOptions struct {
+	CommonOptions
+}
+
+var (
+	diffLong = templates.LongDesc(`
+		Displays the differences between the desired configuration of Jenkins X and the cluster
+`)
+
+	diffExample = templates.Examples(`
+		# Display the differences between an install manifest and the cluster
+		jx diff install --file jx-install.yaml
+	`)
+)
+
+// NewCmdDiff creates the command
+func NewCmdDiff(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &DiffOptions{
+		CommonOptions: CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "diff TYPE [flags]",
+		Short:   "Displays the differences between the desired configuration of Jenkins X and the cluster",
+		Long:    diffLong,
+		Example: diffExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.AddCommand(NewCmdDiffInstall(f, in, out, errOut))
+	return cmd
+}
+
+// Run implements this command
+func (o *DiffOptions) Run() error {
+	return o.Cmd.Help()
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// DiffInstallOptions the command line options
+type DiffInstallOptions struct {
+	GetOptions
+
+	File string
+}
+
+var (
+	diffInstallLong = templates.LongDesc(`
+		Displays the differences between an install manifest used by 'jx install --file' and the installation in the cluster.
+
+		The provider, domain, Docker registry, default storage class, the permanent Environments, the installed addons
+		and the TeamSettings of the development environment are compared. Only the values specified in the manifest
+		are compared other than the environments and addons which are expected to match exactly.
+`)
+
+	diffInstallExample = templates.Examples(`
+		# Display the differences between an install manifest and the cluster
+		jx diff install --file jx-install.yaml
+
+		# Display the differences as JSON
+		jx diff install --file jx-install.yaml -o json
+	`)
+)
+
+// NewCmdDiffInstall creates the command
+func NewCmdDiffInstall(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &DiffInstallOptions{
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "install",
+		Short:   "Displays the differences between an install manifest and the installation in the cluster",
+		Long:    diffInstallLong,
+		Example: diffInstallExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.File, "file", "f", "", "The install manifest YAML file")
+
+	options.addGetFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *DiffInstallOptions) Run() error {
+	if o.File == "" {
+		return util.MissingOption("file")
+	}
+	desired, err := LoadInstallManifest(o.File)
+	if err != nil {
+		return err
+	}
+	live, err := o.liveInstallManifest()
+	if err != nil {
+		return err
+	}
+	drifts, err := DiffInstallManifest(desired, live)
+	if err != nil {
+		return err
+	}
+	if len(drifts) == 0 && !o.isMachineOutput() {
+		log.Infof("The installation matches the install manifest %s\n", util.ColorInfo(o.File))
+		return nil
+	}
+
+	details := []InstallDrift{}
+	table := o.CreateTable()
+	table.AddRow("RESOURCE", "NAME", "CHANGE", "DETAIL")
+	for _, drift := range drifts {
+		detail := drift.Detail
+		if drift.Resource == "teamsettings" {
+			details = append(details, drift)
+			detail = "see below"
+		}
+		table.AddItem(drift, drift.Resource, drift.Name, drift.Change, detail)
+	}
+	table.Render()
+	if !o.isMachineOutput() {
+		for _, drift := range details {
+			log.Infof("\n%s %s:\n%s\n", util.ColorInfo(drift.Resource), drift.Change, drift.Detail)
+		}
+	}
+	return nil
+}
@@ -39,6 +39,7 @@ type EditEnvOptions struct {
 	Options                v1.Environment
 	HelmValuesConfig       config.HelmValuesConfig
 	PromotionStrategy      string
+	Rollout                RolloutFlags
 	NoGitOps               bool
 	ForkEnvironmentGitRepo string
 	EnvJobCredentials      string
@@ -88,6 +89,7 @@ func NewCmdEditEnv(f Factory, in terminal.FileReader, out terminal.FileWriter, e
 	cmd.Flags().StringVarP(&options.Prefix, "prefix", "", "jx", "Environment repo prefix, your Git repo will be of the form 'environment-$prefix-$envName'")
 
 	cmd.Flags().StringVarP(&options.PromotionStrategy, "promotion", "p", "", "The promotion strategy")
+	options.Rollout.addRolloutFlags(cmd)
 	cmd.Flags().StringVarP(&options.ForkEnvironmentGitRepo, "fork-git-repo", "f", kube.DefaultEnvironmentGitRepoURL, "The Git repository used as the fork when creating new Environment Git repos")
 	cmd.Flags().StringVarP(&options.EnvJobCredentials, "env-job-credentials", "", "", "The Jenkins credentials used by the GitOps Job for this environment")
 	cmd.Flags().StringVarP(&options.BranchPattern, "branches", "", "", "The branch pattern for branches to trigger CI/CD pipelines on the environment Git repository")
@@ -156,6 +158,10 @@ func (o *EditEnvOptions) Run() error {
 		return err
 	}
 	o.Options.Spec.PromotionStrategy = v1.PromotionStrategyType(o.PromotionStrategy)
+	o.Options.Spec.Rollout, err = o.Rollout.createRolloutStrategy()
+	if err != nil {
+		return err
+	}
 	gitProvider, err := kube.CreateEnvironmentSurvey(o.BatchMode, authConfigSvc, devEnv, env, &o.Options, o.ForkEnvironmentGitRepo,
 		ns, jxClient, kubeClient, envDir, &o.GitRepositoryOptions, o.HelmValuesConfig, o.Prefix, o.Git(), o.In, o.Out, o.Err)
 	if err != nil {
@@ -21,6 +21,7 @@ const (
 	valid_gc_resources = `Valid resource types include:
 
     * activities
+	* devpods
 	* helm
 	* previews
 	* releases
@@ -37,6 +38,7 @@ var (
 
 	gc_example = templates.Examples(`
 		jx gc activities
+		jx gc devpods
 		jx gc gke
 		jx gc helm
 		jx gc previews
@@ -71,6 +73,7 @@ func NewCmdGC(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut
 	}
 
 	cmd.AddCommand(NewCmdGCActivities(f, in, out, errOut))
+	cmd.AddCommand(NewCmdGCDevPods(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGCPreviews(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGCGKE(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGCHelm(f, in, out, errOut))
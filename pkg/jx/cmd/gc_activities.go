@@ -3,11 +3,14 @@ package cmd
 import (
 	"fmt"
 	"io"
-	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/storage"
+	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -22,19 +25,36 @@ import (
 type GCActivitiesOptions struct {
 	CommonOptions
 
-	RevisionHistoryLimit int
-	jclient              gojenkins.JenkinsClient
+	RevisionHistoryLimit     int
+	DryRun                   bool
+	DeleteClosedPullRequests bool
+	Archive                  v1.ActivityArchiveSpec
+	jclient                  gojenkins.JenkinsClient
 }
 
 var (
 	GCActivitiesLong = templates.LongDesc(`
 		Garbage collect the Jenkins X Activity Custom Resource Definitions
 
+		The activities to keep are defined by the activityRetention policy in the team settings of the dev Environment.
+		The policy can keep activities by age, count and status for branches matching a regular expression, keep every
+		activity which promoted to environments such as production and delete the activities of closed pull requests.
+		Activities matching no rule are kept using the revision history limit of each pipeline.
+
+		Activities can be archived to a local tarball, an S3 bucket or the Elasticsearch of the pipeline-events addon
+		before they are deleted. The files collected for the deleted activities by 'jx step collect' are deleted too.
+
 `)
 
 	GCActivitiesExample = templates.Examples(`
 		jx garbage collect activities
 		jx gc activities
+
+		# Report which activities would be deleted and why
+		jx gc activities --dry-run
+
+		# Archive the activities to an S3 bucket before deleting them
+		jx gc activities --archive s3 --archive-bucket my-activities
 `)
 )
 
@@ -62,6 +82,12 @@ func NewCmdGCActivities(f Factory, in terminal.FileReader, out terminal.FileWrit
 		},
 	}
 	cmd.Flags().IntVarP(&options.RevisionHistoryLimit, "revision-history-limit", "l", 5, "Minimum number of Activities per application to keep")
+	cmd.Flags().BoolVarP(&options.DryRun, "dry-run", "", false, "Reports which activities would be deleted and why without deleting them")
+	cmd.Flags().BoolVarP(&options.DeleteClosedPullRequests, "delete-closed-prs", "", false, "Deletes the activities of pull requests which are closed or merged")
+	cmd.Flags().StringVarP((*string)(&options.Archive.Kind), "archive", "", "", "Where to archive activities before they are deleted. One of: tarball, s3 or elasticsearch. Defaults to the archive of the team settings")
+	cmd.Flags().StringVarP(&options.Archive.Dir, "archive-dir", "", "", "The directory the tarball archives are written to. Defaults to ~/.jx/archive/activities")
+	cmd.Flags().StringVarP(&options.Archive.Bucket, "archive-bucket", "", "", "The S3 bucket the archives are uploaded to")
+	cmd.Flags().StringVarP(&options.Archive.Region, "archive-region", "", "", "The region of the S3 bucket")
 	options.addCommonFlags(cmd)
 	return cmd
 }
@@ -100,12 +126,32 @@ func (o *GCActivitiesOptions) Run() error {
 		return nil
 	}
 
+	teamSettings, err := o.TeamSettings()
+	if err != nil {
+		return err
+	}
+	policy := &v1.ActivityRetentionPolicy{}
+	if teamSettings.ActivityRetention != nil {
+		policy = teamSettings.ActivityRetention.DeepCopy()
+	}
+	if o.DeleteClosedPullRequests {
+		policy.DeleteClosedPullRequests = true
+	}
+	archiveSpec := policy.Archive
+	if o.Archive.Kind != "" {
+		archiveSpec = &o.Archive
+	}
+	archiver, err := o.createActivityArchiver(archiveSpec)
+	if err != nil {
+		return err
+	}
+
 	prowEnabled, err := kube.IsProwEnabled(kubeClient, currentNs)
 	if err != nil {
 		return err
 	}
 
-	var jobNames []string
+	jobNames := map[string]bool{}
 	if !prowEnabled {
 		o.jclient, err = o.JenkinsClient()
 		if err != nil {
@@ -116,62 +162,140 @@ func (o *GCActivitiesOptions) Run() error {
 		if err != nil {
 			return err
 		}
+		names := []string{}
 		for _, j := range jobs {
-			err = o.getAllPipelineJobNames(o.jclient, &jobNames, j.Name)
+			err = o.getAllPipelineJobNames(o.jclient, &names, j.Name)
 			if err != nil {
 				return err
 			}
 		}
+		for _, name := range names {
+			jobNames[name] = true
+		}
 	}
 
-	activityBuilds := make(map[string][]int)
-
-	for _, a := range activities.Items {
-		if !prowEnabled {
+	closedPullRequests := map[string]bool{}
+	retention := &kube.ActivityRetention{
+		Policy:               policy,
+		RevisionHistoryLimit: o.RevisionHistoryLimit,
+		Expired: func(a *v1.PipelineActivity) string {
 			// if activity has no job in Jenkins delete it
-			matched := false
-			for _, j := range jobNames {
-				if a.Spec.Pipeline == j {
-					matched = true
-					break
-				}
+			if !prowEnabled && !jobNames[a.Spec.Pipeline] {
+				return "no Jenkins job"
 			}
-			if !matched {
-				err = client.JenkinsV1().PipelineActivities(currentNs).Delete(a.Name, metav1.NewDeleteOptions(0))
-				if err != nil {
-					return err
+			if policy.DeleteClosedPullRequests && kube.IsPullRequestBranch(a.BranchName()) {
+				closed, ok := closedPullRequests[a.Spec.Pipeline]
+				if !ok {
+					closed = o.isPullRequestClosed(a)
+					closedPullRequests[a.Spec.Pipeline] = closed
 				}
+				if closed {
+					return "pull request closed"
+				}
+			}
+			return ""
+		},
+	}
+	decisions, err := retention.Decide(activities.Items)
+	if err != nil {
+		return err
+	}
+
+	deletions := []*kube.ActivityRetentionDecision{}
+	deleted := []*v1.PipelineActivity{}
+	for _, decision := range decisions {
+		if decision.Delete {
+			deletions = append(deletions, decision)
+			deleted = append(deleted, decision.Activity)
+		}
+	}
+	if o.DryRun {
+		table := o.CreateTable()
+		table.AddRow("NAME", "STATUS", "ACTION", "REASON")
+		for _, decision := range decisions {
+			action := "keep"
+			if decision.Delete {
+				action = "delete"
 			}
+			table.AddRow(decision.Activity.Name, string(decision.Activity.Spec.Status), action, decision.Reason)
 		}
+		table.Render()
+		log.Infof("Would delete %s of %s activities\n", util.ColorInfo(strconv.Itoa(len(deleted))), util.ColorInfo(strconv.Itoa(len(decisions))))
+		return nil
+	}
+	if len(deleted) == 0 {
+		return nil
+	}
 
-		buildNumber, err := strconv.Atoi(a.Spec.Build)
+	if archiver != nil {
+		location, err := archiver.Archive(deleted)
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to archive the activities so not deleting them: %s", err)
 		}
-		// collect all activities for a pipeline
-		activityBuilds[a.Spec.Pipeline] = append(activityBuilds[a.Spec.Pipeline], buildNumber)
+		log.Infof("Archived %d activities to %s\n", len(deleted), util.ColorInfo(location))
 	}
+	for _, decision := range deletions {
+		name := decision.Activity.Name
+		err = client.JenkinsV1().PipelineActivities(currentNs).Delete(name, metav1.NewDeleteOptions(0))
+		if err != nil {
+			return fmt.Errorf("failed to delete activity %s: %v\n", name, err)
+		}
+		if o.Verbose {
+			log.Infof("Deleted activity %s: %s\n", util.ColorInfo(name), decision.Reason)
+		}
+		o.deleteAttachments(decision.Activity)
+	}
+	return nil
+}
 
-	for pipeline, builds := range activityBuilds {
-
-		sort.Ints(builds)
-
-		// iterate over the build numbers and delete any while the activity is under the RevisionHistoryLimit
-		i := 0
-		for i < len(builds)-o.RevisionHistoryLimit {
-			activityName := fmt.Sprintf("%s-%v", pipeline, builds[i])
-			activityName = strings.Replace(activityName, "/", "-", -1)
-			activityName = strings.Replace(activityName, "_", "-", -1)
-			activityName = strings.ToLower(activityName)
-
-			err = client.JenkinsV1().PipelineActivities(currentNs).Delete(activityName, metav1.NewDeleteOptions(0))
+// deleteAttachments deletes the files collected by 'jx step collect' for the activity
+func (o *GCActivitiesOptions) deleteAttachments(a *v1.PipelineActivity) {
+	for _, attachment := range a.Spec.Attachments {
+		for _, u := range attachment.URLs {
+			err := storage.DeleteURL(u)
 			if err != nil {
-				return fmt.Errorf("failed to delete activity %s: %v\n", activityName, err)
+				log.Warnf("Failed to delete attachment %s of activity %s: %s\n", u, a.Name, err)
 			}
-
-			i++
 		}
 	}
+}
 
-	return nil
+// isPullRequestClosed returns true if the pull request of the activity is closed or merged
+func (o *GCActivitiesOptions) isPullRequestClosed(a *v1.PipelineActivity) bool {
+	gitURL := a.Spec.GitURL
+	if gitURL == "" {
+		return false
+	}
+	prNum, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(a.BranchName()), "PR-"))
+	if err != nil {
+		log.Warnf("Unable to convert PR %s to a number\n", a.BranchName())
+		return false
+	}
+	gitInfo, err := gits.ParseGitURL(gitURL)
+	if err != nil {
+		log.Warnf("Failed to parse git URL %s: %s\n", gitURL, err)
+		return false
+	}
+	authConfigSvc, err := o.CreateGitAuthConfigService()
+	if err != nil {
+		log.Warnf("%s\n", err)
+		return false
+	}
+	gitKind, err := o.GitServerKind(gitInfo)
+	if err != nil {
+		log.Warnf("%s\n", err)
+		return false
+	}
+	gitProvider, err := gitInfo.CreateProvider(authConfigSvc, gitKind, o.Git(), o.BatchMode, o.In, o.Out, o.Err)
+	if err != nil {
+		log.Warnf("%s\n", err)
+		return false
+	}
+	pullRequest, err := gitProvider.GetPullRequest(gitInfo.Organisation, gitInfo, prNum)
+	if err != nil || pullRequest.State == nil {
+		log.Warnf("Can not get pull request %s, keeping its activities: %v\n", a.BranchName(), err)
+		return false
+	}
+	lowerState := strings.ToLower(*pullRequest.State)
+	return strings.HasPrefix(lowerState, "clos") || strings.HasPrefix(lowerState, "merged") || strings.HasPrefix(lowerState, "superseded") || strings.HasPrefix(lowerState, "declined")
 }
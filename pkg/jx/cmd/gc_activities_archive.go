@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	pe "github.com/jenkins-x/jx/pkg/pipeline_events"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// ActivityArchiver archives PipelineActivity resources before they are garbage collected
+type ActivityArchiver interface {
+	// Archive archives the activities returning where they were archived to
+	Archive(activities []*v1.PipelineActivity) (string, error)
+}
+
+// TarballActivityArchiver archives activities as a gzipped tarball of YAML files in a directory
+type TarballActivityArchiver struct {
+	Dir string
+}
+
+// S3ActivityArchiver archives activities as a gzipped tarball of YAML files in an S3 bucket
+type S3ActivityArchiver struct {
+	Bucket string
+	Region string
+}
+
+// ElasticsearchActivityArchiver archives activities by sending them to the Elasticsearch of the pipeline-events addon
+type ElasticsearchActivityArchiver struct {
+	Provider pe.PipelineEventsProvider
+}
+
+// Archive writes the activities to a new tarball in the directory
+func (a *TarballActivityArchiver) Archive(activities []*v1.PipelineActivity) (string, error) {
+	err := os.MkdirAll(a.Dir, util.DefaultWritePermissions)
+	if err != nil {
+		return "", err
+	}
+	fileName := filepath.Join(a.Dir, activitiesArchiveName())
+	f, err := os.Create(fileName)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	err = writeActivitiesTarball(f, activities)
+	if err != nil {
+		return "", fmt.Errorf("failed to write %s: %s", fileName, err)
+	}
+	return fileName, nil
+}
+
+// Archive uploads a tarball of the activities to the bucket
+func (a *S3ActivityArchiver) Archive(activities []*v1.PipelineActivity) (string, error) {
+	var buffer bytes.Buffer
+	err := writeActivitiesTarball(&buffer, activities)
+	if err != nil {
+		return "", err
+	}
+	return amazon.PutS3Object(a.Bucket, path.Join("activities", activitiesArchiveName()), bytes.NewReader(buffer.Bytes()), "", a.Region)
+}
+
+// Archive sends each of the activities to Elasticsearch
+func (a *ElasticsearchActivityArchiver) Archive(activities []*v1.PipelineActivity) (string, error) {
+	for _, activity := range activities {
+		err := a.Provider.SendActivity(activity)
+		if err != nil {
+			return "", fmt.Errorf("failed to archive activity %s: %s", activity.Name, err)
+		}
+	}
+	return "elasticsearch", nil
+}
+
+// createActivityArchiver creates the archiver for the archive configuration or nil if activities are not archived
+func (o *CommonOptions) createActivityArchiver(spec *v1.ActivityArchiveSpec) (ActivityArchiver, error) {
+	if spec == nil || spec.Kind == "" {
+		return nil, nil
+	}
+	switch spec.Kind {
+	case v1.ActivityArchiveKindTypeTarball:
+		dir := spec.Dir
+		if dir == "" {
+			configDir, err := util.ConfigDir()
+			if err != nil {
+				return nil, err
+			}
+			dir = filepath.Join(configDir, "archive", "activities")
+		}
+		return &TarballActivityArchiver{Dir: dir}, nil
+	case v1.ActivityArchiveKindTypeS3:
+		if spec.Bucket == "" {
+			return nil, util.MissingOption("archive-bucket")
+		}
+		return &S3ActivityArchiver{Bucket: spec.Bucket, Region: spec.Region}, nil
+	case v1.ActivityArchiveKindTypeElasticsearch:
+		provider, err := o.createElasticsearchPipelineEventsProvider()
+		if err != nil {
+			return nil, err
+		}
+		return &ElasticsearchActivityArchiver{Provider: provider}, nil
+	}
+	return nil, util.InvalidOption("archive", string(spec.Kind), []string{string(v1.ActivityArchiveKindTypeTarball), string(v1.ActivityArchiveKindTypeS3), string(v1.ActivityArchiveKindTypeElasticsearch)})
+}
+
+func activitiesArchiveName() string {
+	return fmt.Sprintf("activities-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+}
+
+// writeActivitiesTarball writes the activities as YAML files to a gzipped tarball
+func writeActivitiesTarball(w io.Writer, activities []*v1.PipelineActivity) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, activity := range activities {
+		data, err := yaml.Marshal(activity)
+		if err != nil {
+			return err
+		}
+		err = tw.WriteHeader(&tar.Header{
+			Name:    activity.Name + ".yml",
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		if err != nil {
+			return err
+		}
+	}
+	err := tw.Close()
+	if err != nil {
+		return err
+	}
+	return gz.Close()
+}
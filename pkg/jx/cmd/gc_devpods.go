@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// GCDevPodsOptions contains the CLI options for this command
+type GCDevPodsOptions struct {
+	CommonOptions
+
+	IdleTimeout time.Duration
+}
+
+var (
+	GCDevPodsLong = templates.LongDesc(`
+		Garbage collect DevPods which are idle.
+
+		A DevPod is idle if there have been no shells into it or files synchronised with it for longer than its idle
+		timeout. The idle timeout is the 'devpod.idleTimeout' of the jenkins-x.yml the DevPod was created from or the
+		--idle-timeout flag.
+
+		Only shells opened via 'jx rsh --devpod' or 'jx create devpod' and files synchronised via 'jx sync' are recorded
+		as activity. Using the Theia IDE, running 'kubectl exec' or synchronising files via ksync with --ksync is not
+		recorded so a DevPod only used that way looks idle. Give such DevPods a longer 'devpod.idleTimeout' or keep a
+		shell open via 'jx rsh --devpod' while you use them.
+
+		Only DevPods with their own idle timeout or whose workspace is kept via 'devpod.workspaceSize' in the
+		jenkins-x.yml are stopped. Stopping a DevPod keeps the persistent volume claim of a kept workspace so that the
+		workspace is restored when the DevPod is created again via 'jx create devpod'. The services and ingresses of
+		the DevPod are removed.
+
+`)
+
+	GCDevPodsExample = templates.Examples(`
+		# stop the DevPods which have been idle for longer than their idle timeout
+		jx gc devpods
+
+		# also stop the DevPods with a kept workspace which have been idle for longer than a day
+		jx gc devpods --idle-timeout 24h
+`)
+)
+
+// NewCmdGCDevPods creates a command object for the "gc devpods" command
+func NewCmdGCDevPods(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &GCDevPodsOptions{
+		CommonOptions: CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "devpods",
+		Short:   "garbage collection for idle DevPods",
+		Long:    GCDevPodsLong,
+		Example: GCDevPodsExample,
+		Aliases: []string{"devpod"},
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().DurationVarP(&options.IdleTimeout, "idle-timeout", "i", 0, "How long a DevPod with a kept workspace but without its own idle timeout can be idle before it is stopped. Defaults to only stopping DevPods with their own idle timeout")
+	return cmd
+}
+
+// Run implements this command
+func (o *GCDevPodsOptions) Run() error {
+	client, curNs, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	ns, _, err := kube.GetDevNamespace(client, curNs)
+	if err != nil {
+		return err
+	}
+	pods := client.CoreV1().Pods(ns)
+	list, err := pods.List(metav1.ListOptions{
+		LabelSelector: kube.LabelDevPodName,
+	})
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, pod := range list.Items {
+		if pod.DeletionTimestamp != nil || !kube.IsDevPodIdle(&pod, o.IdleTimeout, now) {
+			continue
+		}
+		claim, err := kube.GetDevPodVolumeClaim(client, &pod, devPodWorkspaceVolumeName)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				log.Warnf("Failed to find the workspace of DevPod %s: %s\n", pod.Name, err)
+				continue
+			}
+			claim = nil
+		}
+		if !kube.CanStopIdleDevPod(&pod, claim) {
+			if o.Verbose {
+				log.Infof("Not stopping DevPod %s as its workspace would be lost\n", pod.Name)
+			}
+			continue
+		}
+		idle := kube.DevPodIdleTime(&pod, now).Round(time.Minute)
+		err = o.deleteDevPodServices(client, ns, &pod)
+		if err != nil {
+			log.Warnf("Failed to remove the services of DevPod %s: %s\n", pod.Name, err)
+		}
+		err = pods.Delete(pod.Name, &metav1.DeleteOptions{})
+		if err != nil {
+			log.Warnf("Failed to stop DevPod %s: %s\n", pod.Name, err)
+			continue
+		}
+		log.Infof("Stopped DevPod %s of user %s as it was idle for %s\n", util.ColorInfo(pod.Name), util.ColorInfo(pod.Labels[kube.LabelDevPodUsername]), idle.String())
+	}
+	return nil
+}
+
+// deleteDevPodServices deletes the services owned by the DevPod and the ingresses exposing them
+func (o *GCDevPodsOptions) deleteDevPodServices(client kubernetes.Interface, ns string, pod *corev1.Pod) error {
+	services, err := client.CoreV1().Services(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, svc := range services.Items {
+		if !isOwnedBy(svc.OwnerReferences, pod.UID) {
+			continue
+		}
+		err = client.ExtensionsV1beta1().Ingresses(ns).Delete(svc.Name, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		err = client.CoreV1().Services(ns).Delete(svc.Name, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func isOwnedBy(owners []metav1.OwnerReference, uid types.UID) bool {
+	for _, owner := range owners {
+		if owner.UID == uid {
+			return true
+		}
+	}
+	return false
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
 )
 
@@ -32,6 +33,11 @@ var (
 		Garbage collect Jenkins X preview environments.  If a pull request is merged or closed the associated preview
 		environment will be deleted.
 
+		The preview lifecycle policy of the team settings then deletes previews which have not been updated by a push
+		for longer than the TTL or which exceed the maximum number of previews of a repository, and scales previews which
+		have been idle for longer than the idle timeout down to zero. A comment is added to the pull request of each
+		reclaimed preview.
+
 `)
 
 	GCPreviewsExample = templates.Examples(`
@@ -140,5 +146,46 @@ func (o *GCPreviewsOptions) Run() error {
 	if o.Verbose && !previewFound {
 		log.Info("no preview environments found\n")
 	}
+	if previewFound {
+		return o.applyPreviewLifecycle()
+	}
+	return nil
+}
+
+// applyPreviewLifecycle deletes or scales down the remaining preview environments using the lifecycle policy of the team
+func (o *GCPreviewsOptions) applyPreviewLifecycle() error {
+	teamSettings, err := o.TeamSettings()
+	if err != nil {
+		return err
+	}
+	policy := teamSettings.PreviewLifecycle
+	if policy == nil {
+		return nil
+	}
+	lifecycle, err := kube.NewPreviewLifecycle(policy)
+	if err != nil {
+		return err
+	}
+	kubeClient, _, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	jxClient, devNs, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	envs, err := jxClient.JenkinsV1().Environments(devNs).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, decision := range lifecycle.Decide(envs.Items) {
+		if decision.Action == kube.PreviewActionKeep {
+			continue
+		}
+		err = o.reclaimPreview(kubeClient, jxClient, devNs, decision, policy)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -1,15 +1,15 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 
-	"github.com/ghodss/yaml"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/table"
 	"github.com/jenkins-x/jx/pkg/util"
 )
 
@@ -35,6 +35,12 @@ var (
 
 		# List all URLs for services in the current namespace
 		jx get url
+
+		# List the environments as JSON
+		jx get env -o json
+
+		# List the names of the applications using a JSONPath expression
+		jx get applications -o jsonpath='{.items[*].name}'
 	`)
 )
 
@@ -61,6 +67,13 @@ func NewCmdGet(f Factory, in terminal.FileReader, out terminal.FileWriter, errOu
 			err := options.Run()
 			CheckErr(err)
 		},
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			// lets fail fast on an invalid output format before querying anything
+			flag := cmd.Flags().Lookup("output")
+			if flag != nil {
+				CheckErr(table.ValidateOutput(flag.Value.String()))
+			}
+		},
 		SuggestFor: []string{"list", "ps"},
 	}
 
@@ -82,9 +95,11 @@ func NewCmdGet(f Factory, in terminal.FileReader, out terminal.FileWriter, errOu
 	cmd.AddCommand(NewCmdGetIssue(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetIssues(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetLimits(f, in, out, errOut))
+	cmd.AddCommand(NewCmdGetMetrics(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetPipeline(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetPostPreviewJob(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetPreview(f, in, out, errOut))
+	cmd.AddCommand(NewCmdGetProw(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetQuickstartLocation(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetRelease(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetTeam(f, in, out, errOut))
@@ -111,29 +126,23 @@ func outputEmptyListWarning(out io.Writer) error {
 
 func (o *GetOptions) addGetFlags(cmd *cobra.Command) {
 	o.Cmd = cmd
-	cmd.Flags().StringVarP(&o.Output, "output", "o", "", "The output format such as 'yaml'")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", "", "The output format. One of: text, json, yaml, csv, template=<go template> or jsonpath=<expression>")
 }
 
-// renderResult renders the result in a given output format
-func (o *GetOptions) renderResult(value interface{}, format string) error {
-	switch format {
-	case "json":
-		data, err := json.Marshal(value)
-		if err != nil {
-			return err
-		}
-		_, e := o.Out.Write(data)
-		return e
-	case "yaml":
-		data, err := yaml.Marshal(value)
-		if err != nil {
-			return err
-		}
-		_, e := o.Out.Write(data)
-		return e
-	default:
-		return fmt.Errorf("Unsupported output format: %s", format)
+// CreateTable creates a table which renders in the format of the --output flag
+func (o *GetOptions) CreateTable() table.Table {
+	t := o.CommonOptions.CreateTable()
+	err := t.SetOutput(o.Output)
+	if err != nil {
+		log.Warnf("%s\n", err)
 	}
+	return t
+}
+
+// isMachineOutput returns true if the --output flag is a machine readable format so that
+// only the table should be written to the output
+func (o *GetOptions) isMachineOutput() bool {
+	return o.Output != "" && o.Output != table.FormatText
 }
 
 func formatInt32(n int32) string {
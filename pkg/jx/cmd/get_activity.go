@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"io"
 	"strings"
 	"time"
@@ -24,9 +25,20 @@ const (
 	indentation = "  "
 )
 
+// ActivityStepItem is the machine readable output of a step or attachment of a pipeline activity
+type ActivityStepItem struct {
+	Activity           string                `json:"activity"`
+	Name               string                `json:"name"`
+	Description        string                `json:"description,omitempty"`
+	Status             v1.ActivityStatusType `json:"status,omitempty"`
+	StartedTimestamp   *metav1.Time          `json:"startedTimestamp,omitempty"`
+	CompletedTimestamp *metav1.Time          `json:"completedTimestamp,omitempty"`
+	URL                string                `json:"url,omitempty"`
+}
+
 // GetActivityOptions containers the CLI options
 type GetActivityOptions struct {
-	CommonOptions
+	GetOptions
 
 	Filter      string
 	BuildNumber string
@@ -53,11 +65,13 @@ var (
 // NewCmdGetActivity creates the new command for: jx get version
 func NewCmdGetActivity(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
 	options := &GetActivityOptions{
-		CommonOptions: CommonOptions{
-			Factory: f,
-			In:      in,
-			Out:     out,
-			Err:     errOut,
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
 		},
 	}
 	cmd := &cobra.Command{
@@ -76,6 +90,7 @@ func NewCmdGetActivity(f Factory, in terminal.FileReader, out terminal.FileWrite
 	cmd.Flags().StringVarP(&options.Filter, "filter", "f", "", "Text to filter the pipeline names")
 	cmd.Flags().StringVarP(&options.BuildNumber, "build", "b", "", "The build number to filter on")
 	cmd.Flags().BoolVarP(&options.Watch, "watch", "w", false, "Whether to watch the activities for changes")
+	options.addGetFlags(cmd)
 	return cmd
 }
 
@@ -122,8 +137,8 @@ func (o *GetActivityOptions) Run() error {
 	if err != nil {
 		return err
 	}
-	for _, activity := range list.Items {
-		o.addTableRow(&table, &activity)
+	for i := range list.Items {
+		o.addTableRow(&table, &list.Items[i])
 	}
 	table.Render()
 
@@ -144,13 +159,16 @@ func (o *GetActivityOptions) addTableRow(table *tbl.Table, activity *v1.Pipeline
 		} else {
 			statusText += " " + text
 		}
-		table.AddRow(spec.Pipeline+" #"+spec.Build,
+		table.AddItem(activity, spec.Pipeline+" #"+spec.Build,
 			timeToString(spec.StartedTimestamp),
 			durationString(spec.StartedTimestamp, spec.CompletedTimestamp),
 			statusText)
 		indent := indentation
 		for _, step := range spec.Steps {
-			o.addStepRow(table, &step, indent)
+			o.addStepRow(table, activity.Name, &step, indent)
+		}
+		for _, attachment := range spec.Attachments {
+			addAttachmentRow(table, activity.Name, &attachment, indent)
 		}
 		return true
 	}
@@ -208,16 +226,22 @@ func (o *GetActivityOptions) onActivity(table *tbl.Table, obj interface{}, yamlS
 	}
 }
 
-func (o *CommonOptions) addStepRow(table *tbl.Table, parent *v1.PipelineActivityStep, indent string) {
+func (o *CommonOptions) addStepRow(table *tbl.Table, activity string, parent *v1.PipelineActivityStep, indent string) {
 	stage := parent.Stage
 	preview := parent.Preview
 	promote := parent.Promote
+	workflow := parent.Workflow
+	rollback := parent.Rollback
 	if stage != nil {
-		addStageRow(table, stage, indent)
+		addStageRow(table, activity, stage, indent)
 	} else if preview != nil {
-		addPreviewRow(table, preview, indent)
+		addPreviewRow(table, activity, preview, indent)
 	} else if promote != nil {
-		addPromoteRow(table, promote, indent)
+		addPromoteRow(table, activity, promote, indent)
+	} else if workflow != nil {
+		addWorkflowRow(table, activity, workflow, indent)
+	} else if rollback != nil {
+		addRollbackRow(table, activity, rollback, indent)
 	} else {
 		log.Warnf("Unknown step kind %#v\n", parent)
 	}
@@ -228,11 +252,11 @@ func addStageRow(table *tbl.Table, stage *v1.StageActivityStep, indent string) {
 	if stage.Name != "" {
 		name = ""
 	}
-	addStepRowItem(table, &stage.CoreActivityStep, indent, name, "")
+	addStepRowItem(table, activity, &stage.CoreActivityStep, indent, name, "")
 
 	indent += indentation
 	for _, step := range stage.Steps {
-		addStepRowItem(table, &step, indent, "", "")
+		addStepRowItem(table, activity, &step, indent, "", "")
 	}
 }
 
@@ -241,34 +265,68 @@ func addPreviewRow(table *tbl.Table, parent *v1.PreviewActivityStep, indent stri
 	if pullRequestURL == "" {
 		pullRequestURL = parent.Environment
 	}
-	addStepRowItem(table, &parent.CoreActivityStep, indent, "Preview", util.ColorInfo(pullRequestURL))
+	addStepRowItem(table, activity, &parent.CoreActivityStep, indent, "Preview", util.ColorInfo(pullRequestURL))
 	indent += indentation
 
 	appURL := parent.ApplicationURL
 	if appURL != "" {
-		addStepRowItem(table, &parent.CoreActivityStep, indent, "Preview Application", util.ColorInfo(appURL))
+		addStepRowItem(table, activity, &parent.CoreActivityStep, indent, "Preview Application", util.ColorInfo(appURL))
 	}
 }
 
 func addPromoteRow(table *tbl.Table, parent *v1.PromoteActivityStep, indent string) {
-	addStepRowItem(table, &parent.CoreActivityStep, indent, "Promote: "+parent.Environment, "")
+	addStepRowItem(table, activity, &parent.CoreActivityStep, indent, "Promote: "+parent.Environment, "")
 	indent += indentation
 
 	pullRequest := parent.PullRequest
 	update := parent.Update
 	if pullRequest != nil {
-		addStepRowItem(table, &pullRequest.CoreActivityStep, indent, "PullRequest", describePromotePullRequest(pullRequest))
+		addStepRowItem(table, activity, &pullRequest.CoreActivityStep, indent, "PullRequest", describePromotePullRequest(pullRequest))
 	}
 	if update != nil {
-		addStepRowItem(table, &update.CoreActivityStep, indent, "Update", describePromoteUpdate(update))
+		addStepRowItem(table, activity, &update.CoreActivityStep, indent, "Update", describePromoteUpdate(update))
+	}
+	for i := range parent.Rollout {
+		rollout := &parent.Rollout[i]
+		addStepRowItem(table, activity, &rollout.CoreActivityStep, indent, fmt.Sprintf("Rollout: %d%%", rollout.Weight), rollout.Message)
 	}
 	appURL := parent.ApplicationURL
 	if appURL != "" {
-		addStepRowItem(table, &update.CoreActivityStep, indent, "Promoted", " Application is at: "+util.ColorInfo(appURL))
+		addStepRowItem(table, activity, &update.CoreActivityStep, indent, "Promoted", " Application is at: "+util.ColorInfo(appURL))
+	}
+}
+
+func addWorkflowRow(table *tbl.Table, parent *v1.WorkflowActivityStep, indent string) {
+	description := parent.Message
+	if parent.ApprovedBy != "" {
+		description += " by: " + util.ColorInfo(parent.ApprovedBy)
+	}
+	if parent.JobName != "" {
+		description += " Job: " + util.ColorInfo(parent.JobName)
 	}
+	addStepRowItem(table, activity, &parent.CoreActivityStep, indent, string(parent.Kind), description)
 }
 
-func addStepRowItem(table *tbl.Table, step *v1.CoreActivityStep, indent string, name string, description string) {
+func addRollbackRow(table *tbl.Table, parent *v1.RollbackActivityStep, indent string) {
+	description := "from " + util.ColorInfo(parent.FromVersion) + " to " + util.ColorInfo(parent.ToVersion)
+	if parent.PullRequestURL != "" {
+		description += " PullRequest: " + util.ColorInfo(parent.PullRequestURL)
+	}
+	addStepRowItem(table, activity, &parent.CoreActivityStep, indent, "Rollback: "+parent.Environment, description)
+}
+
+func addAttachmentRow(table *tbl.Table, activity string, attachment *v1.Attachment, indent string) {
+	for _, u := range attachment.URLs {
+		item := &ActivityStepItem{
+			Activity: activity,
+			Name:     "Attachment: " + attachment.Name,
+			URL:      u,
+		}
+		table.AddItem(item, indent+item.Name, "", "", util.ColorInfo(u))
+	}
+}
+
+func addStepRowItem(table *tbl.Table, activity string, step *v1.CoreActivityStep, indent string, name string, description string) {
 	text := step.Description
 	if description != "" {
 		if text == "" {
@@ -285,7 +343,15 @@ func addStepRowItem(table *tbl.Table, step *v1.CoreActivityStep, indent string,
 			textName = name + ":" + textName
 		}
 	}
-	table.AddRow(indent+textName,
+	item := &ActivityStepItem{
+		Activity:           activity,
+		Name:               textName,
+		Description:        tbl.StripColors(text),
+		Status:             step.Status,
+		StartedTimestamp:   step.StartedTimestamp,
+		CompletedTimestamp: step.CompletedTimestamp,
+	}
+	table.AddItem(item, indent+textName,
 		timeToString(step.StartedTimestamp),
 		durationString(step.StartedTimestamp, step.CompletedTimestamp),
 		statusString(step.Status)+" "+text)
@@ -298,7 +364,7 @@ func statusString(statusType v1.ActivityStatusType) string {
 		return util.ColorError(text)
 	case v1.ActivityStatusTypeSucceeded:
 		return util.ColorInfo(text)
-	case v1.ActivityStatusTypeRunning:
+	case v1.ActivityStatusTypeRunning, v1.ActivityStatusTypeWaitingForApproval:
 		return util.ColorStatus(text)
 	}
 	return text
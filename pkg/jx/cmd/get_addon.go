@@ -57,9 +57,18 @@ func NewCmdGetAddon(f Factory, in terminal.FileReader, out terminal.FileWriter,
 		},
 	}
 
+	options.addGetFlags(cmd)
 	return cmd
 }
 
+// AddonItem is the machine readable output of an addon
+type AddonItem struct {
+	Name    string `json:"name"`
+	Chart   string `json:"chart"`
+	Enabled bool   `json:"enabled"`
+	Status  string `json:"status,omitempty"`
+}
+
 // Run implements this command
 func (o *GetAddonOptions) Run() error {
 
@@ -95,7 +104,13 @@ func (o *GetAddonOptions) Run() error {
 		if addonEnabled[k] {
 			enableText = "yes"
 		}
-		table.AddRow(k, chart, enableText, status)
+		item := &AddonItem{
+			Name:    k,
+			Chart:   chart,
+			Enabled: addonEnabled[k],
+			Status:  status,
+		}
+		table.AddItem(item, k, chart, enableText, status)
 	}
 
 	table.Render()
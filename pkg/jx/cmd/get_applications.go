@@ -20,7 +20,7 @@ import (
 
 // GetApplicationsOptions containers the CLI options
 type GetApplicationsOptions struct {
-	CommonOptions
+	GetOptions
 
 	Namespace   string
 	Environment string
@@ -52,17 +52,22 @@ var (
 
 		# List applications just showing the versions (hiding urls and pod counts)
 		jx get apps -u -p
+
+		# List the applications and their versions in each environment as JSON
+		jx get apps -o json
 	`)
 )
 
 // NewCmdGetApplications creates the new command for: jx get version
 func NewCmdGetApplications(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
 	options := &GetApplicationsOptions{
-		CommonOptions: CommonOptions{
-			Factory: f,
-			In:      in,
-			Out:     out,
-			Err:     errOut,
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
 		},
 	}
 	cmd := &cobra.Command{
@@ -83,6 +88,7 @@ func NewCmdGetApplications(f Factory, in terminal.FileReader, out terminal.FileW
 	cmd.Flags().BoolVarP(&options.Previews, "preview", "w", false, "Show preview environments only")
 	cmd.Flags().StringVarP(&options.Environment, "env", "e", "", "Filter applications in the given environment")
 	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "Filter applications in the given namespace")
+	options.addGetFlags(cmd)
 	return cmd
 }
 
@@ -91,6 +97,21 @@ type EnvApps struct {
 	Apps        map[string]v1beta1.Deployment
 }
 
+// ApplicationItem is the machine readable output of an application
+type ApplicationItem struct {
+	Name         string                       `json:"name"`
+	Environments []ApplicationEnvironmentItem `json:"environments"`
+}
+
+// ApplicationEnvironmentItem is the machine readable output of an application in an environment
+type ApplicationEnvironmentItem struct {
+	Environment string `json:"environment"`
+	Namespace   string `json:"namespace"`
+	Version     string `json:"version,omitempty"`
+	Pods        string `json:"pods,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
 // Run implements this command
 func (o *GetApplicationsOptions) Run() error {
 	f := o.Factory
@@ -161,6 +182,11 @@ func (o *GetApplicationsOptions) Run() error {
 	}
 	util.ReverseStrings(namespaces)
 	if len(apps) == 0 {
+		if o.isMachineOutput() {
+			table := o.CreateTable()
+			table.Render()
+			return nil
+		}
 		log.Infof("No applications found in environments %s\n", strings.Join(envNames, ", "))
 		return nil
 	}
@@ -191,10 +217,19 @@ func (o *GetApplicationsOptions) Run() error {
 
 	for _, appName := range apps {
 		row := []string{appName}
+		item := &ApplicationItem{
+			Name:         appName,
+			Environments: []ApplicationEnvironmentItem{},
+		}
 		for _, ea := range envApps {
 			version := ""
-			d := ea.Apps[appName]
+			d, found := ea.Apps[appName]
 			version = kube.GetVersion(&d.ObjectMeta)
+			envItem := ApplicationEnvironmentItem{
+				Environment: ea.Environment.Name,
+				Namespace:   ea.Environment.Spec.Namespace,
+				Version:     version,
+			}
 			if ea.Environment.Spec.Kind != v1.EnvironmentKindTypePreview {
 				row = append(row, version)
 			}
@@ -207,6 +242,7 @@ func (o *GetApplicationsOptions) Run() error {
 					pods = formatInt32(ready) + "/" + replicas
 				}
 				row = append(row, pods)
+				envItem.Pods = pods
 			}
 			if !o.HideUrl {
 				url, _ := kube.FindServiceURL(kubeClient, d.Namespace, appName)
@@ -227,9 +263,13 @@ func (o *GetApplicationsOptions) Run() error {
 					}
 				}
 				row = append(row, url)
+				envItem.URL = url
+			}
+			if found {
+				item.Environments = append(item.Environments, envItem)
 			}
 		}
-		table.AddRow(row...)
+		table.AddItem(item, row...)
 	}
 	table.Render()
 	return nil
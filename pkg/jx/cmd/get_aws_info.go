@@ -63,7 +63,20 @@ func (o *GetAWSInfoOptions) Run() error {
 	if err != nil {
 		return err
 	}
+	if o.isMachineOutput() {
+		table := o.CreateTable()
+		table.AddRow("ACCOUNT ID", "REGION")
+		table.AddItem(&AWSInfoItem{AccountID: id, Region: region}, id, region)
+		table.Render()
+		return nil
+	}
 	log.Infof("AWS Account ID: %s\n", util.ColorInfo(id))
 	log.Infof("AWS Region:     %s\n", util.ColorInfo(region))
 	return nil
 }
+
+// AWSInfoItem is the machine readable output of the AWS account information
+type AWSInfoItem struct {
+	AccountID string `json:"accountId"`
+	Region    string `json:"region"`
+}
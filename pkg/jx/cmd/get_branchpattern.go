@@ -68,6 +68,11 @@ func NewCmdGetBranchPattern(f Factory, in terminal.FileReader, out terminal.File
 	return cmd
 }
 
+// BranchPatternItem is the machine readable output of the branch patterns of a team
+type BranchPatternItem struct {
+	Pattern string `json:"pattern"`
+}
+
 // Run implements this command
 func (o *GetBranchPatternOptions) Run() error {
 	patterns, err := o.TeamBranchPatterns()
@@ -76,7 +81,7 @@ func (o *GetBranchPatternOptions) Run() error {
 	}
 	table := o.CreateTable()
 	table.AddRow("BRANCH PATTERNS")
-	table.AddRow(patterns.DefaultBranchPattern)
+	table.AddItem(&BranchPatternItem{Pattern: patterns.DefaultBranchPattern}, patterns.DefaultBranchPattern)
 	table.Render()
 	return nil
 }
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -32,17 +33,23 @@ type GetBuildLogsOptions struct {
 	Filter  string
 	Build   int
 	Pending bool
+	Search  string
 }
 
 var (
 	get_build_log_long = templates.LongDesc(`
-		Display the Git server URLs.
+		Display a build log.
+
+		If the build pod or Jenkins build is no longer available the log is read from the archive created by 'jx step logs'.
 
 `)
 
 	get_build_log_example = templates.Examples(`
-		# List all registered Git server URLs
-		jx get git
+		# Display the log of the latest build of a pipeline
+		jx get build logs myorg/myapp/master
+
+		# Search the archived build logs of a pipeline
+		jx get build logs myorg/myapp/master --search "FAIL|panic"
 	`)
 )
 
@@ -77,6 +84,7 @@ func NewCmdGetBuildLogs(f Factory, in terminal.FileReader, out terminal.FileWrit
 	cmd.Flags().BoolVarP(&options.Pending, "pending", "p", false, "Only display logs which are currently pending to choose from if no build name is supplied")
 	cmd.Flags().StringVarP(&options.Filter, "filter", "f", "", "Filters all the available jobs by those that contain the given text")
 	cmd.Flags().IntVarP(&options.Build, "build", "b", 0, "The build number to view")
+	cmd.Flags().StringVarP(&options.Search, "search", "s", "", "Searches the archived build logs for lines matching the regular expression")
 
 	return cmd
 }
@@ -92,6 +100,10 @@ func (o *GetBuildLogsOptions) Run() error {
 		return err
 	}
 
+	if o.Search != "" {
+		return o.searchArchivedBuildLogs(jxClient, ns)
+	}
+
 	devEnv, err := kube.GetEnrichedDevEnvironment(kubeClient, jxClient, ns)
 	webhookEngine := devEnv.Spec.WebHookEngine
 	if webhookEngine == v1.WebHookEngineProw {
@@ -142,6 +154,11 @@ func (o *GetBuildLogsOptions) Run() error {
 		last, err = jenkinsClient.GetLastBuild(job)
 	}
 	if err != nil {
+		// the build may have been garbage collected so lets try the archived log
+		archived, archiveErr := getArchivedBuilds(jxClient, ns, name, o.Build)
+		if archiveErr == nil && len(archived) > 0 {
+			return o.printArchivedBuildLog(&archived[0])
+		}
 		return err
 	}
 	log.Infof("%s %s\n", util.ColorStatus("view the log at:"), util.ColorInfo(util.UrlJoin(last.Url, "/console")))
@@ -253,10 +270,55 @@ func (o *GetBuildLogsOptions) getProwBuildLog(kubeClient kubernetes.Interface, j
 			}
 		}
 	}
+	if build.Spec.BuildLogsURL != "" {
+		return o.printArchivedBuildLog(build)
+	}
 	log.Warnf("No pod is available for pipeline %s build %s\n", util.ColorInfo(name), util.ColorInfo("#"+strconv.Itoa(buildNumber)))
 	return nil
 }
 
+// printArchivedBuildLog prints the archived log of the build
+func (o *GetBuildLogsOptions) printArchivedBuildLog(activity *v1.PipelineActivity) error {
+	log.Infof("Showing the archived log of pipeline %s build %s from %s\n", util.ColorInfo(activity.Spec.Pipeline),
+		util.ColorInfo("#"+activity.Spec.Build), util.ColorInfo(activity.Spec.BuildLogsURL))
+	data, err := o.readArchivedBuildLog(activity.Spec.BuildLogsURL)
+	if err != nil {
+		return err
+	}
+	_, err = o.Out.Write(data)
+	return err
+}
+
+// searchArchivedBuildLogs prints the lines of the archived build logs which match the search expression
+func (o *GetBuildLogsOptions) searchArchivedBuildLogs(jxClient versioned.Interface, ns string) error {
+	re, err := regexp.Compile(o.Search)
+	if err != nil {
+		return util.InvalidOptionf("search", o.Search, "invalid regular expression: %s", err)
+	}
+	pipeline := ""
+	if len(o.Args) > 0 {
+		pipeline = o.Args[0]
+	}
+	activities, err := getArchivedBuilds(jxClient, ns, pipeline, o.Build)
+	if err != nil {
+		return err
+	}
+	for _, activity := range activities {
+		if o.Filter != "" && !strings.Contains(activity.Spec.Pipeline, o.Filter) {
+			continue
+		}
+		data, err := o.readArchivedBuildLog(activity.Spec.BuildLogsURL)
+		if err != nil {
+			log.Warnf("Failed to read the archived log %s: %s\n", activity.Spec.BuildLogsURL, err)
+			continue
+		}
+		for _, line := range SearchBuildLog(data, re) {
+			fmt.Fprintf(o.Out, "%s #%s: %s\n", util.ColorInfo(activity.Spec.Pipeline), activity.Spec.Build, line)
+		}
+	}
+	return nil
+}
+
 func (o *GetBuildLogsOptions) getPodLog(ns string, pod *corev1.Pod, container corev1.Container) error {
 	log.Infof("Getting the pod log for pod %s and init container %s\n", pod.Name, container.Name)
 	return o.tailLogs(ns, pod.Name, container.Name)
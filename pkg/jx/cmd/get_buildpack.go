@@ -65,6 +65,12 @@ func NewCmdGetBuildPack(f Factory, in terminal.FileReader, out terminal.FileWrit
 	return cmd
 }
 
+// BuildPackItem is the machine readable output of the build pack of a team
+type BuildPackItem struct {
+	URL string `json:"url"`
+	Ref string `json:"ref"`
+}
+
 // Run implements this command
 func (o *GetBuildPackOptions) Run() error {
 	settings, err := o.TeamSettings()
@@ -73,7 +79,11 @@ func (o *GetBuildPackOptions) Run() error {
 	}
 	table := o.CreateTable()
 	table.AddRow("BUILD PACK GIT URL", "GIT REF")
-	table.AddRow(settings.BuildPackURL, settings.BuildPackRef)
+	item := &BuildPackItem{
+		URL: settings.BuildPackURL,
+		Ref: settings.BuildPackRef,
+	}
+	table.AddItem(item, settings.BuildPackURL, settings.BuildPackRef)
 	table.Render()
 	return nil
 }
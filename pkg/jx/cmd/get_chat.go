@@ -60,9 +60,17 @@ func NewCmdGetChat(f Factory, in terminal.FileReader, out terminal.FileWriter, e
 		},
 	}
 	cmd.Flags().StringVarP(&options.Kind, "kind", "k", "", "Filters the chats by the kinds: "+strings.Join(chats.ChatKinds, ", "))
+	options.addGetFlags(cmd)
 	return cmd
 }
 
+// ServerItem is the machine readable output of a registered server such as a chat, git or issue tracker server
+type ServerItem struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	URL  string `json:"url"`
+}
+
 // Run implements this command
 func (o *GetChatOptions) Run() error {
 	authConfigSvc, err := o.CreateChatAuthConfigService()
@@ -71,7 +79,7 @@ func (o *GetChatOptions) Run() error {
 	}
 	config := authConfigSvc.Config()
 
-	if len(config.Servers) == 0 {
+	if len(config.Servers) == 0 && !o.isMachineOutput() {
 		log.Infof("No chat servers registered. To register a new chat servers use: %s\n", util.ColorInfo("jx create chat server"))
 		return nil
 	}
@@ -86,10 +94,15 @@ func (o *GetChatOptions) Run() error {
 
 	for _, s := range config.Servers {
 		kind := s.Kind
-		if filterKind == "" || filterKind == kind {
-			table.AddRow(s.Name, kind, s.URL)
+		item := &ServerItem{
+			Name: s.Name,
+			Kind: kind,
+			URL:  s.URL,
+		}
+		if filterKind == "" {
+			table.AddItem(item, s.Name, kind, s.URL)
 		} else if filterKind == kind {
-			table.AddRow(s.Name, s.URL)
+			table.AddItem(item, s.Name, s.URL)
 		}
 	}
 	table.Render()
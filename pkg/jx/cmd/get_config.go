@@ -5,6 +5,7 @@ import (
 
 	"github.com/jenkins-x/jx/pkg/config"
 	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/table"
 	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
@@ -64,6 +65,25 @@ func NewCmdGetConfig(f Factory, in terminal.FileReader, out terminal.FileWriter,
 
 func (o *GetConfigOptions) addGetConfigFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.Dir, "dir", "d", "", "The root project directory")
+	o.addGetFlags(cmd)
+}
+
+// ConfigServiceItem is the machine readable output of a service of the project configuration
+type ConfigServiceItem struct {
+	Service string `json:"service"`
+	Kind    string `json:"kind"`
+	URL     string `json:"url"`
+	Name    string `json:"name"`
+}
+
+func addConfigServiceRow(table *table.Table, service string, kind string, url string, name string) {
+	item := &ConfigServiceItem{
+		Service: service,
+		Kind:    kind,
+		URL:     url,
+		Name:    name,
+	}
+	table.AddItem(item, service, kind, url, name)
 }
 
 // Run implements this command
@@ -72,7 +92,7 @@ func (o *GetConfigOptions) Run() error {
 	if err != nil {
 		return err
 	}
-	if pc.IsEmpty() {
+	if pc.IsEmpty() && !o.isMachineOutput() {
 		log.Infoln("No project configuration for this directory.")
 		log.Infof("To edit the configuration use: %s\n", util.ColorInfo("jx edit config"))
 		return nil
@@ -82,19 +102,19 @@ func (o *GetConfigOptions) Run() error {
 
 	t := pc.IssueTracker
 	if t != nil {
-		table.AddRow("Issue Tracker", t.Kind, t.URL, t.Project)
+		addConfigServiceRow(&table, "Issue Tracker", t.Kind, t.URL, t.Project)
 	}
 	w := pc.Wiki
 	if w != nil {
-		table.AddRow("Wiki", w.Kind, w.URL, w.Space)
+		addConfigServiceRow(&table, "Wiki", w.Kind, w.URL, w.Space)
 	}
 	ch := pc.Chat
 	if ch != nil {
 		if ch.DeveloperChannel != "" {
-			table.AddRow("Developer Chat", ch.Kind, ch.URL, ch.DeveloperChannel)
+			addConfigServiceRow(&table, "Developer Chat", ch.Kind, ch.URL, ch.DeveloperChannel)
 		}
 		if ch.UserChannel != "" {
-			table.AddRow("User Chat", ch.Kind, ch.URL, ch.UserChannel)
+			addConfigServiceRow(&table, "User Chat", ch.Kind, ch.URL, ch.UserChannel)
 		}
 	}
 	table.Render()
@@ -7,11 +7,11 @@ import (
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 
 	"fmt"
+	"strings"
 
 	"github.com/jenkins-x/jx/pkg/cve"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
-	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
 )
 
@@ -23,12 +23,19 @@ type GetCVEOptions struct {
 	Version           string
 	Env               string
 	VulnerabilityType string
+	Provider          string
+	URL               string
+	Reports           []string
 }
 
 var (
 	getCVELong = templates.LongDesc(`
 		Display Common Vulnerabilities and Exposures (CVEs)
 
+		The vulnerabilities are found by the anchore addon by default. Use --provider clair to query the vulnerability
+		reports of a Clair server for images referenced by digest or --provider report to display Trivy or Grype JSON
+		reports produced in a pipeline.
+
 `)
 
 	getCVEExample = templates.Examples(`
@@ -39,6 +46,12 @@ var (
 		jx get cve --app foo --version 1.0.0
 		jx get cve --app foo --environment staging
 		jx get cve --environment staging
+
+		# query a Clair server
+		jx get cve --provider clair --url http://clair:6060 --image-name myorg/myapp@sha256:0123abcd...
+
+		# display the vulnerabilities in a Trivy report
+		jx get cve --provider report --report trivy.json
 	`)
 )
 
@@ -72,6 +85,7 @@ func NewCmdGetCVE(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 
 	options.addCommonFlags(cmd)
 	options.addGetCVEFlags(cmd)
+	options.addGetFlags(cmd)
 
 	return cmd
 }
@@ -81,6 +95,9 @@ func (o *GetCVEOptions) addGetCVEFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.ImageID, "image-id", "", "", "Image ID in CVE engine if already known")
 	cmd.Flags().StringVarP(&o.Version, "version", "", "", "Version or tag e.g. 0.0.1")
 	cmd.Flags().StringVarP(&o.Env, "environment", "e", "", "The Environment to find running applications")
+	cmd.Flags().StringVarP(&o.Provider, "provider", "", cve.ProviderKindAnchore, "The CVE provider to use. One of: "+strings.Join(cve.ProviderKinds, ", "))
+	cmd.Flags().StringVarP(&o.URL, "url", "", "", "The URL of the Clair server")
+	cmd.Flags().StringArrayVarP(&o.Reports, "report", "", nil, "The Trivy or Grype JSON report files to display when using the report provider")
 }
 
 // Run implements this command
@@ -96,25 +113,14 @@ func (o *GetCVEOptions) Run() error {
 		return fmt.Errorf("cannot create jx client: %v", err)
 	}
 
-	externalURL, err := o.ensureAddonServiceAvailable(kube.AddonServices[defaultAnchoreName])
-	if err != nil {
-		log.Warnf("no CVE provider service found, are you in your teams dev environment?  Type `jx env` to switch.\n")
-		return fmt.Errorf("if no CVE provider running, try running `jx create addon anchore` in your teams dev environment: %v", err)
-	}
-
 	// if no flags are set try and guess the image name from the current directory
-	if o.ImageID == "" && o.ImageName == "" && o.Env == "" {
+	if o.ImageID == "" && o.ImageName == "" && o.Env == "" && o.Provider != cve.ProviderKindReport {
 		return fmt.Errorf("no --image-name, --image-id or --environment flags set\n")
 	}
 
-	server, auth, err := o.CommonOptions.getAddonAuthByKind(kube.ValueKindCVE, externalURL)
-	if err != nil {
-		return fmt.Errorf("error getting anchore engine auth details, %v", err)
-	}
-
-	p, err := cve.NewAnchoreProvider(server, auth)
+	p, err := o.createCVEProvider(o.Provider, o.URL, o.Reports)
 	if err != nil {
-		return fmt.Errorf("error creating anchore provider, %v", err)
+		return err
 	}
 	table := o.CreateTable()
 	table.AddRow("Image", util.ColorInfo("Severity"), "Vulnerability", "URL", "Package", "Fix")
@@ -1,15 +1,21 @@
 package cmd
 
 import (
+	"bytes"
 	"io"
 	"os/user"
 	"time"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 
+	"github.com/jenkins-x/jx/pkg/filesync"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
 )
 
 // GetDevPodOptions the command line options
@@ -21,6 +27,10 @@ var (
 	getDevPodLong = templates.LongDesc(`
 		Display the available DevPods
 
+		The IDLE column shows how long it has been since a shell into the DevPod or a file synchronisation with it.
+		The STORAGE column shows the space used by the working directory of a running DevPod and the size of its
+		persistent volume claim if it has one.
+
 		For more documentation see: [https://jenkins-x.io/developing/devpods/](https://jenkins-x.io/developing/devpods/)
 
 `)
@@ -58,9 +68,20 @@ func NewCmdGetDevPod(f Factory, in terminal.FileReader, out terminal.FileWriter,
 		},
 	}
 
+	options.addGetFlags(cmd)
 	return cmd
 }
 
+// DevPodItem is the machine readable output of a DevPod
+type DevPodItem struct {
+	Name        string `json:"name"`
+	PodTemplate string `json:"podTemplate,omitempty"`
+	Age         string `json:"age"`
+	Idle        string `json:"idle"`
+	Storage     string `json:"storage,omitempty"`
+	Status      string `json:"status"`
+}
+
 // Run implements this command
 func (o *GetDevPodOptions) Run() error {
 
@@ -79,9 +100,16 @@ func (o *GetDevPodOptions) Run() error {
 	}
 
 	names, m, err := kube.GetDevPodNames(client, ns, u.Username)
+	if err != nil {
+		return err
+	}
+	config, err := o.Factory.CreateKubeConfig()
+	if err != nil {
+		return err
+	}
 
 	table := o.CreateTable()
-	table.AddRow("NAME", "POD TEMPLATE", "AGE", "STATUS")
+	table.AddRow("NAME", "POD TEMPLATE", "AGE", "IDLE", "STORAGE", "STATUS")
 
 	for _, k := range names {
 		pod := m[k]
@@ -91,13 +119,65 @@ func (o *GetDevPodOptions) Run() error {
 			labels := pod.Labels
 			d := time.Now().Sub(pod.CreationTimestamp.Time).Round(time.Second)
 			age := d.String()
+			idle := kube.DevPodIdleTime(pod, time.Now()).Round(time.Second).String()
+			storage := o.devPodStorage(client, config, pod)
 			if labels != nil {
 				podTemplate = labels[kube.LabelPodTemplate]
 			}
-			table.AddRow(k, podTemplate, age, status)
+			item := &DevPodItem{
+				Name:        k,
+				PodTemplate: podTemplate,
+				Age:         age,
+				Idle:        idle,
+				Storage:     storage,
+				Status:      status,
+			}
+			table.AddItem(item, k, podTemplate, age, idle, storage, status)
 		}
 	}
 
 	table.Render()
 	return nil
 }
+
+// devPodStorage returns the space used by the working directory of the DevPod and the capacity of its persistent
+// volume claim such as 120Mi/10Gi
+func (o *GetDevPodOptions) devPodStorage(client kubernetes.Interface, config *rest.Config, pod *corev1.Pod) string {
+	used := ""
+	if pod.Status.Phase == corev1.PodRunning && len(pod.Spec.Containers) > 0 {
+		workingDir := devPodWorkspaceDir
+		if pod.Annotations != nil && pod.Annotations[kube.AnnotationWorkingDir] != "" {
+			workingDir = pod.Annotations[kube.AnnotationWorkingDir]
+		}
+		remote := &filesync.PodRemote{
+			KubeClient: client,
+			Config:     config,
+			Namespace:  pod.Namespace,
+			Pod:        pod.Name,
+			Container:  pod.Spec.Containers[0].Name,
+		}
+		var out bytes.Buffer
+		err := remote.Exec([]string{"du", "-sk", workingDir}, nil, &out)
+		if err == nil {
+			var usedBytes int64
+			usedBytes, err = kube.ParseDiskUsage(out.String())
+			if err == nil {
+				used = kube.FormatStorageSize(usedBytes)
+			}
+		}
+		if err != nil && o.Verbose {
+			log.Warnf("Failed to find the disk usage of DevPod %s: %s\n", pod.Name, err)
+		}
+	}
+	pvc, err := kube.GetDevPodVolumeClaim(client, pod, devPodWorkspaceVolumeName)
+	if err != nil {
+		log.Warnf("Failed to find the workspace of DevPod %s: %s\n", pod.Name, err)
+	}
+	if pvc == nil {
+		return used
+	}
+	if used == "" {
+		used = "-"
+	}
+	return used + "/" + kube.VolumeClaimCapacity(pvc)
+}
@@ -5,8 +5,6 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/jenkins-x/jx/pkg/cloud/amazon"
-	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
 	"io"
 	"os"
 	"os/exec"
@@ -23,6 +21,12 @@ type GetEksOptions struct {
 	Region  string
 }
 
+// EksClusterItem is the machine readable output of an EKS cluster
+type EksClusterItem struct {
+	Name         string             `json:"name"`
+	Reservations []*ec2.Reservation `json:"reservations"`
+}
+
 var (
 	getEksLong = templates.LongDesc(`
 		Display one or many EKS cluster resources 
@@ -119,19 +123,14 @@ func (o *GetEksOptions) Run() error {
 			return err
 		}
 
-		if o.Output == "" {
-			fmt.Println("NAME")
-			fmt.Println(cluster)
-		} else if o.Output == "yaml" {
-			reservations, err := yaml.Marshal(instances.Reservations)
-			if err != nil {
-				return err
-			}
-			fmt.Println(string(reservations))
-		} else {
-			return errors.New("Invalid output format.")
+		item := &EksClusterItem{
+			Name:         cluster,
+			Reservations: instances.Reservations,
 		}
-
+		table := o.CreateTable()
+		table.AddRow("NAME")
+		table.AddItem(item, cluster)
+		table.Render()
 		return nil
 	}
 }
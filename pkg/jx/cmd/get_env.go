@@ -34,6 +34,12 @@ var (
 
 		# List all environments using the shorter alias
 		jx get env
+
+		# List the environments as YAML
+		jx get env -o yaml
+
+		# List the names of the environments
+		jx get env -o jsonpath='{.items[*].metadata.name}'
 	`)
 )
 
@@ -106,8 +112,11 @@ func (o *GetEnvOptions) Run() error {
 
 		table := o.CreateTable()
 		table.AddRow("NAME", "LABEL", "KIND", "NAMESPACE", "SOURCE", "REF", "PR")
-		table.AddRow(e, spec.Label, spec.Namespace, kindString(spec), spec.Source.URL, spec.Source.Ref, spec.PullRequestURL)
+		table.AddItem(env, e, spec.Label, spec.Namespace, kindString(spec), spec.Source.URL, spec.Source.Ref, spec.PullRequestURL)
 		table.Render()
+		if o.isMachineOutput() {
+			return nil
+		}
 		log.Blank()
 
 		ens := env.Spec.Namespace
@@ -133,7 +142,7 @@ func (o *GetEnvOptions) Run() error {
 		if err != nil {
 			return err
 		}
-		if len(envs.Items) == 0 {
+		if len(envs.Items) == 0 && !o.isMachineOutput() {
 			log.Infof("No environments found.\nTo create an environment use: jx create env\n")
 			return nil
 		}
@@ -141,10 +150,6 @@ func (o *GetEnvOptions) Run() error {
 		environments := o.filterEnvironments(envs.Items)
 		kube.SortEnvironments(environments)
 
-		if o.Output != "" {
-			envs.Items = environments
-			return o.renderResult(envs, o.Output)
-		}
 		table := o.CreateTable()
 		if o.PreviewOnly {
 			table.AddRow("PULL REQUEST", "NAMESPACE", "APPLICATION")
@@ -152,12 +157,13 @@ func (o *GetEnvOptions) Run() error {
 			table.AddRow("NAME", "LABEL", "KIND", "PROMOTE", "NAMESPACE", "ORDER", "CLUSTER", "SOURCE", "REF", "PR")
 		}
 
-		for _, env := range environments {
+		for i := range environments {
+			env := &environments[i]
 			spec := &env.Spec
 			if o.PreviewOnly {
-				table.AddRow(spec.PullRequestURL, spec.Namespace, util.ColorInfo(spec.PreviewGitSpec.ApplicationURL))
+				table.AddItem(env, spec.PullRequestURL, spec.Namespace, util.ColorInfo(spec.PreviewGitSpec.ApplicationURL))
 			} else {
-				table.AddRow(env.Name, spec.Label, kindString(spec), string(spec.PromotionStrategy), spec.Namespace, util.Int32ToA(spec.Order), spec.Cluster, spec.Source.URL, spec.Source.Ref, spec.PullRequestURL)
+				table.AddItem(env, env.Name, spec.Label, kindString(spec), string(spec.PromotionStrategy), spec.Namespace, util.Int32ToA(spec.Order), spec.Cluster, spec.Source.URL, spec.Source.Ref, spec.PullRequestURL)
 			}
 		}
 		table.Render()
@@ -53,6 +53,7 @@ func NewCmdGetGit(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 		},
 	}
 
+	options.addGetFlags(cmd)
 	return cmd
 }
 
@@ -72,7 +73,12 @@ func (o *GetGitOptions) Run() error {
 		if kind == "" {
 			kind = "github"
 		}
-		table.AddRow(s.Name, kind, s.URL)
+		item := &ServerItem{
+			Name: s.Name,
+			Kind: kind,
+			URL:  s.URL,
+		}
+		table.AddItem(item, s.Name, kind, s.URL)
 	}
 	table.Render()
 	return nil
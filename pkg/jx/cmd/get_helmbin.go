@@ -70,7 +70,19 @@ func (o *GetHelmBinOptions) Run() error {
 	if err != nil {
 		return err
 	}
+	if o.isMachineOutput() {
+		table := o.CreateTable()
+		table.AddRow("HELM")
+		table.AddItem(&HelmBinItem{Helm: helm}, helm)
+		table.Render()
+		return nil
+	}
 	log.Infof("Your team uses the helm binary: %s\n", util.ColorInfo(helm))
 	log.Infof("To change this value use: %s\n", util.ColorInfo("jx edit helmbin helm3"))
 	return nil
 }
+
+// HelmBinItem is the machine readable output of the helm binary of a team
+type HelmBinItem struct {
+	Helm string `json:"helm"`
+}
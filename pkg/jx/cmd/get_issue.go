@@ -3,7 +3,6 @@ package cmd
 import (
 	"io"
 	"os/user"
-	"regexp"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -17,6 +16,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/issues"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/table"
 )
 
 // GetIssueOptions contains the command line options
@@ -112,6 +112,10 @@ func (o *GetIssueOptions) Run() error {
 		return errors.Wrap(err, "failed to create the Kubernetes client")
 	}
 
+	state := ""
+	if issue.State != nil {
+		state = *issue.State
+	}
 	found := false
 	for _, env := range envList.Items {
 		envNs, err := kube.GetEnvironmentNamespace(client, ns, env.Name)
@@ -133,18 +137,38 @@ func (o *GetIssueOptions) Run() error {
 		}
 		for _, app := range apps {
 			if o.match(issue.URL, app) {
-				table.AddRow(issue.URL, *issue.State, app, env.Name)
+				addIssueStatusRow(&table, &IssueStatusItem{
+					URL:         issue.URL,
+					Status:      state,
+					Application: app,
+					Environment: env.Name,
+				})
 				found = true
 			}
 		}
 	}
 	if !found {
-		table.AddRow(issue.URL, *issue.State, "", "")
+		addIssueStatusRow(&table, &IssueStatusItem{
+			URL:    issue.URL,
+			Status: state,
+		})
 	}
 	table.Render()
 	return nil
 }
 
+// IssueStatusItem is the machine readable output of the status of an issue in an environment
+type IssueStatusItem struct {
+	URL         string `json:"url"`
+	Status      string `json:"status"`
+	Application string `json:"application,omitempty"`
+	Environment string `json:"environment,omitempty"`
+}
+
+func addIssueStatusRow(table *table.Table, item *IssueStatusItem) {
+	table.AddItem(item, item.URL, item.Status, item.Application, item.Environment)
+}
+
 func (o *GetIssueOptions) findRelease(tracker issues.IssueProvider, issue *gits.GitIssue, releases []v1.Release) *v1.Release {
 	for _, rel := range releases {
 		prs := rel.Spec.PullRequests
@@ -175,10 +199,7 @@ func (o *GetIssueOptions) findRelease(tracker issues.IssueProvider, issue *gits.
 }
 
 func (o *GetIssueOptions) parseIssueIDs(issue v1.IssueSummary, issueKind string) []string {
-	regex := regexp.MustCompile(`(\#\d+)`)
-	if issueKind == issues.Jira {
-		regex = regexp.MustCompile(`[A-Z][A-Z]+-(\d+)`)
-	}
+	regex := issueKeyRegex(issueKind)
 	issues := []string{}
 	foundIssues := map[string]bool{}
 	matches := regex.FindAllStringSubmatch(issue.Body, -1)
@@ -63,6 +63,13 @@ func NewCmdGetIssues(f Factory, in terminal.FileReader, out terminal.FileWriter,
 	return cmd
 }
 
+// IssueItem is the machine readable output of an issue
+type IssueItem struct {
+	Key   string `json:"key,omitempty"`
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
+
 // Run implements this command
 func (o *GetIssuesOptions) Run() error {
 	tracker, err := o.createIssueProvider(o.Dir)
@@ -78,7 +85,12 @@ func (o *GetIssuesOptions) Run() error {
 	table := o.CreateTable()
 	table.AddRow("ISSUE", "TITLE")
 	for _, i := range issues {
-		table.AddRow(i.URL, i.Title)
+		item := &IssueItem{
+			Key:   i.Key,
+			URL:   i.URL,
+			Title: i.Title,
+		}
+		table.AddItem(item, i.URL, i.Title)
 	}
 	table.Render()
 	return nil
@@ -76,9 +76,20 @@ func NewCmdGetLimits(f Factory, in terminal.FileReader, out terminal.FileWriter,
 		},
 	}
 
+	options.addGetFlags(cmd)
 	return cmd
 }
 
+// LimitItem is the machine readable output of the API rate limit of a git user
+type LimitItem struct {
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	Username  string `json:"username"`
+	Limit     int    `json:"limit"`
+	Remaining int    `json:"remaining"`
+	Reset     string `json:"reset,omitempty"`
+}
+
 // Run implements this command
 func (o *GetLimitsOptions) Run() error {
 	authConfigSvc, err := o.CreateGitAuthConfigService()
@@ -110,7 +121,15 @@ func (o *GetLimitsOptions) Run() error {
 					resetLabel = d.String()
 				}
 
-				table.AddRow(s.Name, s.URL, u.Username, strconv.Itoa(r.Resources.Core.Limit), strconv.Itoa(r.Resources.Core.Remaining), resetLabel)
+				item := &LimitItem{
+					Name:      s.Name,
+					URL:       s.URL,
+					Username:  u.Username,
+					Limit:     r.Resources.Core.Limit,
+					Remaining: r.Resources.Core.Remaining,
+					Reset:     resetLabel,
+				}
+				table.AddItem(item, s.Name, s.URL, u.Username, strconv.Itoa(r.Resources.Core.Limit), strconv.Itoa(r.Resources.Core.Remaining), resetLabel)
 			}
 		}
 
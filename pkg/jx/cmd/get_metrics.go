@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// GetMetricsOptions the command line options
+type GetMetricsOptions struct {
+	GetOptions
+}
+
+var (
+	getMetricsLong = templates.LongDesc(`
+		Display metrics about the team's pipelines and deployments
+`)
+
+	getMetricsExample = templates.Examples(`
+		# Display the delivery metrics of each application and environment over the last 4 weeks
+		jx get metrics delivery
+	`)
+)
+
+// NewCmdGetMetrics creates the command
+func NewCmdGetMetrics(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &GetMetricsOptions{
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "metrics",
+		Short:   "Display metrics about the team's pipelines and deployments",
+		Aliases: []string{"metric"},
+		Long:    getMetricsLong,
+		Example: getMetricsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.AddCommand(NewCmdGetMetricsDelivery(f, in, out, errOut))
+	return cmd
+}
+
+// Run implements this command
+func (o *GetMetricsOptions) Run() error {
+	return o.Cmd.Help()
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// GetMetricsDeliveryOptions the command line options
+type GetMetricsDeliveryOptions struct {
+	GetOptions
+	DeliveryMetricsFlags
+
+	EnvironmentsOnly bool
+}
+
+var (
+	getMetricsDeliveryLong = templates.LongDesc(`
+		Display the delivery metrics of the applications in each environment calculated from the promotions recorded
+		in the PipelineActivity resources:
+
+		* deployment frequency: the number of successful promotions per day
+		* lead time: the median time from the commits of a release to its promotion
+		* change failure rate: the ratio of failed or rolled back promotions to the promotions
+		* mean time to restore: the mean time from a failed or rolled back promotion to the next successful promotion or rollback
+
+`)
+
+	getMetricsDeliveryExample = templates.Examples(`
+		# Display the delivery metrics of each application and environment over the last 4 weeks
+		jx get metrics delivery
+
+		# Display the delivery metrics of production for a month as JSON
+		jx get metrics delivery --env production --from-date "October 1 2018" --to-date "October 31 2018" -o json
+
+		# Display the delivery metrics of each environment
+		jx get metrics delivery --environments
+	`)
+)
+
+// NewCmdGetMetricsDelivery creates the command
+func NewCmdGetMetricsDelivery(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &GetMetricsDeliveryOptions{
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "delivery",
+		Short:   "Display the deployment frequency, lead time, change failure rate and mean time to restore of the applications",
+		Aliases: []string{"dora"},
+		Long:    getMetricsDeliveryLong,
+		Example: getMetricsDeliveryExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.DeliveryMetricsFlags.addFlags(cmd)
+	cmd.Flags().BoolVarP(&options.EnvironmentsOnly, "environments", "", false, "Only display the metrics of each environment rather than each application in each environment")
+
+	options.addGetFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *GetMetricsDeliveryOptions) Run() error {
+	report, err := o.createDeliveryReport(&o.DeliveryMetricsFlags)
+	if err != nil {
+		return err
+	}
+	metrics := report.Applications
+	if o.EnvironmentsOnly {
+		metrics = report.Environments
+	}
+	if len(metrics) == 0 && !o.isMachineOutput() {
+		log.Infof("No promotions found from %s to %s\n", util.ColorInfo(util.FormatDate(report.From)), util.ColorInfo(util.FormatDate(report.To)))
+		return nil
+	}
+	table := o.CreateTable()
+	table.AddRow("APPLICATION", "ENVIRONMENT", "DEPLOYMENTS", "PER DAY", "LEAD TIME", "FAILURE RATE", "TIME TO RESTORE")
+	for _, m := range metrics {
+		table.AddItem(m, m.App, m.Environment, fmt.Sprintf("%d", m.Deployments), fmt.Sprintf("%.2f", m.DeploymentsPerDay),
+			formatMetricsDuration(m.LeadTime()), fmt.Sprintf("%.0f%%", m.ChangeFailureRate*100), formatMetricsDuration(m.MeanTimeToRestore()))
+	}
+	table.Render()
+	return nil
+}
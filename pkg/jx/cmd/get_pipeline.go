@@ -20,6 +20,15 @@ type GetPipelineOptions struct {
 	GetOptions
 }
 
+// PipelineItem is the machine readable output of a pipeline
+type PipelineItem struct {
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	LastBuild string `json:"lastBuild,omitempty"`
+	Status    string `json:"status"`
+	Duration  string `json:"duration,omitempty"`
+}
+
 var (
 	get_pipeline_long = templates.LongDesc(`
 		Display one or more pipelines.
@@ -29,6 +38,9 @@ var (
 	get_pipeline_example = templates.Examples(`
 		# List all pipelines
 		jx get pipeline
+
+		# List the names of the pipelines which are building
+		jx get pipeline -o jsonpath='{.items[?(@.status=="Building")].name}'
 	`)
 )
 
@@ -73,14 +85,10 @@ func (o *GetPipelineOptions) Run() error {
 	if err != nil {
 		return err
 	}
-	if len(jobs) == 0 {
+	if len(jobs) == 0 && !o.isMachineOutput() {
 		return outputEmptyListWarning(o.Out)
 	}
 
-	if o.Output != "" {
-		return o.renderResult(jobs, o.Output)
-	}
-
 	table := o.CreateTable()
 	table.AddRow("Name", "URL", "LAST_BUILD", "STATUS", "DURATION")
 
@@ -110,22 +118,37 @@ func (o *GetPipelineOptions) dump(jenkins gojenkins.JenkinsClient, name string,
 		if err != nil {
 			if jenkins.IsErrNotFound(err) {
 				if o.matchesFilter(&job) {
-					table.AddRow(job.FullName, job.Url, "", "Never Built", "")
+					addPipelineRow(table, &PipelineItem{
+						Name:   job.FullName,
+						URL:    job.Url,
+						Status: "Never Built",
+					})
 				}
 			}
 			return nil
 		}
 		if o.matchesFilter(&job) {
+			item := &PipelineItem{
+				Name:      job.FullName,
+				URL:       job.Url,
+				LastBuild: "#" + last.Id,
+				Status:    last.Result,
+				Duration:  time.Duration(last.Duration).String(),
+			}
 			if last.Building {
-				table.AddRow(job.FullName, job.Url, "#"+last.Id, "Building", time.Duration(last.EstimatedDuration).String()+"(est.)")
-			} else {
-				table.AddRow(job.FullName, job.Url, "#"+last.Id, last.Result, time.Duration(last.Duration).String())
+				item.Status = "Building"
+				item.Duration = time.Duration(last.EstimatedDuration).String() + "(est.)"
 			}
+			addPipelineRow(table, item)
 		}
 	}
 	return nil
 }
 
+func addPipelineRow(table *table.Table, item *PipelineItem) {
+	table.AddItem(item, item.Name, item.URL, item.LastBuild, item.Status, item.Duration)
+}
+
 func (o *GetPipelineOptions) matchesFilter(job *gojenkins.Job) bool {
 	args := o.Args
 	if len(args) == 0 {
@@ -25,13 +25,13 @@ var (
 
 // GetPostPreviewJobOptions the options for the create spring command
 type GetPostPreviewJobOptions struct {
-	CreateOptions
+	GetOptions
 }
 
 // NewCmdGetPostPreviewJob creates a command object for the "create" command
 func NewCmdGetPostPreviewJob(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
 	options := &GetPostPreviewJobOptions{
-		CreateOptions: CreateOptions{
+		GetOptions: GetOptions{
 			CommonOptions: CommonOptions{
 				Factory: f,
 				In:      in,
@@ -56,6 +56,7 @@ func NewCmdGetPostPreviewJob(f Factory, in terminal.FileReader, out terminal.Fil
 		},
 	}
 	options.addCommonFlags(cmd)
+	options.addGetFlags(cmd)
 	return cmd
 }
 
@@ -68,7 +69,8 @@ func (o *GetPostPreviewJobOptions) Run() error {
 	table := o.CreateTable()
 	table.AddRow("NAME", "IMAGE", "BACKOFF_LIMIT", "COMMAND")
 
-	for _, job := range settings.PostPreviewJobs {
+	for i := range settings.PostPreviewJobs {
+		job := &settings.PostPreviewJobs[i]
 		name := job.Name
 		image := ""
 		commands := []string{}
@@ -82,7 +84,7 @@ func (o *GetPostPreviewJobOptions) Run() error {
 		if job.Spec.BackoffLimit != nil {
 			backoffLimit = strconv.Itoa(int(*job.Spec.BackoffLimit))
 		}
-		table.AddRow(name, image, backoffLimit, strings.Join(commands, " "))
+		table.AddItem(job, name, image, backoffLimit, strings.Join(commands, " "))
 	}
 	table.Render()
 
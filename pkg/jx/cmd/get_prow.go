@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/prow"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// GetProwOptions containers the CLI options
+type GetProwOptions struct {
+	GetOptions
+
+	Filter string
+}
+
+var (
+	getProwLong = templates.LongDesc(`
+		Display the effective Prow configuration of each repository.
+
+		This shows the presubmit and postsubmit jobs, the commit statuses and approvals required before a pull request
+		can be merged, the labels Tide needs to merge the pull request and the enabled plugins.
+`)
+
+	getProwExample = templates.Examples(`
+		# List the Prow configuration of all repositories
+		jx get prow
+
+		# Filter the repositories
+		jx get prow -f myapp
+
+		# View the full Prow configuration of the repositories as YAML
+		jx get prow -o yaml
+	`)
+)
+
+// NewCmdGetProw creates the new command for: jx get prow
+func NewCmdGetProw(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &GetProwOptions{
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "prow",
+		Short:   "Display the effective Prow configuration of each repository",
+		Long:    getProwLong,
+		Example: getProwExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Filter, "filter", "f", "", "Filter the repositories with the given text")
+
+	options.addGetFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *GetProwOptions) Run() error {
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	prowOptions := prow.Options{
+		KubeClient: kubeClient,
+		NS:         ns,
+	}
+	configs, err := prowOptions.GetRepositoryConfigs()
+	if err != nil {
+		return err
+	}
+	filtered := []*prow.RepositoryConfig{}
+	for _, c := range configs {
+		if o.Filter == "" || strings.Contains(c.Repo, o.Filter) {
+			filtered = append(filtered, c)
+		}
+	}
+	if len(filtered) == 0 && !o.isMachineOutput() {
+		suffix := ""
+		if o.Filter != "" {
+			suffix = fmt.Sprintf(" for filter: %s", util.ColorInfo(o.Filter))
+		}
+		log.Infof("No Prow configuration found in namespace %s%s.\n", util.ColorInfo(ns), suffix)
+		return nil
+	}
+	table := o.CreateTable()
+	table.AddRow("REPO", "PRESUBMITS", "POSTSUBMITS", "REQUIRED CONTEXTS", "APPROVALS", "TIDE", "PLUGINS")
+	for _, c := range filtered {
+		table.AddItem(c, c.Repo, strings.Join(c.Presubmits, ", "), strings.Join(c.Postsubmits, ", "),
+			strings.Join(c.RequiredContexts, ", "), strconv.Itoa(c.RequiredApprovals), prowTideText(c), strconv.Itoa(len(c.Plugins)))
+	}
+	table.Render()
+	return nil
+}
+
+// prowTideText describes when Tide merges the pull requests of the repository
+func prowTideText(c *prow.RepositoryConfig) string {
+	if !c.Merge {
+		return "no"
+	}
+	if len(c.TideLabels) == 0 {
+		return "yes"
+	}
+	return strings.Join(c.TideLabels, ", ")
+}
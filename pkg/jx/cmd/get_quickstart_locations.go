@@ -93,12 +93,13 @@ func (o *GetQuickstartLocationOptions) Run() error {
 	table := o.CreateTable()
 	table.AddRow("GIT SERVER", "KIND", "OWNER", "INCLUDES", "EXCLUDES")
 
-	for _, location := range locations {
+	for i := range locations {
+		location := &locations[i]
 		kind := location.GitKind
 		if kind == "" {
 			kind = gits.KindGitHub
 		}
-		table.AddRow(location.GitURL, kind, location.Owner, strings.Join(location.Includes, ", "), strings.Join(location.Excludes, ", "))
+		table.AddItem(location, location.GitURL, kind, location.Owner, strings.Join(location.Includes, ", "), strings.Join(location.Excludes, ", "))
 	}
 	table.Render()
 	return nil
@@ -85,7 +85,7 @@ func (o *GetReleaseOptions) Run() error {
 	if err != nil {
 		return err
 	}
-	if len(releases) == 0 {
+	if len(releases) == 0 && !o.isMachineOutput() {
 		suffix := ""
 		if o.Filter != "" {
 			suffix = fmt.Sprintf(" for filter: %s", util.ColorInfo(o.Filter))
@@ -96,8 +96,9 @@ func (o *GetReleaseOptions) Run() error {
 	}
 	table := o.CreateTable()
 	table.AddRow("NAME", "VERSION")
-	for _, release := range releases {
-		table.AddRow(release.Spec.Name, release.Spec.Version)
+	for i := range releases {
+		release := &releases[i]
+		table.AddItem(release, release.Spec.Name, release.Spec.Version)
 	}
 	table.Render()
 	return nil
@@ -18,6 +18,11 @@ type GetTeamOptions struct {
 	Pending bool
 }
 
+// TeamItem is the machine readable output of a team
+type TeamItem struct {
+	Name string `json:"name"`
+}
+
 var (
 	getTeamLong = templates.LongDesc(`
 		Display the Team or Teams a user is a member of.
@@ -77,7 +82,7 @@ func (o *GetTeamOptions) Run() error {
 	if err != nil {
 		return err
 	}
-	if len(teams) == 0 {
+	if len(teams) == 0 && !o.isMachineOutput() {
 		log.Info(`
 You do not belong to any teams.
 Have you installed Jenkins X yet to create a team?
@@ -89,7 +94,7 @@ See https://jenkins-x.io/getting-started/\n for more detail
 	table := o.CreateTable()
 	table.AddRow("NAME")
 	for _, team := range teams {
-		table.AddRow(team.Name)
+		table.AddItem(&TeamItem{Name: team.Name}, team.Name)
 	}
 	table.Render()
 	return nil
@@ -111,7 +116,7 @@ func (o *GetTeamOptions) getPendingTeams() error {
 		return err
 	}
 
-	if len(names) == 0 {
+	if len(names) == 0 && !o.isMachineOutput() {
 		log.Info(`
 There are no pending Teams yet. Try create one via: jx create team --pending
 `)
@@ -120,9 +125,13 @@ There are no pending Teams yet. Try create one via: jx create team --pending
 
 	table := o.CreateTable()
 	table.AddRow("NAME", "STATUS", "KIND", "MEMBERS")
-	for _, team := range teams {
+	for _, name := range names {
+		team := teams[name]
+		if team == nil {
+			continue
+		}
 		spec := &team.Spec
-		table.AddRow(team.Name, string(team.Status.ProvisionStatus), string(spec.Kind), strings.Join(spec.Members, ", "))
+		table.AddItem(team, team.Name, string(team.Status.ProvisionStatus), string(spec.Kind), strings.Join(spec.Members, ", "))
 	}
 	table.Render()
 	return nil
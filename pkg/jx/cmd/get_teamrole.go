@@ -66,7 +66,7 @@ func (o *GetTeamRoleOptions) Run() error {
 	if err != nil {
 		return err
 	}
-	if len(teamRoles) == 0 {
+	if len(teamRoles) == 0 && !o.isMachineOutput() {
 		log.Info(`
 There are no Team roles defined so far!
 `)
@@ -86,8 +86,20 @@ There are no Team roles defined so far!
 				description = ann[kube.AnnotationDescription]
 			}
 		}
-		table.AddRow(name, title, description)
+		item := &TeamRoleItem{
+			Name:        name,
+			Title:       title,
+			Description: description,
+		}
+		table.AddItem(item, name, title, description)
 	}
 	table.Render()
 	return nil
 }
+
+// TeamRoleItem is the machine readable output of a team role
+type TeamRoleItem struct {
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
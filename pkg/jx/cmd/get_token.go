@@ -47,6 +47,16 @@ func NewCmdGetToken(f Factory, in terminal.FileReader, out terminal.FileWriter,
 func (o *GetTokenOptions) addFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.Kind, "kind", "k", "", "Filters the services by the kind")
 	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "Filters the services by the name")
+	o.addGetFlags(cmd)
+}
+
+// TokenItem is the machine readable output of a user of a service. The token itself is never output
+type TokenItem struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	Username string `json:"username,omitempty"`
+	Token    bool   `json:"token"`
 }
 
 // Run implements this command
@@ -70,7 +80,12 @@ func (o *GetTokenOptions) displayUsersWithTokens(authConfigSvc auth.AuthConfigSe
 			user := ""
 			pwd := ""
 			if len(s.Users) == 0 {
-				table.AddRow(kind, name, kind, s.URL, user, pwd)
+				item := &TokenItem{
+					Kind: kind,
+					Name: name,
+					URL:  s.URL,
+				}
+				table.AddItem(item, kind, name, s.URL, user, pwd)
 			} else {
 				for _, u := range s.Users {
 					user = u.Username
@@ -79,7 +94,14 @@ func (o *GetTokenOptions) displayUsersWithTokens(authConfigSvc auth.AuthConfigSe
 						pwd = "yes"
 					}
 				}
-				table.AddRow(kind, name, s.URL, user, pwd)
+				item := &TokenItem{
+					Kind:     kind,
+					Name:     name,
+					URL:      s.URL,
+					Username: user,
+					Token:    pwd != "",
+				}
+				table.AddItem(item, kind, name, s.URL, user, pwd)
 			}
 		}
 	}
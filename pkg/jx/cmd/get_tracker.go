@@ -60,6 +60,7 @@ func NewCmdGetTracker(f Factory, in terminal.FileReader, out terminal.FileWriter
 		},
 	}
 	cmd.Flags().StringVarP(&options.Kind, "kind", "k", "", "Filters the issue trackers by the kinds: "+strings.Join(issues.IssueTrackerKinds, ", "))
+	options.addGetFlags(cmd)
 	return cmd
 }
 
@@ -70,7 +71,7 @@ func (o *GetTrackerOptions) Run() error {
 		return err
 	}
 	config := authConfigSvc.Config()
-	if len(config.Servers) == 0 {
+	if len(config.Servers) == 0 && !o.isMachineOutput() {
 		log.Infof("No issue trackers registered. To register a new issue tracker use: %s\n", util.ColorInfo("jx create tracker server"))
 		return nil
 	}
@@ -86,10 +87,15 @@ func (o *GetTrackerOptions) Run() error {
 
 	for _, s := range config.Servers {
 		kind := s.Kind
-		if filterKind == "" || filterKind == kind {
-			table.AddRow(s.Name, kind, s.URL)
+		item := &ServerItem{
+			Name: s.Name,
+			Kind: kind,
+			URL:  s.URL,
+		}
+		if filterKind == "" {
+			table.AddItem(item, s.Name, kind, s.URL)
 		} else if filterKind == kind {
-			table.AddRow(s.Name, s.URL)
+			table.AddItem(item, s.Name, s.URL)
 		}
 	}
 	table.Render()
@@ -64,6 +64,7 @@ func NewCmdGetURL(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 func (o *GetURLOptions) addGetUrlFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&o.Namespace, "namespace", "n", "", "Specifies the namespace name to look inside")
 	cmd.Flags().StringVarP(&o.Environment, "env", "e", "", "Specifies the Environment name to look inside")
+	o.addGetFlags(cmd)
 }
 
 // Run implements this command
@@ -87,8 +88,9 @@ func (o *GetURLOptions) Run() error {
 	table := o.CreateTable()
 	table.AddRow("Name", "URL")
 
-	for _, url := range urls {
-		table.AddRow(url.Name, url.URL)
+	for i := range urls {
+		url := &urls[i]
+		table.AddItem(url, url.Name, url.URL)
 	}
 	table.Render()
 	return nil
@@ -79,7 +79,7 @@ func (o *GetUserOptions) Run() error {
 		return err
 	}
 
-	if len(names) == 0 {
+	if len(names) == 0 && !o.isMachineOutput() {
 		log.Info(`
 There are no Users yet. Try create one via: jx create user
 `)
@@ -98,10 +98,26 @@ There are no Users yet. Try create one via: jx create user
 			if err != nil {
 				log.Warnf("Failed to find User roles in namespace %s for User %s kind %s: %s\n", ns, userName, userKind, err)
 			}
-			table.AddRow(userName, spec.Name, spec.Email, spec.URL, strings.Join(roleNames, ", "))
+			item := &UserItem{
+				Login: userName,
+				Name:  spec.Name,
+				Email: spec.Email,
+				URL:   spec.URL,
+				Roles: roleNames,
+			}
+			table.AddItem(item, userName, spec.Name, spec.Email, spec.URL, strings.Join(roleNames, ", "))
 		}
 	}
 	table.Render()
 	return nil
 
 }
+
+// UserItem is the machine readable output of a user
+type UserItem struct {
+	Login string   `json:"login"`
+	Name  string   `json:"name,omitempty"`
+	Email string   `json:"email,omitempty"`
+	URL   string   `json:"url,omitempty"`
+	Roles []string `json:"roles"`
+}
@@ -81,8 +81,9 @@ func (o *GetVaultOptions) Run() error {
 
 	table := o.CreateTable()
 	table.AddRow("NAME", "URL", "AUTH-SERVICE-ACCOUNT")
-	for _, vault := range vaults {
-		table.AddRow(vault.Name, vault.URL, vault.AuthServiceAccountName)
+	for i := range vaults {
+		vault := &vaults[i]
+		table.AddItem(vault, vault.Name, vault.URL, vault.AuthServiceAccountName)
 	}
 	table.Render()
 
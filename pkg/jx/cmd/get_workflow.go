@@ -94,8 +94,9 @@ func (o *GetWorkflowOptions) Run() error {
 
 	table := o.CreateTable()
 	table.AddRow("WORKFLOW")
-	for _, workflow := range workflows.Items {
-		table.AddRow(workflow.Name)
+	for i := range workflows.Items {
+		workflow := &workflows.Items[i]
+		table.AddItem(workflow, workflow.Name)
 	}
 	table.Render()
 	return nil
@@ -1018,19 +1018,19 @@ func (options *ImportOptions) doImport() error {
 		if err != nil {
 			return err
 		}
-		return options.addProwConfig(gitURL)
+		return options.addProwConfig(gitURL, gitProvider)
 	}
 
 	return options.ImportProject(gitURL, options.Dir, jenkinsfile, options.BranchPattern, options.Credentials, false, gitProvider, authConfigSvc, false, options.BatchMode)
 }
 
-func (options *ImportOptions) addProwConfig(gitURL string) error {
+func (options *ImportOptions) addProwConfig(gitURL string, gitProvider gits.GitProvider) error {
 	gitInfo, err := gits.ParseGitURL(gitURL)
 	if err != nil {
 		return err
 	}
 	repo := gitInfo.Organisation + "/" + gitInfo.Name
-	err = prow.AddApplication(options.KubeClientCached, []string{repo}, options.currentNamespace, options.DraftPack)
+	err = prow.AddApplication(options.KubeClientCached, []string{repo}, options.currentNamespace, options.DraftPack, gitProvider)
 	if err != nil {
 		return err
 	}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/Pallinder/go-randomdata"
+	"github.com/ghodss/yaml"
 	"github.com/jenkins-x/jx/pkg/addon"
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/auth"
@@ -46,6 +47,8 @@ type InstallOptions struct {
 
 	InitOptions InitOptions
 	Flags       InstallFlags
+
+	manifest *InstallManifest
 }
 
 // InstallFlags flags for the install command
@@ -69,6 +72,8 @@ type InstallFlags struct {
 	Version                  string
 	Prow                     bool
 	DisableSetKubeContext    bool
+	File                     string
+	Export                   string
 }
 
 // Secrets struct for secrets
@@ -93,6 +98,9 @@ const (
 
 	ServerlessJenkins   = "Serverless Jenkins"
 	StaticMasterJenkins = "Static Master Jenkins"
+
+	// InstallManifestValuesFile the helm values of the install manifest
+	InstallManifestValuesFile = "installManifestValues.yaml"
 )
 
 var (
@@ -121,6 +129,12 @@ var (
 
 		# If you know the cloud provider you can pass this as a CLI argument. E.g. for AWS
 		jx install --provider=aws
+
+		# Install using the provider, git server, environments, addons and team settings in an install manifest
+		jx install --file jx-install.yaml
+
+		# Write the install manifest of the current installation
+		jx install --export jx-install.yaml
 `)
 )
 
@@ -148,6 +162,8 @@ func NewCmdInstall(f Factory, in terminal.FileReader, out terminal.FileWriter, e
 	options.addInstallFlags(cmd, false)
 
 	cmd.Flags().StringVarP(&options.Flags.Provider, "provider", "", "", "Cloud service providing the Kubernetes cluster.  Supported providers: "+KubernetesProviderOptions())
+	cmd.Flags().StringVarP(&options.Flags.File, "file", "", "", "The install manifest YAML file to install from. The values in the manifest override the flags")
+	cmd.Flags().StringVarP(&options.Flags.Export, "export", "", "", "Writes the install manifest of the current installation to the given file rather than installing")
 	return cmd
 }
 
@@ -237,6 +253,17 @@ func (flags *InstallFlags) addCloudEnvOptions(cmd *cobra.Command) {
 
 // Run implements this command
 func (options *InstallOptions) Run() error {
+	if options.Flags.Export != "" {
+		return options.exportInstallManifest(options.Flags.Export)
+	}
+	if options.Flags.File != "" {
+		manifest, err := LoadInstallManifest(options.Flags.File)
+		if err != nil {
+			return err
+		}
+		options.applyInstallManifest(manifest)
+	}
+
 	originalGitUsername := options.GitRepositoryOptions.Username
 	originalGitServer := options.GitRepositoryOptions.ServerURL
 	originalGitToken := options.GitRepositoryOptions.ApiToken
@@ -402,6 +429,12 @@ func (options *InstallOptions) Run() error {
 			return err
 		}
 	}
+	if options.manifest != nil && options.manifest.Storage != nil && options.manifest.Storage.DefaultStorageClass != "" {
+		err = options.changeDefaultStorageClass(client, options.manifest.Storage.DefaultStorageClass)
+		if err != nil {
+			return err
+		}
+	}
 
 	if currentContext == "minikube" {
 		if options.Flags.Provider == "" {
@@ -708,6 +741,18 @@ func (options *InstallOptions) Run() error {
 	if err != nil {
 		return errors.Wrap(err, "failed to append the myvalues.yaml file")
 	}
+	if options.manifest != nil && len(options.manifest.HelmValues) > 0 {
+		manifestValuesFileName := filepath.Join(dir, InstallManifestValuesFile)
+		data, err := yaml.Marshal(options.manifest.HelmValues)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal the helm values of the install manifest")
+		}
+		err = ioutil.WriteFile(manifestValuesFileName, data, 0644)
+		if err != nil {
+			return errors.Wrap(err, "failed to write the helm values of the install manifest")
+		}
+		valueFiles = append(valueFiles, manifestValuesFileName)
+	}
 
 	options.currentNamespace = ns
 	if options.Flags.Prow {
@@ -869,6 +914,11 @@ func (options *InstallOptions) Run() error {
 		return errors.Wrap(err, "failed to load the addons configuration")
 	}
 
+	if options.manifest != nil {
+		for _, name := range options.manifest.Addons {
+			addonConfig.GetOrCreate(name).Enabled = true
+		}
+	}
 	for _, ac := range addonConfig.Addons {
 		if ac.Enabled {
 			err = options.installAddon(ac.Name)
@@ -907,7 +957,12 @@ func (options *InstallOptions) Run() error {
 		log.Warnf("failed to update the Jenkins external URL")
 	}
 
-	if !options.Flags.NoDefaultEnvironments {
+	if options.manifest != nil && len(options.manifest.Environments) > 0 {
+		err = options.createInstallManifestEnvironments(jxClient, ns)
+		if err != nil {
+			return err
+		}
+	} else if !options.Flags.NoDefaultEnvironments {
 		// lets only recreate the environments if its the first time we run this
 		_, envNames, err := kube.GetEnvironments(jxClient, ns)
 		if err != nil || len(envNames) <= 1 {
@@ -946,6 +1001,22 @@ func (options *InstallOptions) Run() error {
 		}
 	}
 
+	if options.manifest != nil && options.manifest.TeamSettings != nil {
+		callback := func(env *v1.Environment) error {
+			settings, err := MergeTeamSettings(&env.Spec.TeamSettings, options.manifest.TeamSettings)
+			if err != nil {
+				return err
+			}
+			env.Spec.TeamSettings = *settings
+			log.Info("Applying the TeamSettings of the install manifest\n")
+			return nil
+		}
+		err = options.ModifyDevEnvironment(callback)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = options.saveChartmuseumAuthConfig()
 	if err != nil {
 		return errors.Wrap(err, "failed to save the auth config for Chartmuseum")
@@ -0,0 +1,463 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+
+	"github.com/Pallinder/go-randomdata"
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// defaultStorageClassAnnotation the annotation marking the default storage class of a cluster
+	defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+	// InstallDriftMissing the resource is in the install manifest but not in the cluster
+	InstallDriftMissing = "Missing"
+	// InstallDriftUnexpected the resource is in the cluster but not in the install manifest
+	InstallDriftUnexpected = "Unexpected"
+	// InstallDriftChanged the resource in the cluster is different to the install manifest
+	InstallDriftChanged = "Changed"
+)
+
+// InstallManifest the declarative configuration of a Jenkins X installation used by 'jx install --file'
+type InstallManifest struct {
+	Provider       string                       `json:"provider,omitempty"`
+	Namespace      string                       `json:"namespace,omitempty"`
+	Version        string                       `json:"version,omitempty"`
+	Domain         string                       `json:"domain,omitempty"`
+	DockerRegistry string                       `json:"dockerRegistry,omitempty"`
+	Prow           bool                         `json:"prow,omitempty"`
+	GitServer      *InstallManifestGitServer    `json:"gitServer,omitempty"`
+	Environments   []InstallManifestEnvironment `json:"environments,omitempty"`
+	Addons         []string                     `json:"addons,omitempty"`
+	TeamSettings   *v1.TeamSettings             `json:"teamSettings,omitempty"`
+	Storage        *InstallManifestStorage      `json:"storage,omitempty"`
+	// HelmValues additional values passed to the helm install of the platform chart
+	HelmValues map[string]interface{} `json:"helmValues,omitempty"`
+}
+
+// InstallManifestGitServer the git server used for the environment repositories. The API token is not stored in
+// the manifest so it is passed via --git-api-token or the JX_GIT_TOKEN environment variable
+type InstallManifestGitServer struct {
+	URL      string `json:"url,omitempty"`
+	Username string `json:"username,omitempty"`
+	Owner    string `json:"owner,omitempty"`
+}
+
+// InstallManifestEnvironment a permanent environment created by the install
+type InstallManifestEnvironment struct {
+	Name              string                   `json:"name"`
+	Label             string                   `json:"label,omitempty"`
+	Namespace         string                   `json:"namespace,omitempty"`
+	PromotionStrategy v1.PromotionStrategyType `json:"promotionStrategy,omitempty"`
+	Order             int32                    `json:"order,omitempty"`
+	GitURL            string                   `json:"gitUrl,omitempty"`
+}
+
+// InstallManifestStorage the storage configuration of the cluster
+type InstallManifestStorage struct {
+	DefaultStorageClass string `json:"defaultStorageClass,omitempty"`
+}
+
+// InstallDrift a difference between an install manifest and the cluster
+type InstallDrift struct {
+	Resource string `json:"resource"`
+	Name     string `json:"name"`
+	Change   string `json:"change"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// LoadInstallManifest loads and validates the install manifest in the given file
+func LoadInstallManifest(fileName string) (*InstallManifest, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load install manifest %s", fileName)
+	}
+	manifest := &InstallManifest{}
+	err = yaml.Unmarshal(data, manifest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal install manifest %s", fileName)
+	}
+	err = manifest.Validate()
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid install manifest %s", fileName)
+	}
+	return manifest, nil
+}
+
+// Validate returns an error if the manifest is not valid
+func (m *InstallManifest) Validate() error {
+	names := map[string]bool{}
+	for _, env := range m.Environments {
+		if env.Name == "" {
+			return fmt.Errorf("missing environment name")
+		}
+		if env.Name == kube.LabelValueDevEnvironment {
+			return fmt.Errorf("the %s environment is created by the install and cannot be specified", env.Name)
+		}
+		if names[env.Name] {
+			return fmt.Errorf("duplicate environment %s", env.Name)
+		}
+		names[env.Name] = true
+		if env.PromotionStrategy != "" && util.StringArrayIndex(v1.PromotionStrategyTypeValues, string(env.PromotionStrategy)) < 0 {
+			return util.InvalidOption("promotionStrategy", string(env.PromotionStrategy), v1.PromotionStrategyTypeValues)
+		}
+	}
+	return nil
+}
+
+// Save saves the manifest to the given file
+func (m *InstallManifest) Save(fileName string) error {
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, data, util.DefaultWritePermissions)
+}
+
+// MergeTeamSettings returns the current team settings overridden by the fields which are specified in the manifest
+func MergeTeamSettings(current *v1.TeamSettings, manifest *v1.TeamSettings) (*v1.TeamSettings, error) {
+	if manifest == nil {
+		return current, nil
+	}
+	values := map[string]interface{}{}
+	for _, settings := range []*v1.TeamSettings{current, manifest} {
+		data, err := json.Marshal(settings)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(data, &values)
+		if err != nil {
+			return nil, err
+		}
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	answer := &v1.TeamSettings{}
+	err = json.Unmarshal(data, answer)
+	return answer, err
+}
+
+// DiffInstallManifest compares the desired install manifest with the manifest of the cluster. Only the fields which
+// are specified in the desired manifest are compared
+func DiffInstallManifest(desired *InstallManifest, live *InstallManifest) ([]InstallDrift, error) {
+	answer := []InstallDrift{}
+	changed := func(resource, name, expected, actual string) {
+		if expected != "" && expected != actual {
+			answer = append(answer, InstallDrift{
+				Resource: resource,
+				Name:     name,
+				Change:   InstallDriftChanged,
+				Detail:   fmt.Sprintf("manifest: %s cluster: %s", expected, actual),
+			})
+		}
+	}
+	changed("install", "provider", desired.Provider, live.Provider)
+	changed("install", "namespace", desired.Namespace, live.Namespace)
+	changed("install", "version", desired.Version, live.Version)
+	changed("install", "domain", desired.Domain, live.Domain)
+	changed("install", "dockerRegistry", desired.DockerRegistry, live.DockerRegistry)
+	if desired.Prow != live.Prow {
+		changed("install", "prow", fmt.Sprintf("%t", desired.Prow), fmt.Sprintf("%t", live.Prow))
+	}
+	if desired.Storage != nil {
+		liveStorageClass := ""
+		if live.Storage != nil {
+			liveStorageClass = live.Storage.DefaultStorageClass
+		}
+		changed("storage", "defaultStorageClass", desired.Storage.DefaultStorageClass, liveStorageClass)
+	}
+
+	liveEnvs := map[string]InstallManifestEnvironment{}
+	for _, env := range live.Environments {
+		liveEnvs[env.Name] = env
+	}
+	desiredEnvs := map[string]bool{}
+	for _, env := range desired.Environments {
+		desiredEnvs[env.Name] = true
+		liveEnv, ok := liveEnvs[env.Name]
+		if !ok {
+			answer = append(answer, InstallDrift{Resource: "environment", Name: env.Name, Change: InstallDriftMissing})
+			continue
+		}
+		changed("environment", env.Name+" label", env.Label, liveEnv.Label)
+		changed("environment", env.Name+" namespace", env.Namespace, liveEnv.Namespace)
+		changed("environment", env.Name+" promotionStrategy", string(env.PromotionStrategy), string(liveEnv.PromotionStrategy))
+		changed("environment", env.Name+" gitUrl", env.GitURL, liveEnv.GitURL)
+		if env.Order != 0 && env.Order != liveEnv.Order {
+			changed("environment", env.Name+" order", fmt.Sprintf("%d", env.Order), fmt.Sprintf("%d", liveEnv.Order))
+		}
+	}
+	if len(desired.Environments) > 0 {
+		for _, env := range live.Environments {
+			if !desiredEnvs[env.Name] {
+				answer = append(answer, InstallDrift{Resource: "environment", Name: env.Name, Change: InstallDriftUnexpected})
+			}
+		}
+	}
+
+	for _, addon := range desired.Addons {
+		if util.StringArrayIndex(live.Addons, addon) < 0 {
+			answer = append(answer, InstallDrift{Resource: "addon", Name: addon, Change: InstallDriftMissing})
+		}
+	}
+	for _, addon := range live.Addons {
+		if util.StringArrayIndex(desired.Addons, addon) < 0 {
+			answer = append(answer, InstallDrift{Resource: "addon", Name: addon, Change: InstallDriftUnexpected})
+		}
+	}
+
+	if desired.TeamSettings != nil {
+		current := live.TeamSettings
+		if current == nil {
+			current = &v1.TeamSettings{}
+		}
+		merged, err := MergeTeamSettings(current, desired.TeamSettings)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(current, merged) {
+			diff, err := resourceDiff(current, merged)
+			if err != nil {
+				return nil, err
+			}
+			answer = append(answer, InstallDrift{Resource: "teamsettings", Name: kube.LabelValueDevEnvironment, Change: InstallDriftChanged, Detail: diff})
+		}
+	}
+	return answer, nil
+}
+
+// applyInstallManifest applies the install manifest to the install options
+func (options *InstallOptions) applyInstallManifest(manifest *InstallManifest) {
+	options.manifest = manifest
+	flags := &options.Flags
+	if manifest.Provider != "" {
+		flags.Provider = manifest.Provider
+	}
+	if manifest.Namespace != "" {
+		flags.Namespace = manifest.Namespace
+	}
+	if manifest.Version != "" {
+		flags.Version = manifest.Version
+	}
+	if manifest.Domain != "" {
+		flags.Domain = manifest.Domain
+	}
+	if manifest.DockerRegistry != "" {
+		flags.DockerRegistry = manifest.DockerRegistry
+	}
+	if manifest.Prow {
+		flags.Prow = true
+	}
+	gitServer := manifest.GitServer
+	if gitServer != nil {
+		if gitServer.URL != "" {
+			options.GitRepositoryOptions.ServerURL = gitServer.URL
+		}
+		if gitServer.Username != "" {
+			options.GitRepositoryOptions.Username = gitServer.Username
+		}
+		if gitServer.Owner != "" {
+			flags.EnvironmentGitOwner = gitServer.Owner
+		}
+	}
+}
+
+// createInstallManifestEnvironments creates the environments of the install manifest which do not exist yet
+func (options *InstallOptions) createInstallManifestEnvironments(jxClient versioned.Interface, ns string) error {
+	envMap, _, err := kube.GetEnvironments(jxClient, ns)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the environments in namespace %s", ns)
+	}
+	createEnv := &options.CreateEnvOptions
+	createEnv.GitRepositoryOptions = options.GitRepositoryOptions
+	createEnv.GitRepositoryOptions.Owner = options.Flags.EnvironmentGitOwner
+	createEnv.Prefix = options.Flags.DefaultEnvironmentPrefix
+	if createEnv.Prefix == "" {
+		createEnv.Prefix = strings.ToLower(randomdata.SillyName())
+	}
+	createEnv.Prow = options.Flags.Prow
+	if options.BatchMode {
+		createEnv.BatchMode = options.BatchMode
+	}
+	for _, env := range options.manifest.Environments {
+		if envMap[env.Name] != nil {
+			log.Infof("Environment %s already exists\n", util.ColorInfo(env.Name))
+			continue
+		}
+		label := env.Label
+		if label == "" {
+			label = strings.Title(env.Name)
+		}
+		strategy := env.PromotionStrategy
+		if strategy == "" {
+			strategy = v1.PromotionStrategyTypeAutomatic
+		}
+		createEnv.Options = v1.Environment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: env.Name,
+			},
+			Spec: v1.EnvironmentSpec{
+				Label:             label,
+				Namespace:         env.Namespace,
+				Order:             env.Order,
+				PromotionStrategy: strategy,
+				Source: v1.EnvironmentRepository{
+					URL: env.GitURL,
+				},
+			},
+		}
+		createEnv.PromotionStrategy = string(strategy)
+		log.Infof("Creating the %s environment of the install manifest\n", util.ColorInfo(env.Name))
+		err = createEnv.Run()
+		if err != nil {
+			return errors.Wrapf(err, "failed to create the %s environment in namespace %s", env.Name, ns)
+		}
+	}
+	return nil
+}
+
+// exportInstallManifest writes the install manifest of the current installation to the given file
+func (options *InstallOptions) exportInstallManifest(fileName string) error {
+	manifest, err := options.liveInstallManifest()
+	if err != nil {
+		return err
+	}
+	err = manifest.Save(fileName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save install manifest %s", fileName)
+	}
+	log.Infof("Exported the install manifest to %s\n", util.ColorInfo(fileName))
+	return nil
+}
+
+// liveInstallManifest creates the install manifest of the current installation from the cluster
+func (o *CommonOptions) liveInstallManifest() (*InstallManifest, error) {
+	err := o.registerEnvironmentCRD()
+	if err != nil {
+		return nil, err
+	}
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, _, err := o.KubeClient()
+	if err != nil {
+		return nil, err
+	}
+	envMap, names, err := kube.GetEnvironments(jxClient, ns)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load the environments in namespace %s", ns)
+	}
+	devEnv := envMap[kube.LabelValueDevEnvironment]
+	if devEnv == nil {
+		return nil, fmt.Errorf("no Jenkins X installation found in namespace %s", ns)
+	}
+	settings := devEnv.Spec.TeamSettings
+	manifest := &InstallManifest{
+		Provider:     settings.KubeProvider,
+		Namespace:    ns,
+		Prow:         devEnv.Spec.WebHookEngine == v1.WebHookEngineProw,
+		TeamSettings: &settings,
+	}
+
+	ingressConfig, err := kube.GetIngressConfig(kubeClient, ns)
+	if err == nil {
+		manifest.Domain = ingressConfig.Domain
+	}
+	installSecret, err := kubeClient.CoreV1().Secrets(ns).Get(JXInstallConfig, metav1.GetOptions{})
+	if err == nil {
+		helmValues := config.HelmValuesConfig{}
+		err = yaml.Unmarshal(installSecret.Data[ExtraValuesFile], &helmValues)
+		if err == nil {
+			manifest.DockerRegistry = helmValues.Jenkins.Servers.Global.EnvVars["DOCKER_REGISTRY"]
+		}
+	}
+	charts, err := o.Helm().ListCharts()
+	if err == nil {
+		manifest.Version = platformChartVersion(charts)
+	}
+
+	for _, name := range names {
+		env := envMap[name]
+		if env.Spec.Kind == v1.EnvironmentKindTypeDevelopment || !env.Spec.Kind.IsPermanent() {
+			continue
+		}
+		manifest.Environments = append(manifest.Environments, InstallManifestEnvironment{
+			Name:              env.Name,
+			Label:             env.Spec.Label,
+			Namespace:         env.Spec.Namespace,
+			PromotionStrategy: env.Spec.PromotionStrategy,
+			Order:             env.Spec.Order,
+			GitURL:            env.Spec.Source.URL,
+		})
+		if manifest.GitServer == nil && env.Spec.Source.URL != "" {
+			gitInfo, err := gits.ParseGitURL(env.Spec.Source.URL)
+			if err == nil {
+				manifest.GitServer = &InstallManifestGitServer{
+					URL:   gitInfo.HostURL(),
+					Owner: gitInfo.Organisation,
+				}
+			}
+		}
+	}
+
+	authConfigSvc, err := o.CreateGitAuthConfigService()
+	if err == nil {
+		authConfig := authConfigSvc.Config()
+		if authConfig.PipeLineServer != "" {
+			if manifest.GitServer == nil {
+				manifest.GitServer = &InstallManifestGitServer{}
+			}
+			manifest.GitServer.URL = authConfig.PipeLineServer
+			manifest.GitServer.Username = authConfig.PipeLineUsername
+		}
+	}
+
+	statusMap, err := o.Helm().StatusReleases(ns)
+	if err != nil {
+		log.Warnf("Failed to find Helm installs: %s\n", err)
+	}
+	for _, name := range util.SortedMapKeys(kube.AddonCharts) {
+		if statusMap[name] != "" {
+			manifest.Addons = append(manifest.Addons, name)
+		}
+	}
+
+	storageClasses, err := kubeClient.StorageV1().StorageClasses().List(metav1.ListOptions{})
+	if err == nil {
+		for _, sc := range storageClasses.Items {
+			if sc.Annotations[defaultStorageClassAnnotation] == "true" {
+				manifest.Storage = &InstallManifestStorage{DefaultStorageClass: sc.Name}
+				break
+			}
+		}
+	}
+	return manifest, nil
+}
+
+// platformChartVersion returns the version of the platform chart in the output of 'helm list'
+func platformChartVersion(helmList string) string {
+	prefix := "jenkins-x-platform-"
+	for _, field := range strings.Fields(helmList) {
+		if strings.HasPrefix(field, prefix) {
+			return strings.TrimPrefix(field, prefix)
+		}
+	}
+	return ""
+}
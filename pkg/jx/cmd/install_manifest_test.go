@@ -0,0 +1,149 @@
+package cmd_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadInstallManifest(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-install-manifest")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "jx-install.yaml")
+	err = ioutil.WriteFile(fileName, []byte(`provider: gke
+domain: myorg.io
+prow: true
+gitServer:
+  url: https://github.com
+  owner: myorg
+environments:
+- name: staging
+  order: 100
+- name: production
+  promotionStrategy: Manual
+  order: 200
+addons:
+- anchore
+teamSettings:
+  promotionEngine: Prow
+storage:
+  defaultStorageClass: standard
+helmValues:
+  expose:
+    config:
+      http: "false"
+`), 0644)
+	assert.NoError(t, err)
+
+	manifest, err := cmd.LoadInstallManifest(fileName)
+	assert.NoError(t, err)
+	assert.Equal(t, "gke", manifest.Provider)
+	assert.True(t, manifest.Prow)
+	assert.Equal(t, "myorg", manifest.GitServer.Owner)
+	assert.Len(t, manifest.Environments, 2)
+	assert.Equal(t, v1.PromotionStrategyTypeManual, manifest.Environments[1].PromotionStrategy)
+	assert.Equal(t, []string{"anchore"}, manifest.Addons)
+	assert.Equal(t, v1.PromotionEngineProw, manifest.TeamSettings.PromotionEngine)
+	assert.Equal(t, "standard", manifest.Storage.DefaultStorageClass)
+	assert.NotNil(t, manifest.HelmValues["expose"])
+
+	exported := filepath.Join(dir, "exported.yaml")
+	err = manifest.Save(exported)
+	assert.NoError(t, err)
+	loaded, err := cmd.LoadInstallManifest(exported)
+	assert.NoError(t, err)
+	assert.Equal(t, manifest, loaded)
+
+	err = ioutil.WriteFile(fileName, []byte(`environments:
+- name: staging
+  promotionStrategy: Sometimes
+`), 0644)
+	assert.NoError(t, err)
+	_, err = cmd.LoadInstallManifest(fileName)
+	assert.Error(t, err)
+}
+
+func TestMergeTeamSettings(t *testing.T) {
+	t.Parallel()
+	current := &v1.TeamSettings{
+		KubeProvider:    "gke",
+		PromotionEngine: v1.PromotionEngineJenkins,
+		BuildPackRef:    "2.1",
+	}
+	merged, err := cmd.MergeTeamSettings(current, &v1.TeamSettings{
+		PromotionEngine: v1.PromotionEngineProw,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &v1.TeamSettings{
+		KubeProvider:    "gke",
+		PromotionEngine: v1.PromotionEngineProw,
+		BuildPackRef:    "2.1",
+	}, merged)
+}
+
+func TestDiffInstallManifest(t *testing.T) {
+	t.Parallel()
+	desired := &cmd.InstallManifest{
+		Provider: "gke",
+		Domain:   "myorg.io",
+		Environments: []cmd.InstallManifestEnvironment{
+			{
+				Name:              "staging",
+				PromotionStrategy: v1.PromotionStrategyTypeAutomatic,
+			},
+			{
+				Name: "production",
+			},
+		},
+		Addons: []string{"anchore"},
+		TeamSettings: &v1.TeamSettings{
+			PromotionEngine: v1.PromotionEngineProw,
+		},
+	}
+	live := &cmd.InstallManifest{
+		Provider: "gke",
+		Domain:   "1.2.3.4.nip.io",
+		Environments: []cmd.InstallManifestEnvironment{
+			{
+				Name:              "staging",
+				PromotionStrategy: v1.PromotionStrategyTypeManual,
+			},
+			{
+				Name: "qa",
+			},
+		},
+		Addons: []string{"gitea"},
+		TeamSettings: &v1.TeamSettings{
+			KubeProvider:    "gke",
+			PromotionEngine: v1.PromotionEngineJenkins,
+		},
+	}
+
+	drifts, err := cmd.DiffInstallManifest(desired, live)
+	assert.NoError(t, err)
+	changes := map[string]string{}
+	for _, drift := range drifts {
+		changes[drift.Resource+" "+drift.Name] = drift.Change
+	}
+	assert.Equal(t, map[string]string{
+		"install domain":                        cmd.InstallDriftChanged,
+		"environment staging promotionStrategy": cmd.InstallDriftChanged,
+		"environment production":                cmd.InstallDriftMissing,
+		"environment qa":                        cmd.InstallDriftUnexpected,
+		"addon anchore":                         cmd.InstallDriftMissing,
+		"addon gitea":                           cmd.InstallDriftUnexpected,
+		"teamsettings dev":                      cmd.InstallDriftChanged,
+	}, changes)
+
+	drifts, err = cmd.DiffInstallManifest(live, live)
+	assert.NoError(t, err)
+	assert.Empty(t, drifts)
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx/pkg/config"
 	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
@@ -29,6 +30,11 @@ var (
 	previewLong = templates.LongDesc(`
 		Creates or updates a Preview Environment for the given Pull Request or Branch.
 
+		The 'previewEnvironments' section of the jenkins-x.yml of the project can specify dependencies which are installed
+		into the preview namespace before the preview, such as a database chart or other apps pinned to the versions
+		running in an environment like staging, and seed Jobs which populate them with data before the preview URL is
+		posted. The dependencies are deleted together with the preview environment.
+
 		For more documentation on Preview Environments see: [https://jenkins-x.io/about/features/#preview-environments](https://jenkins-x.io/about/features/#preview-environments)
 
 `)
@@ -412,6 +418,20 @@ func (o *PreviewOptions) Run() error {
 		return err
 	}
 
+	err = o.applyPreviewLifecycle(kubeClient, jxClient, ns)
+	if err != nil {
+		return err
+	}
+
+	previewConfig, err := o.previewEnvironmentConfig()
+	if err != nil {
+		return err
+	}
+	err = o.installPreviewDependencies(kubeClient, jxClient, ns, previewConfig)
+	if err != nil {
+		return err
+	}
+
 	if o.ReleaseName == "" {
 		o.ReleaseName = o.Namespace
 	}
@@ -467,6 +487,20 @@ func (o *PreviewOptions) Run() error {
 		return err
 	}
 
+	// helm does not reset the replicas of a preview which was scaled down to zero while idle
+	err = kube.WakeDeployments(kubeClient, o.Namespace)
+	if err != nil {
+		return err
+	}
+
+	pipeline := o.getJobName()
+	build := o.getBuildNumber()
+
+	err = o.runPreviewSeedJobs(kubeClient, previewConfig, pipeline, build)
+	if err != nil {
+		return err
+	}
+
 	url := ""
 	appNames := []string{o.Application, o.ReleaseName, o.Namespace + "-preview", o.ReleaseName + "-" + o.Application}
 	for _, n := range appNames {
@@ -486,9 +520,6 @@ func (o *PreviewOptions) Run() error {
 		comment += fmt.Sprintf(" [here](%s) ", url)
 	}
 
-	pipeline := o.getJobName()
-	build := o.getBuildNumber()
-
 	if url != "" || o.PullRequestURL != "" {
 		if pipeline != "" && build != "" {
 			name := kube.ToValidName(pipeline + "-" + build)
@@ -563,6 +594,62 @@ func (o *PreviewOptions) Run() error {
 	return o.RunPostPreviewSteps(kubeClient, o.Namespace, url, pipeline, build)
 }
 
+// applyPreviewLifecycle marks the preview as updated by this push, applies the resource quota of the team to its
+// namespace and deletes the least recently updated previews of the repository beyond its maximum number of previews
+func (o *PreviewOptions) applyPreviewLifecycle(kubeClient kubernetes.Interface, jxClient versioned.Interface, ns string) error {
+	environmentsResource := jxClient.JenkinsV1().Environments(ns)
+	env, err := environmentsResource.Get(o.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if env.Annotations == nil {
+		env.Annotations = map[string]string{}
+	}
+	kube.SetPreviewAnnotationTime(env, kube.AnnotationPreviewLastUpdated, time.Now())
+	delete(env.Annotations, kube.AnnotationPreviewScaledDown)
+	delete(env.Annotations, kube.AnnotationPreviewMaximumInstances)
+	previewConfig, err := o.previewEnvironmentConfig()
+	if err != nil {
+		return err
+	}
+	if previewConfig.MaximumInstances > 0 {
+		env.Annotations[kube.AnnotationPreviewMaximumInstances] = strconv.Itoa(previewConfig.MaximumInstances)
+	}
+	_, err = environmentsResource.Update(env)
+	if err != nil {
+		return fmt.Errorf("Failed to update Environment %s due to %s", o.Name, err)
+	}
+
+	teamSettings, err := o.TeamSettings()
+	if err != nil {
+		return err
+	}
+	policy := teamSettings.PreviewLifecycle
+	err = kube.ApplyPreviewResourceQuota(kubeClient, o.Namespace, policy)
+	if err != nil {
+		return err
+	}
+
+	envs, err := environmentsResource.List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	lifecycle := &kube.PreviewLifecycle{Now: time.Now()}
+	if policy != nil {
+		lifecycle.MaximumInstances = policy.MaximumInstances
+	}
+	for _, decision := range lifecycle.Decide(envs.Items) {
+		if decision.Action != kube.PreviewActionDelete || decision.Environment.Name == o.Name {
+			continue
+		}
+		err = o.reclaimPreview(kubeClient, jxClient, ns, decision, policy)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // RunPostPreviewSteps lets run any post-preview steps that are configured for all apps in a team
 func (o *PreviewOptions) RunPostPreviewSteps(kubeClient kubernetes.Interface, ns string, url string, pipeline string, build string) error {
 	teamSettings, err := o.TeamSettings()
@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const releasesChartRepository = "releases"
+
+// previewEnvironmentConfig loads the preview environment configuration from the jenkins-x.yml of the project
+func (o *PreviewOptions) previewEnvironmentConfig() (*config.PreviewEnvironmentConfig, error) {
+	projectConfig, _, err := config.LoadProjectConfig(o.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if projectConfig.PreviewEnvironments == nil {
+		return &config.PreviewEnvironmentConfig{}, nil
+	}
+	return projectConfig.PreviewEnvironments, nil
+}
+
+// installPreviewDependencies installs the dependencies of the preview into its namespace and records their releases
+// on the preview environment as they are installed so that they are deleted with it
+func (o *PreviewOptions) installPreviewDependencies(kubeClient kubernetes.Interface, jxClient versioned.Interface, devNs string, previewConfig *config.PreviewEnvironmentConfig) error {
+	environments := jxClient.JenkinsV1().Environments(devNs)
+	env, err := environments.Get(o.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	previousReleases := kube.PreviewDependencyReleases(env)
+	recorded := append([]string{}, previousReleases...)
+	releases := []string{}
+	for _, dependency := range previewConfig.Dependencies {
+		if dependency == nil {
+			continue
+		}
+		release, err := o.installPreviewDependency(kubeClient, jxClient, devNs, dependency)
+		if err != nil {
+			return errors.Wrapf(err, "failed to install preview dependency %s", dependency.Name)
+		}
+		releases = append(releases, release)
+		if util.StringArrayIndex(recorded, release) < 0 {
+			recorded = append(recorded, release)
+			env, err = o.recordPreviewDependencyReleases(jxClient, devNs, env, recorded)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// lets delete the releases of dependencies which have been removed from the jenkins-x.yml
+	for _, release := range previousReleases {
+		if util.StringArrayIndex(releases, release) < 0 {
+			err = o.Helm().DeleteRelease(o.Namespace, release, true)
+			if err != nil {
+				log.Warnf("Failed to delete the release %s of a removed preview dependency: %s\n", release, err)
+			}
+		}
+	}
+	if strings.Join(recorded, ",") == strings.Join(releases, ",") {
+		return nil
+	}
+	_, err = o.recordPreviewDependencyReleases(jxClient, devNs, env, releases)
+	return err
+}
+
+// recordPreviewDependencyReleases records the releases of the dependencies on the preview environment
+func (o *PreviewOptions) recordPreviewDependencyReleases(jxClient versioned.Interface, devNs string, env *v1.Environment, releases []string) (*v1.Environment, error) {
+	kube.SetPreviewDependencyReleases(env, releases)
+	answer, err := jxClient.JenkinsV1().Environments(devNs).Update(env)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update Environment %s due to %s", env.Name, err)
+	}
+	return answer, nil
+}
+
+func (o *PreviewOptions) installPreviewDependency(kubeClient kubernetes.Interface, jxClient versioned.Interface, devNs string, dependency *config.PreviewDependency) (string, error) {
+	if dependency.Name == "" {
+		return "", util.MissingOption("name")
+	}
+	version := dependency.Version
+	if dependency.Environment != "" {
+		env, err := jxClient.JenkinsV1().Environments(devNs).Get(dependency.Environment, metav1.GetOptions{})
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to find environment %s", dependency.Environment)
+		}
+		version, err = kube.GetAppVersion(kubeClient, env.Spec.Namespace, dependency.Name)
+		if err != nil {
+			return "", err
+		}
+		if version == "" {
+			return "", fmt.Errorf("no version of %s found in environment %s", dependency.Name, dependency.Environment)
+		}
+	}
+
+	chart := dependency.Chart
+	if chart == "" {
+		chart = dependency.Name
+	}
+	repoURL := dependency.Repository
+	repoName := ""
+	if repoURL == "" && !strings.Contains(chart, "/") {
+		repoURL = o.releaseChartMuseumUrl()
+		repoName = releasesChartRepository
+	} else if repoURL != "" {
+		repoName = kube.ToValidName(dependency.Name)
+		paths := strings.SplitN(chart, "/", 2)
+		if len(paths) == 2 {
+			repoName = paths[0]
+			chart = paths[1]
+		}
+	}
+	if repoName != "" {
+		err := o.addHelmRepoIfMissing(repoURL, repoName)
+		if err != nil {
+			return "", err
+		}
+		chart = repoName + "/" + chart
+	}
+
+	valueFiles := []string{}
+	for _, file := range dependency.ValueFiles {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(o.Dir, file)
+		}
+		valueFiles = append(valueFiles, file)
+	}
+
+	release := kube.PreviewDependencyReleaseName(o.Namespace, dependency.Name)
+	var versionPtr *string
+	if version != "" {
+		versionPtr = &version
+		log.Infof("Installing preview dependency %s version %s as release %s\n", util.ColorInfo(chart), util.ColorInfo(version), util.ColorInfo(release))
+	} else {
+		log.Infof("Installing preview dependency %s as release %s\n", util.ColorInfo(chart), util.ColorInfo(release))
+	}
+	timeout, err := strconv.Atoi(defaultInstallTimeout)
+	if err != nil {
+		return "", err
+	}
+	err = o.Helm().UpgradeChart(chart, release, o.Namespace, versionPtr, true, &timeout, false, true, dependency.Values, valueFiles)
+	if err != nil {
+		return "", err
+	}
+	return release, nil
+}
+
+// runPreviewSeedJobs runs the seed Jobs of the preview in its namespace one at a time waiting for each to succeed
+func (o *PreviewOptions) runPreviewSeedJobs(kubeClient kubernetes.Interface, previewConfig *config.PreviewEnvironmentConfig, pipeline string, build string) error {
+	envVars := []corev1.EnvVar{
+		{Name: "JX_PREVIEW_NAMESPACE", Value: o.Namespace},
+		{Name: "JX_PIPELINE", Value: pipeline},
+		{Name: "JX_BUILD", Value: build},
+	}
+	for _, dependency := range previewConfig.Dependencies {
+		if dependency != nil && dependency.Name != "" {
+			envVars = append(envVars, corev1.EnvVar{
+				Name:  "JX_DEPENDENCY_" + strings.ToUpper(strings.Replace(kube.ToValidName(dependency.Name), "-", "_", -1)),
+				Value: kube.PreviewDependencyReleaseName(o.Namespace, dependency.Name),
+			})
+		}
+	}
+	jobs := kubeClient.BatchV1().Jobs(o.Namespace)
+	for _, seed := range previewConfig.SeedJobs {
+		if seed == nil {
+			continue
+		}
+		if seed.Image == "" {
+			return fmt.Errorf("no image specified for the preview seed Job %s", seed.Name)
+		}
+		timeout := o.PostPreviewJobTimeoutDuration
+		if seed.Timeout != "" {
+			var err error
+			timeout, err = time.ParseDuration(seed.Timeout)
+			if err != nil {
+				return errors.Wrapf(err, "invalid timeout %s of preview seed Job %s", seed.Timeout, seed.Name)
+			}
+		}
+		job := createPreviewSeedJob(seed, envVars)
+		// lets delete the Job of a previous push to the pull request
+		_, err := jobs.Get(job.Name, metav1.GetOptions{})
+		if err == nil {
+			err = kube.DeleteJob(kubeClient, o.Namespace, job.Name)
+			if err != nil {
+				return err
+			}
+			err = o.retryUntilTrueOrTimeout(time.Minute, time.Second, func() (bool, error) {
+				_, err := jobs.Get(job.Name, metav1.GetOptions{})
+				if err == nil {
+					return false, nil
+				}
+				if apierrors.IsNotFound(err) {
+					return true, nil
+				}
+				return false, err
+			})
+			if err != nil {
+				return errors.Wrapf(err, "the previous preview seed Job %s was not deleted", job.Name)
+			}
+		} else if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		log.Infof("Running preview seed Job %s in namespace %s\n", util.ColorInfo(job.Name), util.ColorInfo(o.Namespace))
+		_, err = jobs.Create(job)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create preview seed Job %s", job.Name)
+		}
+		err = kube.WaitForJobToTerminate(kubeClient, o.Namespace, job.Name, timeout)
+		if err != nil {
+			return errors.Wrapf(err, "preview seed Job %s did not complete", job.Name)
+		}
+		job, err = jobs.Get(job.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if !kube.IsJobSucceeded(job) {
+			return fmt.Errorf("preview seed Job %s in namespace %s failed", job.Name, o.Namespace)
+		}
+	}
+	return nil
+}
+
+// createPreviewSeedJob creates the Job of the seed with the given environment variables
+func createPreviewSeedJob(seed *config.PreviewSeedJob, envVars []corev1.EnvVar) *batchv1.Job {
+	backoffLimit := int32(0)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: kube.ToValidName("seed-" + seed.Name),
+			Labels: map[string]string{
+				kube.LabelJobKind: kube.ValueJobKindPreviewSeed,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    "seed",
+							Image:   seed.Image,
+							Command: seed.Command,
+							Args:    seed.Args,
+							Env:     append(append([]corev1.EnvVar{}, envVars...), seed.Env...),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// deletePreviewDependencies deletes the helm releases of the dependencies of the preview environment
+func (o *CommonOptions) deletePreviewDependencies(env *v1.Environment) {
+	for _, release := range kube.PreviewDependencyReleases(env) {
+		err := o.Helm().DeleteRelease(env.Spec.Namespace, release, true)
+		if err != nil {
+			log.Warnf("Failed to delete the release %s of a dependency of preview environment %s: %s\n", release, env.Name, err)
+			continue
+		}
+		log.Infof("Deleted preview dependency %s\n", util.ColorInfo(release))
+	}
+}
@@ -12,8 +12,11 @@ import (
 	"github.com/blang/semver"
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	typev1 "github.com/jenkins-x/jx/pkg/client/clientset/versioned/typed/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/cve"
 	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/issues"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
@@ -41,25 +44,27 @@ var (
 type PromoteOptions struct {
 	CommonOptions
 
-	Namespace           string
-	Environment         string
-	Application         string
-	Pipeline            string
-	Build               string
-	Version             string
-	ReleaseName         string
-	LocalHelmRepoName   string
-	HelmRepositoryURL   string
-	NoHelmUpdate        bool
-	AllAutomatic        bool
-	NoMergePullRequest  bool
-	NoPoll              bool
-	NoWaitAfterMerge    bool
-	IgnoreLocalFiles    bool
-	Timeout             string
-	PullRequestPollTime string
-	Filter              string
-	Alias               string
+	Namespace             string
+	Environment           string
+	Application           string
+	Pipeline              string
+	Build                 string
+	Version               string
+	ReleaseName           string
+	LocalHelmRepoName     string
+	HelmRepositoryURL     string
+	NoHelmUpdate          bool
+	AllAutomatic          bool
+	NoMergePullRequest    bool
+	NoPoll                bool
+	NoWaitAfterMerge      bool
+	IgnoreLocalFiles      bool
+	NoRollout             bool
+	IgnoreVulnerabilities bool
+	Timeout               string
+	PullRequestPollTime   string
+	Filter                string
+	Alias                 string
 
 	// allow git to be configured externally before a PR is created
 	ConfigureGitCallback ConfigureGitFolderFn
@@ -82,6 +87,8 @@ type ReleaseInfo struct {
 	FullAppName     string
 	Version         string
 	PullRequestInfo *ReleasePullRequestInfo
+	// RolloutWeight is the percentage of traffic sent to the new version by a rollout via Pull Requests
+	RolloutWeight int32
 }
 
 type ReleasePullRequestInfo struct {
@@ -168,6 +175,8 @@ func (options *PromoteOptions) addPromoteOptions(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&options.NoPoll, "no-poll", "", false, "Disables polling for Pull Request or Pipeline status")
 	cmd.Flags().BoolVarP(&options.NoWaitAfterMerge, "no-wait", "", false, "Disables waiting for completing promotion after the Pull request is merged")
 	cmd.Flags().BoolVarP(&options.IgnoreLocalFiles, "ignore-local-file", "", false, "Ignores the local file system when deducing the Git repository")
+	cmd.Flags().BoolVarP(&options.NoRollout, "no-rollout", "", false, "Disables the canary or blue/green rollout of the Environment and upgrades the application in a single step")
+	cmd.Flags().BoolVarP(&options.IgnoreVulnerabilities, "ignore-vulnerabilities", "", false, "Promotes even if a 'jx step scan image' of the build found vulnerabilities")
 }
 
 // Run implements this command
@@ -385,6 +394,10 @@ func (o *PromoteOptions) Promote(targetNS string, env *v1.Environment, warnIfAut
 	}
 
 	promoteKey := o.createPromoteKey(env)
+	err := o.verifyVulnerabilityScans(promoteKey)
+	if err != nil {
+		return releaseInfo, err
+	}
 	if env != nil {
 		source := &env.Spec.Source
 		if source.URL != "" && env.Spec.Kind.IsPermanent() {
@@ -399,6 +412,11 @@ func (o *PromoteOptions) Promote(targetNS string, env *v1.Environment, warnIfAut
 					if version != "" && a.Spec.Version == "" {
 						a.Spec.Version = version
 					}
+					if releaseInfo.RolloutWeight > 0 {
+						// the rest of the rollout continues once the Pull Request is merged
+						r := kube.GetOrCreatePromoteRolloutStep(ps, releaseInfo.RolloutWeight)
+						kube.UpdatePromoteRolloutStep(r, v1.ActivityStatusTypePending, "")
+					}
 					return nil
 				}
 				err = promoteKey.OnPromotePullRequest(o.Activities, startPromotePR)
@@ -411,7 +429,7 @@ func (o *PromoteOptions) Promote(targetNS string, env *v1.Environment, warnIfAut
 			return releaseInfo, err
 		}
 	}
-	err := o.verifyHelmConfigured()
+	err = o.verifyHelmConfigured()
 	if err != nil {
 		return releaseInfo, err
 	}
@@ -434,7 +452,11 @@ func (o *PromoteOptions) Promote(targetNS string, env *v1.Environment, warnIfAut
 	}
 	promoteKey.OnPromoteUpdate(o.Activities, startPromote)
 
-	err = o.Helm().UpgradeChart(fullAppName, releaseName, targetNS, &version, true, nil, false, true, nil, nil)
+	if env != nil && env.Spec.Rollout != nil && !o.NoRollout {
+		err = o.PromoteRollout(targetNS, env, releaseInfo, promoteKey)
+	} else {
+		err = o.Helm().UpgradeChart(fullAppName, releaseName, targetNS, &version, true, nil, false, true, nil, nil)
+	}
 	if err == nil {
 		err = o.commentOnIssues(targetNS, env, promoteKey)
 		if err != nil {
@@ -468,15 +490,26 @@ func (o *PromoteOptions) PromoteViaPullRequest(env *v1.Environment, releaseInfo
 				return err
 			}
 		}
+		releaseInfo.RolloutWeight = 0
+		if env.Spec.Rollout != nil && !o.NoRollout && requirements.FindDependency(app, o.Alias) != nil {
+			// lets deploy the new version next to the current version so that traffic can be shifted to it
+			requirements.SetAliasVersion(app, version, o.HelmRepositoryURL, kube.CanaryName(app))
+			releaseInfo.Version = version
+			releaseInfo.RolloutWeight = kube.RolloutWeights(env.Spec.Rollout)[0]
+			return nil
+		}
 		requirements.SetAppVersion(app, version, o.HelmRepositoryURL, o.Alias)
 		return nil
 	}
+	modifyChartFilesFn := func(chartDir string) error {
+		return o.writeRolloutChartFiles(chartDir, env, releaseInfo.RolloutWeight)
+	}
 	if o.FakePullRequests != nil {
 		info, err := o.FakePullRequests(env, modifyRequirementsFn, branchNameText, title, message, releaseInfo.PullRequestInfo)
 		releaseInfo.PullRequestInfo = info
 		return err
 	} else {
-		info, err := o.createEnvironmentPullRequest(env, modifyRequirementsFn, branchNameText, title, message, releaseInfo.PullRequestInfo, o.ConfigureGitCallback)
+		info, err := o.createEnvironmentPullRequest(env, modifyRequirementsFn, modifyChartFilesFn, branchNameText, title, message, releaseInfo.PullRequestInfo, o.ConfigureGitCallback)
 		releaseInfo.PullRequestInfo = info
 		return err
 	}
@@ -593,7 +626,8 @@ func (o *PromoteOptions) waitForGitOpsPullRequest(ns string, env *v1.Environment
 							}
 							promoteKey.OnPromotePullRequest(o.Activities, mergedPR)
 
-							if o.NoWaitAfterMerge {
+							// a rollout has to wait to shift the rest of the traffic to the new version
+							if o.NoWaitAfterMerge && releaseInfo.RolloutWeight == 0 {
 								log.Infof("Pull requests are merged, No wait on promotion to complete")
 								return err
 							}
@@ -659,6 +693,13 @@ func (o *PromoteOptions) waitForGitOpsPullRequest(ns string, env *v1.Environment
 								}
 								if succeeded {
 									log.Infoln("Merge status checks all passed so the promotion worked!")
+									if releaseInfo.RolloutWeight > 0 {
+										err = o.continueRolloutViaPullRequests(ns, env, releaseInfo, end, promoteKey)
+										if err != nil {
+											promoteKey.OnPromoteUpdate(o.Activities, kube.FailedPromotionUpdate)
+											return err
+										}
+									}
 									err = o.commentOnIssues(ns, env, promoteKey)
 									if err == nil {
 										err = promoteKey.OnPromoteUpdate(o.Activities, kube.CompletePromotionUpdate)
@@ -768,6 +809,23 @@ func (o *PromoteOptions) verifyHelmConfigured() error {
 	return o.registerLocalHelmRepo(o.LocalHelmRepoName, ns)
 }
 
+// verifyVulnerabilityScans returns an error if the image scans recorded on the PipelineActivity of the build did not pass
+func (o *PromoteOptions) verifyVulnerabilityScans(promoteKey *kube.PromoteStepActivityKey) error {
+	if o.IgnoreVulnerabilities || o.Activities == nil || promoteKey.Build == "" {
+		return nil
+	}
+	activity, err := o.Activities.Get(promoteKey.Name, metav1.GetOptions{})
+	if err != nil {
+		// the build has not recorded any scans
+		return nil
+	}
+	failed := cve.FailedScans(activity)
+	if len(failed) > 0 {
+		return fmt.Errorf("cannot promote as the vulnerability scans of images %s found vulnerabilities. Use --ignore-vulnerabilities to promote anyway", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
 func (o *PromoteOptions) createPromoteKey(env *v1.Environment) *kube.PromoteStepActivityKey {
 	pipeline := o.Pipeline
 	build := o.Build
@@ -1006,18 +1064,26 @@ func (o *PromoteOptions) commentOnIssues(targetNS string, environment *v1.Enviro
 		log.Warnf("No GitInfo discovered so cannot comment on issues that they are now in %s\n", envName)
 		return nil
 	}
-	authConfigSvc, err := o.CreateGitAuthConfigService()
-	if err != nil {
-		return err
-	}
-	gitKind, err := o.GitServerKind(gitInfo)
+	// lets comment on the issue tracker of the project if it does not use the issues of the git provider
+	tracker, trackerConfig, err := o.createRepositoryIssueProvider(gitInfo, "")
 	if err != nil {
-		return err
+		log.Warnf("Failed to create the issue tracker configured for the project: %s\n", err)
 	}
+	var provider gits.GitProvider
+	if tracker == nil {
+		authConfigSvc, err := o.CreateGitAuthConfigService()
+		if err != nil {
+			return err
+		}
+		gitKind, err := o.GitServerKind(gitInfo)
+		if err != nil {
+			return err
+		}
 
-	provider, err := gitInfo.PickOrCreateProvider(authConfigSvc, "user name to comment on issues", o.BatchMode, gitKind, o.Git(), o.In, o.Out, o.Err)
-	if err != nil {
-		return err
+		provider, err = gitInfo.PickOrCreateProvider(authConfigSvc, "user name to comment on issues", o.BatchMode, gitKind, o.Git(), o.In, o.Out, o.Err)
+		if err != nil {
+			return err
+		}
 	}
 
 	releaseName := kube.ToValidNameWithDots(app + "-" + version)
@@ -1072,19 +1138,24 @@ func (o *PromoteOptions) commentOnIssues(targetNS string, environment *v1.Enviro
 	release, err := jxClient.JenkinsV1().Releases(ens).Get(releaseName, metav1.GetOptions{})
 	if err == nil && release != nil {
 		o.releaseResource = release
-		issues := release.Spec.Issues
+		releaseIssues := release.Spec.Issues
 
 		versionMessage := version
 		if release.Spec.ReleaseNotesURL != "" {
 			versionMessage = "[" + version + "](" + release.Spec.ReleaseNotesURL + ")"
 		}
-		for _, issue := range issues {
+		for _, issue := range releaseIssues {
 			if issue.IsClosed() {
 				log.Infof("Commenting that issue %s is now in %s\n", util.ColorInfo(issue.URL), util.ColorInfo(envName))
 
 				comment := fmt.Sprintf(":white_check_mark: the fix for this issue is now deployed to **%s** in version %s %s", envName, versionMessage, available)
 				id := issue.ID
-				if id != "" {
+				if id != "" && tracker != nil {
+					err = tracker.CreateIssueComment(id, comment)
+					if err != nil {
+						log.Warnf("Failed to add comment to issue %s: %s", issue.URL, err)
+					}
+				} else if id != "" {
 					number, err := strconv.Atoi(id)
 					if err != nil {
 						log.Warnf("Could not parse issue id %s for URL %s\n", id, issue.URL)
@@ -1099,10 +1170,40 @@ func (o *PromoteOptions) commentOnIssues(targetNS string, environment *v1.Enviro
 				}
 			}
 		}
+		if tracker != nil && trackerConfig != nil {
+			transition := trackerConfig.EnvironmentTransition(environment.Name, envName)
+			if transition != nil {
+				o.transitionIssues(tracker, transition, releaseIssues, version, envName)
+			}
+		}
 	}
 	return nil
 }
 
+// transitionIssues moves the issues of a release to the status configured for the environment and sets their fix version
+func (o *PromoteOptions) transitionIssues(tracker issues.IssueProvider, transition *config.IssueTransitionConfig, releaseIssues []v1.IssueSummary, version string, envName string) {
+	for _, issue := range releaseIssues {
+		id := issue.ID
+		if id == "" {
+			continue
+		}
+		if transition.Status != "" {
+			log.Infof("Moving issue %s to status %s as it is now in %s\n", util.ColorInfo(id), util.ColorInfo(transition.Status), util.ColorInfo(envName))
+			err := tracker.TransitionIssue(id, transition.Status)
+			if err != nil {
+				log.Warnf("Failed to move issue %s to status %s: %s\n", issue.URL, transition.Status, err)
+			}
+		}
+		if transition.FixVersion {
+			log.Infof("Setting the fix version of issue %s to %s\n", util.ColorInfo(id), util.ColorInfo(version))
+			err := tracker.SetIssueFixVersion(id, version)
+			if err != nil {
+				log.Warnf("Failed to set the fix version of issue %s to %s: %s\n", issue.URL, version, err)
+			}
+		}
+	}
+}
+
 func (o *PromoteOptions) SearchForChart(filter string) (string, error) {
 	answer := ""
 	charts, err := o.Helm().SearchCharts(filter)
@@ -0,0 +1,581 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/prometheus"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultRolloutStepDuration            = time.Minute
+	defaultRolloutTimeout                 = time.Hour
+	defaultRolloutPullRequestPollDuration = 20 * time.Second
+)
+
+// PromoteRollout deploys the new version of the application next to the current version and then progressively shifts
+// traffic to it using the rollout strategy of the environment. If the new version becomes unhealthy the rollout is
+// aborted and all traffic is sent back to the current version
+func (o *PromoteOptions) PromoteRollout(targetNS string, env *v1.Environment, releaseInfo *ReleaseInfo, promoteKey *kube.PromoteStepActivityKey) error {
+	strategy := env.Spec.Rollout
+	kind := strategy.Kind
+	if kind == "" {
+		kind = v1.RolloutKindTypeCanary
+	}
+	stepDuration, err := rolloutStepDuration(env)
+	if err != nil {
+		return err
+	}
+
+	app := o.Application
+	version := releaseInfo.Version
+	canaryRelease := kube.CanaryName(releaseInfo.ReleaseName)
+	canaryService := kube.CanaryName(app)
+	info := util.ColorInfo
+
+	log.Infof("Starting %s rollout of app %s to namespace %s using release %s\n", info(string(kind)), info(app), info(targetNS), info(canaryRelease))
+	values := []string{"service.name=" + canaryService}
+	err = o.Helm().UpgradeChart(releaseInfo.FullAppName, canaryRelease, targetNS, &version, true, nil, false, true, values, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to install release %s", canaryRelease)
+	}
+
+	for _, weight := range kube.RolloutWeights(strategy) {
+		o.updateRolloutStep(promoteKey, weight, v1.ActivityStatusTypeRunning, "")
+
+		err = o.setRolloutWeight(strategy, targetNS, app, canaryService, weight)
+		if err != nil {
+			o.updateRolloutStep(promoteKey, weight, v1.ActivityStatusTypeFailed, err.Error())
+			return o.abortRollout(strategy, targetNS, app, canaryRelease, canaryService, err)
+		}
+		log.Infof("Sending %s%% of traffic to version %s of app %s, checking its health in %s\n", info(strconv.Itoa(int(weight))), info(version), info(app), stepDuration.String())
+		time.Sleep(stepDuration)
+
+		err = o.checkRolloutHealth(strategy, targetNS, "release="+canaryRelease)
+		if err != nil {
+			o.updateRolloutStep(promoteKey, weight, v1.ActivityStatusTypeFailed, err.Error())
+			return o.abortRollout(strategy, targetNS, app, canaryRelease, canaryService, err)
+		}
+		o.updateRolloutStep(promoteKey, weight, v1.ActivityStatusTypeSucceeded, fmt.Sprintf("%d%% of traffic is healthy", weight))
+	}
+
+	// all traffic now goes to the new version so lets upgrade the current release and remove the canary
+	log.Infof("Rollout of app %s succeeded so upgrading release %s\n", info(app), info(releaseInfo.ReleaseName))
+	err = o.Helm().UpgradeChart(releaseInfo.FullAppName, releaseInfo.ReleaseName, targetNS, &version, true, nil, false, true, nil, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to upgrade release %s", releaseInfo.ReleaseName)
+	}
+	err = o.setRolloutWeight(strategy, targetNS, app, canaryService, 0)
+	if err != nil {
+		return err
+	}
+	return o.Helm().DeleteRelease(targetNS, canaryRelease, true)
+}
+
+// rolloutStepDuration returns how long each step of the rollout of the environment waits before checking the health of
+// the new version
+func rolloutStepDuration(env *v1.Environment) (time.Duration, error) {
+	strategy := env.Spec.Rollout
+	if strategy.StepDuration == "" {
+		return defaultRolloutStepDuration, nil
+	}
+	duration, err := time.ParseDuration(strategy.StepDuration)
+	if err != nil {
+		return duration, errors.Wrapf(err, "invalid rollout step duration %s for Environment %s", strategy.StepDuration, env.Name)
+	}
+	return duration, nil
+}
+
+// abortRollout sends all traffic back to the current version and removes the new version
+func (o *PromoteOptions) abortRollout(strategy *v1.RolloutStrategy, ns string, app string, canaryRelease string, canaryService string, cause error) error {
+	log.Warnf("Aborting the rollout of app %s: %s\n", app, cause)
+	err := o.setRolloutWeight(strategy, ns, app, canaryService, 0)
+	if err != nil {
+		log.Warnf("Failed to send all traffic back to app %s: %s\n", app, err)
+	}
+	err = o.Helm().DeleteRelease(ns, canaryRelease, true)
+	if err != nil {
+		log.Warnf("Failed to delete release %s: %s\n", canaryRelease, err)
+	}
+	return errors.Wrapf(cause, "rollout of app %s aborted", app)
+}
+
+// updateRolloutStep updates the rollout step for the given weight on the PipelineActivity
+func (o *PromoteOptions) updateRolloutStep(promoteKey *kube.PromoteStepActivityKey, weight int32, status v1.ActivityStatusType, message string) {
+	err := promoteKey.OnPromoteUpdate(o.Activities, func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromoteUpdateStep) error {
+		r := kube.GetOrCreatePromoteRolloutStep(ps, weight)
+		kube.UpdatePromoteRolloutStep(r, status, message)
+		return nil
+	})
+	if err != nil {
+		log.Warnf("Failed to update PipelineActivity: %s\n", err)
+	}
+}
+
+// setRolloutWeight sends the given percentage of traffic to the canary service using the router of the rollout strategy
+func (o *PromoteOptions) setRolloutWeight(strategy *v1.RolloutStrategy, ns string, app string, canaryService string, weight int32) error {
+	switch strategy.Router {
+	case v1.RolloutRouterTypeIstio:
+		return o.applyRolloutVirtualService(ns, app, canaryService, weight)
+	case v1.RolloutRouterTypeIngress, "":
+		return o.applyRolloutIngress(ns, app, canaryService, weight)
+	default:
+		return fmt.Errorf("unsupported rollout router %s. Supported values are %s", strategy.Router, strings.Join(v1.RolloutRouterTypeValues, ", "))
+	}
+}
+
+func (o *PromoteOptions) applyRolloutVirtualService(ns string, app string, canaryService string, weight int32) error {
+	hosts, err := o.rolloutHosts(ns, app)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(kube.CreateVirtualService(ns, app, hosts, app, canaryService, weight))
+	if err != nil {
+		return err
+	}
+	file, err := ioutil.TempFile("", "jx-virtual-service-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	err = ioutil.WriteFile(file.Name(), data, DefaultWritePermissions)
+	if err != nil {
+		return err
+	}
+	return o.runCommandVerbose("kubectl", "apply", "-n", ns, "-f", file.Name())
+}
+
+// rolloutHosts returns the host name of the service of the app and the hosts of its ingress which are routed by the
+// VirtualService of a rollout
+func (o *PromoteOptions) rolloutHosts(ns string, app string) ([]string, error) {
+	hosts := []string{kube.ServiceHost(app, ns)}
+	kubeClient, _, err := o.KubeClient()
+	if err != nil {
+		return hosts, err
+	}
+	ingress, err := kubeClient.ExtensionsV1beta1().Ingresses(ns).Get(app, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return hosts, nil
+		}
+		return hosts, errors.Wrapf(err, "failed to find ingress %s in namespace %s", app, ns)
+	}
+	return append(hosts, kube.IngressHosts(ingress)...), nil
+}
+
+func (o *PromoteOptions) applyRolloutIngress(ns string, app string, canaryService string, weight int32) error {
+	kubeClient, _, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	ingresses := kubeClient.ExtensionsV1beta1().Ingresses(ns)
+	canaryName := kube.CanaryName(app)
+	if weight == 0 {
+		err = ingresses.Delete(canaryName, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete ingress %s", canaryName)
+		}
+		return nil
+	}
+	stable, err := ingresses.Get(app, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find ingress %s in namespace %s", app, ns)
+	}
+	canary := kube.CreateCanaryIngress(stable, app, canaryService, weight)
+	existing, err := ingresses.Get(canaryName, metav1.GetOptions{})
+	if err == nil {
+		canary.ResourceVersion = existing.ResourceVersion
+		_, err = ingresses.Update(canary)
+	} else {
+		_, err = ingresses.Create(canary)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to save ingress %s", canaryName)
+	}
+	return nil
+}
+
+// checkRolloutHealth returns an error if the pods of the new version matching the selector are unhealthy or the
+// Prometheus query of the rollout strategy exceeds its threshold
+func (o *PromoteOptions) checkRolloutHealth(strategy *v1.RolloutStrategy, ns string, selector string) error {
+	kubeClient, _, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	pods, err := kubeClient.CoreV1().Pods(ns).List(metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find pods matching %s", selector)
+	}
+	err = kube.CheckRolloutPods(pods.Items, strategy.MaxRestarts)
+	if err != nil {
+		return err
+	}
+	if strategy.PrometheusURL == "" || strategy.PrometheusQuery == "" {
+		return nil
+	}
+	threshold, err := strconv.ParseFloat(strategy.PrometheusThreshold, 64)
+	if err != nil {
+		return errors.Wrapf(err, "invalid Prometheus threshold %s", strategy.PrometheusThreshold)
+	}
+	value, err := prometheus.QueryScalar(strategy.PrometheusURL, strategy.PrometheusQuery)
+	if err != nil {
+		return err
+	}
+	if value > threshold {
+		return fmt.Errorf("Prometheus query %s returned %v which is more than the threshold of %v", strategy.PrometheusQuery, value, threshold)
+	}
+	return nil
+}
+
+// continueRolloutViaPullRequests shifts the traffic of a rollout in a GitOps environment to the new version one Pull
+// Request at a time, checking the health of the new version after each step. Once all traffic is healthy the current
+// version is upgraded to the new version. If the new version becomes unhealthy all traffic is sent back to the current
+// version
+func (o *PromoteOptions) continueRolloutViaPullRequests(ns string, env *v1.Environment, releaseInfo *ReleaseInfo, end time.Time, promoteKey *kube.PromoteStepActivityKey) error {
+	strategy := env.Spec.Rollout
+	stepDuration, err := rolloutStepDuration(env)
+	if err != nil {
+		return err
+	}
+	app := o.Application
+	version := releaseInfo.Version
+	info := util.ColorInfo
+
+	for _, weight := range kube.RolloutWeights(strategy) {
+		if weight < releaseInfo.RolloutWeight {
+			continue
+		}
+		o.updateRolloutStep(promoteKey, weight, v1.ActivityStatusTypeRunning, "")
+		if weight > releaseInfo.RolloutWeight {
+			err = o.updateRolloutViaPullRequest(env, releaseInfo, weight, false, end)
+			if err != nil {
+				o.updateRolloutStep(promoteKey, weight, v1.ActivityStatusTypeFailed, err.Error())
+				return o.abortRolloutViaPullRequest(env, releaseInfo, end, err)
+			}
+		}
+		log.Infof("Sending %s%% of traffic to version %s of app %s, checking its health in %s\n", info(strconv.Itoa(int(weight))), info(version), info(app), stepDuration.String())
+		time.Sleep(stepDuration)
+
+		selector, err := o.canarySelector(ns, app)
+		if err == nil {
+			err = o.checkRolloutHealth(strategy, ns, selector)
+		}
+		if err != nil {
+			o.updateRolloutStep(promoteKey, weight, v1.ActivityStatusTypeFailed, err.Error())
+			return o.abortRolloutViaPullRequest(env, releaseInfo, end, err)
+		}
+		o.updateRolloutStep(promoteKey, weight, v1.ActivityStatusTypeSucceeded, fmt.Sprintf("%d%% of traffic is healthy", weight))
+	}
+
+	log.Infof("Rollout of app %s succeeded so upgrading it to version %s\n", info(app), info(version))
+	return o.updateRolloutViaPullRequest(env, releaseInfo, 0, true, end)
+}
+
+// abortRolloutViaPullRequest sends all traffic back to the current version and removes the new version from the
+// GitOps environment
+func (o *PromoteOptions) abortRolloutViaPullRequest(env *v1.Environment, releaseInfo *ReleaseInfo, end time.Time, cause error) error {
+	app := o.Application
+	log.Warnf("Aborting the rollout of app %s: %s\n", app, cause)
+	err := o.updateRolloutViaPullRequest(env, releaseInfo, 0, false, end)
+	if err != nil {
+		log.Warnf("Failed to send all traffic back to app %s: %s\n", app, err)
+	}
+	return errors.Wrapf(cause, "rollout of app %s aborted", app)
+}
+
+// updateRolloutViaPullRequest creates a Pull Request on the GitOps repository of the environment which sends the given
+// percentage of traffic to the new version and waits for it to be merged and deployed. A weight of zero removes the new
+// version and if promoted upgrades the current version to it
+func (o *PromoteOptions) updateRolloutViaPullRequest(env *v1.Environment, releaseInfo *ReleaseInfo, weight int32, promoted bool, end time.Time) error {
+	app := o.Application
+	version := releaseInfo.Version
+	canaryName := kube.CanaryName(app)
+
+	branchNameText := fmt.Sprintf("rollout-%s-%s-%d", app, version, weight)
+	title := fmt.Sprintf("%s rollout of %s at %d%%", app, version, weight)
+	message := fmt.Sprintf("Send %d%% of the traffic of %s to version %s", weight, app, version)
+	if promoted {
+		title = app + " to " + version
+		message = fmt.Sprintf("Promote %s to version %s after a successful rollout", app, version)
+	} else if weight == 0 {
+		title = fmt.Sprintf("abort rollout of %s %s", app, version)
+		message = fmt.Sprintf("Send all traffic of %s back to the current version and remove version %s", app, version)
+	}
+
+	modifyRequirementsFn := func(requirements *helm.Requirements) error {
+		if weight > 0 {
+			return nil
+		}
+		requirements.RemoveAlias(canaryName)
+		if promoted {
+			requirements.SetAppVersion(app, version, o.HelmRepositoryURL, o.Alias)
+		}
+		return nil
+	}
+	modifyChartFilesFn := func(chartDir string) error {
+		return o.writeRolloutChartFiles(chartDir, env, weight)
+	}
+	var pullRequestInfo *ReleasePullRequestInfo
+	var err error
+	if o.FakePullRequests != nil {
+		pullRequestInfo, err = o.FakePullRequests(env, modifyRequirementsFn, branchNameText, title, message, nil)
+	} else {
+		pullRequestInfo, err = o.createEnvironmentPullRequest(env, modifyRequirementsFn, modifyChartFilesFn, branchNameText, title, message, nil, o.ConfigureGitCallback)
+	}
+	if err != nil {
+		return err
+	}
+	releaseInfo.RolloutWeight = weight
+	if pullRequestInfo == nil {
+		return nil
+	}
+	return o.waitForRolloutPullRequest(pullRequestInfo, end)
+}
+
+// waitForRolloutPullRequest merges the Pull Request of a rollout step once its checks pass and then waits for the
+// checks of the merge commit which deploy it to the environment
+func (o *PromoteOptions) waitForRolloutPullRequest(pullRequestInfo *ReleasePullRequestInfo, end time.Time) error {
+	pr := pullRequestInfo.PullRequest
+	gitProvider := pullRequestInfo.GitProvider
+	for {
+		err := gitProvider.UpdatePullRequestStatus(pr)
+		if err != nil {
+			log.Warnf("Failed to query the Pull Request status for %s %s\n", pr.URL, err)
+		} else if pr.Merged != nil && *pr.Merged {
+			if pr.MergeCommitSHA != nil {
+				statuses, err := gitProvider.ListCommitStatus(pr.Owner, pr.Repo, *pr.MergeCommitSHA)
+				if err != nil {
+					log.Warnf("Failed to query merge status of repo %s/%s with merge sha %s due to: %s\n", pr.Owner, pr.Repo, *pr.MergeCommitSHA, err)
+				} else if len(statuses) > 0 {
+					urlStatusMap := map[string]string{}
+					for _, status := range statuses {
+						if status.IsFailed() {
+							return fmt.Errorf("Status: %s URL: %s description: %s", status.State, status.TargetURL, status.Description)
+						}
+						if urlStatusMap[status.URL] != gitStatusSuccess {
+							urlStatusMap[status.URL] = status.State
+						}
+					}
+					succeeded := true
+					for _, state := range urlStatusMap {
+						if state != gitStatusSuccess {
+							succeeded = false
+						}
+					}
+					if succeeded {
+						return nil
+					}
+				}
+			}
+		} else if pr.IsClosed() {
+			return fmt.Errorf("Pull Request %s is closed without merging", pr.URL)
+		} else if !o.NoMergePullRequest {
+			status, err := gitProvider.PullRequestLastCommitStatus(pr)
+			if err != nil {
+				log.Warnf("Failed to query the Pull Request last commit status for %s ref %s %s\n", pr.URL, pr.LastCommitSha, err)
+			} else if status == "success" {
+				err = gitProvider.MergePullRequest(pr, "jx promote automatically merged rollout PR")
+				if err != nil {
+					log.Warnf("Failed to merge the Pull Request %s due to %s\n", pr.URL, err)
+				}
+			} else if status == "error" || status == "failure" {
+				return fmt.Errorf("Pull request %s last commit has status %s for ref %s", pr.URL, status, pr.LastCommitSha)
+			}
+		}
+		if time.Now().After(end) {
+			return fmt.Errorf("Timed out waiting for rollout pull request %s to merge", pr.URL)
+		}
+		time.Sleep(*o.PullRequestPollDuration)
+	}
+}
+
+// writeRolloutChartFiles renders a rollout into the chart of a GitOps environment. The values of the chart configure
+// the service of the new version and the VirtualService or canary Ingress which splits the traffic is added to the chart
+// templates. A weight of zero removes the rollout from the chart
+func (o *PromoteOptions) writeRolloutChartFiles(chartDir string, env *v1.Environment, weight int32) error {
+	app := o.Application
+	canaryName := kube.CanaryName(app)
+	valuesFile := filepath.Join(chartDir, helm.ValuesFileName)
+	templateFile := filepath.Join(chartDir, "templates", canaryName+".yaml")
+	values, err := helm.LoadValuesFile(valuesFile)
+	if err != nil {
+		return err
+	}
+
+	if weight == 0 {
+		if _, ok := values[canaryName]; ok {
+			delete(values, canaryName)
+			err = helm.SaveValuesFile(valuesFile, values)
+			if err != nil {
+				return err
+			}
+		}
+		exists, err := util.FileExists(templateFile)
+		if err != nil || !exists {
+			return err
+		}
+		return os.Remove(templateFile)
+	}
+
+	// the new version uses the same values as the current version apart from the name of its service
+	stableKey := app
+	if o.Alias != "" {
+		stableKey = o.Alias
+	}
+	canaryValues := map[string]interface{}{}
+	if m, ok := values[stableKey].(map[string]interface{}); ok {
+		for k, v := range m {
+			canaryValues[k] = v
+		}
+	}
+	service := map[string]interface{}{}
+	if m, ok := canaryValues["service"].(map[string]interface{}); ok {
+		for k, v := range m {
+			service[k] = v
+		}
+	}
+	service["name"] = canaryName
+	canaryValues["service"] = service
+	values[canaryName] = canaryValues
+	err = helm.SaveValuesFile(valuesFile, values)
+	if err != nil {
+		return err
+	}
+
+	ns := env.Spec.Namespace
+	var resource interface{}
+	strategy := env.Spec.Rollout
+	switch strategy.Router {
+	case v1.RolloutRouterTypeIstio:
+		hosts, err := o.rolloutHosts(ns, app)
+		if err != nil {
+			return err
+		}
+		resource = kube.CreateVirtualService(ns, app, hosts, app, canaryName, weight)
+	case v1.RolloutRouterTypeIngress, "":
+		kubeClient, _, err := o.KubeClient()
+		if err != nil {
+			return err
+		}
+		stable, err := kubeClient.ExtensionsV1beta1().Ingresses(ns).Get(app, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to find ingress %s in namespace %s", app, ns)
+		}
+		ingress := kube.CreateCanaryIngress(stable, app, canaryName, weight)
+		ingress.APIVersion = "extensions/v1beta1"
+		ingress.Kind = "Ingress"
+		resource = ingress
+	default:
+		return fmt.Errorf("unsupported rollout router %s. Supported values are %s", strategy.Router, strings.Join(v1.RolloutRouterTypeValues, ", "))
+	}
+	data, err := yaml.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(templateFile), DefaultWritePermissions)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(templateFile, data, DefaultWritePermissions)
+}
+
+// canarySelector returns the label selector of the pods of the new version of the app in a GitOps environment
+func (o *PromoteOptions) canarySelector(ns string, app string) (string, error) {
+	kubeClient, _, err := o.KubeClient()
+	if err != nil {
+		return "", err
+	}
+	deployments, err := kube.GetDeployments(kubeClient, ns)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to list deployments in namespace %s", ns)
+	}
+	canaryName := kube.CanaryName(app)
+	for name, d := range deployments {
+		if strings.HasSuffix(name, canaryName) && d.Spec.Selector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(d.Spec.Selector)
+			if err != nil {
+				return "", err
+			}
+			return selector.String(), nil
+		}
+	}
+	return "", fmt.Errorf("no deployment found for %s in namespace %s", canaryName, ns)
+}
+
+// RolloutFlags the command line options for configuring the rollout strategy of an Environment
+type RolloutFlags struct {
+	Kind                string
+	Router              string
+	Weights             []int
+	StepDuration        string
+	MaxRestarts         int
+	PrometheusURL       string
+	PrometheusQuery     string
+	PrometheusThreshold string
+}
+
+func (f *RolloutFlags) addRolloutFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.Kind, "rollout", "", "", fmt.Sprintf("The rollout strategy used when promoting to the Environment. Supported values are %s", strings.Join(v1.RolloutKindTypeValues, ", ")))
+	cmd.Flags().StringVarP(&f.Router, "rollout-router", "", string(v1.RolloutRouterTypeIngress), fmt.Sprintf("How traffic is split during a rollout. Supported values are %s", strings.Join(v1.RolloutRouterTypeValues, ", ")))
+	cmd.Flags().IntSliceVarP(&f.Weights, "rollout-weights", "", nil, "The percentages of traffic sent to the new version at each step of a canary rollout")
+	cmd.Flags().StringVarP(&f.StepDuration, "rollout-step-duration", "", "", "How long to wait at each step of a rollout before checking the health of the new version")
+	cmd.Flags().IntVarP(&f.MaxRestarts, "rollout-max-restarts", "", 0, "The maximum number of pod restarts of the new version before the rollout is aborted")
+	cmd.Flags().StringVarP(&f.PrometheusURL, "rollout-prometheus-url", "", "", "The URL of the Prometheus server used to check the health of the new version")
+	cmd.Flags().StringVarP(&f.PrometheusQuery, "rollout-prometheus-query", "", "", "The Prometheus query which must not exceed the threshold during a rollout")
+	cmd.Flags().StringVarP(&f.PrometheusThreshold, "rollout-prometheus-threshold", "", "", "The maximum value of the Prometheus query before the rollout is aborted")
+}
+
+// createRolloutStrategy returns the rollout strategy from the flags or nil if no rollout is specified
+func (f *RolloutFlags) createRolloutStrategy() (*v1.RolloutStrategy, error) {
+	if f.Kind == "" {
+		return nil, nil
+	}
+	if util.StringArrayIndex(v1.RolloutKindTypeValues, f.Kind) < 0 {
+		return nil, util.InvalidOption("rollout", f.Kind, v1.RolloutKindTypeValues)
+	}
+	if util.StringArrayIndex(v1.RolloutRouterTypeValues, f.Router) < 0 {
+		return nil, util.InvalidOption("rollout-router", f.Router, v1.RolloutRouterTypeValues)
+	}
+	if f.StepDuration != "" {
+		_, err := time.ParseDuration(f.StepDuration)
+		if err != nil {
+			return nil, util.InvalidOptionError("rollout-step-duration", f.StepDuration, err)
+		}
+	}
+	if f.PrometheusQuery != "" {
+		_, err := strconv.ParseFloat(f.PrometheusThreshold, 64)
+		if err != nil {
+			return nil, util.InvalidOptionError("rollout-prometheus-threshold", f.PrometheusThreshold, err)
+		}
+	}
+	strategy := &v1.RolloutStrategy{
+		Kind:                v1.RolloutKindType(f.Kind),
+		Router:              v1.RolloutRouterType(f.Router),
+		StepDuration:        f.StepDuration,
+		MaxRestarts:         int32(f.MaxRestarts),
+		PrometheusURL:       f.PrometheusURL,
+		PrometheusQuery:     f.PrometheusQuery,
+		PrometheusThreshold: f.PrometheusThreshold,
+	}
+	for _, w := range f.Weights {
+		strategy.Weights = append(strategy.Weights, int32(w))
+	}
+	return strategy, nil
+}
@@ -0,0 +1,370 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RestoreOptions the options for the restore command
+type RestoreOptions struct {
+	CommonOptions
+
+	GitURL            string
+	Dir               string
+	Commit            string
+	Date              string
+	NamespaceMappings []string
+	Resources         []string
+	DryRun            bool
+}
+
+// BackupFile a resource loaded from the backup repository
+type BackupFile struct {
+	Resource  string
+	Name      string
+	Namespace string
+	File      string
+	// Object the resource or nil for the team settings
+	Object       metav1.Object
+	TeamSettings *v1.TeamSettings
+}
+
+var (
+	restoreLong = templates.LongDesc(`
+		Restores the Jenkins X resources backed up into a git repository by 'jx controller backup' such as the
+		Environments, Teams, Users, EnvironmentRoleBindings, GitServices, Extensions, Workflows and the team settings.
+
+		The backup can be restored as of a commit or date and the namespaces of the resources can be remapped so that
+		they can be restored into a new cluster. The differences to the resources in the cluster are displayed before
+		they are applied.
+`)
+
+	restoreExample = templates.Examples(`
+		# Restore the latest backup
+		jx restore --git-url https://github.com/myorg/organisation-myorg-backup.git
+
+		# Display the changes of restoring the backup as of a date into the myteam namespace
+		jx restore --git-url https://github.com/myorg/organisation-myorg-backup.git --date "October 1 2018" --namespace-mapping jx=myteam --dry-run
+	`)
+)
+
+// NewCmdRestore creates the command
+func NewCmdRestore(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &RestoreOptions{
+		CommonOptions: CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "restore",
+		Short:   "Restores the Jenkins X resources from the backup git repository",
+		Long:    restoreLong,
+		Example: restoreExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.GitURL, "git-url", "u", "", "The URL of the backup git repository")
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "The directory of a clone of the backup git repository to use instead of cloning the git URL")
+	cmd.Flags().StringVarP(&options.Commit, "commit", "c", "", "The commit of the backup to restore. Defaults to the latest commit")
+	cmd.Flags().StringVarP(&options.Date, "date", "", "", "Restores the backup as it was before the date. Should be a format: "+util.DateFormat)
+	cmd.Flags().StringArrayVarP(&options.NamespaceMappings, "namespace-mapping", "m", []string{}, "Maps the namespace of the backed up resources to a new namespace in the form 'old=new'")
+	cmd.Flags().StringArrayVarP(&options.Resources, "resource", "r", []string{}, "The kinds of resource to restore. Defaults to all of them")
+	cmd.Flags().BoolVarP(&options.DryRun, "dry-run", "", false, "Only displays the changes which would be applied")
+
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *RestoreOptions) Run() error {
+	mappings, err := ParseNamespaceMappings(o.NamespaceMappings)
+	if err != nil {
+		return err
+	}
+	if o.Commit != "" && o.Date != "" {
+		return fmt.Errorf("only one of --commit or --date can be specified")
+	}
+	dir := o.Dir
+	if dir == "" {
+		if o.GitURL == "" {
+			return util.MissingOption("git-url")
+		}
+		dir, err = ioutil.TempDir("", "jx-restore-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		log.Infof("Cloning the backup repository %s\n", util.ColorInfo(o.GitURL))
+		err = o.Git().Clone(o.GitURL, dir)
+		if err != nil {
+			return errors.Wrapf(err, "failed to clone %s", o.GitURL)
+		}
+	}
+	commit := o.Commit
+	if o.Date != "" {
+		commit, err = o.Git().GetRevisionBeforeDateText(dir, o.Date)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find the backup before %s", o.Date)
+		}
+		if commit == "" {
+			return fmt.Errorf("no backup found before %s", o.Date)
+		}
+	}
+	if commit != "" {
+		log.Infof("Restoring the backup as of commit %s\n", util.ColorInfo(commit))
+		err = o.Git().Checkout(dir, commit)
+		if err != nil {
+			return err
+		}
+	}
+
+	files, err := LoadBackupFiles(dir, mappings, o.Resources)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		log.Infof("No resources found in the backup\n")
+		return nil
+	}
+
+	registered := map[string]bool{}
+	for _, file := range files {
+		resource := findBackupResource(file.Resource)
+		if resource != nil && !registered[file.Resource] {
+			err = resource.Register(&o.CommonOptions)
+			if err != nil {
+				return err
+			}
+			registered[file.Resource] = true
+		}
+	}
+
+	changes := []func() error{}
+	for _, file := range files {
+		apply, err := o.planRestore(file)
+		if err != nil {
+			return err
+		}
+		if apply != nil {
+			changes = append(changes, apply)
+		}
+	}
+	if len(changes) == 0 {
+		log.Infof("The resources in the cluster are the same as the backup\n")
+		return nil
+	}
+	if o.DryRun {
+		log.Infof("Not applying the %d changes as this is a dry run\n", len(changes))
+		return nil
+	}
+	if !o.BatchMode && !util.Confirm(fmt.Sprintf("Apply the %d changes?", len(changes)), true, "Creates or updates the resources in the cluster from the backup", o.In, o.Out, o.Err) {
+		return nil
+	}
+	for _, apply := range changes {
+		err = apply()
+		if err != nil {
+			return err
+		}
+	}
+	log.Infof("Restored %d resources\n", len(changes))
+	return nil
+}
+
+// planRestore displays the differences between the backup file and the cluster returning the function to apply them
+// or nil if there are no differences
+func (o *RestoreOptions) planRestore(file *BackupFile) (func() error, error) {
+	jxClient, _, err := o.JXClient()
+	if err != nil {
+		return nil, err
+	}
+	description := fmt.Sprintf("%s %s in namespace %s", file.Resource, file.Name, file.Namespace)
+
+	if file.TeamSettings != nil {
+		env, err := jxClient.JenkinsV1().Environments(file.Namespace).Get(file.Name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				log.Warnf("Not restoring the team settings as there is no Environment %s in namespace %s\n", file.Name, file.Namespace)
+				return nil, nil
+			}
+			return nil, err
+		}
+		diff, err := resourceDiff(&env.Spec.TeamSettings, file.TeamSettings)
+		if err != nil || diff == "" {
+			return nil, err
+		}
+		log.Infof("Update %s:\n%s\n", util.ColorInfo(description), diff)
+		return func() error {
+			env.Spec.TeamSettings = *file.TeamSettings
+			_, err := jxClient.JenkinsV1().Environments(file.Namespace).Update(env)
+			return errors.Wrapf(err, "failed to restore %s", description)
+		}, nil
+	}
+
+	resource := findBackupResource(file.Resource)
+	desired := file.Object
+	live, err := resource.Get(jxClient, file.Namespace, file.Name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		data, err := yaml.Marshal(desired)
+		if err != nil {
+			return nil, err
+		}
+		log.Infof("Create %s:\n%s\n", util.ColorInfo(description), string(data))
+		return func() error {
+			kubeClient, _, err := o.KubeClient()
+			if err != nil {
+				return err
+			}
+			err = kube.EnsureNamespaceCreated(kubeClient, file.Namespace, nil, nil)
+			if err != nil {
+				return err
+			}
+			return errors.Wrapf(resource.Create(jxClient, file.Namespace, desired), "failed to restore %s", description)
+		}, nil
+	}
+	resourceVersion := live.GetResourceVersion()
+	clearBackupMetadata(live)
+	diff, err := resourceDiff(live, desired)
+	if err != nil || diff == "" {
+		return nil, err
+	}
+	log.Infof("Update %s:\n%s\n", util.ColorInfo(description), diff)
+	return func() error {
+		desired.SetResourceVersion(resourceVersion)
+		return errors.Wrapf(resource.Update(jxClient, file.Namespace, desired), "failed to restore %s", description)
+	}, nil
+}
+
+// ParseNamespaceMappings parses the namespace mappings in the form 'old=new'
+func ParseNamespaceMappings(values []string) (map[string]string, error) {
+	answer := map[string]string{}
+	for _, value := range values {
+		paths := strings.SplitN(value, "=", 2)
+		if len(paths) != 2 || paths[0] == "" || paths[1] == "" {
+			return nil, util.InvalidOptionf("namespace-mapping", value, "should be in the form 'old=new'")
+		}
+		answer[paths[0]] = paths[1]
+	}
+	return answer, nil
+}
+
+// LoadBackupFiles loads the resources of the backup repository in the order they should be restored remapping their
+// namespaces. If any resources are specified only those kinds of resources are loaded
+func LoadBackupFiles(dir string, mappings map[string]string, resources []string) ([]*BackupFile, error) {
+	names := []string{}
+	for _, r := range backupResources {
+		names = append(names, r.Resource)
+	}
+	names = append(names, backupResourceTeamSettings)
+	for _, r := range resources {
+		if util.StringArrayIndex(names, r) < 0 {
+			return nil, util.InvalidOption("resource", r, names)
+		}
+	}
+
+	answer := []*BackupFile{}
+	for _, name := range names {
+		if len(resources) > 0 && util.StringArrayIndex(resources, name) < 0 {
+			continue
+		}
+		fileNames, err := filepath.Glob(filepath.Join(dir, backupDirName(name), "*", "*.yaml"))
+		if err != nil {
+			return nil, err
+		}
+		for _, fileName := range fileNames {
+			data, err := ioutil.ReadFile(fileName)
+			if err != nil {
+				return nil, err
+			}
+			ns := filepath.Base(filepath.Dir(fileName))
+			if mapped, ok := mappings[ns]; ok {
+				ns = mapped
+			}
+			file := &BackupFile{
+				Resource:  name,
+				Name:      strings.TrimSuffix(filepath.Base(fileName), ".yaml"),
+				Namespace: ns,
+				File:      fileName,
+			}
+			if name == backupResourceTeamSettings {
+				file.TeamSettings = &v1.TeamSettings{}
+				err = yaml.Unmarshal(data, file.TeamSettings)
+			} else {
+				file.Object = findBackupResource(name).New()
+				err = yaml.Unmarshal(data, file.Object)
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to load backup file %s", fileName)
+			}
+			if file.Object != nil {
+				clearBackupMetadata(file.Object)
+				file.Object.SetNamespace(ns)
+				env, ok := file.Object.(*v1.Environment)
+				if ok {
+					if mapped, ok := mappings[env.Spec.Namespace]; ok {
+						env.Spec.Namespace = mapped
+					}
+				}
+			}
+			answer = append(answer, file)
+		}
+	}
+	return answer, nil
+}
+
+// clearBackupMetadata clears the metadata which is specific to the cluster the resource was backed up from
+func clearBackupMetadata(obj metav1.Object) {
+	obj.SetResourceVersion("")
+	obj.SetUID("")
+	obj.SetSelfLink("")
+	obj.SetGeneration(0)
+	obj.SetCreationTimestamp(metav1.Time{})
+}
+
+// resourceDiff returns the unified diff of the YAML of the live and backed up resource or an empty string if they
+// are the same
+func resourceDiff(live interface{}, backup interface{}) (string, error) {
+	liveData, err := yaml.Marshal(live)
+	if err != nil {
+		return "", err
+	}
+	backupData, err := yaml.Marshal(backup)
+	if err != nil {
+		return "", err
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(liveData)),
+		B:        difflib.SplitLines(string(backupData)),
+		FromFile: "cluster",
+		ToFile:   "backup",
+		Context:  3,
+	})
+}
@@ -0,0 +1,83 @@
+package cmd_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNamespaceMappings(t *testing.T) {
+	t.Parallel()
+	mappings, err := cmd.ParseNamespaceMappings([]string{"jx=myteam", "jx-staging=myteam-staging"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"jx": "myteam", "jx-staging": "myteam-staging"}, mappings)
+
+	_, err = cmd.ParseNamespaceMappings([]string{"jx"})
+	assert.Error(t, err)
+}
+
+func TestLoadBackupFiles(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-restore")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeBackupFile(t, dir, "environments", "jx", "staging", `apiVersion: jenkins.io/v1
+kind: Environment
+metadata:
+  name: staging
+  namespace: jx
+  resourceVersion: "1234"
+  uid: 5c2b0f9e-0000-0000-0000-000000000000
+spec:
+  namespace: jx-staging
+`)
+	writeBackupFile(t, dir, "users", "jx", "jstrachan", `apiVersion: jenkins.io/v1
+kind: User
+metadata:
+  name: jstrachan
+  namespace: jx
+`)
+	writeBackupFile(t, dir, "teamsettings", "jx", "dev", `promotionEngine: Prow
+`)
+
+	files, err := cmd.LoadBackupFiles(dir, map[string]string{"jx": "myteam", "jx-staging": "myteam-staging"}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, files, 3)
+
+	assert.Equal(t, "user", files[0].Resource)
+	assert.Equal(t, "jstrachan", files[0].Name)
+	assert.Equal(t, "myteam", files[0].Namespace)
+
+	env, ok := files[1].Object.(*v1.Environment)
+	assert.True(t, ok, "expected an Environment")
+	assert.Equal(t, "myteam", env.Namespace)
+	assert.Equal(t, "myteam-staging", env.Spec.Namespace)
+	assert.Equal(t, "", env.ResourceVersion)
+	assert.Equal(t, "", string(env.UID))
+
+	assert.Equal(t, "teamsettings", files[2].Resource)
+	assert.NotNil(t, files[2].TeamSettings)
+	assert.Equal(t, v1.PromotionEngineProw, files[2].TeamSettings.PromotionEngine)
+
+	files, err = cmd.LoadBackupFiles(dir, nil, []string{"user"})
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, "jx", files[0].Namespace)
+
+	_, err = cmd.LoadBackupFiles(dir, nil, []string{"unknown"})
+	assert.Error(t, err)
+}
+
+func writeBackupFile(t *testing.T, dir string, kind string, ns string, name string, text string) {
+	nsDir := filepath.Join(dir, kind, ns)
+	err := os.MkdirAll(nsDir, 0755)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(nsDir, name+".yaml"), []byte(text), 0644)
+	assert.NoError(t, err)
+}
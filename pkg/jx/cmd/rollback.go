@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+)
+
+// RollbackOptions contains the command line options
+type RollbackOptions struct {
+	CommonOptions
+}
+
+var (
+	rollbackLong = templates.LongDesc(`
+		Rolls back a resource such as an application in an Environment to a previous version.
+`)
+
+	rollbackExample = templates.Examples(`
+		# Rollback the current application in production to the previous version
+		jx rollback app --env production
+	`)
+)
+
+// NewCmdRollback creates the command object
+func NewCmdRollback(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &RollbackOptions{
+		CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "rollback TYPE [flags]",
+		Short:   "Rolls back a resource such as an application to a previous version",
+		Long:    rollbackLong,
+		Example: rollbackExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+		SuggestFor: []string{"revert", "undo"},
+	}
+
+	cmd.AddCommand(NewCmdRollbackApp(f, in, out, errOut))
+	return cmd
+}
+
+// Run implements this command
+func (o *RollbackOptions) Run() error {
+	return o.Cmd.Help()
+}
@@ -0,0 +1,370 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RollbackAppOptions contains the command line options
+type RollbackAppOptions struct {
+	PromoteOptions
+
+	FromVersion string
+}
+
+var (
+	rollbackAppLong = templates.LongDesc(`
+		Rolls back an application in an Environment to a previous version.
+
+		A Pull Request is created on the Environment git repository which restores the previous chart version in the 'requirements.yaml' file.
+		The Pull Request is merged once its checks pass unless the '--no-merge' option is specified.
+
+		The previous version is found from the PipelineActivity resources of the application unless the '--version' option is specified.
+
+`)
+
+	rollbackAppExample = templates.Examples(`
+		# Rollback the current application in production to the previous version
+		jx rollback app --env production
+
+		# Rollback the myapp application in staging to version 1.2.3
+		jx rollback app myapp --env staging --version 1.2.3
+	`)
+)
+
+// NewCmdRollbackApp creates the command object
+func NewCmdRollbackApp(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &RollbackAppOptions{
+		PromoteOptions: PromoteOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "app [application]",
+		Short:   "Rolls back an application in an Environment to a previous version",
+		Long:    rollbackAppLong,
+		Example: rollbackAppExample,
+		Aliases: []string{"application", "apps"},
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Environment, optionEnvironment, "e", "", "The Environment to rollback")
+	cmd.Flags().StringVarP(&options.Version, "version", "v", "", "The version to rollback to. Defaults to the previous version promoted to the Environment")
+	cmd.Flags().StringVarP(&options.FromVersion, "from-version", "", "", "The broken version to rollback from. Defaults to the most recent version promoted to the Environment")
+	cmd.Flags().StringVarP(&options.Pipeline, "pipeline", "", "", "The Pipeline string in the form 'folderName/repoName/branch' of the PipelineActivity to record the rollback on. Defaults to the pipeline which promoted the broken version")
+	cmd.Flags().StringVarP(&options.Build, "build", "", "", "The Build number of the PipelineActivity to record the rollback on. Defaults to the pipeline which promoted the broken version")
+	cmd.Flags().StringVarP(&options.Timeout, optionTimeout, "t", "1h", "The timeout to wait for the rollback Pull Request to merge")
+	cmd.Flags().StringVarP(&options.PullRequestPollTime, optionPullRequestPollTime, "", "20s", "Poll time when waiting for a Pull Request to merge")
+	cmd.Flags().BoolVarP(&options.NoMergePullRequest, "no-merge", "", false, "Disables automatic merge of the rollback Pull Request")
+	return cmd
+}
+
+// Run implements this command
+func (o *RollbackAppOptions) Run() error {
+	app := o.Application
+	if app == "" {
+		var err error
+		if len(o.Args) > 0 {
+			app = o.Args[0]
+		} else {
+			app, err = o.DiscoverAppName()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	o.Application = app
+
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	if o.Environment == "" {
+		if o.BatchMode {
+			return util.MissingOption(optionEnvironment)
+		}
+		m, allEnvNames, err := kube.GetOrderedEnvironments(jxClient, ns)
+		if err != nil {
+			return err
+		}
+		names := []string{}
+		for _, n := range allEnvNames {
+			if m[n].Spec.Kind == v1.EnvironmentKindTypePermanent {
+				names = append(names, n)
+			}
+		}
+		o.Environment, err = kube.PickEnvironment(names, "", o.In, o.Out, o.Err)
+		if err != nil {
+			return err
+		}
+	}
+	if o.PullRequestPollTime != "" {
+		duration, err := time.ParseDuration(o.PullRequestPollTime)
+		if err != nil {
+			return fmt.Errorf("Invalid duration format %s for option --%s: %s", o.PullRequestPollTime, optionPullRequestPollTime, err)
+		}
+		o.PullRequestPollDuration = &duration
+	}
+	if o.Timeout != "" {
+		duration, err := time.ParseDuration(o.Timeout)
+		if err != nil {
+			return fmt.Errorf("Invalid duration format %s for option --%s: %s", o.Timeout, optionTimeout, err)
+		}
+		o.TimeoutDuration = &duration
+	}
+
+	apisClient, err := o.CreateApiExtensionsClient()
+	if err != nil {
+		return err
+	}
+	err = kube.RegisterPipelineActivityCRD(apisClient)
+	if err != nil {
+		return err
+	}
+	env, err := jxClient.JenkinsV1().Environments(ns).Get(o.Environment, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find Environment %s", o.Environment)
+	}
+	if env.Spec.Source.URL == "" {
+		return fmt.Errorf("Environment %s has no git repository so it cannot be rolled back", o.Environment)
+	}
+	o.Activities = jxClient.JenkinsV1().PipelineActivities(ns)
+	list, err := o.Activities.List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list PipelineActivity resources in namespace %s", ns)
+	}
+
+	promoted := kube.PromotedActivities(list.Items, app, env.Name)
+	if o.FromVersion == "" && len(promoted) > 0 {
+		o.FromVersion = promoted[0].Spec.Version
+	}
+	if o.Version == "" {
+		previous := kube.FindPreviousPromotedActivity(list.Items, app, env.Name, o.FromVersion)
+		if previous == nil {
+			return fmt.Errorf("could not find a previous version of %s promoted to Environment %s. Please specify the version via the --version option", app, env.Name)
+		}
+		o.Version = previous.Spec.Version
+	}
+	if o.Pipeline == "" {
+		for _, activity := range promoted {
+			if activity.Spec.Version == o.FromVersion {
+				o.Pipeline = activity.Spec.Pipeline
+				o.Build = activity.Spec.Build
+				break
+			}
+		}
+	}
+	return o.Rollback(env)
+}
+
+// Rollback creates a Pull Request on the Environment git repository to restore the previous version of the application
+// then waits for the Pull Request to merge recording the rollback on the PipelineActivity of the broken version
+func (o *RollbackAppOptions) Rollback(env *v1.Environment) error {
+	app := o.Application
+	version := o.Version
+	fromVersion := o.FromVersion
+	if fromVersion == version {
+		return fmt.Errorf("cannot rollback %s in Environment %s from version %s to the same version", app, env.Name, version)
+	}
+	log.Infof("Rolling back %s in Environment %s from version %s to %s\n", util.ColorInfo(app), util.ColorInfo(env.Name), util.ColorInfo(fromVersion), util.ColorInfo(version))
+
+	branchNameText := "rollback-" + app + "-" + version
+	title := "rollback " + app + " to " + version
+	message := fmt.Sprintf("Rollback %s to version %s", app, version)
+	if fromVersion != "" {
+		message += fmt.Sprintf(" as version %s is broken", fromVersion)
+	}
+
+	modifyRequirementsFn := func(requirements *helm.Requirements) error {
+		for _, dep := range requirements.Dependencies {
+			if dep != nil && (dep.Name == app || (dep.Alias != "" && dep.Alias == app)) {
+				if fromVersion == "" {
+					fromVersion = dep.Version
+				} else if dep.Version != fromVersion {
+					log.Warnf("Environment %s has version %s of %s rather than %s\n", env.Name, dep.Version, app, fromVersion)
+				}
+				dep.Version = version
+				return nil
+			}
+		}
+		return fmt.Errorf("application %s is not deployed in Environment %s", app, env.Name)
+	}
+
+	rollbackKey := o.createPromoteKey(env)
+	startRollback := func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, r *v1.RollbackActivityStep) error {
+		kube.StartRollback(a, s, r)
+		r.FromVersion = fromVersion
+		r.ToVersion = version
+		return nil
+	}
+	err := rollbackKey.OnRollback(o.Activities, startRollback)
+	if err != nil {
+		log.Warnf("Failed to record the rollback on the PipelineActivity: %s\n", err)
+	}
+
+	releaseInfo := &ReleaseInfo{
+		ReleaseName: o.ReleaseName,
+		FullAppName: app,
+		Version:     version,
+	}
+	if o.FakePullRequests != nil {
+		releaseInfo.PullRequestInfo, err = o.FakePullRequests(env, modifyRequirementsFn, branchNameText, title, message, nil)
+	} else {
+		releaseInfo.PullRequestInfo, err = o.createEnvironmentPullRequest(env, modifyRequirementsFn, nil, branchNameText, title, message, nil, o.ConfigureGitCallback)
+	}
+	o.ReleaseInfo = releaseInfo
+	if err != nil {
+		rollbackKey.OnRollback(o.Activities, kube.FailedRollback)
+		return errors.Wrapf(err, "failed to create the rollback Pull Request for Environment %s", env.Name)
+	}
+	pullRequestInfo := releaseInfo.PullRequestInfo
+	if pullRequestInfo == nil || pullRequestInfo.PullRequest == nil {
+		log.Infof("Environment %s is already at version %s of %s\n", util.ColorInfo(env.Name), util.ColorInfo(version), util.ColorInfo(app))
+		return rollbackKey.OnRollback(o.Activities, kube.CompleteRollback)
+	}
+	pr := pullRequestInfo.PullRequest
+	rollbackKey.OnRollback(o.Activities, func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, r *v1.RollbackActivityStep) error {
+		r.PullRequestURL = pr.URL
+		return nil
+	})
+
+	if o.NoMergePullRequest {
+		log.Infof("Not merging the rollback Pull Request %s\n", util.ColorInfo(pr.URL))
+		return nil
+	}
+	mergeSha, err := o.waitForRollbackPullRequest(pullRequestInfo)
+	if err != nil {
+		rollbackKey.OnRollback(o.Activities, kube.FailedRollback)
+		return err
+	}
+	log.Infof("Rolled back %s in Environment %s to version %s\n", util.ColorInfo(app), util.ColorInfo(env.Name), util.ColorInfo(version))
+	err = rollbackKey.OnRollback(o.Activities, func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, r *v1.RollbackActivityStep) error {
+		kube.CompleteRollback(a, s, r)
+		r.MergeCommitSHA = mergeSha
+		return nil
+	})
+	if err != nil {
+		log.Warnf("Failed to record the rollback on the PipelineActivity: %s\n", err)
+	}
+	if fromVersion != "" {
+		err = o.commentOnRollbackIssues(env, fromVersion, version)
+		if err != nil {
+			log.Warnf("Failed to comment on the issues of version %s: %s\n", fromVersion, err)
+		}
+	}
+	return nil
+}
+
+// waitForRollbackPullRequest merges the rollback Pull Request once its checks pass returning the merge commit SHA
+func (o *RollbackAppOptions) waitForRollbackPullRequest(pullRequestInfo *ReleasePullRequestInfo) (string, error) {
+	pr := pullRequestInfo.PullRequest
+	gitProvider := pullRequestInfo.GitProvider
+	if o.TimeoutDuration == nil || o.PullRequestPollDuration == nil {
+		return "", fmt.Errorf("no --%s or --%s option specified so cannot wait for the rollback Pull Request %s", optionTimeout, optionPullRequestPollTime, pr.URL)
+	}
+	duration := *o.TimeoutDuration
+	end := time.Now().Add(duration)
+	logMergeFailure := false
+	for {
+		err := gitProvider.UpdatePullRequestStatus(pr)
+		if err != nil {
+			log.Warnf("Failed to query the Pull Request status for %s %s\n", pr.URL, err)
+		} else if pr.Merged != nil && *pr.Merged {
+			if pr.MergeCommitSHA != nil {
+				return *pr.MergeCommitSHA, nil
+			}
+		} else if pr.IsClosed() {
+			return "", fmt.Errorf("Rollback failed as Pull Request %s is closed without merging", pr.URL)
+		} else {
+			status, err := gitProvider.PullRequestLastCommitStatus(pr)
+			if err != nil {
+				log.Warnf("Failed to query the Pull Request last commit status for %s ref %s %s\n", pr.URL, pr.LastCommitSha, err)
+			} else if status == "success" {
+				err = gitProvider.MergePullRequest(pr, "jx rollback automatically merged rollback PR")
+				if err != nil && !logMergeFailure {
+					logMergeFailure = true
+					log.Warnf("Failed to merge the Pull Request %s due to %s maybe I don't have karma?\n", pr.URL, err)
+				}
+			} else if status == "error" || status == "failure" {
+				return "", fmt.Errorf("Pull request %s last commit has status %s for ref %s", pr.URL, status, pr.LastCommitSha)
+			}
+		}
+		if time.Now().After(end) {
+			return "", fmt.Errorf("Timed out waiting for pull request %s to merge. Waited %s", pr.URL, duration.String())
+		}
+		time.Sleep(*o.PullRequestPollDuration)
+	}
+}
+
+// commentOnRollbackIssues comments on the issues fixed in the broken version that the fix is no longer in the environment
+func (o *RollbackAppOptions) commentOnRollbackIssues(env *v1.Environment, fromVersion string, toVersion string) error {
+	ens := env.Spec.Namespace
+	if ens == "" {
+		return nil
+	}
+	jxClient, _, err := o.JXClient()
+	if err != nil {
+		return err
+	}
+	releaseName := kube.ToValidNameWithDots(o.Application + "-" + fromVersion)
+	release, err := jxClient.JenkinsV1().Releases(ens).Get(releaseName, metav1.GetOptions{})
+	if err != nil || release == nil {
+		log.Infof("No Release %s found in namespace %s so not commenting on issues\n", releaseName, ens)
+		return nil
+	}
+	if len(release.Spec.Issues) == 0 {
+		return nil
+	}
+	gitURL := release.Spec.GitHTTPURL
+	if gitURL == "" {
+		return fmt.Errorf("Release %s has no git URL", releaseName)
+	}
+	provider, gitInfo, err := o.createGitProviderForURLWithoutKind(gitURL)
+	if err != nil {
+		return err
+	}
+	envName := env.Spec.Label
+	if envName == "" {
+		envName = env.Name
+	}
+	for _, issue := range release.Spec.Issues {
+		number, err := strconv.Atoi(issue.ID)
+		if err != nil || number <= 0 {
+			log.Warnf("Could not parse issue id %s for URL %s\n", issue.ID, issue.URL)
+			continue
+		}
+		log.Infof("Commenting that version %s has been rolled back on issue %s\n", util.ColorInfo(fromVersion), util.ColorInfo(issue.URL))
+		comment := fmt.Sprintf(":warning: version %s has been rolled back in **%s** to version %s so the fix for this issue is no longer deployed there", fromVersion, envName, toVersion)
+		err = provider.CreateIssueComment(gitInfo.Organisation, gitInfo.Name, number, comment)
+		if err != nil {
+			log.Warnf("Failed to add comment to issue %s: %s\n", issue.URL, err)
+		}
+	}
+	return nil
+}
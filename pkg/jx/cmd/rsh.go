@@ -232,6 +232,11 @@ func (o *RshOptions) Run() error {
 	if o.Verbose {
 		log.Infof("Running command: kubectl %s\n", strings.Join(a, " "))
 	}
+	if o.DevPod {
+		// lets record the activity of the DevPod while the shell is open so that it is not stopped as idle
+		done := o.keepDevPodActive(client, ns, name)
+		defer done()
+	}
 	return o.runCommandInteractive(true, "kubectl", a...)
 }
 
@@ -91,7 +91,11 @@ func (o *StartProtectionOptions) Run() error {
 	}
 	orgrepo := o.Args[1]
 	context := o.Args[0]
-	err = prow.AddProtection(kClient, []string{orgrepo}, context, ns)
+	gitProvider, err := o.prowGitProvider()
+	if err != nil {
+		return err
+	}
+	err = prow.AddProtection(kClient, []string{orgrepo}, context, ns, gitProvider)
 	if err != nil {
 		return err
 	}
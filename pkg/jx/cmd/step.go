@@ -43,11 +43,13 @@ func NewCmdStep(f Factory, in terminal.FileReader, out terminal.FileWriter, errO
 
 	cmd.AddCommand(NewCmdStepBlog(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepChangelog(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepChat(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateBuild(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepGit(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepGpgCredentials(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepHelm(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepLinkServices(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepLogs(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepNexus(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepNextVersion(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepNextBuildNumber(f, in, out, errOut))
@@ -56,6 +58,7 @@ func NewCmdStep(f Factory, in terminal.FileReader, out terminal.FileWriter, errO
 	cmd.AddCommand(NewCmdStepPost(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepReport(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepRelease(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepScan(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepSplitMonorepo(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepTag(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepValidate(f, in, out, errOut))
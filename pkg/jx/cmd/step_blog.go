@@ -54,6 +54,7 @@ type StepBlogOptions struct {
 	CombineMinorReleases        bool
 	DeveloperChannelMemberCount int
 	UserChannelMemberCount      int
+	DeliveryMetrics             bool
 
 	State StepBlogState
 }
@@ -109,6 +110,7 @@ func NewCmdStepBlog(f Factory, in terminal.FileReader, out terminal.FileWriter,
 	cmd.Flags().BoolVarP(&options.CombineMinorReleases, "combine-minor", "c", true, "If enabled lets combine minor releases together to simplify the charts")
 	cmd.Flags().IntVarP(&options.DeveloperChannelMemberCount, "dev-channel-members", "", 0, "If no chat bots can connect to your chat server you can pass in the counts for the developer channel here")
 	cmd.Flags().IntVarP(&options.UserChannelMemberCount, "user-channel-members", "", 0, "If no chat bots can connect to your chat server you can pass in the counts for the user channel here")
+	cmd.Flags().BoolVarP(&options.DeliveryMetrics, "delivery-metrics", "", false, "If enabled adds charts of the DORA delivery metrics of the team's environments and applications")
 	return cmd
 }
 
@@ -161,6 +163,12 @@ func (o *StepBlogOptions) Run() error {
 			return err
 		}
 	}
+	if o.DeliveryMetrics {
+		err = o.deliveryMetricsReport()
+		if err != nil {
+			return err
+		}
+	}
 	return o.addReportsToBlog()
 }
 
@@ -211,6 +219,22 @@ func (o *StepBlogOptions) downloadsReport(provider gits.GitProvider, owner strin
 	return report.Render()
 }
 
+// deliveryMetricsReport adds the charts of the delivery metrics of the environments and applications
+func (o *StepBlogOptions) deliveryMetricsReport() error {
+	report, err := o.createDeliveryReport(&DeliveryMetricsFlags{
+		FromDate: o.FromDate,
+		ToDate:   o.ToDate,
+	})
+	if err != nil {
+		return err
+	}
+	if len(report.Applications) == 0 {
+		log.Warnf("No promotions found from %s to %s\n", util.FormatDate(report.From), util.FormatDate(report.To))
+		return nil
+	}
+	return report.RenderBarReports(o.createBarReport)
+}
+
 // createBarReport creates the new report instance
 func (o *StepBlogOptions) createBarReport(name string, legends ...string) reports.BarReport {
 	outDir := o.BlogOutputDir
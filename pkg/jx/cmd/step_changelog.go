@@ -131,8 +131,10 @@ e.g. define environment variables GIT_USERNAME and GIT_API_TOKEN
 
 `)
 
-	GitHubIssueRegex = regexp.MustCompile(`(\#\d+)`)
-	JIRAIssueRegex   = regexp.MustCompile(`[A-Z][A-Z]+-(\d+)`)
+	GitHubIssueRegex      = regexp.MustCompile(`(\#\d+)`)
+	JIRAIssueRegex        = regexp.MustCompile(`[A-Z][A-Z]+-(\d+)`)
+	YouTrackIssueRegex    = regexp.MustCompile(`\b[A-Z][A-Z0-9_]*-\d+\b`)
+	AzureBoardsIssueRegex = regexp.MustCompile(`\bAB#\d+\b`)
 )
 
 func NewCmdStepChangelog(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
@@ -517,20 +519,18 @@ func (o *StepChangelogOptions) addCommit(spec *v1.ReleaseSpec, commit *object.Co
 
 func (o *StepChangelogOptions) addIssuesAndPullRequests(spec *v1.ReleaseSpec, commit *v1.CommitSummary, rawCommit *object.Commit) error {
 	tracker := o.State.Tracker
-
-	gitProvider := o.State.GitProvider
-	if gitProvider == nil || !gitProvider.HasIssues() {
-		return nil
-	}
-	regex := GitHubIssueRegex
 	issueKind := issues.GetIssueProvider(tracker)
+	if issueKind == issues.Git {
+		gitProvider := o.State.GitProvider
+		if gitProvider == nil || !gitProvider.HasIssues() {
+			return nil
+		}
+	}
 	if !o.State.LoggedIssueKind {
 		o.State.LoggedIssueKind = true
 		log.Infof("Finding issues in commit messages using %s format\n", issueKind)
 	}
-	if issueKind == issues.Jira {
-		regex = JIRAIssueRegex
-	}
+	regex := issueKeyRegex(issueKind)
 	message := fullCommitMessageText(rawCommit)
 	matches := regex.FindAllStringSubmatch(message, -1)
 	for _, match := range matches {
@@ -597,6 +597,20 @@ func (o *StepChangelogOptions) addIssuesAndPullRequests(spec *v1.ReleaseSpec, co
 	return nil
 }
 
+// issueKeyRegex returns the regular expression which finds references to issues of the given kind of issue tracker
+func issueKeyRegex(issueKind string) *regexp.Regexp {
+	switch issueKind {
+	case issues.Jira:
+		return JIRAIssueRegex
+	case issues.YouTrack:
+		return YouTrackIssueRegex
+	case issues.AzureBoards:
+		return AzureBoardsIssueRegex
+	default:
+		return GitHubIssueRegex
+	}
+}
+
 // toV1Labels converts git labels to IssueLabel
 func toV1Labels(labels []gits.GitLabel) []v1.IssueLabel {
 	answer := []v1.IssueLabel{}
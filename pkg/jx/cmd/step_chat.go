@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepChatOptions contains the command line flags
+type StepChatOptions struct {
+	StepOptions
+
+	Dir         string
+	Message     string
+	Channel     string
+	UserChannel bool
+	Thread      string
+}
+
+var (
+	stepChatLong = templates.LongDesc(`
+		Posts a message to the chat channel configured in the 'chat' section of the 'jenkins-x.yml' file
+
+		By default the message is posted to the developer channel.
+`)
+
+	stepChatExample = templates.Examples(`
+		# post a message to the developer channel
+		jx step chat -m "the build is broken"
+
+		# post a message to the user channel
+		jx step chat --user-channel -m "version 1.2.3 has been released"
+
+		# reply to the thread of a previous message
+		jx step chat --thread 1540000000.000100 -m "the build is fixed"
+	`)
+)
+
+// NewCmdStepChat creates the command
+func NewCmdStepChat(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := StepChatOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "chat",
+		Short:   "Posts a message to the chat channel of the project",
+		Long:    stepChatLong,
+		Example: stepChatExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", ".", "The directory containing the 'jenkins-x.yml' file")
+	cmd.Flags().StringVarP(&options.Message, "message", "m", "", "The message to post")
+	cmd.Flags().StringVarP(&options.Channel, "channel", "c", "", "The channel to post to. Defaults to the channel in the 'jenkins-x.yml' file")
+	cmd.Flags().BoolVarP(&options.UserChannel, "user-channel", "", false, "Post to the user channel rather than the developer channel")
+	cmd.Flags().StringVarP(&options.Thread, "thread", "t", "", "The ID of a previous message to reply to in its thread")
+
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *StepChatOptions) Run() error {
+	if o.Message == "" {
+		return util.MissingOption("message")
+	}
+	projectConfig, fileName, err := config.LoadProjectConfig(o.Dir)
+	if err != nil {
+		return err
+	}
+	chatConfig := projectConfig.Chat
+	if chatConfig == nil || chatConfig.URL == "" {
+		return fmt.Errorf("No chat configured in %s. Please add one via: jx edit config -k chat", fileName)
+	}
+	channel := o.Channel
+	if channel == "" {
+		if o.UserChannel {
+			channel = chatConfig.UserChannel
+		} else {
+			channel = chatConfig.DeveloperChannel
+		}
+	}
+	if channel == "" {
+		return fmt.Errorf("No channel configured in %s. Please specify one via --channel", fileName)
+	}
+	provider, err := o.createChatProvider(chatConfig)
+	if err != nil {
+		return err
+	}
+	if o.Thread != "" {
+		message, err := provider.PostThreadReply(channel, o.Thread, o.Message)
+		if err != nil {
+			return err
+		}
+		log.Infof("Posted reply %s to thread %s in channel %s\n", util.ColorInfo(message.ID), util.ColorInfo(o.Thread), util.ColorInfo(channel))
+		return nil
+	}
+	message, err := provider.PostMessage(channel, o.Message)
+	if err != nil {
+		return err
+	}
+	log.Infof("Posted message %s to channel %s\n", util.ColorInfo(message.ID), util.ColorInfo(channel))
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	jenkinsv1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/storage"
 	"github.com/jenkins-x/jx/pkg/util"
 
 	"github.com/pkg/errors"
@@ -30,6 +31,16 @@ type StepCollectOptions struct {
 	HttpStepCollectOptions
 	Pattern    []string
 	Classifier string
+	Bucket     string
+	Region     string
+	Endpoint   string
+	Dir        string
+}
+
+// collectedFile a file matched by a pattern and its path relative to the classifier in the store
+type collectedFile struct {
+	Source string
+	Path   string
 }
 
 type GitHubPagesStepCollectOptions struct {
@@ -53,20 +64,38 @@ const ghPagesBranchName = "gh-pages"
 const (
 	GitHubPagesCollectProviderKind CollectProviderKind = "GitHub"
 	HttpCollectProviderKind        CollectProviderKind = "Http"
+	S3CollectProviderKind          CollectProviderKind = "s3"
+	GCSCollectProviderKind         CollectProviderKind = "gcs"
+	PVCCollectProviderKind         CollectProviderKind = "pvc"
 )
 
+// defaultArtifactsDir the directory artifacts are stored in by the pvc provider if no directory is configured
+const defaultArtifactsDir = "/var/jx/artifacts"
+
 var CollectProvidersKinds = []string{
 	string(GitHubPagesCollectProviderKind),
 	string(HttpCollectProviderKind),
+	string(S3CollectProviderKind),
+	string(GCSCollectProviderKind),
+	string(PVCCollectProviderKind),
 }
 
 var (
 	StepCollectLong = templates.LongDesc(`
-		This pipeline step collects the specified files that need storing from the build 
+		This pipeline step collects the specified files that need storing from the build such as test reports and coverage.
+
+		The files are stored in the gh-pages branch of a GitHub repository, an S3 or S3 compatible bucket, a Google Cloud
+		Storage bucket or a directory such as a mounted persistent volume at <pipeline>/<build>/<classifier>/<file>.
+		If no provider is specified the artifactStorage of the team settings is used. The URLs of the files are added as
+		an attachment to the PipelineActivity of the build and the files are deleted when the activity is garbage collected.
 `)
 
 	StepCollectExample = templates.Examples(`
-		jx step collect TODO
+		# collect the test reports using the artifact storage of the team
+		jx step collect --pattern target/surefire-reports --classifier test-reports
+
+		# collect the coverage report to an S3 compatible bucket
+		jx step collect --provider s3 --bucket my-artifacts --endpoint http://minio:9000 --pattern target/site/jacoco --classifier coverage
 `)
 )
 
@@ -97,22 +126,205 @@ func NewCmdStepCollect(f Factory, in terminal.FileReader, out terminal.FileWrite
 	cmd.Flags().StringArrayVarP(&options.Pattern, "pattern", "", make([]string, 0), fmt.Sprintf("Specify the pattern to use to look for files"))
 	cmd.Flags().StringVarP(&options.HttpStepCollectOptions.Destination, "destination", "", "", fmt.Sprintf("Specify the HTTP endpoint to send each file to"))
 	cmd.Flags().StringVarP(&options.Classifier, "classifier", "", "", "A name which classifies this type of file e.g. test-reports, coverage")
+	cmd.Flags().StringVarP(&options.Bucket, "bucket", "", "", "The bucket to store the files in for the s3 and gcs providers")
+	cmd.Flags().StringVarP(&options.Region, "region", "", "", "The region of the bucket for the s3 provider")
+	cmd.Flags().StringVarP(&options.Endpoint, "endpoint", "", "", "The URL of an S3 compatible object store such as Minio for the s3 provider")
+	cmd.Flags().StringVarP(&options.Dir, "dir", "", "", fmt.Sprintf("The directory such as a mounted persistent volume to store the files in for the pvc provider. Defaults to %s", defaultArtifactsDir))
 	return cmd
 }
 
 func (o *StepCollectOptions) Run() error {
 	if o.Provider == "" {
-		return errors.New("Must specify a provider using --provider")
+		teamSettings, err := o.TeamSettings()
+		if err != nil {
+			return err
+		}
+		o.defaultFromArtifactStorage(teamSettings.ArtifactStorage)
+	}
+	if o.Provider == "" {
+		return errors.New("Must specify a provider using --provider or the artifactStorage of the team settings")
 	}
-	if strings.ToLower(o.Provider) == strings.ToLower(string(GitHubPagesCollectProviderKind)) {
-		err := o.GitHubPagesStepCollectOptions.collect(*o)
-		return err
-	} else if strings.ToLower(o.Provider) == strings.ToLower(string(HttpCollectProviderKind)) {
+	switch strings.ToLower(o.Provider) {
+	case strings.ToLower(string(GitHubPagesCollectProviderKind)):
+		return o.GitHubPagesStepCollectOptions.collect(*o)
+	case strings.ToLower(string(HttpCollectProviderKind)):
 		return o.HttpStepCollectOptions.collect()
-	} else {
-		return errors.New(fmt.Sprintf("Unrecognized provider %s", o.Provider))
+	case string(S3CollectProviderKind), string(GCSCollectProviderKind), string(PVCCollectProviderKind):
+		store, err := o.createArtifactStore()
+		if err != nil {
+			return err
+		}
+		return o.collectToStore(store)
+	default:
+		return util.InvalidOption("provider", o.Provider, CollectProvidersKinds)
 	}
-	return nil
+}
+
+// defaultFromArtifactStorage defaults the provider and any options which are not specified from the artifact storage
+// of the team settings
+func (o *StepCollectOptions) defaultFromArtifactStorage(spec *jenkinsv1.ArtifactStorageSpec) {
+	if spec == nil {
+		return
+	}
+	if o.Provider == "" {
+		o.Provider = spec.Kind
+	}
+	if o.Bucket == "" {
+		o.Bucket = spec.Bucket
+	}
+	if o.Region == "" {
+		o.Region = spec.Region
+	}
+	if o.Endpoint == "" {
+		o.Endpoint = spec.Endpoint
+	}
+	if o.Dir == "" {
+		o.Dir = spec.Dir
+	}
+}
+
+// createArtifactStore creates the store of the s3, gcs or pvc provider
+func (o *StepCollectOptions) createArtifactStore() (storage.Store, error) {
+	switch strings.ToLower(o.Provider) {
+	case string(S3CollectProviderKind):
+		if o.Bucket == "" {
+			return nil, util.MissingOption("bucket")
+		}
+		store := storage.NewS3Store(o.Bucket, "", o.Region)
+		store.Endpoint = o.Endpoint
+		return store, nil
+	case string(GCSCollectProviderKind):
+		if o.Bucket == "" {
+			return nil, util.MissingOption("bucket")
+		}
+		return storage.NewGCSStore(o.Bucket), nil
+	default:
+		dir := o.Dir
+		if dir == "" {
+			dir = defaultArtifactsDir
+		}
+		return storage.NewFileStore(dir), nil
+	}
+}
+
+// collectToStore writes the files matching the patterns to the store and attaches their URLs to the activity
+func (o *StepCollectOptions) collectToStore(store storage.Store) error {
+	if o.Classifier == "" {
+		return util.MissingOption("classifier")
+	}
+	pipeline := o.getJobName()
+	build := o.getBuildNumber()
+	if pipeline == "" || build == "" {
+		return fmt.Errorf("no $JOB_NAME or $BUILD_NUMBER environment variables found")
+	}
+	files, err := collectFiles(o.Pattern)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		log.Warnf("No files found matching the patterns %s\n", strings.Join(o.Pattern, ", "))
+		return nil
+	}
+	urls := []string{}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file.Source)
+		if err != nil {
+			return err
+		}
+		u, err := store.Write(ArtifactPath(pipeline, build, o.Classifier, file.Path), data)
+		if err != nil {
+			return errors.Wrapf(err, "failed to store %s", file.Source)
+		}
+		log.Infof("Collected %s\n", util.ColorInfo(u))
+		urls = append(urls, u)
+	}
+	return o.addAttachment(pipeline, build, urls)
+}
+
+// ArtifactPath returns the path in a store of a file collected with the classifier from a build of a pipeline
+func ArtifactPath(pipeline string, build string, classifier string, file string) string {
+	return pipeline + "/" + build + "/" + classifier + "/" + strings.TrimPrefix(filepath.ToSlash(file), "/")
+}
+
+// collectFiles returns the files matching the glob patterns. Directories are collected recursively keeping their
+// name as the first element of the path of their files
+func collectFiles(patterns []string) ([]collectedFile, error) {
+	answer := []collectedFile{}
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, util.InvalidOptionf("pattern", pattern, "invalid pattern: %s", err)
+		}
+		for _, match := range matches {
+			base := filepath.Dir(match)
+			err = filepath.Walk(match, func(path string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() {
+					return err
+				}
+				rel, err := filepath.Rel(base, path)
+				if err != nil {
+					return err
+				}
+				answer = append(answer, collectedFile{
+					Source: path,
+					Path:   filepath.ToSlash(rel),
+				})
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return answer, nil
+}
+
+// addAttachment adds the URLs to the attachment of the classifier on the activity of the build
+func (o *StepCollectOptions) addAttachment(pipeline string, build string, urls []string) error {
+	apisClient, err := o.CreateApiExtensionsClient()
+	if err != nil {
+		return err
+	}
+	err = kube.RegisterPipelineActivityCRD(apisClient)
+	if err != nil {
+		return err
+	}
+	client, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return errors.Wrap(err, "cannot create the JX client")
+	}
+	activities := client.JenkinsV1().PipelineActivities(ns)
+	key := &kube.PipelineActivityKey{
+		Name:     kube.ToValidName(pipeline + "-" + build),
+		Pipeline: pipeline,
+		Build:    build,
+	}
+	a, _, err := key.GetOrCreate(activities)
+	if err != nil {
+		return err
+	}
+	addAttachmentURLs(a, o.Classifier, urls)
+	_, err = activities.Update(a)
+	return err
+}
+
+// addAttachmentURLs adds the URLs to the attachment with the name creating it if required
+func addAttachmentURLs(a *jenkinsv1.PipelineActivity, name string, urls []string) {
+	for i := range a.Spec.Attachments {
+		attachment := &a.Spec.Attachments[i]
+		if attachment.Name == name {
+			for _, u := range urls {
+				if util.StringArrayIndex(attachment.URLs, u) < 0 {
+					attachment.URLs = append(attachment.URLs, u)
+				}
+			}
+			return
+		}
+	}
+	a.Spec.Attachments = append(a.Spec.Attachments, jenkinsv1.Attachment{
+		Name: name,
+		URLs: urls,
+	})
 }
 
 func (o *GitHubPagesStepCollectOptions) collect(options StepCollectOptions) (err error) {
@@ -217,52 +429,11 @@ func (o *GitHubPagesStepCollectOptions) collect(options StepCollectOptions) (err
 		return err
 	}
 
-	f := options.Factory
-	client, ns, err := f.CreateJXClient()
-	if err != nil {
-		return errors.Wrap(err, "cannot create the JX client")
-	}
-
-	apisClient, err := options.CreateApiExtensionsClient()
-	if err != nil {
-		return err
-	}
-	err = kube.RegisterPipelineActivityCRD(apisClient)
-	if err != nil {
-		return err
-	}
-
-	activities := client.JenkinsV1().PipelineActivities(ns)
-	if err != nil {
-		return err
+	if buildNo == "" {
+		return nil
 	}
-	build := options.getBuildNumber()
-	// TODO this pipeline name construction needs moving to a shared lib, and other things refactoring to use it
-	pipeline := fmt.Sprintf("%s-%s-%s-%s", org, repoName, branchName, build)
-
-	if pipeline != "" && build != "" {
-		name := kube.ToValidName(pipeline)
-		key := &kube.PromoteStepActivityKey{
-			PipelineActivityKey: kube.PipelineActivityKey{
-				Name:     name,
-				Pipeline: pipeline,
-				Build:    build,
-			},
-		}
-		a, _, err := key.GetOrCreate(activities)
-		if err != nil {
-			return err
-		}
-		a.Spec.Attachments = append(a.Spec.Attachments, jenkinsv1.Attachment{
-			Name: options.Classifier,
-			URLs: urls,
-		})
-		_, err = client.JenkinsV1().PipelineActivities(ns).Update(a)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	pipeline := fmt.Sprintf("%s/%s/%s", org, repoName, branchName)
+	return options.addAttachment(pipeline, buildNo, urls)
 }
 
 func (o *GitHubPagesStepCollectOptions) contains(strings []string, str string) bool {
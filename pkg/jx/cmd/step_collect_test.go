@@ -0,0 +1,14 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArtifactPath(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "myorg/myapp/master/3/coverage/jacoco/index.html", cmd.ArtifactPath("myorg/myapp/master", "3", "coverage", "jacoco/index.html"))
+	assert.Equal(t, "myorg/myapp/PR-12/1/test-reports/TEST-Foo.xml", cmd.ArtifactPath("myorg/myapp/PR-12", "1", "test-reports", "/TEST-Foo.xml"))
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/builds"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StepLogsOptions contains the command line flags
+type StepLogsOptions struct {
+	StepOptions
+
+	Pipeline string
+	Build    string
+	Store    BuildLogsStoreFlags
+}
+
+var (
+	stepLogsLong = templates.LongDesc(`
+		Archives the complete log of a build to a long term store so that it can still be viewed via 'jx get build logs'
+		once the build pod or Jenkins job has been garbage collected.
+
+		The log is stored in a git repository, an S3 bucket or a directory such as a mounted persistent volume and its
+		URL is recorded on the PipelineActivity of the build.
+
+		Only the log of a completed build can be archived so this command fails if the build is still running, such as
+		when it is invoked by the build itself. To archive the complete log of every build use
+		'jx controller build --archive-logs' which archives the log of each Jenkins build or Knative build pod once it
+		completes.
+`)
+
+	stepLogsExample = templates.Examples(`
+		# archive the log of a completed build to an S3 bucket
+		jx step logs -p myorg/myapp/master -b 2 --logs-store s3 --logs-bucket my-build-logs
+
+		# archive the log of a completed build to a git repository
+		jx step logs -p myorg/myapp/master -b 3 --logs-store git --logs-git-url https://github.com/myorg/build-logs.git
+`)
+)
+
+// NewCmdStepLogs creates the command
+func NewCmdStepLogs(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := StepLogsOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "logs",
+		Short:   "Archives the log of a build to long term storage",
+		Long:    stepLogsLong,
+		Example: stepLogsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Pipeline, "pipeline", "p", "", "The pipeline name of the build. Defaults to $JOB_NAME")
+	cmd.Flags().StringVarP(&options.Build, "build", "b", "", "The build number. Defaults to $BUILD_NUMBER")
+	options.Store.addFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *StepLogsOptions) Run() error {
+	pipeline := o.Pipeline
+	if pipeline == "" {
+		pipeline = o.getJobName()
+	}
+	if pipeline == "" {
+		return util.MissingOption("pipeline")
+	}
+	build := o.Build
+	if build == "" {
+		build = o.getBuildNumber()
+	}
+	buildNumber, err := strconv.Atoi(build)
+	if err != nil {
+		return util.InvalidOptionf("build", build, "the build must be a number")
+	}
+
+	store, err := o.createBuildLogsStore(&o.Store)
+	if err != nil {
+		return err
+	}
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	kubeClient, _, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+
+	activities, err := jxClient.JenkinsV1().PipelineActivities(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var activity *v1.PipelineActivity
+	for i := range activities.Items {
+		a := &activities.Items[i]
+		if a.Spec.Pipeline == pipeline && a.Spec.Build == build {
+			activity = a
+			break
+		}
+	}
+	if activity == nil {
+		return fmt.Errorf("no PipelineActivity found for pipeline %s build #%s", pipeline, build)
+	}
+
+	devEnv, err := kube.GetEnrichedDevEnvironment(kubeClient, jxClient, ns)
+	if err != nil {
+		return err
+	}
+	var data []byte
+	completed := false
+	if devEnv.Spec.WebHookEngine == v1.WebHookEngineProw {
+		pods, err := builds.GetBuildPods(kubeClient, ns)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, pod := range pods {
+			initContainers := pod.Spec.InitContainers
+			if len(initContainers) > 0 {
+				params := BuildParams{}
+				params.DefaultValuesFromEnvVars(initContainers[len(initContainers)-1].Env)
+				if params.MatchesPipeline(activity) {
+					found = true
+					completed = isBuildPodCompleted(pod)
+					if completed {
+						data, err = o.getBuildPodLog(kubeClient, ns, pod)
+						if err != nil {
+							return err
+						}
+					}
+					break
+				}
+			}
+		}
+		if !found {
+			return fmt.Errorf("no build pod found for pipeline %s build #%s", pipeline, build)
+		}
+	} else {
+		data, completed, err = o.getJenkinsBuildLog(pipeline, buildNumber)
+		if err != nil {
+			return err
+		}
+	}
+	if !completed {
+		return fmt.Errorf("pipeline %s build #%s has not completed so its complete log cannot be archived yet. Use 'jx controller build --archive-logs' to archive the log of each build once it completes", pipeline, build)
+	}
+
+	err = o.archiveBuildLog(jxClient, ns, activity, data, store)
+	if err != nil {
+		return err
+	}
+	log.Infof("Archived the log of pipeline %s build %s to %s\n", util.ColorInfo(pipeline), util.ColorInfo("#"+build), util.ColorInfo(activity.Spec.BuildLogsURL))
+	return nil
+}
@@ -40,7 +40,9 @@ func NewCmdStepReport(f Factory, in terminal.FileReader, out terminal.FileWriter
 	}
 
 	cmd.AddCommand(NewCmdStepReportActivities(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepReportDora(f, in, out, errOut))
 	cmd.AddCommand(NewCmdStepReportReleases(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepReportTests(f, in, out, errOut))
 
 	return cmd
 }
@@ -24,15 +24,27 @@ type StepReportActivitiesOptions struct {
 	StepReportOptions
 	Watch bool
 	pe.PipelineEventsProvider
+
+	EventFlags PipelineEventsFlags
 }
 
 var (
 	StepReportActivitiesLong = templates.LongDesc(`
-		This pipeline step reports activities to pluggable backends like ElasticSearch
+		This pipeline step reports activities to pluggable backends like ElasticSearch, CloudEvents brokers,
+		signed webhooks and Kafka.
+
+		The event sinks are configured via 'jx create addon pipeline-events' or the command line flags. Events which fail
+		to be delivered are queued in the --queue-dir directory and retried so that a sink which is down does not lose events.
 `)
 
 	StepReportActivitiesExample = templates.Examples(`
 		jx step report activities
+
+		# watch the activities publishing them as CloudEvents and to a signed webhook
+		jx step report activities --watch --cloudevents-url http://broker-ingress.knative-eventing/jx/default --webhook-url https://example.com/hook --webhook-secret mysecret
+
+		# write the activities to Kafka via the Kafka REST Proxy without using Elasticsearch
+		jx step report activities --watch --no-elasticsearch --kafka-url http://kafka-rest-proxy:8082 --kafka-topic pipelines
 `)
 )
 
@@ -64,6 +76,7 @@ func NewCmdStepReportActivities(f Factory, in terminal.FileReader, out terminal.
 	}
 
 	cmd.Flags().BoolVarP(&options.Watch, "watch", "w", false, "Whether to watch activities")
+	options.EventFlags.addFlags(cmd)
 	options.addCommonFlags(cmd)
 	return cmd
 }
@@ -94,22 +107,13 @@ func (o *StepReportActivitiesOptions) Run() error {
 		return err
 	}
 
-	esServiceName := kube.AddonServices[defaultPEName]
-	externalURL, err := o.ensureAddonServiceAvailable(esServiceName)
-	if err != nil {
-		log.Warnf("no %s service found, are you in your teams dev environment?  Type `jx env` to switch.\n", esServiceName)
-		return fmt.Errorf("try running `jx create addon pipeline-events` in your teams dev environment: %v", err)
-	}
-
-	server, auth, err := o.CommonOptions.getAddonAuthByKind(kube.ValueKindPipelineEvent, externalURL)
+	o.PipelineEventsProvider, err = o.createPipelineEventsProvider(&o.EventFlags)
 	if err != nil {
-		return fmt.Errorf("error getting %s auth details, %v", kube.ValueKindPipelineEvent, err)
+		return err
 	}
 
-	o.PipelineEventsProvider, err = pe.NewElasticsearchProvider(server, auth)
-	if err != nil {
-		return fmt.Errorf("error creating elasticsearch provider, %v", err)
-	}
+	// lets send any events which failed to be delivered previously
+	retryQueuedPipelineEvents(o.PipelineEventsProvider)
 
 	if o.Watch {
 		err = o.watchPipelineActivities(jxClient, o.currentNamespace)
@@ -131,14 +135,16 @@ func (o *StepReportActivitiesOptions) getPipelineActivities(jxClient versioned.I
 	if err != nil {
 		return err
 	}
-	for _, a := range activities.Items {
-		err := o.PipelineEventsProvider.SendActivity(&a)
+	var answer error
+	for i := range activities.Items {
+		err := o.PipelineEventsProvider.SendActivity(&activities.Items[i])
 		if err != nil {
+			// lets carry on sending the other activities as failed events are queued for retry
 			log.Errorf("%v\n", err)
-			return err
+			answer = err
 		}
 	}
-	return nil
+	return answer
 }
 
 func (o *StepReportActivitiesOptions) watchPipelineActivities(jxClient versioned.Interface, ns string) error {
@@ -188,6 +194,7 @@ func (o *StepReportActivitiesOptions) watchPipelineActivities(jxClient versioned
 
 	stop := make(chan struct{})
 	go controller.Run(stop)
+	go retryPipelineEvents(o.PipelineEventsProvider, o.EventFlags.RetryInterval, stop)
 
 	// Wait forever
 	select {}
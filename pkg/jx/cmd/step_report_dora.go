@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/reports"
+	"github.com/jenkins-x/jx/pkg/table"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepReportDoraOptions contains the command line flags
+type StepReportDoraOptions struct {
+	StepReportOptions
+	DeliveryMetricsFlags
+
+	Output     string
+	OutputFile string
+}
+
+var (
+	stepReportDoraLong = templates.LongDesc(`
+		This pipeline step reports the DORA delivery metrics of the team: deployment frequency, lead time from commit
+		to deployment, change failure rate and mean time to restore for each environment and application.
+
+		The metrics are calculated from the promotions and rollbacks recorded in the PipelineActivity resources and the
+		commits of the Release resources.
+`)
+
+	stepReportDoraExample = templates.Examples(`
+		# Display charts of the delivery metrics over the last 4 weeks
+		jx step report dora
+
+		# Save the delivery metrics of production as JSON
+		jx step report dora --env production --output-file dora.json
+`)
+)
+
+// NewCmdStepReportDora creates the command
+func NewCmdStepReportDora(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := StepReportDoraOptions{
+		StepReportOptions: StepReportOptions{
+			StepOptions: StepOptions{
+				CommonOptions: CommonOptions{
+					Factory: f,
+					In:      in,
+					Out:     out,
+					Err:     errOut,
+				},
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "dora",
+		Short:   "Reports the DORA delivery metrics",
+		Aliases: []string{"delivery"},
+		Long:    stepReportDoraLong,
+		Example: stepReportDoraExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.DeliveryMetricsFlags.addFlags(cmd)
+	cmd.Flags().StringVarP(&options.Output, "output", "o", "", "The output format of the report. One of: text or json")
+	cmd.Flags().StringVarP(&options.OutputFile, "output-file", "", "", "The file to write the report to as JSON")
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *StepReportDoraOptions) Run() error {
+	if o.Output != "" && o.Output != table.FormatText && o.Output != table.FormatJSON {
+		return util.InvalidOption("output", o.Output, []string{table.FormatText, table.FormatJSON})
+	}
+	report, err := o.createDeliveryReport(&o.DeliveryMetricsFlags)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if o.OutputFile != "" {
+		err = ioutil.WriteFile(o.OutputFile, data, DefaultWritePermissions)
+		if err != nil {
+			return fmt.Errorf("failed to save file %s: %s", o.OutputFile, err)
+		}
+		log.Infof("Saved the delivery metrics to %s\n", util.ColorInfo(o.OutputFile))
+	}
+	if o.Output == table.FormatJSON {
+		_, err = fmt.Fprintln(o.Out, string(data))
+		return err
+	}
+	if len(report.Applications) == 0 {
+		log.Infof("No promotions found from %s to %s\n", util.ColorInfo(util.FormatDate(report.From)), util.ColorInfo(util.FormatDate(report.To)))
+		return nil
+	}
+	return report.RenderBarReports(func(name string, legends ...string) reports.BarReport {
+		return reports.NewTableBarReport(o.CreateTable(), legends...)
+	})
+}
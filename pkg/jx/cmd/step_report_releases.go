@@ -24,11 +24,14 @@ type StepReportReleasesOptions struct {
 	StepReportOptions
 	Watch bool
 	pe.PipelineEventsProvider
+
+	EventFlags PipelineEventsFlags
 }
 
 var (
 	StepReportReleasesLong = templates.LongDesc(`
-		This pipeline step reports releases to pluggable backends like ElasticSearch
+		This pipeline step reports releases to pluggable backends like ElasticSearch, CloudEvents brokers,
+		signed webhooks and Kafka.
 `)
 
 	StepReportReleasesExample = templates.Examples(`
@@ -63,6 +66,7 @@ func NewCmdStepReportReleases(f Factory, in terminal.FileReader, out terminal.Fi
 	}
 
 	cmd.Flags().BoolVarP(&options.Watch, "watch", "w", false, "Whether to watch Releases")
+	options.EventFlags.addFlags(cmd)
 	options.addCommonFlags(cmd)
 	return cmd
 }
@@ -93,22 +97,13 @@ func (o *StepReportReleasesOptions) Run() error {
 		return err
 	}
 
-	esServiceName := kube.AddonServices[defaultPEName]
-	externalURL, err := o.ensureAddonServiceAvailable(esServiceName)
+	o.PipelineEventsProvider, err = o.createPipelineEventsProvider(&o.EventFlags)
 	if err != nil {
-		log.Warnf("no %s service found, are you in your teams dev environment?  Type `jx env` to switch.\n", esServiceName)
-		return fmt.Errorf("try running `jx create addon pipeline-events` in your teams dev environment: %v", err)
+		return err
 	}
 
-	server, auth, err := o.CommonOptions.getAddonAuthByKind(kube.ValueKindPipelineEvent, externalURL)
-	if err != nil {
-		return fmt.Errorf("error getting %s auth details, %v", kube.ValueKindPipelineEvent, err)
-	}
-
-	o.PipelineEventsProvider, err = pe.NewElasticsearchProvider(server, auth)
-	if err != nil {
-		return fmt.Errorf("error creating elasticsearch provider, %v", err)
-	}
+	// lets send any events which failed to be delivered previously
+	retryQueuedPipelineEvents(o.PipelineEventsProvider)
 
 	if o.Watch {
 		err = o.watchPipelineReleases(jxClient, o.currentNamespace)
@@ -130,14 +125,16 @@ func (o *StepReportReleasesOptions) getPipelineReleases(jxClient versioned.Inter
 	if err != nil {
 		return err
 	}
-	for _, r := range releases.Items {
-		err := o.PipelineEventsProvider.SendRelease(&r)
+	var answer error
+	for i := range releases.Items {
+		err := o.PipelineEventsProvider.SendRelease(&releases.Items[i])
 		if err != nil {
+			// lets carry on sending the other releases as failed events are queued for retry
 			log.Errorf("%v\n", err)
-			return err
+			answer = err
 		}
 	}
-	return nil
+	return answer
 }
 
 func (o *StepReportReleasesOptions) watchPipelineReleases(jxClient versioned.Interface, ns string) error {
@@ -187,6 +184,7 @@ func (o *StepReportReleasesOptions) watchPipelineReleases(jxClient versioned.Int
 
 	stop := make(chan struct{})
 	go controller.Run(stop)
+	go retryPipelineEvents(o.PipelineEventsProvider, o.EventFlags.RetryInterval, stop)
 
 	// Wait forever
 	select {}
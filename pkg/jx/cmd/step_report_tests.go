@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/testreports"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StepReportTestsOptions contains the command line flags
+type StepReportTestsOptions struct {
+	StepReportOptions
+
+	Pattern   []string
+	Format    string
+	Name      string
+	Slowest   int
+	History   int
+	NoComment bool
+}
+
+var (
+	stepReportTestsLong = templates.LongDesc(`
+		This pipeline step parses the JUnit XML, xUnit.net XML or 'go test -json' reports of a build into a summary of
+		the total, failed and skipped tests and the slowest tests.
+
+		The summary is stored as a fact on the PipelineActivity of the build and, for pull requests, posted as a comment
+		on the pull request. Tests which both pass and fail for the same commit, or which keep changing between passing and
+		failing across the recent builds of the pipeline, are reported as flaky.
+`)
+
+	stepReportTestsExample = templates.Examples(`
+		# report the surefire test results of a Maven build
+		jx step report tests --pattern target/surefire-reports
+
+		# report the results of 'go test -json ./... > report.json'
+		jx step report tests --pattern report.json --format gotest
+`)
+)
+
+// NewCmdStepReportTests creates the command
+func NewCmdStepReportTests(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := StepReportTestsOptions{
+		StepReportOptions: StepReportOptions{
+			StepOptions: StepOptions{
+				CommonOptions: CommonOptions{
+					Factory: f,
+					In:      in,
+					Out:     out,
+					Err:     errOut,
+				},
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "tests",
+		Short:   "Reports the results of the tests of a build",
+		Aliases: []string{"test"},
+		Long:    stepReportTestsLong,
+		Example: stepReportTestsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringArrayVarP(&options.Pattern, "pattern", "", []string{}, "The test report files or directories of .xml and .json test reports to parse. Supports glob patterns")
+	cmd.Flags().StringVarP(&options.Format, "format", "", "", fmt.Sprintf("The format of the test reports. Detected from the content of each report if not specified. One of: %s", strings.Join(testreports.Formats, ", ")))
+	cmd.Flags().StringVarP(&options.Name, "name", "n", "tests", "The name of the test results recorded on the PipelineActivity such as tests or integration-tests")
+	cmd.Flags().IntVarP(&options.Slowest, "slowest", "", 5, "The number of slowest tests to report")
+	cmd.Flags().IntVarP(&options.History, "history", "", 10, "The number of recent builds of the pipeline to compare with to detect flaky tests")
+	cmd.Flags().BoolVarP(&options.NoComment, "no-comment", "", false, "Disables the comment on the pull request")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepReportTestsOptions) Run() error {
+	if len(o.Pattern) == 0 {
+		return util.MissingOption("pattern")
+	}
+	if o.Format != "" && util.StringArrayIndex(testreports.Formats, o.Format) < 0 {
+		return util.InvalidOption("format", o.Format, testreports.Formats)
+	}
+	files, err := collectFiles(o.Pattern)
+	if err != nil {
+		return err
+	}
+	cases := []testreports.TestCase{}
+	for _, file := range files {
+		ext := strings.ToLower(filepath.Ext(file.Source))
+		if ext != ".xml" && ext != ".json" {
+			continue
+		}
+		fileCases, err := testreports.ParseFile(file.Source, o.Format)
+		if err != nil {
+			return err
+		}
+		cases = append(cases, fileCases...)
+	}
+	if len(cases) == 0 {
+		log.Warnf("No test results found matching the patterns %s\n", strings.Join(o.Pattern, ", "))
+		return nil
+	}
+	summary := testreports.Summarize(cases, o.Slowest)
+
+	pipeline := o.getJobName()
+	build := o.getBuildNumber()
+	if pipeline == "" || build == "" {
+		log.Warnf("No $JOB_NAME or $BUILD_NUMBER environment variables found so cannot record the test results on the PipelineActivity\n")
+		o.logTestSummary(summary)
+		return nil
+	}
+	apisClient, err := o.CreateApiExtensionsClient()
+	if err != nil {
+		return err
+	}
+	err = kube.RegisterPipelineActivityCRD(apisClient)
+	if err != nil {
+		return err
+	}
+	jxClient, devNs, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	activities := jxClient.JenkinsV1().PipelineActivities(devNs)
+	key := &kube.PipelineActivityKey{
+		Name:     kube.ToValidName(pipeline + "-" + build),
+		Pipeline: pipeline,
+		Build:    build,
+	}
+	activity, _, err := key.GetOrCreate(activities)
+	if err != nil {
+		return err
+	}
+
+	list, err := activities.List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	summary.Flaky = testreports.FlakyTests(o.recentTestRuns(list.Items, activity, summary))
+	o.logTestSummary(summary)
+
+	testreports.AddTestFact(activity, summary.Fact(o.Name))
+	activity, err = activities.Update(activity)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update PipelineActivity %s", key.Name)
+	}
+
+	if !o.NoComment && kube.IsPullRequestBranch(activity.BranchName()) {
+		return o.commentTestSummary(activity, summary)
+	}
+	return nil
+}
+
+// recentTestRuns returns the test runs of the recent builds of the pipeline of the activity ordered by build number
+// ending with the current test results
+func (o *StepReportTestsOptions) recentTestRuns(activities []v1.PipelineActivity, current *v1.PipelineActivity, summary *testreports.Summary) []*testreports.TestRun {
+	currentBuild, _ := strconv.Atoi(current.Spec.Build)
+	previous := []v1.PipelineActivity{}
+	for _, a := range activities {
+		build, err := strconv.Atoi(a.Spec.Build)
+		if a.Spec.Pipeline == current.Spec.Pipeline && err == nil && build < currentBuild {
+			previous = append(previous, a)
+		}
+	}
+	sort.Slice(previous, func(i, j int) bool {
+		bi, _ := strconv.Atoi(previous[i].Spec.Build)
+		bj, _ := strconv.Atoi(previous[j].Spec.Build)
+		return bi < bj
+	})
+	if len(previous) > o.History {
+		previous = previous[len(previous)-o.History:]
+	}
+	runs := []*testreports.TestRun{}
+	for i := range previous {
+		run := testreports.TestRunFromActivity(&previous[i], o.Name)
+		if run != nil {
+			runs = append(runs, run)
+		}
+	}
+	return append(runs, testreports.NewTestRun(current.Spec.LastCommitSHA, summary.FailedTests()))
+}
+
+func (o *StepReportTestsOptions) logTestSummary(summary *testreports.Summary) {
+	log.Infof("Tests: %s passed: %s failed: %s skipped: %s\n", util.ColorInfo(strconv.Itoa(summary.Total)), util.ColorInfo(strconv.Itoa(summary.Passed)), util.ColorError(strconv.Itoa(summary.Failed)), util.ColorWarning(strconv.Itoa(summary.Skipped)))
+	for _, test := range summary.FailedTests() {
+		log.Infof("Failed: %s\n", util.ColorError(test))
+	}
+	for _, test := range summary.Flaky {
+		log.Infof("Flaky: %s\n", util.ColorWarning(test))
+	}
+	for _, c := range summary.Slowest {
+		log.Infof("Slow: %s %s\n", c.FullName(), util.ColorInfo(c.Duration.String()))
+	}
+}
+
+// commentTestSummary posts the summary as a comment on the pull request of the activity
+func (o *StepReportTestsOptions) commentTestSummary(activity *v1.PipelineActivity, summary *testreports.Summary) error {
+	gitURL := activity.Spec.GitURL
+	if gitURL == "" {
+		gitURL = os.Getenv(envVarSourceUrl)
+	}
+	if gitURL == "" {
+		log.Warnf("No git URL found for %s so cannot comment on the pull request\n", activity.Name)
+		return nil
+	}
+	gitInfo, err := gits.ParseGitURL(gitURL)
+	if err != nil {
+		return err
+	}
+	prNumber, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(activity.BranchName()), "PR-"))
+	if err != nil {
+		return fmt.Errorf("failed to find the pull request number of branch %s: %s", activity.BranchName(), err)
+	}
+	provider, err := o.gitProviderForURL(gitURL, "user name to comment on the pull request")
+	if err != nil {
+		return err
+	}
+	pr := &gits.GitPullRequest{
+		Owner:  gitInfo.Organisation,
+		Repo:   gitInfo.Name,
+		Number: &prNumber,
+	}
+	title := fmt.Sprintf("Test results of build #%s", activity.Spec.Build)
+	err = provider.AddPRComment(pr, summary.Markdown(title))
+	if err != nil {
+		return errors.Wrapf(err, "failed to comment on pull request %d", prNumber)
+	}
+	return nil
+}
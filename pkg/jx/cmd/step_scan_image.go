@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/cve"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepScanImageOptions contains the command line flags
+type StepScanImageOptions struct {
+	StepOptions
+
+	Image         string
+	Provider      string
+	URL           string
+	Reports       []string
+	Severity      string
+	Ignore        []string
+	IgnoreUnfixed bool
+	NoFail        bool
+}
+
+var (
+	stepScanImageLong = templates.LongDesc(`
+		Checks the vulnerabilities of an image and fails the build if any are at or above the given severity.
+
+		The vulnerabilities are read from the Trivy or Grype JSON reports produced earlier in the pipeline by default
+		or can be looked up in a Clair server or the anchore addon. The result of the scan is recorded as a Fact on the
+		PipelineActivity of the build so that 'jx promote' refuses to promote a build whose scan did not pass.
+
+		The severities are: ` + strings.Join(cve.Severities, ", ") + `
+`)
+
+	stepScanImageExample = templates.Examples(`
+		# fail the build if the Trivy report contains high or critical vulnerabilities
+		trivy image --format json --output trivy.json myorg/myapp:1.0.0
+		jx step scan image myorg/myapp:1.0.0 --report trivy.json
+
+		# only fail on critical vulnerabilities which have a fix available
+		jx step scan image myorg/myapp:1.0.0 --report grype.json --severity critical --ignore-unfixed
+
+		# look up an image in a Clair server
+		jx step scan image myorg/myapp@sha256:0123abcd... --provider clair --url http://clair:6060
+
+		# record the result without failing the build so that promotions are blocked instead
+		jx step scan image myorg/myapp:1.0.0 --report trivy.json --no-fail
+	`)
+)
+
+// NewCmdStepScanImage creates the command object for the "step scan image" command
+func NewCmdStepScanImage(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepScanImageOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "image [image]",
+		Short:   "Fails the build if an image has vulnerabilities at or above a severity",
+		Long:    stepScanImageLong,
+		Example: stepScanImageExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Image, "image", "i", "", "The image to scan")
+	cmd.Flags().StringVarP(&options.Provider, "provider", "p", cve.ProviderKindReport, "The CVE provider to use. One of: "+strings.Join(cve.ProviderKinds, ", "))
+	cmd.Flags().StringVarP(&options.URL, "url", "", "", "The URL of the Clair server")
+	cmd.Flags().StringArrayVarP(&options.Reports, "report", "r", nil, "The Trivy or Grype JSON report files of the image")
+	cmd.Flags().StringVarP(&options.Severity, "severity", "s", cve.SeverityHigh, "The minimum severity of the vulnerabilities which fail the build")
+	cmd.Flags().StringArrayVarP(&options.Ignore, "ignore", "", nil, "The IDs of vulnerabilities to ignore such as CVE-2019-1543")
+	cmd.Flags().BoolVarP(&options.IgnoreUnfixed, "ignore-unfixed", "", false, "Ignores vulnerabilities which do not have a fix available yet")
+	cmd.Flags().BoolVarP(&options.NoFail, "no-fail", "", false, "Records the result on the PipelineActivity without failing the build")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepScanImageOptions) Run() error {
+	if o.Image == "" && len(o.Args) > 0 {
+		o.Image = o.Args[0]
+	}
+	if o.Image == "" && o.Provider != cve.ProviderKindReport {
+		return util.MissingOption("image")
+	}
+	severity := cve.NormalizeSeverity(o.Severity)
+	if severity == cve.SeverityUnknown && !strings.EqualFold(o.Severity, cve.SeverityUnknown) {
+		return util.InvalidOption("severity", o.Severity, cve.Severities)
+	}
+
+	provider, err := o.createCVEProvider(o.Provider, o.URL, o.Reports)
+	if err != nil {
+		return err
+	}
+	scanner, ok := provider.(cve.ImageScanner)
+	if !ok {
+		return fmt.Errorf("the %s CVE provider cannot scan images", o.Provider)
+	}
+	vList, err := scanner.GetImageVulnerabilities(o.Image)
+	if err != nil {
+		return err
+	}
+	if vList.Image == "" {
+		return fmt.Errorf("the reports do not include the name of the image so please specify it as an argument")
+	}
+	result := cve.Scan(vList, o.Provider, severity, o.Ignore, o.IgnoreUnfixed)
+
+	err = o.addScanFact(result)
+	if err != nil {
+		log.Warnf("Failed to record the scan on the PipelineActivity: %s\n", err)
+	}
+
+	if result.Passed() {
+		log.Infof("Image %s has %d vulnerabilities but none at or above severity %s\n", util.ColorInfo(result.Image), len(result.Vulnerabilities), util.ColorInfo(severity))
+		return nil
+	}
+	table := o.CreateTable()
+	table.AddRow("Image", util.ColorInfo("Severity"), "Vulnerability", "URL", "Package", "Fix")
+	cve.AddVulnerabilityRows(&table, result.Image, result.Failures)
+	table.Render()
+
+	message := fmt.Sprintf("image %s has %d vulnerabilities at or above severity %s", result.Image, len(result.Failures), severity)
+	if o.NoFail {
+		log.Warnf("The %s\n", message)
+		return nil
+	}
+	return errors.New(message)
+}
+
+// addScanFact records the scan result as a Fact on the PipelineActivity of the current build
+func (o *StepScanImageOptions) addScanFact(result *cve.ScanResult) error {
+	pipeline := o.getJobName()
+	build := o.getBuildNumber()
+	if pipeline == "" || build == "" {
+		log.Warnf("No $JOB_NAME or $BUILD_NUMBER environment variables found so cannot record the scan on the PipelineActivity\n")
+		return nil
+	}
+	apisClient, err := o.CreateApiExtensionsClient()
+	if err != nil {
+		return err
+	}
+	err = kube.RegisterPipelineActivityCRD(apisClient)
+	if err != nil {
+		return err
+	}
+	jxClient, devNs, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	activities := jxClient.JenkinsV1().PipelineActivities(devNs)
+	key := &kube.PipelineActivityKey{
+		Name:     kube.ToValidName(pipeline + "-" + build),
+		Pipeline: pipeline,
+		Build:    build,
+	}
+	activity, _, err := key.GetOrCreate(activities)
+	if err != nil {
+		return err
+	}
+	cve.AddScanFact(activity, result)
+	_, err = activities.Update(activity)
+	return err
+}
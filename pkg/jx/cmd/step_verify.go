@@ -3,21 +3,33 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/config"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/jenkins-x/jx/pkg/verify"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
-const appLabel = "app"
+const (
+	appLabel = "app"
+
+	prometheusServerService = "prometheus-server"
+
+	defaultVerifyTimeout  = 5 * time.Minute
+	defaultVerifyInterval = 10 * time.Second
+)
 
 type StepVerifyOptions struct {
 	StepOptions
@@ -25,15 +37,27 @@ type StepVerifyOptions struct {
 	After    int32
 	Pods     int32
 	Restarts int32
+	Rollback bool
+	Dir      string
 }
 
 var (
 	StepVerifyLong = templates.LongDesc(`
 		This pipeline step performs deployment verification
+
+		It checks that the pods of the application are running and then performs the checks in the 'verify' section of
+		the 'jenkins-x.yml' file such as HTTP probes, Prometheus queries and smoke test Jobs. Each check is retried until
+		it passes or its timeout expires and its result is recorded as a Fact on the PipelineActivity
 	`)
 
 	StepVerifyExample = templates.Examples(`
 		jx step verify
+
+		# rollback the environment to the previous version if the verification fails
+		jx step verify --rollback
+
+		# verify using the checks in the jenkins-x.yml file of a directory
+		jx step verify --dir ./myapp
 	`)
 )
 
@@ -62,16 +86,20 @@ func NewCmdStepVerify(f Factory, in terminal.FileReader, out terminal.FileWriter
 		},
 	}
 
-	cmd.Flags().Int32VarP(&options.After, "after", "", 60, "The time in seconds after which the application should be ready")
+	cmd.Flags().Int32VarP(&options.After, "after", "", 0, "The time in seconds to wait before starting the verification. The checks are retried until their timeout expires so this only delays the first attempt")
 	cmd.Flags().Int32VarP(&options.Pods, "pods", "p", 1, "Number of expected pods to be running")
 	cmd.Flags().Int32VarP(&options.Restarts, "restarts", "r", 0, "Maximum number of restarts which are acceptable within the given time")
+	cmd.Flags().BoolVarP(&options.Rollback, "rollback", "", false, "Rollback the Environment to the previous version of the application if the verification fails")
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", ".", "The directory containing the 'jenkins-x.yml' file with the verification checks")
 
 	return cmd
 }
 
 func (o *StepVerifyOptions) Run() error {
-	// Wait for the given time to exceed before starting the verification
-	time.Sleep(time.Duration(o.After) * time.Second)
+	// the checks are retried until their timeout so only wait if an initial delay was explicitly requested
+	if o.After > 0 {
+		time.Sleep(time.Duration(o.After) * time.Second)
+	}
 
 	apisClient, err := o.CreateApiExtensionsClient()
 	if err != nil {
@@ -107,11 +135,56 @@ func (o *StepVerifyOptions) Run() error {
 		return errors.Wrap(err, "failed to determine the application name and namespace from pipeline activity")
 	}
 
-	log.Infof("Verifying if app '%s' is running in namespace '%s'", app, ns)
+	verifyConfig, err := o.loadVerifyConfig()
+	if err != nil {
+		return err
+	}
+	timeout, interval, err := verifyDurations(verifyConfig)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Verifying if app '%s' is running in namespace '%s'\n", app, ns)
+	results := []*verify.Result{
+		verify.Retry(timeout, interval, func() *verify.Result {
+			return o.verifyPods(kubeClient, app, ns)
+		}),
+	}
+	if results[0].Passed && verifyConfig != nil {
+		results = append(results, o.runVerifyChecks(kubeClient, verifyConfig, activity, app, ns, devNs, timeout, interval)...)
+	}
+	verify.AddFacts(activity, results)
+
+	failures := []string{}
+	for _, result := range results {
+		if result.Passed {
+			log.Infof("Check %s passed: %s\n", util.ColorInfo(result.Name), result.Message)
+		} else {
+			log.Warnf("Check %s failed after %d attempts: %s\n", result.Name, result.Attempts, result.Message)
+			failures = append(failures, result.Message)
+		}
+	}
+	if len(failures) > 0 {
+		return o.verificationFailed(activity, errors.New(strings.Join(failures, "\n")))
+	}
 
+	err = o.updatePipelineActivity(activity, v1.ActivityStatusTypeSucceeded)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// verifyPods checks that the expected number of pods of the application are running without too many restarts
+func (o *StepVerifyOptions) verifyPods(kubeClient kubernetes.Interface, app string, ns string) *verify.Result {
+	result := &verify.Result{
+		Name: verify.KindPods,
+		Kind: verify.KindPods,
+	}
 	pods, err := kubeClient.CoreV1().Pods(ns).List(metav1.ListOptions{})
 	if err != nil {
-		return errors.Wrapf(err, "failed to list the PODs in namespace '%s'", ns)
+		result.Message = fmt.Sprintf("failed to list the PODs in namespace '%s': %s", ns, err)
+		return result
 	}
 
 	var foundPods int32
@@ -129,40 +202,149 @@ func (o *StepVerifyOptions) Run() error {
 					if restarts < o.Restarts {
 						continue
 					} else {
-						err = o.updatePipelineActivity(activity, v1.ActivityStatusTypeFailed)
-						if err != nil {
-							return err
-						}
-						return fmt.Errorf("pod '%s' is '%s' and was restarted '%d', which exceeds max number of restarts '%d'",
+						result.Message = fmt.Sprintf("pod '%s' is '%s' and was restarted '%d', which exceeds max number of restarts '%d'",
 							pod.Name, pod.Status.Phase, restarts, o.Restarts)
+						return result
 					}
 				} else {
 					if restarts > o.Restarts {
-						err = o.updatePipelineActivity(activity, v1.ActivityStatusTypeFailed)
-						if err != nil {
-							return err
-						}
-						return fmt.Errorf("pod '%s' is running but was restarted '%d', which exceeds max number of restarts '%d'",
+						result.Message = fmt.Sprintf("pod '%s' is running but was restarted '%d', which exceeds max number of restarts '%d'",
 							pod.Name, restarts, o.Restarts)
+						return result
 					}
 				}
 			}
 		}
 	}
+	result.Measurements = append(result.Measurements, v1.Measurement{
+		Name:             verify.KindPods,
+		MeasurementType:  v1.MeasurementCount,
+		MeasurementValue: int(foundPods),
+	})
 
 	if foundPods != o.Pods {
-		err = o.updatePipelineActivity(activity, v1.ActivityStatusTypeFailed)
+		result.Message = fmt.Sprintf("found '%d' pods running but expects '%d'", foundPods, o.Pods)
+		return result
+	}
+	result.Passed = true
+	result.Message = fmt.Sprintf("found '%d' pods running", foundPods)
+	return result
+}
+
+// loadVerifyConfig loads the verification checks from the project configuration if there are any
+func (o *StepVerifyOptions) loadVerifyConfig() (*config.VerifyConfig, error) {
+	projectConfig, fileName, err := config.LoadProjectConfig(o.Dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load the project configuration %s", fileName)
+	}
+	return projectConfig.Verify, nil
+}
+
+// verifyDurations returns how long the checks are retried for and the interval between attempts. The defaults are
+// used when the project has no verify configuration so that the pods of a new deployment have time to become ready
+func verifyDurations(verifyConfig *config.VerifyConfig) (time.Duration, time.Duration, error) {
+	timeout := defaultVerifyTimeout
+	interval := defaultVerifyInterval
+	if verifyConfig == nil {
+		return timeout, interval, nil
+	}
+	var err error
+	if verifyConfig.Timeout != "" {
+		timeout, err = time.ParseDuration(verifyConfig.Timeout)
+		if err != nil {
+			return timeout, interval, errors.Wrapf(err, "invalid verify timeout %s", verifyConfig.Timeout)
+		}
+	}
+	if verifyConfig.Interval != "" {
+		interval, err = time.ParseDuration(verifyConfig.Interval)
 		if err != nil {
-			return err
+			return timeout, interval, errors.Wrapf(err, "invalid verify interval %s", verifyConfig.Interval)
 		}
-		return fmt.Errorf("found '%d' pods running but expects '%d'", foundPods, o.Pods)
 	}
+	return timeout, interval, nil
+}
 
-	err = o.updatePipelineActivity(activity, v1.ActivityStatusTypeSucceeded)
-	if err != nil {
-		return err
+// runVerifyChecks runs each of the configured checks retrying them until they pass or their timeout expires
+func (o *StepVerifyOptions) runVerifyChecks(kubeClient kubernetes.Interface, verifyConfig *config.VerifyConfig, activity *v1.PipelineActivity,
+	app string, ns string, devNs string, timeout time.Duration, interval time.Duration) []*verify.Result {
+	appURL := activityApplicationURL(activity)
+	prometheusURL := fmt.Sprintf("http://%s.%s", prometheusServerService, devNs)
+	envVars := []corev1.EnvVar{
+		{
+			Name:  "APP_NAME",
+			Value: app,
+		},
+		{
+			Name:  "APP_URL",
+			Value: appURL,
+		},
+		{
+			Name:  "NAMESPACE",
+			Value: ns,
+		},
+		{
+			Name:  "VERSION",
+			Value: activity.Spec.Version,
+		},
 	}
-	return nil
+
+	results := []*verify.Result{}
+	for i, check := range verifyConfig.Checks {
+		name := verify.CheckName(check, i)
+		checkTimeout := timeout
+		if check.Timeout != "" {
+			d, err := time.ParseDuration(check.Timeout)
+			if err != nil {
+				results = append(results, &verify.Result{
+					Name:    name,
+					Message: fmt.Sprintf("invalid timeout %s for check %s: %s", check.Timeout, name, err),
+				})
+				continue
+			}
+			checkTimeout = d
+		}
+		log.Infof("Running check %s\n", util.ColorInfo(name))
+		var result *verify.Result
+		switch verify.CheckKind(check) {
+		case verify.KindHTTP:
+			result = verify.Retry(checkTimeout, interval, func() *verify.Result {
+				return verify.CheckHTTP(name, check.HTTP, appURL)
+			})
+		case verify.KindPrometheus:
+			result = verify.Retry(checkTimeout, interval, func() *verify.Result {
+				return verify.CheckPrometheus(name, check.Prometheus, prometheusURL)
+			})
+		case verify.KindJob:
+			jobTimeout := checkTimeout
+			if jobTimeout == 0 {
+				jobTimeout = defaultVerifyTimeout
+			}
+			result = verify.Retry(0, 0, func() *verify.Result {
+				return verify.CheckJob(kubeClient, ns, name, check.Job, envVars, jobTimeout)
+			})
+		default:
+			result = &verify.Result{
+				Name:    name,
+				Message: fmt.Sprintf("check %s must specify one of http, prometheus or job", name),
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// activityApplicationURL returns the URL of the application the activity promoted or previewed
+func activityApplicationURL(activity *v1.PipelineActivity) string {
+	answer := ""
+	for _, step := range activity.Spec.Steps {
+		if step.Promote != nil && step.Promote.ApplicationURL != "" {
+			answer = step.Promote.ApplicationURL
+		}
+		if step.Preview != nil && step.Preview.ApplicationURL != "" {
+			answer = step.Preview.ApplicationURL
+		}
+	}
+	return answer
 }
 
 func (o *StepVerifyOptions) detectPipelineActivity(jxClient versioned.Interface, namespace string) (*v1.PipelineActivity, error) {
@@ -218,6 +400,50 @@ func (o *StepVerifyOptions) determineAppAndNamespace(kubeClient kubernetes.Inter
 	return "", "", fmt.Errorf("could not determine the application name and namespace from activity '%s'", activity.Name)
 }
 
+// verificationFailed marks the activity as failed and rolls back the promotion if required returning the verification error
+func (o *StepVerifyOptions) verificationFailed(activity *v1.PipelineActivity, verifyErr error) error {
+	err := o.updatePipelineActivity(activity, v1.ActivityStatusTypeFailed)
+	if err != nil {
+		return err
+	}
+	if o.Rollback {
+		err = o.rollback(activity)
+		if err != nil {
+			log.Warnf("Failed to rollback: %s\n", err)
+		}
+	}
+	return verifyErr
+}
+
+// rollback rolls back the environment the activity promoted to so that it uses the previous version of the application
+func (o *StepVerifyOptions) rollback(activity *v1.PipelineActivity) error {
+	envName := ""
+	for _, step := range activity.Spec.Steps {
+		if step.Promote != nil {
+			envName = step.Promote.Environment
+		}
+	}
+	if envName == "" {
+		log.Infof("PipelineActivity %s has not promoted to an Environment so there is nothing to rollback\n", activity.Name)
+		return nil
+	}
+	options := &RollbackAppOptions{
+		PromoteOptions: PromoteOptions{
+			CommonOptions:       o.CommonOptions,
+			Application:         activity.RepositoryName(),
+			Environment:         envName,
+			Pipeline:            activity.Spec.Pipeline,
+			Build:               activity.Spec.Build,
+			IgnoreLocalFiles:    true,
+			Timeout:             "1h",
+			PullRequestPollTime: "20s",
+		},
+		FromVersion: activity.Spec.Version,
+	}
+	options.BatchMode = true
+	return options.Run()
+}
+
 func (o *StepVerifyOptions) updatePipelineActivity(activity *v1.PipelineActivity, status v1.ActivityStatusType) error {
 	apisClient, err := o.CreateApiExtensionsClient()
 	if err != nil {
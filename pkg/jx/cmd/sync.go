@@ -12,8 +12,10 @@ import (
 
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 
+	"github.com/jenkins-x/jx/pkg/filesync"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
@@ -36,6 +38,12 @@ type SyncOptions struct {
 	Reload    bool
 	WatchOnly bool
 
+	Ksync        bool
+	Includes     []string
+	Excludes     []string
+	PollInterval time.Duration
+	Username     string
+
 	stopCh chan struct{}
 }
 
@@ -43,6 +51,12 @@ var (
 	sync_long = templates.LongDesc(`
 		Synchronises your local files to a DevPod so you an build and test your code easily on the cloud
 
+		Files are synchronised in both directions over the Kubernetes exec API so that files generated in the DevPod
+		are copied back. Files ignored by .gitignore are not synchronised unless they match an --include pattern. Files
+		changed both locally and in the DevPod since the last synchronisation are reported as conflicts and left alone.
+
+		Use --ksync to synchronise with ksync instead which requires the ksync DaemonSet in the kube-system namespace.
+
 		For more documentation see: [https://jenkins-x.io/developing/devpods/](https://jenkins-x.io/developing/devpods/)
 
 `)
@@ -50,6 +64,9 @@ var (
 	sync_example = templates.Examples(`
 		# Starts synchronizing the current directory files to the users DevPod
 		jx sync 
+
+		# Also synchronise the generated sources from the DevPod which are ignored by .gitignore
+		jx sync --include "target/generated-sources/**"
 `)
 
 	defaultStignoreFile = `.git
@@ -85,13 +102,16 @@ func NewCmdSync(f Factory, in terminal.FileReader, out terminal.FileWriter, errO
 			CheckErr(err)
 		},
 	}
-	/*	cmd.Flags().StringVarP(&options.Container, "container", "c", "", "The name of the container to log")
-		cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "the namespace to look for the Deployment. Defaults to the current namespace")
-		cmd.Flags().StringVarP(&options.Pod, "pod", "p", "", "the pod name to use")
-		cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "The directory to watch. Defaults to the current directory")
-		cmd.Flags().StringVarP(&options.RemoteDir, "remote-dir", "r", "", "The remote directory in the DevPod to sync")
-		cmd.Flags().BoolVarP(&options.Reload, "reload", "", false, "Should we reload the remote container on file changes?")
-	*/
+	cmd.Flags().StringVarP(&options.Container, "container", "c", "", "The name of the DevPod container to sync. Defaults to the first container")
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace of the DevPod. Defaults to the dev namespace")
+	cmd.Flags().StringVarP(&options.Pod, "pod", "p", "", "The name of the DevPod. Defaults to the DevPod of the current user created with --sync for the directory")
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "The directory to sync. Defaults to the current directory")
+	cmd.Flags().StringVarP(&options.RemoteDir, "remote-dir", "r", "", "The directory in the DevPod to sync. Defaults to the working directory of the DevPod")
+	cmd.Flags().StringVarP(&options.Username, "username", "", "", "The username of the DevPod. If not specified defaults to the current operating system user or $USER'")
+	cmd.Flags().StringArrayVarP(&options.Includes, "include", "i", []string{}, "Patterns in the .gitignore syntax of files to sync even if they are ignored, such as generated sources")
+	cmd.Flags().StringArrayVarP(&options.Excludes, "exclude", "e", []string{}, "Patterns in the .gitignore syntax of files not to sync in addition to the .gitignore files")
+	cmd.Flags().DurationVarP(&options.PollInterval, "poll", "", filesync.DefaultPollInterval, "How often the DevPod is checked for changed files")
+	cmd.Flags().BoolVarP(&options.Ksync, "ksync", "", false, "Uses ksync to sync the files rather than the Kubernetes exec API")
 	cmd.Flags().BoolVarP(&options.Daemon, "daemon", "", false, "Runs ksync in a background daemon")
 	cmd.Flags().BoolVarP(&options.NoKsyncInit, "no-init", "", false, "Disables the use of 'ksync init' to ensure we have initialised ksync")
 	cmd.Flags().BoolVarP(&options.SingleMode, "single-mode", "", false, "Terminates eagerly if `ksync watch` fails")
@@ -102,6 +122,9 @@ func NewCmdSync(f Factory, in terminal.FileReader, out terminal.FileWriter, errO
 }
 
 func (o *SyncOptions) Run() error {
+	if !o.Ksync {
+		return o.runNativeSync()
+	}
 
 	// ksync is installed to the jx/bin dir, so we can add it for the user
 	os.Setenv("PATH", util.PathWithBinary())
@@ -261,3 +284,128 @@ func (o *SyncOptions) killWatchProcess(cmd *exec.Cmd) {
 		log.Warnf("failed to kill 'ksync watch' process: %s\n", err)
 	}
 }
+
+// runNativeSync synchronises the directory with the DevPod until the process is terminated
+func (o *SyncOptions) runNativeSync() error {
+	client, curNs, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	ns := o.Namespace
+	if ns == "" {
+		ns, _, err = kube.GetDevNamespace(client, curNs)
+		if err != nil {
+			return err
+		}
+	}
+	dir := o.Dir
+	if dir == "" {
+		dir, err = os.Getwd()
+		if err != nil {
+			return err
+		}
+	}
+	pod, err := o.findSyncDevPod(client, ns, dir)
+	if err != nil {
+		return err
+	}
+	remoteDir := o.RemoteDir
+	if remoteDir == "" {
+		remoteDir = pod.Annotations[kube.AnnotationWorkingDir]
+	}
+	if remoteDir == "" {
+		return util.MissingOption("remote-dir")
+	}
+	return o.NativeSync(client, ns, pod.Name, dir, remoteDir, nil)
+}
+
+// findSyncDevPod returns the pod of the option or the DevPod of the user which was created to sync the directory
+func (o *SyncOptions) findSyncDevPod(client kubernetes.Interface, ns string, dir string) (*corev1.Pod, error) {
+	if o.Pod != "" {
+		return client.CoreV1().Pods(ns).Get(o.Pod, metav1.GetOptions{})
+	}
+	userName, err := o.getUsername(o.Username)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: map[string]string{
+		kube.LabelDevPodUsername: userName,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := client.CoreV1().Pods(ns).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp == nil && pod.Annotations[kube.AnnotationLocalDir] == dir {
+			return pod, nil
+		}
+	}
+	return nil, fmt.Errorf("no DevPod found in namespace %s syncing directory %s. Please run 'jx create devpod --sync'", ns, dir)
+}
+
+// NativeSync synchronises the directory with the remote directory of the pod over the Kubernetes exec API. The
+// first synchronisation completes before it returns if the stop channel is not nil and the files are then
+// synchronised in the background until the stop channel is closed. Otherwise the files are synchronised until the
+// process is terminated
+func (o *SyncOptions) NativeSync(client kubernetes.Interface, ns string, podName string, dir string, remoteDir string, stop <-chan struct{}) error {
+	config, err := o.Factory.CreateKubeConfig()
+	if err != nil {
+		return err
+	}
+	container := o.Container
+	if container == "" {
+		pod, err := client.CoreV1().Pods(ns).Get(podName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if len(pod.Spec.Containers) == 0 {
+			return fmt.Errorf("pod %s has no containers", podName)
+		}
+		container = pod.Spec.Containers[0].Name
+	}
+	matcher, err := filesync.NewMatcher(dir, o.Includes, o.Excludes)
+	if err != nil {
+		return err
+	}
+	remote := &filesync.PodRemote{
+		KubeClient: client,
+		Config:     config,
+		Namespace:  ns,
+		Pod:        podName,
+		Container:  container,
+		Dir:        remoteDir,
+	}
+	syncer := filesync.NewSyncer(dir, matcher, remote)
+	if o.PollInterval > 0 {
+		syncer.PollInterval = o.PollInterval
+	}
+	lastActive := time.Time{}
+	syncer.OnChange = func(plan *filesync.Plan) {
+		if time.Since(lastActive) > devPodActivityInterval {
+			lastActive = time.Now()
+			o.markDevPodActive(client, ns, podName)
+		}
+	}
+
+	info := util.ColorInfo
+	log.Infof("synchronizing directory %s to DevPod %s path %s\n", info(dir), info(podName), info(remoteDir))
+	plan, err := syncer.Sync()
+	if err != nil {
+		return err
+	}
+	log.Infof("Synchronised %s: %s\n", info(dir), plan.String())
+	if stop != nil {
+		go func() {
+			err := syncer.Watch(stop)
+			if err != nil {
+				log.Warnf("Failed to watch %s: %s\n", dir, err)
+			}
+		}()
+		return nil
+	}
+	return syncer.Watch(make(chan struct{}))
+}
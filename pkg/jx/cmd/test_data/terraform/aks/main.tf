@@ -0,0 +1,45 @@
+provider "azurerm" {
+  version = "~> 1.19"
+}
+
+resource "azurerm_resource_group" "cluster" {
+  name     = "${var.aks_resource_group}"
+  location = "${var.aks_location}"
+
+  tags {
+    created_by        = "${var.created_by}"
+    created_timestamp = "${var.created_timestamp}"
+    organisation      = "${var.organisation}"
+  }
+}
+
+resource "azurerm_kubernetes_cluster" "jx" {
+  name                = "${var.cluster_name}"
+  location            = "${azurerm_resource_group.cluster.location}"
+  resource_group_name = "${azurerm_resource_group.cluster.name}"
+  dns_prefix          = "${var.cluster_name}"
+  kubernetes_version  = "${var.kubernetes_version}"
+
+  agent_pool_profile {
+    name            = "default"
+    count           = "${var.min_node_count}"
+    vm_size         = "${var.node_vm_size}"
+    os_type         = "Linux"
+    os_disk_size_gb = "${var.node_disk_size}"
+  }
+
+  service_principal {
+    client_id     = "${var.client_id}"
+    client_secret = "${var.client_secret}"
+  }
+
+  role_based_access_control {
+    enabled = true
+  }
+
+  tags {
+    created_by        = "${var.created_by}"
+    created_timestamp = "${var.created_timestamp}"
+    organisation      = "${var.organisation}"
+  }
+}
@@ -0,0 +1,11 @@
+output "cluster_name" {
+  value = "${azurerm_kubernetes_cluster.jx.name}"
+}
+
+output "resource_group" {
+  value = "${azurerm_resource_group.cluster.name}"
+}
+
+output "host" {
+  value = "${azurerm_kubernetes_cluster.jx.kube_config.0.host}"
+}
@@ -0,0 +1,9 @@
+terraform {
+  required_version = ">= 0.11.0"
+  backend "azurerm" {
+    resource_group_name  = "jx-myorg-terraform-state"
+    storage_account_name = "jxmyorgstate"
+    container_name       = "terraform-state"
+    key                  = "dev.tfstate"
+  }
+}
\ No newline at end of file
@@ -0,0 +1,41 @@
+variable "created_by" {}
+
+variable "created_timestamp" {}
+
+variable "cluster_name" {}
+
+variable "organisation" {}
+
+variable "provider" {}
+
+variable "aks_location" {}
+
+variable "aks_resource_group" {}
+
+variable "node_vm_size" {
+  default = "Standard_D2s_v3"
+}
+
+variable "min_node_count" {
+  default = "3"
+}
+
+variable "max_node_count" {
+  default = "5"
+}
+
+variable "node_disk_size" {
+  default = "100"
+}
+
+variable "kubernetes_version" {
+  default = "1.11.5"
+}
+
+variable "client_id" {
+  description = "The client id of the Azure service principal used by the cluster. Pass with -var or TF_VAR_client_id"
+}
+
+variable "client_secret" {
+  description = "The client secret of the Azure service principal used by the cluster. Pass with -var or TF_VAR_client_secret"
+}
@@ -0,0 +1,51 @@
+provider "aws" {
+  version = "~> 1.52"
+  region  = "${var.aws_region}"
+}
+
+data "aws_availability_zones" "available" {}
+
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "1.50.0"
+
+  name               = "${var.cluster_name}"
+  cidr               = "10.0.0.0/16"
+  azs                = ["${data.aws_availability_zones.available.names[0]}", "${data.aws_availability_zones.available.names[1]}", "${data.aws_availability_zones.available.names[2]}"]
+  public_subnets     = ["10.0.1.0/24", "10.0.2.0/24", "10.0.3.0/24"]
+  enable_nat_gateway = false
+
+  tags = {
+    "kubernetes.io/cluster/${var.cluster_name}" = "shared"
+    created_by                                  = "${var.created_by}"
+    created_timestamp                           = "${var.created_timestamp}"
+    organisation                                = "${var.organisation}"
+  }
+}
+
+module "eks" {
+  source  = "terraform-aws-modules/eks/aws"
+  version = "1.8.0"
+
+  cluster_name     = "${var.cluster_name}"
+  cluster_version  = "${var.kubernetes_version}"
+  subnets          = ["${module.vpc.public_subnets}"]
+  vpc_id           = "${module.vpc.vpc_id}"
+  write_kubeconfig = false
+
+  worker_groups = [
+    {
+      instance_type        = "${var.node_instance_type}"
+      asg_min_size         = "${var.min_node_count}"
+      asg_desired_capacity = "${var.min_node_count}"
+      asg_max_size         = "${var.max_node_count}"
+      root_volume_size     = "${var.node_disk_size}"
+    },
+  ]
+
+  tags = {
+    created_by        = "${var.created_by}"
+    created_timestamp = "${var.created_timestamp}"
+    organisation      = "${var.organisation}"
+  }
+}
@@ -0,0 +1,7 @@
+output "cluster_name" {
+  value = "${module.eks.cluster_id}"
+}
+
+output "cluster_endpoint" {
+  value = "${module.eks.cluster_endpoint}"
+}
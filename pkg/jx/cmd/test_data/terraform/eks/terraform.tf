@@ -0,0 +1,8 @@
+terraform {
+  required_version = ">= 0.11.0"
+  backend "s3" {
+    bucket = "jx-myorg-terraform-state"
+    key    = "dev/terraform.tfstate"
+    region = "us-west-2"
+  }
+}
\ No newline at end of file
@@ -0,0 +1,31 @@
+variable "created_by" {}
+
+variable "created_timestamp" {}
+
+variable "cluster_name" {}
+
+variable "organisation" {}
+
+variable "provider" {}
+
+variable "aws_region" {}
+
+variable "node_instance_type" {
+  default = "m5.large"
+}
+
+variable "min_node_count" {
+  default = "3"
+}
+
+variable "max_node_count" {
+  default = "5"
+}
+
+variable "node_disk_size" {
+  default = "100"
+}
+
+variable "kubernetes_version" {
+  default = "1.10"
+}
@@ -32,6 +32,9 @@ var (
 
 		jx update cluster gke
 
+		# re-apply the Terraform module of a cluster created by jx create terraform
+		jx update cluster aks terraform -o myorg -n dev
+
 `)
 )
 
@@ -54,6 +57,8 @@ func NewCmdUpdateCluster(f Factory, in terminal.FileReader, out terminal.FileWri
 	}
 
 	cmd.AddCommand(NewCmdUpdateClusterGKE(f, in, out, errOut))
+	cmd.AddCommand(NewCmdUpdateClusterAKS(f, in, out, errOut))
+	cmd.AddCommand(NewCmdUpdateClusterEKS(f, in, out, errOut))
 
 	return cmd
 }
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// UpdateClusterAKSOptions the options for updating an AKS cluster
+type UpdateClusterAKSOptions struct {
+	UpdateClusterOptions
+}
+
+// UpdateClusterAKSTerraformOptions the options for updating an AKS cluster created by jx create terraform
+type UpdateClusterAKSTerraformOptions struct {
+	UpdateClusterTerraformOptions
+
+	ClientID     string
+	ClientSecret string
+}
+
+var (
+	updateClusterAKSTerraformLong = templates.LongDesc(`
+
+		Command re-applies the Terraform module generated by 'jx create terraform' in clusters/<cluster>/terraform
+		of the organisation repository against the specified AKS cluster
+
+`)
+
+	updateClusterAKSTerraformExample = templates.Examples(`
+
+		jx update cluster aks terraform -o myorg -n dev
+
+`)
+)
+
+// NewCmdUpdateClusterAKS creates the command for updating an AKS cluster
+func NewCmdUpdateClusterAKS(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &UpdateClusterAKSOptions{
+		UpdateClusterOptions: createUpdateClusterOptions(f, in, out, errOut, AKS),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "aks",
+		Short: "Updates an existing Kubernetes cluster on AKS: Runs on Azure",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(NewCmdUpdateClusterAKSTerraform(f, in, out, errOut))
+
+	return cmd
+}
+
+// Run implements this command
+func (o *UpdateClusterAKSOptions) Run() error {
+	return o.Cmd.Help()
+}
+
+// NewCmdUpdateClusterAKSTerraform creates the command for updating an AKS cluster created by jx create terraform
+func NewCmdUpdateClusterAKSTerraform(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &UpdateClusterAKSTerraformOptions{
+		UpdateClusterTerraformOptions: createUpdateClusterTerraformOptions(f, in, out, errOut, AKS),
+	}
+
+	cmd := &cobra.Command{
+		Use:     "terraform",
+		Short:   "Updates an existing Kubernetes cluster on AKS using Terraform: Runs on Azure",
+		Long:    updateClusterAKSTerraformLong,
+		Example: updateClusterAKSTerraformExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addFlags(cmd)
+	cmd.Flags().BoolVarP(&options.Flags.SkipLogin, "skip-login", "", false, "Skip login if already logged in using `az login`")
+	cmd.Flags().StringVarP(&options.ClientID, "client-id", "", "", "The client ID of the Azure service principal used by the cluster. Can also be set with $TF_VAR_client_id")
+	cmd.Flags().StringVarP(&options.ClientSecret, "client-secret", "", "", "The client secret of the Azure service principal used by the cluster. Can also be set with $TF_VAR_client_secret")
+
+	return cmd
+}
+
+// Run implements this command
+func (o *UpdateClusterAKSTerraformOptions) Run() error {
+	err := o.installRequirements(AKS, "terraform")
+	if err != nil {
+		return err
+	}
+	if !o.Flags.SkipLogin {
+		err = o.runCommandVerbose("az", "login")
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.ClientID != "" {
+		os.Setenv("TF_VAR_client_id", o.ClientID)
+	}
+	if o.ClientSecret != "" {
+		os.Setenv("TF_VAR_client_secret", o.ClientSecret)
+	}
+	return o.updateClusterTerraform()
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// UpdateClusterEKSOptions the options for updating an EKS cluster
+type UpdateClusterEKSOptions struct {
+	UpdateClusterOptions
+}
+
+// UpdateClusterEKSTerraformOptions the options for updating an EKS cluster created by jx create terraform
+type UpdateClusterEKSTerraformOptions struct {
+	UpdateClusterTerraformOptions
+}
+
+var (
+	updateClusterEKSTerraformLong = templates.LongDesc(`
+
+		Command re-applies the Terraform module generated by 'jx create terraform' in clusters/<cluster>/terraform
+		of the organisation repository against the specified EKS cluster
+
+`)
+
+	updateClusterEKSTerraformExample = templates.Examples(`
+
+		jx update cluster eks terraform -o myorg -n dev
+
+`)
+)
+
+// NewCmdUpdateClusterEKS creates the command for updating an EKS cluster
+func NewCmdUpdateClusterEKS(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &UpdateClusterEKSOptions{
+		UpdateClusterOptions: createUpdateClusterOptions(f, in, out, errOut, EKS),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "eks",
+		Short: "Updates an existing Kubernetes cluster on EKS: Runs on Amazon Web Services",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(NewCmdUpdateClusterEKSTerraform(f, in, out, errOut))
+
+	return cmd
+}
+
+// Run implements this command
+func (o *UpdateClusterEKSOptions) Run() error {
+	return o.Cmd.Help()
+}
+
+// NewCmdUpdateClusterEKSTerraform creates the command for updating an EKS cluster created by jx create terraform
+func NewCmdUpdateClusterEKSTerraform(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &UpdateClusterEKSTerraformOptions{
+		UpdateClusterTerraformOptions: createUpdateClusterTerraformOptions(f, in, out, errOut, EKS),
+	}
+
+	cmd := &cobra.Command{
+		Use:     "terraform",
+		Short:   "Updates an existing Kubernetes cluster on EKS using Terraform: Runs on Amazon Web Services",
+		Long:    updateClusterEKSTerraformLong,
+		Example: updateClusterEKSTerraformExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addFlags(cmd)
+
+	return cmd
+}
+
+// Run implements this command
+func (o *UpdateClusterEKSTerraformOptions) Run() error {
+	err := o.installRequirements(EKS, "terraform", "aws")
+	if err != nil {
+		return err
+	}
+	return o.updateClusterTerraform()
+}
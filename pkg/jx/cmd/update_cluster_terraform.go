@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/terraform"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// UpdateClusterTerraformOptions the options for updating a cluster created by jx create terraform
+type UpdateClusterTerraformOptions struct {
+	UpdateClusterOptions
+
+	Flags UpdateClusterTerraformFlags
+}
+
+// UpdateClusterTerraformFlags the flags for updating a cluster created by jx create terraform
+type UpdateClusterTerraformFlags struct {
+	ClusterName             string
+	OrganisationName        string
+	Dir                     string
+	SkipLogin               bool
+	IgnoreTerraformWarnings bool
+}
+
+func createUpdateClusterTerraformOptions(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer, cloudProvider string) UpdateClusterTerraformOptions {
+	commonOptions := CommonOptions{
+		Factory: f,
+		In:      in,
+		Out:     out,
+		Err:     errOut,
+	}
+	options := UpdateClusterTerraformOptions{
+		UpdateClusterOptions: UpdateClusterOptions{
+			UpdateOptions: UpdateOptions{
+				CommonOptions: commonOptions,
+			},
+			Provider: cloudProvider,
+		},
+	}
+	return options
+}
+
+func (o *UpdateClusterTerraformOptions) addFlags(cmd *cobra.Command) {
+	o.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&o.Flags.ClusterName, optionClusterName, "n", "", "The name of the cluster in the organisation repository")
+	cmd.Flags().StringVarP(&o.Flags.OrganisationName, "organisation-name", "o", "", "The organisation name of the cluster, the repo of which is organisation-<org name>")
+	cmd.Flags().StringVarP(&o.Flags.Dir, "dir", "d", "", "The directory of the Terraform module of the cluster. Defaults to clusters/<name>/terraform in the local clone of the organisation repository")
+	cmd.Flags().BoolVarP(&o.Flags.IgnoreTerraformWarnings, "ignore-terraform-warnings", "", false, "Ignore any warnings about the Terraform plan being potentially destructive")
+}
+
+// terraformDir returns the directory of the Terraform module of the cluster
+func (o *UpdateClusterTerraformOptions) terraformDir() (string, error) {
+	if o.Flags.Dir != "" {
+		return o.Flags.Dir, nil
+	}
+	if o.Flags.ClusterName == "" {
+		return "", util.MissingOption(optionClusterName)
+	}
+	if o.Flags.OrganisationName == "" {
+		return "", util.MissingOption("organisation-name")
+	}
+	organisationDir, err := util.OrganisationsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(organisationDir, fmt.Sprintf("organisation-%s", o.Flags.OrganisationName), Clusters, o.Flags.ClusterName, Terraform), nil
+}
+
+// updateClusterTerraform re-applies the Terraform module of a cluster created by jx create terraform after
+// checking that it was generated for the provider of the command
+func (o *UpdateClusterTerraformOptions) updateClusterTerraform() error {
+	dir, err := o.terraformDir()
+	if err != nil {
+		return err
+	}
+	terraformVars := filepath.Join(dir, "terraform.tfvars")
+	exists, err := util.FileExists(terraformVars)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("unable to find the Terraform variables %s", terraformVars)
+	}
+	provider, err := terraform.ReadValueFromFile(terraformVars, "provider")
+	if err != nil {
+		return err
+	}
+	if provider != o.Provider {
+		return fmt.Errorf("the cluster in %s was created for the provider %s, use: jx update cluster %s terraform", dir, provider, provider)
+	}
+
+	if !o.BatchMode {
+		surveyOpts := survey.WithStdio(o.In, o.Out, o.Err)
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Updating a %s cluster with Terraform is an experimental feature in jx.  Would you like to continue?", o.Provider),
+		}
+		survey.AskOne(prompt, &confirm, nil, surveyOpts)
+
+		if !confirm {
+			// exit at this point
+			return nil
+		}
+	}
+
+	log.Infof("Updating the cluster using the Terraform module in %s\n", util.ColorInfo(dir))
+	createOptions := &CreateTerraformOptions{
+		CreateOptions: CreateOptions{
+			CommonOptions: o.CommonOptions,
+		},
+		Flags: Flags{
+			IgnoreTerraformWarnings: o.Flags.IgnoreTerraformWarnings,
+		},
+	}
+	_, err = createOptions.planAndApplyTerraform(dir, "")
+	return err
+}
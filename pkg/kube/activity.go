@@ -42,6 +42,7 @@ type PromoteStepActivityKey struct {
 
 type PromotePullRequestFn func(*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.PromoteActivityStep, *v1.PromotePullRequestStep) error
 type PromoteUpdateFn func(*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.PromoteActivityStep, *v1.PromoteUpdateStep) error
+type RollbackFn func(*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.RollbackActivityStep) error
 
 type PipelineDetails struct {
 	GitOwner      string
@@ -440,6 +441,59 @@ func (k *PromoteStepActivityKey) OnPromoteUpdate(activities typev1.PipelineActiv
 	return err
 }
 
+// GetOrCreateRollback gets or creates the Rollback step for the key
+func (k *PromoteStepActivityKey) GetOrCreateRollback(activities typev1.PipelineActivityInterface) (*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.RollbackActivityStep, bool, error) {
+	a, _, err := k.GetOrCreate(activities)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	spec := &a.Spec
+	for i := range spec.Steps {
+		step := &spec.Steps[i]
+		if k.matchesRollback(step) {
+			return a, step, step.Rollback, false, nil
+		}
+	}
+	rollback := &v1.RollbackActivityStep{
+		CoreActivityStep: v1.CoreActivityStep{
+			StartedTimestamp: &metav1.Time{
+				Time: time.Now(),
+			},
+		},
+		Environment: k.Environment,
+	}
+	spec.Steps = append(spec.Steps, v1.PipelineActivityStep{
+		Kind:     v1.ActivityStepKindTypeRollback,
+		Rollback: rollback,
+	})
+	return a, &spec.Steps[len(spec.Steps)-1], rollback, true, nil
+}
+
+func (k *PromoteStepActivityKey) OnRollback(activities typev1.PipelineActivityInterface, fn RollbackFn) error {
+	if !k.IsValid() {
+		return nil
+	}
+	if activities == nil {
+		log.Warn("Warning: no PipelineActivities client available!")
+		return nil
+	}
+	a, s, r, added, err := k.GetOrCreateRollback(activities)
+	if err != nil {
+		return err
+	}
+	r1 := *r
+	err = fn(a, s, r)
+	if err != nil {
+		return err
+	}
+	r2 := *r
+
+	if added || !reflect.DeepEqual(r1, r2) {
+		_, err = activities.Update(a)
+	}
+	return err
+}
+
 func asYaml(activity *v1.PipelineActivity) string {
 	data, err := yaml.Marshal(activity)
 	if err == nil {
@@ -458,3 +512,8 @@ func (k *PromoteStepActivityKey) matchesPromote(step *v1.PipelineActivityStep) b
 	s := step.Promote
 	return s != nil && s.Environment == k.Environment
 }
+
+func (k *PromoteStepActivityKey) matchesRollback(step *v1.PipelineActivityStep) bool {
+	s := step.Rollback
+	return s != nil && s.Environment == k.Environment
+}
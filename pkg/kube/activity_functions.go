@@ -152,3 +152,37 @@ func FailedPromotionUpdate(a *v1.PipelineActivity, s *v1.PipelineActivityStep, p
 	p.Status = v1.ActivityStatusTypeFailed
 	return nil
 }
+
+func StartRollback(a *v1.PipelineActivity, s *v1.PipelineActivityStep, r *v1.RollbackActivityStep) error {
+	if r.StartedTimestamp == nil {
+		r.StartedTimestamp = &metav1.Time{
+			Time: time.Now(),
+		}
+	}
+	if r.Status != v1.ActivityStatusTypeRunning {
+		r.Status = v1.ActivityStatusTypeRunning
+	}
+	return nil
+}
+
+func CompleteRollback(a *v1.PipelineActivity, s *v1.PipelineActivityStep, r *v1.RollbackActivityStep) error {
+	StartRollback(a, s, r)
+	if r.CompletedTimestamp == nil {
+		r.CompletedTimestamp = &metav1.Time{
+			Time: time.Now(),
+		}
+	}
+	r.Status = v1.ActivityStatusTypeSucceeded
+	return nil
+}
+
+func FailedRollback(a *v1.PipelineActivity, s *v1.PipelineActivityStep, r *v1.RollbackActivityStep) error {
+	StartRollback(a, s, r)
+	if r.CompletedTimestamp == nil {
+		r.CompletedTimestamp = &metav1.Time{
+			Time: time.Now(),
+		}
+	}
+	r.Status = v1.ActivityStatusTypeFailed
+	return nil
+}
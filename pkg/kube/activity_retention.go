@@ -0,0 +1,188 @@
+package kube
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+)
+
+// ActivityRetentionDecision whether an activity is kept or deleted by a retention policy and why
+type ActivityRetentionDecision struct {
+	Activity *v1.PipelineActivity
+	Delete   bool
+	Reason   string
+}
+
+// ActivityRetention decides which PipelineActivity resources to garbage collect using a retention policy
+type ActivityRetention struct {
+	Policy *v1.ActivityRetentionPolicy
+	// RevisionHistoryLimit the number of activities of each pipeline kept if they match no rule of the policy
+	RevisionHistoryLimit int
+	Now                  time.Time
+	// Expired returns the reason an activity should be deleted whatever the rules such as its pull request is closed
+	// or an empty string if the rules should decide
+	Expired func(a *v1.PipelineActivity) string
+}
+
+type activityRetentionRule struct {
+	v1.ActivityRetentionRule
+	name     string
+	branches *regexp.Regexp
+	counts   map[string]int
+}
+
+// IsPullRequestBranch returns true if the branch name is a pull request such as PR-123
+func IsPullRequestBranch(branch string) bool {
+	return strings.HasPrefix(strings.ToUpper(branch), "PR-")
+}
+
+// Decide returns the decision for each activity ordered by pipeline with the most recent build first
+func (r *ActivityRetention) Decide(activities []v1.PipelineActivity) ([]*ActivityRetentionDecision, error) {
+	policy := r.Policy
+	if policy == nil {
+		policy = &v1.ActivityRetentionPolicy{}
+	}
+	rules := []*activityRetentionRule{}
+	for i, rule := range policy.Rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("rule %d", i+1)
+		}
+		compiled := &activityRetentionRule{
+			ActivityRetentionRule: rule,
+			name:                  name,
+			counts:                map[string]int{},
+		}
+		if rule.Branches != "" {
+			re, err := regexp.Compile("^(" + rule.Branches + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid branches expression %s of activity retention %s: %s", rule.Branches, name, err)
+			}
+			compiled.branches = re
+		}
+		rules = append(rules, compiled)
+	}
+	keepPromotedTo := map[string]bool{}
+	for _, env := range policy.KeepPromotedTo {
+		keepPromotedTo[env] = true
+	}
+
+	sorted := []*v1.PipelineActivity{}
+	for i := range activities {
+		sorted = append(sorted, &activities[i])
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a1 := sorted[i]
+		a2 := sorted[j]
+		if a1.Spec.Pipeline != a2.Spec.Pipeline {
+			return a1.Spec.Pipeline < a2.Spec.Pipeline
+		}
+		return buildNumber(a1) > buildNumber(a2)
+	})
+
+	defaultCounts := map[string]int{}
+	answer := []*ActivityRetentionDecision{}
+	for _, a := range sorted {
+		decision := &ActivityRetentionDecision{
+			Activity: a,
+		}
+		answer = append(answer, decision)
+
+		if env := promotedToEnvironment(a, keepPromotedTo); env != "" {
+			decision.Reason = "promoted to " + env
+			continue
+		}
+		if r.Expired != nil {
+			reason := r.Expired(a)
+			if reason != "" {
+				decision.Delete = true
+				decision.Reason = reason
+				continue
+			}
+		}
+
+		matched := false
+		for _, rule := range rules {
+			if !rule.matches(a) {
+				continue
+			}
+			matched = true
+			rule.counts[a.Spec.Pipeline]++
+			if decision.Reason != "" {
+				continue
+			}
+			if rule.Keep > 0 && rule.counts[a.Spec.Pipeline] <= rule.Keep {
+				decision.Reason = fmt.Sprintf("%s keeps the last %d", rule.name, rule.Keep)
+			} else if rule.MaxAgeDays > 0 && r.age(a) < time.Duration(rule.MaxAgeDays)*24*time.Hour {
+				decision.Reason = fmt.Sprintf("%s keeps %d days", rule.name, rule.MaxAgeDays)
+			}
+		}
+		if matched {
+			if decision.Reason == "" {
+				decision.Delete = true
+				decision.Reason = "expired by the retention rules"
+			}
+			continue
+		}
+		defaultCounts[a.Spec.Pipeline]++
+		if defaultCounts[a.Spec.Pipeline] <= r.RevisionHistoryLimit {
+			decision.Reason = fmt.Sprintf("revision history limit of %d", r.RevisionHistoryLimit)
+		} else {
+			decision.Delete = true
+			decision.Reason = fmt.Sprintf("exceeds the revision history limit of %d", r.RevisionHistoryLimit)
+		}
+	}
+	return answer, nil
+}
+
+func (r *activityRetentionRule) matches(a *v1.PipelineActivity) bool {
+	if r.branches != nil && !r.branches.MatchString(a.BranchName()) {
+		return false
+	}
+	if len(r.Statuses) == 0 {
+		return true
+	}
+	for _, status := range r.Statuses {
+		if status == a.Spec.Status {
+			return true
+		}
+	}
+	return false
+}
+
+// age returns how long ago the activity started
+func (r *ActivityRetention) age(a *v1.PipelineActivity) time.Duration {
+	started := a.CreationTimestamp.Time
+	if a.Spec.StartedTimestamp != nil {
+		started = a.Spec.StartedTimestamp.Time
+	}
+	now := r.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	return now.Sub(started)
+}
+
+// promotedToEnvironment returns the name of the environment the activity successfully promoted to or an empty string
+func promotedToEnvironment(a *v1.PipelineActivity, envs map[string]bool) string {
+	for _, step := range a.Spec.Steps {
+		promote := step.Promote
+		if promote != nil && envs[promote.Environment] && promote.Status == v1.ActivityStatusTypeSucceeded {
+			return promote.Environment
+		}
+	}
+	return ""
+}
+
+func buildNumber(a *v1.PipelineActivity) int {
+	n, err := strconv.Atoi(a.Spec.Build)
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,100 @@
+package kube_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func createRetentionActivity(branch string, build int, status v1.ActivityStatusType, started time.Time) v1.PipelineActivity {
+	return v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "myorg-myapp-" + branch + "-" + strconv.Itoa(build),
+		},
+		Spec: v1.PipelineActivitySpec{
+			Pipeline:         "myorg/myapp/" + branch,
+			Build:            strconv.Itoa(build),
+			Status:           status,
+			StartedTimestamp: &metav1.Time{Time: started},
+		},
+	}
+}
+
+func TestActivityRetention(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	day := 24 * time.Hour
+
+	released := createRetentionActivity("master", 1, v1.ActivityStatusTypeSucceeded, now.Add(-100*day))
+	released.Spec.Steps = []v1.PipelineActivityStep{
+		{
+			Kind: v1.ActivityStepKindTypePromote,
+			Promote: &v1.PromoteActivityStep{
+				CoreActivityStep: v1.CoreActivityStep{
+					Status: v1.ActivityStatusTypeSucceeded,
+				},
+				Environment: "production",
+			},
+		},
+	}
+	activities := []v1.PipelineActivity{
+		released,
+		createRetentionActivity("master", 2, v1.ActivityStatusTypeFailed, now.Add(-40*day)),
+		createRetentionActivity("master", 3, v1.ActivityStatusTypeFailed, now.Add(-10*day)),
+		createRetentionActivity("master", 4, v1.ActivityStatusTypeSucceeded, now.Add(-5*day)),
+		createRetentionActivity("master", 5, v1.ActivityStatusTypeSucceeded, now.Add(-4*day)),
+		createRetentionActivity("PR-1", 1, v1.ActivityStatusTypeSucceeded, now.Add(-3*day)),
+		createRetentionActivity("PR-1", 2, v1.ActivityStatusTypeSucceeded, now.Add(-2*day)),
+		createRetentionActivity("PR-1", 3, v1.ActivityStatusTypeSucceeded, now.Add(-1*day)),
+		createRetentionActivity("PR-2", 1, v1.ActivityStatusTypeSucceeded, now.Add(-1*day)),
+	}
+	retention := &kube.ActivityRetention{
+		Policy: &v1.ActivityRetentionPolicy{
+			Rules: []v1.ActivityRetentionRule{
+				{
+					Name:       "failed",
+					Statuses:   []v1.ActivityStatusType{v1.ActivityStatusTypeFailed},
+					MaxAgeDays: 30,
+				},
+				{
+					Name:     "pull requests",
+					Branches: "PR-.*",
+					Keep:     2,
+				},
+			},
+			KeepPromotedTo: []string{"production"},
+		},
+		RevisionHistoryLimit: 1,
+		Now:                  now,
+		Expired: func(a *v1.PipelineActivity) string {
+			if a.BranchName() == "PR-2" {
+				return "pull request closed"
+			}
+			return ""
+		},
+	}
+
+	decisions, err := retention.Decide(activities)
+	assert.NoError(t, err)
+	deleted := map[string]string{}
+	for _, d := range decisions {
+		if d.Delete {
+			deleted[d.Activity.Name] = d.Reason
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"myorg-myapp-master-2": "expired by the retention rules",
+		"myorg-myapp-master-4": "exceeds the revision history limit of 1",
+		"myorg-myapp-PR-1-1":   "expired by the retention rules",
+		"myorg-myapp-PR-2-1":   "pull request closed",
+	}, deleted)
+
+	retention.Policy.Rules[0].Branches = "["
+	_, err = retention.Decide(activities)
+	assert.Error(t, err, "invalid branches expression")
+}
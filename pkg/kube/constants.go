@@ -82,6 +82,12 @@ const (
 	// SecretJenkinsPipelineIssueCredentials the issue tracker credentials secret
 	SecretJenkinsPipelineIssueCredentials = "jx-pipeline-issues-"
 
+	// SecretPipelineEventSinks the secret containing the configuration of the pipeline event sinks
+	SecretPipelineEventSinks = "jx-pipeline-event-sinks"
+
+	// SecretDataPipelineEventSinks the key of the pipeline event sinks configuration in its secret
+	SecretDataPipelineEventSinks = "sinks.yml"
+
 	// ConfigMapExposecontroller the name of the ConfigMap with the Exposecontroller configuration
 	ConfigMapExposecontroller = "exposecontroller"
 
@@ -146,6 +152,9 @@ const (
 	// ValueKindEnvironmentRole to indicate a Role which maps to an EnvironmentRoleBinding
 	ValueKindEnvironmentRole = "EnvironmentRole"
 
+	// ValueKindWorkflowApprovers to indicate a Role which grants the approvers of a Workflow step permission to approve it
+	ValueKindWorkflowApprovers = "WorkflowApprovers"
+
 	// ValueKindCVE an addon auth PipelineEvent
 	ValueKindRelease = "Release"
 
@@ -194,6 +203,18 @@ const (
 	// ValueJobKindPostPreview
 	ValueJobKindPostPreview = "post-preview-step"
 
+	// ValueJobKindWorkflowVerify a Job which verifies a release as part of a Workflow
+	ValueJobKindWorkflowVerify = "workflow-verify-step"
+
+	// ValueJobKindVerifyCheck a Job which runs a smoke test for 'jx step verify'
+	ValueJobKindVerifyCheck = "verify-check"
+
+	// ValueJobKindPreviewSeed a Job which seeds the dependencies of a preview environment with data
+	ValueJobKindPreviewSeed = "preview-seed"
+
+	// LabelPipelineActivity the name of the PipelineActivity a resource was created for
+	LabelPipelineActivity = "jenkins.io/pipeline-activity"
+
 	// AnnotationURL indicates a service/server's URL
 	AnnotationURL = "jenkins.io/url"
 
@@ -0,0 +1,163 @@
+package kube
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// AnnotationDevPodLastActive the time a shell or file synchronisation last used the DevPod
+	AnnotationDevPodLastActive = "jenkins.io/devpod-last-active"
+
+	// AnnotationDevPodIdleTimeout how long the DevPod can be idle before it is stopped
+	AnnotationDevPodIdleTimeout = "jenkins.io/devpod-idle-timeout"
+)
+
+// DevPodLastActive returns the time the DevPod was last used which defaults to the time it was created
+func DevPodLastActive(pod *v1.Pod) time.Time {
+	answer := pod.CreationTimestamp.Time
+	if pod.Annotations != nil {
+		t, err := time.Parse(time.RFC3339, pod.Annotations[AnnotationDevPodLastActive])
+		if err == nil && t.After(answer) {
+			answer = t
+		}
+	}
+	return answer
+}
+
+// DevPodIdleTime returns how long the DevPod has not been used for
+func DevPodIdleTime(pod *v1.Pod, now time.Time) time.Duration {
+	idle := now.Sub(DevPodLastActive(pod))
+	if idle < 0 {
+		return 0
+	}
+	return idle
+}
+
+// DevPodIdleTimeout returns the idle timeout of the DevPod or the default if the DevPod does not have a valid one
+func DevPodIdleTimeout(pod *v1.Pod, defaultTimeout time.Duration) time.Duration {
+	if pod.Annotations != nil {
+		text := pod.Annotations[AnnotationDevPodIdleTimeout]
+		if text != "" {
+			d, err := time.ParseDuration(text)
+			if err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultTimeout
+}
+
+// IsDevPodIdle returns true if the DevPod has not been used for longer than its idle timeout
+func IsDevPodIdle(pod *v1.Pod, defaultTimeout time.Duration, now time.Time) bool {
+	timeout := DevPodIdleTimeout(pod, defaultTimeout)
+	return timeout > 0 && DevPodIdleTime(pod, now) > timeout
+}
+
+// CanStopIdleDevPod returns true if stopping the DevPod when it is idle does not lose its workspace because the
+// workspace is a persistent volume claim which is not owned by the DevPod or if the DevPod has its own idle timeout
+func CanStopIdleDevPod(pod *v1.Pod, workspaceClaim *v1.PersistentVolumeClaim) bool {
+	if pod.Annotations != nil && pod.Annotations[AnnotationDevPodIdleTimeout] != "" {
+		return true
+	}
+	return workspaceClaim != nil && len(workspaceClaim.OwnerReferences) == 0
+}
+
+// MarkDevPodActive records that the DevPod was used at the given time
+func MarkDevPodActive(client kubernetes.Interface, ns string, name string, t time.Time) error {
+	pods := client.CoreV1().Pods(ns)
+	pod, err := pods.Get(name, meta_v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[AnnotationDevPodLastActive] = t.UTC().Format(time.RFC3339)
+	_, err = pods.Update(pod)
+	if err != nil {
+		return fmt.Errorf("Failed to update DevPod %s: %s", name, err)
+	}
+	return nil
+}
+
+// DevPodWorkspaceClaimName returns the name of the persistent volume claim of the workspace of the DevPod
+func DevPodWorkspaceClaimName(username string, label string, suffix string) string {
+	name := username + "-" + label
+	if suffix != "" {
+		name += "-" + suffix
+	}
+	return ToValidName(name + "-workspace")
+}
+
+// FindDevPodUsingVolumeClaim returns the DevPod which is not terminating or terminated and mounts the persistent
+// volume claim with the given name or nil if there is none
+func FindDevPodUsingVolumeClaim(client kubernetes.Interface, ns string, claimName string) (*v1.Pod, error) {
+	pods, err := client.CoreV1().Pods(ns).List(meta_v1.ListOptions{
+		LabelSelector: LabelDevPodName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == claimName {
+				return pod, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// GetDevPodVolumeClaim returns the persistent volume claim of the volume of the DevPod with the given name or nil
+func GetDevPodVolumeClaim(client kubernetes.Interface, pod *v1.Pod, volumeName string) (*v1.PersistentVolumeClaim, error) {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name == volumeName && volume.PersistentVolumeClaim != nil {
+			return client.CoreV1().PersistentVolumeClaims(pod.Namespace).Get(volume.PersistentVolumeClaim.ClaimName, meta_v1.GetOptions{})
+		}
+	}
+	return nil, nil
+}
+
+// VolumeClaimCapacity returns the capacity of the persistent volume claim falling back to its storage request
+func VolumeClaimCapacity(pvc *v1.PersistentVolumeClaim) string {
+	q, ok := pvc.Status.Capacity[v1.ResourceStorage]
+	if !ok {
+		q, ok = pvc.Spec.Resources.Requests[v1.ResourceStorage]
+	}
+	if !ok {
+		return ""
+	}
+	return q.String()
+}
+
+// ParseDiskUsage parses the output of 'du -sk' returning the number of bytes used
+func ParseDiskUsage(output string) (int64, error) {
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("no disk usage found")
+	}
+	kb, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid disk usage %s: %s", output, err)
+	}
+	return kb * 1024, nil
+}
+
+// FormatStorageSize formats the number of bytes rounded up to the nearest mebibyte such as 120Mi or 2Gi
+func FormatStorageSize(bytes int64) string {
+	const mebibyte = 1024 * 1024
+	mebibytes := (bytes + mebibyte - 1) / mebibyte
+	return resource.NewQuantity(mebibytes*mebibyte, resource.BinarySI).String()
+}
@@ -0,0 +1,146 @@
+package kube_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube_mocks "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDevPodIdle(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2018, 11, 1, 12, 0, 0, 0, time.UTC)
+	pod := &v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:              "james-go",
+			CreationTimestamp: meta_v1.NewTime(now.Add(-5 * time.Hour)),
+		},
+	}
+	assert.Equal(t, 5*time.Hour, kube.DevPodIdleTime(pod, now))
+	assert.True(t, kube.IsDevPodIdle(pod, 4*time.Hour, now), "idle since it was created")
+	assert.False(t, kube.IsDevPodIdle(pod, 0, now), "no idle timeout")
+
+	pod.Annotations = map[string]string{
+		kube.AnnotationDevPodLastActive:  now.Add(-time.Hour).Format(time.RFC3339),
+		kube.AnnotationDevPodIdleTimeout: "30m",
+	}
+	assert.Equal(t, time.Hour, kube.DevPodIdleTime(pod, now))
+	assert.Equal(t, 30*time.Minute, kube.DevPodIdleTimeout(pod, 4*time.Hour))
+	assert.True(t, kube.IsDevPodIdle(pod, 4*time.Hour, now), "idle for longer than its own timeout")
+
+	pod.Annotations[kube.AnnotationDevPodIdleTimeout] = "forever"
+	assert.Equal(t, 4*time.Hour, kube.DevPodIdleTimeout(pod, 4*time.Hour))
+	assert.False(t, kube.IsDevPodIdle(pod, 4*time.Hour, now), "used within the default timeout")
+}
+
+func TestCanStopIdleDevPod(t *testing.T) {
+	t.Parallel()
+	pod := &v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name: "james-go",
+			UID:  "123",
+		},
+	}
+	assert.False(t, kube.CanStopIdleDevPod(pod, nil), "empty dir workspace")
+
+	claim := &v1.PersistentVolumeClaim{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name: "james-go-pvc",
+			OwnerReferences: []meta_v1.OwnerReference{
+				kube.PodOwnerRef(pod),
+			},
+		},
+	}
+	assert.False(t, kube.CanStopIdleDevPod(pod, claim), "workspace owned by the DevPod")
+
+	claim.OwnerReferences = nil
+	assert.True(t, kube.CanStopIdleDevPod(pod, claim), "kept workspace")
+
+	pod.Annotations = map[string]string{
+		kube.AnnotationDevPodIdleTimeout: "30m",
+	}
+	assert.True(t, kube.CanStopIdleDevPod(pod, nil), "own idle timeout")
+}
+
+func TestMarkDevPodActive(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2018, 11, 1, 12, 0, 0, 0, time.UTC)
+	kubeClient := kube_mocks.NewSimpleClientset(&v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "james-go",
+			Namespace: "jx",
+		},
+	})
+	err := kube.MarkDevPodActive(kubeClient, "jx", "james-go", now)
+	assert.NoError(t, err)
+
+	pod, err := kubeClient.CoreV1().Pods("jx").Get("james-go", meta_v1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, now, kube.DevPodLastActive(pod))
+
+	assert.Error(t, kube.MarkDevPodActive(kubeClient, "jx", "missing", now))
+}
+
+func TestFindDevPodUsingVolumeClaim(t *testing.T) {
+	t.Parallel()
+	devPod := func(name string, claimName string, phase v1.PodPhase) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      name,
+				Namespace: "jx",
+				Labels: map[string]string{
+					kube.LabelDevPodName: name,
+				},
+			},
+			Spec: v1.PodSpec{
+				Volumes: []v1.Volume{
+					{
+						Name: "workspace",
+						VolumeSource: v1.VolumeSource{
+							PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+								ClaimName: claimName,
+							},
+						},
+					},
+				},
+			},
+			Status: v1.PodStatus{
+				Phase: phase,
+			},
+		}
+	}
+	kubeClient := kube_mocks.NewSimpleClientset(
+		devPod("james-go", "james-go-workspace", v1.PodRunning),
+		devPod("james-maven", "james-maven-workspace", v1.PodFailed),
+	)
+
+	pod, err := kube.FindDevPodUsingVolumeClaim(kubeClient, "jx", "james-go-workspace")
+	assert.NoError(t, err)
+	if assert.NotNil(t, pod, "DevPod using the claim") {
+		assert.Equal(t, "james-go", pod.Name)
+	}
+
+	pod, err = kube.FindDevPodUsingVolumeClaim(kubeClient, "jx", "james-maven-workspace")
+	assert.NoError(t, err)
+	assert.Nil(t, pod, "terminated DevPods do not use the claim")
+}
+
+func TestDevPodStorage(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "james-go-workspace", kube.DevPodWorkspaceClaimName("james", "go", ""))
+	assert.Equal(t, "james-go-api-workspace", kube.DevPodWorkspaceClaimName("james", "go", "api"))
+
+	used, err := kube.ParseDiskUsage("2048\t/workspace\n")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2*1024*1024), used)
+	_, err = kube.ParseDiskUsage("")
+	assert.Error(t, err)
+
+	assert.Equal(t, "2Mi", kube.FormatStorageSize(used))
+	assert.Equal(t, "1Mi", kube.FormatStorageSize(10))
+	assert.Equal(t, "1Gi", kube.FormatStorageSize(1024*1024*1024))
+}
@@ -207,6 +207,9 @@ func CreateEnvironmentSurvey(batchMode bool, authConfigSvc auth.AuthConfigServic
 	if string(data.Spec.PromotionStrategy) == "" {
 		data.Spec.PromotionStrategy = v1.PromotionStrategyTypeAutomatic
 	}
+	if config.Spec.Rollout != nil {
+		data.Spec.Rollout = config.Spec.Rollout
+	}
 	if config.Spec.Order != 0 {
 		data.Spec.Order = config.Spec.Order
 	} else {
@@ -0,0 +1,77 @@
+package kube
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// AnnotationPreviewDependencies the comma separated helm releases of the dependencies of a preview Environment
+	AnnotationPreviewDependencies = "jenkins.io/preview-dependencies"
+
+	maxReleaseNameLength = 53
+
+	releaseNameHashLength = 8
+)
+
+// PreviewDependencyReleaseName returns the helm release name of a dependency of the preview in the namespace.
+// Names which are too long for helm are truncated and suffixed with a short hash of the full name so that the
+// releases of different previews or dependencies do not collide
+func PreviewDependencyReleaseName(previewNs string, name string) string {
+	answer := ToValidName(previewNs + "-" + name)
+	if len(answer) > maxReleaseNameLength {
+		hash := fmt.Sprintf("%x", sha256.Sum256([]byte(answer)))[0:releaseNameHashLength]
+		prefix := strings.TrimSuffix(answer[0:maxReleaseNameLength-releaseNameHashLength-1], "-")
+		answer = prefix + "-" + hash
+	}
+	return answer
+}
+
+// PreviewDependencyReleases returns the helm releases of the dependencies of the preview environment
+func PreviewDependencyReleases(env *v1.Environment) []string {
+	answer := []string{}
+	for _, release := range strings.Split(env.Annotations[AnnotationPreviewDependencies], ",") {
+		release = strings.TrimSpace(release)
+		if release != "" {
+			answer = append(answer, release)
+		}
+	}
+	return answer
+}
+
+// SetPreviewDependencyReleases records the helm releases of the dependencies on the preview environment
+func SetPreviewDependencyReleases(env *v1.Environment, releases []string) {
+	if len(releases) == 0 {
+		delete(env.Annotations, AnnotationPreviewDependencies)
+		return
+	}
+	if env.Annotations == nil {
+		env.Annotations = map[string]string{}
+	}
+	env.Annotations[AnnotationPreviewDependencies] = strings.Join(releases, ",")
+}
+
+// GetAppVersion returns the version of the app deployed in the namespace or an empty string if it is not deployed
+func GetAppVersion(kubeClient kubernetes.Interface, ns string, app string) (string, error) {
+	deployments, err := GetDeployments(kubeClient, ns)
+	if err != nil {
+		return "", err
+	}
+	names := []string{}
+	for name := range deployments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		d := deployments[name]
+		if GetAppName(name, ns) == app || GetName(&d.ObjectMeta) == app {
+			return GetVersion(&d.ObjectMeta), nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,70 @@
+package kube_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/apps/v1beta1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube_mocks "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPreviewDependencyReleases(t *testing.T) {
+	t.Parallel()
+	env := &v1.Environment{}
+	assert.Empty(t, kube.PreviewDependencyReleases(env))
+
+	kube.SetPreviewDependencyReleases(env, []string{"jx-myorg-myapp-pr-1-postgresql", "jx-myorg-myapp-pr-1-customers"})
+	assert.Equal(t, []string{"jx-myorg-myapp-pr-1-postgresql", "jx-myorg-myapp-pr-1-customers"}, kube.PreviewDependencyReleases(env))
+
+	kube.SetPreviewDependencyReleases(env, nil)
+	assert.Empty(t, env.Annotations[kube.AnnotationPreviewDependencies])
+
+	assert.Equal(t, "jx-myorg-myapp-pr-1-postgresql", kube.PreviewDependencyReleaseName("jx-myorg-myapp-pr-1", "postgresql"))
+	name := kube.PreviewDependencyReleaseName("jx-myorganisation-my-very-long-application-name-pr-123", "postgresql")
+	assert.True(t, len(name) <= 53, "release name %s should be at most 53 characters", name)
+	assert.True(t, strings.HasPrefix(name, "jx-myorganisation-my-very-long-application-"), "release name %s should start with the preview namespace", name)
+	otherName := kube.PreviewDependencyReleaseName("jx-myorganisation-my-very-long-application-name-pr-124", "postgresql")
+	assert.True(t, len(otherName) <= 53, "release name %s should be at most 53 characters", otherName)
+	assert.NotEqual(t, name, otherName, "truncated release names of different previews should not collide")
+	assert.Equal(t, name, kube.PreviewDependencyReleaseName("jx-myorganisation-my-very-long-application-name-pr-123", "postgresql"))
+}
+
+func TestGetAppVersion(t *testing.T) {
+	t.Parallel()
+	kubeClient := kube_mocks.NewSimpleClientset(
+		&v1beta1.Deployment{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "jx-staging-customers",
+				Namespace: "jx-staging",
+				Labels: map[string]string{
+					"version": "1.2.3",
+				},
+			},
+		},
+		&v1beta1.Deployment{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      "jx-staging-orders",
+				Namespace: "jx-staging",
+				Labels: map[string]string{
+					"chart": "orders-0.0.7",
+				},
+			},
+		},
+	)
+
+	version, err := kube.GetAppVersion(kubeClient, "jx-staging", "customers")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3", version)
+
+	version, err = kube.GetAppVersion(kubeClient, "jx-staging", "orders")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0.7", version)
+
+	version, err = kube.GetAppVersion(kubeClient, "jx-staging", "payments")
+	assert.NoError(t, err)
+	assert.Equal(t, "", version)
+}
@@ -0,0 +1,462 @@
+package kube
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// AnnotationPreviewLastUpdated the time a preview Environment was last updated by a push to its pull request
+	AnnotationPreviewLastUpdated = "jenkins.io/preview-last-updated"
+	// AnnotationPreviewLastWoken the time a preview Environment was last woken up by a request
+	AnnotationPreviewLastWoken = "jenkins.io/preview-last-woken"
+	// AnnotationPreviewScaledDown marks a preview Environment whose deployments are scaled down to zero replicas
+	AnnotationPreviewScaledDown = "jenkins.io/preview-scaled-down"
+	// AnnotationPreviewMaximumInstances the maximum number of previews of the repository configured in its jenkins-x.yml
+	AnnotationPreviewMaximumInstances = "jenkins.io/preview-maximum-instances"
+	// AnnotationReplicas the number of replicas of a deployment before it was scaled down to zero
+	AnnotationReplicas = "jenkins.io/replicas"
+
+	// AnnotationIngressDefaultBackend the service an nginx ingress sends the responses with custom errors to
+	AnnotationIngressDefaultBackend = "nginx.ingress.kubernetes.io/default-backend"
+	// AnnotationIngressCustomHTTPErrors the status codes an nginx ingress sends to its default backend
+	AnnotationIngressCustomHTTPErrors = "nginx.ingress.kubernetes.io/custom-http-errors"
+
+	// PreviewQuotaName the name of the ResourceQuota and LimitRange of preview namespaces
+	PreviewQuotaName = "preview"
+)
+
+// PreviewAction the action taken for a preview environment by its lifecycle
+type PreviewAction string
+
+const (
+	// PreviewActionKeep keeps the preview running
+	PreviewActionKeep PreviewAction = "keep"
+	// PreviewActionDelete deletes the preview environment and its namespace
+	PreviewActionDelete PreviewAction = "delete"
+	// PreviewActionScaleDown scales the deployments of the preview down to zero replicas
+	PreviewActionScaleDown PreviewAction = "scale-down"
+)
+
+// PreviewLifecycle decides which preview environments to delete or scale down
+type PreviewLifecycle struct {
+	TTL              time.Duration
+	IdleTimeout      time.Duration
+	MaximumInstances int
+	Now              time.Time
+}
+
+// PreviewDecision the action for a preview environment and why it is taken
+type PreviewDecision struct {
+	Environment *v1.Environment
+	Action      PreviewAction
+	Reason      string
+}
+
+// NewPreviewLifecycle creates the lifecycle of the policy
+func NewPreviewLifecycle(policy *v1.PreviewLifecyclePolicy) (*PreviewLifecycle, error) {
+	answer := &PreviewLifecycle{
+		Now: time.Now(),
+	}
+	if policy == nil {
+		return answer, nil
+	}
+	var err error
+	if policy.TTL != "" {
+		answer.TTL, err = time.ParseDuration(policy.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid preview TTL %s: %s", policy.TTL, err)
+		}
+	}
+	if policy.IdleTimeout != "" {
+		answer.IdleTimeout, err = time.ParseDuration(policy.IdleTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid preview idle timeout %s: %s", policy.IdleTimeout, err)
+		}
+	}
+	answer.MaximumInstances = policy.MaximumInstances
+	return answer, nil
+}
+
+// PreviewLastUpdated returns the time the preview was last updated by a push or created
+func PreviewLastUpdated(env *v1.Environment) time.Time {
+	t := annotationTime(env, AnnotationPreviewLastUpdated)
+	if t.IsZero() {
+		return env.CreationTimestamp.Time
+	}
+	return t
+}
+
+// PreviewLastActive returns the time the preview was last updated or woken up by a request
+func PreviewLastActive(env *v1.Environment) time.Time {
+	answer := PreviewLastUpdated(env)
+	woken := annotationTime(env, AnnotationPreviewLastWoken)
+	if woken.After(answer) {
+		return woken
+	}
+	return answer
+}
+
+// IsPreviewScaledDown returns true if the deployments of the preview are scaled down to zero replicas
+func IsPreviewScaledDown(env *v1.Environment) bool {
+	return env.Annotations[AnnotationPreviewScaledDown] == "true"
+}
+
+func previewDuration(d time.Duration) string {
+	return d.Round(time.Minute).String()
+}
+
+func annotationTime(env *v1.Environment, annotation string) time.Time {
+	value := env.Annotations[annotation]
+	if value == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// SetPreviewAnnotationTime sets the annotation of the preview environment to the time
+func SetPreviewAnnotationTime(env *v1.Environment, annotation string, t time.Time) {
+	if env.Annotations == nil {
+		env.Annotations = map[string]string{}
+	}
+	env.Annotations[annotation] = t.UTC().Format(time.RFC3339)
+}
+
+// PreviewRepository returns the normalised git URL of the repository of the preview
+func PreviewRepository(env *v1.Environment) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSuffix(env.Spec.Source.URL, "/")), ".git")
+}
+
+// Decide returns the decision for each preview environment in the same order. Previews which have not been updated
+// for longer than the TTL are deleted, then the least recently updated previews of each repository which exceed its
+// maximum number of instances and finally previews which have been idle for longer than the idle timeout are scaled down
+func (l *PreviewLifecycle) Decide(envs []v1.Environment) []*PreviewDecision {
+	answer := []*PreviewDecision{}
+	repositories := map[string][]*PreviewDecision{}
+	for i := range envs {
+		env := &envs[i]
+		if env.Spec.Kind != v1.EnvironmentKindTypePreview {
+			continue
+		}
+		decision := &PreviewDecision{
+			Environment: env,
+			Action:      PreviewActionKeep,
+		}
+		answer = append(answer, decision)
+		age := l.Now.Sub(PreviewLastUpdated(env))
+		if l.TTL > 0 && age > l.TTL {
+			decision.Action = PreviewActionDelete
+			decision.Reason = fmt.Sprintf("not updated for %s which is longer than the TTL of %s", previewDuration(age), previewDuration(l.TTL))
+			continue
+		}
+		repo := PreviewRepository(env)
+		repositories[repo] = append(repositories[repo], decision)
+	}
+
+	for _, decisions := range repositories {
+		sort.SliceStable(decisions, func(i, j int) bool {
+			return PreviewLastUpdated(decisions[i].Environment).After(PreviewLastUpdated(decisions[j].Environment))
+		})
+		max := l.MaximumInstances
+		for _, d := range decisions {
+			value := d.Environment.Annotations[AnnotationPreviewMaximumInstances]
+			if value != "" {
+				n, err := strconv.Atoi(value)
+				if err == nil {
+					max = n
+				}
+				break
+			}
+		}
+		if max <= 0 {
+			continue
+		}
+		for i := max; i < len(decisions); i++ {
+			decisions[i].Action = PreviewActionDelete
+			decisions[i].Reason = fmt.Sprintf("the repository has more than the maximum of %d previews", max)
+		}
+	}
+
+	if l.IdleTimeout > 0 {
+		for _, decision := range answer {
+			env := decision.Environment
+			if decision.Action != PreviewActionKeep || IsPreviewScaledDown(env) {
+				continue
+			}
+			idle := l.Now.Sub(PreviewLastActive(env))
+			if idle > l.IdleTimeout {
+				decision.Action = PreviewActionScaleDown
+				decision.Reason = fmt.Sprintf("idle for %s which is longer than the idle timeout of %s", previewDuration(idle), previewDuration(l.IdleTimeout))
+			}
+		}
+	}
+	return answer
+}
+
+// ScaleDownDeployments scales every deployment in the namespace down to zero replicas recording their replicas
+// so that they can be woken up again
+func ScaleDownDeployments(kubeClient kubernetes.Interface, ns string) error {
+	deployments := kubeClient.AppsV1beta1().Deployments(ns)
+	list, err := deployments.List(meta_v1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range list.Items {
+		d := &list.Items[i]
+		if d.Spec.Replicas != nil && *d.Spec.Replicas == 0 {
+			continue
+		}
+		replicas := int32(1)
+		if d.Spec.Replicas != nil {
+			replicas = *d.Spec.Replicas
+		}
+		if d.Annotations == nil {
+			d.Annotations = map[string]string{}
+		}
+		d.Annotations[AnnotationReplicas] = strconv.Itoa(int(replicas))
+		zero := int32(0)
+		d.Spec.Replicas = &zero
+		_, err = deployments.Update(d)
+		if err != nil {
+			return fmt.Errorf("failed to scale down deployment %s in namespace %s: %s", d.Name, ns, err)
+		}
+	}
+	return nil
+}
+
+// WakeDeployments scales the deployments in the namespace which were scaled down to zero back to their replicas
+func WakeDeployments(kubeClient kubernetes.Interface, ns string) error {
+	deployments := kubeClient.AppsV1beta1().Deployments(ns)
+	list, err := deployments.List(meta_v1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range list.Items {
+		d := &list.Items[i]
+		value := d.Annotations[AnnotationReplicas]
+		if value == "" {
+			continue
+		}
+		replicas, err := strconv.Atoi(value)
+		if err != nil || replicas <= 0 {
+			replicas = 1
+		}
+		r := int32(replicas)
+		d.Spec.Replicas = &r
+		delete(d.Annotations, AnnotationReplicas)
+		_, err = deployments.Update(d)
+		if err != nil {
+			return fmt.Errorf("failed to wake deployment %s in namespace %s: %s", d.Name, ns, err)
+		}
+	}
+	return nil
+}
+
+// RouteIngressErrorsToService makes the nginx ingresses in the namespace send the 503 responses returned while
+// their services have no pods to the given service
+func RouteIngressErrorsToService(kubeClient kubernetes.Interface, ns string, serviceName string) error {
+	ingresses := kubeClient.ExtensionsV1beta1().Ingresses(ns)
+	list, err := ingresses.List(meta_v1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range list.Items {
+		ing := &list.Items[i]
+		if ing.Annotations[AnnotationIngressDefaultBackend] == serviceName {
+			continue
+		}
+		if ing.Annotations == nil {
+			ing.Annotations = map[string]string{}
+		}
+		ing.Annotations[AnnotationIngressDefaultBackend] = serviceName
+		ing.Annotations[AnnotationIngressCustomHTTPErrors] = "503"
+		_, err = ingresses.Update(ing)
+		if err != nil {
+			return fmt.Errorf("failed to update ingress %s in namespace %s: %s", ing.Name, ns, err)
+		}
+	}
+	return nil
+}
+
+// RemoveIngressErrorRouting stops the nginx ingresses in the namespace sending their errors to the given service
+// which RouteIngressErrorsToService configured
+func RemoveIngressErrorRouting(kubeClient kubernetes.Interface, ns string, serviceName string) error {
+	ingresses := kubeClient.ExtensionsV1beta1().Ingresses(ns)
+	list, err := ingresses.List(meta_v1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range list.Items {
+		ing := &list.Items[i]
+		if ing.Annotations[AnnotationIngressDefaultBackend] != serviceName {
+			continue
+		}
+		delete(ing.Annotations, AnnotationIngressDefaultBackend)
+		delete(ing.Annotations, AnnotationIngressCustomHTTPErrors)
+		_, err = ingresses.Update(ing)
+		if err != nil {
+			return fmt.Errorf("failed to update ingress %s in namespace %s: %s", ing.Name, ns, err)
+		}
+	}
+	return nil
+}
+
+// HasIngressForHost returns true if an ingress in the namespace has a rule for the host
+func HasIngressForHost(kubeClient kubernetes.Interface, ns string, host string) (bool, error) {
+	list, err := kubeClient.ExtensionsV1beta1().Ingresses(ns).List(meta_v1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+	for i := range list.Items {
+		for _, h := range IngressHosts(&list.Items[i]) {
+			if strings.EqualFold(h, host) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// EnsureExternalNameService creates or updates a service in the namespace which is an alias of the host name
+func EnsureExternalNameService(kubeClient kubernetes.Interface, ns string, name string, externalName string) error {
+	services := kubeClient.CoreV1().Services(ns)
+	svc, err := services.Get(name, meta_v1.GetOptions{})
+	if err == nil {
+		if svc.Spec.Type == corev1.ServiceTypeExternalName && svc.Spec.ExternalName == externalName {
+			return nil
+		}
+		svc.Spec.Type = corev1.ServiceTypeExternalName
+		svc.Spec.ExternalName = externalName
+		svc.Spec.ClusterIP = ""
+		svc.Spec.Selector = nil
+		_, err = services.Update(svc)
+		return err
+	}
+	if !errors.IsNotFound(err) {
+		return err
+	}
+	_, err = services.Create(&corev1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:         corev1.ServiceTypeExternalName,
+			ExternalName: externalName,
+		},
+	})
+	return err
+}
+
+// PreviewResourceQuota returns the ResourceQuota and LimitRange of a preview namespace for the policy. Either is nil
+// if the policy does not configure it
+func PreviewResourceQuota(policy *v1.PreviewLifecyclePolicy) (*corev1.ResourceQuota, *corev1.LimitRange, error) {
+	if policy == nil {
+		return nil, nil, nil
+	}
+	var quota *corev1.ResourceQuota
+	var limitRange *corev1.LimitRange
+	if len(policy.Quota) > 0 {
+		hard, err := parseResourceList(policy.Quota)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid preview quota: %s", err)
+		}
+		quota = &corev1.ResourceQuota{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name: PreviewQuotaName,
+			},
+			Spec: corev1.ResourceQuotaSpec{
+				Hard: hard,
+			},
+		}
+	}
+	if len(policy.DefaultRequests) > 0 || len(policy.DefaultLimits) > 0 {
+		requests, err := parseResourceList(policy.DefaultRequests)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid preview default requests: %s", err)
+		}
+		limits, err := parseResourceList(policy.DefaultLimits)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid preview default limits: %s", err)
+		}
+		limitRange = &corev1.LimitRange{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name: PreviewQuotaName,
+			},
+			Spec: corev1.LimitRangeSpec{
+				Limits: []corev1.LimitRangeItem{
+					{
+						Type:           corev1.LimitTypeContainer,
+						DefaultRequest: requests,
+						Default:        limits,
+					},
+				},
+			},
+		}
+	}
+	return quota, limitRange, nil
+}
+
+func parseResourceList(values map[string]string) (corev1.ResourceList, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	answer := corev1.ResourceList{}
+	for name, value := range values {
+		q, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity %s for %s: %s", value, name, err)
+		}
+		answer[corev1.ResourceName(name)] = q
+	}
+	return answer, nil
+}
+
+// ApplyPreviewResourceQuota creates or updates the ResourceQuota and LimitRange of the policy in the preview namespace
+func ApplyPreviewResourceQuota(kubeClient kubernetes.Interface, ns string, policy *v1.PreviewLifecyclePolicy) error {
+	quota, limitRange, err := PreviewResourceQuota(policy)
+	if err != nil {
+		return err
+	}
+	if quota != nil {
+		quotas := kubeClient.CoreV1().ResourceQuotas(ns)
+		existing, err := quotas.Get(quota.Name, meta_v1.GetOptions{})
+		if err == nil {
+			existing.Spec = quota.Spec
+			_, err = quotas.Update(existing)
+		} else if errors.IsNotFound(err) {
+			_, err = quotas.Create(quota)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to apply the ResourceQuota of namespace %s: %s", ns, err)
+		}
+	}
+	if limitRange != nil {
+		limitRanges := kubeClient.CoreV1().LimitRanges(ns)
+		existing, err := limitRanges.Get(limitRange.Name, meta_v1.GetOptions{})
+		if err == nil {
+			existing.Spec = limitRange.Spec
+			_, err = limitRanges.Update(existing)
+		} else if errors.IsNotFound(err) {
+			_, err = limitRanges.Create(limitRange)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to apply the LimitRange of namespace %s: %s", ns, err)
+		}
+	}
+	return nil
+}
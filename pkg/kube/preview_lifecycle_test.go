@@ -0,0 +1,148 @@
+package kube_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube_mocks "k8s.io/client-go/kubernetes/fake"
+)
+
+func createLifecyclePreview(name string, repo string, updated time.Time) v1.Environment {
+	env := v1.Environment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.Time{Time: updated.Add(-time.Hour)},
+		},
+		Spec: v1.EnvironmentSpec{
+			Kind: v1.EnvironmentKindTypePreview,
+			Source: v1.EnvironmentRepository{
+				URL: "https://github.com/myorg/" + repo + ".git",
+			},
+		},
+	}
+	kube.SetPreviewAnnotationTime(&env, kube.AnnotationPreviewLastUpdated, updated)
+	return env
+}
+
+func TestPreviewLifecycle(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	lifecycle, err := kube.NewPreviewLifecycle(&v1.PreviewLifecyclePolicy{
+		TTL:              "72h",
+		IdleTimeout:      "2h",
+		MaximumInstances: 2,
+	})
+	assert.NoError(t, err)
+	lifecycle.Now = now
+
+	expired := createLifecyclePreview("myorg-app1-pr-1", "app1", now.Add(-80*time.Hour))
+	newest := createLifecyclePreview("myorg-app1-pr-4", "app1", now.Add(-10*time.Minute))
+	idle := createLifecyclePreview("myorg-app1-pr-3", "app1", now.Add(-3*time.Hour))
+	extra := createLifecyclePreview("myorg-app1-pr-2", "app1", now.Add(-4*time.Hour))
+	woken := createLifecyclePreview("myorg-app2-pr-1", "app2", now.Add(-5*time.Hour))
+	kube.SetPreviewAnnotationTime(&woken, kube.AnnotationPreviewLastWoken, now.Add(-time.Hour))
+	scaledDown := createLifecyclePreview("myorg-app3-pr-1", "app3", now.Add(-5*time.Hour))
+	scaledDown.Annotations[kube.AnnotationPreviewScaledDown] = "true"
+	configured := createLifecyclePreview("myorg-app4-pr-1", "app4", now.Add(-time.Minute))
+	configured.Annotations[kube.AnnotationPreviewMaximumInstances] = "1"
+	configuredOld := createLifecyclePreview("myorg-app4-pr-2", "app4", now.Add(-20*time.Minute))
+	staging := v1.Environment{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging"},
+		Spec:       v1.EnvironmentSpec{Kind: v1.EnvironmentKindTypePermanent},
+	}
+
+	decisions := lifecycle.Decide([]v1.Environment{expired, newest, idle, extra, woken, scaledDown, configured, configuredOld, staging})
+	actions := map[string]kube.PreviewAction{}
+	for _, d := range decisions {
+		actions[d.Environment.Name] = d.Action
+	}
+	assert.Equal(t, map[string]kube.PreviewAction{
+		"myorg-app1-pr-1": kube.PreviewActionDelete,
+		"myorg-app1-pr-4": kube.PreviewActionKeep,
+		"myorg-app1-pr-3": kube.PreviewActionScaleDown,
+		"myorg-app1-pr-2": kube.PreviewActionDelete,
+		"myorg-app2-pr-1": kube.PreviewActionKeep,
+		"myorg-app3-pr-1": kube.PreviewActionKeep,
+		"myorg-app4-pr-1": kube.PreviewActionKeep,
+		"myorg-app4-pr-2": kube.PreviewActionDelete,
+	}, actions)
+	assert.Equal(t, "myorg-app1-pr-1", decisions[0].Environment.Name)
+	assert.Contains(t, decisions[0].Reason, "TTL")
+
+	_, err = kube.NewPreviewLifecycle(&v1.PreviewLifecyclePolicy{TTL: "3 days"})
+	assert.Error(t, err)
+}
+
+func TestPreviewResourceQuota(t *testing.T) {
+	t.Parallel()
+	quota, limitRange, err := kube.PreviewResourceQuota(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, quota)
+	assert.Nil(t, limitRange)
+
+	quota, limitRange, err = kube.PreviewResourceQuota(&v1.PreviewLifecyclePolicy{
+		Quota:           map[string]string{"cpu": "2", "memory": "4Gi", "pods": "10"},
+		DefaultRequests: map[string]string{"cpu": "100m"},
+		DefaultLimits:   map[string]string{"memory": "512Mi"},
+	})
+	assert.NoError(t, err)
+	if assert.NotNil(t, quota) {
+		hard := quota.Spec.Hard
+		assert.Equal(t, "4Gi", hard.Memory().String())
+		pods := hard[corev1.ResourcePods]
+		assert.Equal(t, int64(10), pods.Value())
+	}
+	if assert.NotNil(t, limitRange) && assert.Len(t, limitRange.Spec.Limits, 1) {
+		item := limitRange.Spec.Limits[0]
+		assert.Equal(t, corev1.LimitTypeContainer, item.Type)
+		assert.Equal(t, "100m", item.DefaultRequest.Cpu().String())
+		assert.Equal(t, "512Mi", item.Default.Memory().String())
+	}
+
+	_, _, err = kube.PreviewResourceQuota(&v1.PreviewLifecyclePolicy{Quota: map[string]string{"cpu": "lots"}})
+	assert.Error(t, err)
+}
+
+func TestPreviewIngressErrorRouting(t *testing.T) {
+	t.Parallel()
+	ns := "jx-myorg-myapp-pr-1"
+	kubeClient := kube_mocks.NewSimpleClientset(&v1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myapp",
+			Namespace: ns,
+		},
+		Spec: v1beta1.IngressSpec{
+			Rules: []v1beta1.IngressRule{
+				{
+					Host: "myapp.jx-myorg-myapp-pr-1.example.com",
+				},
+			},
+		},
+	})
+
+	found, err := kube.HasIngressForHost(kubeClient, ns, "MyApp.jx-myorg-myapp-pr-1.example.com")
+	assert.NoError(t, err)
+	assert.True(t, found, "ingress for the host")
+	found, err = kube.HasIngressForHost(kubeClient, ns, "other.example.com")
+	assert.NoError(t, err)
+	assert.False(t, found, "no ingress for the host")
+
+	err = kube.RouteIngressErrorsToService(kubeClient, ns, "preview-waker")
+	assert.NoError(t, err)
+	ing, err := kubeClient.ExtensionsV1beta1().Ingresses(ns).Get("myapp", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "preview-waker", ing.Annotations[kube.AnnotationIngressDefaultBackend])
+
+	err = kube.RemoveIngressErrorRouting(kubeClient, ns, "preview-waker")
+	assert.NoError(t, err)
+	ing, err = kubeClient.ExtensionsV1beta1().Ingresses(ns).Get("myapp", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, ing.Annotations[kube.AnnotationIngressDefaultBackend])
+	assert.Empty(t, ing.Annotations[kube.AnnotationIngressCustomHTTPErrors])
+}
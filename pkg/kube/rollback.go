@@ -0,0 +1,48 @@
+package kube
+
+import (
+	"sort"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+)
+
+// PromotedActivities returns the activities of the given application which have successfully promoted to the
+// given environment ordered with the most recent promotion first
+func PromotedActivities(activities []v1.PipelineActivity, app string, envName string) []*v1.PipelineActivity {
+	answer := []*v1.PipelineActivity{}
+	promotions := map[string]*v1.PromoteActivityStep{}
+	for i := range activities {
+		activity := &activities[i]
+		if activity.RepositoryName() != app || activity.Spec.Version == "" {
+			continue
+		}
+		for _, step := range activity.Spec.Steps {
+			promote := step.Promote
+			if promote != nil && promote.Environment == envName && promote.Status == v1.ActivityStatusTypeSucceeded {
+				answer = append(answer, activity)
+				promotions[activity.Name] = promote
+				break
+			}
+		}
+	}
+	sort.SliceStable(answer, func(i, j int) bool {
+		t1 := promotions[answer[i].Name].CompletedTimestamp
+		t2 := promotions[answer[j].Name].CompletedTimestamp
+		if t1 == nil || t2 == nil {
+			return t2 == nil && t1 != nil
+		}
+		return t2.Before(t1)
+	})
+	return answer
+}
+
+// FindPreviousPromotedActivity returns the most recent activity which promoted a different version of the application
+// to the environment than the given version or nil if there is no previous version
+func FindPreviousPromotedActivity(activities []v1.PipelineActivity, app string, envName string, version string) *v1.PipelineActivity {
+	for _, activity := range PromotedActivities(activities, app, envName) {
+		if activity.Spec.Version != version {
+			return activity
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package kube_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFindPreviousPromotedActivity(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	activities := []v1.PipelineActivity{
+		createPromotedActivity("myapp", "1", "1.0.1", "production", v1.ActivityStatusTypeSucceeded, now.Add(-3*time.Hour)),
+		createPromotedActivity("myapp", "3", "1.0.3", "production", v1.ActivityStatusTypeSucceeded, now),
+		createPromotedActivity("myapp", "2", "1.0.2", "production", v1.ActivityStatusTypeSucceeded, now.Add(-2*time.Hour)),
+		createPromotedActivity("myapp", "4", "1.0.4", "production", v1.ActivityStatusTypeFailed, now.Add(-1*time.Hour)),
+		createPromotedActivity("myapp", "5", "1.0.5", "staging", v1.ActivityStatusTypeSucceeded, now.Add(-1*time.Hour)),
+		createPromotedActivity("other", "1", "2.0.0", "production", v1.ActivityStatusTypeSucceeded, now.Add(-1*time.Hour)),
+	}
+
+	promoted := kube.PromotedActivities(activities, "myapp", "production")
+	versions := []string{}
+	for _, a := range promoted {
+		versions = append(versions, a.Spec.Version)
+	}
+	assert.Equal(t, []string{"1.0.3", "1.0.2", "1.0.1"}, versions, "promoted versions")
+
+	previous := kube.FindPreviousPromotedActivity(activities, "myapp", "production", "1.0.3")
+	if assert.NotNil(t, previous, "previous version") {
+		assert.Equal(t, "1.0.2", previous.Spec.Version, "previous version")
+	}
+
+	previous = kube.FindPreviousPromotedActivity(activities, "myapp", "production", "1.0.2")
+	if assert.NotNil(t, previous, "previous version of a rolled back version") {
+		assert.Equal(t, "1.0.3", previous.Spec.Version, "previous version")
+	}
+
+	previous = kube.FindPreviousPromotedActivity(activities, "myapp", "staging", "1.0.5")
+	assert.Nil(t, previous, "no previous version in staging")
+}
+
+func createPromotedActivity(app string, build string, version string, envName string, status v1.ActivityStatusType, completed time.Time) v1.PipelineActivity {
+	return v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "myorg-" + app + "-master-" + build,
+		},
+		Spec: v1.PipelineActivitySpec{
+			Pipeline: "myorg/" + app + "/master",
+			Build:    build,
+			Version:  version,
+			Steps: []v1.PipelineActivityStep{
+				{
+					Kind: v1.ActivityStepKindTypePromote,
+					Promote: &v1.PromoteActivityStep{
+						CoreActivityStep: v1.CoreActivityStep{
+							Status: status,
+							CompletedTimestamp: &metav1.Time{
+								Time: completed,
+							},
+						},
+						Environment: envName,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,221 @@
+package kube
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AnnotationIngressCanary marks an nginx ingress as the canary of another ingress for the same host
+	AnnotationIngressCanary = "nginx.ingress.kubernetes.io/canary"
+	// AnnotationIngressCanaryWeight is the percentage of traffic an nginx canary ingress receives
+	AnnotationIngressCanaryWeight = "nginx.ingress.kubernetes.io/canary-weight"
+
+	// CanarySuffix is the suffix added to the release, service and ingress names of the new version during a rollout
+	CanarySuffix = "-canary"
+)
+
+var (
+	// DefaultCanaryWeights are the traffic percentages used for a canary rollout if none are specified
+	DefaultCanaryWeights = []int32{10, 50, 100}
+)
+
+// CanaryName returns the name of the canary resource for the given release, service or ingress name
+func CanaryName(name string) string {
+	return name + CanarySuffix
+}
+
+// RolloutWeights returns the traffic percentages the new version receives at each step of the rollout.
+// The weights are always increasing and the last step always sends all traffic to the new version
+func RolloutWeights(strategy *v1.RolloutStrategy) []int32 {
+	if strategy == nil || strategy.Kind == v1.RolloutKindTypeBlueGreen {
+		return []int32{100}
+	}
+	weights := strategy.Weights
+	if len(weights) == 0 {
+		weights = DefaultCanaryWeights
+	}
+	answer := []int32{}
+	var last int32
+	for _, w := range weights {
+		if w <= last || w <= 0 {
+			continue
+		}
+		if w >= 100 {
+			break
+		}
+		answer = append(answer, w)
+		last = w
+	}
+	return append(answer, 100)
+}
+
+// GetOrCreatePromoteRolloutStep returns the rollout step of the promotion for the given traffic weight, lazily creating it
+func GetOrCreatePromoteRolloutStep(ps *v1.PromoteActivityStep, weight int32) *v1.PromoteRolloutStep {
+	for i := range ps.Rollout {
+		if ps.Rollout[i].Weight == weight {
+			return &ps.Rollout[i]
+		}
+	}
+	ps.Rollout = append(ps.Rollout, v1.PromoteRolloutStep{
+		Weight: weight,
+	})
+	return &ps.Rollout[len(ps.Rollout)-1]
+}
+
+// UpdatePromoteRolloutStep updates the status of the rollout step, setting its timestamps
+func UpdatePromoteRolloutStep(r *v1.PromoteRolloutStep, status v1.ActivityStatusType, message string) {
+	now := &meta_v1.Time{
+		Time: time.Now(),
+	}
+	if r.StartedTimestamp == nil {
+		r.StartedTimestamp = now
+	}
+	if status.IsTerminated() && r.CompletedTimestamp == nil {
+		r.CompletedTimestamp = now
+	}
+	r.Status = status
+	if message != "" {
+		r.Message = message
+	}
+}
+
+// RolloutResumeWeight returns the weight from which an unfinished rollout of the promotion should continue and true
+// if the rollout was started and has not failed or completed. The rollout continues from the highest weight which was
+// healthy, or the first weight if none has been checked yet, as at least that much traffic was sent to the new version
+func RolloutResumeWeight(ps *v1.PromoteActivityStep) (int32, bool) {
+	if len(ps.Rollout) == 0 || ps.Status.IsTerminated() {
+		return 0, false
+	}
+	first := ps.Rollout[0]
+	if first.Status == "" || first.Status == v1.ActivityStatusTypePending {
+		return 0, false
+	}
+	weight := first.Weight
+	for _, r := range ps.Rollout {
+		switch r.Status {
+		case v1.ActivityStatusTypeFailed, v1.ActivityStatusTypeError, v1.ActivityStatusTypeAborted:
+			return 0, false
+		case v1.ActivityStatusTypeSucceeded:
+			if r.Weight > weight {
+				weight = r.Weight
+			}
+		}
+	}
+	return weight, true
+}
+
+// CheckRolloutPods returns an error if any of the given pods of a rollout are not ready or have restarted
+// more than the maximum number of times
+func CheckRolloutPods(pods []corev1.Pod, maxRestarts int32) error {
+	if len(pods) == 0 {
+		return fmt.Errorf("no pods are running")
+	}
+	for i := range pods {
+		pod := &pods[i]
+		restarts := GetPodRestarts(pod)
+		if restarts > maxRestarts {
+			return fmt.Errorf("pod %s has restarted %d times which is more than the maximum of %d", pod.Name, restarts, maxRestarts)
+		}
+		if !IsPodReady(pod) {
+			return fmt.Errorf("pod %s is not ready and has status %s", pod.Name, PodStatus(pod))
+		}
+	}
+	return nil
+}
+
+// CreateCanaryIngress creates an nginx canary ingress from the ingress of the current version which sends the given
+// percentage of traffic to the canary service
+func CreateCanaryIngress(stable *v1beta1.Ingress, stableService string, canaryService string, weight int32) *v1beta1.Ingress {
+	annotations := map[string]string{}
+	for k, v := range stable.Annotations {
+		annotations[k] = v
+	}
+	annotations[AnnotationIngressCanary] = "true"
+	annotations[AnnotationIngressCanaryWeight] = strconv.Itoa(int(weight))
+
+	answer := &v1beta1.Ingress{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:        CanaryName(stable.Name),
+			Namespace:   stable.Namespace,
+			Labels:      stable.Labels,
+			Annotations: annotations,
+		},
+	}
+	stable.Spec.DeepCopyInto(&answer.Spec)
+
+	// the TLS configuration is owned by the stable ingress
+	answer.Spec.TLS = nil
+	if answer.Spec.Backend != nil && answer.Spec.Backend.ServiceName == stableService {
+		answer.Spec.Backend.ServiceName = canaryService
+	}
+	for i := range answer.Spec.Rules {
+		http := answer.Spec.Rules[i].HTTP
+		if http == nil {
+			continue
+		}
+		for j := range http.Paths {
+			backend := &http.Paths[j].Backend
+			if backend.ServiceName == stableService {
+				backend.ServiceName = canaryService
+			}
+		}
+	}
+	return answer
+}
+
+// ServiceHost returns the fully qualified host name of the service in the namespace
+func ServiceHost(service string, ns string) string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", service, ns)
+}
+
+// IngressHosts returns the hosts of the rules of the ingress
+func IngressHosts(ingress *v1beta1.Ingress) []string {
+	answer := []string{}
+	for _, rule := range ingress.Spec.Rules {
+		if rule.Host != "" {
+			answer = append(answer, rule.Host)
+		}
+	}
+	return answer
+}
+
+// CreateVirtualService creates an Istio VirtualService which splits the traffic for the hosts between the stable and
+// canary services with the given percentage going to the canary
+func CreateVirtualService(ns string, name string, hosts []string, stableService string, canaryService string, weight int32) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "networking.istio.io/v1alpha3",
+		"kind":       "VirtualService",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": ns,
+		},
+		"spec": map[string]interface{}{
+			"hosts": hosts,
+			"http": []interface{}{
+				map[string]interface{}{
+					"route": []interface{}{
+						map[string]interface{}{
+							"destination": map[string]interface{}{
+								"host": stableService,
+							},
+							"weight": 100 - weight,
+						},
+						map[string]interface{}{
+							"destination": map[string]interface{}{
+								"host": canaryService,
+							},
+							"weight": weight,
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,184 @@
+package kube_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRolloutWeights(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		strategy *v1.RolloutStrategy
+		expected []int32
+	}{
+		{"no strategy", nil, []int32{100}},
+		{"blue green", &v1.RolloutStrategy{Kind: v1.RolloutKindTypeBlueGreen, Weights: []int32{10, 50}}, []int32{100}},
+		{"default canary", &v1.RolloutStrategy{Kind: v1.RolloutKindTypeCanary}, []int32{10, 50, 100}},
+		{"custom canary", &v1.RolloutStrategy{Kind: v1.RolloutKindTypeCanary, Weights: []int32{5, 25}}, []int32{5, 25, 100}},
+		{"unordered canary", &v1.RolloutStrategy{Kind: v1.RolloutKindTypeCanary, Weights: []int32{0, 20, 10, 40, 100, 60}}, []int32{20, 40, 100}},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, kube.RolloutWeights(test.strategy), test.name)
+	}
+}
+
+func TestPromoteRolloutSteps(t *testing.T) {
+	t.Parallel()
+	promote := &v1.PromoteActivityStep{}
+
+	step := kube.GetOrCreatePromoteRolloutStep(promote, 10)
+	kube.UpdatePromoteRolloutStep(step, v1.ActivityStatusTypeRunning, "")
+	assert.NotNil(t, step.StartedTimestamp, "started")
+	assert.Nil(t, step.CompletedTimestamp, "not completed")
+
+	step = kube.GetOrCreatePromoteRolloutStep(promote, 10)
+	kube.UpdatePromoteRolloutStep(step, v1.ActivityStatusTypeSucceeded, "healthy")
+	step = kube.GetOrCreatePromoteRolloutStep(promote, 50)
+	kube.UpdatePromoteRolloutStep(step, v1.ActivityStatusTypeFailed, "too many restarts")
+
+	if assert.Len(t, promote.Rollout, 2, "rollout steps") {
+		assert.Equal(t, int32(10), promote.Rollout[0].Weight)
+		assert.Equal(t, v1.ActivityStatusTypeSucceeded, promote.Rollout[0].Status)
+		assert.Equal(t, "healthy", promote.Rollout[0].Message)
+		assert.NotNil(t, promote.Rollout[0].CompletedTimestamp, "completed")
+		assert.Equal(t, int32(50), promote.Rollout[1].Weight)
+		assert.Equal(t, v1.ActivityStatusTypeFailed, promote.Rollout[1].Status)
+	}
+}
+
+func TestRolloutResumeWeight(t *testing.T) {
+	t.Parallel()
+	promote := &v1.PromoteActivityStep{}
+	_, ok := kube.RolloutResumeWeight(promote)
+	assert.False(t, ok, "no rollout")
+
+	step := kube.GetOrCreatePromoteRolloutStep(promote, 10)
+	kube.UpdatePromoteRolloutStep(step, v1.ActivityStatusTypePending, "")
+	_, ok = kube.RolloutResumeWeight(promote)
+	assert.False(t, ok, "rollout not started")
+
+	kube.UpdatePromoteRolloutStep(step, v1.ActivityStatusTypeRunning, "")
+	weight, ok := kube.RolloutResumeWeight(promote)
+	assert.True(t, ok, "rollout started")
+	assert.Equal(t, int32(10), weight)
+
+	kube.UpdatePromoteRolloutStep(step, v1.ActivityStatusTypeSucceeded, "healthy")
+	step = kube.GetOrCreatePromoteRolloutStep(promote, 50)
+	kube.UpdatePromoteRolloutStep(step, v1.ActivityStatusTypeRunning, "")
+	weight, ok = kube.RolloutResumeWeight(promote)
+	assert.True(t, ok, "rollout in progress")
+	assert.Equal(t, int32(10), weight, "resume from the highest healthy weight")
+
+	kube.UpdatePromoteRolloutStep(step, v1.ActivityStatusTypeFailed, "too many restarts")
+	_, ok = kube.RolloutResumeWeight(promote)
+	assert.False(t, ok, "rollout failed")
+}
+
+func TestCheckRolloutPods(t *testing.T) {
+	t.Parallel()
+	readyPod := func(name string, restarts int32) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{
+						Type:   corev1.PodReady,
+						Status: corev1.ConditionTrue,
+					},
+				},
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						RestartCount: restarts,
+					},
+				},
+			},
+		}
+	}
+	pending := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pending",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+		},
+	}
+
+	assert.NoError(t, kube.CheckRolloutPods([]corev1.Pod{readyPod("a", 0), readyPod("b", 1)}, 1), "healthy pods")
+	assert.Error(t, kube.CheckRolloutPods([]corev1.Pod{readyPod("a", 0), readyPod("b", 2)}, 1), "too many restarts")
+	assert.Error(t, kube.CheckRolloutPods([]corev1.Pod{readyPod("a", 0), pending}, 1), "pod not ready")
+	assert.Error(t, kube.CheckRolloutPods(nil, 1), "no pods")
+}
+
+func TestCreateCanaryIngress(t *testing.T) {
+	t.Parallel()
+	stable := &v1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myapp",
+			Namespace: "jx-production",
+			Annotations: map[string]string{
+				"kubernetes.io/ingress.class": "nginx",
+			},
+		},
+		Spec: v1beta1.IngressSpec{
+			TLS: []v1beta1.IngressTLS{
+				{
+					Hosts: []string{"myapp.example.com"},
+				},
+			},
+			Rules: []v1beta1.IngressRule{
+				{
+					Host: "myapp.example.com",
+					IngressRuleValue: v1beta1.IngressRuleValue{
+						HTTP: &v1beta1.HTTPIngressRuleValue{
+							Paths: []v1beta1.HTTPIngressPath{
+								{
+									Backend: v1beta1.IngressBackend{
+										ServiceName: "myapp",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{"myapp.example.com"}, kube.IngressHosts(stable))
+
+	canary := kube.CreateCanaryIngress(stable, "myapp", "myapp-canary", 10)
+	assert.Equal(t, "myapp-canary", canary.Name)
+	assert.Equal(t, "jx-production", canary.Namespace)
+	assert.Equal(t, "true", canary.Annotations[kube.AnnotationIngressCanary])
+	assert.Equal(t, "10", canary.Annotations[kube.AnnotationIngressCanaryWeight])
+	assert.Equal(t, "nginx", canary.Annotations["kubernetes.io/ingress.class"])
+	assert.Empty(t, canary.Spec.TLS, "TLS")
+	assert.Equal(t, "myapp.example.com", canary.Spec.Rules[0].Host)
+	assert.Equal(t, "myapp-canary", canary.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName)
+
+	// the stable ingress must not be modified
+	assert.Equal(t, "myapp", stable.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName)
+	assert.Len(t, stable.Annotations, 1, "stable annotations")
+}
+
+func TestCreateVirtualService(t *testing.T) {
+	t.Parallel()
+	hosts := []string{kube.ServiceHost("myapp", "jx-production"), "myapp.example.com"}
+	vs := kube.CreateVirtualService("jx-production", "myapp", hosts, "myapp", "myapp-canary", 10)
+
+	spec := vs["spec"].(map[string]interface{})
+	assert.Equal(t, []string{"myapp.jx-production.svc.cluster.local", "myapp.example.com"}, spec["hosts"])
+	routes := spec["http"].([]interface{})[0].(map[string]interface{})["route"].([]interface{})
+	assert.Equal(t, int32(90), routes[0].(map[string]interface{})["weight"])
+	assert.Equal(t, int32(10), routes[1].(map[string]interface{})["weight"])
+}
@@ -26,8 +26,8 @@ const (
 )
 
 type ServiceURL struct {
-	Name string
-	URL  string
+	Name string `json:"name"`
+	URL  string `json:"url"`
 }
 
 func GetServices(client kubernetes.Interface, ns string) (map[string]*v1.Service, error) {
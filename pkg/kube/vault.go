@@ -29,9 +29,9 @@ const (
 
 // Vault stores some details of a Vault resource
 type Vault struct {
-	Name                   string
-	URL                    string
-	AuthServiceAccountName string
+	Name                   string `json:"name"`
+	URL                    string `json:"url"`
+	AuthServiceAccountName string `json:"authServiceAccountName"`
 }
 
 // GCPConfig keeps the configuration for Google Cloud
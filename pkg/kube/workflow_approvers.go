@@ -0,0 +1,114 @@
+package kube
+
+import (
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io"
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// WorkflowApproveVerb the RBAC verb which allows a user to approve a step of a Workflow. The step is the
+	// subresource of the workflows resource so that each step can have its own approvers
+	WorkflowApproveVerb = "approve"
+
+	workflowsResource = "workflows"
+)
+
+// WorkflowApproversRoleName returns the name of the Role and RoleBinding which allow the approvers of the step of
+// the workflow to approve it
+func WorkflowApproversRoleName(workflowName string, stepName string) string {
+	return ToValidName("jx-approve-" + workflowName + "-" + stepName)
+}
+
+// EnsureWorkflowApproversRole creates or updates the Role and RoleBinding which allow the given Kubernetes users to
+// approve the step of the workflow so that the API server checks the identity of the user approving the step
+func EnsureWorkflowApproversRole(kubeClient kubernetes.Interface, ns string, workflowName string, stepName string, approvers []string) error {
+	name := WorkflowApproversRoleName(workflowName, stepName)
+	labels := map[string]string{
+		LabelKind: ValueKindWorkflowApprovers,
+	}
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{jenkinsio.GroupName},
+				Resources:     []string{workflowsResource + "/" + stepName},
+				ResourceNames: []string{workflowName},
+				Verbs:         []string{WorkflowApproveVerb},
+			},
+		},
+	}
+	roles := kubeClient.RbacV1().Roles(ns)
+	existingRole, err := roles.Get(name, metav1.GetOptions{})
+	if err == nil {
+		existingRole.Rules = role.Rules
+		_, err = roles.Update(existingRole)
+	} else if apierrors.IsNotFound(err) {
+		_, err = roles.Create(role)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to save Role %s in namespace %s", name, ns)
+	}
+
+	subjects := []rbacv1.Subject{}
+	for _, approver := range approvers {
+		subjects = append(subjects, rbacv1.Subject{
+			Kind:     rbacv1.UserKind,
+			APIGroup: rbacv1.GroupName,
+			Name:     approver,
+		})
+	}
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Subjects: subjects,
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     name,
+		},
+	}
+	bindings := kubeClient.RbacV1().RoleBindings(ns)
+	existingBinding, err := bindings.Get(name, metav1.GetOptions{})
+	if err == nil {
+		existingBinding.Subjects = binding.Subjects
+		_, err = bindings.Update(existingBinding)
+	} else if apierrors.IsNotFound(err) {
+		_, err = bindings.Create(binding)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to save RoleBinding %s in namespace %s", name, ns)
+	}
+	return nil
+}
+
+// CanApproveWorkflowStep asks the API server whether the authenticated user of the client is allowed to approve the
+// step of the workflow returning the reason if the user is not allowed
+func CanApproveWorkflowStep(kubeClient kubernetes.Interface, ns string, workflowName string, stepName string) (bool, string, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   ns,
+				Verb:        WorkflowApproveVerb,
+				Group:       jenkinsio.GroupName,
+				Resource:    workflowsResource,
+				Subresource: stepName,
+				Name:        workflowName,
+			},
+		},
+	}
+	answer, err := kubeClient.AuthorizationV1().SelfSubjectAccessReviews().Create(review)
+	if err != nil {
+		return false, "", errors.Wrapf(err, "failed to review access to approve step %s of Workflow %s", stepName, workflowName)
+	}
+	return answer.Status.Allowed, answer.Status.Reason, nil
+}
@@ -0,0 +1,41 @@
+package kube_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube_mocks "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEnsureWorkflowApproversRole(t *testing.T) {
+	t.Parallel()
+	kubeClient := kube_mocks.NewSimpleClientset()
+	ns := "jx"
+	name := kube.WorkflowApproversRoleName("default", "production-gate")
+	assert.Equal(t, "jx-approve-default-production-gate", name)
+
+	err := kube.EnsureWorkflowApproversRole(kubeClient, ns, "default", "production-gate", []string{"alice"})
+	assert.NoError(t, err)
+	err = kube.EnsureWorkflowApproversRole(kubeClient, ns, "default", "production-gate", []string{"alice", "bob"})
+	assert.NoError(t, err)
+
+	role, err := kubeClient.RbacV1().Roles(ns).Get(name, meta_v1.GetOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, role.Rules, 1) {
+		rule := role.Rules[0]
+		assert.Equal(t, []string{"workflows/production-gate"}, rule.Resources)
+		assert.Equal(t, []string{"default"}, rule.ResourceNames)
+		assert.Equal(t, []string{kube.WorkflowApproveVerb}, rule.Verbs)
+	}
+
+	binding, err := kubeClient.RbacV1().RoleBindings(ns).Get(name, meta_v1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, name, binding.RoleRef.Name)
+	users := []string{}
+	for _, subject := range binding.Subjects {
+		users = append(users, subject.Name)
+	}
+	assert.Equal(t, []string{"alice", "bob"}, users)
+}
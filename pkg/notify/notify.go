@@ -0,0 +1,191 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EventKind the kind of a notification event
+type EventKind string
+
+const (
+	// EventKindBuildFailed a pipeline failed
+	EventKindBuildFailed EventKind = "buildFailed"
+	// EventKindRelease a new version of an application was released
+	EventKindRelease EventKind = "release"
+	// EventKindPromotePullRequest a Pull Request was created to promote a version to an Environment
+	EventKindPromotePullRequest EventKind = "promotePullRequest"
+	// EventKindPreview a preview environment is available
+	EventKindPreview EventKind = "preview"
+
+	// MaxChangelogItems the maximum number of commits or issues included in a release notification
+	MaxChangelogItems = 10
+)
+
+// EventKinds the supported kinds of notification event
+var EventKinds = []string{
+	string(EventKindBuildFailed),
+	string(EventKindRelease),
+	string(EventKindPromotePullRequest),
+	string(EventKindPreview),
+}
+
+// Event an event which should be posted to chat
+type Event struct {
+	Kind       EventKind
+	Owner      string
+	Repository string
+
+	// uniquely identifies the event so that it is only posted once
+	Key string
+
+	// identifies repeated events such as the failures of the same pipeline so they can be rate limited
+	RateKey string
+
+	Time    time.Time
+	Message string
+}
+
+// ActivityEvents returns the notification events for the current state of the pipeline activity
+func ActivityEvents(activity *v1.PipelineActivity) []*Event {
+	answer := []*Event{}
+	spec := &activity.Spec
+	owner, repo := activityRepository(activity)
+
+	if spec.Status == v1.ActivityStatusTypeFailed || spec.Status == v1.ActivityStatusTypeError {
+		lines := []string{fmt.Sprintf("Build %s #%s %s", spec.Pipeline, spec.Build, strings.ToLower(string(spec.Status)))}
+		if spec.BuildURL != "" {
+			lines = append(lines, spec.BuildURL)
+		}
+		if spec.LastCommitMessage != "" {
+			lines = append(lines, "Last commit: "+firstLine(spec.LastCommitMessage)+" "+spec.LastCommitURL)
+		}
+		answer = append(answer, &Event{
+			Kind:       EventKindBuildFailed,
+			Owner:      owner,
+			Repository: repo,
+			Key:        activity.Name + "/" + string(EventKindBuildFailed),
+			RateKey:    spec.Pipeline + "/" + string(EventKindBuildFailed),
+			Time:       timeOf(spec.CompletedTimestamp, spec.StartedTimestamp),
+			Message:    strings.Join(lines, "\n"),
+		})
+	}
+
+	for _, step := range spec.Steps {
+		if step.Promote != nil && step.Promote.PullRequest != nil && step.Promote.PullRequest.PullRequestURL != "" {
+			pr := step.Promote.PullRequest
+			answer = append(answer, &Event{
+				Kind:       EventKindPromotePullRequest,
+				Owner:      owner,
+				Repository: repo,
+				Key:        activity.Name + "/" + string(EventKindPromotePullRequest) + "/" + step.Promote.Environment,
+				Time:       timeOf(pr.StartedTimestamp, step.Promote.StartedTimestamp),
+				Message: fmt.Sprintf("Promoting %s version %s to %s: %s",
+					applicationName(activity), spec.Version, step.Promote.Environment, pr.PullRequestURL),
+			})
+		}
+		if step.Preview != nil && step.Preview.ApplicationURL != "" {
+			preview := step.Preview
+			message := fmt.Sprintf("Preview of %s is available at %s", spec.Pipeline, preview.ApplicationURL)
+			if preview.PullRequestURL != "" {
+				message += "\nPull Request: " + preview.PullRequestURL
+			}
+			answer = append(answer, &Event{
+				Kind:       EventKindPreview,
+				Owner:      owner,
+				Repository: repo,
+				Key:        activity.Name + "/" + string(EventKindPreview),
+				Time:       timeOf(preview.CompletedTimestamp, preview.StartedTimestamp),
+				Message:    message,
+			})
+		}
+	}
+	return answer
+}
+
+// ReleaseEvent returns the notification event for a release including a summary of its changelog
+func ReleaseEvent(release *v1.Release) *Event {
+	spec := &release.Spec
+	name := spec.Name
+	if name == "" {
+		name = spec.GitRepository
+	}
+	lines := []string{fmt.Sprintf("Released %s version %s", name, spec.Version)}
+	if spec.ReleaseNotesURL != "" {
+		lines = append(lines, "Release notes: "+spec.ReleaseNotesURL)
+	}
+	if len(spec.Commits) > 0 {
+		lines = append(lines, "Commits:")
+		for i, commit := range spec.Commits {
+			if i >= MaxChangelogItems {
+				lines = append(lines, fmt.Sprintf("• ... and %d more", len(spec.Commits)-i))
+				break
+			}
+			line := "• " + firstLine(commit.Message)
+			if len(commit.SHA) > 7 {
+				line += " (" + commit.SHA[0:7] + ")"
+			}
+			if commit.Author != nil && commit.Author.Name != "" {
+				line += " " + commit.Author.Name
+			}
+			lines = append(lines, line)
+		}
+	}
+	if len(spec.Issues) > 0 {
+		lines = append(lines, "Issues:")
+		for i, issue := range spec.Issues {
+			if i >= MaxChangelogItems {
+				lines = append(lines, fmt.Sprintf("• ... and %d more", len(spec.Issues)-i))
+				break
+			}
+			lines = append(lines, strings.TrimSpace(fmt.Sprintf("• #%s %s %s", issue.ID, issue.Title, issue.URL)))
+		}
+	}
+	return &Event{
+		Kind:       EventKindRelease,
+		Owner:      spec.GitOwner,
+		Repository: spec.GitRepository,
+		Key:        release.Namespace + "/" + release.Name,
+		Time:       release.CreationTimestamp.Time,
+		Message:    strings.Join(lines, "\n"),
+	}
+}
+
+// activityRepository returns the git owner and repository of the activity defaulting them from the pipeline name
+func activityRepository(activity *v1.PipelineActivity) (string, string) {
+	owner := activity.Spec.GitOwner
+	repo := activity.Spec.GitRepository
+	paths := strings.Split(activity.Spec.Pipeline, "/")
+	if owner == "" && len(paths) > 2 {
+		owner = paths[0]
+	}
+	if repo == "" && len(paths) > 2 {
+		repo = paths[1]
+	}
+	return owner, repo
+}
+
+func applicationName(activity *v1.PipelineActivity) string {
+	_, repo := activityRepository(activity)
+	if repo == "" {
+		return activity.Spec.Pipeline
+	}
+	return repo
+}
+
+func timeOf(timestamps ...*metav1.Time) time.Time {
+	for _, t := range timestamps {
+		if t != nil {
+			return t.Time
+		}
+	}
+	return time.Time{}
+}
+
+func firstLine(text string) string {
+	return strings.TrimSpace(strings.SplitN(text, "\n", 2)[0])
+}
@@ -0,0 +1,205 @@
+package notify_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/notify"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestActivityEvents(t *testing.T) {
+	t.Parallel()
+	now := metav1.Now()
+	activity := &v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "myorg-myapp-master-3",
+		},
+		Spec: v1.PipelineActivitySpec{
+			Pipeline:           "myorg/myapp/master",
+			Build:              "3",
+			Version:            "1.0.3",
+			Status:             v1.ActivityStatusTypeFailed,
+			CompletedTimestamp: &now,
+			BuildURL:           "http://jenkins/job/3",
+			LastCommitMessage:  "fix the thing\n\nmore details",
+			Steps: []v1.PipelineActivityStep{
+				{
+					Kind: v1.ActivityStepKindTypePromote,
+					Promote: &v1.PromoteActivityStep{
+						Environment: "staging",
+						PullRequest: &v1.PromotePullRequestStep{
+							PullRequestURL: "https://github.com/myorg/environment-staging/pull/1",
+						},
+					},
+				},
+				{
+					Kind: v1.ActivityStepKindTypePreview,
+					Preview: &v1.PreviewActivityStep{
+						ApplicationURL: "http://myapp.jx-myorg-myapp-pr-1.example.com",
+					},
+				},
+			},
+		},
+	}
+
+	events := notify.ActivityEvents(activity)
+	if assert.Len(t, events, 3, "events") {
+		assert.Equal(t, notify.EventKindBuildFailed, events[0].Kind)
+		assert.Equal(t, "myorg", events[0].Owner)
+		assert.Equal(t, "myapp", events[0].Repository)
+		assert.Equal(t, "myorg/myapp/master/buildFailed", events[0].RateKey)
+		assert.Equal(t, now.Time, events[0].Time)
+		assert.Contains(t, events[0].Message, "Build myorg/myapp/master #3 failed")
+		assert.Contains(t, events[0].Message, "Last commit: fix the thing")
+		assert.NotContains(t, events[0].Message, "more details")
+
+		assert.Equal(t, notify.EventKindPromotePullRequest, events[1].Kind)
+		assert.Equal(t, "Promoting myapp version 1.0.3 to staging: https://github.com/myorg/environment-staging/pull/1", events[1].Message)
+
+		assert.Equal(t, notify.EventKindPreview, events[2].Kind)
+		assert.Contains(t, events[2].Message, "http://myapp.jx-myorg-myapp-pr-1.example.com")
+	}
+
+	activity.Spec.Status = v1.ActivityStatusTypeSucceeded
+	activity.Spec.Steps = nil
+	assert.Empty(t, notify.ActivityEvents(activity), "events for a successful build")
+}
+
+func TestReleaseEvent(t *testing.T) {
+	t.Parallel()
+	release := &v1.Release{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myapp-1-0-3",
+			Namespace: "jx",
+		},
+		Spec: v1.ReleaseSpec{
+			Name:            "myapp",
+			Version:         "1.0.3",
+			GitOwner:        "myorg",
+			GitRepository:   "myapp",
+			ReleaseNotesURL: "https://github.com/myorg/myapp/releases/tag/v1.0.3",
+			Commits: []v1.CommitSummary{
+				{
+					Message: "add a feature\n\nsigned off",
+					SHA:     "0123456789abcdef",
+					Author: &v1.UserDetails{
+						Name: "Jane",
+					},
+				},
+			},
+			Issues: []v1.IssueSummary{
+				{
+					ID:    "12",
+					Title: "the feature",
+					URL:   "https://github.com/myorg/myapp/issues/12",
+				},
+			},
+		},
+	}
+
+	event := notify.ReleaseEvent(release)
+	assert.Equal(t, notify.EventKindRelease, event.Kind)
+	assert.Equal(t, "jx/myapp-1-0-3", event.Key)
+	assert.Equal(t, `Released myapp version 1.0.3
+Release notes: https://github.com/myorg/myapp/releases/tag/v1.0.3
+Commits:
+• add a feature (0123456) Jane
+Issues:
+• #12 the feature https://github.com/myorg/myapp/issues/12`, event.Message)
+}
+
+func TestChannels(t *testing.T) {
+	t.Parallel()
+	chatConfig := &config.ChatConfig{
+		DeveloperChannel: "#dev",
+		UserChannel:      "#users",
+	}
+	assert.Equal(t, []string{"#dev"}, notify.Channels(chatConfig, notify.EventKindBuildFailed))
+	assert.Equal(t, []string{"#users"}, notify.Channels(chatConfig, notify.EventKindRelease))
+
+	chatConfig.Notifications = []*config.ChatNotificationRule{
+		{
+			Kinds:   []string{"buildFailed"},
+			Channel: "#alerts",
+		},
+		{
+			Kinds: []string{"buildFailed", "preview"},
+		},
+	}
+	assert.Equal(t, []string{"#alerts", "#dev"}, notify.Channels(chatConfig, notify.EventKindBuildFailed))
+	assert.Equal(t, []string{"#dev"}, notify.Channels(chatConfig, notify.EventKindPreview))
+	assert.Empty(t, notify.Channels(chatConfig, notify.EventKindRelease))
+	assert.Empty(t, notify.Channels(nil, notify.EventKindRelease))
+}
+
+func TestTrackerRateLimitsRepeatedFailures(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	tracker := notify.NewTracker(time.Hour)
+	tracker.Now = func() time.Time {
+		return now
+	}
+	failure := func(build string) *notify.Event {
+		return &notify.Event{
+			Kind:    notify.EventKindBuildFailed,
+			Key:     "myorg-myapp-master-" + build + "/buildFailed",
+			RateKey: "myorg/myapp/master/buildFailed",
+		}
+	}
+
+	ok, suppressed := tracker.Accept(failure("1"))
+	assert.True(t, ok, "first failure")
+	assert.Equal(t, 0, suppressed)
+
+	ok, _ = tracker.Accept(failure("1"))
+	assert.False(t, ok, "same failure posted twice")
+
+	now = now.Add(10 * time.Minute)
+	ok, _ = tracker.Accept(failure("2"))
+	assert.False(t, ok, "failure within the rate limit")
+	ok, _ = tracker.Accept(failure("3"))
+	assert.False(t, ok, "failure within the rate limit")
+
+	now = now.Add(time.Hour)
+	ok, suppressed = tracker.Accept(failure("4"))
+	assert.True(t, ok, "failure after the rate limit")
+	assert.Equal(t, 2, suppressed, "suppressed failures")
+
+	ok, _ = tracker.Accept(&notify.Event{Key: "myorg-myapp-master-4/preview"})
+	assert.True(t, ok, "events without a rate key are not rate limited")
+}
+
+func TestTrackerForgetsEventsWhichWereNotPosted(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	tracker := notify.NewTracker(time.Hour)
+	tracker.Now = func() time.Time {
+		return now
+	}
+	failure := func(build string) *notify.Event {
+		return &notify.Event{
+			Kind:    notify.EventKindBuildFailed,
+			Key:     "myorg-myapp-master-" + build + "/buildFailed",
+			RateKey: "myorg/myapp/master/buildFailed",
+		}
+	}
+
+	ok, _ := tracker.Accept(failure("1"))
+	assert.True(t, ok, "first failure")
+	ok, _ = tracker.Accept(failure("2"))
+	assert.False(t, ok, "failure within the rate limit")
+
+	now = now.Add(2 * time.Hour)
+	ok, suppressed := tracker.Accept(failure("3"))
+	assert.True(t, ok, "failure after the rate limit")
+	assert.Equal(t, 1, suppressed, "suppressed failures")
+	tracker.Forget(failure("3"), suppressed)
+
+	ok, suppressed = tracker.Accept(failure("3"))
+	assert.True(t, ok, "failure which could not be posted is accepted again")
+	assert.Equal(t, 1, suppressed, "suppressed failures are still reported")
+}
@@ -0,0 +1,40 @@
+package notify
+
+import (
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// Channels returns the chat channels the event kind should be posted to.
+//
+// If the chat configuration has no notification rules then releases are posted to the user channel and all other
+// events to the developer channel
+func Channels(chatConfig *config.ChatConfig, kind EventKind) []string {
+	answer := []string{}
+	if chatConfig == nil {
+		return answer
+	}
+	if len(chatConfig.Notifications) == 0 {
+		channel := chatConfig.DeveloperChannel
+		if kind == EventKindRelease && chatConfig.UserChannel != "" {
+			channel = chatConfig.UserChannel
+		}
+		if channel != "" {
+			answer = append(answer, channel)
+		}
+		return answer
+	}
+	for _, rule := range chatConfig.Notifications {
+		if rule == nil || !rule.Matches(string(kind)) {
+			continue
+		}
+		channel := rule.Channel
+		if channel == "" {
+			channel = chatConfig.DeveloperChannel
+		}
+		if channel != "" && util.StringArrayIndex(answer, channel) < 0 {
+			answer = append(answer, channel)
+		}
+	}
+	return answer
+}
@@ -0,0 +1,97 @@
+package notify
+
+import (
+	"sync"
+	"time"
+)
+
+// Tracker remembers which events have been posted so that each event is only posted once and rate limits repeated
+// events such as the failures of the same pipeline
+type Tracker struct {
+	// the minimum time between notifications with the same rate key
+	RateLimit time.Duration
+
+	// how long posted events are remembered for
+	Retention time.Duration
+
+	// returns the current time
+	Now func() time.Time
+
+	lock       sync.Mutex
+	posted     map[string]time.Time
+	lastPosted map[string]time.Time
+	suppressed map[string]int
+}
+
+// NewTracker creates a new tracker with the given rate limit
+func NewTracker(rateLimit time.Duration) *Tracker {
+	return &Tracker{
+		RateLimit:  rateLimit,
+		Retention:  24 * time.Hour,
+		Now:        time.Now,
+		posted:     map[string]time.Time{},
+		lastPosted: map[string]time.Time{},
+		suppressed: map[string]int{},
+	}
+}
+
+// Accept returns true if the event should be posted. If the event is posted after earlier events with the same rate
+// key were suppressed then the number of suppressed events is returned. The event is recorded as posted so Forget
+// must be called if it could not be posted
+func (t *Tracker) Accept(event *Event) (bool, int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.Now()
+	t.prune(now)
+	if _, ok := t.posted[event.Key]; ok {
+		return false, 0
+	}
+	t.posted[event.Key] = now
+
+	if event.RateKey == "" || t.RateLimit <= 0 {
+		return true, 0
+	}
+	last, ok := t.lastPosted[event.RateKey]
+	if ok && now.Sub(last) < t.RateLimit {
+		t.suppressed[event.RateKey]++
+		return false, 0
+	}
+	suppressed := t.suppressed[event.RateKey]
+	delete(t.suppressed, event.RateKey)
+	t.lastPosted[event.RateKey] = now
+	return true, suppressed
+}
+
+// Forget forgets an accepted event which could not be posted so that it is accepted again when it is next seen.
+// The suppressed count returned by Accept is restored so that it is reported when the event is posted
+func (t *Tracker) Forget(event *Event, suppressed int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.posted, event.Key)
+	if event.RateKey == "" || t.RateLimit <= 0 {
+		return
+	}
+	// the event was only accepted after the rate limit of the previous post had expired so there is no need to
+	// remember when that was
+	delete(t.lastPosted, event.RateKey)
+	if suppressed > 0 {
+		t.suppressed[event.RateKey] += suppressed
+	}
+}
+
+// prune forgets events older than the retention period
+func (t *Tracker) prune(now time.Time) {
+	for key, posted := range t.posted {
+		if now.Sub(posted) > t.Retention {
+			delete(t.posted, key)
+		}
+	}
+	for key, posted := range t.lastPosted {
+		if now.Sub(posted) > t.Retention && now.Sub(posted) > t.RateLimit {
+			delete(t.lastPosted, key)
+			delete(t.suppressed, key)
+		}
+	}
+}
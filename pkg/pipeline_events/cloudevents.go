@@ -0,0 +1,83 @@
+package pipline_events
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const (
+	// CloudEventsSpecVersion the version of the CloudEvents specification of the events
+	CloudEventsSpecVersion = "1.0"
+
+	cloudEventsContentType = "application/cloudevents+json"
+)
+
+// CloudEventsSink publishes events as CloudEvents using the HTTP protocol binding. Events are sent in binary content
+// mode by default with the attributes as ce- headers or in structured content mode as a JSON CloudEvent
+type CloudEventsSink struct {
+	Client     *http.Client
+	SinkName   string
+	URL        string
+	Structured bool
+}
+
+// CloudEvent a CloudEvent in the JSON event format used by structured content mode
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Type            string          `json:"type"`
+	Source          string          `json:"source"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            string          `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+}
+
+// NewCloudEventsSink creates a sink which sends CloudEvents to the URL
+func NewCloudEventsSink(name string, u string, structured bool) *CloudEventsSink {
+	return &CloudEventsSink{
+		Client:     newSinkClient(),
+		SinkName:   name,
+		URL:        u,
+		Structured: structured,
+	}
+}
+
+// Name returns the name of the sink
+func (s *CloudEventsSink) Name() string {
+	return s.SinkName
+}
+
+// Send sends the event as a CloudEvent
+func (s *CloudEventsSink) Send(event *Event) error {
+	eventTime := event.Time.Format(time.RFC3339Nano)
+	if s.Structured {
+		ce := &CloudEvent{
+			SpecVersion:     CloudEventsSpecVersion,
+			ID:              event.ID,
+			Type:            event.Type,
+			Source:          event.Source,
+			Subject:         event.Subject,
+			Time:            eventTime,
+			DataContentType: "application/json",
+			Data:            event.Data,
+		}
+		data, err := json.Marshal(ce)
+		if err != nil {
+			return err
+		}
+		return postEvent(s.Client, s.URL, data, map[string]string{
+			"Content-Type": cloudEventsContentType,
+		})
+	}
+	return postEvent(s.Client, s.URL, event.Data, map[string]string{
+		"Content-Type":   "application/json",
+		"ce-specversion": CloudEventsSpecVersion,
+		"ce-id":          event.ID,
+		"ce-type":        event.Type,
+		"ce-source":      event.Source,
+		"ce-subject":     event.Subject,
+		"ce-time":        eventTime,
+	})
+}
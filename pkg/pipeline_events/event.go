@@ -0,0 +1,69 @@
+package pipline_events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// EventSource the source of the events published by Jenkins X
+	EventSource = "https://jenkins-x.io/pipeline-events"
+
+	// EventTypeActivity the type of the event published when a PipelineActivity is created or updated
+	EventTypeActivity = "io.jenkins-x.pipelineactivity"
+
+	// EventTypeRelease the type of the event published when a Release is created or updated
+	EventTypeRelease = "io.jenkins-x.release"
+)
+
+// Event is a change to a PipelineActivity or Release which is published to the event sinks
+type Event struct {
+	// ID the unique ID of the event. Events for the same version of a resource have the same ID so that
+	// consumers can ignore duplicate deliveries
+	ID string `json:"id"`
+	// Type the type of the event such as io.jenkins-x.pipelineactivity
+	Type string `json:"type"`
+	// Source the source of the event
+	Source string `json:"source"`
+	// Subject the namespace and name of the resource
+	Subject string `json:"subject"`
+	// Time when the event was created
+	Time time.Time `json:"time"`
+	// Data the resource as JSON
+	Data json.RawMessage `json:"data"`
+}
+
+// NewActivityEvent creates an event for the PipelineActivity
+func NewActivityEvent(a *v1.PipelineActivity) (*Event, error) {
+	return newEvent(EventTypeActivity, &a.ObjectMeta, a)
+}
+
+// NewReleaseEvent creates an event for the Release
+func NewReleaseEvent(r *v1.Release) (*Event, error) {
+	return newEvent(EventTypeRelease, &r.ObjectMeta, r)
+}
+
+func newEvent(eventType string, metadata *metav1.ObjectMeta, resource interface{}) (*Event, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	id := string(metadata.UID)
+	if id == "" {
+		id = metadata.Namespace + "-" + metadata.Name
+	}
+	if metadata.ResourceVersion != "" {
+		id += "-" + metadata.ResourceVersion
+	}
+	return &Event{
+		ID:      id,
+		Type:    eventType,
+		Source:  EventSource,
+		Subject: metadata.Namespace + "/" + metadata.Name,
+		Time:    time.Now().UTC(),
+		Data:    data,
+	}, nil
+}
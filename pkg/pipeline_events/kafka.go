@@ -0,0 +1,65 @@
+package pipline_events
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const kafkaJSONContentType = "application/vnd.kafka.json.v2+json"
+
+// KafkaSink writes events to a Kafka topic using the Kafka REST Proxy. The events are keyed by their subject so
+// that all the events of a resource are written to the same partition in order
+type KafkaSink struct {
+	Client   *http.Client
+	SinkName string
+	URL      string
+	Topic    string
+}
+
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key,omitempty"`
+	Value *Event `json:"value"`
+}
+
+// NewKafkaSink creates a sink which writes events to the topic using the Kafka REST Proxy at the URL
+func NewKafkaSink(name string, u string, topic string) *KafkaSink {
+	return &KafkaSink{
+		Client:   newSinkClient(),
+		SinkName: name,
+		URL:      u,
+		Topic:    topic,
+	}
+}
+
+// Name returns the name of the sink
+func (s *KafkaSink) Name() string {
+	return s.SinkName
+}
+
+// Send writes the event to the topic
+func (s *KafkaSink) Send(event *Event) error {
+	records := &kafkaRecords{
+		Records: []kafkaRecord{
+			{
+				Key:   event.Subject,
+				Value: event,
+			},
+		},
+	}
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	u := util.UrlJoin(s.URL, "topics", url.PathEscape(s.Topic))
+	return postEvent(s.Client, u, data, map[string]string{
+		"Content-Type": kafkaJSONContentType,
+		"Accept":       "application/vnd.kafka.v2+json",
+	})
+}
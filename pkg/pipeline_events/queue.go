@@ -0,0 +1,138 @@
+package pipline_events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// EventQueue is a persistent queue of the events to be delivered to each sink. Events are written to a directory per
+// sink before they are sent and are only removed once the sink accepts them so that events are delivered at least once
+// even if a sink is down or the process restarts
+type EventQueue struct {
+	Dir string
+
+	lock sync.Mutex
+}
+
+// NewEventQueue creates a queue which stores the events in the directory
+func NewEventQueue(dir string) (*EventQueue, error) {
+	err := os.MkdirAll(dir, util.DefaultWritePermissions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the event queue directory %s: %s", dir, err)
+	}
+	return &EventQueue{
+		Dir: dir,
+	}, nil
+}
+
+// Enqueue stores the event to be delivered to the sink
+func (q *EventQueue) Enqueue(sinkName string, event *Event) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	dir, err := q.sinkDir(sinkName)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%020d-%s.json", time.Now().UnixNano(), safeFileName(event.ID))
+	// lets write to a temporary file first so that a partially written event is never delivered
+	tmpFile := filepath.Join(dir, "."+name)
+	err = ioutil.WriteFile(tmpFile, data, util.DefaultWritePermissions)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, filepath.Join(dir, name))
+}
+
+// Len returns the number of events waiting to be delivered to the sink
+func (q *EventQueue) Len(sinkName string) (int, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	files, err := q.files(sinkName)
+	return len(files), err
+}
+
+// Flush sends the queued events to the sink in the order they were queued. It stops at the first event which fails so
+// that the remaining events are retried in order on the next flush
+func (q *EventQueue) Flush(sink EventSink) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	files, err := q.files(sink.Name())
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		event := &Event{}
+		err = json.Unmarshal(data, event)
+		if err != nil {
+			// lets not block the queue on an event we can never send
+			os.Remove(file)
+			return fmt.Errorf("discarded invalid queued event %s: %s", file, err)
+		}
+		err = sink.Send(event)
+		if err != nil {
+			return fmt.Errorf("failed to send event %s to sink %s, %d events queued for retry: %s", event.ID, sink.Name(), len(files), err)
+		}
+		err = os.Remove(file)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+func (q *EventQueue) files(sinkName string) ([]string, error) {
+	dir, err := q.sinkDir(sinkName)
+	if err != nil {
+		return nil, err
+	}
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	answer := []string{}
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		answer = append(answer, filepath.Join(dir, name))
+	}
+	sort.Strings(answer)
+	return answer, nil
+}
+
+func (q *EventQueue) sinkDir(sinkName string) (string, error) {
+	dir := filepath.Join(q.Dir, safeFileName(sinkName))
+	err := os.MkdirAll(dir, util.DefaultWritePermissions)
+	return dir, err
+}
+
+func safeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '-'
+		}
+		return r
+	}, name)
+}
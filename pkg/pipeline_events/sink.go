@@ -0,0 +1,152 @@
+package pipline_events
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// SinkKindCloudEvents publishes events as CloudEvents over HTTP
+	SinkKindCloudEvents = "cloudevents"
+
+	// SinkKindWebhook posts events to a webhook signed with a HMAC of the body
+	SinkKindWebhook = "webhook"
+
+	// SinkKindKafka writes events to a Kafka topic via the Kafka REST Proxy
+	SinkKindKafka = "kafka"
+
+	defaultSinkTimeout = 30 * time.Second
+)
+
+// SinkKinds the kinds of event sinks
+var SinkKinds = []string{SinkKindCloudEvents, SinkKindWebhook, SinkKindKafka}
+
+// EventSink publishes events to another system
+type EventSink interface {
+	// Name returns the unique name of the sink which is used to queue failed deliveries
+	Name() string
+
+	// Send publishes the event returning an error if it was not delivered
+	Send(event *Event) error
+}
+
+// SinksConfig the configuration of the event sinks
+type SinksConfig struct {
+	Sinks []SinkConfig `json:"sinks,omitempty"`
+}
+
+// SinkConfig the configuration of an event sink
+type SinkConfig struct {
+	// Name the name of the sink which defaults to the kind
+	Name string `json:"name,omitempty"`
+	// Kind the kind of the sink
+	Kind string `json:"kind"`
+	// URL the URL to send events to. For Kafka this is the URL of the Kafka REST Proxy
+	URL string `json:"url"`
+	// Secret the secret used to sign the webhook requests
+	Secret string `json:"secret,omitempty"`
+	// Topic the Kafka topic
+	Topic string `json:"topic,omitempty"`
+	// Structured whether CloudEvents are sent in structured rather than binary content mode
+	Structured bool `json:"structured,omitempty"`
+}
+
+// LoadSinksConfig parses the YAML configuration of the event sinks
+func LoadSinksConfig(data []byte) (*SinksConfig, error) {
+	config := &SinksConfig{}
+	err := yaml.Unmarshal(data, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the event sinks configuration: %s", err)
+	}
+	return config, nil
+}
+
+// ToYAML marshals the configuration of the event sinks
+func (c *SinksConfig) ToYAML() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+// AddSink adds the sink replacing any existing sink of the same name
+func (c *SinksConfig) AddSink(sink SinkConfig) {
+	for i, s := range c.Sinks {
+		if s.SinkName() == sink.SinkName() {
+			c.Sinks[i] = sink
+			return
+		}
+	}
+	c.Sinks = append(c.Sinks, sink)
+}
+
+// SinkName returns the name of the sink defaulting to the kind
+func (c *SinkConfig) SinkName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Kind
+}
+
+// CreateSinks creates the event sinks of the configuration
+func (c *SinksConfig) CreateSinks() ([]EventSink, error) {
+	answer := []EventSink{}
+	for i := range c.Sinks {
+		sink, err := CreateSink(&c.Sinks[i])
+		if err != nil {
+			return answer, err
+		}
+		answer = append(answer, sink)
+	}
+	return answer, nil
+}
+
+// CreateSink creates the event sink for the configuration
+func CreateSink(config *SinkConfig) (EventSink, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("no URL configured for the %s event sink", config.SinkName())
+	}
+	switch config.Kind {
+	case SinkKindCloudEvents:
+		return NewCloudEventsSink(config.SinkName(), config.URL, config.Structured), nil
+	case SinkKindWebhook:
+		return NewWebhookSink(config.SinkName(), config.URL, config.Secret), nil
+	case SinkKindKafka:
+		if config.Topic == "" {
+			return nil, fmt.Errorf("no topic configured for the %s event sink", config.SinkName())
+		}
+		return NewKafkaSink(config.SinkName(), config.URL, config.Topic), nil
+	default:
+		return nil, util.InvalidOption("kind", config.Kind, SinkKinds)
+	}
+}
+
+// postEvent posts the body to the URL with the headers returning an error if the response is not successful
+func postEvent(client *http.Client, u string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequest("POST", u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error POSTing event to %s: %s", u, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("error response POSTing event to %s: %s %s", u, resp.Status, string(data))
+	}
+	return nil
+}
+
+func newSinkClient() *http.Client {
+	return &http.Client{
+		Timeout: defaultSinkTimeout,
+	}
+}
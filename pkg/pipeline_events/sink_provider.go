@@ -0,0 +1,122 @@
+package pipline_events
+
+import (
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// Retrier is implemented by providers which queue events that failed to be delivered
+type Retrier interface {
+	// Retry retries sending the queued events
+	Retry() error
+}
+
+// SinkProvider implements PipelineEventsProvider by publishing the events to the event sinks. If a queue is
+// configured the events are queued for each sink and retried until they are delivered
+type SinkProvider struct {
+	Sinks []EventSink
+	Queue *EventQueue
+}
+
+// NewSinkProvider creates a provider which publishes events to the sinks using the queue to retry failed deliveries
+func NewSinkProvider(queue *EventQueue, sinks ...EventSink) PipelineEventsProvider {
+	return &SinkProvider{
+		Sinks: sinks,
+		Queue: queue,
+	}
+}
+
+// SendActivity publishes the activity to the sinks
+func (p *SinkProvider) SendActivity(a *v1.PipelineActivity) error {
+	event, err := NewActivityEvent(a)
+	if err != nil {
+		return err
+	}
+	return p.SendEvent(event)
+}
+
+// SendRelease publishes the release to the sinks
+func (p *SinkProvider) SendRelease(r *v1.Release) error {
+	event, err := NewReleaseEvent(r)
+	if err != nil {
+		return err
+	}
+	return p.SendEvent(event)
+}
+
+// SendEvent publishes the event to all of the sinks
+func (p *SinkProvider) SendEvent(event *Event) error {
+	errs := []error{}
+	for _, sink := range p.Sinks {
+		var err error
+		if p.Queue != nil {
+			err = p.Queue.Enqueue(sink.Name(), event)
+			if err == nil {
+				err = p.Queue.Flush(sink)
+			}
+		} else {
+			err = sink.Send(event)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return util.CombineErrors(errs...)
+}
+
+// Retry retries sending any queued events to the sinks
+func (p *SinkProvider) Retry() error {
+	if p.Queue == nil {
+		return nil
+	}
+	errs := []error{}
+	for _, sink := range p.Sinks {
+		err := p.Queue.Flush(sink)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return util.CombineErrors(errs...)
+}
+
+// Providers sends the events to each of the providers
+type Providers []PipelineEventsProvider
+
+// SendActivity sends the activity to all of the providers
+func (p Providers) SendActivity(a *v1.PipelineActivity) error {
+	errs := []error{}
+	for _, provider := range p {
+		err := provider.SendActivity(a)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return util.CombineErrors(errs...)
+}
+
+// SendRelease sends the release to all of the providers
+func (p Providers) SendRelease(r *v1.Release) error {
+	errs := []error{}
+	for _, provider := range p {
+		err := provider.SendRelease(r)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return util.CombineErrors(errs...)
+}
+
+// Retry retries sending the queued events of the providers
+func (p Providers) Retry() error {
+	errs := []error{}
+	for _, provider := range p {
+		retrier, ok := provider.(Retrier)
+		if ok {
+			err := retrier.Retry()
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return util.CombineErrors(errs...)
+}
@@ -0,0 +1,177 @@
+package pipline_events_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	pe "github.com/jenkins-x/jx/pkg/pipeline_events"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func createTestActivity() *v1.PipelineActivity {
+	return &v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "myorg-myapp-master-1",
+			Namespace:       "jx",
+			UID:             "1234",
+			ResourceVersion: "5",
+		},
+	}
+}
+
+func TestCloudEventsSink(t *testing.T) {
+	t.Parallel()
+	var headers http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	event, err := pe.NewActivityEvent(createTestActivity())
+	assert.NoError(t, err)
+
+	sink := pe.NewCloudEventsSink("cloudevents", server.URL, false)
+	err = sink.Send(event)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0", headers.Get("ce-specversion"))
+	assert.Equal(t, "1234-5", headers.Get("ce-id"))
+	assert.Equal(t, pe.EventTypeActivity, headers.Get("ce-type"))
+	assert.Equal(t, "jx/myorg-myapp-master-1", headers.Get("ce-subject"))
+	assert.Equal(t, string(event.Data), string(body))
+
+	sink = pe.NewCloudEventsSink("cloudevents", server.URL, true)
+	err = sink.Send(event)
+	assert.NoError(t, err)
+	assert.Equal(t, "application/cloudevents+json", headers.Get("Content-Type"))
+	ce := &pe.CloudEvent{}
+	err = json.Unmarshal(body, ce)
+	assert.NoError(t, err)
+	assert.Equal(t, "1234-5", ce.ID)
+	assert.Equal(t, pe.EventTypeActivity, ce.Type)
+	assert.Equal(t, string(event.Data), string(ce.Data))
+}
+
+func TestWebhookSinkSignsBody(t *testing.T) {
+	t.Parallel()
+	valid := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		valid = pe.ValidateWebhookSignature("mysecret", body, r.Header.Get(pe.WebhookSignatureHeader))
+		assert.Equal(t, pe.EventTypeActivity, r.Header.Get(pe.WebhookEventHeader))
+	}))
+	defer server.Close()
+
+	event, err := pe.NewActivityEvent(createTestActivity())
+	assert.NoError(t, err)
+	err = pe.NewWebhookSink("webhook", server.URL, "mysecret").Send(event)
+	assert.NoError(t, err)
+	assert.True(t, valid, "the webhook signature should be valid")
+	assert.False(t, pe.ValidateWebhookSignature("another", []byte("{}"), pe.SignWebhookBody("mysecret", []byte("{}"))))
+}
+
+func TestKafkaSink(t *testing.T) {
+	t.Parallel()
+	var path string
+	var records struct {
+		Records []struct {
+			Key   string    `json:"key"`
+			Value *pe.Event `json:"value"`
+		} `json:"records"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		assert.Equal(t, "application/vnd.kafka.json.v2+json", r.Header.Get("Content-Type"))
+		json.NewDecoder(r.Body).Decode(&records)
+	}))
+	defer server.Close()
+
+	event, err := pe.NewActivityEvent(createTestActivity())
+	assert.NoError(t, err)
+	err = pe.NewKafkaSink("kafka", server.URL+"/", "pipelines").Send(event)
+	assert.NoError(t, err)
+	assert.Equal(t, "/topics/pipelines", path)
+	if assert.Len(t, records.Records, 1) {
+		assert.Equal(t, "jx/myorg-myapp-master-1", records.Records[0].Key)
+		assert.Equal(t, "1234-5", records.Records[0].Value.ID)
+	}
+}
+
+func TestSinkProviderQueuesFailedEvents(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-event-queue")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	up := false
+	received := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		received = append(received, r.Header.Get(pe.WebhookDeliveryHeader))
+	}))
+	defer server.Close()
+
+	queue, err := pe.NewEventQueue(dir)
+	assert.NoError(t, err)
+	sink := pe.NewWebhookSink("webhook", server.URL, "")
+	provider := pe.NewSinkProvider(queue, sink)
+
+	activity := createTestActivity()
+	err = provider.SendActivity(activity)
+	assert.Error(t, err, "the sink is down")
+	activity.ResourceVersion = "6"
+	err = provider.SendActivity(activity)
+	assert.Error(t, err, "the sink is down")
+
+	count, err := queue.Len("webhook")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	up = true
+	err = provider.(pe.Retrier).Retry()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1234-5", "1234-6"}, received)
+
+	count, err = queue.Len("webhook")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestLoadSinksConfig(t *testing.T) {
+	t.Parallel()
+	config, err := pe.LoadSinksConfig([]byte(`sinks:
+- kind: cloudevents
+  url: http://broker
+- name: audit
+  kind: webhook
+  url: https://example.com/hook
+  secret: mysecret
+- kind: kafka
+  url: http://kafka-rest-proxy:8082
+`))
+	assert.NoError(t, err)
+	assert.Len(t, config.Sinks, 3)
+	_, err = config.CreateSinks()
+	assert.Error(t, err, "the kafka sink has no topic")
+
+	config.AddSink(pe.SinkConfig{Kind: pe.SinkKindKafka, URL: "http://kafka-rest-proxy:8082", Topic: "pipelines"})
+	assert.Len(t, config.Sinks, 3)
+	sinks, err := config.CreateSinks()
+	assert.NoError(t, err)
+	if assert.Len(t, sinks, 3) {
+		assert.Equal(t, "cloudevents", sinks[0].Name())
+		assert.Equal(t, "audit", sinks[1].Name())
+		assert.Equal(t, "kafka", sinks[2].Name())
+	}
+}
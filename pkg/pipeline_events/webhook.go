@@ -0,0 +1,73 @@
+package pipline_events
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	// WebhookSignatureHeader the header containing the HMAC SHA256 signature of the body such as sha256=<hex digest>
+	WebhookSignatureHeader = "X-Jx-Signature"
+
+	// WebhookEventHeader the header containing the type of the event
+	WebhookEventHeader = "X-Jx-Event"
+
+	// WebhookDeliveryHeader the header containing the ID of the event
+	WebhookDeliveryHeader = "X-Jx-Delivery"
+)
+
+// WebhookSink posts events as JSON to a webhook. If a secret is configured the body is signed using HMAC SHA256
+// so that the receiver can verify the events came from Jenkins X
+type WebhookSink struct {
+	Client   *http.Client
+	SinkName string
+	URL      string
+	Secret   string
+}
+
+// NewWebhookSink creates a sink which posts events to the URL
+func NewWebhookSink(name string, u string, secret string) *WebhookSink {
+	return &WebhookSink{
+		Client:   newSinkClient(),
+		SinkName: name,
+		URL:      u,
+		Secret:   secret,
+	}
+}
+
+// Name returns the name of the sink
+func (s *WebhookSink) Name() string {
+	return s.SinkName
+}
+
+// Send posts the event to the webhook
+func (s *WebhookSink) Send(event *Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{
+		"Content-Type":        "application/json",
+		WebhookEventHeader:    event.Type,
+		WebhookDeliveryHeader: event.ID,
+	}
+	if s.Secret != "" {
+		headers[WebhookSignatureHeader] = SignWebhookBody(s.Secret, data)
+	}
+	return postEvent(s.Client, s.URL, data, headers)
+}
+
+// SignWebhookBody returns the signature of the body using the secret in the format: sha256=<hex digest>
+func SignWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidateWebhookSignature returns true if the signature is valid for the body and secret
+func ValidateWebhookSignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(signature), []byte(SignWebhookBody(secret, body)))
+}
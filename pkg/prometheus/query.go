@@ -0,0 +1,96 @@
+package prometheus
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultQueryTimeout = 30 * time.Second
+)
+
+// QueryResponse is the response of the Prometheus instant query API
+type QueryResponse struct {
+	Status    string    `json:"status"`
+	ErrorType string    `json:"errorType,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Data      QueryData `json:"data"`
+}
+
+// QueryData is the data of an instant query response
+type QueryData struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+// VectorSample is a single sample of a vector result
+type VectorSample struct {
+	Metric map[string]string `json:"metric"`
+	Value  []interface{}     `json:"value"`
+}
+
+// QueryScalar evaluates the PromQL query against the Prometheus server at the given URL and returns the value of the
+// scalar result or of the first sample of a vector result
+func QueryScalar(prometheusURL string, query string) (float64, error) {
+	u := strings.TrimSuffix(prometheusURL, "/") + "/api/v1/query?query=" + url.QueryEscape(query)
+	client := &http.Client{
+		Timeout: defaultQueryTimeout,
+	}
+	resp, err := client.Get(u)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query Prometheus at %s: %s", prometheusURL, err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the Prometheus response: %s", err)
+	}
+	return ParseScalarResponse(body)
+}
+
+// ParseScalarResponse parses the body of an instant query response returning the value of the scalar result or of the
+// first sample of a vector result
+func ParseScalarResponse(body []byte) (float64, error) {
+	response := &QueryResponse{}
+	err := json.Unmarshal(body, response)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse the Prometheus response %s: %s", string(body), err)
+	}
+	if response.Status != "success" {
+		return 0, fmt.Errorf("Prometheus query failed with %s: %s", response.ErrorType, response.Error)
+	}
+	var value []interface{}
+	switch response.Data.ResultType {
+	case "scalar":
+		err = json.Unmarshal(response.Data.Result, &value)
+		if err != nil {
+			return 0, err
+		}
+	case "vector":
+		samples := []VectorSample{}
+		err = json.Unmarshal(response.Data.Result, &samples)
+		if err != nil {
+			return 0, err
+		}
+		if len(samples) == 0 {
+			return 0, fmt.Errorf("Prometheus query returned no samples")
+		}
+		value = samples[0].Value
+	default:
+		return 0, fmt.Errorf("unsupported Prometheus result type %s", response.Data.ResultType)
+	}
+	if len(value) != 2 {
+		return 0, fmt.Errorf("invalid Prometheus sample %v", value)
+	}
+	text, ok := value[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("invalid Prometheus sample value %v", value[1])
+	}
+	return strconv.ParseFloat(text, 64)
+}
@@ -0,0 +1,34 @@
+package prometheus_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScalarResponse(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		body     string
+		expected float64
+		err      bool
+	}{
+		{"vector", `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"app":"myapp"},"value":[1435781451.781,"0.05"]}]}}`, 0.05, false},
+		{"scalar", `{"status":"success","data":{"resultType":"scalar","result":[1435781451.781,"3"]}}`, 3, false},
+		{"empty vector", `{"status":"success","data":{"resultType":"vector","result":[]}}`, 0, true},
+		{"matrix", `{"status":"success","data":{"resultType":"matrix","result":[]}}`, 0, true},
+		{"error", `{"status":"error","errorType":"bad_data","error":"parse error"}`, 0, true},
+		{"invalid json", `not json`, 0, true},
+	}
+	for _, test := range tests {
+		actual, err := prometheus.ParseScalarResponse([]byte(test.body))
+		if test.err {
+			assert.Error(t, err, test.name)
+		} else {
+			assert.NoError(t, err, test.name)
+			assert.Equal(t, test.expected, actual, test.name)
+		}
+	}
+}
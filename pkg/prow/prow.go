@@ -2,9 +2,11 @@ package prow
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
 	build "github.com/knative/build/pkg/apis/build/v1alpha1"
@@ -38,6 +40,14 @@ type Kind string
 
 const ProwConfigMapName = "config"
 
+const ProwPluginsConfigMapName = "plugins"
+
+// approvedLabel the label the approve plugin adds to pull requests which Tide requires before merging
+const approvedLabel = "approved"
+
+// labelPlugins the plugins which rely on labels so cannot be used with Git providers which do not support labels
+var labelPlugins = []string{"approve", "hold", "lgtm", "lifecycle", "size", "wip"}
+
 // Options for Prow
 type Options struct {
 	KubeClient           kubernetes.Interface
@@ -47,9 +57,26 @@ type Options struct {
 	DraftPack            string
 	EnvironmentNamespace string
 	Context              string
+
+	// GitProvider protects the branches of the repositories on Git providers other than GitHub where Prow's
+	// branch protector cannot be used
+	GitProvider gits.GitProvider
 }
 
-func add(kubeClient kubernetes.Interface, repos []string, ns string, kind Kind, draftPack, environmentNamespace string, context string) error {
+// RepositoryConfig the effective Prow configuration of a repository
+type RepositoryConfig struct {
+	Repo              string   `json:"repo"`
+	Presubmits        []string `json:"presubmits,omitempty"`
+	Postsubmits       []string `json:"postsubmits,omitempty"`
+	RequiredContexts  []string `json:"requiredContexts,omitempty"`
+	RequiredApprovals int      `json:"requiredApprovals,omitempty"`
+	TideLabels        []string `json:"tideLabels,omitempty"`
+	TideMissingLabels []string `json:"tideMissingLabels,omitempty"`
+	Merge             bool     `json:"merge"`
+	Plugins           []string `json:"plugins,omitempty"`
+}
+
+func add(kubeClient kubernetes.Interface, repos []string, ns string, kind Kind, draftPack, environmentNamespace string, context string, gitProvider gits.GitProvider) error {
 
 	if len(repos) == 0 {
 		return fmt.Errorf("no repo defined")
@@ -62,6 +89,7 @@ func add(kubeClient kubernetes.Interface, repos []string, ns string, kind Kind,
 		DraftPack:            draftPack,
 		EnvironmentNamespace: environmentNamespace,
 		Context:              context,
+		GitProvider:          gitProvider,
 	}
 
 	err := o.AddProwConfig()
@@ -72,16 +100,36 @@ func add(kubeClient kubernetes.Interface, repos []string, ns string, kind Kind,
 	return o.AddProwPlugins()
 }
 
-func AddEnvironment(kubeClient kubernetes.Interface, repos []string, ns, environmentNamespace string) error {
-	return add(kubeClient, repos, ns, Environment, "", environmentNamespace, "")
+// AddEnvironment adds the environment repositories to Prow. The Git provider is used to protect the branches of
+// repositories which are not on GitHub and can be nil
+func AddEnvironment(kubeClient kubernetes.Interface, repos []string, ns, environmentNamespace string, gitProvider gits.GitProvider) error {
+	return add(kubeClient, repos, ns, Environment, "", environmentNamespace, "", gitProvider)
+}
+
+// AddApplication adds the application repositories to Prow. The Git provider is used to protect the branches of
+// repositories which are not on GitHub and can be nil
+func AddApplication(kubeClient kubernetes.Interface, repos []string, ns, draftPack string, gitProvider gits.GitProvider) error {
+	return add(kubeClient, repos, ns, Application, draftPack, "", "", gitProvider)
 }
 
-func AddApplication(kubeClient kubernetes.Interface, repos []string, ns, draftPack string) error {
-	return add(kubeClient, repos, ns, Application, draftPack, "", "")
+// AddProtection adds the required context to the protected branches of the repositories. The Git provider is used to
+// protect the branches of repositories which are not on GitHub and can be nil
+func AddProtection(kubeClient kubernetes.Interface, repos []string, context string, ns string, gitProvider gits.GitProvider) error {
+	return add(kubeClient, repos, ns, Protection, "", "", context, gitProvider)
 }
 
-func AddProtection(kubeClient kubernetes.Interface, repos []string, context string, ns string) error {
-	return add(kubeClient, repos, ns, Protection, "", "", context)
+// gitKind returns the kind of Git provider of the repositories
+func (o *Options) gitKind() string {
+	if o.GitProvider != nil {
+		return o.GitProvider.Kind()
+	}
+	return gits.KindGitHub
+}
+
+// supportsLabels returns false if the Git provider has no pull request labels so that approvals are enforced by the
+// Git provider rather than by Tide
+func (o *Options) supportsLabels() bool {
+	return o.gitKind() != gits.KindBitBucketServer
 }
 
 // create Git repo?
@@ -205,9 +253,13 @@ func (o *Options) createPreSubmitApplication() config.Presubmit {
 func (o *Options) addRepoToTideConfig(t *config.Tide, repo string, kind Kind) error {
 	switch o.Kind {
 	case Application:
+		if !o.supportsLabels() {
+			// there is no approved label so lets merge once the pull request has the approvals the Git provider requires
+			return o.addRepoToEnvironmentTideQuery(t, repo)
+		}
 		found := false
 		for index, q := range t.Queries {
-			if util.Contains(q.Labels, "approved") {
+			if util.Contains(q.Labels, approvedLabel) {
 				found = true
 				repos := t.Queries[index].Repos
 				if !util.Contains(repos, repo) {
@@ -222,22 +274,7 @@ func (o *Options) addRepoToTideConfig(t *config.Tide, repo string, kind Kind) er
 			t.Queries = append(t.Queries, o.createApplicationTideQuery())
 		}
 	case Environment:
-		found := false
-		for index, q := range t.Queries {
-			if !util.Contains(q.Labels, "approved") {
-				found = true
-				repos := t.Queries[index].Repos
-				if !util.Contains(repos, repo) {
-					repos = append(repos, repo)
-					t.Queries[index].Repos = repos
-				}
-			}
-		}
-
-		if !found {
-			log.Infof("Failed to find 'environment' tide config, adding...\n")
-			t.Queries = append(t.Queries, o.createEnvironmentTideQuery())
-		}
+		return o.addRepoToEnvironmentTideQuery(t, repo)
 	case Protection:
 		// No Tide config needed for Protection
 	default:
@@ -246,6 +283,26 @@ func (o *Options) addRepoToTideConfig(t *config.Tide, repo string, kind Kind) er
 	return nil
 }
 
+func (o *Options) addRepoToEnvironmentTideQuery(t *config.Tide, repo string) error {
+	found := false
+	for index, q := range t.Queries {
+		if !util.Contains(q.Labels, approvedLabel) {
+			found = true
+			repos := t.Queries[index].Repos
+			if !util.Contains(repos, repo) {
+				repos = append(repos, repo)
+				t.Queries[index].Repos = repos
+			}
+		}
+	}
+
+	if !found {
+		log.Infof("Failed to find 'environment' tide config, adding...\n")
+		t.Queries = append(t.Queries, o.createEnvironmentTideQuery())
+	}
+	return nil
+}
+
 func (o *Options) addRepoToBranchProtection(bp *config.BranchProtection, repoSpec string, context string, kind Kind) error {
 	bp.ProtectTested = true
 	if bp.Orgs == nil {
@@ -279,6 +336,15 @@ func (o *Options) addRepoToBranchProtection(bp *config.BranchProtection, repoSpe
 		if !util.Contains(contexts, ServerlessJenkins) {
 			contexts = append(contexts, ServerlessJenkins)
 		}
+		if !o.supportsLabels() && bp.Orgs[requiredOrg].Repos[requiredRepo].Policy.RequiredPullRequestReviews == nil {
+			// without the approve plugin the pull request approval is required by the Git provider instead
+			approvals := 1
+			repo := bp.Orgs[requiredOrg].Repos[requiredRepo]
+			repo.Policy.RequiredPullRequestReviews = &config.ReviewPolicy{
+				Approvals: &approvals,
+			}
+			bp.Orgs[requiredOrg].Repos[requiredRepo] = repo
+		}
 	case Environment:
 		if !util.Contains(contexts, PromotionBuild) {
 			contexts = append(contexts, PromotionBuild)
@@ -294,10 +360,39 @@ func (o *Options) addRepoToBranchProtection(bp *config.BranchProtection, repoSpe
 	return nil
 }
 
+// protectBranch applies the branch protection of the repository via the Git provider as Prow's branch protector
+// only supports GitHub
+func (o *Options) protectBranch(bp *config.BranchProtection, repoSpec string) error {
+	if o.GitProvider == nil || o.gitKind() == gits.KindGitHub {
+		return nil
+	}
+	s := strings.Split(repoSpec, "/")
+	if len(s) != 2 {
+		return fmt.Errorf("%s is not of the format org/repo", repoSpec)
+	}
+	protection := branchProtection(bp.Orgs[s[0]].Repos[s[1]].Policy)
+	log.Infof("Protecting the %s branch of %s\n", protection.Branch, util.ColorInfo(repoSpec))
+	return o.GitProvider.ProtectBranch(s[0], s[1], protection)
+}
+
+// branchProtection converts the Prow branch protection policy into the protection of the master branch
+func branchProtection(policy config.Policy) *gits.GitBranchProtection {
+	protection := &gits.GitBranchProtection{
+		Branch: "master",
+	}
+	if policy.RequiredStatusChecks != nil {
+		protection.RequiredContexts = policy.RequiredStatusChecks.Contexts
+	}
+	if policy.RequiredPullRequestReviews != nil && policy.RequiredPullRequestReviews.Approvals != nil {
+		protection.RequiredApprovals = *policy.RequiredPullRequestReviews.Approvals
+	}
+	return protection
+}
+
 func (o *Options) createApplicationTideQuery() config.TideQuery {
 	return config.TideQuery{
 		Repos:         []string{"jenkins-x/dummy"},
-		Labels:        []string{"approved"},
+		Labels:        []string{approvedLabel},
 		MissingLabels: []string{"do-not-merge", "do-not-merge/hold", "do-not-merge/work-in-progress", "needs-ok-to-test", "needs-rebase"},
 	}
 }
@@ -369,6 +464,10 @@ func (o *Options) AddProwConfig() error {
 		if err != nil {
 			return err
 		}
+		err = o.protectBranch(&prowConfig.BranchProtection, r)
+		if err != nil {
+			return err
+		}
 	}
 
 	for _, r := range o.Repos {
@@ -501,39 +600,20 @@ func (o *Options) GetBranchProtectionContexts(org string, repo string) ([]string
 // AddProwPlugins adds plugins to prow
 func (o *Options) AddProwPlugins() error {
 
-	pluginsList := []string{"config-updater", "approve", "assign", "blunderbuss", "help", "hold", "lgtm", "lifecycle", "size", "trigger", "wip", "heart", "cat", "override"}
+	pluginsList := o.pluginsList()
 
-	cm, err := o.KubeClient.CoreV1().ConfigMaps(o.NS).Get("plugins", metav1.GetOptions{})
-	create := true
-	pluginConfig := &plugins.Configuration{}
+	pluginConfig, create, err := o.GetPluginConfig()
 	if err != nil {
-		pluginConfig.Plugins = make(map[string][]string)
-		pluginConfig.Approve = []plugins.Approve{}
-
-		pluginConfig.ConfigUpdater.Maps = make(map[string]plugins.ConfigMapSpec)
-		pluginConfig.ConfigUpdater.Maps["prow/config.yaml"] = plugins.ConfigMapSpec{Name: ProwConfigMapName}
-		pluginConfig.ConfigUpdater.Maps["prow/plugins.yaml"] = plugins.ConfigMapSpec{Name: "plugins"}
-
-	} else {
-		create = false
-		err = yaml.Unmarshal([]byte(cm.Data["plugins.yaml"]), &pluginConfig)
-		if err != nil {
-			return err
-		}
-		if pluginConfig == nil {
-			pluginConfig = &plugins.Configuration{}
-		}
-		if len(pluginConfig.Plugins) == 0 {
-			pluginConfig.Plugins = make(map[string][]string)
-		}
-		if len(pluginConfig.Approve) == 0 {
-			pluginConfig.Approve = []plugins.Approve{}
-		}
+		return err
 	}
 
 	for _, r := range o.Repos {
 		pluginConfig.Plugins[r] = pluginsList
 
+		if !o.supportsLabels() {
+			// the approvals are required by the Git provider rather than the approve plugin
+			continue
+		}
 		a := plugins.Approve{
 			Repos:               []string{r},
 			ReviewActsAsApprove: true,
@@ -550,10 +630,10 @@ func (o *Options) AddProwPlugins() error {
 
 	data := make(map[string]string)
 	data["plugins.yaml"] = string(pluginYAML)
-	cm = &corev1.ConfigMap{
+	cm := &corev1.ConfigMap{
 		Data: data,
 		ObjectMeta: metav1.ObjectMeta{
-			Name: "plugins",
+			Name: ProwPluginsConfigMapName,
 		},
 	}
 	if create {
@@ -564,3 +644,117 @@ func (o *Options) AddProwPlugins() error {
 
 	return err
 }
+
+// pluginsList returns the plugins of the repositories leaving out the plugins which need labels if the Git provider
+// does not support them
+func (o *Options) pluginsList() []string {
+	pluginsList := []string{"config-updater", "approve", "assign", "blunderbuss", "help", "hold", "lgtm", "lifecycle", "size", "trigger", "wip", "heart", "cat", "override"}
+	if o.supportsLabels() {
+		return pluginsList
+	}
+	answer := []string{}
+	for _, p := range pluginsList {
+		if !util.Contains(labelPlugins, p) {
+			answer = append(answer, p)
+		}
+	}
+	return answer
+}
+
+// GetPluginConfig returns the plugin configuration of Prow and true if it does not exist yet
+func (o *Options) GetPluginConfig() (*plugins.Configuration, bool, error) {
+	cm, err := o.KubeClient.CoreV1().ConfigMaps(o.NS).Get(ProwPluginsConfigMapName, metav1.GetOptions{})
+	create := true
+	pluginConfig := &plugins.Configuration{}
+	if err != nil {
+		pluginConfig.Plugins = make(map[string][]string)
+		pluginConfig.Approve = []plugins.Approve{}
+
+		pluginConfig.ConfigUpdater.Maps = make(map[string]plugins.ConfigMapSpec)
+		pluginConfig.ConfigUpdater.Maps["prow/config.yaml"] = plugins.ConfigMapSpec{Name: ProwConfigMapName}
+		pluginConfig.ConfigUpdater.Maps["prow/plugins.yaml"] = plugins.ConfigMapSpec{Name: ProwPluginsConfigMapName}
+
+	} else {
+		create = false
+		err = yaml.Unmarshal([]byte(cm.Data["plugins.yaml"]), &pluginConfig)
+		if err != nil {
+			return pluginConfig, create, err
+		}
+		if pluginConfig == nil {
+			pluginConfig = &plugins.Configuration{}
+		}
+		if len(pluginConfig.Plugins) == 0 {
+			pluginConfig.Plugins = make(map[string][]string)
+		}
+		if len(pluginConfig.Approve) == 0 {
+			pluginConfig.Approve = []plugins.Approve{}
+		}
+	}
+	return pluginConfig, create, nil
+}
+
+// GetRepositoryConfigs returns the effective Prow configuration of each repository sorted by repository name
+func (o *Options) GetRepositoryConfigs() ([]*RepositoryConfig, error) {
+	prowConfig, _, err := o.GetProwConfig()
+	if err != nil {
+		return nil, err
+	}
+	pluginConfig, _, err := o.GetPluginConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	configs := map[string]*RepositoryConfig{}
+	repoConfig := func(repo string) *RepositoryConfig {
+		answer := configs[repo]
+		if answer == nil {
+			answer = &RepositoryConfig{
+				Repo: repo,
+			}
+			configs[repo] = answer
+		}
+		return answer
+	}
+	for repo, jobs := range prowConfig.Presubmits {
+		rc := repoConfig(repo)
+		for _, job := range jobs {
+			rc.Presubmits = append(rc.Presubmits, job.Name)
+		}
+	}
+	for repo, jobs := range prowConfig.Postsubmits {
+		rc := repoConfig(repo)
+		for _, job := range jobs {
+			rc.Postsubmits = append(rc.Postsubmits, job.Name)
+		}
+	}
+	for _, q := range prowConfig.Tide.Queries {
+		for _, repo := range q.Repos {
+			rc := repoConfig(repo)
+			if !rc.Merge {
+				rc.Merge = true
+				rc.TideLabels = q.Labels
+				rc.TideMissingLabels = q.MissingLabels
+			}
+		}
+	}
+	for org, prowOrg := range prowConfig.BranchProtection.Orgs {
+		for name, prowRepo := range prowOrg.Repos {
+			rc := repoConfig(org + "/" + name)
+			protection := branchProtection(prowRepo.Policy)
+			rc.RequiredContexts = protection.RequiredContexts
+			rc.RequiredApprovals = protection.RequiredApprovals
+		}
+	}
+	for repo, repoPlugins := range pluginConfig.Plugins {
+		repoConfig(repo).Plugins = repoPlugins
+	}
+
+	answer := []*RepositoryConfig{}
+	for _, rc := range configs {
+		answer = append(answer, rc)
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		return answer[i].Repo < answer[j].Repo
+	})
+	return answer, nil
+}
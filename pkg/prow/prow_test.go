@@ -1,6 +1,7 @@
 package prow_test
 
 import (
+	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/prow"
 	"github.com/stretchr/testify/assert"
 
@@ -269,3 +270,133 @@ func TestReplaceProwConfig(t *testing.T) {
 	assert.Equal(t, 3, len(prowConfig.Tide.Queries[0].Repos))
 	assert.Equal(t, 2, len(prowConfig.Tide.Queries[1].Repos))
 }
+
+func TestProwConfigGitLabProtectsBranches(t *testing.T) {
+	t.Parallel()
+	o := TestOptions{}
+	o.Setup()
+	o.Kind = prow.Application
+	repo := gits.NewFakeRepository("test", "repo")
+	provider := gits.NewFakeProvider(repo)
+	provider.Type = gits.Gitlab
+	o.GitProvider = provider
+
+	err := o.AddProwConfig()
+	assert.NoError(t, err)
+	err = o.AddProwPlugins()
+	assert.NoError(t, err)
+
+	protection := repo.BranchProtections["master"]
+	if assert.NotNil(t, protection) {
+		assert.Equal(t, []string{prow.ServerlessJenkins}, protection.RequiredContexts)
+		assert.Equal(t, 0, protection.RequiredApprovals)
+	}
+
+	prowConfig, _, err := o.GetProwConfig()
+	assert.NoError(t, err)
+	assert.Contains(t, prowConfig.Tide.Queries[0].Labels, "approved")
+	assert.Contains(t, prowConfig.Tide.Queries[0].Repos, "test/repo")
+
+	pluginConfig, _, err := o.GetPluginConfig()
+	assert.NoError(t, err)
+	assert.Contains(t, pluginConfig.Plugins["test/repo"], "approve")
+}
+
+func TestProwConfigBitbucketServerRequiresApprovals(t *testing.T) {
+	t.Parallel()
+	o := TestOptions{}
+	o.Setup()
+	o.Kind = prow.Application
+	repo := gits.NewFakeRepository("test", "repo")
+	provider := gits.NewFakeProvider(repo)
+	provider.Type = gits.BitbucketServer
+	o.GitProvider = provider
+
+	err := o.AddProwConfig()
+	assert.NoError(t, err)
+	err = o.AddProwPlugins()
+	assert.NoError(t, err)
+
+	protection := repo.BranchProtections["master"]
+	if assert.NotNil(t, protection) {
+		assert.Equal(t, []string{prow.ServerlessJenkins}, protection.RequiredContexts)
+		assert.Equal(t, 1, protection.RequiredApprovals)
+	}
+
+	prowConfig, _, err := o.GetProwConfig()
+	assert.NoError(t, err)
+	assert.NotContains(t, prowConfig.Tide.Queries[0].Repos, "test/repo")
+	assert.Empty(t, prowConfig.Tide.Queries[1].Labels)
+	assert.Contains(t, prowConfig.Tide.Queries[1].Repos, "test/repo")
+
+	pluginConfig, _, err := o.GetPluginConfig()
+	assert.NoError(t, err)
+	assert.NotContains(t, pluginConfig.Plugins["test/repo"], "approve")
+	assert.Contains(t, pluginConfig.Plugins["test/repo"], "trigger")
+	assert.Empty(t, pluginConfig.Approve)
+}
+
+func TestProwConfigGitHubDoesNotProtectBranches(t *testing.T) {
+	t.Parallel()
+	o := TestOptions{}
+	o.Setup()
+	o.Kind = prow.Application
+	repo := gits.NewFakeRepository("test", "repo")
+	provider := gits.NewFakeProvider(repo)
+	provider.Type = gits.GitHub
+	o.GitProvider = provider
+
+	err := o.AddProwConfig()
+	assert.NoError(t, err)
+
+	assert.Empty(t, repo.BranchProtections)
+}
+
+func TestGetRepositoryConfigs(t *testing.T) {
+	t.Parallel()
+	o := TestOptions{}
+	o.Setup()
+	o.Kind = prow.Environment
+	o.EnvironmentNamespace = "jx-staging"
+	o.Repos = []string{"test/environment-staging"}
+
+	err := o.AddProwConfig()
+	assert.NoError(t, err)
+	err = o.AddProwPlugins()
+	assert.NoError(t, err)
+
+	o.Kind = prow.Application
+	o.Repos = []string{"test/repo"}
+	err = o.AddProwConfig()
+	assert.NoError(t, err)
+	err = o.AddProwPlugins()
+	assert.NoError(t, err)
+
+	configs, err := o.GetRepositoryConfigs()
+	assert.NoError(t, err)
+
+	repos := map[string]*prow.RepositoryConfig{}
+	for _, c := range configs {
+		repos[c.Repo] = c
+	}
+	env := repos["test/environment-staging"]
+	if assert.NotNil(t, env) {
+		assert.Equal(t, []string{prow.PromotionBuild}, env.Presubmits)
+		assert.Equal(t, []string{"promotion"}, env.Postsubmits)
+		assert.Equal(t, []string{prow.PromotionBuild}, env.RequiredContexts)
+		assert.True(t, env.Merge)
+		assert.Empty(t, env.TideLabels)
+		assert.NotEmpty(t, env.Plugins)
+	}
+	app := repos["test/repo"]
+	if assert.NotNil(t, app) {
+		assert.Equal(t, []string{prow.ServerlessJenkins}, app.Presubmits)
+		assert.Equal(t, []string{"release"}, app.Postsubmits)
+		assert.Equal(t, []string{prow.ServerlessJenkins}, app.RequiredContexts)
+		assert.True(t, app.Merge)
+		assert.Equal(t, []string{"approved"}, app.TideLabels)
+	}
+	for i := 1; i < len(configs); i++ {
+		assert.True(t, configs[i-1].Repo < configs[i].Repo, "repository configs should be sorted")
+	}
+}
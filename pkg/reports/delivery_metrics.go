@@ -0,0 +1,352 @@
+package reports
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+)
+
+// DeliveryMetrics the DORA delivery metrics of an application in an environment or of all the applications in an
+// environment over a time range
+type DeliveryMetrics struct {
+	App                      string  `json:"app,omitempty"`
+	Environment              string  `json:"environment"`
+	Deployments              int     `json:"deployments"`
+	DeploymentsPerDay        float64 `json:"deploymentsPerDay"`
+	Failures                 int     `json:"failures"`
+	ChangeFailureRate        float64 `json:"changeFailureRate"`
+	LeadTimeSeconds          float64 `json:"leadTimeSeconds"`
+	MeanTimeToRestoreSeconds float64 `json:"meanTimeToRestoreSeconds"`
+	Restores                 int     `json:"restores"`
+
+	attempts     int
+	leadTimes    []time.Duration
+	restoreTimes []time.Duration
+}
+
+// DeliveryReport the delivery metrics for each application in each environment and for each environment
+type DeliveryReport struct {
+	From         time.Time          `json:"from"`
+	To           time.Time          `json:"to"`
+	Applications []*DeliveryMetrics `json:"applications"`
+	Environments []*DeliveryMetrics `json:"environments"`
+}
+
+// DeliveryMetricsFilter filters the activities which are included in the delivery metrics
+type DeliveryMetricsFilter struct {
+	From        time.Time
+	To          time.Time
+	Environment string
+	App         string
+}
+
+type deliveryEventKind int
+
+const (
+	deploymentEvent deliveryEventKind = iota
+	failedPromotionEvent
+	rollbackEvent
+)
+
+type deliveryEvent struct {
+	kind       deliveryEventKind
+	time       time.Time
+	leadTime   time.Duration
+	failedFrom time.Time
+	restoredAt *time.Time
+}
+
+// LeadTime returns the median time from commit to deployment
+func (m *DeliveryMetrics) LeadTime() time.Duration {
+	return time.Duration(m.LeadTimeSeconds * float64(time.Second))
+}
+
+// MeanTimeToRestore returns the mean time taken to restore the service after a failed or rolled back deployment
+func (m *DeliveryMetrics) MeanTimeToRestore() time.Duration {
+	return time.Duration(m.MeanTimeToRestoreSeconds * float64(time.Second))
+}
+
+// Name returns the name of the metrics used in charts
+func (m *DeliveryMetrics) Name() string {
+	if m.App == "" {
+		return m.Environment
+	}
+	return m.App + " in " + m.Environment
+}
+
+// CalculateDeliveryMetrics calculates the deployment frequency, lead time from commit to deployment, change failure
+// rate and mean time to restore from the promotions of the pipeline activities. The commit times are taken from the
+// releases of the promoted versions or the start of the pipeline if there is no release with commit times
+func CalculateDeliveryMetrics(activities []v1.PipelineActivity, releases []v1.Release, filter *DeliveryMetricsFilter) *DeliveryReport {
+	commitTimes := releaseCommitTimes(releases)
+	events := map[string][]*deliveryEvent{}
+	keys := map[string]*DeliveryMetrics{}
+	for i := range activities {
+		activity := &activities[i]
+		app := activity.RepositoryName()
+		if app == "" || (filter.App != "" && filter.App != app) {
+			continue
+		}
+		promotions := map[string]*v1.PromoteActivityStep{}
+		for _, step := range activity.Spec.Steps {
+			if step.Promote != nil {
+				promotions[step.Promote.Environment] = step.Promote
+			}
+		}
+		for _, step := range activity.Spec.Steps {
+			env, event := toDeliveryEvent(activity, &step, promotions, commitTimes)
+			if event == nil || (filter.Environment != "" && filter.Environment != env) {
+				continue
+			}
+			if event.time.Before(filter.From) || event.time.After(filter.To) {
+				continue
+			}
+			key := app + "/" + env
+			if keys[key] == nil {
+				keys[key] = &DeliveryMetrics{
+					App:         app,
+					Environment: env,
+				}
+			}
+			events[key] = append(events[key], event)
+		}
+	}
+
+	report := &DeliveryReport{
+		From: filter.From,
+		To:   filter.To,
+	}
+	envMetrics := map[string]*DeliveryMetrics{}
+	for key, metrics := range keys {
+		list := events[key]
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].time.Before(list[j].time)
+		})
+		metrics.addEvents(list)
+		report.Applications = append(report.Applications, metrics)
+
+		env := envMetrics[metrics.Environment]
+		if env == nil {
+			env = &DeliveryMetrics{
+				Environment: metrics.Environment,
+			}
+			envMetrics[metrics.Environment] = env
+			report.Environments = append(report.Environments, env)
+		}
+		env.add(metrics)
+	}
+	days := filter.To.Sub(filter.From).Hours() / 24
+	for _, metrics := range report.Applications {
+		metrics.summarize(days)
+	}
+	for _, metrics := range report.Environments {
+		metrics.summarize(days)
+	}
+	sort.Slice(report.Applications, func(i, j int) bool {
+		a1 := report.Applications[i]
+		a2 := report.Applications[j]
+		if a1.App == a2.App {
+			return a1.Environment < a2.Environment
+		}
+		return a1.App < a2.App
+	})
+	sort.Slice(report.Environments, func(i, j int) bool {
+		return report.Environments[i].Environment < report.Environments[j].Environment
+	})
+	return report
+}
+
+// RenderBarReports renders a bar chart for each of the metrics of the environments and applications using the
+// function to create the reports. The chart names are valid JavaScript identifiers as they are used by blog charts
+func (r *DeliveryReport) RenderBarReports(createBarReport func(name string, legends ...string) BarReport) error {
+	metrics := append(append([]*DeliveryMetrics{}, r.Environments...), r.Applications...)
+	charts := []struct {
+		name   string
+		legend string
+		value  func(m *DeliveryMetrics) int
+	}{
+		{"deployments", "Deployments", func(m *DeliveryMetrics) int {
+			return m.Deployments
+		}},
+		{"leadTime", "Lead Time (hours)", func(m *DeliveryMetrics) int {
+			return int(m.LeadTime().Hours() + 0.5)
+		}},
+		{"changeFailureRate", "Change Failure Rate (%)", func(m *DeliveryMetrics) int {
+			return int(m.ChangeFailureRate*100 + 0.5)
+		}},
+		{"timeToRestore", "Mean Time To Restore (hours)", func(m *DeliveryMetrics) int {
+			return int(m.MeanTimeToRestore().Hours() + 0.5)
+		}},
+	}
+	for _, chart := range charts {
+		report := createBarReport(chart.name, "Name", chart.legend)
+		for _, m := range metrics {
+			report.AddNumber(m.Name(), chart.value(m))
+		}
+		err := report.Render()
+		if err != nil {
+			return fmt.Errorf("failed to render the %s report: %s", chart.name, err)
+		}
+	}
+	return nil
+}
+
+func (m *DeliveryMetrics) addEvents(events []*deliveryEvent) {
+	var failedSince *time.Time
+	for _, event := range events {
+		switch event.kind {
+		case deploymentEvent:
+			m.Deployments++
+			m.attempts++
+			m.leadTimes = append(m.leadTimes, event.leadTime)
+			if failedSince != nil {
+				m.restoreTimes = append(m.restoreTimes, event.time.Sub(*failedSince))
+				failedSince = nil
+			}
+		case failedPromotionEvent:
+			m.Failures++
+			m.attempts++
+			if failedSince == nil {
+				t := event.time
+				failedSince = &t
+			}
+		case rollbackEvent:
+			m.Failures++
+			if event.restoredAt != nil {
+				m.restoreTimes = append(m.restoreTimes, event.restoredAt.Sub(event.failedFrom))
+				failedSince = nil
+			} else if failedSince == nil {
+				t := event.failedFrom
+				failedSince = &t
+			}
+		}
+	}
+}
+
+func (m *DeliveryMetrics) add(other *DeliveryMetrics) {
+	m.Deployments += other.Deployments
+	m.Failures += other.Failures
+	m.attempts += other.attempts
+	m.leadTimes = append(m.leadTimes, other.leadTimes...)
+	m.restoreTimes = append(m.restoreTimes, other.restoreTimes...)
+}
+
+func (m *DeliveryMetrics) summarize(days float64) {
+	if days > 0 {
+		m.DeploymentsPerDay = float64(m.Deployments) / days
+	}
+	if m.attempts > 0 {
+		m.ChangeFailureRate = float64(m.Failures) / float64(m.attempts)
+	}
+	if len(m.leadTimes) > 0 {
+		sorted := append([]time.Duration{}, m.leadTimes...)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i] < sorted[j]
+		})
+		mid := len(sorted) / 2
+		median := sorted[mid]
+		if len(sorted)%2 == 0 {
+			median = (sorted[mid-1] + sorted[mid]) / 2
+		}
+		m.LeadTimeSeconds = median.Seconds()
+	}
+	m.Restores = len(m.restoreTimes)
+	if m.Restores > 0 {
+		var total time.Duration
+		for _, d := range m.restoreTimes {
+			total += d
+		}
+		m.MeanTimeToRestoreSeconds = (total / time.Duration(m.Restores)).Seconds()
+	}
+}
+
+// toDeliveryEvent returns the environment and delivery event for the step or nil if the step is not a completed
+// promotion or a rollback
+func toDeliveryEvent(activity *v1.PipelineActivity, step *v1.PipelineActivityStep, promotions map[string]*v1.PromoteActivityStep, commitTimes map[string]time.Time) (string, *deliveryEvent) {
+	if promote := step.Promote; promote != nil {
+		switch promote.Status {
+		case v1.ActivityStatusTypeSucceeded:
+			if promote.CompletedTimestamp == nil {
+				return "", nil
+			}
+			deployed := promote.CompletedTimestamp.Time
+			committed, ok := commitTimes[releaseKey(activity.RepositoryName(), activity.Spec.Version)]
+			if !ok || committed.After(deployed) {
+				committed = deployed
+				if activity.Spec.StartedTimestamp != nil {
+					committed = activity.Spec.StartedTimestamp.Time
+				}
+			}
+			return promote.Environment, &deliveryEvent{
+				kind:     deploymentEvent,
+				time:     deployed,
+				leadTime: deployed.Sub(committed),
+			}
+		case v1.ActivityStatusTypeFailed, v1.ActivityStatusTypeError:
+			t := promote.CompletedTimestamp
+			if t == nil {
+				t = promote.StartedTimestamp
+			}
+			if t == nil {
+				return "", nil
+			}
+			return promote.Environment, &deliveryEvent{
+				kind: failedPromotionEvent,
+				time: t.Time,
+			}
+		}
+		return "", nil
+	}
+	rollback := step.Rollback
+	if rollback == nil || rollback.StartedTimestamp == nil {
+		return "", nil
+	}
+	event := &deliveryEvent{
+		kind:       rollbackEvent,
+		time:       rollback.StartedTimestamp.Time,
+		failedFrom: rollback.StartedTimestamp.Time,
+	}
+	// the service was broken from when the rolled back version was deployed
+	promote := promotions[rollback.Environment]
+	if promote != nil && promote.CompletedTimestamp != nil && promote.CompletedTimestamp.Time.Before(rollback.StartedTimestamp.Time) {
+		event.failedFrom = promote.CompletedTimestamp.Time
+	}
+	if rollback.Status == v1.ActivityStatusTypeSucceeded && rollback.CompletedTimestamp != nil {
+		t := rollback.CompletedTimestamp.Time
+		event.restoredAt = &t
+	}
+	return rollback.Environment, event
+}
+
+// releaseCommitTimes returns the time of the earliest commit of each release indexed by the app and version
+func releaseCommitTimes(releases []v1.Release) map[string]time.Time {
+	answer := map[string]time.Time{}
+	for _, release := range releases {
+		spec := &release.Spec
+		app := spec.Name
+		if app == "" {
+			app = spec.GitRepository
+		}
+		key := releaseKey(app, spec.Version)
+		for _, commit := range spec.Commits {
+			for _, user := range []*v1.UserDetails{commit.Author, commit.Committer} {
+				// the changelog only records the commit time for users without a login
+				if user == nil || user.Login != "" || user.CreationTimestamp == nil {
+					continue
+				}
+				t := user.CreationTimestamp.Time
+				if current, ok := answer[key]; !ok || t.Before(current) {
+					answer[key] = t
+				}
+			}
+		}
+	}
+	return answer
+}
+
+func releaseKey(app string, version string) string {
+	return app + "/" + strings.TrimPrefix(version, "v")
+}
@@ -0,0 +1,124 @@
+package reports_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/reports"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var testStart = time.Date(2018, time.November, 1, 0, 0, 0, 0, time.UTC)
+
+func testTime(hours int) *metav1.Time {
+	return &metav1.Time{Time: testStart.Add(time.Duration(hours) * time.Hour)}
+}
+
+func promotedActivity(app string, version string, env string, status v1.ActivityStatusType, started int, completed int) v1.PipelineActivity {
+	return v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "myorg-" + app + "-master-" + version,
+		},
+		Spec: v1.PipelineActivitySpec{
+			Pipeline:         "myorg/" + app + "/master",
+			Version:          version,
+			StartedTimestamp: testTime(started),
+			Steps: []v1.PipelineActivityStep{
+				{
+					Kind: v1.ActivityStepKindTypePromote,
+					Promote: &v1.PromoteActivityStep{
+						CoreActivityStep: v1.CoreActivityStep{
+							Status:             status,
+							StartedTimestamp:   testTime(completed - 1),
+							CompletedTimestamp: testTime(completed),
+						},
+						Environment: env,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCalculateDeliveryMetrics(t *testing.T) {
+	t.Parallel()
+	rolledBack := promotedActivity("myapp", "1.0.2", "production", v1.ActivityStatusTypeSucceeded, 40, 42)
+	rolledBack.Spec.Steps = append(rolledBack.Spec.Steps, v1.PipelineActivityStep{
+		Kind: v1.ActivityStepKindTypeRollback,
+		Rollback: &v1.RollbackActivityStep{
+			CoreActivityStep: v1.CoreActivityStep{
+				Status:             v1.ActivityStatusTypeSucceeded,
+				StartedTimestamp:   testTime(45),
+				CompletedTimestamp: testTime(46),
+			},
+			Environment: "production",
+		},
+	})
+	activities := []v1.PipelineActivity{
+		promotedActivity("myapp", "1.0.0", "production", v1.ActivityStatusTypeSucceeded, 1, 4),
+		promotedActivity("myapp", "1.0.1", "production", v1.ActivityStatusTypeFailed, 10, 12),
+		rolledBack,
+		promotedActivity("myapp", "1.0.3", "production", v1.ActivityStatusTypeSucceeded, 50, 52),
+		promotedActivity("myapp", "1.0.3", "staging", v1.ActivityStatusTypeSucceeded, 50, 51),
+		promotedActivity("other", "2.0.0", "staging", v1.ActivityStatusTypeSucceeded, 20, 22),
+		promotedActivity("other", "0.0.1", "staging", v1.ActivityStatusTypeSucceeded, -100, -90),
+	}
+	releases := []v1.Release{
+		{
+			Spec: v1.ReleaseSpec{
+				Name:    "myapp",
+				Version: "v1.0.0",
+				Commits: []v1.CommitSummary{
+					{
+						Author:    &v1.UserDetails{Name: "someone", CreationTimestamp: &metav1.Time{Time: testStart}},
+						Committer: &v1.UserDetails{Login: "someone", CreationTimestamp: testTime(-1000)},
+					},
+				},
+			},
+		},
+	}
+
+	report := reports.CalculateDeliveryMetrics(activities, releases, &reports.DeliveryMetricsFilter{
+		From: testStart,
+		To:   testStart.Add(10 * 24 * time.Hour),
+	})
+
+	if assert.Len(t, report.Applications, 3) {
+		prod := report.Applications[0]
+		assert.Equal(t, "myapp", prod.App)
+		assert.Equal(t, "production", prod.Environment)
+		assert.Equal(t, 3, prod.Deployments)
+		assert.Equal(t, 0.3, prod.DeploymentsPerDay)
+		assert.Equal(t, 2, prod.Failures)
+		assert.Equal(t, 0.5, prod.ChangeFailureRate)
+		// lead times of 4h from the release commit, 2h and 2h from the pipeline start
+		assert.Equal(t, 2*time.Hour, prod.LeadTime())
+		// restored after 30h by the next deployment and after 4h by the rollback
+		assert.Equal(t, 2, prod.Restores)
+		assert.Equal(t, 17*time.Hour, prod.MeanTimeToRestore())
+
+		assert.Equal(t, "myapp", report.Applications[1].App)
+		assert.Equal(t, "staging", report.Applications[1].Environment)
+		assert.Equal(t, "other", report.Applications[2].App)
+		assert.Equal(t, 1, report.Applications[2].Deployments, "deployments outside the time range should be ignored")
+	}
+	if assert.Len(t, report.Environments, 2) {
+		staging := report.Environments[1]
+		assert.Equal(t, "staging", staging.Environment)
+		assert.Equal(t, "", staging.App)
+		assert.Equal(t, 2, staging.Deployments)
+		assert.Equal(t, 0.0, staging.ChangeFailureRate)
+		assert.Equal(t, time.Hour+30*time.Minute, staging.LeadTime())
+	}
+
+	report = reports.CalculateDeliveryMetrics(activities, releases, &reports.DeliveryMetricsFilter{
+		From:        testStart,
+		To:          testStart.Add(10 * 24 * time.Hour),
+		Environment: "staging",
+		App:         "other",
+	})
+	assert.Len(t, report.Applications, 1)
+	assert.Len(t, report.Environments, 1)
+}
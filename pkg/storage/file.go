@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// FileStore stores files in a local directory such as a mounted persistent volume
+type FileStore struct {
+	Dir string
+}
+
+// NewFileStore creates a store of files in the directory
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{
+		Dir: dir,
+	}
+}
+
+// Write writes the data to the path in the directory
+func (s *FileStore) Write(path string, data []byte) (string, error) {
+	fileName, err := filepath.Abs(filepath.Join(s.Dir, filepath.FromSlash(path)))
+	if err != nil {
+		return "", err
+	}
+	err = os.MkdirAll(filepath.Dir(fileName), 0755)
+	if err != nil {
+		return "", err
+	}
+	err = ioutil.WriteFile(fileName, data, 0644)
+	if err != nil {
+		return "", err
+	}
+	return "file://" + filepath.ToSlash(fileName), nil
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// GCSStore stores files in a Google Cloud Storage bucket using gsutil
+type GCSStore struct {
+	Bucket string
+}
+
+// NewGCSStore creates a store of files in the Google Cloud Storage bucket
+func NewGCSStore(bucket string) *GCSStore {
+	return &GCSStore{
+		Bucket: bucket,
+	}
+}
+
+// Write uploads the data to the path in the bucket
+func (s *GCSStore) Write(path string, data []byte) (string, error) {
+	tmpFile, err := ioutil.TempFile("", "jx-gcs-")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmpFile.Name())
+	_, err = tmpFile.Write(data)
+	tmpFile.Close()
+	if err != nil {
+		return "", err
+	}
+	fileURL := s.URL(path)
+	cmd := util.Command{
+		Name: "gsutil",
+		Args: []string{"cp", tmpFile.Name(), fileURL},
+	}
+	_, err = cmd.RunWithoutRetry()
+	if err != nil {
+		return "", err
+	}
+	return fileURL, nil
+}
+
+// URL returns the gs URL of the path in the bucket
+func (s *GCSStore) URL(path string) string {
+	return "gs://" + s.Bucket + "/" + strings.TrimPrefix(path, "/")
+}
+
+func readGCS(fileURL string) ([]byte, error) {
+	// the output of util.Command is trimmed so use exec directly to keep the file content intact
+	data, err := exec.Command("gsutil", "cat", fileURL).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", fileURL, err)
+	}
+	return data, nil
+}
+
+func deleteGCS(fileURL string) error {
+	cmd := util.Command{
+		Name: "gsutil",
+		Args: []string{"rm", fileURL},
+	}
+	_, err := cmd.RunWithoutRetry()
+	return err
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// GitStore stores files in a branch of a git repository
+type GitStore struct {
+	URL    string
+	Branch string
+	Git    gits.Gitter
+}
+
+// NewGitStore creates a store of files in the branch of the git repository
+func NewGitStore(gitURL string, branch string, gitter gits.Gitter) *GitStore {
+	if branch == "" {
+		branch = "master"
+	}
+	return &GitStore{
+		URL:    gitURL,
+		Branch: branch,
+		Git:    gitter,
+	}
+}
+
+// gitStoreWriteAttempts how many times a file is written to the branch when the push is rejected because another
+// file was pushed to the branch at the same time
+const gitStoreWriteAttempts = 5
+
+// Write commits the data to the path in the branch and pushes it, returning the raw URL of the file. If the push is
+// rejected because the branch changed since it was cloned the latest branch is cloned and the commit is applied again
+func (s *GitStore) Write(path string, data []byte) (string, error) {
+	var err error
+	for i := 1; i <= gitStoreWriteAttempts; i++ {
+		var pushFailed bool
+		pushFailed, err = s.write(path, data)
+		if err == nil {
+			return s.RawURL(path), nil
+		}
+		if !pushFailed || i == gitStoreWriteAttempts {
+			break
+		}
+		log.Warnf("Failed to push %s to branch %s of %s, retrying with the latest branch: %s\n", path, s.Branch, s.URL, err)
+		time.Sleep(time.Duration(i) * time.Second)
+	}
+	return "", err
+}
+
+// write clones the branch, commits the data to the path and pushes it returning true if the push failed so that
+// it can be retried
+func (s *GitStore) write(path string, data []byte) (bool, error) {
+	dir, err := ioutil.TempDir("", "jx-git-store")
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(dir)
+
+	err = s.Git.ShallowCloneBranch(s.URL, s.Branch, dir)
+	if err != nil {
+		log.Infof("No existing %s branch in %s so creating it\n", s.Branch, s.URL)
+		err = s.Git.Clone(s.URL, dir)
+		if err != nil {
+			return false, err
+		}
+		err = s.Git.CheckoutOrphan(dir, s.Branch)
+		if err != nil {
+			return false, err
+		}
+		err = s.Git.RemoveForce(dir, ".")
+		if err != nil {
+			return false, err
+		}
+	}
+
+	fileName := filepath.Join(dir, filepath.FromSlash(path))
+	err = os.MkdirAll(filepath.Dir(fileName), 0755)
+	if err != nil {
+		return false, err
+	}
+	err = ioutil.WriteFile(fileName, data, 0644)
+	if err != nil {
+		return false, err
+	}
+	err = s.Git.Add(dir, path)
+	if err != nil {
+		return false, err
+	}
+	err = s.Git.CommitDir(dir, fmt.Sprintf("Add %s", path))
+	if err != nil {
+		return false, err
+	}
+	err = s.Git.Push(dir)
+	if err != nil {
+		return true, err
+	}
+	return false, nil
+}
+
+// RawURL returns the URL to download the file at the path in the branch
+func (s *GitStore) RawURL(path string) string {
+	return util.UrlJoin(strings.TrimSuffix(s.URL, ".git"), "raw", s.Branch, path)
+}
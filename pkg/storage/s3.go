@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+)
+
+// S3Store stores files in an S3 bucket or in a bucket of an S3 compatible object store such as Minio
+type S3Store struct {
+	Bucket   string
+	Profile  string
+	Region   string
+	Endpoint string
+}
+
+// NewS3Store creates a store of files in the S3 bucket
+func NewS3Store(bucket string, profile string, region string) *S3Store {
+	return &S3Store{
+		Bucket:  bucket,
+		Profile: profile,
+		Region:  region,
+	}
+}
+
+// Write uploads the data to the key of the bucket
+func (s *S3Store) Write(path string, data []byte) (string, error) {
+	svc, err := amazon.NewS3Client(s.Profile, s.Region, s.Endpoint)
+	if err != nil {
+		return "", err
+	}
+	_, err = svc.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(path),
+		Body:   bytes.NewReader(data),
+	})
+	if err != nil {
+		return "", err
+	}
+	return s.URL(path), nil
+}
+
+// URL returns the s3 URL of the key in the bucket. The region and endpoint are added as query parameters so that
+// the file can be read and deleted again from the URL alone
+func (s *S3Store) URL(path string) string {
+	values := url.Values{}
+	if s.Region != "" {
+		values.Set("region", s.Region)
+	}
+	if s.Endpoint != "" {
+		values.Set("endpoint", s.Endpoint)
+	}
+	answer := "s3://" + s.Bucket + "/" + path
+	if len(values) > 0 {
+		answer += "?" + values.Encode()
+	}
+	return answer
+}
+
+func s3ClientForURL(u *url.URL) (*s3.S3, string, error) {
+	query := u.Query()
+	svc, err := amazon.NewS3Client("", query.Get("region"), query.Get("endpoint"))
+	return svc, strings.TrimPrefix(u.Path, "/"), err
+}
+
+func readS3(u *url.URL) ([]byte, error) {
+	svc, key, err := s3ClientForURL(u)
+	if err != nil {
+		return nil, err
+	}
+	output, err := svc.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(u.Host),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer output.Body.Close()
+	return ioutil.ReadAll(output.Body)
+}
+
+func deleteS3(u *url.URL) error {
+	svc, key, err := s3ClientForURL(u)
+	if err != nil {
+		return err
+	}
+	_, err = svc.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(u.Host),
+		Key:    aws.String(key),
+	})
+	return err
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+const (
+	// KindGit stores files in a branch of a git repository
+	KindGit = "git"
+	// KindS3 stores files in an S3 bucket
+	KindS3 = "s3"
+	// KindGCS stores files in a Google Cloud Storage bucket
+	KindGCS = "gcs"
+	// KindPVC stores files in a directory such as a mounted persistent volume
+	KindPVC = "pvc"
+)
+
+// Kinds the kinds of store which are supported
+var Kinds = []string{KindGit, KindS3, KindGCS, KindPVC}
+
+// Store stores files such as build logs for the long term
+type Store interface {
+	// Write stores the data at the given path relative to the root of the store and returns the URL of the file
+	Write(path string, data []byte) (string, error)
+}
+
+// ReadURL reads the file at the URL returned by a Store. Files stored in git are read from their raw HTTP URL
+func ReadURL(fileURL string) ([]byte, error) {
+	return ReadURLWithBasicAuth(fileURL, "", "")
+}
+
+// ReadURLWithBasicAuth reads the file at the URL returned by a Store using the given basic auth credentials for
+// HTTP URLs, such as the git provider user and API token to read files stored in a private git repository
+func ReadURLWithBasicAuth(fileURL string, username string, password string) ([]byte, error) {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %s: %s", fileURL, err)
+	}
+	switch u.Scheme {
+	case "file":
+		return ioutil.ReadFile(u.Path)
+	case "s3":
+		return readS3(u)
+	case "gs":
+		return readGCS(fileURL)
+	case "http", "https":
+		req, err := http.NewRequest(http.MethodGet, fileURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		if username != "" || password != "" {
+			req.SetBasicAuth(username, password)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("failed to read %s: status %s", fileURL, resp.Status)
+		}
+		return ioutil.ReadAll(resp.Body)
+	default:
+		return nil, fmt.Errorf("unsupported URL scheme %s in %s", u.Scheme, fileURL)
+	}
+}
+
+// DeleteURL deletes the file at the URL returned by a Store. Files stored in git are kept in the history of the
+// branch so their HTTP URLs are ignored
+func DeleteURL(fileURL string) error {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL %s: %s", fileURL, err)
+	}
+	switch u.Scheme {
+	case "file":
+		err = os.Remove(u.Path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	case "s3":
+		return deleteS3(u)
+	case "gs":
+		return deleteGCS(fileURL)
+	case "http", "https":
+		return nil
+	default:
+		return fmt.Errorf("unsupported URL scheme %s in %s", u.Scheme, fileURL)
+	}
+}
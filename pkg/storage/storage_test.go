@@ -0,0 +1,132 @@
+package storage_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-file-store")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := storage.NewFileStore(dir)
+	u, err := store.Write("myorg/myapp/master/1.log", []byte("hello\n"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(u, "file://"), "URL %s should be a file URL", u)
+	assert.True(t, strings.HasSuffix(u, "myorg/myapp/master/1.log"), "URL %s should end with the path", u)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "myorg", "myapp", "master", "1.log"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello\n", string(data))
+
+	data, err = storage.ReadURL(u)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello\n", string(data))
+}
+
+func TestReadURLUnsupportedScheme(t *testing.T) {
+	t.Parallel()
+	_, err := storage.ReadURL("ftp://example.com/1.log")
+	assert.Error(t, err)
+}
+
+func TestReadURLWithBasicAuth(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "myuser" || password != "mytoken" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("hello\n"))
+	}))
+	defer server.Close()
+
+	u := server.URL + "/myorg/build-logs/raw/master/myorg/myapp/master/1.log"
+	_, err := storage.ReadURL(u)
+	assert.Error(t, err)
+
+	data, err := storage.ReadURLWithBasicAuth(u, "myuser", "mytoken")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello\n", string(data))
+}
+
+func TestGitStoreRawURL(t *testing.T) {
+	t.Parallel()
+	store := storage.NewGitStore("https://github.com/myorg/build-logs.git", "", nil)
+	assert.Equal(t, "master", store.Branch)
+	assert.Equal(t, "https://github.com/myorg/build-logs/raw/master/myorg/myapp/master/1.log", store.RawURL("myorg/myapp/master/1.log"))
+}
+
+// rejectingGitter rejects the first push as if another file was pushed to the branch at the same time
+type rejectingGitter struct {
+	*gits.GitFake
+	pushes int
+}
+
+func (g *rejectingGitter) Push(dir string) error {
+	g.pushes++
+	if g.pushes == 1 {
+		return errors.New("rejected: non-fast-forward")
+	}
+	return nil
+}
+
+func TestGitStoreRetriesRejectedPush(t *testing.T) {
+	t.Parallel()
+	gitter := &rejectingGitter{GitFake: &gits.GitFake{}}
+	store := storage.NewGitStore("https://github.com/myorg/build-logs.git", "", gitter)
+	u, err := store.Write("myorg/myapp/master/1.log", []byte("hello\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, gitter.pushes, "the rejected push should be retried")
+	assert.Equal(t, "https://github.com/myorg/build-logs/raw/master/myorg/myapp/master/1.log", u)
+}
+
+func TestDeleteURL(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-delete-url")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := storage.NewFileStore(dir)
+	u, err := store.Write("myorg/myapp/master/1/coverage/index.html", []byte("<html/>"))
+	assert.NoError(t, err)
+
+	err = storage.DeleteURL(u)
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "myorg", "myapp", "master", "1", "coverage", "index.html"))
+	assert.True(t, os.IsNotExist(err), "file should have been deleted")
+
+	err = storage.DeleteURL(u)
+	assert.NoError(t, err, "deleting a missing file should not fail")
+
+	err = storage.DeleteURL("https://myorg.github.io/myapp/jenkins-x/master/1/coverage/index.html")
+	assert.NoError(t, err)
+	err = storage.DeleteURL("ftp://example.com/1.log")
+	assert.Error(t, err)
+}
+
+func TestObjectStoreURLs(t *testing.T) {
+	t.Parallel()
+	s3Store := storage.NewS3Store("my-artifacts", "", "")
+	assert.Equal(t, "s3://my-artifacts/myorg/myapp/master/1/junit.xml", s3Store.URL("myorg/myapp/master/1/junit.xml"))
+
+	s3Store.Region = "us-east-1"
+	s3Store.Endpoint = "http://minio:9000"
+	assert.Equal(t, "s3://my-artifacts/myorg/myapp/master/1/junit.xml?endpoint=http%3A%2F%2Fminio%3A9000&region=us-east-1", s3Store.URL("myorg/myapp/master/1/junit.xml"))
+
+	gcsStore := storage.NewGCSStore("my-artifacts")
+	assert.Equal(t, "gs://my-artifacts/myorg/myapp/master/1/junit.xml", gcsStore.URL("myorg/myapp/master/1/junit.xml"))
+}
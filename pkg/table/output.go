@@ -0,0 +1,206 @@
+package table
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/util"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// The output formats of a table
+const (
+	// FormatText renders the rows as padded columns of text
+	FormatText = "text"
+	// FormatJSON renders the items as JSON
+	FormatJSON = "json"
+	// FormatYAML renders the items as YAML
+	FormatYAML = "yaml"
+	// FormatCSV renders the rows as comma separated values
+	FormatCSV = "csv"
+	// FormatTemplate renders the items with a Go template such as: template={{range .items}}{{.name}}{{end}}
+	FormatTemplate = "template"
+	// FormatJSONPath renders the items with a JSONPath expression such as: jsonpath={.items[*].name}
+	FormatJSONPath = "jsonpath"
+)
+
+// OutputFormats the output formats of a table
+var OutputFormats = []string{FormatText, FormatJSON, FormatYAML, FormatCSV, FormatTemplate, FormatJSONPath}
+
+var colorCodes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// SetOutput sets the output format of the table from an output flag such as "json", "template=<go template>" or
+// "jsonpath=<expression>" like the output flag of kubectl
+func (t *Table) SetOutput(output string) error {
+	format := output
+	expression := ""
+	idx := strings.Index(output, "=")
+	if idx >= 0 {
+		format = output[0:idx]
+		expression = output[idx+1:]
+	}
+	if format == "go-template" {
+		format = FormatTemplate
+	}
+	switch format {
+	case "", FormatText, FormatJSON, FormatYAML, FormatCSV:
+		if idx >= 0 {
+			return fmt.Errorf("the %s output format does not take a template: %s", format, output)
+		}
+	case FormatTemplate:
+		_, err := template.New("output").Parse(expression)
+		if err != nil {
+			return fmt.Errorf("invalid template %s: %s", expression, err)
+		}
+	case FormatJSONPath:
+		expression = jsonPathExpression(expression)
+		err := jsonpath.New("output").Parse(expression)
+		if err != nil {
+			return fmt.Errorf("invalid jsonpath %s: %s", expression, err)
+		}
+	default:
+		return util.InvalidOption("output", output, OutputFormats)
+	}
+	if (format == FormatTemplate || format == FormatJSONPath) && expression == "" {
+		return fmt.Errorf("the %s output format requires an expression such as %s=...", format, format)
+	}
+	t.Format = format
+	t.Template = expression
+	return nil
+}
+
+// ValidateOutput returns an error if the output flag is not a valid output format
+func ValidateOutput(output string) error {
+	t := &Table{}
+	return t.SetOutput(output)
+}
+
+// Values returns the values rendered by the machine readable formats. These are the typed items of the table or,
+// if no items have been added, a map of each row keyed by the column names of the first row
+func (t *Table) Values() []interface{} {
+	if len(t.Items) > 0 {
+		return t.Items
+	}
+	answer := []interface{}{}
+	if len(t.Rows) == 0 {
+		return answer
+	}
+	keys := []string{}
+	for _, header := range t.Rows[0] {
+		keys = append(keys, ColumnKey(header))
+	}
+	for _, row := range t.Rows[1:] {
+		value := map[string]string{}
+		for ci, col := range row {
+			if ci < len(keys) {
+				value[keys[ci]] = StripColors(col)
+			}
+		}
+		answer = append(answer, value)
+	}
+	return answer
+}
+
+func (t *Table) renderCSV() error {
+	w := csv.NewWriter(t.Out)
+	for _, row := range t.Rows {
+		values := []string{}
+		for _, col := range row {
+			values = append(values, StripColors(col))
+		}
+		err := w.Write(values)
+		if err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func (t *Table) renderItems() error {
+	list := map[string]interface{}{
+		"items": t.Values(),
+	}
+	switch t.Format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(t.Out, "%s\n", data)
+		return err
+	case FormatYAML:
+		data, err := yaml.Marshal(list)
+		if err != nil {
+			return err
+		}
+		_, err = t.Out.Write(data)
+		return err
+	}
+
+	// templates use the JSON field names like kubectl so lets convert the items into generic values
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	var values interface{}
+	err = json.Unmarshal(data, &values)
+	if err != nil {
+		return err
+	}
+	switch t.Format {
+	case FormatTemplate:
+		tmpl, err := template.New("output").Parse(t.Template)
+		if err != nil {
+			return err
+		}
+		return tmpl.Execute(t.Out, values)
+	case FormatJSONPath:
+		jp := jsonpath.New("output")
+		jp.AllowMissingKeys(true)
+		err = jp.Parse(t.Template)
+		if err != nil {
+			return err
+		}
+		var buffer bytes.Buffer
+		err = jp.Execute(&buffer, values)
+		if err != nil {
+			return err
+		}
+		_, err = t.Out.Write(buffer.Bytes())
+		return err
+	default:
+		return util.InvalidOption("output", t.Format, OutputFormats)
+	}
+}
+
+// ColumnKey converts a column name such as "UP-TO-DATE" or "PULL REQUEST" into a key such as "upToDate" or "pullRequest"
+func ColumnKey(column string) string {
+	words := strings.FieldsFunc(strings.ToLower(StripColors(column)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i := 1; i < len(words); i++ {
+		words[i] = strings.Title(words[i])
+	}
+	return strings.Join(words, "")
+}
+
+// StripColors removes the terminal color codes from the text
+func StripColors(text string) string {
+	return colorCodes.ReplaceAllString(text, "")
+}
+
+// jsonPathExpression wraps the expression in braces if required like kubectl does
+func jsonPathExpression(expression string) string {
+	if expression != "" && !strings.Contains(expression, "{") {
+		return "{" + expression + "}"
+	}
+	return expression
+}
@@ -0,0 +1,93 @@
+package table_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/table"
+	"github.com/stretchr/testify/assert"
+)
+
+type testApp struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+func createTestTable(t *testing.T, output string) (*table.Table, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	tbl := table.CreateTable(out)
+	err := tbl.SetOutput(output)
+	assert.NoError(t, err, "output %s", output)
+	tbl.AddRow("APPLICATION", "UP-TO-DATE")
+	tbl.AddItem(&testApp{Name: "myapp", Version: "1.0.0"}, "myapp", "\x1b[32m1.0.0\x1b[0m")
+	tbl.AddItem(&testApp{Name: "other", Version: "2.0.0"}, "other", "2.0.0")
+	return &tbl, out
+}
+
+func TestRenderOutputFormats(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		output   string
+		expected string
+	}{
+		{"json", `{
+  "items": [
+    {
+      "name": "myapp",
+      "version": "1.0.0"
+    },
+    {
+      "name": "other",
+      "version": "2.0.0"
+    }
+  ]
+}
+`},
+		{"yaml", `items:
+- name: myapp
+  version: 1.0.0
+- name: other
+  version: 2.0.0
+`},
+		{"csv", "APPLICATION,UP-TO-DATE\nmyapp,1.0.0\nother,2.0.0\n"},
+		{"template={{range .items}}{{.name}}={{.version}} {{end}}", "myapp=1.0.0 other=2.0.0 "},
+		{"jsonpath={.items[*].name}", "myapp other"},
+		{"jsonpath=.items[1].version", "2.0.0"},
+	}
+	for _, test := range tests {
+		tbl, out := createTestTable(t, test.output)
+		err := tbl.RenderOutput()
+		assert.NoError(t, err, "output %s", test.output)
+		assert.Equal(t, test.expected, out.String(), "output %s", test.output)
+	}
+}
+
+func TestRenderRowsWithoutItems(t *testing.T) {
+	t.Parallel()
+	out := &bytes.Buffer{}
+	tbl := table.CreateTable(out)
+	tbl.Format = table.FormatJSON
+	tbl.AddRow("NAME", "PULL REQUEST")
+	tbl.AddRow("myapp", "https://github.com/myorg/myapp/pull/1")
+	assert.Equal(t, []interface{}{
+		map[string]string{
+			"name":        "myapp",
+			"pullRequest": "https://github.com/myorg/myapp/pull/1",
+		},
+	}, tbl.Values())
+
+	empty := table.CreateTable(out)
+	empty.AddRow("NAME")
+	assert.Empty(t, empty.Values())
+}
+
+func TestInvalidOutput(t *testing.T) {
+	t.Parallel()
+	assert.NoError(t, table.ValidateOutput(""))
+	assert.NoError(t, table.ValidateOutput("go-template={{.items}}"))
+	assert.Error(t, table.ValidateOutput("xml"))
+	assert.Error(t, table.ValidateOutput("json=foo"))
+	assert.Error(t, table.ValidateOutput("template"))
+	assert.Error(t, table.ValidateOutput("template={{.items"))
+	assert.Error(t, table.ValidateOutput("jsonpath={.items[}"))
+}
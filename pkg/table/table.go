@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
 )
 
@@ -12,6 +13,13 @@ type Table struct {
 	Rows         [][]string
 	ColumnWidths []int
 	ColumnAlign  []int
+
+	// Format is the output format of the table which defaults to text
+	Format string
+	// Template is the Go template or JSONPath expression used by the template and jsonpath formats
+	Template string
+	// Items are the typed values of the rows which are rendered by the machine readable formats
+	Items []interface{}
 }
 
 func CreateTable(out io.Writer) Table {
@@ -20,9 +28,10 @@ func CreateTable(out io.Writer) Table {
 	}
 }
 
-// Clear removes all rows while preserving the layout
+// Clear removes all rows and items while preserving the layout
 func (t *Table) Clear() {
 	t.Rows = [][]string{}
+	t.Items = nil
 }
 
 // AddRow adds a new row to the table
@@ -30,7 +39,35 @@ func (t *Table) AddRow(col ...string) {
 	t.Rows = append(t.Rows, col)
 }
 
+// AddItem adds a new row to the table for the typed item which is rendered by the json, yaml, template and
+// jsonpath formats so that their schema does not change when the text columns change
+func (t *Table) AddItem(item interface{}, col ...string) {
+	t.Items = append(t.Items, item)
+	t.AddRow(col...)
+}
+
+// Render renders the table in its output format
 func (t *Table) Render() {
+	err := t.RenderOutput()
+	if err != nil {
+		log.Warnf("Failed to render the output as %s: %s\n", t.Format, err)
+	}
+}
+
+// RenderOutput renders the table in its output format returning any error
+func (t *Table) RenderOutput() error {
+	switch t.Format {
+	case FormatText, "":
+		t.renderText()
+		return nil
+	case FormatCSV:
+		return t.renderCSV()
+	default:
+		return t.renderItems()
+	}
+}
+
+func (t *Table) renderText() {
 	// lets figure out the max widths of each column
 	for _, row := range t.Rows {
 		for ci, col := range row {
@@ -0,0 +1,245 @@
+package terraform
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	aksMainTf = `provider "azurerm" {
+  version = "~> 1.19"
+}
+
+resource "azurerm_resource_group" "cluster" {
+  name     = "${var.aks_resource_group}"
+  location = "${var.aks_location}"
+
+  tags {
+    created_by        = "${var.created_by}"
+    created_timestamp = "${var.created_timestamp}"
+    organisation      = "${var.organisation}"
+  }
+}
+
+resource "azurerm_kubernetes_cluster" "jx" {
+  name                = "${var.cluster_name}"
+  location            = "${azurerm_resource_group.cluster.location}"
+  resource_group_name = "${azurerm_resource_group.cluster.name}"
+  dns_prefix          = "${var.cluster_name}"
+  kubernetes_version  = "${var.kubernetes_version}"
+
+  agent_pool_profile {
+    name            = "default"
+    count           = "${var.min_node_count}"
+    vm_size         = "${var.node_vm_size}"
+    os_type         = "Linux"
+    os_disk_size_gb = "${var.node_disk_size}"
+  }
+
+  service_principal {
+    client_id     = "${var.client_id}"
+    client_secret = "${var.client_secret}"
+  }
+
+  role_based_access_control {
+    enabled = true
+  }
+
+  tags {
+    created_by        = "${var.created_by}"
+    created_timestamp = "${var.created_timestamp}"
+    organisation      = "${var.organisation}"
+  }
+}
+`
+
+	aksVariablesTf = `variable "created_by" {}
+
+variable "created_timestamp" {}
+
+variable "cluster_name" {}
+
+variable "organisation" {}
+
+variable "provider" {}
+
+variable "aks_location" {}
+
+variable "aks_resource_group" {}
+
+variable "node_vm_size" {
+  default = "Standard_D2s_v3"
+}
+
+variable "min_node_count" {
+  default = "3"
+}
+
+variable "max_node_count" {
+  default = "5"
+}
+
+variable "node_disk_size" {
+  default = "100"
+}
+
+variable "kubernetes_version" {
+  default = "1.11.5"
+}
+
+variable "client_id" {
+  description = "The client id of the Azure service principal used by the cluster. Pass with -var or TF_VAR_client_id"
+}
+
+variable "client_secret" {
+  description = "The client secret of the Azure service principal used by the cluster. Pass with -var or TF_VAR_client_secret"
+}
+`
+
+	aksOutputsTf = `output "cluster_name" {
+  value = "${azurerm_kubernetes_cluster.jx.name}"
+}
+
+output "resource_group" {
+  value = "${azurerm_resource_group.cluster.name}"
+}
+
+output "host" {
+  value = "${azurerm_kubernetes_cluster.jx.kube_config.0.host}"
+}
+`
+
+	eksMainTf = `provider "aws" {
+  version = "~> 1.52"
+  region  = "${var.aws_region}"
+}
+
+data "aws_availability_zones" "available" {}
+
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "1.50.0"
+
+  name               = "${var.cluster_name}"
+  cidr               = "10.0.0.0/16"
+  azs                = ["${data.aws_availability_zones.available.names[0]}", "${data.aws_availability_zones.available.names[1]}", "${data.aws_availability_zones.available.names[2]}"]
+  public_subnets     = ["10.0.1.0/24", "10.0.2.0/24", "10.0.3.0/24"]
+  enable_nat_gateway = false
+
+  tags = {
+    "kubernetes.io/cluster/${var.cluster_name}" = "shared"
+    created_by                                  = "${var.created_by}"
+    created_timestamp                           = "${var.created_timestamp}"
+    organisation                                = "${var.organisation}"
+  }
+}
+
+module "eks" {
+  source  = "terraform-aws-modules/eks/aws"
+  version = "1.8.0"
+
+  cluster_name     = "${var.cluster_name}"
+  cluster_version  = "${var.kubernetes_version}"
+  subnets          = ["${module.vpc.public_subnets}"]
+  vpc_id           = "${module.vpc.vpc_id}"
+  write_kubeconfig = false
+
+  worker_groups = [
+    {
+      instance_type        = "${var.node_instance_type}"
+      asg_min_size         = "${var.min_node_count}"
+      asg_desired_capacity = "${var.min_node_count}"
+      asg_max_size         = "${var.max_node_count}"
+      root_volume_size     = "${var.node_disk_size}"
+    },
+  ]
+
+  tags = {
+    created_by        = "${var.created_by}"
+    created_timestamp = "${var.created_timestamp}"
+    organisation      = "${var.organisation}"
+  }
+}
+`
+
+	eksVariablesTf = `variable "created_by" {}
+
+variable "created_timestamp" {}
+
+variable "cluster_name" {}
+
+variable "organisation" {}
+
+variable "provider" {}
+
+variable "aws_region" {}
+
+variable "node_instance_type" {
+  default = "m5.large"
+}
+
+variable "min_node_count" {
+  default = "3"
+}
+
+variable "max_node_count" {
+  default = "5"
+}
+
+variable "node_disk_size" {
+  default = "100"
+}
+
+variable "kubernetes_version" {
+  default = "1.10"
+}
+`
+
+	eksOutputsTf = `output "cluster_name" {
+  value = "${module.eks.cluster_id}"
+}
+
+output "cluster_endpoint" {
+  value = "${module.eks.cluster_endpoint}"
+}
+`
+)
+
+var (
+	aksModule = map[string]string{
+		"main.tf":      aksMainTf,
+		"variables.tf": aksVariablesTf,
+		"outputs.tf":   aksOutputsTf,
+	}
+	eksModule = map[string]string{
+		"main.tf":      eksMainTf,
+		"variables.tf": eksVariablesTf,
+		"outputs.tf":   eksOutputsTf,
+	}
+)
+
+// WriteAKSModule writes the Terraform module which creates an AKS cluster to the directory
+func WriteAKSModule(dir string) error {
+	return writeModule(dir, aksModule)
+}
+
+// WriteEKSModule writes the Terraform module which creates an EKS cluster and its VPC to the directory
+func WriteEKSModule(dir string) error {
+	return writeModule(dir, eksModule)
+}
+
+func writeModule(dir string, files map[string]string) error {
+	names := []string{}
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte(files[name]), 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
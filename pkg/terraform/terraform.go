@@ -22,7 +22,9 @@ func Init(terraformDir string, serviceAccountPath string) error {
 		}
 	}
 
-	os.Setenv("GOOGLE_CREDENTIALS", serviceAccountPath)
+	if serviceAccountPath != "" {
+		os.Setenv("GOOGLE_CREDENTIALS", serviceAccountPath)
+	}
 	cmd := util.Command{
 		Name: "terraform",
 		Args: []string{"init", terraformDir},
@@ -38,11 +40,7 @@ func Plan(terraformDir string, terraformVars string, serviceAccountPath string)
 	fmt.Println("Showing Terraform Plan")
 	cmd := util.Command{
 		Name: "terraform",
-		Args: []string{"plan",
-			fmt.Sprintf("-var-file=%s", terraformVars),
-			"-var",
-			fmt.Sprintf("credentials=%s", serviceAccountPath),
-			terraformDir},
+		Args: terraformArgs([]string{"plan"}, terraformDir, terraformVars, serviceAccountPath),
 	}
 	out, err := cmd.RunWithoutRetry()
 	if err != nil {
@@ -55,13 +53,9 @@ func Apply(terraformDir string, terraformVars string, serviceAccountPath string,
 	fmt.Println("Applying Terraform")
 	cmd := util.Command{
 		Name: "terraform",
-		Args: []string{"apply", "-auto-approve",
-			fmt.Sprintf("-var-file=%s", terraformVars),
-			"-var",
-			fmt.Sprintf("credentials=%s", serviceAccountPath),
-			terraformDir},
-		Out: stdout,
-		Err: stderr,
+		Args: terraformArgs([]string{"apply", "-auto-approve"}, terraformDir, terraformVars, serviceAccountPath),
+		Out:  stdout,
+		Err:  stderr,
 	}
 	_, err := cmd.RunWithoutRetry()
 	if err != nil {
@@ -70,6 +64,16 @@ func Apply(terraformDir string, terraformVars string, serviceAccountPath string,
 	return nil
 }
 
+// terraformArgs returns the arguments of a terraform command passing the credentials variable only if a
+// service account is used such as for GKE
+func terraformArgs(args []string, terraformDir string, terraformVars string, serviceAccountPath string) []string {
+	args = append(args, fmt.Sprintf("-var-file=%s", terraformVars))
+	if serviceAccountPath != "" {
+		args = append(args, "-var", fmt.Sprintf("credentials=%s", serviceAccountPath))
+	}
+	return append(args, terraformDir)
+}
+
 func WriteKeyValueToFileIfNotExists(path string, key string, value string) error {
 	// file exists
 	if _, err := os.Stat(path); err == nil {
@@ -0,0 +1,86 @@
+package testreports
+
+import (
+	"sort"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+)
+
+// minFlakyChanges the number of times a test must change between failing and passing across different commits to be
+// considered flaky. A single failure which is then fixed such as pass, fail, pass only changes twice
+const minFlakyChanges = 3
+
+// TestRun the failed tests of a build of a pipeline
+type TestRun struct {
+	CommitSHA string
+	Failed    map[string]bool
+}
+
+// NewTestRun creates a test run of the failed tests
+func NewTestRun(commitSHA string, failed []string) *TestRun {
+	run := &TestRun{
+		CommitSHA: commitSHA,
+		Failed:    map[string]bool{},
+	}
+	for _, test := range failed {
+		run.Failed[test] = true
+	}
+	return run
+}
+
+// TestRunFromActivity returns the test run recorded in the fact of the given name on the activity or nil if the
+// activity has no test results
+func TestRunFromActivity(activity *v1.PipelineActivity, name string) *TestRun {
+	for _, fact := range activity.Spec.Facts {
+		if fact.FactType != v1.FactTypeTestResults || fact.Name != name {
+			continue
+		}
+		failed := []string{}
+		for _, statement := range fact.Statements {
+			if statement.StatementType == StatementTypeFailed {
+				failed = append(failed, statement.Name)
+			}
+		}
+		return NewTestRun(activity.Spec.LastCommitSHA, failed)
+	}
+	return nil
+}
+
+// FlakyTests returns the tests which are flaky given the runs ordered from the oldest to the most recent build.
+// A test is flaky if it both passed and failed for the same commit or if it changed from failing to passing or back
+// at least three times
+func FlakyTests(runs []*TestRun) []string {
+	tests := map[string]bool{}
+	for _, run := range runs {
+		for test := range run.Failed {
+			tests[test] = true
+		}
+	}
+	answer := []string{}
+	for test := range tests {
+		if isFlaky(test, runs) {
+			answer = append(answer, test)
+		}
+	}
+	sort.Strings(answer)
+	return answer
+}
+
+func isFlaky(test string, runs []*TestRun) bool {
+	commitResults := map[string]bool{}
+	changes := 0
+	for i, run := range runs {
+		failed := run.Failed[test]
+		if i > 0 && failed != runs[i-1].Failed[test] {
+			changes++
+		}
+		if run.CommitSHA != "" {
+			previous, ok := commitResults[run.CommitSHA]
+			if ok && previous != failed {
+				return true
+			}
+			commitResults[run.CommitSHA] = failed
+		}
+	}
+	return changes >= minFlakyChanges
+}
@@ -0,0 +1,304 @@
+package testreports
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// FormatJUnit the JUnit XML format written by Maven Surefire, Gradle, pytest, jest-junit and most other tools
+	FormatJUnit = "junit"
+	// FormatXUnit the XML format written by xUnit.net
+	FormatXUnit = "xunit"
+	// FormatGoTest the JSON events written by 'go test -json'
+	FormatGoTest = "gotest"
+)
+
+// Formats the supported test report formats
+var Formats = []string{FormatJUnit, FormatXUnit, FormatGoTest}
+
+type junitSuites struct {
+	Suites []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name   string       `xml:"name,attr"`
+	Suites []junitSuite `xml:"testsuite"`
+	Cases  []junitCase  `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure"`
+	Error     *junitMessage `xml:"error"`
+	Skipped   *junitMessage `xml:"skipped"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type xunitAssemblies struct {
+	Assemblies []xunitAssembly `xml:"assembly"`
+}
+
+type xunitAssembly struct {
+	Name        string            `xml:"name,attr"`
+	Collections []xunitCollection `xml:"collection"`
+}
+
+type xunitCollection struct {
+	Tests []xunitTest `xml:"test"`
+}
+
+type xunitTest struct {
+	Name    string `xml:"name,attr"`
+	Type    string `xml:"type,attr"`
+	Time    string `xml:"time,attr"`
+	Result  string `xml:"result,attr"`
+	Reason  string `xml:"reason"`
+	Failure *struct {
+		Message string `xml:"message"`
+	} `xml:"failure"`
+}
+
+type goTestEvent struct {
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64
+	Output  string
+}
+
+// ParseFile parses the test report file detecting its format if the format is empty
+func ParseFile(fileName string, format string) ([]TestCase, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format, err = DetectFormat(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect the format of %s: %s", fileName, err)
+		}
+	}
+	cases, err := Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %s", fileName, err)
+	}
+	return cases, nil
+}
+
+// Parse parses the test report in the given format
+func Parse(data []byte, format string) ([]TestCase, error) {
+	switch format {
+	case FormatJUnit:
+		return ParseJUnit(data)
+	case FormatXUnit:
+		return ParseXUnit(data)
+	case FormatGoTest:
+		return ParseGoTest(data)
+	default:
+		return nil, fmt.Errorf("unsupported test report format %s. Supported formats are: %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+// DetectFormat detects the format of the test report from its content
+func DetectFormat(data []byte) (string, error) {
+	text := bytes.TrimSpace(data)
+	if bytes.HasPrefix(text, []byte("{")) {
+		return FormatGoTest, nil
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(text))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", fmt.Errorf("not a JUnit, xUnit or go test report")
+		}
+		if element, ok := token.(xml.StartElement); ok {
+			switch element.Name.Local {
+			case "testsuites", "testsuite":
+				return FormatJUnit, nil
+			case "assemblies", "assembly":
+				return FormatXUnit, nil
+			default:
+				return "", fmt.Errorf("unknown root element %s", element.Name.Local)
+			}
+		}
+	}
+}
+
+// ParseJUnit parses a JUnit XML report whose root element is either testsuites or a single testsuite
+func ParseJUnit(data []byte) ([]TestCase, error) {
+	suites := junitSuites{}
+	err := xml.Unmarshal(data, &suites)
+	if err != nil {
+		return nil, err
+	}
+	if len(suites.Suites) == 0 {
+		suite := junitSuite{}
+		err = xml.Unmarshal(data, &suite)
+		if err != nil {
+			return nil, err
+		}
+		suites.Suites = append(suites.Suites, suite)
+	}
+	answer := []TestCase{}
+	for _, suite := range suites.Suites {
+		answer = appendJUnitSuite(answer, &suite)
+	}
+	return answer, nil
+}
+
+func appendJUnitSuite(answer []TestCase, suite *junitSuite) []TestCase {
+	for _, c := range suite.Cases {
+		testCase := TestCase{
+			Suite:    suite.Name,
+			Class:    c.ClassName,
+			Name:     c.Name,
+			Duration: parseSeconds(c.Time),
+			Status:   StatusPassed,
+		}
+		if c.Failure != nil {
+			testCase.Status = StatusFailed
+			testCase.Message = c.Failure.describe()
+		} else if c.Error != nil {
+			testCase.Status = StatusFailed
+			testCase.Message = c.Error.describe()
+		} else if c.Skipped != nil {
+			testCase.Status = StatusSkipped
+			testCase.Message = c.Skipped.describe()
+		}
+		answer = append(answer, testCase)
+	}
+	for _, child := range suite.Suites {
+		answer = appendJUnitSuite(answer, &child)
+	}
+	return answer
+}
+
+func (m *junitMessage) describe() string {
+	if m.Message != "" {
+		return m.Message
+	}
+	return strings.TrimSpace(m.Text)
+}
+
+// ParseXUnit parses an xUnit.net v2 XML report
+func ParseXUnit(data []byte) ([]TestCase, error) {
+	assemblies := xunitAssemblies{}
+	err := xml.Unmarshal(data, &assemblies)
+	if err != nil {
+		return nil, err
+	}
+	if len(assemblies.Assemblies) == 0 {
+		assembly := xunitAssembly{}
+		err = xml.Unmarshal(data, &assembly)
+		if err != nil {
+			return nil, err
+		}
+		assemblies.Assemblies = append(assemblies.Assemblies, assembly)
+	}
+	answer := []TestCase{}
+	for _, assembly := range assemblies.Assemblies {
+		for _, collection := range assembly.Collections {
+			for _, test := range collection.Tests {
+				// the name of an xUnit.net test already includes its type so only keep the method name
+				name := strings.TrimPrefix(test.Name, test.Type+".")
+				testCase := TestCase{
+					Suite:    assembly.Name,
+					Class:    test.Type,
+					Name:     name,
+					Duration: parseSeconds(test.Time),
+					Status:   StatusPassed,
+				}
+				switch strings.ToLower(test.Result) {
+				case "fail":
+					testCase.Status = StatusFailed
+					if test.Failure != nil {
+						testCase.Message = strings.TrimSpace(test.Failure.Message)
+					}
+				case "skip":
+					testCase.Status = StatusSkipped
+					testCase.Message = strings.TrimSpace(test.Reason)
+				}
+				answer = append(answer, testCase)
+			}
+		}
+	}
+	return answer, nil
+}
+
+// ParseGoTest parses the JSON events written by 'go test -json'
+func ParseGoTest(data []byte) ([]TestCase, error) {
+	answer := []TestCase{}
+	output := map[string]*bytes.Buffer{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		event := goTestEvent{}
+		err := json.Unmarshal(line, &event)
+		if err != nil {
+			return nil, err
+		}
+		if event.Test == "" {
+			continue
+		}
+		key := event.Package + " " + event.Test
+		var status string
+		switch event.Action {
+		case "output":
+			buffer := output[key]
+			if buffer == nil {
+				buffer = &bytes.Buffer{}
+				output[key] = buffer
+			}
+			buffer.WriteString(event.Output)
+			continue
+		case "pass":
+			status = StatusPassed
+		case "fail":
+			status = StatusFailed
+		case "skip":
+			status = StatusSkipped
+		default:
+			continue
+		}
+		testCase := TestCase{
+			Suite:    event.Package,
+			Class:    event.Package,
+			Name:     event.Test,
+			Duration: time.Duration(event.Elapsed * float64(time.Second)),
+			Status:   status,
+		}
+		if status == StatusFailed && output[key] != nil {
+			testCase.Message = strings.TrimSpace(output[key].String())
+		}
+		delete(output, key)
+		answer = append(answer, testCase)
+	}
+	return answer, scanner.Err()
+}
+
+// parseSeconds parses a duration in seconds such as 1.5 or 1,234.5 returning zero if it is not valid
+func parseSeconds(text string) time.Duration {
+	seconds, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(text), ",", "", -1), 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
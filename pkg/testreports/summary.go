@@ -0,0 +1,219 @@
+package testreports
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+)
+
+const (
+	// StatusPassed the status of a test which passed
+	StatusPassed = "passed"
+	// StatusFailed the status of a test which failed or had an error
+	StatusFailed = "failed"
+	// StatusSkipped the status of a test which was skipped or ignored
+	StatusSkipped = "skipped"
+)
+
+const (
+	// MeasurementTotal the name of the measurement recording the number of tests
+	MeasurementTotal = "total"
+	// MeasurementPassed the name of the measurement recording the number of passed tests
+	MeasurementPassed = "passed"
+	// MeasurementFailed the name of the measurement recording the number of failed tests
+	MeasurementFailed = "failed"
+	// MeasurementSkipped the name of the measurement recording the number of skipped tests
+	MeasurementSkipped = "skipped"
+	// MeasurementDuration the name of the measurement recording the total duration of the tests
+	MeasurementDuration = "duration"
+
+	// StatementTestsPassed the name of the statement recording whether every test passed
+	StatementTestsPassed = "passed"
+
+	// TagSlowest tags the measurements of the durations of the slowest tests
+	TagSlowest = "slowest"
+	// StatementTypeFailed the type of the statements naming the failed tests
+	StatementTypeFailed = "failed"
+	// StatementTypeFlaky the type of the statements naming the flaky tests
+	StatementTypeFlaky = "flaky"
+)
+
+// TestCase the result of a single test
+type TestCase struct {
+	Suite    string
+	Class    string
+	Name     string
+	Duration time.Duration
+	Status   string
+	Message  string
+}
+
+// FullName returns the name of the test qualified by its class or package
+func (t *TestCase) FullName() string {
+	if t.Class == "" {
+		return t.Name
+	}
+	return t.Class + "." + t.Name
+}
+
+// Summary summarises the results of the tests of a build
+type Summary struct {
+	Total    int
+	Passed   int
+	Failed   int
+	Skipped  int
+	Duration time.Duration
+	Failures []TestCase
+	Slowest  []TestCase
+	Flaky    []string
+}
+
+// Summarize summarises the tests keeping the given number of slowest tests
+func Summarize(cases []TestCase, slowest int) *Summary {
+	summary := &Summary{}
+	for _, c := range cases {
+		summary.Total++
+		summary.Duration += c.Duration
+		switch c.Status {
+		case StatusFailed:
+			summary.Failed++
+			summary.Failures = append(summary.Failures, c)
+		case StatusSkipped:
+			summary.Skipped++
+		default:
+			summary.Passed++
+		}
+	}
+	sorted := make([]TestCase, len(cases))
+	copy(sorted, cases)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Duration > sorted[j].Duration
+	})
+	for _, c := range sorted {
+		if len(summary.Slowest) >= slowest || c.Duration <= 0 {
+			break
+		}
+		summary.Slowest = append(summary.Slowest, c)
+	}
+	return summary
+}
+
+// AllPassed returns true if no tests failed
+func (s *Summary) AllPassed() bool {
+	return s.Failed == 0
+}
+
+// FailedTests returns the full names of the failed tests
+func (s *Summary) FailedTests() []string {
+	answer := []string{}
+	for _, c := range s.Failures {
+		answer = append(answer, c.FullName())
+	}
+	return answer
+}
+
+// Fact returns the fact recording the summary on a PipelineActivity. The failed and flaky tests are recorded as
+// statements so that later builds can detect flaky tests
+func (s *Summary) Fact(name string) v1.Fact {
+	measurements := []v1.Measurement{
+		{Name: MeasurementTotal, MeasurementType: v1.MeasurementCount, MeasurementValue: s.Total},
+		{Name: MeasurementPassed, MeasurementType: v1.MeasurementCount, MeasurementValue: s.Passed},
+		{Name: MeasurementFailed, MeasurementType: v1.MeasurementCount, MeasurementValue: s.Failed},
+		{Name: MeasurementSkipped, MeasurementType: v1.MeasurementCount, MeasurementValue: s.Skipped},
+		{Name: MeasurementDuration, MeasurementType: v1.MeasurementMilliseconds, MeasurementValue: durationMillis(s.Duration)},
+	}
+	for _, c := range s.Slowest {
+		measurements = append(measurements, v1.Measurement{
+			Name:             c.FullName(),
+			MeasurementType:  v1.MeasurementMilliseconds,
+			MeasurementValue: durationMillis(c.Duration),
+			Tags:             []string{TagSlowest},
+		})
+	}
+	statements := []v1.Statement{
+		{
+			Name:             StatementTestsPassed,
+			StatementType:    StatementTestsPassed,
+			MeasurementValue: s.AllPassed(),
+		},
+	}
+	for _, test := range s.FailedTests() {
+		statements = append(statements, v1.Statement{
+			Name:             test,
+			StatementType:    StatementTypeFailed,
+			MeasurementValue: false,
+		})
+	}
+	for _, test := range s.Flaky {
+		statements = append(statements, v1.Statement{
+			Name:             test,
+			StatementType:    StatementTypeFlaky,
+			MeasurementValue: true,
+		})
+	}
+	return v1.Fact{
+		Name:         name,
+		FactType:     v1.FactTypeTestResults,
+		Measurements: measurements,
+		Statements:   statements,
+	}
+}
+
+// AddTestFact adds the fact to the activity replacing any previous test results of the same name
+func AddTestFact(activity *v1.PipelineActivity, fact v1.Fact) {
+	for i := range activity.Spec.Facts {
+		existing := &activity.Spec.Facts[i]
+		if existing.Name == fact.Name && existing.FactType == fact.FactType {
+			fact.ID = existing.ID
+			*existing = fact
+			return
+		}
+	}
+	fact.ID = len(activity.Spec.Facts) + 1
+	activity.Spec.Facts = append(activity.Spec.Facts, fact)
+}
+
+// Markdown returns the summary as markdown suitable for a pull request comment
+func (s *Summary) Markdown(title string) string {
+	var buffer bytes.Buffer
+	icon := ":white_check_mark:"
+	if !s.AllPassed() {
+		icon = ":x:"
+	}
+	fmt.Fprintf(&buffer, "### %s %s\n\n", icon, title)
+	buffer.WriteString("| Total | Passed | Failed | Skipped | Duration |\n")
+	buffer.WriteString("| --- | --- | --- | --- | --- |\n")
+	fmt.Fprintf(&buffer, "| %d | %d | %d | %d | %s |\n", s.Total, s.Passed, s.Failed, s.Skipped, s.Duration.Round(time.Millisecond))
+	if len(s.Failures) > 0 {
+		buffer.WriteString("\n**Failed tests**\n\n")
+		for _, c := range s.Failures {
+			message := strings.TrimSpace(strings.SplitN(c.Message, "\n", 2)[0])
+			if message == "" {
+				fmt.Fprintf(&buffer, "* `%s`\n", c.FullName())
+			} else {
+				fmt.Fprintf(&buffer, "* `%s`: %s\n", c.FullName(), message)
+			}
+		}
+	}
+	if len(s.Flaky) > 0 {
+		buffer.WriteString("\n**Flaky tests**\n\n")
+		for _, test := range s.Flaky {
+			fmt.Fprintf(&buffer, "* `%s`\n", test)
+		}
+	}
+	if len(s.Slowest) > 0 {
+		buffer.WriteString("\n**Slowest tests**\n\n")
+		for _, c := range s.Slowest {
+			fmt.Fprintf(&buffer, "* `%s` %s\n", c.FullName(), c.Duration.Round(time.Millisecond))
+		}
+	}
+	return buffer.String()
+}
+
+func durationMillis(d time.Duration) int {
+	return int(d / time.Millisecond)
+}
@@ -0,0 +1,140 @@
+package testreports_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/testreports"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJUnit(t *testing.T) {
+	t.Parallel()
+	cases, err := testreports.ParseFile(filepath.Join("test_data", "junit.xml"), "")
+	assert.NoError(t, err)
+	if assert.Len(t, cases, 4) {
+		assert.Equal(t, "com.example.CalculatorTest.testAdd", cases[0].FullName())
+		assert.Equal(t, testreports.StatusPassed, cases[0].Status)
+		assert.Equal(t, 250*time.Millisecond, cases[0].Duration)
+
+		assert.Equal(t, testreports.StatusFailed, cases[1].Status)
+		assert.Equal(t, "expected 2 but was 3", cases[1].Message)
+		assert.Equal(t, 1200500*time.Millisecond, cases[1].Duration)
+
+		assert.Equal(t, testreports.StatusFailed, cases[2].Status)
+		assert.Equal(t, "Connection refused", cases[2].Message)
+		assert.Equal(t, testreports.StatusSkipped, cases[3].Status)
+	}
+
+	cases, err = testreports.ParseFile(filepath.Join("test_data", "junit-suite.xml"), "")
+	assert.NoError(t, err)
+	if assert.Len(t, cases, 2) {
+		assert.Equal(t, "pytest", cases[0].Suite)
+		assert.Equal(t, "tests.test_app.test_health", cases[1].FullName())
+	}
+}
+
+func TestParseXUnit(t *testing.T) {
+	t.Parallel()
+	cases, err := testreports.ParseFile(filepath.Join("test_data", "xunit.xml"), "")
+	assert.NoError(t, err)
+	if assert.Len(t, cases, 3) {
+		assert.Equal(t, "MyApp.Tests.ValuesTest.Get", cases[0].FullName())
+		assert.Equal(t, testreports.StatusPassed, cases[0].Status)
+		assert.Equal(t, testreports.StatusFailed, cases[1].Status)
+		assert.Equal(t, "Assert.Equal() Failure", cases[1].Message)
+		assert.Equal(t, testreports.StatusSkipped, cases[2].Status)
+		assert.Equal(t, "not implemented yet", cases[2].Message)
+	}
+}
+
+func TestParseGoTest(t *testing.T) {
+	t.Parallel()
+	cases, err := testreports.ParseFile(filepath.Join("test_data", "gotest.json"), "")
+	assert.NoError(t, err)
+	if assert.Len(t, cases, 3) {
+		assert.Equal(t, "github.com/myorg/myapp/pkg/app.TestIndex", cases[0].FullName())
+		assert.Equal(t, testreports.StatusPassed, cases[0].Status)
+		assert.Equal(t, 150*time.Millisecond, cases[0].Duration)
+		assert.Equal(t, testreports.StatusFailed, cases[1].Status)
+		assert.True(t, strings.Contains(cases[1].Message, "expected 200 but got 500"), "message %s should contain the test output", cases[1].Message)
+		assert.Equal(t, testreports.StatusSkipped, cases[2].Status)
+	}
+}
+
+func TestDetectFormatUnknown(t *testing.T) {
+	t.Parallel()
+	_, err := testreports.DetectFormat([]byte("<html></html>"))
+	assert.Error(t, err)
+	_, err = testreports.Parse([]byte("{}"), "nunit")
+	assert.Error(t, err)
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+	cases, err := testreports.ParseFile(filepath.Join("test_data", "junit.xml"), testreports.FormatJUnit)
+	assert.NoError(t, err)
+
+	summary := testreports.Summarize(cases, 2)
+	assert.Equal(t, 4, summary.Total)
+	assert.Equal(t, 1, summary.Passed)
+	assert.Equal(t, 2, summary.Failed)
+	assert.Equal(t, 1, summary.Skipped)
+	assert.False(t, summary.AllPassed())
+	assert.Equal(t, []string{"com.example.CalculatorTest.testDivide", "com.example.CalculatorTest.testConnect"}, summary.FailedTests())
+	if assert.Len(t, summary.Slowest, 2) {
+		assert.Equal(t, "testDivide", summary.Slowest[0].Name)
+		assert.Equal(t, "testConnect", summary.Slowest[1].Name)
+	}
+
+	summary.Flaky = []string{"com.example.CalculatorTest.testConnect"}
+	fact := summary.Fact("tests")
+	assert.Equal(t, v1.FactTypeTestResults, fact.FactType)
+	assert.Equal(t, testreports.MeasurementTotal, fact.Measurements[0].Name)
+	assert.Equal(t, 4, fact.Measurements[0].MeasurementValue)
+	assert.Equal(t, []string{testreports.TagSlowest}, fact.Measurements[len(fact.Measurements)-1].Tags)
+
+	activity := &v1.PipelineActivity{}
+	testreports.AddTestFact(activity, fact)
+	testreports.AddTestFact(activity, fact)
+	assert.Len(t, activity.Spec.Facts, 1)
+
+	run := testreports.TestRunFromActivity(activity, "tests")
+	if assert.NotNil(t, run) {
+		assert.Len(t, run.Failed, 2)
+		assert.True(t, run.Failed["com.example.CalculatorTest.testDivide"])
+	}
+	assert.Nil(t, testreports.TestRunFromActivity(activity, "integration-tests"))
+
+	markdown := summary.Markdown("Test results")
+	assert.True(t, strings.Contains(markdown, "| 4 | 1 | 2 | 1 |"), "markdown should contain the totals:\n%s", markdown)
+	assert.True(t, strings.Contains(markdown, "* `com.example.CalculatorTest.testDivide`: expected 2 but was 3"), "markdown should contain the failures:\n%s", markdown)
+	assert.True(t, strings.Contains(markdown, "**Flaky tests**"), "markdown should contain the flaky tests:\n%s", markdown)
+}
+
+func TestFlakyTests(t *testing.T) {
+	t.Parallel()
+	runs := []*testreports.TestRun{
+		testreports.NewTestRun("a1", []string{"TestFlaky", "TestFixed"}),
+		testreports.NewTestRun("a2", []string{"TestFixed"}),
+		testreports.NewTestRun("a3", []string{"TestFlaky"}),
+		testreports.NewTestRun("a3", []string{"TestSameCommit"}),
+		testreports.NewTestRun("a4", []string{"TestBroken"}),
+		testreports.NewTestRun("a5", []string{}),
+	}
+	assert.Equal(t, []string{"TestFlaky", "TestSameCommit"}, testreports.FlakyTests(runs))
+}
+
+func TestFlakyTestsAcrossCommits(t *testing.T) {
+	t.Parallel()
+	runs := []*testreports.TestRun{
+		testreports.NewTestRun("a1", []string{"TestFlipping"}),
+		testreports.NewTestRun("a2", []string{"TestBrokenThenFixed"}),
+		testreports.NewTestRun("a3", []string{"TestFlipping"}),
+		testreports.NewTestRun("a4", []string{}),
+	}
+	assert.Equal(t, []string{"TestFlipping"}, testreports.FlakyTests(runs), "a test which passes, fails and then passes again is not flaky")
+}
@@ -0,0 +1,119 @@
+package verify
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/config"
+)
+
+const (
+	// MeasurementStatusCode the name of the measurement recording the HTTP status code of a probe
+	MeasurementStatusCode = "statusCode"
+
+	defaultHTTPTimeout = 30 * time.Second
+)
+
+// CheckHTTP probes the endpoint of the check resolving its path against the application URL if it has no URL
+func CheckHTTP(name string, check *config.HTTPVerifyCheck, appURL string) *Result {
+	result := &Result{
+		Name: name,
+		Kind: KindHTTP,
+	}
+	u, err := HTTPCheckURL(check, appURL)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	method := check.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequest(method, u, nil)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	for k, v := range check.Headers {
+		req.Header.Set(k, v)
+	}
+	client := &http.Client{
+		Timeout: defaultHTTPTimeout,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		result.Message = fmt.Sprintf("failed to invoke %s %s: %s", method, u, err)
+		return result
+	}
+	defer resp.Body.Close()
+	result.Measurements = append(result.Measurements, v1.Measurement{
+		Name:             MeasurementStatusCode,
+		MeasurementType:  v1.MeasurementCount,
+		MeasurementValue: resp.StatusCode,
+	})
+
+	expected := check.ExpectedStatus
+	if len(expected) == 0 {
+		expected = []int{http.StatusOK}
+	}
+	statusOK := false
+	for _, status := range expected {
+		if status == resp.StatusCode {
+			statusOK = true
+			break
+		}
+	}
+	if !statusOK {
+		result.Message = fmt.Sprintf("%s %s returned status %d but expected %v", method, u, resp.StatusCode, expected)
+		return result
+	}
+
+	if check.BodyContains != "" || check.BodyRegex != "" {
+		data, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			result.Message = fmt.Sprintf("failed to read the response of %s: %s", u, err)
+			return result
+		}
+		body := string(data)
+		if check.BodyContains != "" && !strings.Contains(body, check.BodyContains) {
+			result.Message = fmt.Sprintf("response of %s does not contain %s", u, check.BodyContains)
+			return result
+		}
+		if check.BodyRegex != "" {
+			r, err := regexp.Compile(check.BodyRegex)
+			if err != nil {
+				result.Message = fmt.Sprintf("invalid body regex %s: %s", check.BodyRegex, err)
+				return result
+			}
+			if !r.MatchString(body) {
+				result.Message = fmt.Sprintf("response of %s does not match %s", u, check.BodyRegex)
+				return result
+			}
+		}
+	}
+	result.Passed = true
+	result.Message = fmt.Sprintf("%s %s returned status %d", method, u, resp.StatusCode)
+	return result
+}
+
+// HTTPCheckURL returns the URL to probe for the check
+func HTTPCheckURL(check *config.HTTPVerifyCheck, appURL string) (string, error) {
+	if check.URL != "" {
+		return check.URL, nil
+	}
+	if appURL == "" {
+		return "", fmt.Errorf("no url specified and the URL of the application is not known")
+	}
+	if !strings.Contains(appURL, "://") {
+		appURL = "http://" + appURL
+	}
+	if check.Path == "" {
+		return appURL, nil
+	}
+	return strings.TrimSuffix(appURL, "/") + "/" + strings.TrimPrefix(check.Path, "/"), nil
+}
@@ -0,0 +1,89 @@
+package verify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/kube"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CheckJob runs the smoke test Job of the check in the namespace and waits for it to terminate
+func CheckJob(kubeClient kubernetes.Interface, ns string, name string, check *config.JobVerifyCheck, envVars []corev1.EnvVar, timeout time.Duration) *Result {
+	result := &Result{
+		Name: name,
+		Kind: KindJob,
+	}
+	if check.Image == "" {
+		result.Message = "no image specified for the Job"
+		return result
+	}
+	job := CreateCheckJob(name, check, envVars)
+	jobs := kubeClient.BatchV1().Jobs(ns)
+	job, err := jobs.Create(job)
+	if err != nil {
+		result.Message = fmt.Sprintf("failed to create Job for check %s: %s", name, err)
+		return result
+	}
+	jobName := job.Name
+	defer kube.DeleteJob(kubeClient, ns, jobName)
+
+	err = kube.WaitForJobToTerminate(kubeClient, ns, jobName, timeout)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	job, err = jobs.Get(jobName, metav1.GetOptions{})
+	if err != nil {
+		result.Message = fmt.Sprintf("failed to find Job %s: %s", jobName, err)
+		return result
+	}
+	if !kube.IsJobSucceeded(job) {
+		result.Message = fmt.Sprintf("Job %s failed", jobName)
+		return result
+	}
+	result.Passed = true
+	result.Message = fmt.Sprintf("Job %s succeeded", jobName)
+	return result
+}
+
+// CreateCheckJob creates the Job which runs the smoke test of the check
+func CreateCheckJob(name string, check *config.JobVerifyCheck, envVars []corev1.EnvVar) *batchv1.Job {
+	jobName := kube.ToValidName("verify-" + name)
+	if len(jobName) > 57 {
+		jobName = strings.TrimSuffix(jobName[0:57], "-")
+	}
+	jobName += "-" + strings.ToLower(string(uuid.NewUUID())[0:5])
+	backoffLimit := int32(0)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: jobName,
+			Labels: map[string]string{
+				kube.LabelJobKind: kube.ValueJobKindVerifyCheck,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    "verify",
+							Image:   check.Image,
+							Command: check.Command,
+							Args:    check.Args,
+							Env:     append(append([]corev1.EnvVar{}, envVars...), check.Env...),
+						},
+					},
+				},
+			},
+		},
+	}
+}
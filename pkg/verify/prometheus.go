@@ -0,0 +1,54 @@
+package verify
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/prometheus"
+)
+
+const (
+	// MeasurementValue the name of the measurement recording the rounded value of a Prometheus query
+	MeasurementValue = "value"
+)
+
+// CheckPrometheus evaluates the query of the check against its Prometheus server or the default server if it has no URL
+func CheckPrometheus(name string, check *config.PrometheusVerifyCheck, defaultURL string) *Result {
+	result := &Result{
+		Name: name,
+		Kind: KindPrometheus,
+	}
+	u := check.URL
+	if u == "" {
+		u = defaultURL
+	}
+	if check.Query == "" {
+		result.Message = "no Prometheus query specified"
+		return result
+	}
+	value, err := prometheus.QueryScalar(u, check.Query)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	result.Measurements = append(result.Measurements, v1.Measurement{
+		Name:             MeasurementValue,
+		MeasurementType:  v1.MeasurementCount,
+		MeasurementValue: int(math.Round(value)),
+		Tags:             []string{strconv.FormatFloat(value, 'f', -1, 64)},
+	})
+	if check.Min != nil && value < *check.Min {
+		result.Message = fmt.Sprintf("query %s returned %v which is less than the minimum of %v", check.Query, value, *check.Min)
+		return result
+	}
+	if check.Max != nil && value > *check.Max {
+		result.Message = fmt.Sprintf("query %s returned %v which is more than the maximum of %v", check.Query, value, *check.Max)
+		return result
+	}
+	result.Passed = true
+	result.Message = fmt.Sprintf("query %s returned %v", check.Query, value)
+	return result
+}
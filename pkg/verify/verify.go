@@ -0,0 +1,129 @@
+package verify
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/config"
+)
+
+const (
+	// KindPods the kind of result of checking the pods of the application
+	KindPods = "pods"
+	// KindHTTP the kind of result of an HTTP check
+	KindHTTP = "http"
+	// KindPrometheus the kind of result of a Prometheus check
+	KindPrometheus = "prometheus"
+	// KindJob the kind of result of a Job check
+	KindJob = "job"
+
+	// StatementPassed the name of the statement recording whether a check passed
+	StatementPassed = "passed"
+	// MeasurementAttempts the name of the measurement recording how many times a check was attempted
+	MeasurementAttempts = "attempts"
+	// MeasurementDuration the name of the measurement recording how long a check took
+	MeasurementDuration = "duration"
+)
+
+// Result the result of a verification check
+type Result struct {
+	Name         string
+	Kind         string
+	Passed       bool
+	Message      string
+	Attempts     int
+	Duration     time.Duration
+	Measurements []v1.Measurement
+}
+
+// CheckName returns the name of the check defaulting it from its kind and index if it is not specified
+func CheckName(check *config.VerifyCheck, index int) string {
+	if check.Name != "" {
+		return check.Name
+	}
+	return fmt.Sprintf("%s-%d", CheckKind(check), index+1)
+}
+
+// CheckKind returns the kind of the check or an empty string if it does not specify a supported check
+func CheckKind(check *config.VerifyCheck) string {
+	switch {
+	case check.HTTP != nil:
+		return KindHTTP
+	case check.Prometheus != nil:
+		return KindPrometheus
+	case check.Job != nil:
+		return KindJob
+	default:
+		return ""
+	}
+}
+
+// Retry runs the check until it passes or the timeout expires waiting for the interval between attempts
+// returning the result of the last attempt
+func Retry(timeout time.Duration, interval time.Duration, check func() *Result) *Result {
+	start := time.Now()
+	deadline := start.Add(timeout)
+	attempts := 0
+	for {
+		attempts++
+		result := check()
+		result.Attempts = attempts
+		result.Duration = time.Since(start)
+		if result.Passed || !time.Now().Add(interval).Before(deadline) {
+			return result
+		}
+		time.Sleep(interval)
+	}
+}
+
+// Fact returns the fact recording the result on a PipelineActivity
+func (r *Result) Fact() v1.Fact {
+	measurements := append([]v1.Measurement{}, r.Measurements...)
+	measurements = append(measurements,
+		v1.Measurement{
+			Name:             MeasurementAttempts,
+			MeasurementType:  v1.MeasurementCount,
+			MeasurementValue: r.Attempts,
+		},
+		v1.Measurement{
+			Name:             MeasurementDuration,
+			MeasurementType:  v1.MeasurementMilliseconds,
+			MeasurementValue: int(r.Duration / time.Millisecond),
+		},
+	)
+	return v1.Fact{
+		Name:         r.Name,
+		FactType:     v1.FactTypeVerify,
+		Measurements: measurements,
+		Statements: []v1.Statement{
+			{
+				Name:             StatementPassed,
+				StatementType:    r.Kind,
+				MeasurementValue: r.Passed,
+			},
+		},
+		Tags: []string{r.Kind},
+	}
+}
+
+// AddFacts adds the facts of the results to the activity replacing any previous verification facts of the same name
+func AddFacts(activity *v1.PipelineActivity, results []*Result) {
+	for _, result := range results {
+		fact := result.Fact()
+		found := false
+		for i := range activity.Spec.Facts {
+			existing := &activity.Spec.Facts[i]
+			if existing.Name == fact.Name && existing.FactType == fact.FactType {
+				fact.ID = existing.ID
+				*existing = fact
+				found = true
+				break
+			}
+		}
+		if !found {
+			fact.ID = len(activity.Spec.Facts) + 1
+			activity.Spec.Facts = append(activity.Spec.Facts, fact)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package verify_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/verify"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckHTTP(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			fmt.Fprint(w, `{"status":"UP","version":"1.0.1"}`)
+		case "/admin":
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, "ok")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		check    config.HTTPVerifyCheck
+		appURL   string
+		expected bool
+	}{
+		{"app url", config.HTTPVerifyCheck{Path: "/health"}, server.URL, true},
+		{"explicit url", config.HTTPVerifyCheck{URL: server.URL + "/health"}, "", true},
+		{"no url", config.HTTPVerifyCheck{Path: "/health"}, "", false},
+		{"not found", config.HTTPVerifyCheck{Path: "/missing"}, server.URL, false},
+		{"expected not found", config.HTTPVerifyCheck{Path: "/missing", ExpectedStatus: []int{404}}, server.URL, true},
+		{"body contains", config.HTTPVerifyCheck{Path: "/health", BodyContains: `"UP"`}, server.URL, true},
+		{"body does not contain", config.HTTPVerifyCheck{Path: "/health", BodyContains: `"DOWN"`}, server.URL, false},
+		{"body regex", config.HTTPVerifyCheck{Path: "/health", BodyRegex: `"version":"1\.0\.\d+"`}, server.URL, true},
+		{"body does not match", config.HTTPVerifyCheck{Path: "/health", BodyRegex: `"version":"2\.`}, server.URL, false},
+		{"headers", config.HTTPVerifyCheck{Path: "admin", Headers: map[string]string{"Authorization": "Bearer token"}}, server.URL, true},
+		{"missing headers", config.HTTPVerifyCheck{Path: "admin"}, server.URL, false},
+	}
+	for _, test := range tests {
+		result := verify.CheckHTTP(test.name, &test.check, test.appURL)
+		assert.Equal(t, test.expected, result.Passed, "%s: %s", test.name, result.Message)
+		assert.Equal(t, verify.KindHTTP, result.Kind, test.name)
+	}
+}
+
+func TestCheckPrometheus(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query", r.URL.Path)
+		assert.Equal(t, "error_rate", r.URL.Query().Get("query"))
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1435781451.781,"0.25"]}]}}`)
+	}))
+	defer server.Close()
+
+	low := 0.1
+	high := 0.5
+
+	result := verify.CheckPrometheus("errors", &config.PrometheusVerifyCheck{Query: "error_rate", Max: &high}, server.URL)
+	assert.True(t, result.Passed, result.Message)
+	if assert.Len(t, result.Measurements, 1) {
+		assert.Equal(t, []string{"0.25"}, result.Measurements[0].Tags)
+	}
+
+	result = verify.CheckPrometheus("errors", &config.PrometheusVerifyCheck{URL: server.URL, Query: "error_rate", Max: &low}, "")
+	assert.False(t, result.Passed, "above maximum")
+
+	result = verify.CheckPrometheus("errors", &config.PrometheusVerifyCheck{Query: "error_rate", Min: &high}, server.URL)
+	assert.False(t, result.Passed, "below minimum")
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+	count := 0
+	result := verify.Retry(time.Second, time.Millisecond, func() *verify.Result {
+		count++
+		return &verify.Result{
+			Name:   "flaky",
+			Passed: count == 3,
+		}
+	})
+	assert.True(t, result.Passed, "passed")
+	assert.Equal(t, 3, result.Attempts, "attempts")
+
+	result = verify.Retry(0, time.Millisecond, func() *verify.Result {
+		return &verify.Result{
+			Name: "broken",
+		}
+	})
+	assert.False(t, result.Passed, "failed")
+	assert.Equal(t, 1, result.Attempts, "attempts without a timeout")
+}
+
+func TestAddFacts(t *testing.T) {
+	t.Parallel()
+	activity := &v1.PipelineActivity{
+		Spec: v1.PipelineActivitySpec{
+			Facts: []v1.Fact{
+				{
+					Name:     "coverage",
+					ID:       1,
+					FactType: v1.FactTypeCoverage,
+				},
+			},
+		},
+	}
+	verify.AddFacts(activity, []*verify.Result{
+		{Name: "health", Kind: verify.KindHTTP, Attempts: 2},
+	})
+	verify.AddFacts(activity, []*verify.Result{
+		{Name: "health", Kind: verify.KindHTTP, Passed: true, Attempts: 3},
+		{Name: "coverage", Kind: verify.KindJob, Passed: true, Attempts: 1},
+	})
+
+	facts := activity.Spec.Facts
+	if assert.Len(t, facts, 3, "facts") {
+		assert.Equal(t, v1.FactTypeCoverage, facts[0].FactType)
+		assert.Equal(t, "health", facts[1].Name)
+		assert.Equal(t, 2, facts[1].ID)
+		assert.True(t, facts[1].Statements[0].MeasurementValue, "health passed")
+		assert.Equal(t, verify.MeasurementAttempts, facts[1].Measurements[0].Name)
+		assert.Equal(t, 3, facts[1].Measurements[0].MeasurementValue)
+		assert.Equal(t, "coverage", facts[2].Name)
+		assert.Equal(t, v1.FactTypeVerify, facts[2].FactType)
+		assert.Equal(t, 3, facts[2].ID)
+	}
+}
@@ -0,0 +1,299 @@
+package workflow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StepName returns the name of the workflow step which defaults to the kind and environment of promotion steps.
+// Other unnamed steps default to their kind followed by their position among the unnamed steps of the same kind
+// in the workflow such as approve, approve-2
+func StepName(flow *v1.Workflow, step *v1.WorkflowStep) string {
+	if step.Name != "" {
+		return step.Name
+	}
+	if step.Promote != nil && step.Promote.Environment != "" {
+		return "promote-" + step.Promote.Environment
+	}
+	name := strings.ToLower(string(step.Kind))
+	if flow == nil {
+		return name
+	}
+	count := 0
+	for i := range flow.Spec.Steps {
+		s := &flow.Spec.Steps[i]
+		if s == step {
+			if count > 0 {
+				return fmt.Sprintf("%s-%d", name, count+1)
+			}
+			return name
+		}
+		if s.Name == "" && s.Kind == step.Kind && (s.Promote == nil || s.Promote.Environment == "") {
+			count++
+		}
+	}
+	return name
+}
+
+// FindWorkflowStep returns the step of the workflow for the given name or nil if it does not exist
+func FindWorkflowStep(flow *v1.Workflow, name string) *v1.WorkflowStep {
+	for i := range flow.Spec.Steps {
+		step := &flow.Spec.Steps[i]
+		if StepName(flow, step) == name {
+			return step
+		}
+	}
+	return nil
+}
+
+// FindWorkflowActivityStep returns the workflow step of the activity for the given step name or nil if it has not started yet
+func FindWorkflowActivityStep(activity *v1.PipelineActivity, name string) *v1.WorkflowActivityStep {
+	for _, step := range activity.Spec.Steps {
+		w := step.Workflow
+		if w != nil && w.Name == name {
+			return w
+		}
+	}
+	return nil
+}
+
+// GetOrCreateWorkflowActivityStep gets or creates the workflow step of the activity for the given step of the workflow
+func GetOrCreateWorkflowActivityStep(flow *v1.Workflow, activity *v1.PipelineActivity, step *v1.WorkflowStep) (*v1.WorkflowActivityStep, bool) {
+	name := StepName(flow, step)
+	answer := FindWorkflowActivityStep(activity, name)
+	if answer != nil {
+		return answer, false
+	}
+	answer = &v1.WorkflowActivityStep{
+		CoreActivityStep: v1.CoreActivityStep{
+			Name:        name,
+			Description: step.Description,
+			StartedTimestamp: &metav1.Time{
+				Time: time.Now(),
+			},
+		},
+		Kind: step.Kind,
+	}
+	activity.Spec.Steps = append(activity.Spec.Steps, v1.PipelineActivityStep{
+		Kind:     v1.ActivityStepKindTypeWorkflow,
+		Workflow: answer,
+	})
+	return answer, true
+}
+
+// CompleteWorkflowActivityStep marks the workflow step as completed with the given status
+func CompleteWorkflowActivityStep(step *v1.WorkflowActivityStep, status v1.ActivityStatusType, message string) {
+	now := &metav1.Time{
+		Time: time.Now(),
+	}
+	if step.StartedTimestamp == nil {
+		step.StartedTimestamp = now
+	}
+	if step.CompletedTimestamp == nil {
+		step.CompletedTimestamp = now
+	}
+	step.Status = status
+	if message != "" {
+		step.Message = message
+	}
+}
+
+// IsStepCompleted returns true if the step with the given name has succeeded or was skipped in the activity
+func IsStepCompleted(flow *v1.Workflow, activity *v1.PipelineActivity, name string) bool {
+	status := StepStatus(flow, activity, name)
+	return status == v1.ActivityStatusTypeSucceeded || status == v1.ActivityStatusTypeSkipped
+}
+
+// StepStatus returns the status of the step with the given name in the activity
+func StepStatus(flow *v1.Workflow, activity *v1.PipelineActivity, name string) v1.ActivityStatusType {
+	w := FindWorkflowActivityStep(activity, name)
+	if w != nil {
+		return w.Status
+	}
+	step := FindWorkflowStep(flow, name)
+	if step != nil && step.Promote != nil {
+		return promoteStatus(activity, step.Promote.Environment)
+	}
+	return v1.ActivityStatusTypeNone
+}
+
+func promoteStatus(activity *v1.PipelineActivity, envName string) v1.ActivityStatusType {
+	for _, step := range activity.Spec.Steps {
+		promote := step.Promote
+		if promote != nil && promote.Environment == envName {
+			return promote.Status
+		}
+	}
+	return v1.ActivityStatusTypeNone
+}
+
+// ArePreconditionsMet returns true if all the environments and steps the given step depends on have completed.
+// The reason is returned if the preconditions are not met
+func ArePreconditionsMet(flow *v1.Workflow, activity *v1.PipelineActivity, step *v1.WorkflowStep) (bool, string) {
+	for _, envName := range step.Preconditions.Environments {
+		status := promoteStatus(activity, envName)
+		if status == v1.ActivityStatusTypeNone {
+			// the promotion may have been skipped by a condition
+			promoteStep := findPromoteStep(flow, envName)
+			if promoteStep != nil && FindWorkflowActivityStep(activity, StepName(flow, promoteStep)) != nil {
+				status = StepStatus(flow, activity, StepName(flow, promoteStep))
+			}
+		}
+		if status != v1.ActivityStatusTypeSucceeded && status != v1.ActivityStatusTypeSkipped {
+			return false, fmt.Sprintf("precondition Environment: %s has status %s", envName, string(status))
+		}
+	}
+	for _, name := range step.Preconditions.Steps {
+		if !IsStepCompleted(flow, activity, name) {
+			return false, fmt.Sprintf("precondition step: %s has status %s", name, string(StepStatus(flow, activity, name)))
+		}
+	}
+	return true, ""
+}
+
+func findPromoteStep(flow *v1.Workflow, envName string) *v1.WorkflowStep {
+	for i := range flow.Spec.Steps {
+		step := &flow.Spec.Steps[i]
+		if step.Promote != nil && step.Promote.Environment == envName {
+			return step
+		}
+	}
+	return nil
+}
+
+// EvaluateConditions returns true if all of the conditions are true for the facts of the activity
+func EvaluateConditions(activity *v1.PipelineActivity, conditions []v1.WorkflowCondition) (bool, error) {
+	for _, condition := range conditions {
+		result, err := EvaluateCondition(activity, &condition)
+		if err != nil || !result {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// EvaluateCondition returns true if the condition is true for the facts of the activity
+func EvaluateCondition(activity *v1.PipelineActivity, condition *v1.WorkflowCondition) (bool, error) {
+	if condition.Fact == "" {
+		return false, fmt.Errorf("no fact specified for workflow condition %#v", condition)
+	}
+	operator := condition.Operator
+	if operator == "" {
+		operator = v1.WorkflowConditionOperatorEquals
+	}
+	fact := findFact(activity, condition.Fact)
+	if operator == v1.WorkflowConditionOperatorExists {
+		return fact != nil, nil
+	}
+	if fact == nil {
+		return false, nil
+	}
+	if condition.Statement != "" {
+		for _, statement := range fact.Statements {
+			if statement.Name == condition.Statement {
+				expected, err := strconv.ParseBool(condition.Value)
+				if err != nil {
+					return false, fmt.Errorf("the value %s of workflow condition on statement %s is not a boolean: %s", condition.Value, condition.Statement, err)
+				}
+				switch operator {
+				case v1.WorkflowConditionOperatorEquals:
+					return statement.MeasurementValue == expected, nil
+				case v1.WorkflowConditionOperatorNotEquals:
+					return statement.MeasurementValue != expected, nil
+				default:
+					return false, fmt.Errorf("unsupported operator %s for workflow condition on statement %s", operator, condition.Statement)
+				}
+			}
+		}
+		return false, nil
+	}
+	if condition.Measurement != "" {
+		for _, measurement := range fact.Measurements {
+			if measurement.Name == condition.Measurement {
+				expected, err := strconv.Atoi(condition.Value)
+				if err != nil {
+					return false, fmt.Errorf("the value %s of workflow condition on measurement %s is not a number: %s", condition.Value, condition.Measurement, err)
+				}
+				actual := measurement.MeasurementValue
+				switch operator {
+				case v1.WorkflowConditionOperatorEquals:
+					return actual == expected, nil
+				case v1.WorkflowConditionOperatorNotEquals:
+					return actual != expected, nil
+				case v1.WorkflowConditionOperatorLessThan:
+					return actual < expected, nil
+				case v1.WorkflowConditionOperatorGreaterThan:
+					return actual > expected, nil
+				default:
+					return false, fmt.Errorf("unsupported operator %s for workflow condition on measurement %s", operator, condition.Measurement)
+				}
+			}
+		}
+		return false, nil
+	}
+	return false, fmt.Errorf("workflow condition on fact %s must specify a measurement or statement unless the operator is %s", condition.Fact, v1.WorkflowConditionOperatorExists)
+}
+
+// findFact finds the fact of the activity with the given name or fact type
+func findFact(activity *v1.PipelineActivity, name string) *v1.Fact {
+	for i := range activity.Spec.Facts {
+		fact := &activity.Spec.Facts[i]
+		if fact.Name == name || fact.FactType == name {
+			return fact
+		}
+	}
+	return nil
+}
+
+// HasApprovers returns true if only the approvers of the given approval step are allowed to approve it. Their
+// permission is granted by a Role so that the API server checks the identity of the user approving the step
+func HasApprovers(step *v1.WorkflowStep) bool {
+	approve := step.Approve
+	return approve != nil && len(approve.Approvers) > 0
+}
+
+// CreateWorkflowApproveStep creates an approval step which must be approved before the steps which depend on it
+func CreateWorkflowApproveStep(name string, preconditionSteps ...v1.WorkflowStep) v1.WorkflowStep {
+	answer := v1.WorkflowStep{
+		Kind:    v1.WorkflowStepKindTypeApprove,
+		Name:    name,
+		Approve: &v1.ApproveWorkflowStep{},
+	}
+	addPreconditionSteps(&answer, preconditionSteps...)
+	return answer
+}
+
+// CreateWorkflowWaitStep creates a step which waits for the given duration
+func CreateWorkflowWaitStep(name string, duration string, preconditionSteps ...v1.WorkflowStep) v1.WorkflowStep {
+	answer := v1.WorkflowStep{
+		Kind: v1.WorkflowStepKindTypeWait,
+		Name: name,
+		Wait: &v1.WaitWorkflowStep{
+			Duration: duration,
+		},
+	}
+	addPreconditionSteps(&answer, preconditionSteps...)
+	return answer
+}
+
+// addPreconditionSteps adds the environments of promotion steps or the names of other steps as preconditions of the step.
+// The other steps are not part of a workflow yet so should be named
+func addPreconditionSteps(step *v1.WorkflowStep, preconditionSteps ...v1.WorkflowStep) {
+	for i := range preconditionSteps {
+		preconditionStep := &preconditionSteps[i]
+		promote := preconditionStep.Promote
+		if promote != nil {
+			envName := promote.Environment
+			if envName != "" {
+				step.Preconditions.Environments = append(step.Preconditions.Environments, envName)
+			}
+		} else {
+			step.Preconditions.Steps = append(step.Preconditions.Steps, StepName(nil, preconditionStep))
+		}
+	}
+}
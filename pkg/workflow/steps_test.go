@@ -0,0 +1,138 @@
+package workflow_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateConditions(t *testing.T) {
+	t.Parallel()
+	activity := &v1.PipelineActivity{
+		Spec: v1.PipelineActivitySpec{
+			Facts: []v1.Fact{
+				{
+					Name:     "coverage",
+					FactType: "jx.coverage",
+					Measurements: []v1.Measurement{
+						{
+							Name:             "lines",
+							MeasurementValue: 80,
+						},
+					},
+					Statements: []v1.Statement{
+						{
+							Name:             "passed",
+							MeasurementValue: true,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		condition v1.WorkflowCondition
+		expected  bool
+		err       bool
+	}{
+		{"default operator", v1.WorkflowCondition{Fact: "coverage", Measurement: "lines", Value: "80"}, true, false},
+		{"fact type", v1.WorkflowCondition{Fact: "jx.coverage", Measurement: "lines", Value: "80"}, true, false},
+		{"greater than", v1.WorkflowCondition{Fact: "coverage", Measurement: "lines", Operator: v1.WorkflowConditionOperatorGreaterThan, Value: "70"}, true, false},
+		{"less than", v1.WorkflowCondition{Fact: "coverage", Measurement: "lines", Operator: v1.WorkflowConditionOperatorLessThan, Value: "70"}, false, false},
+		{"not equals", v1.WorkflowCondition{Fact: "coverage", Measurement: "lines", Operator: v1.WorkflowConditionOperatorNotEquals, Value: "80"}, false, false},
+		{"statement", v1.WorkflowCondition{Fact: "coverage", Statement: "passed", Value: "true"}, true, false},
+		{"statement not equals", v1.WorkflowCondition{Fact: "coverage", Statement: "passed", Operator: v1.WorkflowConditionOperatorNotEquals, Value: "true"}, false, false},
+		{"exists", v1.WorkflowCondition{Fact: "coverage", Operator: v1.WorkflowConditionOperatorExists}, true, false},
+		{"missing fact", v1.WorkflowCondition{Fact: "security", Measurement: "cves", Value: "0"}, false, false},
+		{"missing fact exists", v1.WorkflowCondition{Fact: "security", Operator: v1.WorkflowConditionOperatorExists}, false, false},
+		{"missing measurement", v1.WorkflowCondition{Fact: "coverage", Measurement: "branches", Value: "80"}, false, false},
+		{"invalid number", v1.WorkflowCondition{Fact: "coverage", Measurement: "lines", Value: "high"}, false, true},
+		{"no measurement or statement", v1.WorkflowCondition{Fact: "coverage", Value: "80"}, false, true},
+	}
+	for _, test := range tests {
+		actual, err := workflow.EvaluateConditions(activity, []v1.WorkflowCondition{test.condition})
+		if test.err {
+			assert.Error(t, err, test.name)
+		} else {
+			assert.NoError(t, err, test.name)
+		}
+		assert.Equal(t, test.expected, actual, test.name)
+	}
+}
+
+func TestArePreconditionsMet(t *testing.T) {
+	t.Parallel()
+	staging := workflow.CreateWorkflowPromoteStep("staging")
+	approve := workflow.CreateWorkflowApproveStep("approve-production", staging)
+	soak := workflow.CreateWorkflowWaitStep("soak", "1h", staging)
+	production := workflow.CreateWorkflowPromoteStep("production", approve, soak)
+	flow := workflow.CreateWorkflow("jx", "myflow", staging, approve, soak, production)
+
+	activity := &v1.PipelineActivity{}
+
+	met, _ := workflow.ArePreconditionsMet(flow, activity, &approve)
+	assert.False(t, met, "approve before staging promoted")
+
+	activity.Spec.Steps = append(activity.Spec.Steps, v1.PipelineActivityStep{
+		Kind: v1.ActivityStepKindTypePromote,
+		Promote: &v1.PromoteActivityStep{
+			CoreActivityStep: v1.CoreActivityStep{
+				Status: v1.ActivityStatusTypeSucceeded,
+			},
+			Environment: "staging",
+		},
+	})
+
+	// the approval and wait steps can now run in parallel
+	met, _ = workflow.ArePreconditionsMet(flow, activity, &approve)
+	assert.True(t, met, "approve after staging promoted")
+	met, _ = workflow.ArePreconditionsMet(flow, activity, &soak)
+	assert.True(t, met, "soak after staging promoted")
+
+	approveStep, created := workflow.GetOrCreateWorkflowActivityStep(flow, activity, &approve)
+	assert.True(t, created, "created approve step")
+	approveStep.Status = v1.ActivityStatusTypeWaitingForApproval
+
+	_, created = workflow.GetOrCreateWorkflowActivityStep(flow, activity, &approve)
+	assert.False(t, created, "approve step already exists")
+
+	met, reason := workflow.ArePreconditionsMet(flow, activity, &production)
+	assert.False(t, met, "production waiting for approval")
+	assert.Contains(t, reason, "approve-production")
+
+	workflow.CompleteWorkflowActivityStep(approveStep, v1.ActivityStatusTypeSucceeded, "Approved")
+	soakStep, _ := workflow.GetOrCreateWorkflowActivityStep(flow, activity, &soak)
+	workflow.CompleteWorkflowActivityStep(soakStep, v1.ActivityStatusTypeSkipped, "")
+
+	met, _ = workflow.ArePreconditionsMet(flow, activity, &production)
+	assert.True(t, met, "production after approval and skipped soak")
+	assert.NotNil(t, approveStep.CompletedTimestamp, "approve step completed")
+}
+
+func TestHasApprovers(t *testing.T) {
+	t.Parallel()
+	step := workflow.CreateWorkflowApproveStep("approve")
+	assert.False(t, workflow.HasApprovers(&step), "no approvers")
+
+	step.Approve.Approvers = []string{"alice", "bob"}
+	assert.True(t, workflow.HasApprovers(&step), "approvers")
+}
+
+func TestStepName(t *testing.T) {
+	t.Parallel()
+	staging := workflow.CreateWorkflowPromoteStep("staging")
+	first := workflow.CreateWorkflowApproveStep("", staging)
+	second := workflow.CreateWorkflowApproveStep("", staging)
+	named := workflow.CreateWorkflowApproveStep("approve-production", staging)
+	flow := workflow.CreateWorkflow("jx", "myflow", staging, first, second, named)
+
+	assert.Equal(t, "promote-staging", workflow.StepName(flow, &flow.Spec.Steps[0]))
+	assert.Equal(t, "approve", workflow.StepName(flow, &flow.Spec.Steps[1]))
+	assert.Equal(t, "approve-2", workflow.StepName(flow, &flow.Spec.Steps[2]))
+	assert.Equal(t, "approve-production", workflow.StepName(flow, &flow.Spec.Steps[3]))
+	assert.Equal(t, &flow.Spec.Steps[2], workflow.FindWorkflowStep(flow, "approve-2"))
+}
@@ -73,14 +73,6 @@ func CreateWorkflowPromoteStep(envName string, preconditionSteps ...v1.WorkflowS
 			Environment: envName,
 		},
 	}
-	for _, preconditionStep := range preconditionSteps {
-		promote := preconditionStep.Promote
-		if promote != nil {
-			envName := promote.Environment
-			if envName != "" {
-				answer.Preconditions.Environments = append(answer.Preconditions.Environments, envName)
-			}
-		}
-	}
+	addPreconditionSteps(&answer, preconditionSteps...)
 	return answer
 }